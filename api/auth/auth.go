@@ -4,6 +4,7 @@
 package auth
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/base64"
 	"errors"
@@ -14,10 +15,17 @@ import (
 	"sync"
 	"time"
 
+	stdjson "encoding/json"
+
 	"github.com/golang-jwt/jwt"
 
 	"github.com/gorilla/rpc/v2"
 
+	"go.uber.org/zap"
+
+	"github.com/dim4egster/qmallgo/api"
+	"github.com/dim4egster/qmallgo/database"
+	"github.com/dim4egster/qmallgo/database/prefixdb"
 	"github.com/dim4egster/qmallgo/utils/json"
 	"github.com/dim4egster/qmallgo/utils/logging"
 	"github.com/dim4egster/qmallgo/utils/password"
@@ -35,6 +43,10 @@ const (
 	defaultTokenLifespan = time.Hour * 12
 
 	maxEndpoints = 128
+
+	// maxMethods is the maximum number of method patterns in each of a token's
+	// allowed and denied methods.
+	maxMethods = 128
 )
 
 var (
@@ -52,25 +64,50 @@ var (
 	errNoPassword                  = errors.New("no password")
 	errNoEndpoints                 = errors.New("must name at least one endpoint")
 	errTooManyEndpoints            = fmt.Errorf("can only name at most %d endpoints", maxEndpoints)
+	errTooManyMethods              = fmt.Errorf("can only name at most %d allowed and %d denied methods", maxMethods, maxMethods)
+	errNoTokenID                   = errors.New("token ID not provided")
+	errUnknownTokenID              = errors.New("no token with this ID was issued under the current password")
+
+	tokensPrefix  = []byte("tokens")
+	revokedPrefix = []byte("revoked")
 
 	_ Auth = &auth{}
 )
 
+// endpointsKey is the context key of the endpoints that the token authorizing
+// a request gives access to.
+type endpointsKey struct{}
+
 type Auth interface {
 	// Create and return a new token that allows access to each API endpoint for
 	// [duration] such that the API's path ends with an element of [endpoints].
 	// If one of the elements of [endpoints] is "*", all APIs are accessible.
 	NewToken(pw string, duration time.Duration, endpoints []string) (string, error)
 
+	// Create and return a new token like NewToken that only allows calling the
+	// API methods that are permitted by [permissions].
+	NewScopedToken(pw string, duration time.Duration, endpoints []string, permissions Permissions) (string, error)
+
+	// Returns the tokens that were issued under the current password and
+	// haven't expired yet.
+	ListTokens(pw string) ([]TokenInfo, error)
+
 	// Revokes [token]; it will not be accepted as authorization for future API
-	// calls. If the token is invalid, this is a no-op.  If a token is revoked
-	// and then the password is changed, and then changed back to the current
-	// password, the token will be un-revoked. Therefore, passwords shouldn't be
-	// re-used before previously revoked tokens have expired.
+	// calls. If the token is invalid, this is a no-op. Revocations are
+	// persisted until the token expires.
 	RevokeToken(pw, token string) error
 
-	// Authenticates [token] for access to [url].
-	AuthenticateToken(token, url string) error
+	// Revokes the token with ID [tokenID] that was issued under the current
+	// password.
+	RevokeTokenByID(pw, tokenID string) error
+
+	// Authenticates [token] for calling each of [methods] at [url].
+	AuthenticateToken(token, url string, methods []string) error
+
+	// Returns the ID of [token] if it was issued under the current password and
+	// hasn't expired or been revoked. The endpoints and methods that it allows
+	// calling aren't checked.
+	VerifyToken(token string) (string, error)
 
 	// Change the password required to create and revoke tokens.
 	// [oldPW] is the current password.
@@ -84,43 +121,94 @@ type Auth interface {
 	CreateHandler() (http.Handler, error)
 
 	// WrapHandler wraps an http.Handler. Before passing a request to the
-	// provided handler, the auth token is authenticated.
+	// provided handler, the auth token is authenticated for the request's
+	// endpoint and JSON-RPC methods, and the call is logged.
 	WrapHandler(h http.Handler) http.Handler
 }
 
+// TokenInfo describes a token that was issued under the current password.
+type TokenInfo struct {
+	ID        string   `json:"id"`
+	Endpoints []string `json:"endpoints"`
+	Permissions
+	ExpiresAt time.Time `json:"expiresAt"`
+	Revoked   bool      `json:"revoked"`
+}
+
 type auth struct {
 	// Used to mock time.
 	clock mockable.Clock
 
 	log      logging.Logger
 	endpoint string
+	// The maximum number of bytes of a request body that is read to find the
+	// JSON-RPC methods it calls.
+	maxRequestBodySize int64
 
 	lock sync.RWMutex
 	// Can be changed via API call.
 	password password.Hash
-	// Set of token IDs that have been revoked
-	revoked map[string]struct{}
+	// Key: The ID of a token that has been revoked
+	// Value: The time that token expires
+	revoked map[string]time.Time
+
+	// Key: The ID of a token issued under the current password
+	// Value: The JSON encoding of the token's TokenInfo
+	tokenDB database.Database
+	// Key: The ID of a token that has been revoked
+	// Value: The time that token expires
+	revokedDB database.Database
 }
 
-func New(log logging.Logger, endpoint, pw string) (Auth, error) {
-	a := &auth{
-		log:      log,
-		endpoint: endpoint,
-		revoked:  make(map[string]struct{}),
+func New(log logging.Logger, endpoint, pw string, maxRequestBodySize int64, db database.Database) (Auth, error) {
+	a, err := newAuth(log, endpoint, maxRequestBodySize, db)
+	if err != nil {
+		return nil, err
 	}
 	return a, a.password.Set(pw)
 }
 
-func NewFromHash(log logging.Logger, endpoint string, pw password.Hash) Auth {
-	return &auth{
-		log:      log,
-		endpoint: endpoint,
-		password: pw,
-		revoked:  make(map[string]struct{}),
+func NewFromHash(log logging.Logger, endpoint string, pw password.Hash, maxRequestBodySize int64, db database.Database) (Auth, error) {
+	a, err := newAuth(log, endpoint, maxRequestBodySize, db)
+	if err != nil {
+		return nil, err
+	}
+	a.password = pw
+	return a, nil
+}
+
+// newAuth loads the unexpired revocations from [db].
+func newAuth(log logging.Logger, endpoint string, maxRequestBodySize int64, db database.Database) (*auth, error) {
+	a := &auth{
+		log:                log,
+		endpoint:           endpoint,
+		maxRequestBodySize: maxRequestBodySize,
+		revoked:            make(map[string]time.Time),
+		tokenDB:            prefixdb.New(tokensPrefix, db),
+		revokedDB:          prefixdb.New(revokedPrefix, db),
+	}
+
+	it := a.revokedDB.NewIterator()
+	defer it.Release()
+
+	for it.Next() {
+		expiresAt, err := database.ParseTimestamp(it.Value())
+		if err != nil {
+			return nil, err
+		}
+		a.revoked[string(it.Key())] = expiresAt
 	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+	return a, a.pruneExpired()
 }
 
 func (a *auth) NewToken(pw string, duration time.Duration, endpoints []string) (string, error) {
+	return a.NewScopedToken(pw, duration, endpoints, Permissions{})
+}
+
+func (a *auth) NewScopedToken(pw string, duration time.Duration, endpoints []string, permissions Permissions) (string, error) {
 	if pw == "" {
 		return "", errNoPassword
 	}
@@ -129,9 +217,15 @@ func (a *auth) NewToken(pw string, duration time.Duration, endpoints []string) (
 	} else if l > maxEndpoints {
 		return "", errTooManyEndpoints
 	}
+	if len(permissions.AllowedMethods) > maxMethods || len(permissions.DeniedMethods) > maxMethods {
+		return "", errTooManyMethods
+	}
+	if err := permissions.verify(); err != nil {
+		return "", err
+	}
 
-	a.lock.RLock()
-	defer a.lock.RUnlock()
+	a.lock.Lock()
+	defer a.lock.Unlock()
 
 	if !a.password.Check(pw) {
 		return "", errWrongPassword
@@ -156,16 +250,61 @@ func (a *auth) NewToken(pw string, duration time.Duration, endpoints []string) (
 			ExpiresAt: a.clock.Time().Add(duration).Unix(),
 			Id:        id,
 		},
+		Permissions: permissions,
 	}
 	if canAccessAll {
 		claims.Endpoints = []string{"*"}
 	} else {
 		claims.Endpoints = endpoints
 	}
+
+	infoBytes, err := stdjson.Marshal(&TokenInfo{
+		ID:          id,
+		Endpoints:   claims.Endpoints,
+		Permissions: permissions,
+		ExpiresAt:   time.Unix(claims.ExpiresAt, 0),
+	})
+	if err != nil {
+		return "", err
+	}
+	if err := a.tokenDB.Put([]byte(id), infoBytes); err != nil {
+		return "", err
+	}
+
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &claims)
 	return token.SignedString(a.password.Password[:]) // Sign the token and return its string repr.
 }
 
+func (a *auth) ListTokens(pw string) ([]TokenInfo, error) {
+	if pw == "" {
+		return nil, errNoPassword
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if !a.password.Check(pw) {
+		return nil, errWrongPassword
+	}
+	if err := a.pruneExpired(); err != nil {
+		return nil, err
+	}
+
+	it := a.tokenDB.NewIterator()
+	defer it.Release()
+
+	tokens := []TokenInfo{}
+	for it.Next() {
+		info := TokenInfo{}
+		if err := stdjson.Unmarshal(it.Value(), &info); err != nil {
+			return nil, err
+		}
+		_, info.Revoked = a.revoked[info.ID]
+		tokens = append(tokens, info)
+	}
+	return tokens, it.Error()
+}
+
 func (a *auth) RevokeToken(tokenStr, pw string) error {
 	if tokenStr == "" {
 		return errNoToken
@@ -196,38 +335,124 @@ func (a *auth) RevokeToken(tokenStr, pw string) error {
 	if !ok {
 		return fmt.Errorf("expected auth token's claims to be type endpointClaims but is %T", token.Claims)
 	}
-	a.revoked[claims.Id] = struct{}{}
-	return nil
+	return a.revoke(claims.Id, time.Unix(claims.ExpiresAt, 0))
 }
 
-func (a *auth) AuthenticateToken(tokenStr, url string) error {
+func (a *auth) RevokeTokenByID(pw, tokenID string) error {
+	if tokenID == "" {
+		return errNoTokenID
+	}
+	if pw == "" {
+		return errNoPassword
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if !a.password.Check(pw) {
+		return errWrongPassword
+	}
+
+	infoBytes, err := a.tokenDB.Get([]byte(tokenID))
+	if err == database.ErrNotFound {
+		return errUnknownTokenID
+	}
+	if err != nil {
+		return err
+	}
+
+	info := TokenInfo{}
+	if err := stdjson.Unmarshal(infoBytes, &info); err != nil {
+		return err
+	}
+	return a.revoke(tokenID, info.ExpiresAt)
+}
+
+func (a *auth) AuthenticateToken(tokenStr, url string, methods []string) error {
+	_, _, err := a.authenticate(tokenStr, url, true, methods, true)
+	return err
+}
+
+func (a *auth) VerifyToken(tokenStr string) (string, error) {
 	a.lock.RLock()
 	defer a.lock.RUnlock()
 
-	token, err := jwt.ParseWithClaims(tokenStr, &endpointClaims{}, a.getTokenKey)
-	if err != nil { // Probably because signature wrong
-		return err
+	claims, err := a.parse(tokenStr)
+	if err != nil {
+		return "", err
+	}
+	if _, revoked := a.revoked[claims.Id]; revoked {
+		return "", errTokenRevoked
 	}
+	return claims.Id, nil
+}
 
-	// Make sure this token gives access to the requested endpoint
-	claims, ok := token.Claims.(*endpointClaims)
-	if !ok {
-		// Error is intentionally dropped here as there is nothing left to do
-		// with it.
-		return fmt.Errorf("expected auth token's claims to be type endpointClaims but is %T", token.Claims)
+// authenticate returns the ID of the token, if it could be parsed, and the
+// endpoints it allows accessing, along with whether the token allows calling
+// each of [methods] at [url]. If [urlKnown] is false, the endpoint depends on
+// the request and is left to the handler to check with AllowsEndpoint. If
+// [methodsKnown] is false, the methods being called couldn't be determined and
+// only tokens that don't restrict methods are accepted.
+func (a *auth) authenticate(tokenStr, url string, urlKnown bool, methods []string, methodsKnown bool) (string, []string, error) {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+
+	claims, err := a.parse(tokenStr)
+	if err != nil {
+		return "", nil, err
 	}
 
+	// Make sure this token gives access to the requested endpoint
 	_, revoked := a.revoked[claims.Id]
 	if revoked {
-		return errTokenRevoked
+		return claims.Id, nil, errTokenRevoked
 	}
 
-	for _, endpoint := range claims.Endpoints {
+	if urlKnown && !allowsEndpoint(claims.Endpoints, url) {
+		return claims.Id, nil, errTokenInsufficientPermission
+	}
+	if !methodsKnown && claims.isScoped() {
+		return claims.Id, nil, errTokenMethodNotAllowed
+	}
+	return claims.Id, claims.Endpoints, claims.allowsMethods(methods)
+}
+
+// allowsEndpoint returns true if one of [endpoints] gives access to [url].
+func allowsEndpoint(endpoints []string, url string) bool {
+	for _, endpoint := range endpoints {
 		if endpoint == "*" || strings.HasSuffix(url, endpoint) {
-			return nil
+			return true
 		}
 	}
-	return errTokenInsufficientPermission
+	return false
+}
+
+// AllowsEndpoint returns true if the token that authorized the request of
+// [ctx] gives access to [endpoint]. Handlers that serve several endpoints call
+// it once they know which endpoint a request is for. Returns true if the
+// request wasn't authorized with a token.
+func AllowsEndpoint(ctx context.Context, endpoint string) bool {
+	endpoints, ok := ctx.Value(endpointsKey{}).([]string)
+	return !ok || allowsEndpoint(endpoints, endpoint)
+}
+
+// parse returns the claims of [tokenStr] if it was signed under the current
+// password and hasn't expired.
+//
+// Assumes [a.lock] is held.
+func (a *auth) parse(tokenStr string) (*endpointClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenStr, &endpointClaims{}, a.getTokenKey)
+	if err != nil { // Probably because signature wrong
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(*endpointClaims)
+	if !ok {
+		// Error is intentionally dropped here as there is nothing left to do
+		// with it.
+		return nil, fmt.Errorf("expected auth token's claims to be type endpointClaims but is %T", token.Claims)
+	}
+	return claims, nil
 }
 
 func (a *auth) ChangePassword(oldPW, newPW string) error {
@@ -248,10 +473,9 @@ func (a *auth) ChangePassword(oldPW, newPW string) error {
 		return err
 	}
 
-	// All the revoked tokens are now invalid; no need to mark specifically as
-	// revoked.
-	a.revoked = make(map[string]struct{})
-	return nil
+	// All the issued tokens are now invalid. Revocations are kept until the
+	// tokens expire in case the password is changed back.
+	return database.Clear(a.tokenDB, a.tokenDB)
 }
 
 func (a *auth) CreateHandler() (http.Handler, error) {
@@ -288,15 +512,113 @@ func (a *auth) WrapHandler(h http.Handler) http.Handler {
 		// Returns actual auth token. Slice guaranteed to not go OOB
 		tokenStr := rawHeader[len(headerValStart):]
 
-		if err := a.AuthenticateToken(tokenStr, r.URL.Path); err != nil {
+		var (
+			url          = r.URL.Path
+			urlKnown     = true
+			methods      []string
+			methodsKnown = true
+			err          error
+		)
+		if api.IsGRPCRequest(r) {
+			// The body of a gRPC request is a stream, so the endpoint and the
+			// methods are derived from the path instead.
+			url, urlKnown, methods, methodsKnown = grpcCall(r.URL.Path)
+		} else {
+			var request *json.Request
+			r, request, err = json.ParseRequest(w, r, a.maxRequestBodySize)
+			if errors.Is(err, json.ErrRequestTooLarge) {
+				writeErrorResponse(w, http.StatusRequestEntityTooLarge, err)
+				return
+			}
+			if err != nil {
+				writeUnauthorizedResponse(w, err)
+				return
+			}
+			// A body that doesn't parse may still be executed by the codec or
+			// the batch handler, which are more lenient, so it's rejected
+			// rather than treated as calling no methods.
+			if request.Err != nil {
+				writeErrorResponse(w, http.StatusBadRequest, request.Err)
+				return
+			}
+			methods = request.Methods
+		}
+
+		tokenID, endpoints, err := a.authenticate(tokenStr, url, urlKnown, methods, methodsKnown)
+		if err != nil {
+			a.log.Info("rejected API call",
+				zap.String("tokenID", tokenID),
+				zap.String("endpoint", r.URL.Path),
+				zap.Strings("methods", methods),
+				zap.String("remoteAddr", r.RemoteAddr),
+				zap.Error(err),
+			)
 			writeUnauthorizedResponse(w, err)
 			return
 		}
 
-		h.ServeHTTP(w, r)
+		a.log.Info("authorized API call",
+			zap.String("tokenID", tokenID),
+			zap.String("endpoint", r.URL.Path),
+			zap.Strings("methods", methods),
+			zap.String("remoteAddr", r.RemoteAddr),
+		)
+		h.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), endpointsKey{}, endpoints)))
 	})
 }
 
+// revoke persists the revocation of [tokenID] until [expiresAt].
+//
+// Assumes [a.lock] is held.
+func (a *auth) revoke(tokenID string, expiresAt time.Time) error {
+	if err := database.PutTimestamp(a.revokedDB, []byte(tokenID), expiresAt); err != nil {
+		return err
+	}
+	a.revoked[tokenID] = expiresAt
+	return nil
+}
+
+// pruneExpired removes the expired tokens and revocations. Expired tokens are
+// rejected regardless of whether they were revoked.
+//
+// Assumes [a.lock] is held.
+func (a *auth) pruneExpired() error {
+	now := a.clock.Time()
+	for tokenID, expiresAt := range a.revoked {
+		if !now.After(expiresAt) {
+			continue
+		}
+		if err := a.revokedDB.Delete([]byte(tokenID)); err != nil {
+			return err
+		}
+		delete(a.revoked, tokenID)
+	}
+
+	it := a.tokenDB.NewIterator()
+	defer it.Release()
+
+	var expired [][]byte
+	for it.Next() {
+		info := TokenInfo{}
+		if err := stdjson.Unmarshal(it.Value(), &info); err != nil {
+			return err
+		}
+		if now.After(info.ExpiresAt) {
+			expired = append(expired, it.Key())
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+
+	for _, tokenID := range expired {
+		if err := a.tokenDB.Delete(tokenID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // getTokenKey returns the key to use when making and parsing tokens
 func (a *auth) getTokenKey(t *jwt.Token) (interface{}, error) {
 	if t.Method != jwt.SigningMethodHS256 {
@@ -18,10 +18,15 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/dim4egster/qmallgo/database"
+	"github.com/dim4egster/qmallgo/database/memdb"
+	"github.com/dim4egster/qmallgo/utils/json"
 	"github.com/dim4egster/qmallgo/utils/logging"
 	"github.com/dim4egster/qmallgo/utils/password"
 )
 
+const testMaxRequestBodySize = 1024
+
 var (
 	testPassword              = "password!@#$%$#@!"
 	hashedPassword            = password.Hash{}
@@ -34,11 +39,21 @@ func init() {
 	}
 }
 
+func newTestAuth(t *testing.T) *auth {
+	return newTestAuthWithDB(t, memdb.New())
+}
+
+func newTestAuthWithDB(t *testing.T, db database.Database) *auth {
+	a, err := NewFromHash(logging.NoLog{}, "auth", hashedPassword, testMaxRequestBodySize, db)
+	require.NoError(t, err)
+	return a.(*auth)
+}
+
 // Always returns 200 (http.StatusOK)
 var dummyHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
 
 func TestNewTokenWrongPassword(t *testing.T) {
-	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword)
+	auth := newTestAuth(t)
 
 	_, err := auth.NewToken("", defaultTokenLifespan, []string{"endpoint1, endpoint2"})
 	require.Error(t, err, "should have failed because password is wrong")
@@ -48,7 +63,7 @@ func TestNewTokenWrongPassword(t *testing.T) {
 }
 
 func TestNewTokenHappyPath(t *testing.T) {
-	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword).(*auth)
+	auth := newTestAuth(t)
 
 	now := time.Now()
 	auth.clock.Set(now)
@@ -75,7 +90,7 @@ func TestNewTokenHappyPath(t *testing.T) {
 }
 
 func TestTokenHasWrongSig(t *testing.T) {
-	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword).(*auth)
+	auth := newTestAuth(t)
 
 	// Make a token
 	endpoints := []string{"endpoint1", "endpoint2", "endpoint3"}
@@ -100,7 +115,7 @@ func TestTokenHasWrongSig(t *testing.T) {
 }
 
 func TestChangePassword(t *testing.T) {
-	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword).(*auth)
+	auth := newTestAuth(t)
 
 	password2 := "fejhkefjhefjhefhje" // #nosec G101
 	var err error
@@ -128,7 +143,7 @@ func TestChangePassword(t *testing.T) {
 }
 
 func TestRevokeToken(t *testing.T) {
-	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword).(*auth)
+	auth := newTestAuth(t)
 
 	// Make a token
 	endpoints := []string{"/ext/info", "/ext/bc/X", "/ext/metrics"}
@@ -140,8 +155,29 @@ func TestRevokeToken(t *testing.T) {
 	require.Len(t, auth.revoked, 1, "revoked token list is incorrect")
 }
 
+func TestVerifyToken(t *testing.T) {
+	require := require.New(t)
+
+	auth := newTestAuth(t)
+
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, []string{"/ext/info"})
+	require.NoError(err)
+
+	// The token is valid regardless of the endpoints it allows.
+	tokenID, err := auth.VerifyToken(tokenStr)
+	require.NoError(err)
+	require.NotEmpty(tokenID)
+
+	_, err = auth.VerifyToken("not.a.token")
+	require.Error(err)
+
+	require.NoError(auth.RevokeToken(tokenStr, testPassword))
+	_, err = auth.VerifyToken(tokenStr)
+	require.ErrorIs(err, errTokenRevoked)
+}
+
 func TestWrapHandlerHappyPath(t *testing.T) {
-	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword)
+	auth := newTestAuth(t)
 
 	// Make a token
 	endpoints := []string{"/ext/info", "/ext/bc/X", "/ext/metrics"}
@@ -160,7 +196,7 @@ func TestWrapHandlerHappyPath(t *testing.T) {
 }
 
 func TestWrapHandlerRevokedToken(t *testing.T) {
-	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword)
+	auth := newTestAuth(t)
 
 	// Make a token
 	endpoints := []string{"/ext/info", "/ext/bc/X", "/ext/metrics"}
@@ -184,7 +220,7 @@ func TestWrapHandlerRevokedToken(t *testing.T) {
 }
 
 func TestWrapHandlerExpiredToken(t *testing.T) {
-	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword).(*auth)
+	auth := newTestAuth(t)
 
 	auth.clock.Set(time.Now().Add(-2 * defaultTokenLifespan))
 
@@ -207,7 +243,7 @@ func TestWrapHandlerExpiredToken(t *testing.T) {
 }
 
 func TestWrapHandlerNoAuthToken(t *testing.T) {
-	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword)
+	auth := newTestAuth(t)
 
 	endpoints := []string{"/ext/info", "/ext/bc/X", "/ext/metrics"}
 	wrappedHandler := auth.WrapHandler(dummyHandler)
@@ -222,7 +258,7 @@ func TestWrapHandlerNoAuthToken(t *testing.T) {
 }
 
 func TestWrapHandlerUnauthorizedEndpoint(t *testing.T) {
-	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword)
+	auth := newTestAuth(t)
 
 	// Make a token
 	endpoints := []string{"/ext/info"}
@@ -244,7 +280,7 @@ func TestWrapHandlerUnauthorizedEndpoint(t *testing.T) {
 }
 
 func TestWrapHandlerAuthEndpoint(t *testing.T) {
-	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword)
+	auth := newTestAuth(t)
 
 	// Make a token
 	endpoints := []string{"/ext/info", "/ext/bc/X", "/ext/metrics", "", "/foo", "/ext/info/foo"}
@@ -260,7 +296,7 @@ func TestWrapHandlerAuthEndpoint(t *testing.T) {
 }
 
 func TestWrapHandlerAccessAll(t *testing.T) {
-	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword)
+	auth := newTestAuth(t)
 
 	// Make a token that allows access to all endpoints
 	endpoints := []string{"/ext/info", "/ext/bc/X", "/ext/metrics", "", "/foo", "/ext/foo/info"}
@@ -285,7 +321,7 @@ func TestWriteUnauthorizedResponse(t *testing.T) {
 }
 
 func TestWrapHandlerMutatedRevokedToken(t *testing.T) {
-	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword)
+	auth := newTestAuth(t)
 
 	// Make a token
 	endpoints := []string{"/ext/info", "/ext/bc/X", "/ext/metrics"}
@@ -309,7 +345,7 @@ func TestWrapHandlerMutatedRevokedToken(t *testing.T) {
 }
 
 func TestWrapHandlerInvalidSigningMethod(t *testing.T) {
-	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword).(*auth)
+	auth := newTestAuth(t)
 
 	// Make a token
 	endpoints := []string{"/ext/info", "/ext/bc/X", "/ext/metrics"}
@@ -344,3 +380,171 @@ func TestWrapHandlerInvalidSigningMethod(t *testing.T) {
 		require.Regexp(t, unAuthorizedResponseRegex, rr.Body.String())
 	}
 }
+
+func newRPCRequest(t *testing.T, endpoint, tokenStr string, methods ...string) *http.Request {
+	requests := make([]string, len(methods))
+	for i, method := range methods {
+		requests[i] = fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":%q,"params":{}}`, i, method)
+	}
+	body := strings.Join(requests, ",")
+	if len(methods) > 1 {
+		body = "[" + body + "]"
+	}
+	req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("http://127.0.0.1:4960%s", endpoint), strings.NewReader(body))
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", tokenStr))
+	return req
+}
+
+func TestWrapHandlerMethodPermissions(t *testing.T) {
+	auth := newTestAuth(t)
+
+	tokenStr, err := auth.NewScopedToken(testPassword, defaultTokenLifespan, []string{"/ext/bc/X"}, Permissions{
+		AllowedMethods: []string{"avm.get*"},
+		DeniedMethods:  []string{"avm.getUTXOs"},
+	})
+	require.NoError(t, err)
+
+	// The wrapped handler must still be able to read the request.
+	var servedMethod string
+	wrappedHandler := auth.WrapHandler(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		_, request, err := json.ParseRequest(httptest.NewRecorder(), r, testMaxRequestBodySize)
+		require.NoError(t, err)
+		require.NoError(t, request.Err)
+		servedMethod = strings.Join(request.Methods, ",")
+	}))
+
+	tests := []struct {
+		methods      []string
+		expectedCode int
+	}{
+		{methods: []string{"avm.getBalance"}, expectedCode: http.StatusOK},
+		{methods: []string{"avm.getBalance", "avm.getTx"}, expectedCode: http.StatusOK},
+		{methods: []string{"avm.send"}, expectedCode: http.StatusUnauthorized},
+		{methods: []string{"avm.getUTXOs"}, expectedCode: http.StatusUnauthorized},
+		{methods: []string{"avm.getBalance", "avm.send"}, expectedCode: http.StatusUnauthorized},
+		{methods: nil, expectedCode: http.StatusUnauthorized},
+	}
+	for _, test := range tests {
+		servedMethod = ""
+		rr := httptest.NewRecorder()
+		wrappedHandler.ServeHTTP(rr, newRPCRequest(t, "/ext/bc/X", tokenStr, test.methods...))
+		require.Equal(t, test.expectedCode, rr.Code, test.methods)
+		if test.expectedCode == http.StatusOK {
+			require.Equal(t, strings.Join(test.methods, ","), servedMethod)
+		} else {
+			require.Contains(t, rr.Body.String(), errTokenMethodNotAllowed.Error())
+		}
+	}
+
+	_, err = auth.NewScopedToken(testPassword, defaultTokenLifespan, []string{"*"}, Permissions{
+		AllowedMethods: []string{"avm.[get"},
+	})
+	require.ErrorIs(t, err, errInvalidMethodPattern)
+}
+
+func TestWrapHandlerRequestTooLarge(t *testing.T) {
+	require := require.New(t)
+	auth := newTestAuth(t)
+
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, []string{"/ext/bc/X"})
+	require.NoError(err)
+	wrappedHandler := auth.WrapHandler(dummyHandler)
+
+	// Each request in the batch is over 50 bytes long.
+	methods := make([]string, testMaxRequestBodySize/50)
+	for i := range methods {
+		methods[i] = "avm.getBalance"
+	}
+	rr := httptest.NewRecorder()
+	wrappedHandler.ServeHTTP(rr, newRPCRequest(t, "/ext/bc/X", tokenStr, methods...))
+	require.Equal(http.StatusRequestEntityTooLarge, rr.Code)
+	require.Contains(rr.Body.String(), json.ErrRequestTooLarge.Error())
+}
+
+func TestWrapHandlerRejectsMalformedRequests(t *testing.T) {
+	require := require.New(t)
+
+	auth := newTestAuth(t)
+
+	// The token is only denied methods, so a request whose methods are unknown
+	// would otherwise be allowed.
+	tokenStr, err := auth.NewScopedToken(testPassword, defaultTokenLifespan, []string{"/ext/bc/X"}, Permissions{
+		DeniedMethods: []string{"avm.send"},
+	})
+	require.NoError(err)
+
+	served := false
+	wrappedHandler := auth.WrapHandler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		served = true
+	}))
+
+	bodies := []string{
+		// The codec decodes the first request and ignores the trailing data.
+		`{"jsonrpc":"2.0","id":1,"method":"avm.send","params":{}} x`,
+		// The batch handler serves the object even though the batch has an
+		// element that isn't one.
+		`[{"jsonrpc":"2.0","id":1,"method":"avm.send","params":{}},5]`,
+		"hello",
+	}
+	for _, body := range bodies {
+		served = false
+		req := httptest.NewRequest(http.MethodPost, "http://127.0.0.1:4960/ext/bc/X", strings.NewReader(body))
+		req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", tokenStr))
+		rr := httptest.NewRecorder()
+		wrappedHandler.ServeHTTP(rr, req)
+		require.Equal(http.StatusBadRequest, rr.Code, body)
+		require.False(served, body)
+	}
+}
+
+func TestListAndRevokeTokenByID(t *testing.T) {
+	db := memdb.New()
+	auth := newTestAuthWithDB(t, db)
+
+	now := time.Now()
+	auth.clock.Set(now)
+
+	permissions := Permissions{AllowedMethods: []string{"info.*"}}
+	tokenStr, err := auth.NewScopedToken(testPassword, defaultTokenLifespan, []string{"/ext/info"}, permissions)
+	require.NoError(t, err)
+	_, err = auth.NewToken(testPassword, time.Minute, []string{"*"})
+	require.NoError(t, err)
+
+	_, err = auth.ListTokens("notThePassword")
+	require.ErrorIs(t, err, errWrongPassword)
+
+	tokens, err := auth.ListTokens(testPassword)
+	require.NoError(t, err)
+	require.Len(t, tokens, 2)
+
+	var info TokenInfo
+	for _, token := range tokens {
+		if token.Permissions.AllowedMethods != nil {
+			info = token
+		}
+	}
+	require.Equal(t, []string{"/ext/info"}, info.Endpoints)
+	require.Equal(t, permissions, info.Permissions)
+	require.Equal(t, now.Add(defaultTokenLifespan).Unix(), info.ExpiresAt.Unix())
+	require.False(t, info.Revoked)
+
+	require.ErrorIs(t, auth.RevokeTokenByID(testPassword, "unknown"), errUnknownTokenID)
+	require.NoError(t, auth.RevokeTokenByID(testPassword, info.ID))
+
+	rr := httptest.NewRecorder()
+	auth.WrapHandler(dummyHandler).ServeHTTP(rr, newRPCRequest(t, "/ext/info", tokenStr, "info.getNodeID"))
+	require.Equal(t, http.StatusUnauthorized, rr.Code)
+	require.Contains(t, rr.Body.String(), errTokenRevoked.Error())
+
+	// The revocation is persisted across restarts, and the expired token is
+	// no longer listed.
+	restarted := newTestAuthWithDB(t, db)
+	restarted.clock.Set(now.Add(2 * time.Minute))
+	require.ErrorIs(t, restarted.AuthenticateToken(tokenStr, "/ext/info", []string{"info.getNodeID"}), errTokenRevoked)
+
+	tokens, err = restarted.ListTokens(testPassword)
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	require.Equal(t, info.ID, tokens[0].ID)
+	require.True(t, tokens[0].Revoked)
+}
@@ -4,9 +4,72 @@
 package auth
 
 import (
+	"errors"
+	"fmt"
+	"path"
+
 	"github.com/golang-jwt/jwt"
 )
 
+var (
+	errTokenMethodNotAllowed = errors.New("the provided auth token does not allow calling this method")
+	errInvalidMethodPattern  = errors.New("invalid method pattern")
+)
+
+// Permissions restrict the API methods that a token allows calling. Each
+// element is a pattern, as accepted by path.Match, of JSON-RPC method names
+// e.g. "avm.get*".
+type Permissions struct {
+	// If non-empty, only methods matching an element may be called. Requests
+	// that don't name a JSON-RPC method are then rejected.
+	AllowedMethods []string `json:"allowedMethods,omitempty"`
+	// Methods matching an element may not be called, even if they match
+	// [AllowedMethods].
+	DeniedMethods []string `json:"deniedMethods,omitempty"`
+}
+
+// verify returns an error if any of the patterns is malformed.
+func (p *Permissions) verify() error {
+	for _, patterns := range [][]string{p.AllowedMethods, p.DeniedMethods} {
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("%w %q: %s", errInvalidMethodPattern, pattern, err)
+			}
+		}
+	}
+	return nil
+}
+
+// isScoped returns true if only some methods may be called.
+func (p *Permissions) isScoped() bool {
+	return len(p.AllowedMethods) != 0 || len(p.DeniedMethods) != 0
+}
+
+// allowsMethods returns nil iff all of [methods] may be called.
+func (p *Permissions) allowsMethods(methods []string) error {
+	if len(p.AllowedMethods) != 0 && len(methods) == 0 {
+		return errTokenMethodNotAllowed
+	}
+	for _, method := range methods {
+		if matchesAny(p.DeniedMethods, method) ||
+			(len(p.AllowedMethods) != 0 && !matchesAny(p.AllowedMethods, method)) {
+			return fmt.Errorf("%w: %q", errTokenMethodNotAllowed, method)
+		}
+	}
+	return nil
+}
+
+func matchesAny(patterns []string, method string) bool {
+	for _, pattern := range patterns {
+		// The patterns were verified when the token was issued, so the error
+		// can be ignored.
+		if matched, _ := path.Match(pattern, method); matched {
+			return true
+		}
+	}
+	return false
+}
+
 // Custom claim type used for API access token
 type endpointClaims struct {
 	jwt.StandardClaims
@@ -15,4 +78,7 @@ type endpointClaims struct {
 	// If endpoints has an element "*", allows access to all API endpoints
 	// In this case, "*" should be the only element of [endpoints]
 	Endpoints []string `json:"endpoints,omitempty"`
+
+	// Restricts the methods that may be called at [Endpoints]
+	Permissions
 }
@@ -0,0 +1,78 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package auth
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// grpcService is a gRPC service that mirrors a JSON-RPC API.
+type grpcService struct {
+	// jsonService is the name of the JSON-RPC service.
+	jsonService string
+	// endpoint is the endpoint of the JSON-RPC API. Empty if the endpoint
+	// depends on the request, in which case the gRPC service checks it with
+	// AllowsEndpoint.
+	endpoint string
+}
+
+var (
+	// grpcServices maps the gRPC services that mirror a JSON-RPC API to that
+	// API.
+	grpcServices = map[string]grpcService{
+		"avm.AVM": {
+			jsonService: "avm",
+			endpoint:    "/ext/bc/X",
+		},
+		"health.Health": {
+			jsonService: "health",
+			endpoint:    "/ext/health",
+		},
+		"index.Index": {
+			jsonService: "index",
+		},
+		"info.Info": {
+			jsonService: "info",
+			endpoint:    "/ext/info",
+		},
+		"platformvm.PlatformVM": {
+			jsonService: "platform",
+			endpoint:    "/ext/bc/P",
+		},
+	}
+
+	// grpcMethodOverrides maps the gRPC methods that don't have a JSON-RPC
+	// method of the same name to the JSON-RPC methods they call.
+	grpcMethodOverrides = map[string][]string{
+		"/index.Index/StreamContainerRange": {
+			"index.getLastAccepted",
+			"index.getIndex",
+			"index.getContainerRange",
+		},
+	}
+)
+
+// grpcCall returns the endpoint and the JSON-RPC methods called by the gRPC
+// method at [fullMethod], e.g. "/avm.AVM/GetTx" calls "avm.getTx" at
+// "/ext/bc/X". The endpoint is unknown if it depends on the request. The
+// methods are unknown if [fullMethod] doesn't mirror JSON-RPC methods, in which
+// case [fullMethod] is returned as the endpoint.
+func grpcCall(fullMethod string) (string, bool, []string, bool) {
+	serviceName, method, ok := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if !ok || method == "" {
+		return fullMethod, true, nil, false
+	}
+	service, ok := grpcServices[serviceName]
+	if !ok {
+		return fullMethod, true, nil, false
+	}
+	endpointKnown := service.endpoint != ""
+	if methods, ok := grpcMethodOverrides[fullMethod]; ok {
+		return service.endpoint, endpointKnown, methods, true
+	}
+	first, size := utf8.DecodeRuneInString(method)
+	return service.endpoint, endpointKnown, []string{service.jsonService + "." + string(unicode.ToLower(first)) + method[size:]}, true
+}
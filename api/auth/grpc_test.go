@@ -0,0 +1,203 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package auth
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	infopb "github.com/dim4egster/qmallgo/proto/pb/info"
+)
+
+type testInfoServer struct {
+	infopb.UnimplementedInfoServer
+}
+
+func (*testInfoServer) GetNetworkID(context.Context, *emptypb.Empty) (*infopb.GetNetworkIDResponse, error) {
+	return &infopb.GetNetworkIDResponse{NetworkId: 12345}, nil
+}
+
+func TestGRPCCall(t *testing.T) {
+	tests := []struct {
+		fullMethod    string
+		endpoint      string
+		endpointKnown bool
+		methods       []string
+		known         bool
+	}{
+		{
+			fullMethod:    "/avm.AVM/GetUTXOs",
+			endpoint:      "/ext/bc/X",
+			endpointKnown: true,
+			methods:       []string{"avm.getUTXOs"},
+			known:         true,
+		},
+		{
+			fullMethod:    "/platformvm.PlatformVM/GetHeight",
+			endpoint:      "/ext/bc/P",
+			endpointKnown: true,
+			methods:       []string{"platform.getHeight"},
+			known:         true,
+		},
+		{
+			fullMethod:    "/info.Info/Uptime",
+			endpoint:      "/ext/info",
+			endpointKnown: true,
+			methods:       []string{"info.uptime"},
+			known:         true,
+		},
+		{
+			fullMethod:    "/health.Health/Liveness",
+			endpoint:      "/ext/health",
+			endpointKnown: true,
+			methods:       []string{"health.liveness"},
+			known:         true,
+		},
+		{
+			fullMethod: "/index.Index/StreamContainerRange",
+			endpoint:   "",
+			methods:    []string{"index.getLastAccepted", "index.getIndex", "index.getContainerRange"},
+			known:      true,
+		},
+		{
+			fullMethod:    "/grpc.health.v1.Health/Check",
+			endpoint:      "/grpc.health.v1.Health/Check",
+			endpointKnown: true,
+		},
+		{
+			fullMethod:    "/info.Info/",
+			endpoint:      "/info.Info/",
+			endpointKnown: true,
+		},
+		{
+			fullMethod:    "/ext/info",
+			endpoint:      "/ext/info",
+			endpointKnown: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.fullMethod, func(t *testing.T) {
+			require := require.New(t)
+
+			endpoint, endpointKnown, methods, known := grpcCall(test.fullMethod)
+			require.Equal(test.endpoint, endpoint)
+			require.Equal(test.endpointKnown, endpointKnown)
+			require.Equal(test.known, known)
+			require.Equal(test.methods, methods)
+		})
+	}
+}
+
+func TestWrapHandlerGRPC(t *testing.T) {
+	require := require.New(t)
+
+	auth := newTestAuth(t)
+
+	grpcServer := grpc.NewServer()
+	infopb.RegisterInfoServer(grpcServer, &testInfoServer{})
+	httpServer := httptest.NewServer(h2c.NewHandler(auth.WrapHandler(grpcServer), &http2.Server{}))
+	defer httpServer.Close()
+
+	conn, err := grpc.Dial(
+		strings.TrimPrefix(httpServer.URL, "http://"),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(err)
+	defer conn.Close()
+	client := infopb.NewInfoClient(conn)
+
+	call := func(token string) (*infopb.GetNetworkIDResponse, error) {
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+		return client.GetNetworkID(ctx, &emptypb.Empty{})
+	}
+
+	// An unscoped token may call any method.
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, []string{"*"})
+	require.NoError(err)
+	reply, err := call(tokenStr)
+	require.NoError(err)
+	require.Equal(uint32(12345), reply.NetworkId)
+
+	// A token that denies the JSON-RPC method may not call its gRPC mirror.
+	tokenStr, err = auth.NewScopedToken(testPassword, defaultTokenLifespan, []string{"*"}, Permissions{
+		DeniedMethods: []string{"info.getNetworkID"},
+	})
+	require.NoError(err)
+	_, err = call(tokenStr)
+	require.Equal(codes.Unauthenticated, status.Code(err))
+
+	// A token that allows the JSON-RPC method may call its gRPC mirror.
+	tokenStr, err = auth.NewScopedToken(testPassword, defaultTokenLifespan, []string{"*"}, Permissions{
+		AllowedMethods: []string{"info.*"},
+	})
+	require.NoError(err)
+	_, err = call(tokenStr)
+	require.NoError(err)
+
+	// A token for the JSON-RPC endpoint may call its gRPC mirror.
+	tokenStr, err = auth.NewToken(testPassword, defaultTokenLifespan, []string{"/ext/info"})
+	require.NoError(err)
+	_, err = call(tokenStr)
+	require.NoError(err)
+
+	// A token for another endpoint may not.
+	tokenStr, err = auth.NewToken(testPassword, defaultTokenLifespan, []string{"/ext/health"})
+	require.NoError(err)
+	_, err = call(tokenStr)
+	require.Equal(codes.Unauthenticated, status.Code(err))
+}
+
+func TestAllowsEndpoint(t *testing.T) {
+	require := require.New(t)
+
+	auth := newTestAuth(t)
+
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, []string{"/ext/index/X/tx"})
+	require.NoError(err)
+
+	// The index endpoint depends on the request, so it's checked by the
+	// handler.
+	_, endpoints, err := auth.authenticate(tokenStr, "", false, []string{"index.getIndex"}, true)
+	require.NoError(err)
+
+	ctx := context.WithValue(context.Background(), endpointsKey{}, endpoints)
+	require.True(AllowsEndpoint(ctx, "/ext/index/X/tx"))
+	require.False(AllowsEndpoint(ctx, "/ext/index/P/block"))
+
+	// Requests that weren't authorized with a token aren't restricted.
+	require.True(AllowsEndpoint(context.Background(), "/ext/index/P/block"))
+}
+
+func TestWrapHandlerGRPCUnknownMethod(t *testing.T) {
+	require := require.New(t)
+
+	auth := newTestAuth(t)
+
+	unscoped, err := auth.NewToken(testPassword, defaultTokenLifespan, []string{"*"})
+	require.NoError(err)
+	_, _, err = auth.authenticate(unscoped, "/grpc.health.v1.Health/Check", true, nil, false)
+	require.NoError(err)
+
+	// Scoped tokens are rejected when the methods being called are unknown.
+	scoped, err := auth.NewScopedToken(testPassword, defaultTokenLifespan, []string{"*"}, Permissions{
+		DeniedMethods: []string{"admin.*"},
+	})
+	require.NoError(err)
+	_, _, err = auth.authenticate(scoped, "/grpc.health.v1.Health/Check", true, nil, false)
+	require.ErrorIs(err, errTokenMethodNotAllowed)
+}
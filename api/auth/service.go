@@ -26,6 +26,11 @@ type NewTokenArgs struct {
 	// allows access to all API endpoints. [Endpoints] must have between 1 and
 	// [maxEndpoints] elements
 	Endpoints []string `json:"endpoints"`
+	// Methods that may and may not be called with this token e.g. if
+	// allowedMethods is ["avm.get*"] then the token holder can only call the
+	// X-Chain API's read-only methods. Each list can have at most
+	// [maxMethods] elements
+	Permissions
 }
 
 type Token struct {
@@ -36,7 +41,19 @@ func (s *Service) NewToken(_ *http.Request, args *NewTokenArgs, reply *Token) er
 	s.auth.log.Debug("Auth: NewToken called")
 
 	var err error
-	reply.Token, err = s.auth.NewToken(args.Password.Password, defaultTokenLifespan, args.Endpoints)
+	reply.Token, err = s.auth.NewScopedToken(args.Password.Password, defaultTokenLifespan, args.Endpoints, args.Permissions)
+	return err
+}
+
+type ListTokensReply struct {
+	Tokens []TokenInfo `json:"tokens"`
+}
+
+func (s *Service) ListTokens(_ *http.Request, args *Password, reply *ListTokensReply) error {
+	s.auth.log.Debug("Auth: ListTokens called")
+
+	var err error
+	reply.Tokens, err = s.auth.ListTokens(args.Password)
 	return err
 }
 
@@ -51,6 +68,17 @@ func (s *Service) RevokeToken(_ *http.Request, args *RevokeTokenArgs, _ *api.Emp
 	return s.auth.RevokeToken(args.Token.Token, args.Password.Password)
 }
 
+type RevokeTokenByIDArgs struct {
+	Password
+	TokenID string `json:"tokenID"` // The ID of the token, as returned by ListTokens
+}
+
+func (s *Service) RevokeTokenByID(_ *http.Request, args *RevokeTokenByIDArgs, _ *api.EmptyReply) error {
+	s.auth.log.Debug("Auth: RevokeTokenByID called")
+
+	return s.auth.RevokeTokenByID(args.Password.Password, args.TokenID)
+}
+
 type ChangePasswordArgs struct {
 	OldPassword string `json:"oldPassword"` // Current authorization password
 	NewPassword string `json:"newPassword"` // New authorization password
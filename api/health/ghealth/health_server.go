@@ -0,0 +1,80 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ghealth
+
+import (
+	"context"
+	"encoding/json"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/dim4egster/qmallgo/api/health"
+	"github.com/dim4egster/qmallgo/vms/rpcchainvm/grpcutils"
+
+	healthpb "github.com/dim4egster/qmallgo/proto/pb/health"
+)
+
+var _ healthpb.HealthServer = &Server{}
+
+// Server serves the health API over gRPC.
+type Server struct {
+	healthpb.UnsafeHealthServer
+	reporter health.Reporter
+}
+
+// NewServer returns a gRPC server that serves the health reported by
+// [reporter].
+func NewServer(reporter health.Reporter) *Server {
+	return &Server{
+		reporter: reporter,
+	}
+}
+
+func (s *Server) Readiness(context.Context, *emptypb.Empty) (*healthpb.ReadinessResponse, error) {
+	report, err := newReport(s.reporter.Readiness())
+	return &healthpb.ReadinessResponse{
+		Report: report,
+	}, err
+}
+
+func (s *Server) Health(context.Context, *emptypb.Empty) (*healthpb.HealthResponse, error) {
+	report, err := newReport(s.reporter.Health())
+	return &healthpb.HealthResponse{
+		Report: report,
+	}, err
+}
+
+func (s *Server) Liveness(context.Context, *emptypb.Empty) (*healthpb.LivenessResponse, error) {
+	report, err := newReport(s.reporter.Liveness())
+	return &healthpb.LivenessResponse{
+		Report: report,
+	}, err
+}
+
+func newReport(checks map[string]health.Result, healthy bool) (*healthpb.Report, error) {
+	report := &healthpb.Report{
+		Healthy: healthy,
+		Checks:  make(map[string]*healthpb.Result, len(checks)),
+	}
+	for name, result := range checks {
+		details, err := json.Marshal(result.Details)
+		if err != nil {
+			return nil, err
+		}
+		pbResult := &healthpb.Result{
+			Details:            details,
+			Timestamp:          grpcutils.TimestampFromTime(result.Timestamp),
+			DurationNs:         int64(result.Duration),
+			ContiguousFailures: result.ContiguousFailures,
+		}
+		if result.Error != nil {
+			pbResult.Error = *result.Error
+		}
+		if result.TimeOfFirstFailure != nil {
+			pbResult.TimeOfFirstFailure = grpcutils.TimestampFromTime(*result.TimeOfFirstFailure)
+		}
+		report.Checks[name] = pbResult
+	}
+	return report, nil
+}
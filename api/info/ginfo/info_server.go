@@ -0,0 +1,133 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ginfo
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/dim4egster/qmallgo/api/info"
+
+	infopb "github.com/dim4egster/qmallgo/proto/pb/info"
+)
+
+var _ infopb.InfoServer = &Server{}
+
+// Server serves the info API over gRPC.
+type Server struct {
+	infopb.UnsafeInfoServer
+	service *info.Info
+}
+
+// NewServer returns a gRPC server that serves its requests with [service].
+func NewServer(service *info.Info) *Server {
+	return &Server{
+		service: service,
+	}
+}
+
+func (s *Server) GetNodeVersion(context.Context, *emptypb.Empty) (*infopb.GetNodeVersionResponse, error) {
+	reply := &info.GetNodeVersionReply{}
+	if err := s.service.GetNodeVersion(nil, nil, reply); err != nil {
+		return nil, err
+	}
+	return &infopb.GetNodeVersionResponse{
+		Version:         reply.Version,
+		DatabaseVersion: reply.DatabaseVersion,
+		GitCommit:       reply.GitCommit,
+		VmVersions:      reply.VMVersions,
+	}, nil
+}
+
+func (s *Server) GetNodeID(context.Context, *emptypb.Empty) (*infopb.GetNodeIDResponse, error) {
+	reply := &info.GetNodeIDReply{}
+	if err := s.service.GetNodeID(nil, nil, reply); err != nil {
+		return nil, err
+	}
+	resp := &infopb.GetNodeIDResponse{
+		NodeId: reply.NodeID[:],
+	}
+	if reply.NodePOP != nil {
+		resp.BlsPublicKey = reply.NodePOP.PublicKey[:]
+		resp.BlsProofOfPossession = reply.NodePOP.ProofOfPossession[:]
+	}
+	return resp, nil
+}
+
+func (s *Server) GetNodeIP(context.Context, *emptypb.Empty) (*infopb.GetNodeIPResponse, error) {
+	reply := &info.GetNodeIPReply{}
+	err := s.service.GetNodeIP(nil, nil, reply)
+	return &infopb.GetNodeIPResponse{
+		Ip: reply.IP,
+	}, err
+}
+
+func (s *Server) GetNetworkID(context.Context, *emptypb.Empty) (*infopb.GetNetworkIDResponse, error) {
+	reply := &info.GetNetworkIDReply{}
+	err := s.service.GetNetworkID(nil, nil, reply)
+	return &infopb.GetNetworkIDResponse{
+		NetworkId: uint32(reply.NetworkID),
+	}, err
+}
+
+func (s *Server) GetNetworkName(context.Context, *emptypb.Empty) (*infopb.GetNetworkNameResponse, error) {
+	reply := &info.GetNetworkNameReply{}
+	err := s.service.GetNetworkName(nil, nil, reply)
+	return &infopb.GetNetworkNameResponse{
+		NetworkName: reply.NetworkName,
+	}, err
+}
+
+func (s *Server) GetBlockchainID(_ context.Context, req *infopb.GetBlockchainIDRequest) (*infopb.GetBlockchainIDResponse, error) {
+	reply := &info.GetBlockchainIDReply{}
+	err := s.service.GetBlockchainID(nil, &info.GetBlockchainIDArgs{
+		Alias: req.Alias,
+	}, reply)
+	if err != nil {
+		return nil, err
+	}
+	return &infopb.GetBlockchainIDResponse{
+		BlockchainId: reply.BlockchainID[:],
+	}, nil
+}
+
+func (s *Server) IsBootstrapped(_ context.Context, req *infopb.IsBootstrappedRequest) (*infopb.IsBootstrappedResponse, error) {
+	reply := &info.IsBootstrappedResponse{}
+	err := s.service.IsBootstrapped(nil, &info.IsBootstrappedArgs{
+		Chain: req.Chain,
+	}, reply)
+	return &infopb.IsBootstrappedResponse{
+		IsBootstrapped: reply.IsBootstrapped,
+	}, err
+}
+
+func (s *Server) GetTxFee(context.Context, *emptypb.Empty) (*infopb.GetTxFeeResponse, error) {
+	reply := &info.GetTxFeeResponse{}
+	if err := s.service.GetTxFee(nil, nil, reply); err != nil {
+		return nil, err
+	}
+	return &infopb.GetTxFeeResponse{
+		TxFee:                         uint64(reply.TxFee),
+		CreateAssetTxFee:              uint64(reply.CreateAssetTxFee),
+		CreateSubnetTxFee:             uint64(reply.CreateSubnetTxFee),
+		TransformSubnetTxFee:          uint64(reply.TransformSubnetTxFee),
+		CreateBlockchainTxFee:         uint64(reply.CreateBlockchainTxFee),
+		AddPrimaryNetworkValidatorFee: uint64(reply.AddPrimaryNetworkValidatorFee),
+		AddPrimaryNetworkDelegatorFee: uint64(reply.AddPrimaryNetworkDelegatorFee),
+		AddSubnetValidatorFee:         uint64(reply.AddSubnetValidatorFee),
+		AddSubnetDelegatorFee:         uint64(reply.AddSubnetDelegatorFee),
+	}, nil
+}
+
+func (s *Server) Uptime(context.Context, *emptypb.Empty) (*infopb.UptimeResponse, error) {
+	reply := &info.UptimeResponse{}
+	if err := s.service.Uptime(nil, nil, reply); err != nil {
+		return nil, err
+	}
+	return &infopb.UptimeResponse{
+		RewardingStakePercentage:  float64(reply.RewardingStakePercentage),
+		WeightedAveragePercentage: float64(reply.WeightedAveragePercentage),
+	}, nil
+}
@@ -60,8 +60,8 @@ type Parameters struct {
 	VMManager                     vms.Manager
 }
 
-// NewService returns a new admin API service
-func NewService(
+// New returns a new info API service
+func New(
 	parameters Parameters,
 	log logging.Logger,
 	chainManager chains.Manager,
@@ -70,12 +70,8 @@ func NewService(
 	network network.Network,
 	validators validators.Set,
 	benchlist benchlist.Manager,
-) (*common.HTTPHandler, error) {
-	newServer := rpc.NewServer()
-	codec := json.NewCodec()
-	newServer.RegisterCodec(codec, "application/json")
-	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
-	if err := newServer.RegisterService(&Info{
+) *Info {
+	return &Info{
 		Parameters:   parameters,
 		log:          log,
 		chainManager: chainManager,
@@ -84,7 +80,16 @@ func NewService(
 		networking:   network,
 		validators:   validators,
 		benchlist:    benchlist,
-	}, "info"); err != nil {
+	}
+}
+
+// CreateHandler returns the JSON-RPC handler of this service
+func (service *Info) CreateHandler() (*common.HTTPHandler, error) {
+	newServer := rpc.NewServer()
+	codec := json.NewCodec()
+	newServer.RegisterCodec(codec, "application/json")
+	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
+	if err := newServer.RegisterService(service, "info"); err != nil {
 		return nil, err
 	}
 	return &common.HTTPHandler{Handler: newServer}, nil
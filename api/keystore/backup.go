@@ -0,0 +1,177 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keystore
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/argon2"
+
+	"github.com/dim4egster/qmallgo/database/encdb"
+	"github.com/dim4egster/qmallgo/database/memdb"
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/utils/hashing"
+	"github.com/dim4egster/qmallgo/utils/password"
+	"github.com/dim4egster/qmallgo/utils/wrappers"
+)
+
+const (
+	kdfArgon2id = "argon2id"
+
+	// cipherXChaCha20Poly1305 is the AEAD that encdb encrypts values with,
+	// keyed by the SHA256 hash of the user's password.
+	cipherXChaCha20Poly1305 = "xchacha20-poly1305"
+)
+
+var (
+	errUnknownBackupVersion = errors.New("unknown backup version")
+	errUnsupportedKDF       = errors.New("unsupported key derivation function")
+	errUnsupportedCipher    = errors.New("unsupported cipher")
+	errIncorrectPassword    = errors.New("incorrect password")
+	errMismatchedPrefix     = errors.New("chain prefix doesn't match its blockchainID")
+
+	defaultKDFParams = kdfParams{
+		Name:    kdfArgon2id,
+		Time:    password.KDFTime,
+		Memory:  password.KDFMemory,
+		Threads: password.KDFThreads,
+		KeyLen:  password.KDFKeyLen,
+	}
+)
+
+// kdfParams describes how the password hash of a backup was derived.
+type kdfParams struct {
+	Name    string `serialize:"true"`
+	Time    uint32 `serialize:"true"`
+	Memory  uint32 `serialize:"true"`
+	Threads uint8  `serialize:"true"`
+	KeyLen  uint32 `serialize:"true"`
+}
+
+// chainSection holds the data a user stored in a single blockchain's database.
+type chainSection struct {
+	// BlockchainID is [ids.Empty] if the chain wasn't known to the keystore
+	// when the backup was created.
+	BlockchainID ids.ID `serialize:"true"`
+	// Prefix is prepended to the keys in Data to get their keys in the user's
+	// database.
+	Prefix []byte   `serialize:"true"`
+	Data   []kvPair `serialize:"true"`
+}
+
+// backup is the versioned, self-describing encoding of a user that is
+// produced by ExportUser.
+type backup struct {
+	KDF           kdfParams `serialize:"true"`
+	password.Hash `serialize:"true"`
+	Cipher        string         `serialize:"true"`
+	Chains        []chainSection `serialize:"true"`
+}
+
+// VerifyBackup checks that [backupBytes], as returned by ExportUser, is a well
+// formed backup whose password is [pw] and whose values can all be decrypted
+// with [pw]. The backup isn't imported into any keystore.
+func VerifyBackup(backupBytes []byte, pw string) error {
+	b, err := parseBackup(backupBytes)
+	if err != nil {
+		return err
+	}
+	return b.verify(pw)
+}
+
+// parseBackup parses both versioned backups and the unversioned users that
+// were exported by previous versions of the keystore.
+func parseBackup(backupBytes []byte) (*backup, error) {
+	p := wrappers.Packer{Bytes: backupBytes}
+	version := p.UnpackShort()
+	if p.Errored() {
+		return nil, p.Err
+	}
+
+	switch version {
+	case codecVersion:
+		userData := user{}
+		if _, err := c.Unmarshal(backupBytes, &userData); err != nil {
+			return nil, err
+		}
+		// The keys of an unversioned user are the full keys of the user's
+		// database, so they can't be attributed to a chain.
+		return &backup{
+			KDF:    defaultKDFParams,
+			Hash:   userData.Hash,
+			Cipher: cipherXChaCha20Poly1305,
+			Chains: []chainSection{{
+				Data: userData.Data,
+			}},
+		}, nil
+	case backupCodecVersion:
+		b := &backup{}
+		_, err := c.Unmarshal(backupBytes, b)
+		return b, err
+	default:
+		return nil, fmt.Errorf("%w: %d", errUnknownBackupVersion, version)
+	}
+}
+
+// checkPassword returns nil iff [pw] is the password of this backup.
+//
+// Only backups whose password hash was derived with the parameters the
+// keystore uses are accepted. Deriving the hash with the parameters of an
+// untrusted backup could otherwise panic or exhaust the node's memory.
+func (b *backup) checkPassword(pw string) error {
+	if b.KDF != defaultKDFParams {
+		return fmt.Errorf(
+			"%w: %s with time %d, memory %d, %d threads and %d byte keys",
+			errUnsupportedKDF,
+			b.KDF.Name,
+			b.KDF.Time,
+			b.KDF.Memory,
+			b.KDF.Threads,
+			b.KDF.KeyLen,
+		)
+	}
+	key := argon2.IDKey([]byte(pw), b.Hash.Salt[:], b.KDF.Time, b.KDF.Memory, b.KDF.Threads, b.KDF.KeyLen)
+	if subtle.ConstantTimeCompare(key, b.Hash.Password[:]) != 1 {
+		return errIncorrectPassword
+	}
+	return nil
+}
+
+// verify checks the password of this backup and that every value can be
+// decrypted with it.
+func (b *backup) verify(pw string) error {
+	if err := b.checkPassword(pw); err != nil {
+		return err
+	}
+	if b.Cipher != cipherXChaCha20Poly1305 {
+		return fmt.Errorf("%w: %s", errUnsupportedCipher, b.Cipher)
+	}
+
+	for _, chain := range b.Chains {
+		if chain.BlockchainID != ids.Empty && !bytes.Equal(chain.Prefix, hashing.ComputeHash256(chain.BlockchainID[:])) {
+			return fmt.Errorf("%w: %s", errMismatchedPrefix, chain.BlockchainID)
+		}
+
+		rawDB := memdb.New()
+		for _, kvp := range chain.Data {
+			if err := rawDB.Put(kvp.Key, kvp.Value); err != nil {
+				return err
+			}
+		}
+		db, err := encdb.New([]byte(pw), rawDB)
+		if err != nil {
+			return err
+		}
+
+		for _, kvp := range chain.Data {
+			if _, err := db.Get(kvp.Key); err != nil {
+				return fmt.Errorf("couldn't decrypt data of chain %s: %w", chain.BlockchainID, err)
+			}
+		}
+	}
+	return nil
+}
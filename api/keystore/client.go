@@ -24,6 +24,8 @@ type Client interface {
 	ImportUser(ctx context.Context, importTo api.UserPass, exportedUser []byte, options ...rpc.Option) error
 	// Delete the given user
 	DeleteUser(context.Context, api.UserPass, ...rpc.Option) error
+	// Re-encrypt the given user's data with [newPassword]
+	ChangePassword(ctx context.Context, user api.UserPass, newPassword string, options ...rpc.Option) error
 }
 
 // Client implementation for Qmall Keystore API Endpoint
@@ -75,3 +77,10 @@ func (c *client) ImportUser(ctx context.Context, user api.UserPass, account []by
 func (c *client) DeleteUser(ctx context.Context, user api.UserPass, options ...rpc.Option) error {
 	return c.requester.SendRequest(ctx, "deleteUser", &user, &api.EmptyReply{}, options...)
 }
+
+func (c *client) ChangePassword(ctx context.Context, user api.UserPass, newPassword string, options ...rpc.Option) error {
+	return c.requester.SendRequest(ctx, "changePassword", &ChangePasswordArgs{
+		UserPass:    user,
+		NewPassword: newPassword,
+	}, &api.EmptyReply{}, options...)
+}
@@ -14,6 +14,9 @@ const (
 	maxSliceLength = 256 * 1024
 
 	codecVersion = 0
+	// backupCodecVersion is the version of the user backups produced by
+	// ExportUser. Users exported with [codecVersion] are still importable.
+	backupCodecVersion = 1
 )
 
 var c codec.Manager
@@ -24,4 +27,7 @@ func init() {
 	if err := c.RegisterCodec(codecVersion, lc); err != nil {
 		panic(err)
 	}
+	if err := c.RegisterCodec(backupCodecVersion, lc); err != nil {
+		panic(err)
+	}
 }
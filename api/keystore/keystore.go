@@ -4,6 +4,7 @@
 package keystore
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"net/http"
@@ -17,6 +18,7 @@ import (
 	"github.com/dim4egster/qmallgo/database/manager"
 	"github.com/dim4egster/qmallgo/database/prefixdb"
 	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/utils/hashing"
 	"github.com/dim4egster/qmallgo/utils/json"
 	"github.com/dim4egster/qmallgo/utils/logging"
 	"github.com/dim4egster/qmallgo/utils/password"
@@ -66,13 +68,19 @@ type Keystore interface {
 	ListUsers() ([]string, error)
 
 	// ImportUser imports a serialized encoding of a user's information complete
-	// with encrypted database values. The password is integrity checked.
+	// with encrypted database values. The password is integrity checked and
+	// every value must be decryptable with it.
 	ImportUser(username, pw string, user []byte) error
 
-	// ExportUser exports a serialized encoding of a user's information complete
-	// with encrypted database values.
+	// ExportUser exports a versioned backup of a user's information complete
+	// with encrypted database values, grouped by blockchain.
 	ExportUser(username, pw string) ([]byte, error)
 
+	// ChangePassword re-encrypts all of the user's data with [newPW] and
+	// replaces the user's password in a single atomic write. Databases that
+	// were previously returned for this user must not be used afterwards.
+	ChangePassword(username, oldPW, newPW string) error
+
 	// Get the password that is used by [username]. If [username] doesn't exist,
 	// no error is returned and a nil password hash is returned.
 	getPassword(username string) (*password.Hash, error)
@@ -97,6 +105,11 @@ type keystore struct {
 	// Value: The hash of that user's password
 	usernameToPassword map[string]*password.Hash
 
+	// Key: The hash of a blockchainID, which prefixes the blockchain's data in
+	//      each user's database
+	// Value: That blockchainID
+	blockchainIDs map[ids.ID]ids.ID
+
 	// Used to persist users and their data
 	userDB database.Database
 	bcDB   database.Database
@@ -114,6 +127,7 @@ func New(log logging.Logger, dbManager manager.Manager) Keystore {
 	return &keystore{
 		log:                log,
 		usernameToPassword: make(map[string]*password.Hash),
+		blockchainIDs:      make(map[ids.ID]ids.ID),
 		userDB:             prefixdb.New(usersPrefix, currentDB.Database),
 		bcDB:               prefixdb.New(bcsPrefix, currentDB.Database),
 	}
@@ -131,6 +145,10 @@ func (ks *keystore) CreateHandler() (http.Handler, error) {
 }
 
 func (ks *keystore) NewBlockchainKeyStore(blockchainID ids.ID) BlockchainKeystore {
+	ks.lock.Lock()
+	ks.addBlockchainID(blockchainID)
+	ks.lock.Unlock()
+
 	return &blockchainKeystore{
 		blockchainID: blockchainID,
 		ks:           ks,
@@ -161,6 +179,8 @@ func (ks *keystore) GetRawDatabase(bID ids.ID, username, pw string) (database.Da
 		return nil, fmt.Errorf("incorrect password for user %q", username)
 	}
 
+	ks.addBlockchainID(bID)
+
 	userDB := prefixdb.New([]byte(username), ks.bcDB)
 	bcDB := prefixdb.NewNested(bID[:], userDB)
 	return bcDB, nil
@@ -293,15 +313,15 @@ func (ks *keystore) ImportUser(username, pw string, userBytes []byte) error {
 		return fmt.Errorf("user already exists: %s", username)
 	}
 
-	userData := user{}
-	if _, err := c.Unmarshal(userBytes, &userData); err != nil {
+	b, err := parseBackup(userBytes)
+	if err != nil {
 		return err
 	}
-	if !userData.Hash.Check(pw) {
-		return fmt.Errorf("incorrect password for user %q", username)
+	if err := b.verify(pw); err != nil {
+		return fmt.Errorf("couldn't verify backup of user %q: %w", username, err)
 	}
 
-	usrBytes, err := c.Marshal(codecVersion, &userData.Hash)
+	usrBytes, err := c.Marshal(codecVersion, &b.Hash)
 	if err != nil {
 		return err
 	}
@@ -313,16 +333,26 @@ func (ks *keystore) ImportUser(username, pw string, userBytes []byte) error {
 
 	userDataDB := prefixdb.New([]byte(username), ks.bcDB)
 	dataBatch := userDataDB.NewBatch()
-	for _, kvp := range userData.Data {
-		if err := dataBatch.Put(kvp.Key, kvp.Value); err != nil {
-			return fmt.Errorf("error on database put: %w", err)
+	for _, chain := range b.Chains {
+		for _, kvp := range chain.Data {
+			key := make([]byte, len(chain.Prefix)+len(kvp.Key))
+			copy(key, chain.Prefix)
+			copy(key[len(chain.Prefix):], kvp.Key)
+			if err := dataBatch.Put(key, kvp.Value); err != nil {
+				return fmt.Errorf("error on database put: %w", err)
+			}
 		}
 	}
 
 	if err := atomic.WriteAll(dataBatch, userBatch); err != nil {
 		return err
 	}
-	ks.usernameToPassword[username] = &userData.Hash
+	ks.usernameToPassword[username] = &b.Hash
+	for _, chain := range b.Chains {
+		if chain.BlockchainID != ids.Empty {
+			ks.addBlockchainID(chain.BlockchainID)
+		}
+	}
 	return nil
 }
 
@@ -345,14 +375,41 @@ func (ks *keystore) ExportUser(username, pw string) ([]byte, error) {
 		return nil, fmt.Errorf("incorrect password for user %q", username)
 	}
 
-	userDB := prefixdb.New([]byte(username), ks.bcDB)
+	b := &backup{
+		KDF:    defaultKDFParams,
+		Hash:   *passwordHash,
+		Cipher: cipherXChaCha20Poly1305,
+	}
 
-	userData := user{Hash: *passwordHash}
+	// Each blockchain's data is stored under the hash of its blockchainID, so
+	// the data of a blockchain is contiguous.
+	userDB := prefixdb.New([]byte(username), ks.bcDB)
 	it := userDB.NewIterator()
 	defer it.Release()
+
+	var chain *chainSection
 	for it.Next() {
-		userData.Data = append(userData.Data, kvPair{
-			Key:   it.Key(),
+		key := it.Key()
+		prefixLen := hashing.HashLen
+		if len(key) < prefixLen {
+			prefixLen = 0
+		}
+		prefix := key[:prefixLen]
+		if chain == nil || !bytes.Equal(chain.Prefix, prefix) {
+			b.Chains = append(b.Chains, chainSection{
+				Prefix: prefix,
+			})
+			chain = &b.Chains[len(b.Chains)-1]
+			if prefixLen == hashing.HashLen {
+				prefixID, err := ids.ToID(prefix)
+				if err != nil {
+					return nil, err
+				}
+				chain.BlockchainID = ks.blockchainIDs[prefixID]
+			}
+		}
+		chain.Data = append(chain.Data, kvPair{
+			Key:   key[prefixLen:],
 			Value: it.Value(),
 		})
 	}
@@ -360,8 +417,78 @@ func (ks *keystore) ExportUser(username, pw string) ([]byte, error) {
 		return nil, err
 	}
 
-	// Return the byte representation of the user
-	return c.Marshal(codecVersion, &userData)
+	// Return the byte representation of the backup
+	return c.Marshal(backupCodecVersion, b)
+}
+
+func (ks *keystore) ChangePassword(username, oldPW, newPW string) error {
+	if username == "" {
+		return errEmptyUsername
+	}
+	if len(username) > maxUserLen {
+		return errUserMaxLength
+	}
+
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	passwordHash, err := ks.getPassword(username)
+	if err != nil {
+		return err
+	}
+	if passwordHash == nil || !passwordHash.Check(oldPW) {
+		return fmt.Errorf("incorrect password for user %q", username)
+	}
+
+	if err := password.IsValid(newPW, password.OK); err != nil {
+		return err
+	}
+
+	newPasswordHash := &password.Hash{}
+	if err := newPasswordHash.Set(newPW); err != nil {
+		return err
+	}
+
+	passwordBytes, err := c.Marshal(codecVersion, newPasswordHash)
+	if err != nil {
+		return err
+	}
+
+	userBatch := ks.userDB.NewBatch()
+	if err := userBatch.Put([]byte(username), passwordBytes); err != nil {
+		return err
+	}
+
+	// Every blockchain's values are encrypted with the same key, so all of the
+	// user's data can be decrypted and re-encrypted at once.
+	userDataDB := prefixdb.New([]byte(username), ks.bcDB)
+	oldDB, err := encdb.New([]byte(oldPW), userDataDB)
+	if err != nil {
+		return err
+	}
+	newDB, err := encdb.New([]byte(newPW), userDataDB)
+	if err != nil {
+		return err
+	}
+	dataBatch := newDB.NewBatch()
+
+	it := oldDB.NewIterator()
+	defer it.Release()
+
+	for it.Next() {
+		if err := dataBatch.Put(it.Key(), it.Value()); err != nil {
+			return err
+		}
+	}
+	if err := it.Error(); err != nil {
+		return fmt.Errorf("couldn't decrypt data of user %q: %w", username, err)
+	}
+
+	if err := atomic.WriteAll(dataBatch, userBatch); err != nil {
+		return err
+	}
+	ks.usernameToPassword[username] = newPasswordHash
+	return nil
 }
 
 func (ks *keystore) getPassword(username string) (*password.Hash, error) {
@@ -386,3 +513,11 @@ func (ks *keystore) getPassword(username string) (*password.Hash, error) {
 	_, err = c.Unmarshal(userBytes, passwordHash)
 	return passwordHash, err
 }
+
+// addBlockchainID records [blockchainID] so that its data can be attributed to
+// it in backups.
+//
+// Assumes [ks.lock] is held.
+func (ks *keystore) addBlockchainID(blockchainID ids.ID) {
+	ks.blockchainIDs[ids.ID(hashing.ComputeHash256Array(blockchainID[:]))] = blockchainID
+}
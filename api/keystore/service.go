@@ -103,6 +103,21 @@ func (s *service) ExportUser(_ *http.Request, args *ExportUserArgs, reply *Expor
 	return nil
 }
 
+type ChangePasswordArgs struct {
+	// The username and current password of the user
+	api.UserPass
+	// The password that the user's data is re-encrypted with
+	NewPassword string `json:"newPassword"`
+}
+
+func (s *service) ChangePassword(_ *http.Request, args *ChangePasswordArgs, _ *api.EmptyReply) error {
+	s.ks.log.Debug("Keystore: ChangePassword called",
+		logging.UserString("username", args.Username),
+	)
+
+	return s.ks.ChangePassword(args.Username, args.Password, args.NewPassword)
+}
+
 // CreateTestKeystore returns a new keystore that can be utilized for testing
 func CreateTestKeystore() (Keystore, error) {
 	dbManager, err := manager.NewManagerFromDBs([]*manager.VersionedDatabase{
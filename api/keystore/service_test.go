@@ -6,11 +6,15 @@ package keystore
 import (
 	"bytes"
 	"fmt"
+	"math"
 	"math/rand"
 	"reflect"
 	"testing"
 
+	"github.com/stretchr/testify/require"
+
 	"github.com/dim4egster/qmallgo/api"
+	"github.com/dim4egster/qmallgo/database/prefixdb"
 	"github.com/dim4egster/qmallgo/ids"
 	"github.com/dim4egster/qmallgo/utils/formatting"
 )
@@ -324,6 +328,212 @@ func TestServiceExportImport(t *testing.T) {
 	}
 }
 
+func TestServiceExportPerChainBackup(t *testing.T) {
+	require := require.New(t)
+
+	ks, err := CreateTestKeystore()
+	require.NoError(err)
+	require.NoError(ks.CreateUser("bob", strongPassword))
+
+	chainA := ids.GenerateTestID()
+	chainB := ids.GenerateTestID()
+	for _, chainID := range []ids.ID{chainA, chainB} {
+		db, err := ks.NewBlockchainKeyStore(chainID).GetDatabase("bob", strongPassword)
+		require.NoError(err)
+		require.NoError(db.Put(chainID[:], []byte("hello")))
+	}
+
+	backupBytes, err := ks.ExportUser("bob", strongPassword)
+	require.NoError(err)
+	require.NoError(VerifyBackup(backupBytes, strongPassword))
+	require.ErrorIs(VerifyBackup(backupBytes, "wrong password"), errIncorrectPassword)
+
+	b, err := parseBackup(backupBytes)
+	require.NoError(err)
+	require.Equal(defaultKDFParams, b.KDF)
+	require.Equal(cipherXChaCha20Poly1305, b.Cipher)
+	require.Len(b.Chains, 2)
+	for _, chain := range b.Chains {
+		require.Len(chain.Data, 1)
+		require.Equal(chain.BlockchainID[:], chain.Data[0].Key)
+	}
+
+	// Corrupting a value must be detected without importing the backup.
+	corruptedValue := append([]byte{}, b.Chains[0].Data[0].Value...)
+	corruptedValue[len(corruptedValue)-1] ^= 1
+	b.Chains[0].Data[0].Value = corruptedValue
+	corruptedBytes, err := c.Marshal(backupCodecVersion, b)
+	require.NoError(err)
+	require.Error(VerifyBackup(corruptedBytes, strongPassword))
+
+	newKS, err := CreateTestKeystore()
+	require.NoError(err)
+	require.Error(newKS.ImportUser("bob", strongPassword, corruptedBytes))
+	require.NoError(newKS.ImportUser("bob", strongPassword, backupBytes))
+
+	db, err := newKS.GetDatabase(chainB, "bob", strongPassword)
+	require.NoError(err)
+	val, err := db.Get(chainB[:])
+	require.NoError(err)
+	require.Equal([]byte("hello"), val)
+}
+
+func TestServiceImportBackupKDFParams(t *testing.T) {
+	ks, err := CreateTestKeystore()
+	require.NoError(t, err)
+	require.NoError(t, ks.CreateUser("bob", strongPassword))
+	backupBytes, err := ks.ExportUser("bob", strongPassword)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		mutate func(*kdfParams)
+	}{
+		{
+			name:   "unknown kdf",
+			mutate: func(p *kdfParams) { p.Name = "scrypt" },
+		},
+		{
+			name:   "zero time",
+			mutate: func(p *kdfParams) { p.Time = 0 },
+		},
+		{
+			name:   "huge time",
+			mutate: func(p *kdfParams) { p.Time = math.MaxUint32 },
+		},
+		{
+			name:   "huge memory",
+			mutate: func(p *kdfParams) { p.Memory = math.MaxUint32 },
+		},
+		{
+			name:   "zero threads",
+			mutate: func(p *kdfParams) { p.Threads = 0 },
+		},
+		{
+			name:   "short key",
+			mutate: func(p *kdfParams) { p.KeyLen = 16 },
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			b, err := parseBackup(backupBytes)
+			require.NoError(err)
+			test.mutate(&b.KDF)
+			badBytes, err := c.Marshal(backupCodecVersion, b)
+			require.NoError(err)
+
+			require.ErrorIs(VerifyBackup(badBytes, strongPassword), errUnsupportedKDF)
+
+			newKS, err := CreateTestKeystore()
+			require.NoError(err)
+			require.ErrorIs(newKS.ImportUser("bob", strongPassword, badBytes), errUnsupportedKDF)
+		})
+	}
+}
+
+func TestServiceImportUnversionedUser(t *testing.T) {
+	require := require.New(t)
+
+	ks, err := CreateTestKeystore()
+	require.NoError(err)
+	require.NoError(ks.CreateUser("bob", strongPassword))
+
+	db, err := ks.GetDatabase(ids.Empty, "bob", strongPassword)
+	require.NoError(err)
+	require.NoError(db.Put([]byte("hello"), []byte("world")))
+
+	// Build the user the way previous versions of ExportUser did.
+	passwordHash, err := ks.(*keystore).getPassword("bob")
+	require.NoError(err)
+	userData := user{Hash: *passwordHash}
+	it := prefixdb.New([]byte("bob"), ks.(*keystore).bcDB).NewIterator()
+	for it.Next() {
+		userData.Data = append(userData.Data, kvPair{
+			Key:   it.Key(),
+			Value: it.Value(),
+		})
+	}
+	require.NoError(it.Error())
+	it.Release()
+	userBytes, err := c.Marshal(codecVersion, &userData)
+	require.NoError(err)
+
+	require.NoError(VerifyBackup(userBytes, strongPassword))
+
+	newKS, err := CreateTestKeystore()
+	require.NoError(err)
+	require.NoError(newKS.ImportUser("bob", strongPassword, userBytes))
+
+	db, err = newKS.GetDatabase(ids.Empty, "bob", strongPassword)
+	require.NoError(err)
+	val, err := db.Get([]byte("hello"))
+	require.NoError(err)
+	require.Equal([]byte("world"), val)
+}
+
+func TestServiceChangePassword(t *testing.T) {
+	require := require.New(t)
+
+	ks, err := CreateTestKeystore()
+	require.NoError(err)
+	s := service{ks: ks.(*keystore)}
+	require.NoError(s.CreateUser(nil, &api.UserPass{
+		Username: "bob",
+		Password: strongPassword,
+	}, &api.EmptyReply{}))
+
+	chainIDs := []ids.ID{ids.Empty, ids.GenerateTestID()}
+	for _, chainID := range chainIDs {
+		db, err := ks.GetDatabase(chainID, "bob", strongPassword)
+		require.NoError(err)
+		require.NoError(db.Put([]byte("hello"), chainID[:]))
+	}
+
+	newPassword := strongPassword + "!"
+	err = s.ChangePassword(nil, &ChangePasswordArgs{
+		UserPass: api.UserPass{
+			Username: "bob",
+			Password: "wrong password",
+		},
+		NewPassword: newPassword,
+	}, &api.EmptyReply{})
+	require.Error(err)
+
+	err = s.ChangePassword(nil, &ChangePasswordArgs{
+		UserPass: api.UserPass{
+			Username: "bob",
+			Password: strongPassword,
+		},
+		NewPassword: "weak",
+	}, &api.EmptyReply{})
+	require.Error(err)
+
+	require.NoError(s.ChangePassword(nil, &ChangePasswordArgs{
+		UserPass: api.UserPass{
+			Username: "bob",
+			Password: strongPassword,
+		},
+		NewPassword: newPassword,
+	}, &api.EmptyReply{}))
+
+	_, err = ks.GetDatabase(ids.Empty, "bob", strongPassword)
+	require.Error(err)
+
+	for _, chainID := range chainIDs {
+		db, err := ks.GetDatabase(chainID, "bob", newPassword)
+		require.NoError(err)
+		val, err := db.Get([]byte("hello"))
+		require.NoError(err)
+		require.Equal(chainID[:], val)
+	}
+
+	backupBytes, err := ks.ExportUser("bob", newPassword)
+	require.NoError(err)
+	require.NoError(VerifyBackup(backupBytes, newPassword))
+}
+
 func TestServiceDeleteUser(t *testing.T) {
 	testUser := "testUser"
 	password := "passwTest@fake01ord"
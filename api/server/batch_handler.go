@@ -0,0 +1,211 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/dim4egster/qmallgo/utils/json"
+
+	stdjson "encoding/json"
+)
+
+const (
+	// JSON-RPC 2.0 error codes
+	jsonRPCParseError     = -32700
+	jsonRPCInvalidRequest = -32600
+	jsonRPCInternalError  = -32603
+)
+
+var _ http.Handler = &batchHandler{}
+
+type jsonRPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type jsonRPCErrorResponse struct {
+	Version string              `json:"jsonrpc"`
+	Error   jsonRPCError        `json:"error"`
+	ID      *stdjson.RawMessage `json:"id"`
+}
+
+// batchHandler serves JSON-RPC 2.0 batch requests by passing each request of
+// the batch to [handler] and responding with the array of their responses.
+// Requests that aren't batches are passed to [handler] unmodified.
+//
+// The headers of the responses to the requests of a batch are set on the
+// response to the batch. If the responses disagree on a header, the value of
+// the first response is kept, except for Retry-After, which is set to the
+// longest delay.
+type batchHandler struct {
+	handler http.Handler
+	// The maximum number of requests in a batch. If 0, batches are rejected.
+	maxBatchSize int
+	// The maximum number of bytes of a request body that is read.
+	maxRequestBodySize int64
+}
+
+func newBatchHandler(handler http.Handler, maxBatchSize int, maxRequestBodySize int64) http.Handler {
+	return &batchHandler{
+		handler:            handler,
+		maxBatchSize:       maxBatchSize,
+		maxRequestBodySize: maxRequestBodySize,
+	}
+}
+
+func (h *batchHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.Body == nil {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+
+	// The body was parsed already if the request passed through the rate
+	// limiter or the auth wrapper.
+	r, request, err := json.ParseRequest(w, r, h.maxRequestBodySize)
+	if err != nil {
+		if errors.Is(err, json.ErrRequestTooLarge) {
+			msg := fmt.Sprintf("request body exceeds the maximum of %d bytes", h.maxRequestBodySize)
+			http.Error(w, msg, http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, fmt.Sprintf("couldn't read request body: %s", err), http.StatusBadRequest)
+		return
+	}
+	if !request.IsBatch {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+	if request.Err != nil {
+		writeJSONRPCResponse(w, newJSONRPCErrorResponse(nil, jsonRPCParseError, request.Err.Error()))
+		return
+	}
+
+	switch {
+	case len(request.Batch) == 0:
+		writeJSONRPCResponse(w, newJSONRPCErrorResponse(nil, jsonRPCInvalidRequest, "empty batch"))
+		return
+	case len(request.Batch) > h.maxBatchSize:
+		msg := fmt.Sprintf("batch of %d requests exceeds the maximum of %d", len(request.Batch), h.maxBatchSize)
+		writeJSONRPCResponse(w, newJSONRPCErrorResponse(nil, jsonRPCInvalidRequest, msg))
+		return
+	}
+
+	var (
+		responses  = make([]stdjson.RawMessage, 0, len(request.Batch))
+		retryAfter = -1
+	)
+	for i, batchRequest := range request.Batch {
+		id, isNotification := requestID(batchRequest)
+		response, header := h.serveRequest(r, &json.Request{
+			Body:    batchRequest,
+			Methods: request.Methods[i : i+1],
+		})
+		retryAfter = copyHeader(w.Header(), header, retryAfter)
+		if isNotification {
+			continue
+		}
+		if !stdjson.Valid(response) {
+			msg := string(bytes.TrimSpace(response))
+			if msg == "" {
+				msg = "empty response"
+			}
+			response, err = stdjson.Marshal(newJSONRPCErrorResponse(id, jsonRPCInternalError, msg))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+		responses = append(responses, response)
+	}
+
+	if retryAfter >= 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	}
+
+	// A batch of notifications has no response.
+	if len(responses) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSONRPCResponse(w, responses)
+}
+
+// serveRequest passes a copy of [r] whose body is [request] to [h.handler] and
+// returns the body and the headers of its response.
+func (h *batchHandler) serveRequest(r *http.Request, request *json.Request) ([]byte, http.Header) {
+	subRequest := json.WithRequest(r.Clone(r.Context()), request)
+
+	recorder := &responseRecorder{header: make(http.Header)}
+	h.handler.ServeHTTP(recorder, subRequest)
+	return recorder.body.Bytes(), recorder.header
+}
+
+// copyHeader sets each header of [src] on [dst] that isn't already set, other
+// than the headers that describe the body of [src]. Returns the larger of
+// [retryAfter] and the Retry-After delay, in seconds, of [src].
+func copyHeader(dst, src http.Header, retryAfter int) int {
+	for key, values := range src {
+		switch key {
+		case "Content-Length", "Content-Type", "Content-Encoding", "Retry-After":
+			continue
+		}
+		if _, ok := dst[key]; !ok {
+			dst[key] = append([]string(nil), values...)
+		}
+	}
+	if delay, err := strconv.Atoi(src.Get("Retry-After")); err == nil && delay > retryAfter {
+		return delay
+	}
+	return retryAfter
+}
+
+// requestID returns the ID of [request] and whether [request] is a
+// notification, i.e. a request without an ID that expects no response.
+func requestID(request []byte) (*stdjson.RawMessage, bool) {
+	fields := map[string]*stdjson.RawMessage{}
+	if err := stdjson.Unmarshal(request, &fields); err != nil {
+		return nil, false
+	}
+	id, ok := fields["id"]
+	return id, !ok
+}
+
+func newJSONRPCErrorResponse(id *stdjson.RawMessage, code int, msg string) *jsonRPCErrorResponse {
+	return &jsonRPCErrorResponse{
+		Version: "2.0",
+		Error: jsonRPCError{
+			Code:    code,
+			Message: msg,
+		},
+		ID: id,
+	}
+}
+
+func writeJSONRPCResponse(w http.ResponseWriter, response interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if err := stdjson.NewEncoder(w).Encode(response); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// responseRecorder buffers the response to a single request of a batch.
+type responseRecorder struct {
+	header http.Header
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	return r.body.Write(b)
+}
+
+func (*responseRecorder) WriteHeader(int) {}
@@ -0,0 +1,229 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	stdjson "encoding/json"
+
+	"github.com/gorilla/rpc/v2"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/snow/engine/common"
+	"github.com/dim4egster/qmallgo/utils/json"
+	"github.com/dim4egster/qmallgo/utils/logging"
+)
+
+var errTestFailure = errors.New("test failure")
+
+type EchoArgs struct {
+	Message string `json:"message"`
+	Fail    bool   `json:"fail"`
+}
+
+type EchoReply struct {
+	Message string `json:"message"`
+}
+
+type testService struct{}
+
+func (*testService) Echo(_ *http.Request, args *EchoArgs, reply *EchoReply) error {
+	if args.Fail {
+		return errTestFailure
+	}
+	reply.Message = args.Message
+	return nil
+}
+
+type testResponse struct {
+	Result *EchoReply    `json:"result"`
+	Error  *jsonRPCError `json:"error"`
+	ID     *int          `json:"id"`
+}
+
+func newTestService(t *testing.T) http.Handler {
+	s := rpc.NewServer()
+	s.RegisterCodec(json.NewCodec(), "application/json")
+	require.NoError(t, s.RegisterService(&testService{}, "test"))
+	return s
+}
+
+func newTestBatchRequest(body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "http://127.0.0.1:4960/ext/test", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+func TestBatchHandler(t *testing.T) {
+	require := require.New(t)
+
+	h := newBatchHandler(newTestService(t), 3, 1024)
+
+	rr := serve(h, newTestBatchRequest(`[
+		{"jsonrpc":"2.0","id":1,"method":"test.echo","params":{"message":"hello"}},
+		{"jsonrpc":"2.0","method":"test.echo","params":{"message":"notification"}},
+		{"jsonrpc":"2.0","id":2,"method":"test.echo","params":{"fail":true}}
+	]`))
+	require.Equal(http.StatusOK, rr.Code)
+
+	var responses []testResponse
+	require.NoError(stdjson.Unmarshal(rr.Body.Bytes(), &responses))
+	require.Len(responses, 2)
+	require.Equal(1, *responses[0].ID)
+	require.Equal("hello", responses[0].Result.Message)
+	require.Nil(responses[0].Error)
+	require.Equal(2, *responses[1].ID)
+	require.Equal(errTestFailure.Error(), responses[1].Error.Message)
+
+	// Requests that aren't batches are served directly.
+	rr = serve(h, newTestBatchRequest(`{"jsonrpc":"2.0","id":3,"method":"test.echo","params":{"message":"single"}}`))
+	require.Equal(http.StatusOK, rr.Code)
+	var response testResponse
+	require.NoError(stdjson.Unmarshal(rr.Body.Bytes(), &response))
+	require.Equal("single", response.Result.Message)
+}
+
+func TestBatchHandlerRejectsInvalidBatches(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		code int
+	}{
+		{
+			name: "empty",
+			body: `[]`,
+			code: jsonRPCInvalidRequest,
+		},
+		{
+			name: "too large",
+			body: `[{"id":1},{"id":2},{"id":3},{"id":4}]`,
+			code: jsonRPCInvalidRequest,
+		},
+		{
+			name: "malformed",
+			body: `[{"id":1},`,
+			code: jsonRPCParseError,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			handler := &testHandler{}
+			rr := serve(newBatchHandler(handler, 3, 1024), newTestBatchRequest(test.body))
+			require.False(handler.called)
+
+			var response testResponse
+			require.NoError(stdjson.Unmarshal(rr.Body.Bytes(), &response))
+			require.Nil(response.ID)
+			require.Equal(test.code, response.Error.Code)
+		})
+	}
+}
+
+func TestBatchHandlerRejectsLargeBodies(t *testing.T) {
+	require := require.New(t)
+
+	handler := &testHandler{}
+	h := newBatchHandler(handler, 100, 64)
+
+	rr := serve(h, newTestBatchRequest(`[{"jsonrpc":"2.0","id":1,"method":"test.echo","params":{"message":"too long"}}]`))
+	require.Equal(http.StatusRequestEntityTooLarge, rr.Code)
+	require.False(handler.called)
+
+	rr = serve(h, newTestBatchRequest(`[{"jsonrpc":"2.0","id":1}]`))
+	require.Equal(http.StatusOK, rr.Code)
+	require.True(handler.called)
+}
+
+func TestBatchHandlerCopiesHeaders(t *testing.T) {
+	require := require.New(t)
+
+	h := newBatchHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(err)
+		var request testResponse
+		require.NoError(stdjson.Unmarshal(body, &request))
+
+		w.Header().Set("Retry-After", strconv.Itoa(*request.ID))
+		w.Header().Set("X-Request-ID", strconv.Itoa(*request.ID))
+		w.Header().Set("Content-Type", "text/plain")
+		_, err = w.Write(body)
+		require.NoError(err)
+	}), 3, 1024)
+
+	rr := serve(h, newTestBatchRequest(`[{"id":1},{"id":3},{"id":2}]`))
+	require.Equal(http.StatusOK, rr.Code)
+	require.Equal("3", rr.Header().Get("Retry-After"))
+	require.Equal("1", rr.Header().Get("X-Request-ID"))
+	require.Equal("application/json; charset=utf-8", rr.Header().Get("Content-Type"))
+}
+
+func TestBatchHandlerReusesParsedRequest(t *testing.T) {
+	require := require.New(t)
+
+	var methods []string
+	h := newBatchHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Each request of the batch is passed down already parsed.
+		_, request, err := json.ParseRequest(w, r, 0)
+		require.NoError(err)
+		require.NoError(request.Err)
+		methods = append(methods, request.Methods...)
+		_, err = w.Write(request.Body)
+		require.NoError(err)
+	}), 3, 1024)
+
+	req := newTestBatchRequest(`[{"id":1,"method":"test.a"},{"id":2,"method":"test.b"}]`)
+	req, request, err := json.ParseRequest(httptest.NewRecorder(), req, 1024)
+	require.NoError(err)
+	require.Equal([]string{"test.a", "test.b"}, request.Methods)
+
+	// The body is read once, by the first call to ParseRequest.
+	req.Body = io.NopCloser(strings.NewReader(""))
+	rr := serve(h, req)
+	require.Equal(http.StatusOK, rr.Code)
+	require.Equal([]string{"test.a", "test.b"}, methods)
+}
+
+func TestServerCompressesBatchResponses(t *testing.T) {
+	require := require.New(t)
+
+	s := &server{}
+	s.Initialize(logging.NoLog{}, nil, "127.0.0.1", 0, []string{"*"}, 100, 1024*1024, time.Second, ids.EmptyNodeID)
+	require.NoError(s.AddRoute(&common.HTTPHandler{
+		LockOptions: common.NoLock,
+		Handler:     newTestService(t),
+	}, nil, "test", ""))
+
+	requests := make([]string, 100)
+	for i := range requests {
+		requests[i] = `{"jsonrpc":"2.0","id":1,"method":"test.echo","params":{"message":"a message long enough to be compressed"}}`
+	}
+	req := newTestBatchRequest("[" + strings.Join(requests, ",") + "]")
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	rr := serve(s.handler, req)
+	require.Equal(http.StatusOK, rr.Code)
+	require.Equal("gzip", rr.Header().Get("Content-Encoding"))
+
+	reader, err := gzip.NewReader(rr.Body)
+	require.NoError(err)
+	body, err := io.ReadAll(reader)
+	require.NoError(err)
+
+	var responses []testResponse
+	require.NoError(stdjson.Unmarshal(body, &responses))
+	require.Len(responses, len(requests))
+}
@@ -15,6 +15,7 @@ import (
 	common "github.com/dim4egster/qmallgo/snow/engine/common"
 	logging "github.com/dim4egster/qmallgo/utils/logging"
 	gomock "github.com/golang/mock/gomock"
+	grpc "google.golang.org/grpc"
 )
 
 // MockPathAdder is a mock of PathAdder interface.
@@ -261,9 +262,9 @@ func (mr *MockServerMockRecorder) DispatchTLS(certBytes, keyBytes interface{}) *
 }
 
 // Initialize mocks base method.
-func (m *MockServer) Initialize(log logging.Logger, factory logging.Factory, host string, port uint16, allowedOrigins []string, shutdownTimeout time.Duration, nodeID ids.NodeID, wrappers ...Wrapper) {
+func (m *MockServer) Initialize(log logging.Logger, factory logging.Factory, host string, port uint16, allowedOrigins []string, maxBatchSize int, maxRequestBodySize int64, shutdownTimeout time.Duration, nodeID ids.NodeID, wrappers ...Wrapper) {
 	m.ctrl.T.Helper()
-	varargs := []interface{}{log, factory, host, port, allowedOrigins, shutdownTimeout, nodeID}
+	varargs := []interface{}{log, factory, host, port, allowedOrigins, maxBatchSize, maxRequestBodySize, shutdownTimeout, nodeID}
 	for _, a := range wrappers {
 		varargs = append(varargs, a)
 	}
@@ -271,9 +272,9 @@ func (m *MockServer) Initialize(log logging.Logger, factory logging.Factory, hos
 }
 
 // Initialize indicates an expected call of Initialize.
-func (mr *MockServerMockRecorder) Initialize(log, factory, host, port, allowedOrigins, shutdownTimeout, nodeID interface{}, wrappers ...interface{}) *gomock.Call {
+func (mr *MockServerMockRecorder) Initialize(log, factory, host, port, allowedOrigins, maxBatchSize, maxRequestBodySize, shutdownTimeout, nodeID interface{}, wrappers ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	varargs := append([]interface{}{log, factory, host, port, allowedOrigins, shutdownTimeout, nodeID}, wrappers...)
+	varargs := append([]interface{}{log, factory, host, port, allowedOrigins, maxBatchSize, maxRequestBodySize, shutdownTimeout, nodeID}, wrappers...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Initialize", reflect.TypeOf((*MockServer)(nil).Initialize), varargs...)
 }
 
@@ -289,6 +290,18 @@ func (mr *MockServerMockRecorder) RegisterChain(chainName, engine interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterChain", reflect.TypeOf((*MockServer)(nil).RegisterChain), chainName, engine)
 }
 
+// RegisterService mocks base method.
+func (m *MockServer) RegisterService(desc *grpc.ServiceDesc, impl interface{}) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RegisterService", desc, impl)
+}
+
+// RegisterService indicates an expected call of RegisterService.
+func (mr *MockServerMockRecorder) RegisterService(desc, impl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterService", reflect.TypeOf((*MockServer)(nil).RegisterService), desc, impl)
+}
+
 // Shutdown mocks base method.
 func (m *MockServer) Shutdown() error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,427 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"go.uber.org/zap"
+
+	"golang.org/x/time/rate"
+
+	"github.com/dim4egster/qmallgo/api"
+	"github.com/dim4egster/qmallgo/utils/json"
+	"github.com/dim4egster/qmallgo/utils/logging"
+	"github.com/dim4egster/qmallgo/utils/timer/mockable"
+	"github.com/dim4egster/qmallgo/utils/wrappers"
+)
+
+const (
+	authHeaderKey      = "Authorization"
+	authHeaderValStart = "Bearer "
+
+	// Buckets are checked for removal at most once per this period. A bucket
+	// is removed once it has been idle for this long and has refilled.
+	rateLimitBucketTTL = time.Minute
+
+	// The maximum number of buckets that are tracked at once. Requests that
+	// would need a new bucket are rejected while this many are tracked.
+	maxRateLimitBuckets = 100000
+)
+
+var (
+	errInvalidRateLimit = errors.New("rate limit must allow a positive rate and burst")
+
+	_ Wrapper     = &rateLimiter{}
+	_ routeFilter = &rateLimiter{}
+)
+
+// TokenVerifier returns the ID of an auth token if it is valid.
+type TokenVerifier interface {
+	VerifyToken(token string) (string, error)
+}
+
+// RateLimit is a token bucket that refills at [RequestsPerSecond] and holds at
+// most [Burst] requests.
+type RateLimit struct {
+	RequestsPerSecond float64 `json:"requestsPerSecond"`
+	Burst             int     `json:"burst"`
+}
+
+func (l RateLimit) verify() error {
+	if l.RequestsPerSecond <= 0 || l.Burst <= 0 {
+		return fmt.Errorf("%w: %+v", errInvalidRateLimit, l)
+	}
+	return nil
+}
+
+type RateLimitConfig struct {
+	Enabled bool `json:"enabled"`
+
+	// The limit of each IP and each auth token when calling a JSON-RPC method
+	// of an endpoint, unless it is overridden below.
+	RateLimit `json:"defaultLimit"`
+
+	// Overrides [RateLimit] for the endpoints with these paths e.g.
+	// "/ext/index/X/tx".
+	EndpointLimits map[string]RateLimit `json:"endpointLimits"`
+
+	// Overrides [RateLimit] and [EndpointLimits] for these JSON-RPC methods e.g.
+	// "avm.getUTXOs".
+	MethodLimits map[string]RateLimit `json:"methodLimits"`
+}
+
+func (c *RateLimitConfig) Verify() error {
+	if !c.Enabled {
+		return nil
+	}
+	if err := c.RateLimit.verify(); err != nil {
+		return err
+	}
+	for endpoint, limit := range c.EndpointLimits {
+		if err := limit.verify(); err != nil {
+			return fmt.Errorf("invalid limit for endpoint %q: %w", endpoint, err)
+		}
+	}
+	for method, limit := range c.MethodLimits {
+		if err := limit.verify(); err != nil {
+			return fmt.Errorf("invalid limit for method %q: %w", method, err)
+		}
+	}
+	return nil
+}
+
+type bucketKey struct {
+	// Either the IP or the ID of the verified auth token of the client
+	client string
+	// The path of a served endpoint, or "" for any other path
+	endpoint string
+	// A method in [MethodLimits], or "" for any other method
+	method string
+}
+
+type bucket struct {
+	limiter *rate.Limiter
+	// The time it takes for an empty bucket to refill
+	refillTime time.Duration
+	lastUsed   time.Time
+}
+
+type rateLimiterMetrics struct {
+	numAllowed prometheus.Counter
+	numLimited prometheus.Counter
+	numBuckets prometheus.Gauge
+}
+
+func newRateLimiterMetrics(namespace string, reg prometheus.Registerer) (*rateLimiterMetrics, error) {
+	m := &rateLimiterMetrics{
+		numAllowed: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rate_limiter_allowed",
+			Help:      "Number of API requests that were within their rate limits",
+		}),
+		numLimited: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rate_limiter_limited",
+			Help:      "Number of API requests that were rejected because they exceeded a rate limit",
+		}),
+		numBuckets: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "rate_limiter_buckets",
+			Help:      "Number of (client, endpoint, method) tuples whose request rate is being tracked",
+		}),
+	}
+	errs := wrappers.Errs{}
+	errs.Add(
+		reg.Register(m.numAllowed),
+		reg.Register(m.numLimited),
+		reg.Register(m.numBuckets),
+	)
+	return m, errs.Err
+}
+
+// rateLimiter rejects requests, with status 429, once a client exceeds its
+// request rate to a JSON-RPC method of an endpoint. Every request is limited
+// by the IP it was sent from and, if it carries a valid one, by its auth
+// token.
+//
+// Requests to paths that aren't served share a bucket per client, as do
+// calls to methods without a limit in [MethodLimits], so that the number of
+// buckets of a client doesn't depend on the request.
+type rateLimiter struct {
+	// Used to mock time.
+	clock mockable.Clock
+
+	log     logging.Logger
+	config  RateLimitConfig
+	metrics *rateLimiterMetrics
+	// The maximum number of bytes of a request body that is read to find the
+	// JSON-RPC methods it calls.
+	maxRequestBodySize int64
+	// Verifies auth tokens. If nil, requests are only limited by IP.
+	tokenVerifier TokenVerifier
+	// Returns true if the path is served. Set by the server.
+	isRoute func(path string) bool
+
+	lock       sync.Mutex
+	buckets    map[bucketKey]*bucket
+	maxBuckets int
+	lastPrune  time.Time
+}
+
+// NewRateLimiter returns a Wrapper that enforces the limits of [config].
+// Requests whose body is larger than [maxRequestBodySize] are rejected. If
+// [tokenVerifier] is non-nil, requests with a valid auth token are also limited
+// by that token.
+func NewRateLimiter(
+	log logging.Logger,
+	config RateLimitConfig,
+	maxRequestBodySize int64,
+	tokenVerifier TokenVerifier,
+	namespace string,
+	reg prometheus.Registerer,
+) (Wrapper, error) {
+	if err := config.Verify(); err != nil {
+		return nil, err
+	}
+	metrics, err := newRateLimiterMetrics(namespace, reg)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't initialize rate limiter metrics: %w", err)
+	}
+	return &rateLimiter{
+		log:                log,
+		config:             config,
+		metrics:            metrics,
+		maxRequestBodySize: maxRequestBodySize,
+		tokenVerifier:      tokenVerifier,
+		buckets:            make(map[bucketKey]*bucket),
+		maxBuckets:         maxRateLimitBuckets,
+	}, nil
+}
+
+func (l *rateLimiter) setIsRoute(isRoute func(path string) bool) {
+	l.isRoute = isRoute
+}
+
+func (l *rateLimiter) WrapHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The body of a gRPC request is a stream, so gRPC requests are only
+		// limited per client.
+		var methods []string
+		if !api.IsGRPCRequest(r) {
+			var (
+				request *json.Request
+				err     error
+			)
+			r, request, err = json.ParseRequest(w, r, l.maxRequestBodySize)
+			if errors.Is(err, json.ErrRequestTooLarge) {
+				http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+				return
+			}
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			// The methods of a body that doesn't parse are unknown, so it
+			// can't be charged against the limits of the methods it calls.
+			if request.Err != nil {
+				http.Error(w, request.Err.Error(), http.StatusBadRequest)
+				return
+			}
+			methods = request.Methods
+		}
+
+		clients := []string{remoteIP(r)}
+		if tokenID, ok := l.verifyToken(r); ok {
+			clients = append(clients, tokenID)
+		}
+
+		if retryAfter, ok := l.allow(clients, l.endpointKey(r.URL.Path), l.methodKeys(methods)); !ok {
+			l.log.Debug("rate limited API call",
+				zap.String("remoteAddr", r.RemoteAddr),
+				zap.String("endpoint", r.URL.Path),
+				zap.Strings("methods", methods),
+				zap.Duration("retryAfter", retryAfter),
+			)
+			writeTooManyRequestsResponse(w, retryAfter)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// verifyToken returns the ID of the auth token of [r], if it carries a valid
+// one. Unverified tokens aren't tracked, as a client could otherwise create a
+// bucket per request by sending random tokens.
+func (l *rateLimiter) verifyToken(r *http.Request) (string, bool) {
+	if l.tokenVerifier == nil {
+		return "", false
+	}
+	rawHeader := r.Header.Get(authHeaderKey)
+	if !strings.HasPrefix(rawHeader, authHeaderValStart) {
+		return "", false
+	}
+	tokenID, err := l.tokenVerifier.VerifyToken(rawHeader[len(authHeaderValStart):])
+	if err != nil {
+		return "", false
+	}
+	// Token IDs can't collide with IPs as they're prefixed.
+	return "token:" + tokenID, true
+}
+
+// endpointKey returns [path] if it is served, and "" otherwise.
+func (l *rateLimiter) endpointKey(path string) string {
+	if l.isRoute == nil || !l.isRoute(path) {
+		return ""
+	}
+	return path
+}
+
+// methodKeys returns, for each of [methods], the method if it has a limit in
+// [MethodLimits] and "" otherwise. Requests that don't call a JSON-RPC method
+// are also limited under "".
+func (l *rateLimiter) methodKeys(methods []string) []string {
+	keys := make([]string, 0, len(methods))
+	for _, method := range methods {
+		if _, ok := l.config.MethodLimits[method]; !ok {
+			method = ""
+		}
+		keys = append(keys, method)
+	}
+	if len(keys) == 0 {
+		keys = append(keys, "")
+	}
+	return keys
+}
+
+// allow consumes a request from the buckets of each of [clients] for each of
+// [methods] of [endpoint]. If any of the buckets is empty, nothing is consumed
+// and the time until the request would be allowed is returned.
+func (l *rateLimiter) allow(clients []string, endpoint string, methods []string) (time.Duration, bool) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := l.clock.Time()
+	l.prune(now)
+
+	var (
+		reservations = make([]*rate.Reservation, 0, len(clients)*len(methods))
+		retryAfter   time.Duration
+	)
+clients:
+	for _, client := range clients {
+		for _, method := range methods {
+			b, ok := l.getBucket(bucketKey{
+				client:   client,
+				endpoint: endpoint,
+				method:   method,
+			}, now)
+			if !ok {
+				l.log.Warn("too many rate limit buckets",
+					zap.Int("numBuckets", len(l.buckets)),
+				)
+				retryAfter = rateLimitBucketTTL
+				break clients
+			}
+			reservation := b.limiter.ReserveN(now, 1)
+			reservations = append(reservations, reservation)
+			if delay := reservation.DelayFrom(now); delay > retryAfter {
+				retryAfter = delay
+			}
+		}
+	}
+
+	if retryAfter == 0 {
+		l.metrics.numAllowed.Inc()
+		return 0, true
+	}
+
+	// Cancel in reverse order so that the tokens of a bucket that was reserved
+	// multiple times are fully restored.
+	for i := len(reservations) - 1; i >= 0; i-- {
+		reservations[i].CancelAt(now)
+	}
+	l.metrics.numLimited.Inc()
+	return retryAfter, false
+}
+
+// getBucket returns the bucket of [key], which is created if it isn't tracked.
+// Returns false if there are already [l.maxBuckets] buckets.
+//
+// Assumes [l.lock] is held.
+func (l *rateLimiter) getBucket(key bucketKey, now time.Time) (*bucket, bool) {
+	b, ok := l.buckets[key]
+	if !ok {
+		if len(l.buckets) >= l.maxBuckets {
+			return nil, false
+		}
+		limit := l.limit(key.endpoint, key.method)
+		b = &bucket{
+			limiter:    rate.NewLimiter(rate.Limit(limit.RequestsPerSecond), limit.Burst),
+			refillTime: time.Duration(float64(limit.Burst) / limit.RequestsPerSecond * float64(time.Second)),
+		}
+		l.buckets[key] = b
+		l.metrics.numBuckets.Set(float64(len(l.buckets)))
+	}
+	b.lastUsed = now
+	return b, true
+}
+
+// limit returns the most specific limit that applies to [method] of
+// [endpoint].
+func (l *rateLimiter) limit(endpoint, method string) RateLimit {
+	if limit, ok := l.config.MethodLimits[method]; ok && method != "" {
+		return limit
+	}
+	if limit, ok := l.config.EndpointLimits[endpoint]; ok {
+		return limit
+	}
+	return l.config.RateLimit
+}
+
+// prune removes the buckets that have been idle for [rateLimitBucketTTL], and
+// long enough to have refilled, as they would be recreated identically.
+//
+// Assumes [l.lock] is held.
+func (l *rateLimiter) prune(now time.Time) {
+	if now.Sub(l.lastPrune) < rateLimitBucketTTL {
+		return
+	}
+	l.lastPrune = now
+
+	for key, b := range l.buckets {
+		if idle := now.Sub(b.lastUsed); idle >= rateLimitBucketTTL && idle >= b.refillTime {
+			delete(l.buckets, key)
+		}
+	}
+	l.metrics.numBuckets.Set(float64(len(l.buckets)))
+}
+
+// remoteIP returns the IP that [r] was sent from.
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// writeTooManyRequestsResponse writes a response with status
+// http.StatusTooManyRequests that asks the client to retry after
+// [retryAfter], rounded up to the second.
+func writeTooManyRequestsResponse(w http.ResponseWriter, retryAfter time.Duration) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+}
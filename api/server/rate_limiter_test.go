@@ -0,0 +1,254 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dim4egster/qmallgo/utils/logging"
+)
+
+var errInvalidTestToken = errors.New("invalid token")
+
+// testTokenVerifier maps the valid tokens to their IDs.
+type testTokenVerifier map[string]string
+
+func (v testTokenVerifier) VerifyToken(token string) (string, error) {
+	tokenID, ok := v[token]
+	if !ok {
+		return "", errInvalidTestToken
+	}
+	return tokenID, nil
+}
+
+func newTestRateLimiter(t *testing.T, config RateLimitConfig) (*rateLimiter, http.Handler, *testHandler) {
+	tokenVerifier := testTokenVerifier{
+		"token": "tokenID",
+	}
+	wrapper, err := NewRateLimiter(logging.NoLog{}, config, 1024, tokenVerifier, "", prometheus.NewRegistry())
+	require.NoError(t, err)
+	l := wrapper.(*rateLimiter)
+	l.clock.Set(time.Unix(1000, 0))
+	l.setIsRoute(func(path string) bool {
+		switch path {
+		case "/ext/bc/X", "/ext/bc/P", "/ext/index/X/tx":
+			return true
+		default:
+			return false
+		}
+	})
+
+	handler := &testHandler{}
+	return l, l.WrapHandler(handler), handler
+}
+
+func newTestRequest(remoteAddr, token, endpoint string, methods ...string) *http.Request {
+	requests := make([]string, len(methods))
+	for i, method := range methods {
+		requests[i] = fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":%q,"params":{}}`, i, method)
+	}
+	body := "[" + strings.Join(requests, ",") + "]"
+	req := httptest.NewRequest(http.MethodPost, "http://127.0.0.1:4960"+endpoint, strings.NewReader(body))
+	req.RemoteAddr = remoteAddr
+	if token != "" {
+		req.Header.Add("Authorization", "Bearer "+token)
+	}
+	return req
+}
+
+func serve(h http.Handler, req *http.Request) *httptest.ResponseRecorder {
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestRateLimiterLimitsPerClientEndpointAndMethod(t *testing.T) {
+	require := require.New(t)
+
+	l, h, handler := newTestRateLimiter(t, RateLimitConfig{
+		Enabled: true,
+		RateLimit: RateLimit{
+			RequestsPerSecond: 1,
+			Burst:             2,
+		},
+		EndpointLimits: map[string]RateLimit{
+			"/ext/index/X/tx": {RequestsPerSecond: 1, Burst: 1},
+		},
+		MethodLimits: map[string]RateLimit{
+			"avm.getUTXOs": {RequestsPerSecond: 0.5, Burst: 1},
+		},
+	})
+
+	// The burst of the default limit is allowed.
+	for i := 0; i < 2; i++ {
+		rr := serve(h, newTestRequest("1.2.3.4:1", "", "/ext/bc/X", "avm.getBalance"))
+		require.Equal(http.StatusOK, rr.Code)
+	}
+	rr := serve(h, newTestRequest("1.2.3.4:2", "", "/ext/bc/X", "avm.getBalance"))
+	require.Equal(http.StatusTooManyRequests, rr.Code)
+	require.Equal("1", rr.Header().Get("Retry-After"))
+
+	// Methods without their own limit share a bucket.
+	require.Equal(http.StatusTooManyRequests, serve(h, newTestRequest("1.2.3.4:1", "", "/ext/bc/X", "avm.getTx")).Code)
+
+	// Other endpoints and IPs have their own buckets.
+	require.Equal(http.StatusOK, serve(h, newTestRequest("1.2.3.4:1", "", "/ext/bc/P", "avm.getBalance")).Code)
+	require.Equal(http.StatusOK, serve(h, newTestRequest("5.6.7.8:1", "", "/ext/bc/X", "avm.getBalance")).Code)
+
+	// Method limits take precedence over endpoint limits.
+	require.Equal(http.StatusOK, serve(h, newTestRequest("1.2.3.4:1", "", "/ext/index/X/tx", "index.getLastAccepted")).Code)
+	require.Equal(http.StatusTooManyRequests, serve(h, newTestRequest("1.2.3.4:1", "", "/ext/index/X/tx", "index.getLastAccepted")).Code)
+	require.Equal(http.StatusOK, serve(h, newTestRequest("1.2.3.4:1", "", "/ext/bc/X", "avm.getUTXOs")).Code)
+	rr = serve(h, newTestRequest("1.2.3.4:1", "", "/ext/bc/X", "avm.getUTXOs"))
+	require.Equal(http.StatusTooManyRequests, rr.Code)
+	require.Equal("2", rr.Header().Get("Retry-After"))
+
+	// The bucket refills over time.
+	l.clock.Set(l.clock.Time().Add(time.Second))
+	handler.called = false
+	require.Equal(http.StatusOK, serve(h, newTestRequest("1.2.3.4:1", "", "/ext/bc/X", "avm.getBalance")).Code)
+	require.True(handler.called)
+}
+
+func TestRateLimiterLimitsTokensAndBatches(t *testing.T) {
+	require := require.New(t)
+
+	_, h, handler := newTestRateLimiter(t, RateLimitConfig{
+		Enabled: true,
+		RateLimit: RateLimit{
+			RequestsPerSecond: 1,
+			Burst:             2,
+		},
+	})
+
+	// A token is limited across IPs.
+	require.Equal(http.StatusOK, serve(h, newTestRequest("1.1.1.1:1", "token", "/ext/bc/X", "avm.getBalance")).Code)
+	require.Equal(http.StatusOK, serve(h, newTestRequest("2.2.2.2:1", "token", "/ext/bc/X", "avm.getBalance")).Code)
+	require.Equal(http.StatusTooManyRequests, serve(h, newTestRequest("3.3.3.3:1", "token", "/ext/bc/X", "avm.getBalance")).Code)
+
+	// An invalid token isn't tracked.
+	require.Equal(http.StatusOK, serve(h, newTestRequest("5.5.5.5:1", "invalid", "/ext/bc/X", "avm.getBalance")).Code)
+	require.Equal(http.StatusOK, serve(h, newTestRequest("6.6.6.6:1", "invalid", "/ext/bc/X", "avm.getBalance")).Code)
+	require.Equal(http.StatusOK, serve(h, newTestRequest("7.7.7.7:1", "invalid", "/ext/bc/X", "avm.getBalance")).Code)
+
+	// A rejected batch doesn't consume any of the requests it would have
+	// been allowed.
+	handler.called = false
+	rr := serve(h, newTestRequest("4.4.4.4:1", "", "/ext/bc/X", "avm.getTx", "avm.getTx", "avm.getTx"))
+	require.Equal(http.StatusTooManyRequests, rr.Code)
+	require.False(handler.called)
+	require.Equal(http.StatusOK, serve(h, newTestRequest("4.4.4.4:1", "", "/ext/bc/X", "avm.getTx", "avm.getTx")).Code)
+	require.True(handler.called)
+}
+
+func TestRateLimiterBoundsBuckets(t *testing.T) {
+	require := require.New(t)
+
+	l, h, _ := newTestRateLimiter(t, RateLimitConfig{
+		Enabled: true,
+		RateLimit: RateLimit{
+			RequestsPerSecond: 1,
+			Burst:             10,
+		},
+	})
+	l.maxBuckets = 3
+
+	// Paths that aren't served and methods without their own limit don't
+	// create buckets.
+	require.Equal(http.StatusOK, serve(h, newTestRequest("1.1.1.1:1", "", "/ext/bc/X", "avm.getBalance", "avm.getTx")).Code)
+	require.Equal(http.StatusOK, serve(h, newTestRequest("1.1.1.1:1", "", "/ext/unknown", "avm.getBalance")).Code)
+	require.Equal(http.StatusOK, serve(h, newTestRequest("1.1.1.1:1", "", "/ext/other", "unknown.method")).Code)
+	require.Len(l.buckets, 2)
+
+	require.Equal(http.StatusOK, serve(h, newTestRequest("2.2.2.2:1", "", "/ext/bc/X", "avm.getBalance")).Code)
+	require.Len(l.buckets, 3)
+
+	// Once the limit is reached, requests that need a new bucket are
+	// rejected.
+	rr := serve(h, newTestRequest("3.3.3.3:1", "", "/ext/bc/X", "avm.getBalance"))
+	require.Equal(http.StatusTooManyRequests, rr.Code)
+	require.Equal("60", rr.Header().Get("Retry-After"))
+	rr = serve(h, newTestRequest("1.1.1.1:1", "token", "/ext/bc/P", "avm.getBalance"))
+	require.Equal(http.StatusTooManyRequests, rr.Code)
+	require.Len(l.buckets, 3)
+
+	// Tracked clients are still served.
+	require.Equal(http.StatusOK, serve(h, newTestRequest("2.2.2.2:1", "", "/ext/bc/X", "avm.getBalance")).Code)
+
+	// Idle buckets are pruned to make room.
+	l.clock.Set(l.clock.Time().Add(2 * rateLimitBucketTTL))
+	require.Equal(http.StatusOK, serve(h, newTestRequest("3.3.3.3:1", "", "/ext/bc/X", "avm.getBalance")).Code)
+	require.Len(l.buckets, 1)
+}
+
+func TestRateLimiterPrunesIdleBuckets(t *testing.T) {
+	require := require.New(t)
+
+	l, h, _ := newTestRateLimiter(t, RateLimitConfig{
+		Enabled: true,
+		RateLimit: RateLimit{
+			RequestsPerSecond: 1,
+			Burst:             1,
+		},
+	})
+
+	require.Equal(http.StatusOK, serve(h, newTestRequest("1.1.1.1:1", "", "/ext/bc/X", "avm.getBalance")).Code)
+	require.Len(l.buckets, 1)
+
+	l.clock.Set(l.clock.Time().Add(2 * rateLimitBucketTTL))
+	require.Equal(http.StatusOK, serve(h, newTestRequest("2.2.2.2:1", "", "/ext/bc/X", "avm.getBalance")).Code)
+	require.Len(l.buckets, 1)
+}
+
+func TestRateLimitConfigVerify(t *testing.T) {
+	config := RateLimitConfig{
+		Enabled: true,
+		RateLimit: RateLimit{
+			RequestsPerSecond: 1,
+			Burst:             1,
+		},
+		MethodLimits: map[string]RateLimit{
+			"avm.getUTXOs": {RequestsPerSecond: 1},
+		},
+	}
+	require.ErrorIs(t, config.Verify(), errInvalidRateLimit)
+}
+
+func TestRateLimiterRejectsMalformedRequests(t *testing.T) {
+	require := require.New(t)
+
+	_, h, handler := newTestRateLimiter(t, RateLimitConfig{
+		Enabled: true,
+		RateLimit: RateLimit{
+			RequestsPerSecond: 1,
+			Burst:             1,
+		},
+		MethodLimits: map[string]RateLimit{
+			"avm.getUTXOs": {RequestsPerSecond: 1, Burst: 1},
+		},
+	})
+
+	// Bodies whose methods are unknown aren't charged against the bucket of
+	// requests without a method.
+	for _, body := range []string{
+		`{"jsonrpc":"2.0","id":1,"method":"avm.getUTXOs","params":{}} x`,
+		`[{"jsonrpc":"2.0","id":1,"method":"avm.getUTXOs","params":{}},5]`,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "http://127.0.0.1:4960/ext/bc/X", strings.NewReader(body))
+		req.RemoteAddr = "1.1.1.1:1"
+		handler.called = false
+		require.Equal(http.StatusBadRequest, serve(h, req).Code)
+		require.False(handler.called)
+	}
+}
@@ -58,6 +58,14 @@ func (r *router) GetHandler(base, endpoint string) (http.Handler, error) {
 	return handler, nil
 }
 
+// HasRoute returns true if [url] maps to a handler.
+func (r *router) HasRoute(url string) bool {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.router.Get(url) != nil
+}
+
 func (r *router) AddRouter(base, endpoint string, handler http.Handler) error {
 	r.lock.Lock()
 	defer r.lock.Unlock()
@@ -21,6 +21,12 @@ import (
 
 	"go.uber.org/zap"
 
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+
+	"google.golang.org/grpc"
+
+	"github.com/dim4egster/qmallgo/api"
 	"github.com/dim4egster/qmallgo/ids"
 	"github.com/dim4egster/qmallgo/snow"
 	"github.com/dim4egster/qmallgo/snow/engine/common"
@@ -37,8 +43,9 @@ const (
 var (
 	errUnknownLockOption = errors.New("invalid lock options")
 
-	_ PathAdder = readPathAdder{}
-	_ Server    = &server{}
+	_ PathAdder             = readPathAdder{}
+	_ Server                = &server{}
+	_ grpc.ServiceRegistrar = &server{}
 )
 
 type PathAdder interface {
@@ -69,6 +76,8 @@ type Server interface {
 		host string,
 		port uint16,
 		allowedOrigins []string,
+		maxBatchSize int,
+		maxRequestBodySize int64,
 		shutdownTimeout time.Duration,
 		nodeID ids.NodeID,
 		wrappers ...Wrapper)
@@ -90,6 +99,9 @@ type Server interface {
 		ctx *snow.ConsensusContext,
 		base, endpoint string,
 	) error
+	// RegisterService registers a gRPC service that is served on the same port
+	// as the HTTP API to HTTP/2 requests with a gRPC content type.
+	RegisterService(desc *grpc.ServiceDesc, impl interface{})
 	// Shutdown this server
 	Shutdown() error
 }
@@ -110,6 +122,9 @@ type server struct {
 	// Maps endpoints to handlers
 	router *router
 
+	// Serves the registered gRPC services
+	grpcServer *grpc.Server
+
 	srv *http.Server
 }
 
@@ -124,6 +139,8 @@ func (s *server) Initialize(
 	host string,
 	port uint16,
 	allowedOrigins []string,
+	maxBatchSize int,
+	maxRequestBodySize int64,
 	shutdownTimeout time.Duration,
 	nodeID ids.NodeID,
 	wrappers ...Wrapper,
@@ -134,25 +151,36 @@ func (s *server) Initialize(
 	s.listenPort = port
 	s.shutdownTimeout = shutdownTimeout
 	s.router = newRouter()
+	s.grpcServer = grpc.NewServer()
 
 	s.log.Info("API created",
 		zap.Strings("allowedOrigins", allowedOrigins),
+		zap.Int("maxBatchSize", maxBatchSize),
 	)
 
 	corsHandler := cors.New(cors.Options{
 		AllowedOrigins:   allowedOrigins,
 		AllowCredentials: true,
-	}).Handler(s.router)
+	}).Handler(newBatchHandler(s.router, maxBatchSize, maxRequestBodySize))
+	// Responses, including the responses to batches, are compressed if the
+	// client accepts gzip encoding.
 	gzipHandler := gziphandler.GzipHandler(corsHandler)
 	s.handler = http.HandlerFunc(
 		func(w http.ResponseWriter, r *http.Request) {
 			// Attach this node's ID as a header
 			w.Header().Set("node-id", nodeID.String())
+			if api.IsGRPCRequest(r) {
+				s.grpcServer.ServeHTTP(w, r)
+				return
+			}
 			gzipHandler.ServeHTTP(w, r)
 		},
 	)
 
 	for _, wrapper := range wrappers {
+		if filter, ok := wrapper.(routeFilter); ok {
+			filter.setIsRoute(s.router.HasRoute)
+		}
 		s.handler = wrapper.WrapHandler(s.handler)
 	}
 }
@@ -177,7 +205,8 @@ func (s *server) Dispatch() error {
 	}
 
 	s.srv = &http.Server{
-		Handler:           s.handler,
+		// Allow gRPC clients to connect without TLS
+		Handler:           h2c.NewHandler(s.handler, &http2.Server{}),
 		ReadHeaderTimeout: readHeaderTimeout,
 	}
 	return s.srv.Serve(listener)
@@ -192,6 +221,7 @@ func (s *server) DispatchTLS(certBytes, keyBytes []byte) error {
 	config := &tls.Config{
 		MinVersion:   tls.VersionTLS12,
 		Certificates: []tls.Certificate{cert},
+		NextProtos:   []string{http2.NextProtoTLS, "http/1.1"},
 	}
 
 	listener, err := tls.Listen("tcp", listenAddress, config)
@@ -283,6 +313,13 @@ func (s *server) AddChainRoute(handler *common.HTTPHandler, ctx *snow.ConsensusC
 	return s.router.AddRouter(url, endpoint, h)
 }
 
+func (s *server) RegisterService(desc *grpc.ServiceDesc, impl interface{}) {
+	s.log.Info("adding gRPC service",
+		zap.String("service", desc.ServiceName),
+	)
+	s.grpcServer.RegisterService(desc, impl)
+}
+
 func (s *server) AddRoute(handler *common.HTTPHandler, lock *sync.RWMutex, base, endpoint string) error {
 	return s.addRoute(handler, lock, base, endpoint)
 }
@@ -0,0 +1,65 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/snow/engine/common"
+	"github.com/dim4egster/qmallgo/utils/logging"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestServerServesGRPCAndJSONRPC(t *testing.T) {
+	require := require.New(t)
+
+	s := &server{}
+	s.Initialize(logging.NoLog{}, nil, "127.0.0.1", 0, []string{"*"}, 100, 1024*1024, time.Second, ids.EmptyNodeID)
+	require.NoError(s.AddRoute(&common.HTTPHandler{
+		LockOptions: common.NoLock,
+		Handler:     newTestService(t),
+	}, nil, "test", ""))
+	healthpb.RegisterHealthServer(s, health.NewServer())
+
+	httpServer := httptest.NewServer(h2c.NewHandler(s.handler, &http2.Server{}))
+	defer httpServer.Close()
+
+	// JSON-RPC requests are served over HTTP/1.1.
+	resp, err := http.Post(
+		httpServer.URL+"/ext/test",
+		"application/json",
+		strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"test.echo","params":{"message":"hello"}}`),
+	)
+	require.NoError(err)
+	require.Equal(http.StatusOK, resp.StatusCode)
+	require.NoError(resp.Body.Close())
+
+	// gRPC requests are served over HTTP/2 without TLS.
+	conn, err := grpc.Dial(
+		strings.TrimPrefix(httpServer.URL, "http://"),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(err)
+	defer conn.Close()
+
+	reply, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(err)
+	require.Equal(healthpb.HealthCheckResponse_SERVING, reply.Status)
+}
@@ -11,3 +11,9 @@ type Wrapper interface {
 	// WrapHandler wraps an http.Handler.
 	WrapHandler(h http.Handler) http.Handler
 }
+
+// routeFilter is implemented by the Wrappers that need to know which paths
+// are served.
+type routeFilter interface {
+	setIsRoute(isRoute func(path string) bool)
+}
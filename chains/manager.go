@@ -38,12 +38,17 @@ import (
 	"github.com/dim4egster/qmallgo/snow/networking/sender"
 	"github.com/dim4egster/qmallgo/snow/networking/timeout"
 	"github.com/dim4egster/qmallgo/snow/validators"
+	"github.com/dim4egster/qmallgo/trace"
 	"github.com/dim4egster/qmallgo/utils/constants"
+	"github.com/dim4egster/qmallgo/utils/crypto/bls"
+	"github.com/dim4egster/qmallgo/utils/crypto/keychain"
 	"github.com/dim4egster/qmallgo/utils/logging"
 	"github.com/dim4egster/qmallgo/version"
 	"github.com/dim4egster/qmallgo/vms"
 	"github.com/dim4egster/qmallgo/vms/metervm"
+	"github.com/dim4egster/qmallgo/vms/platformvm/warp"
 	"github.com/dim4egster/qmallgo/vms/proposervm"
+	"github.com/dim4egster/qmallgo/vms/tracedvm"
 
 	dbManager "github.com/dim4egster/qmallgo/database/manager"
 	timetracker "github.com/dim4egster/qmallgo/snow/networking/tracker"
@@ -141,6 +146,7 @@ type ChainConfig struct {
 type ManagerConfig struct {
 	StakingEnabled              bool            // True iff the network has staking enabled
 	StakingCert                 tls.Certificate // needed to sign snowman++ blocks
+	StakingBLSKey               *bls.SecretKey  // needed to sign warp messages
 	Log                         logging.Logger
 	LogFactory                  logging.Factory
 	VMManager                   vms.Manager // Manage mappings from vm ID --> vm
@@ -157,6 +163,7 @@ type ManagerConfig struct {
 	NetworkID                   uint32             // ID of the network this node is connected to
 	Server                      server.Server      // Handles HTTP API calls
 	Keystore                    keystore.Keystore
+	Keychain                    keychain.Keychain // External signer of the keystore services, if any
 	AtomicMemory                *atomic.Memory
 	AVAXAssetID                 ids.ID
 	XChainID                    ids.ID
@@ -173,7 +180,12 @@ type ManagerConfig struct {
 	MeterVMEnabled   bool // Should each VM be wrapped with a MeterVM
 	Metrics          metrics.MultiGatherer
 
+	TracingEnabled bool         // Should each VM be wrapped with a TracedVM
+	Tracer         trace.Tracer // Records spans of the operations of each chain
+
 	ConsensusGossipFrequency time.Duration
+	// Lanes that each chain's incoming messages are queued in
+	ConsensusMessageLanes handler.LanesConfig
 
 	GossipConfig sender.GossipConfig
 
@@ -193,6 +205,9 @@ type ManagerConfig struct {
 	// Tracks CPU/disk usage caused by each peer.
 	ResourceTracker timetracker.ResourceTracker
 
+	// Tracks how quickly and reliably each peer responds to requests.
+	HealthTracker timetracker.HealthTracker
+
 	StateSyncBeacons []ids.NodeID
 }
 
@@ -385,6 +400,7 @@ func (m *manager) buildChain(chainParams ChainParameters, sb Subnet) (*chain, er
 
 			Log:          chainLog,
 			Keystore:     m.Keystore.NewBlockchainKeyStore(chainParams.ID),
+			Keychain:     m.Keychain,
 			SharedMemory: m.AtomicMemory.NewSharedMemory(chainParams.ID),
 			BCLookup:     m,
 			SNLookup:     m,
@@ -393,6 +409,11 @@ func (m *manager) buildChain(chainParams ChainParameters, sb Subnet) (*chain, er
 			ValidatorState:    m.validatorState,
 			StakingCertLeaf:   m.StakingCert.Leaf,
 			StakingLeafSigner: m.StakingCert.PrivateKey.(crypto.Signer),
+
+			WarpSigner: warp.NewSigner(m.StakingBLSKey, chainParams.ID),
+
+			Tracer:       m.Tracer,
+			TraceContext: &trace.CurrentContext{},
 		},
 		DecisionAcceptor:  m.DecisionAcceptorGroup,
 		ConsensusAcceptor: m.ConsensusAcceptorGroup,
@@ -631,6 +652,7 @@ func (m *manager) createAvalancheChain(
 		sb.afterBootstrapped(),
 		m.ConsensusGossipFrequency,
 		m.ResourceTracker,
+		m.ConsensusMessageLanes,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("error initializing network handler: %w", err)
@@ -801,6 +823,10 @@ func (m *manager) createSnowmanChain(
 		return nil, fmt.Errorf("error while fetching chain config: %w", err)
 	}
 
+	if m.TracingEnabled {
+		vm = tracedvm.NewBlockVM(vm, m.PrimaryAliasOrDefault(ctx.ChainID), m.Tracer)
+	}
+
 	vm = proposervm.New(
 		vm,
 		m.ApricotPhase4Time,
@@ -811,6 +837,9 @@ func (m *manager) createSnowmanChain(
 	if m.MeterVMEnabled {
 		vm = metervm.NewBlockVM(vm)
 	}
+	if m.TracingEnabled {
+		vm = tracedvm.NewBlockVM(vm, "proposervm", m.Tracer)
+	}
 	if err := vm.Initialize(
 		ctx.Context,
 		vmDBManager,
@@ -838,6 +867,7 @@ func (m *manager) createSnowmanChain(
 		sb.afterBootstrapped(),
 		m.ConsensusGossipFrequency,
 		m.ResourceTracker,
+		m.ConsensusMessageLanes,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't initialize message handler: %w", err)
@@ -880,6 +910,7 @@ func (m *manager) createSnowmanChain(
 		Validators:    vdrs,
 		Params:        consensusParams,
 		Consensus:     &smcon.Topological{},
+		HealthScorer:  m.HealthTracker,
 	}
 	engine, err := smeng.New(engineConfig)
 	if err != nil {
@@ -19,23 +19,29 @@ import (
 
 	"github.com/spf13/viper"
 
+	"github.com/dim4egster/qmallgo/api/server"
 	"github.com/dim4egster/qmallgo/app/runner"
 	"github.com/dim4egster/qmallgo/chains"
 	"github.com/dim4egster/qmallgo/genesis"
 	"github.com/dim4egster/qmallgo/ids"
 	"github.com/dim4egster/qmallgo/ipcs"
+	"github.com/dim4egster/qmallgo/ipcs/socket"
 	"github.com/dim4egster/qmallgo/nat"
 	"github.com/dim4egster/qmallgo/network"
 	"github.com/dim4egster/qmallgo/network/dialer"
+	"github.com/dim4egster/qmallgo/network/recorder"
 	"github.com/dim4egster/qmallgo/network/throttling"
 	"github.com/dim4egster/qmallgo/node"
 	"github.com/dim4egster/qmallgo/snow/consensus/avalanche"
 	"github.com/dim4egster/qmallgo/snow/consensus/snowball"
 	"github.com/dim4egster/qmallgo/snow/networking/benchlist"
+	"github.com/dim4egster/qmallgo/snow/networking/handler"
 	"github.com/dim4egster/qmallgo/snow/networking/router"
 	"github.com/dim4egster/qmallgo/snow/networking/sender"
 	"github.com/dim4egster/qmallgo/snow/networking/tracker"
 	"github.com/dim4egster/qmallgo/staking"
+	"github.com/dim4egster/qmallgo/trace"
+	"github.com/dim4egster/qmallgo/utils/compression"
 	"github.com/dim4egster/qmallgo/utils/constants"
 	"github.com/dim4egster/qmallgo/utils/crypto/bls"
 	"github.com/dim4egster/qmallgo/utils/dynamicip"
@@ -77,6 +83,7 @@ var (
 	errCannotWhitelistPrimaryNetwork = errors.New("cannot whitelist primary network")
 	errStakingKeyContentUnset        = fmt.Errorf("%s key not set but %s set", StakingTLSKeyContentKey, StakingCertContentKey)
 	errStakingCertContentUnset       = fmt.Errorf("%s key set but %s not set", StakingTLSKeyContentKey, StakingCertContentKey)
+	errInvalidTracingHeader          = errors.New("tracing header must be of the form key=value")
 )
 
 func GetRunnerConfig(v *viper.Viper) (runner.Config, error) {
@@ -127,16 +134,18 @@ func GetRunnerConfig(v *viper.Viper) (runner.Config, error) {
 func getConsensusConfig(v *viper.Viper) avalanche.Parameters {
 	return avalanche.Parameters{
 		Parameters: snowball.Parameters{
-			K:                       v.GetInt(SnowSampleSizeKey),
-			Alpha:                   v.GetInt(SnowQuorumSizeKey),
-			BetaVirtuous:            v.GetInt(SnowVirtuousCommitThresholdKey),
-			BetaRogue:               v.GetInt(SnowRogueCommitThresholdKey),
-			ConcurrentRepolls:       v.GetInt(SnowConcurrentRepollsKey),
-			OptimalProcessing:       v.GetInt(SnowOptimalProcessingKey),
-			MaxOutstandingItems:     v.GetInt(SnowMaxProcessingKey),
-			MaxItemProcessingTime:   v.GetDuration(SnowMaxTimeProcessingKey),
-			MixedQueryNumPushVdr:    int(v.GetUint(SnowMixedQueryNumPushVdrKey)),
-			MixedQueryNumPushNonVdr: int(v.GetUint(SnowMixedQueryNumPushNonVdrKey)),
+			K:                        v.GetInt(SnowSampleSizeKey),
+			Alpha:                    v.GetInt(SnowQuorumSizeKey),
+			BetaVirtuous:             v.GetInt(SnowVirtuousCommitThresholdKey),
+			BetaRogue:                v.GetInt(SnowRogueCommitThresholdKey),
+			ConcurrentRepolls:        v.GetInt(SnowConcurrentRepollsKey),
+			OptimalProcessing:        v.GetInt(SnowOptimalProcessingKey),
+			MaxOutstandingItems:      v.GetInt(SnowMaxProcessingKey),
+			MaxItemProcessingTime:    v.GetDuration(SnowMaxTimeProcessingKey),
+			MixedQueryNumPushVdr:     int(v.GetUint(SnowMixedQueryNumPushVdrKey)),
+			MixedQueryNumPushNonVdr:  int(v.GetUint(SnowMixedQueryNumPushNonVdrKey)),
+			AdaptiveSampling:         v.GetBool(SnowAdaptiveSamplingEnabledKey),
+			AdaptiveSamplingMinScore: v.GetFloat64(SnowAdaptiveSamplingMinScoreKey),
 		},
 		BatchSize: v.GetInt(SnowAvalancheBatchSizeKey),
 		Parents:   v.GetInt(SnowAvalancheNumParentsKey),
@@ -194,10 +203,13 @@ func getAPIAuthConfig(v *viper.Viper) (node.APIAuthConfig, error) {
 	return config, nil
 }
 
-func getIPCConfig(v *viper.Viper) node.IPCConfig {
+func getIPCConfig(v *viper.Viper) (node.IPCConfig, error) {
 	config := node.IPCConfig{
 		IPCAPIEnabled: v.GetBool(IpcAPIEnabledKey),
 		IPCPath:       ipcs.DefaultBaseURL,
+		IPCSocketConfig: socket.Config{
+			BufferSize: v.GetInt(IpcsReaderBufferSizeKey),
+		},
 	}
 	if v.IsSet(IpcsChainIDsKey) {
 		config.IPCDefaultChainIDs = strings.Split(v.GetString(IpcsChainIDsKey), ",")
@@ -205,7 +217,15 @@ func getIPCConfig(v *viper.Viper) node.IPCConfig {
 	if v.IsSet(IpcsPathKey) {
 		config.IPCPath = GetExpandedArg(v, IpcsPathKey)
 	}
-	return config
+	if config.IPCSocketConfig.BufferSize <= 0 {
+		return node.IPCConfig{}, fmt.Errorf("%q must be > 0", IpcsReaderBufferSizeKey)
+	}
+	policy, err := socket.PolicyFromString(v.GetString(IpcsSlowReaderPolicyKey))
+	if err != nil {
+		return node.IPCConfig{}, fmt.Errorf("invalid %q: %w", IpcsSlowReaderPolicyKey, err)
+	}
+	config.IPCSocketConfig.Policy = policy
+	return config, nil
 }
 
 func getHTTPConfig(v *viper.Viper) (node.HTTPConfig, error) {
@@ -253,26 +273,61 @@ func getHTTPConfig(v *viper.Viper) (node.HTTPConfig, error) {
 			KeystoreAPIEnabled: v.GetBool(KeystoreAPIEnabledKey),
 			MetricsAPIEnabled:  v.GetBool(MetricsAPIEnabledKey),
 			HealthAPIEnabled:   v.GetBool(HealthAPIEnabledKey),
+			GRPCAPIEnabled:     v.GetBool(GRPCAPIEnabledKey),
+			ExternalSignerArgs: v.GetStringSlice(KeystoreExternalSignerArgsKey),
 		},
-		HTTPHost:          v.GetString(HTTPHostKey),
-		HTTPPort:          uint16(v.GetUint(HTTPPortKey)),
-		HTTPSEnabled:      v.GetBool(HTTPSEnabledKey),
-		HTTPSKey:          httpsKey,
-		HTTPSCert:         httpsCert,
-		APIAllowedOrigins: v.GetStringSlice(HTTPAllowedOrigins),
+		HTTPHost:              v.GetString(HTTPHostKey),
+		HTTPPort:              uint16(v.GetUint(HTTPPortKey)),
+		HTTPSEnabled:          v.GetBool(HTTPSEnabledKey),
+		HTTPSKey:              httpsKey,
+		HTTPSCert:             httpsCert,
+		APIAllowedOrigins:     v.GetStringSlice(HTTPAllowedOrigins),
+		APIMaxBatchSize:       int(v.GetUint(APIMaxBatchSizeKey)),
+		APIMaxRequestBodySize: int64(v.GetUint64(APIMaxRequestBodySizeKey)),
 
 		ShutdownTimeout: v.GetDuration(HTTPShutdownTimeoutKey),
 		ShutdownWait:    v.GetDuration(HTTPShutdownWaitKey),
 	}
 
+	if v.IsSet(KeystoreExternalSignerPathKey) {
+		config.ExternalSignerPath = GetExpandedArg(v, KeystoreExternalSignerPathKey)
+	}
 	config.APIAuthConfig, err = getAPIAuthConfig(v)
 	if err != nil {
 		return node.HTTPConfig{}, err
 	}
-	config.IPCConfig = getIPCConfig(v)
+	config.IPCConfig, err = getIPCConfig(v)
+	if err != nil {
+		return node.HTTPConfig{}, err
+	}
+	config.RateLimitConfig, err = getAPIRateLimitConfig(v)
+	if err != nil {
+		return node.HTTPConfig{}, err
+	}
 	return config, nil
 }
 
+func getAPIRateLimitConfig(v *viper.Viper) (server.RateLimitConfig, error) {
+	config := server.RateLimitConfig{
+		Enabled: v.GetBool(APIRateLimitEnabledKey),
+		RateLimit: server.RateLimit{
+			RequestsPerSecond: v.GetFloat64(APIRateLimitRequestsPerSecondKey),
+			Burst:             v.GetInt(APIRateLimitBurstKey),
+		},
+	}
+	if endpointLimits := v.GetString(APIRateLimitEndpointLimitsKey); endpointLimits != "" {
+		if err := json.Unmarshal([]byte(endpointLimits), &config.EndpointLimits); err != nil {
+			return server.RateLimitConfig{}, fmt.Errorf("couldn't parse %s: %w", APIRateLimitEndpointLimitsKey, err)
+		}
+	}
+	if methodLimits := v.GetString(APIRateLimitMethodLimitsKey); methodLimits != "" {
+		if err := json.Unmarshal([]byte(methodLimits), &config.MethodLimits); err != nil {
+			return server.RateLimitConfig{}, fmt.Errorf("couldn't parse %s: %w", APIRateLimitMethodLimitsKey, err)
+		}
+	}
+	return config, config.Verify()
+}
+
 func getRouterHealthConfig(v *viper.Viper, halflife time.Duration) (router.HealthConfig, error) {
 	config := router.HealthConfig{
 		MaxDropRate:            v.GetFloat64(RouterHealthMaxDropRateKey),
@@ -388,6 +443,13 @@ func getNetworkConfig(v *viper.Viper, halflife time.Duration) (network.Config, e
 
 		TLSKeyLogFile: v.GetString(NetworkTLSKeyLogFileKey),
 
+		RecorderEnabled: v.GetBool(NetworkRecorderEnabledKey),
+		RecorderConfig: recorder.Config{
+			Directory: GetExpandedArg(v, NetworkRecorderDirKey),
+			MaxSize:   int(v.GetUint(NetworkRecorderMaxSizeKey)),
+			MaxFiles:  int(v.GetUint(NetworkRecorderMaxFilesKey)),
+		},
+
 		TimeoutConfig: network.TimeoutConfig{
 			PingPongTimeout:      v.GetDuration(NetworkPingTimeoutKey),
 			ReadHandshakeTimeout: v.GetDuration(NetworkReadHandshakeTimeoutKey),
@@ -447,6 +509,28 @@ func getNetworkConfig(v *viper.Viper, halflife time.Duration) (network.Config, e
 		return network.Config{}, fmt.Errorf("%s must be >= 0", NetworkReadHandshakeTimeoutKey)
 	case config.MaxClockDifference < 0:
 		return network.Config{}, fmt.Errorf("%s must be >= 0", NetworkMaxClockDifferenceKey)
+	case config.RecorderEnabled && config.RecorderConfig.MaxSize == 0:
+		return network.Config{}, fmt.Errorf("%s must be > 0", NetworkRecorderMaxSizeKey)
+	}
+
+	compressionType, err := compression.TypeFromString(v.GetString(NetworkCompressionTypeKey))
+	if err != nil {
+		return network.Config{}, fmt.Errorf("couldn't parse %s: %w", NetworkCompressionTypeKey, err)
+	}
+	if !config.CompressionEnabled {
+		compressionType = compression.TypeNone
+	}
+	config.CompressionType = compressionType
+
+	if v.IsSet(NetworkCompressionZstdDictionaryFileKey) {
+		dictionaryFilepath := GetExpandedArg(v, NetworkCompressionZstdDictionaryFileKey)
+		config.ZstdDictionary, err = os.ReadFile(filepath.Clean(dictionaryFilepath))
+		if err != nil {
+			return network.Config{}, fmt.Errorf("couldn't read %s: %w", NetworkCompressionZstdDictionaryFileKey, err)
+		}
+		if len(config.ZstdDictionary) == 0 {
+			return network.Config{}, fmt.Errorf("%s must not be empty", NetworkCompressionZstdDictionaryFileKey)
+		}
 	}
 	return config, nil
 }
@@ -653,6 +737,44 @@ func getProfilerConfig(v *viper.Viper) (profiler.Config, error) {
 	return config, nil
 }
 
+func getTraceConfig(v *viper.Viper) (trace.Config, error) {
+	if !v.GetBool(TracingEnabledKey) {
+		return trace.Config{}, nil
+	}
+
+	exporterType, err := trace.ExporterTypeFromString(v.GetString(TracingExporterTypeKey))
+	if err != nil {
+		return trace.Config{}, fmt.Errorf("couldn't parse %s: %w", TracingExporterTypeKey, err)
+	}
+
+	endpoint := v.GetString(TracingEndpointKey)
+	if endpoint == "" {
+		return trace.Config{}, fmt.Errorf("%s must be specified", TracingEndpointKey)
+	}
+
+	headers := make(map[string]string)
+	if headersStr := v.GetString(TracingHeadersKey); headersStr != "" {
+		for _, header := range strings.Split(headersStr, ",") {
+			key, value, ok := strings.Cut(header, "=")
+			if !ok || key == "" {
+				return trace.Config{}, fmt.Errorf("%w: %q", errInvalidTracingHeader, header)
+			}
+			headers[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+
+	return trace.Config{
+		ExporterConfig: trace.ExporterConfig{
+			Type:     exporterType,
+			Endpoint: endpoint,
+			Headers:  headers,
+			Insecure: v.GetBool(TracingInsecureKey),
+		},
+		Enabled:         true,
+		TraceSampleRate: v.GetFloat64(TracingSampleRateKey),
+	}, nil
+}
+
 func getStakingTLSCertFromFlag(v *viper.Viper) (tls.Certificate, error) {
 	stakingKeyRawContent := v.GetString(StakingTLSKeyContentKey)
 	stakingKeyContent, err := base64.StdEncoding.DecodeString(stakingKeyRawContent)
@@ -789,6 +911,8 @@ func getStakingConfig(v *viper.Viper, networkID uint32) (node.StakingConfig, err
 		StakingKeyPath:        GetExpandedArg(v, StakingTLSKeyPathKey),
 		StakingCertPath:       GetExpandedArg(v, StakingCertPathKey),
 		StakingSignerPath:     GetExpandedArg(v, StakingSignerKeyPathKey),
+
+		ValidatorHistoryBackfillDepth: v.GetUint64(ValidatorHistoryBackfillDepthKey),
 	}
 	if !config.EnableStaking && config.DisabledStakingWeight == 0 {
 		return node.StakingConfig{}, errInvalidStakerWeights
@@ -1243,6 +1367,23 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 		return node.Config{}, fmt.Errorf("%s must be >= 0", ConsensusGossipFrequencyKey)
 	}
 
+	// Message queues
+	if lanes := v.GetString(ConsensusMessageLanesKey); lanes != "" {
+		if err := json.Unmarshal([]byte(lanes), &nodeConfig.ConsensusMessageLanes); err != nil {
+			return node.Config{}, fmt.Errorf("couldn't parse %s: %w", ConsensusMessageLanesKey, err)
+		}
+	}
+	// Queues whose lanes aren't overridden use the default lanes.
+	if nodeConfig.ConsensusMessageLanes.Sync == nil {
+		nodeConfig.ConsensusMessageLanes.Sync = handler.DefaultSyncLanes
+	}
+	if nodeConfig.ConsensusMessageLanes.Async == nil {
+		nodeConfig.ConsensusMessageLanes.Async = handler.DefaultAsyncLanes
+	}
+	if err := nodeConfig.ConsensusMessageLanes.Verify(); err != nil {
+		return node.Config{}, fmt.Errorf("invalid %s: %w", ConsensusMessageLanesKey, err)
+	}
+
 	var err error
 	// Logging
 	nodeConfig.LoggingConfig, err = getLoggingConfig(v)
@@ -1307,6 +1448,12 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 	// Metrics
 	nodeConfig.MeterVMEnabled = v.GetBool(MeterVMsEnabledKey)
 
+	// Tracing
+	nodeConfig.TraceConfig, err = getTraceConfig(v)
+	if err != nil {
+		return node.Config{}, err
+	}
+
 	// Adaptive Timeout Config
 	nodeConfig.AdaptiveTimeoutConfig, err = getAdaptiveTimeoutConfig(v)
 	if err != nil {
@@ -1383,6 +1530,10 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 	nodeConfig.SystemTrackerProcessingHalflife = v.GetDuration(SystemTrackerProcessingHalflifeKey)
 	nodeConfig.SystemTrackerCPUHalflife = v.GetDuration(SystemTrackerCPUHalflifeKey)
 	nodeConfig.SystemTrackerDiskHalflife = v.GetDuration(SystemTrackerDiskHalflifeKey)
+	nodeConfig.SystemTrackerHealthHalflife = v.GetDuration(SystemTrackerHealthHalflifeKey)
+	if nodeConfig.SystemTrackerHealthHalflife <= 0 {
+		return node.Config{}, fmt.Errorf("%q must > 0", SystemTrackerHealthHalflifeKey)
+	}
 
 	nodeConfig.RequiredAvailableDiskSpace, nodeConfig.WarningThresholdAvailableDiskSpace, err = getDiskSpaceConfig(v)
 	if err != nil {
@@ -18,7 +18,11 @@ import (
 
 	"github.com/dim4egster/qmallgo/database/leveldb"
 	"github.com/dim4egster/qmallgo/database/memdb"
+	"github.com/dim4egster/qmallgo/database/pebbledb"
 	"github.com/dim4egster/qmallgo/genesis"
+	"github.com/dim4egster/qmallgo/ipcs/socket"
+	"github.com/dim4egster/qmallgo/trace"
+	"github.com/dim4egster/qmallgo/utils/compression"
 	"github.com/dim4egster/qmallgo/utils/constants"
 	"github.com/dim4egster/qmallgo/utils/ulimit"
 	"github.com/dim4egster/qmallgo/utils/units"
@@ -38,6 +42,7 @@ var (
 	defaultDBDir                = filepath.Join(defaultUnexpandedDataDir, "db")
 	defaultLogDir               = filepath.Join(defaultUnexpandedDataDir, "logs")
 	defaultProfileDir           = filepath.Join(defaultUnexpandedDataDir, "profiles")
+	defaultRecorderDir          = filepath.Join(defaultUnexpandedDataDir, "recordings")
 	defaultStakingPath          = filepath.Join(defaultUnexpandedDataDir, "staking")
 	defaultStakingTLSKeyPath    = filepath.Join(defaultStakingPath, "staker.key")
 	defaultStakingCertPath      = filepath.Join(defaultStakingPath, "staker.crt")
@@ -111,7 +116,7 @@ func addNodeFlags(fs *flag.FlagSet) {
 	fs.Uint64(AddSubnetDelegatorFeeKey, genesis.LocalParams.AddSubnetDelegatorFee, "Transaction fee, in nQMALL, for transactions that add new subnet delegators")
 
 	// Database
-	fs.String(DBTypeKey, leveldb.Name, fmt.Sprintf("Database type to use. Should be one of {%s, %s}", leveldb.Name, memdb.Name))
+	fs.String(DBTypeKey, leveldb.Name, fmt.Sprintf("Database type to use. Should be one of {%s, %s, %s}", leveldb.Name, pebbledb.Name, memdb.Name))
 	fs.String(DBPathKey, defaultDBDir, "Path to database directory")
 	fs.String(DBConfigFileKey, "", fmt.Sprintf("Path to database config file. Ignored if %s is specified", DBConfigContentKey))
 	fs.String(DBConfigContentKey, "", "Specifies base64 encoded database config content")
@@ -170,6 +175,8 @@ func addNodeFlags(fs *flag.FlagSet) {
 	fs.Duration(NetworkPingFrequencyKey, constants.DefaultPingFrequency, "Frequency of pinging other peers")
 
 	fs.Bool(NetworkCompressionEnabledKey, true, "If true, compress certain outbound messages. This node will be able to parse compressed inbound messages regardless of this flag's value")
+	fs.String(NetworkCompressionTypeKey, compression.TypeGzip.String(), fmt.Sprintf("Compression type to use for outbound messages. Must be one of %s. Peers that are unable to decompress the configured type are sent gzip compressed messages. Ignored if %s is false", compression.Types, NetworkCompressionEnabledKey))
+	fs.String(NetworkCompressionZstdDictionaryFileKey, "", fmt.Sprintf("Path to a zstd dictionary used to compress outbound messages that carry containers. Only used if %s is zstd. Peers that aren't using the same dictionary are sent messages compressed without it", NetworkCompressionTypeKey))
 	fs.Duration(NetworkMaxClockDifferenceKey, time.Minute, "Max allowed clock difference value between this node and peers")
 	fs.Bool(NetworkAllowPrivateIPsKey, true, "Allows the node to initiate outbound connection attempts to peers with private IPs")
 	fs.Bool(NetworkRequireValidatorToConnectKey, false, "If true, this node will only maintain a connection with another node if this node is a validator, the other node is a validator, or the other node is a beacon")
@@ -178,6 +185,11 @@ func addNodeFlags(fs *flag.FlagSet) {
 
 	fs.String(NetworkTLSKeyLogFileKey, "", "TLS key log file path. Should only be specified for debugging")
 
+	fs.Bool(NetworkRecorderEnabledKey, false, "If true, the messages sent to and received from peers, and the peers connecting and disconnecting, are recorded. Should only be enabled for debugging")
+	fs.String(NetworkRecorderDirKey, defaultRecorderDir, fmt.Sprintf("Directory the network recording is written to. Ignored if %s is false", NetworkRecorderEnabledKey))
+	fs.Uint(NetworkRecorderMaxSizeKey, 100, "Size, in megabytes, a network recording file can grow to before a new file is started")
+	fs.Uint(NetworkRecorderMaxFilesKey, 10, "Number of old network recording files to retain. 0 retains all of them")
+
 	// Benchlist
 	fs.Int(BenchlistFailThresholdKey, 10, "Number of consecutive failed queries before benchlisting a node")
 	fs.Duration(BenchlistDurationKey, 15*time.Minute, "Max amount of time a peer is benchlisted after surpassing the threshold")
@@ -186,6 +198,7 @@ func addNodeFlags(fs *flag.FlagSet) {
 	// Router
 	fs.Duration(ConsensusGossipFrequencyKey, 10*time.Second, "Frequency of gossiping accepted frontiers")
 	fs.Duration(ConsensusShutdownTimeoutKey, 30*time.Second, "Timeout before killing an unresponsive chain")
+	fs.String(ConsensusMessageLanesKey, "", `JSON object overriding the lanes that a chain's synchronous and asynchronous messages are queued in. Ops that aren't assigned to a lane share a lane with a weight of 1. Example: {"sync":[{"name":"consensus","weight":4,"ops":["push_query","pull_query","chits"]}],"async":[{"name":"app","weight":1,"ops":["app_request"]}]}`)
 	fs.Uint(ConsensusGossipAcceptedFrontierValidatorSizeKey, 0, "Number of validators to gossip to when gossiping accepted frontier")
 	fs.Uint(ConsensusGossipAcceptedFrontierNonValidatorSizeKey, 0, "Number of non-validators to gossip to when gossiping accepted frontier")
 	fs.Uint(ConsensusGossipAcceptedFrontierPeerSizeKey, 15, "Number of peers to gossip to when gossiping accepted frontier")
@@ -227,14 +240,24 @@ func addNodeFlags(fs *flag.FlagSet) {
 		fmt.Sprintf("Password file used to initially create/validate API authorization tokens. Ignored if %s is specified. Leading and trailing whitespace is removed from the password. Can be changed via API call",
 			APIAuthPasswordKey))
 	fs.String(APIAuthPasswordKey, "", "Specifies password for API authorization tokens")
+	fs.Uint(APIMaxBatchSizeKey, 1000, "Maximum number of JSON-RPC requests in a batch request. If 0, batch requests are rejected")
+	fs.Uint64(APIMaxRequestBodySizeKey, 16*units.MiB, "Maximum size, in bytes, of the body of an API request that is read to authorize it, rate limit it or split it into a batch")
+	fs.Bool(APIRateLimitEnabledKey, false, "If true, the requests of each IP and each API authorization token are rate limited per endpoint and JSON-RPC method")
+	fs.Float64(APIRateLimitRequestsPerSecondKey, 50, "Number of requests per second each client may make to each endpoint and JSON-RPC method")
+	fs.Int(APIRateLimitBurstKey, 100, "Number of requests each client may make to each endpoint and JSON-RPC method in a burst")
+	fs.String(APIRateLimitEndpointLimitsKey, "", `JSON object overriding the rate limit of endpoints. Example: {"/ext/index/X/tx":{"requestsPerSecond":5,"burst":10}}`)
+	fs.String(APIRateLimitMethodLimitsKey, "", `JSON object overriding the rate limit of JSON-RPC methods. Example: {"avm.getUTXOs":{"requestsPerSecond":5,"burst":10}}`)
 
 	// Enable/Disable APIs
 	fs.Bool(AdminAPIEnabledKey, false, "If true, this node exposes the Admin API")
 	fs.Bool(InfoAPIEnabledKey, true, "If true, this node exposes the Info API")
 	fs.Bool(KeystoreAPIEnabledKey, true, "If true, this node exposes the Keystore API")
+	fs.String(KeystoreExternalSignerPathKey, "", "Path of an external signer the P-Chain and X-Chain keystore services sign through, rather than with the keys of the keystore users. See main/signer for the reference signer")
+	fs.String(KeystoreExternalSignerArgsKey, "", "Arguments the external signer is launched with. Example: --keychain-file=/path/to/keychain --password-file=/path/to/password")
 	fs.Bool(MetricsAPIEnabledKey, true, "If true, this node exposes the Metrics API")
 	fs.Bool(HealthAPIEnabledKey, true, "If true, this node exposes the Health API")
 	fs.Bool(IpcAPIEnabledKey, false, "If true, IPCs can be opened")
+	fs.Bool(GRPCAPIEnabledKey, false, "If true, this node serves the read methods of the Info, Health, Index, P-Chain and X-Chain APIs over gRPC on the HTTP port")
 
 	// Health Checks
 	fs.Duration(HealthCheckFreqKey, 30*time.Second, "Time between health checks")
@@ -263,6 +286,7 @@ func addNodeFlags(fs *flag.FlagSet) {
 	fs.String(StakingSignerKeyContentKey, "", "Specifies base64 encoded signer private key for staking")
 
 	fs.Uint64(StakingDisabledWeightKey, 100, "Weight to provide to each peer when staking is disabled")
+	fs.Uint64(ValidatorHistoryBackfillDepthKey, 100_000, "Maximum number of P-chain blocks walked back to find when the current validators started validating, when their history is first recorded. Staking periods that started earlier are reported as partial")
 	// Uptime Requirement
 	fs.Float64(UptimeRequirementKey, genesis.LocalParams.UptimeRequirement, "Fraction of time a validator must be online to receive rewards")
 	// Minimum Stake required to validate the Primary Network
@@ -311,14 +335,26 @@ func addNodeFlags(fs *flag.FlagSet) {
 	fs.Duration(SnowMaxTimeProcessingKey, 2*time.Minute, "Maximum amount of time an item should be processing and still be healthy")
 	fs.Uint(SnowMixedQueryNumPushVdrKey, 10, fmt.Sprintf("If this node is a validator, when a container is inserted into consensus, send a Push Query to %s validators and a Pull Query to the others. Must be <= k.", SnowMixedQueryNumPushVdrKey))
 	fs.Uint(SnowMixedQueryNumPushNonVdrKey, 0, fmt.Sprintf("If this node is not a validator, when a container is inserted into consensus, send a Push Query to %s validators and a Pull Query to the others. Must be <= k.", SnowMixedQueryNumPushNonVdrKey))
+	fs.Bool(SnowAdaptiveSamplingEnabledKey, false, "If true, validators are sampled by their stake scaled by how quickly and reliably they respond to requests")
+	fs.Float64(SnowAdaptiveSamplingMinScoreKey, 0.5, fmt.Sprintf("Minimum portion of its stake a validator keeps as its sampling weight if %s is true. Must be in (0, 1]", SnowAdaptiveSamplingEnabledKey))
 
 	// Metrics
 	fs.Bool(MeterVMsEnabledKey, true, "Enable Meter VMs to track VM performance with more granularity")
 	fs.Duration(UptimeMetricFreqKey, 30*time.Second, "Frequency of renewing this node's average uptime metric")
 
+	// Tracing
+	fs.Bool(TracingEnabledKey, false, "If true, enable opentelemetry tracing")
+	fs.String(TracingExporterTypeKey, trace.GRPC.String(), fmt.Sprintf("Type of exporter to use for tracing. Options are [%s, %s]", trace.GRPC, trace.HTTP))
+	fs.String(TracingEndpointKey, "localhost:4317", "The endpoint of the collector that traces are exported to")
+	fs.Bool(TracingInsecureKey, true, "If true, don't use TLS when exporting traces")
+	fs.Float64(TracingSampleRateKey, 0.1, "The fraction of traces to sample. If >= 1, always sample. If <= 0, never sample")
+	fs.String(TracingHeadersKey, "", "Comma separated list of key=value headers to send to the tracing collector. Example: api-key=abc,tenant=node1")
+
 	// IPC
 	fs.String(IpcsChainIDsKey, "", "Comma separated list of chain ids to add to the IPC engine. Example: 11111111111111111111111111111111LpoYY,4R5p2RXDGLqaifZE4hHWH9owe34pfoBULn1DrQTWivjg8o4aH")
 	fs.String(IpcsPathKey, "", "The directory (Unix) or named pipe name prefix (Windows) for IPC sockets")
+	fs.Int(IpcsReaderBufferSizeKey, socket.DefaultBufferSize, "Number of events that may be pending for an IPC reader")
+	fs.String(IpcsSlowReaderPolicyKey, socket.PolicyDrop.String(), fmt.Sprintf("Action taken when an IPC reader's buffer is full. Must be one of {%s, %s}", socket.PolicyDrop, socket.PolicyDisconnect))
 
 	// Indexer
 	fs.Bool(IndexEnabledKey, false, "If true, index all accepted containers and transactions and expose them via an API")
@@ -347,6 +383,7 @@ func addNodeFlags(fs *flag.FlagSet) {
 	fs.Duration(SystemTrackerProcessingHalflifeKey, 15*time.Second, "Halflife to use for the processing requests tracker. Larger halflife --> usage metrics change more slowly")
 	fs.Duration(SystemTrackerCPUHalflifeKey, 15*time.Second, "Halflife to use for the cpu tracker. Larger halflife --> cpu usage metrics change more slowly")
 	fs.Duration(SystemTrackerDiskHalflifeKey, time.Minute, "Halflife to use for the disk tracker. Larger halflife --> disk usage metrics change more slowly")
+	fs.Duration(SystemTrackerHealthHalflifeKey, time.Minute, "Halflife to use for the peer health tracker. Larger halflife --> peer response latencies, failure rates and bandwidths change more slowly")
 	fs.Uint64(SystemTrackerRequiredAvailableDiskSpaceKey, units.GiB/2, "Minimum number of available bytes on disk, under which the node will shutdown.")
 	fs.Uint64(SystemTrackerWarningThresholdAvailableDiskSpaceKey, units.GiB, fmt.Sprintf("Warning threshold for the number of available bytes on disk, under which the node will be considered unhealthy.  Must be >= [%s]", SystemTrackerRequiredAvailableDiskSpaceKey))
 
@@ -33,6 +33,7 @@ const (
 	StakeMinConsumptionRateKey                         = "stake-min-consumption-rate"
 	StakeMintingPeriodKey                              = "stake-minting-period"
 	StakeSupplyCapKey                                  = "stake-supply-cap"
+	ValidatorHistoryBackfillDepthKey                   = "validator-history-backfill-depth"
 	AssertionsEnabledKey                               = "assertions-enabled"
 	DBTypeKey                                          = "db-type"
 	DBPathKey                                          = "db-dir"
@@ -60,6 +61,13 @@ const (
 	APIAuthRequiredKey                                 = "api-auth-required"
 	APIAuthPasswordKey                                 = "api-auth-password"
 	APIAuthPasswordFileKey                             = "api-auth-password-file"
+	APIMaxBatchSizeKey                                 = "api-max-batch-size"
+	APIMaxRequestBodySizeKey                           = "api-max-request-body-size"
+	APIRateLimitEnabledKey                             = "api-rate-limit-enabled"
+	APIRateLimitRequestsPerSecondKey                   = "api-rate-limit-requests-per-second"
+	APIRateLimitBurstKey                               = "api-rate-limit-burst"
+	APIRateLimitEndpointLimitsKey                      = "api-rate-limit-endpoint-limits"
+	APIRateLimitMethodLimitsKey                        = "api-rate-limit-method-limits"
 	StateSyncIPsKey                                    = "state-sync-ips"
 	StateSyncIDsKey                                    = "state-sync-ids"
 	BootstrapIPsKey                                    = "bootstrap-ips"
@@ -98,12 +106,18 @@ const (
 	NetworkPingFrequencyKey                            = "network-ping-frequency"
 	NetworkMaxReconnectDelayKey                        = "network-max-reconnect-delay"
 	NetworkCompressionEnabledKey                       = "network-compression-enabled"
+	NetworkCompressionTypeKey                          = "network-compression-type"
+	NetworkCompressionZstdDictionaryFileKey            = "network-compression-zstd-dictionary-file"
 	NetworkMaxClockDifferenceKey                       = "network-max-clock-difference"
 	NetworkAllowPrivateIPsKey                          = "network-allow-private-ips"
 	NetworkRequireValidatorToConnectKey                = "network-require-validator-to-connect"
 	NetworkPeerReadBufferSizeKey                       = "network-peer-read-buffer-size"
 	NetworkPeerWriteBufferSizeKey                      = "network-peer-write-buffer-size"
 	NetworkTLSKeyLogFileKey                            = "network-tls-key-log-file-unsafe"
+	NetworkRecorderEnabledKey                          = "network-recorder-enabled"
+	NetworkRecorderDirKey                              = "network-recorder-dir"
+	NetworkRecorderMaxSizeKey                          = "network-recorder-max-size"
+	NetworkRecorderMaxFilesKey                         = "network-recorder-max-files"
 	BenchlistFailThresholdKey                          = "benchlist-fail-threshold"
 	BenchlistDurationKey                               = "benchlist-duration"
 	BenchlistMinFailingDurationKey                     = "benchlist-min-failing-duration"
@@ -129,17 +143,25 @@ const (
 	SnowMaxTimeProcessingKey                           = "snow-max-time-processing"
 	SnowMixedQueryNumPushVdrKey                        = "snow-mixed-query-num-push-vdr"
 	SnowMixedQueryNumPushNonVdrKey                     = "snow-mixed-query-num-push-non-vdr"
+	SnowAdaptiveSamplingEnabledKey                     = "snow-adaptive-sampling-enabled"
+	SnowAdaptiveSamplingMinScoreKey                    = "snow-adaptive-sampling-min-score"
 	WhitelistedSubnetsKey                              = "whitelisted-subnets"
 	AdminAPIEnabledKey                                 = "api-admin-enabled"
 	InfoAPIEnabledKey                                  = "api-info-enabled"
 	KeystoreAPIEnabledKey                              = "api-keystore-enabled"
+	KeystoreExternalSignerPathKey                      = "keystore-external-signer-path"
+	KeystoreExternalSignerArgsKey                      = "keystore-external-signer-args"
 	MetricsAPIEnabledKey                               = "api-metrics-enabled"
 	HealthAPIEnabledKey                                = "api-health-enabled"
 	IpcAPIEnabledKey                                   = "api-ipcs-enabled"
+	GRPCAPIEnabledKey                                  = "api-grpc-enabled"
 	IpcsChainIDsKey                                    = "ipcs-chain-ids"
 	IpcsPathKey                                        = "ipcs-path"
+	IpcsReaderBufferSizeKey                            = "ipcs-reader-buffer-size"
+	IpcsSlowReaderPolicyKey                            = "ipcs-slow-reader-policy"
 	MeterVMsEnabledKey                                 = "meter-vms-enabled"
 	ConsensusGossipFrequencyKey                        = "consensus-gossip-frequency"
+	ConsensusMessageLanesKey                           = "consensus-message-lanes"
 	ConsensusGossipAcceptedFrontierValidatorSizeKey    = "consensus-accepted-frontier-gossip-validator-size"
 	ConsensusGossipAcceptedFrontierNonValidatorSizeKey = "consensus-accepted-frontier-gossip-non-validator-size"
 	ConsensusGossipAcceptedFrontierPeerSizeKey         = "consensus-accepted-frontier-gossip-peer-size"
@@ -187,6 +209,7 @@ const (
 	SystemTrackerProcessingHalflifeKey                 = "system-tracker-processing-halflife"
 	SystemTrackerCPUHalflifeKey                        = "system-tracker-cpu-halflife"
 	SystemTrackerDiskHalflifeKey                       = "system-tracker-disk-halflife"
+	SystemTrackerHealthHalflifeKey                     = "system-tracker-health-halflife"
 	SystemTrackerRequiredAvailableDiskSpaceKey         = "system-tracker-disk-required-available-space"
 	SystemTrackerWarningThresholdAvailableDiskSpaceKey = "system-tracker-disk-warning-threshold-available-space"
 	DiskVdrAllocKey                                    = "throttler-inbound-disk-validator-alloc"
@@ -198,4 +221,10 @@ const (
 	UptimeMetricFreqKey                                = "uptime-metric-freq"
 	VMAliasesFileKey                                   = "vm-aliases-file"
 	VMAliasesContentKey                                = "vm-aliases-file-content"
+	TracingEnabledKey                                  = "tracing-enabled"
+	TracingExporterTypeKey                             = "tracing-exporter-type"
+	TracingEndpointKey                                 = "tracing-endpoint"
+	TracingInsecureKey                                 = "tracing-insecure"
+	TracingSampleRateKey                               = "tracing-sample-rate"
+	TracingHeadersKey                                  = "tracing-headers"
 )
@@ -11,8 +11,10 @@ import (
 )
 
 var (
-	_ database.Database = &Database{}
-	_ database.Batch    = &batch{}
+	_ database.Database    = &Database{}
+	_ database.Snapshotter = &Database{}
+	_ database.Snapshot    = &snapshot{}
+	_ database.Batch       = &batch{}
 )
 
 // CorruptableDB is a wrapper around Database
@@ -86,6 +88,27 @@ func (db *Database) NewBatch() database.Batch {
 	}
 }
 
+// NewSnapshot returns a snapshot of the underlying database. If the underlying
+// database doesn't support snapshots, [database.ErrSnapshotNotSupported] is
+// returned.
+func (db *Database) NewSnapshot() (database.Snapshot, error) {
+	if err := db.corrupted(); err != nil {
+		return nil, err
+	}
+	snapshotter, ok := db.Database.(database.Snapshotter)
+	if !ok {
+		return nil, database.ErrSnapshotNotSupported
+	}
+	snap, err := snapshotter.NewSnapshot()
+	if err != nil {
+		return nil, db.handleError(err)
+	}
+	return &snapshot{
+		Snapshot: snap,
+		db:       db,
+	}, nil
+}
+
 func (db *Database) corrupted() error {
 	db.errorLock.RLock()
 	defer db.errorLock.RUnlock()
@@ -95,7 +118,7 @@ func (db *Database) corrupted() error {
 
 func (db *Database) handleError(err error) error {
 	switch err {
-	case nil, database.ErrNotFound, database.ErrClosed:
+	case nil, database.ErrNotFound, database.ErrClosed, database.ErrSnapshotNotSupported:
 	// If we get an error other than "not found" or "closed", disallow future
 	// database operations to avoid possible corruption
 	default:
@@ -124,3 +147,28 @@ func (b *batch) Write() error {
 	}
 	return b.db.handleError(b.Batch.Write())
 }
+
+// snapshot is a wrapper around a snapshot of the underlying database.
+type snapshot struct {
+	database.Snapshot
+	db *Database
+}
+
+// Has returns if the key was set in the database when the snapshot was taken
+func (s *snapshot) Has(key []byte) (bool, error) {
+	if err := s.db.corrupted(); err != nil {
+		return false, err
+	}
+	has, err := s.Snapshot.Has(key)
+	return has, s.db.handleError(err)
+}
+
+// Get returns the value the key mapped to in the database when the snapshot
+// was taken
+func (s *snapshot) Get(key []byte) ([]byte, error) {
+	if err := s.db.corrupted(); err != nil {
+		return nil, err
+	}
+	value, err := s.Snapshot.Get(key)
+	return value, s.db.handleError(err)
+}
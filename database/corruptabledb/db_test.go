@@ -21,6 +21,28 @@ func TestInterface(t *testing.T) {
 	}
 }
 
+func TestSnapshotInterface(t *testing.T) {
+	for _, test := range database.SnapshotTests {
+		baseDB := memdb.New()
+		db := New(baseDB)
+		test(t, db)
+	}
+}
+
+func TestSnapshotNotSupported(t *testing.T) {
+	require := require.New(t)
+
+	// Embedding the database hides its NewSnapshot method.
+	baseDB := struct{ database.Database }{memdb.New()}
+	db := New(baseDB)
+
+	_, err := db.NewSnapshot()
+	require.ErrorIs(err, database.ErrSnapshotNotSupported)
+
+	// Not supporting snapshots shouldn't be treated as corruption.
+	require.NoError(db.Put([]byte("hello"), []byte("world")))
+}
+
 // TestCorruption tests to make sure corruptabledb wrapper works as expected.
 func TestCorruption(t *testing.T) {
 	key := []byte("hello")
@@ -78,6 +78,27 @@ type Compacter interface {
 	Compact(start []byte, limit []byte) error
 }
 
+// Snapshot is a read-only, point-in-time view of a database. Writes made to the
+// database after the snapshot was taken are not visible through the snapshot.
+//
+// Once the snapshot has been released, or the database it was taken from has
+// been closed, all operations on the snapshot return [ErrClosed].
+type Snapshot interface {
+	KeyValueReader
+	Iteratee
+
+	// Release releases the resources held by the snapshot. Release should
+	// always succeed and can be called multiple times without causing error.
+	Release()
+}
+
+// Snapshotter wraps the NewSnapshot method of a backing data store.
+type Snapshotter interface {
+	// NewSnapshot returns a consistent, read-only view of the current state of
+	// the database. The returned snapshot must be released after use.
+	NewSnapshot() (Snapshot, error)
+}
+
 // Database contains all the methods required to allow handling different
 // key-value data stores backing the database.
 type Database interface {
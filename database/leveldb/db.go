@@ -63,9 +63,11 @@ const (
 )
 
 var (
-	_ database.Database = &Database{}
-	_ database.Batch    = &batch{}
-	_ database.Iterator = &iter{}
+	_ database.Database    = &Database{}
+	_ database.Snapshotter = &Database{}
+	_ database.Snapshot    = &snapshot{}
+	_ database.Batch       = &batch{}
+	_ database.Iterator    = &iter{}
 )
 
 // Database is a persistent key-value store. Apart from basic data storage
@@ -332,6 +334,18 @@ func (db *Database) NewIteratorWithStartAndPrefix(start, prefix []byte) database
 	}
 }
 
+// NewSnapshot returns a read-only, point-in-time view of the database
+func (db *Database) NewSnapshot() (database.Snapshot, error) {
+	snap, err := db.DB.GetSnapshot()
+	if err != nil {
+		return nil, updateError(err)
+	}
+	return &snapshot{
+		db:   db,
+		snap: snap,
+	}, nil
+}
+
 // This comment is basically copy pasted from the underlying levelDB library:
 
 // Compact the underlying DB for the given key range.
@@ -430,6 +444,68 @@ func (r *replayer) Delete(key []byte) {
 	r.err = r.writerDeleter.Delete(key)
 }
 
+// snapshot is a wrapper around a levelDB snapshot.
+type snapshot struct {
+	db   *Database
+	snap *leveldb.Snapshot
+}
+
+// Has returns if the key was set in the database when the snapshot was taken
+func (s *snapshot) Has(key []byte) (bool, error) {
+	has, err := s.snap.Has(key, nil)
+	return has, updateError(err)
+}
+
+// Get returns the value the key mapped to in the database when the snapshot
+// was taken
+func (s *snapshot) Get(key []byte) ([]byte, error) {
+	value, err := s.snap.Get(key, nil)
+	return value, updateError(err)
+}
+
+// NewIterator creates a lexicographically ordered iterator over the snapshot
+func (s *snapshot) NewIterator() database.Iterator {
+	return &iter{
+		db:       s.db,
+		Iterator: s.snap.NewIterator(new(util.Range), nil),
+	}
+}
+
+// NewIteratorWithStart creates a lexicographically ordered iterator over the
+// snapshot starting at the provided key
+func (s *snapshot) NewIteratorWithStart(start []byte) database.Iterator {
+	return &iter{
+		db:       s.db,
+		Iterator: s.snap.NewIterator(&util.Range{Start: start}, nil),
+	}
+}
+
+// NewIteratorWithPrefix creates a lexicographically ordered iterator over the
+// snapshot ignoring keys that do not start with the provided prefix
+func (s *snapshot) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return &iter{
+		db:       s.db,
+		Iterator: s.snap.NewIterator(util.BytesPrefix(prefix), nil),
+	}
+}
+
+// NewIteratorWithStartAndPrefix creates a lexicographically ordered iterator
+// over the snapshot starting at start and ignoring keys that do not start with
+// the provided prefix
+func (s *snapshot) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	iterRange := util.BytesPrefix(prefix)
+	if bytes.Compare(start, prefix) == 1 {
+		iterRange.Start = start
+	}
+	return &iter{
+		db:       s.db,
+		Iterator: s.snap.NewIterator(iterRange, nil),
+	}
+}
+
+// Release releases the snapshot
+func (s *snapshot) Release() { s.snap.Release() }
+
 type iter struct {
 	db *Database
 	iterator.Iterator
@@ -471,7 +547,7 @@ func (it *iter) Value() []byte { return it.val }
 
 func updateError(err error) error {
 	switch err {
-	case leveldb.ErrClosed:
+	case leveldb.ErrClosed, leveldb.ErrSnapshotReleased:
 		return database.ErrClosed
 	case leveldb.ErrNotFound:
 		return database.ErrNotFound
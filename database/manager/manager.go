@@ -17,6 +17,7 @@ import (
 	"github.com/dim4egster/qmallgo/database/leveldb"
 	"github.com/dim4egster/qmallgo/database/memdb"
 	"github.com/dim4egster/qmallgo/database/meterdb"
+	"github.com/dim4egster/qmallgo/database/pebbledb"
 	"github.com/dim4egster/qmallgo/database/prefixdb"
 	"github.com/dim4egster/qmallgo/utils"
 	"github.com/dim4egster/qmallgo/utils/logging"
@@ -96,6 +97,29 @@ func NewLevelDB(
 	)
 }
 
+// NewPebbleDB creates a database manager of pebbleDBs at [filePath] by creating
+// a database instance from each directory with a version <= [currentVersion].
+// If [includePreviousVersions], opens previous database versions and includes
+// them in the returned Manager.
+func NewPebbleDB(
+	dbDirPath string,
+	dbConfig []byte,
+	log logging.Logger,
+	currentVersion *version.Semantic,
+	namespace string,
+	reg prometheus.Registerer,
+) (Manager, error) {
+	return new(
+		pebbledb.New,
+		dbDirPath,
+		dbConfig,
+		log,
+		currentVersion,
+		namespace,
+		reg,
+	)
+}
+
 // new creates a database manager at [filePath] by creating a database instance
 // from each directory with a version <= [currentVersion]. If
 // [includePreviousVersions], opens previous database versions and includes them
@@ -22,9 +22,12 @@ const (
 )
 
 var (
-	_ database.Database = &Database{}
-	_ database.Batch    = &batch{}
-	_ database.Iterator = &iterator{}
+	_ database.Database    = &Database{}
+	_ database.Snapshotter = &Database{}
+	_ database.Snapshot    = &snapshot{}
+	_ database.Batch       = &batch{}
+	_ database.Iterator    = &iterator{}
+	_ database.Iterator    = &snapshotIterator{}
 )
 
 // Database is an ephemeral key-value store that implements the Database
@@ -147,6 +150,25 @@ func (db *Database) NewIteratorWithStartAndPrefix(start, prefix []byte) database
 	}
 }
 
+// NewSnapshot returns a copy of the current state of the database. Values are
+// never modified in place, so only the map itself needs to be copied.
+func (db *Database) NewSnapshot() (database.Snapshot, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return nil, database.ErrClosed
+	}
+	snapshotDB := NewWithSize(len(db.db))
+	for key, value := range db.db {
+		snapshotDB.db[key] = value
+	}
+	return &snapshot{
+		parent: db,
+		db:     snapshotDB,
+	}, nil
+}
+
 func (db *Database) Compact(start []byte, limit []byte) error {
 	db.lock.RLock()
 	defer db.lock.RUnlock()
@@ -283,3 +305,70 @@ func (it *iterator) Value() []byte {
 }
 
 func (it *iterator) Release() { it.keys = nil; it.values = nil }
+
+// snapshot is a read-only copy of a database. It is considered closed once
+// either the copy has been released or the database it was taken from has been
+// closed.
+type snapshot struct {
+	parent *Database
+	db     *Database
+}
+
+func (s *snapshot) Has(key []byte) (bool, error) {
+	if s.parent.isClosed() {
+		return false, database.ErrClosed
+	}
+	return s.db.Has(key)
+}
+
+func (s *snapshot) Get(key []byte) ([]byte, error) {
+	if s.parent.isClosed() {
+		return nil, database.ErrClosed
+	}
+	return s.db.Get(key)
+}
+
+func (s *snapshot) NewIterator() database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(nil, nil)
+}
+
+func (s *snapshot) NewIteratorWithStart(start []byte) database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(start, nil)
+}
+
+func (s *snapshot) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(nil, prefix)
+}
+
+func (s *snapshot) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	return &snapshotIterator{
+		Iterator: s.db.NewIteratorWithStartAndPrefix(start, prefix),
+		parent:   s.parent,
+	}
+}
+
+func (s *snapshot) Release() { _ = s.db.Close() }
+
+// snapshotIterator additionally stops iterating once the database that the
+// snapshot was taken from has been closed.
+type snapshotIterator struct {
+	database.Iterator
+	parent *Database
+	err    error
+}
+
+func (it *snapshotIterator) Next() bool {
+	if it.parent.isClosed() {
+		it.Iterator.Release()
+		it.err = database.ErrClosed
+		return false
+	}
+	return it.Iterator.Next()
+}
+
+func (it *snapshotIterator) Error() error {
+	if it.err != nil {
+		return it.err
+	}
+	return it.Iterator.Error()
+}
@@ -11,9 +11,11 @@ import (
 )
 
 var (
-	_ database.Database = &Database{}
-	_ database.Batch    = &batch{}
-	_ database.Iterator = &iterator{}
+	_ database.Database    = &Database{}
+	_ database.Snapshotter = &Database{}
+	_ database.Snapshot    = &snapshot{}
+	_ database.Batch       = &batch{}
+	_ database.Iterator    = &iterator{}
 )
 
 // Database tracks the amount of time each operation takes and how many bytes
@@ -114,6 +116,28 @@ func (db *Database) NewIteratorWithStartAndPrefix(
 	return it
 }
 
+// NewSnapshot returns a snapshot of the underlying database. If the underlying
+// database doesn't support snapshots, [database.ErrSnapshotNotSupported] is
+// returned.
+func (db *Database) NewSnapshot() (database.Snapshot, error) {
+	snapshotter, ok := db.db.(database.Snapshotter)
+	if !ok {
+		return nil, database.ErrSnapshotNotSupported
+	}
+
+	start := db.clock.Time()
+	snap, err := snapshotter.NewSnapshot()
+	end := db.clock.Time()
+	db.newSnapshot.Observe(float64(end.Sub(start)))
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot{
+		snapshot: snap,
+		db:       db,
+	}, nil
+}
+
 func (db *Database) Compact(start, limit []byte) error {
 	startTime := db.clock.Time()
 	err := db.db.Compact(start, limit)
@@ -249,3 +273,61 @@ func (it *iterator) Release() {
 	end := it.db.clock.Time()
 	it.db.iRelease.Observe(float64(end.Sub(start)))
 }
+
+type snapshot struct {
+	snapshot database.Snapshot
+	db       *Database
+}
+
+func (s *snapshot) Has(key []byte) (bool, error) {
+	start := s.db.clock.Time()
+	has, err := s.snapshot.Has(key)
+	end := s.db.clock.Time()
+	s.db.readSize.Observe(float64(len(key)))
+	s.db.sHas.Observe(float64(end.Sub(start)))
+	s.db.sHasSize.Observe(float64(len(key)))
+	return has, err
+}
+
+func (s *snapshot) Get(key []byte) ([]byte, error) {
+	start := s.db.clock.Time()
+	value, err := s.snapshot.Get(key)
+	end := s.db.clock.Time()
+	s.db.readSize.Observe(float64(len(key) + len(value)))
+	s.db.sGet.Observe(float64(end.Sub(start)))
+	s.db.sGetSize.Observe(float64(len(key) + len(value)))
+	return value, err
+}
+
+func (s *snapshot) NewIterator() database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(nil, nil)
+}
+
+func (s *snapshot) NewIteratorWithStart(start []byte) database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(start, nil)
+}
+
+func (s *snapshot) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(nil, prefix)
+}
+
+func (s *snapshot) NewIteratorWithStartAndPrefix(
+	start,
+	prefix []byte,
+) database.Iterator {
+	startTime := s.db.clock.Time()
+	it := &iterator{
+		iterator: s.snapshot.NewIteratorWithStartAndPrefix(start, prefix),
+		db:       s.db,
+	}
+	end := s.db.clock.Time()
+	s.db.newIterator.Observe(float64(end.Sub(startTime)))
+	return it
+}
+
+func (s *snapshot) Release() {
+	start := s.db.clock.Time()
+	s.snapshot.Release()
+	end := s.db.clock.Time()
+	s.db.sRelease.Observe(float64(end.Sub(start)))
+}
@@ -41,6 +41,7 @@ type metrics struct {
 	delete, deleteSize,
 	newBatch,
 	newIterator,
+	newSnapshot,
 	compact,
 	close,
 	healthCheck,
@@ -55,7 +56,10 @@ type metrics struct {
 	iError,
 	iKey,
 	iValue,
-	iRelease metric.Averager
+	iRelease,
+	sHas, sHasSize,
+	sGet, sGetSize,
+	sRelease metric.Averager
 }
 
 func newMetrics(namespace string, reg prometheus.Registerer) (metrics, error) {
@@ -73,6 +77,7 @@ func newMetrics(namespace string, reg prometheus.Registerer) (metrics, error) {
 		deleteSize:  newSizeMetric(namespace, "delete", reg, &errs),
 		newBatch:    newTimeMetric(namespace, "new_batch", reg, &errs),
 		newIterator: newTimeMetric(namespace, "new_iterator", reg, &errs),
+		newSnapshot: newTimeMetric(namespace, "new_snapshot", reg, &errs),
 		compact:     newTimeMetric(namespace, "compact", reg, &errs),
 		close:       newTimeMetric(namespace, "close", reg, &errs),
 		healthCheck: newTimeMetric(namespace, "health_check", reg, &errs),
@@ -92,5 +97,10 @@ func newMetrics(namespace string, reg prometheus.Registerer) (metrics, error) {
 		iKey:        newTimeMetric(namespace, "iterator_key", reg, &errs),
 		iValue:      newTimeMetric(namespace, "iterator_value", reg, &errs),
 		iRelease:    newTimeMetric(namespace, "iterator_release", reg, &errs),
+		sHas:        newTimeMetric(namespace, "snapshot_has", reg, &errs),
+		sHasSize:    newSizeMetric(namespace, "snapshot_has", reg, &errs),
+		sGet:        newTimeMetric(namespace, "snapshot_get", reg, &errs),
+		sGetSize:    newSizeMetric(namespace, "snapshot_get", reg, &errs),
+		sRelease:    newTimeMetric(namespace, "snapshot_release", reg, &errs),
 	}, errs.Err
 }
@@ -0,0 +1,87 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package migrate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/dim4egster/qmallgo/utils/perms"
+)
+
+// checkpoint records the progress of migrating a single versioned database.
+type checkpoint struct {
+	// LastKey is the last key that was written to the destination. Keys are
+	// copied in order, so every key <= LastKey has been migrated.
+	LastKey []byte `json:"lastKey"`
+	// NumCopied is the number of keys that have been written to the
+	// destination.
+	NumCopied uint64 `json:"numCopied"`
+	// NumKeys is the number of keys in the source database. It is only
+	// populated once the source has been counted.
+	NumKeys uint64 `json:"numKeys"`
+	// Counted is true once [NumKeys] has been populated.
+	Counted bool `json:"counted"`
+	// Done is true once every key has been migrated.
+	Done bool `json:"done"`
+}
+
+// checkpoints persists the progress of a migration to a file so that an
+// interrupted migration can be resumed.
+type checkpoints struct {
+	path string
+	// Versions maps a database version string to its migration progress.
+	Versions map[string]*checkpoint `json:"versions"`
+}
+
+// loadCheckpoints reads the checkpoints stored at [path]. If no file exists at
+// [path], empty checkpoints are returned.
+func loadCheckpoints(path string) (*checkpoints, error) {
+	c := &checkpoints{
+		path:     path,
+		Versions: make(map[string]*checkpoint),
+	}
+	bytes, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint file %q: %w", path, err)
+	}
+	if err := json.Unmarshal(bytes, c); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint file %q: %w", path, err)
+	}
+	if c.Versions == nil {
+		c.Versions = make(map[string]*checkpoint)
+	}
+	return c, nil
+}
+
+// get returns the checkpoint of [version], creating it if it doesn't exist.
+func (c *checkpoints) get(version string) *checkpoint {
+	cp, ok := c.Versions[version]
+	if !ok {
+		cp = &checkpoint{}
+		c.Versions[version] = cp
+	}
+	return cp
+}
+
+// write atomically persists the checkpoints to disk.
+func (c *checkpoints) write() error {
+	bytes, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	// Writing to a temporary file and renaming it ensures that a crash never
+	// leaves a partially written checkpoint behind.
+	tmpPath := c.path + ".tmp"
+	if err := perms.WriteFile(tmpPath, bytes, perms.ReadWrite); err != nil {
+		return fmt.Errorf("failed to write checkpoint file %q: %w", tmpPath, err)
+	}
+	return os.Rename(tmpPath, c.path)
+}
@@ -0,0 +1,283 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package migrate
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"go.uber.org/zap"
+
+	"github.com/dim4egster/qmallgo/database"
+	"github.com/dim4egster/qmallgo/database/leveldb"
+	"github.com/dim4egster/qmallgo/database/pebbledb"
+	"github.com/dim4egster/qmallgo/utils/logging"
+	"github.com/dim4egster/qmallgo/utils/timer"
+	"github.com/dim4egster/qmallgo/utils/units"
+	"github.com/dim4egster/qmallgo/version"
+)
+
+const (
+	// DefaultBatchSize is the default number of bytes that are buffered before
+	// being written to the destination database.
+	DefaultBatchSize = 4 * units.MiB
+
+	// DefaultProgressFrequency is the default frequency that progress is
+	// logged.
+	DefaultProgressFrequency = 10 * time.Second
+
+	// CheckpointFileName is the name of the file, in the destination
+	// directory, that records the progress of a migration.
+	CheckpointFileName = "migration_checkpoint.json"
+)
+
+var (
+	errKeyMismatch   = errors.New("key mismatch")
+	errValueMismatch = errors.New("value mismatch")
+	errMissingKey    = errors.New("missing key")
+	errUnexpectedKey = errors.New("unexpected key")
+
+	// Factories contains the constructors of every persistent database type
+	// that can be migrated from or to.
+	Factories = map[string]Factory{
+		leveldb.Name:  leveldb.New,
+		pebbledb.Name: pebbledb.New,
+	}
+)
+
+// Factory creates a persistent database at [path].
+type Factory func(path string, config []byte, log logging.Logger, namespace string, reg prometheus.Registerer) (database.Database, error)
+
+type Config struct {
+	// BatchSize is the number of bytes that are buffered before being written
+	// to the destination database.
+	BatchSize int
+	// ProgressFrequency is the frequency that progress is logged.
+	ProgressFrequency time.Duration
+	// CheckpointPath is the file used to persist the progress of the
+	// migration.
+	CheckpointPath string
+}
+
+// Migrator copies the contents of versioned databases into another database
+// backend. If a migration is interrupted, it is resumed from the last written
+// batch the next time it is run with the same checkpoint file.
+type Migrator struct {
+	log         logging.Logger
+	config      Config
+	checkpoints *checkpoints
+}
+
+func New(log logging.Logger, config Config) (*Migrator, error) {
+	checkpoints, err := loadCheckpoints(config.CheckpointPath)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{
+		log:         log,
+		config:      config,
+		checkpoints: checkpoints,
+	}, nil
+}
+
+// Migrate streams every key of [src] into [dst]. The progress of the
+// migration is tracked under [dbVersion].
+func (m *Migrator) Migrate(dbVersion *version.Semantic, src database.Iteratee, dst database.Batcher) error {
+	versionStr := dbVersion.String()
+	cp := m.checkpoints.get(versionStr)
+	if cp.Done {
+		m.log.Info("skipping previously migrated database",
+			zap.Stringer("dbVersion", dbVersion),
+			zap.Uint64("numKeys", cp.NumCopied),
+		)
+		return nil
+	}
+
+	if !cp.Counted {
+		m.log.Info("counting keys to migrate",
+			zap.Stringer("dbVersion", dbVersion),
+		)
+		numKeys, err := database.Count(src)
+		if err != nil {
+			return fmt.Errorf("failed to count keys of database %s: %w", versionStr, err)
+		}
+		cp.NumKeys = uint64(numKeys)
+		cp.Counted = true
+		if err := m.checkpoints.write(); err != nil {
+			return err
+		}
+	}
+
+	m.log.Info("migrating database",
+		zap.Stringer("dbVersion", dbVersion),
+		zap.Uint64("numKeys", cp.NumKeys),
+		zap.Uint64("numCopied", cp.NumCopied),
+	)
+
+	var (
+		startTime        = time.Now()
+		lastLog          = startTime
+		startNumCopied   = cp.NumCopied
+		remainingToCopy  = cp.NumKeys - cp.NumCopied
+		resumeAfterKey   = cp.LastKey
+		batch            = dst.NewBatch()
+		numCopiedInBatch uint64
+		lastKey          []byte
+	)
+	if cp.NumCopied > cp.NumKeys {
+		// The source database was modified after it was counted.
+		remainingToCopy = 0
+	}
+
+	flush := func() error {
+		if err := batch.Write(); err != nil {
+			return fmt.Errorf("failed to write batch: %w", err)
+		}
+		batch.Reset()
+
+		cp.LastKey = lastKey
+		cp.NumCopied += numCopiedInBatch
+		numCopiedInBatch = 0
+		return m.checkpoints.write()
+	}
+
+	it := src.NewIteratorWithStart(resumeAfterKey)
+	defer it.Release()
+
+	for it.Next() {
+		key := it.Key()
+		if resumeAfterKey != nil && bytes.Equal(key, resumeAfterKey) {
+			// [resumeAfterKey] was already written in a prior run.
+			continue
+		}
+		if err := batch.Put(key, it.Value()); err != nil {
+			return err
+		}
+		lastKey = key
+		numCopiedInBatch++
+
+		if batch.Size() < m.config.BatchSize {
+			continue
+		}
+		if err := flush(); err != nil {
+			return err
+		}
+
+		if now := time.Now(); now.Sub(lastLog) >= m.config.ProgressFrequency {
+			lastLog = now
+			numCopiedThisRun := cp.NumCopied - startNumCopied
+			fields := []zap.Field{
+				zap.Stringer("dbVersion", dbVersion),
+				zap.Uint64("numCopied", cp.NumCopied),
+				zap.Uint64("numKeys", cp.NumKeys),
+			}
+			if numCopiedThisRun < remainingToCopy {
+				fields = append(fields, zap.Duration("eta", timer.EstimateETA(
+					startTime,
+					numCopiedThisRun,
+					remainingToCopy,
+				)))
+			}
+			m.log.Info("migrating database", fields...)
+		}
+	}
+	if err := it.Error(); err != nil {
+		return fmt.Errorf("failed to iterate over database %s: %w", versionStr, err)
+	}
+	if numCopiedInBatch > 0 {
+		if err := flush(); err != nil {
+			return err
+		}
+	}
+
+	cp.Done = true
+	if err := m.checkpoints.write(); err != nil {
+		return err
+	}
+
+	m.log.Info("finished migrating database",
+		zap.Stringer("dbVersion", dbVersion),
+		zap.Uint64("numCopied", cp.NumCopied),
+		zap.Duration("duration", time.Since(startTime)),
+	)
+	return nil
+}
+
+// Verify returns nil if [src] and [dst] contain exactly the same key-value
+// pairs.
+func Verify(src, dst database.Iteratee) error {
+	srcIt := src.NewIterator()
+	defer srcIt.Release()
+
+	dstIt := dst.NewIterator()
+	defer dstIt.Release()
+
+	for {
+		srcNext := srcIt.Next()
+		dstNext := dstIt.Next()
+		switch {
+		case !srcNext && !dstNext:
+			if err := srcIt.Error(); err != nil {
+				return err
+			}
+			return dstIt.Error()
+		case !dstNext:
+			if err := dstIt.Error(); err != nil {
+				return err
+			}
+			return fmt.Errorf("%w: 0x%x", errMissingKey, srcIt.Key())
+		case !srcNext:
+			if err := srcIt.Error(); err != nil {
+				return err
+			}
+			return fmt.Errorf("%w: 0x%x", errUnexpectedKey, dstIt.Key())
+		}
+
+		srcKey := srcIt.Key()
+		dstKey := dstIt.Key()
+		if !bytes.Equal(srcKey, dstKey) {
+			return fmt.Errorf("%w: expected 0x%x but found 0x%x", errKeyMismatch, srcKey, dstKey)
+		}
+		if !bytes.Equal(srcIt.Value(), dstIt.Value()) {
+			return fmt.Errorf("%w: for key 0x%x", errValueMismatch, srcKey)
+		}
+	}
+}
+
+// Prune removes every versioned database directory in [dbDirPath] with a
+// version strictly less than [minVersion]. Directories that aren't named after
+// a version are left untouched. The removed paths are returned.
+func Prune(dbDirPath string, minVersion *version.Semantic) ([]string, error) {
+	entries, err := os.ReadDir(dbDirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		dbVersion, err := version.Parse(entry.Name())
+		if err != nil {
+			continue
+		}
+		if dbVersion.Compare(minVersion) >= 0 {
+			continue
+		}
+
+		path := filepath.Join(dbDirPath, entry.Name())
+		if err := os.RemoveAll(path); err != nil {
+			return removed, fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+		removed = append(removed, path)
+	}
+	return removed, nil
+}
@@ -0,0 +1,191 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dim4egster/qmallgo/database"
+	"github.com/dim4egster/qmallgo/database/memdb"
+	"github.com/dim4egster/qmallgo/utils/logging"
+	"github.com/dim4egster/qmallgo/utils/perms"
+	"github.com/dim4egster/qmallgo/version"
+)
+
+var errTest = errors.New("non-nil error")
+
+func populate(t *testing.T, db database.KeyValueWriter, numKeys int) {
+	for i := 0; i < numKeys; i++ {
+		key := []byte(fmt.Sprintf("key%04d", i))
+		value := []byte(fmt.Sprintf("value%04d", i))
+		require.NoError(t, db.Put(key, value))
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	require := require.New(t)
+
+	src := memdb.New()
+	populate(t, src, 100)
+
+	dst := memdb.New()
+	m, err := New(logging.NoLog{}, Config{
+		BatchSize:         64,
+		ProgressFrequency: DefaultProgressFrequency,
+		CheckpointPath:    filepath.Join(t.TempDir(), CheckpointFileName),
+	})
+	require.NoError(err)
+
+	require.NoError(m.Migrate(version.CurrentDatabase, src, dst))
+	require.NoError(Verify(src, dst))
+
+	cp := m.checkpoints.get(version.CurrentDatabase.String())
+	require.True(cp.Done)
+	require.Equal(uint64(100), cp.NumKeys)
+	require.Equal(uint64(100), cp.NumCopied)
+}
+
+// failingBatcher fails to write any batch after [numWrites] batches have been
+// written.
+type failingBatcher struct {
+	database.Database
+	numWrites int
+}
+
+func (f *failingBatcher) NewBatch() database.Batch {
+	return &failingBatch{
+		Batch:   f.Database.NewBatch(),
+		batcher: f,
+	}
+}
+
+type failingBatch struct {
+	database.Batch
+	batcher *failingBatcher
+}
+
+func (b *failingBatch) Write() error {
+	if b.batcher.numWrites <= 0 {
+		return errTest
+	}
+	b.batcher.numWrites--
+	return b.Batch.Write()
+}
+
+func TestMigrateResume(t *testing.T) {
+	require := require.New(t)
+
+	src := memdb.New()
+	populate(t, src, 100)
+
+	dst := memdb.New()
+	config := Config{
+		BatchSize:         64,
+		ProgressFrequency: DefaultProgressFrequency,
+		CheckpointPath:    filepath.Join(t.TempDir(), CheckpointFileName),
+	}
+	m, err := New(logging.NoLog{}, config)
+	require.NoError(err)
+
+	err = m.Migrate(version.CurrentDatabase, src, &failingBatcher{
+		Database:  dst,
+		numWrites: 10,
+	})
+	require.ErrorIs(err, errTest)
+
+	numCopied, err := database.Count(dst)
+	require.NoError(err)
+	require.Less(numCopied, 100)
+
+	// Reloading the checkpoint should resume from the last written batch.
+	m, err = New(logging.NoLog{}, config)
+	require.NoError(err)
+
+	cp := m.checkpoints.get(version.CurrentDatabase.String())
+	require.False(cp.Done)
+	require.Equal(uint64(numCopied), cp.NumCopied)
+
+	require.NoError(m.Migrate(version.CurrentDatabase, src, dst))
+	require.NoError(Verify(src, dst))
+	require.Equal(uint64(100), cp.NumCopied)
+}
+
+func TestVerify(t *testing.T) {
+	tests := []struct {
+		name        string
+		modify      func(*testing.T, database.Database)
+		expectedErr error
+	}{
+		{
+			name:        "equal",
+			modify:      func(*testing.T, database.Database) {},
+			expectedErr: nil,
+		},
+		{
+			name: "missing key",
+			modify: func(t *testing.T, db database.Database) {
+				require.NoError(t, db.Delete([]byte("key0009")))
+			},
+			expectedErr: errMissingKey,
+		},
+		{
+			name: "unexpected key",
+			modify: func(t *testing.T, db database.Database) {
+				require.NoError(t, db.Put([]byte("key9999"), nil))
+			},
+			expectedErr: errUnexpectedKey,
+		},
+		{
+			name: "key mismatch",
+			modify: func(t *testing.T, db database.Database) {
+				require.NoError(t, db.Delete([]byte("key0005")))
+				require.NoError(t, db.Put([]byte("key0005a"), []byte("value0005")))
+			},
+			expectedErr: errKeyMismatch,
+		},
+		{
+			name: "value mismatch",
+			modify: func(t *testing.T, db database.Database) {
+				require.NoError(t, db.Put([]byte("key0005"), []byte("other")))
+			},
+			expectedErr: errValueMismatch,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			src := memdb.New()
+			populate(t, src, 10)
+
+			dst := memdb.New()
+			populate(t, dst, 10)
+			test.modify(t, dst)
+
+			err := Verify(src, dst)
+			require.ErrorIs(t, err, test.expectedErr)
+		})
+	}
+}
+
+func TestPrune(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	for _, name := range []string{"v1.0.0", "v1.4.5", "v0.9.0", "not-a-version"} {
+		require.NoError(os.Mkdir(filepath.Join(dir, name), perms.ReadWriteExecute))
+	}
+
+	removed, err := Prune(dir, version.DatabaseVersion1_0_0)
+	require.NoError(err)
+	require.Equal([]string{filepath.Join(dir, "v0.9.0")}, removed)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(err)
+	require.Len(entries, 3)
+}
@@ -0,0 +1,866 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pebbledb
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/bloom"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"go.uber.org/zap"
+
+	"github.com/dim4egster/qmallgo/database"
+	"github.com/dim4egster/qmallgo/utils"
+	"github.com/dim4egster/qmallgo/utils/logging"
+	"github.com/dim4egster/qmallgo/utils/units"
+)
+
+const (
+	// Name is the name of this database for database switches
+	Name = "pebbledb"
+
+	// DefaultCacheSize is the number of bytes to use for block caching in
+	// pebble.
+	DefaultCacheSize = 512 * units.MiB
+
+	// DefaultMemTableSize is the number of bytes to use for each memtable in
+	// pebble.
+	DefaultMemTableSize = 64 * units.MiB
+
+	// DefaultMemTableStopWritesThreshold is the number of queued memtables
+	// after which writes are stalled.
+	DefaultMemTableStopWritesThreshold = 8
+
+	// DefaultMaxOpenFiles is the number of files descriptors to cap pebble to
+	// use.
+	DefaultMaxOpenFiles = 4096
+
+	// DefaultL0CompactionThreshold is the number of level 0 read amplification
+	// that triggers a level 0 compaction.
+	DefaultL0CompactionThreshold = 2
+
+	// DefaultL0StopWritesThreshold is the number of level 0 read amplification
+	// that causes writes to be stalled.
+	DefaultL0StopWritesThreshold = 1000
+
+	// DefaultBytesPerSync is the number of bytes written to sstables before
+	// pebble issues a background sync.
+	DefaultBytesPerSync = 512 * units.KiB
+
+	// DefaultWALBytesPerSync is the number of bytes written to the WAL before
+	// pebble issues a background sync.
+	DefaultWALBytesPerSync = 0
+
+	// DefaultBitsPerKey is the number of bits to add to the bloom filter per
+	// key.
+	DefaultBitsPerKey = 10
+
+	// DefaultMetricUpdateFrequency is the frequency to poll the pebble
+	// metrics.
+	DefaultMetricUpdateFrequency = 10 * time.Second
+
+	// pebbleByteOverhead is the number of bytes of constant overhead that
+	// should be added to a batch size per operation.
+	pebbleByteOverhead = 8
+
+	// numLevels is the number of levels in the pebble LSM.
+	numLevels = 7
+)
+
+var (
+	_ database.Database    = &Database{}
+	_ database.Snapshotter = &Database{}
+	_ database.Snapshot    = &snapshot{}
+	_ database.Batch       = &batch{}
+	_ database.Iterator    = &iter{}
+)
+
+// Database is a persistent key-value store backed by pebble. Apart from basic
+// data storage functionality it also supports batch writes and iterating over
+// the keyspace in binary-alphabetical order.
+type Database struct {
+	// lock protects [pebbleDB], [closed], [openIterators] and
+	// [openSnapshots]. Pebble panics if it is used after being closed, so
+	// every access is guarded.
+	lock          sync.RWMutex
+	pebbleDB      *pebble.DB
+	closed        bool
+	openIterators map[*iter]struct{}
+	openSnapshots map[*snapshot]struct{}
+	writeOptions  *pebble.WriteOptions
+
+	// metrics is only initialized and used when [MetricUpdateFrequency] is >= 0
+	// in the config
+	metrics   metrics
+	closeOnce sync.Once
+	// closeCh is closed when Close() is called.
+	closeCh chan struct{}
+	// closeWg is used to wait for all goroutines created by New() to exit.
+	closeWg sync.WaitGroup
+}
+
+type config struct {
+	// CacheSize is the number of bytes to use for the block cache.
+	//
+	// The default value is 512MiB.
+	CacheSize int64 `json:"cacheSize"`
+	// BytesPerSync is the number of bytes written to sstables before pebble
+	// issues a background sync to smooth out disk writes.
+	//
+	// The default value is 512KiB.
+	BytesPerSync int `json:"bytesPerSync"`
+	// WALBytesPerSync is the number of bytes written to the WAL before pebble
+	// issues a background sync. Zero disables background syncing.
+	//
+	// The default value is 0.
+	WALBytesPerSync int `json:"walBytesPerSync"`
+	// MemTableStopWritesThreshold is the hard limit on the number of queued
+	// memtables. Writes are stalled once this limit is reached.
+	//
+	// The default value is 8.
+	MemTableStopWritesThreshold int `json:"memTableStopWritesThreshold"`
+	// MemTableSize is the size of a single memtable.
+	//
+	// The default value is 64MiB.
+	MemTableSize int `json:"memTableSize"`
+	// MaxOpenFiles is the soft limit on the number of open files.
+	//
+	// The default value is 4096.
+	MaxOpenFiles int `json:"maxOpenFiles"`
+	// MaxConcurrentCompactions is the maximum number of concurrent
+	// compactions.
+	//
+	// The default value is the number of CPUs.
+	MaxConcurrentCompactions int `json:"maxConcurrentCompactions"`
+	// L0CompactionThreshold is the amount of level 0 read amplification
+	// necessary to trigger a level 0 compaction.
+	//
+	// The default value is 2.
+	L0CompactionThreshold int `json:"l0CompactionThreshold"`
+	// L0StopWritesThreshold is the amount of level 0 read amplification
+	// necessary to stall writes.
+	//
+	// The default value is 1000.
+	L0StopWritesThreshold int `json:"l0StopWritesThreshold"`
+	// FilterBitsPerKey is the number of bits per key used by the bloom filter
+	// of every level.
+	//
+	// The default value is 10.
+	FilterBitsPerKey int `json:"filterBitsPerKey"`
+	// Sync determines whether writes are synced to disk before returning.
+	//
+	// The default is false.
+	Sync bool `json:"sync"`
+
+	// MetricUpdateFrequency is the frequency to poll pebble metrics.
+	// If <= 0, pebble metrics aren't polled.
+	MetricUpdateFrequency time.Duration `json:"metricUpdateFrequency"`
+}
+
+// New returns a wrapped pebble DB object.
+func New(file string, configBytes []byte, log logging.Logger, namespace string, reg prometheus.Registerer) (database.Database, error) {
+	parsedConfig := config{
+		CacheSize:                   DefaultCacheSize,
+		BytesPerSync:                DefaultBytesPerSync,
+		WALBytesPerSync:             DefaultWALBytesPerSync,
+		MemTableStopWritesThreshold: DefaultMemTableStopWritesThreshold,
+		MemTableSize:                DefaultMemTableSize,
+		MaxOpenFiles:                DefaultMaxOpenFiles,
+		MaxConcurrentCompactions:    runtime.NumCPU(),
+		L0CompactionThreshold:       DefaultL0CompactionThreshold,
+		L0StopWritesThreshold:       DefaultL0StopWritesThreshold,
+		FilterBitsPerKey:            DefaultBitsPerKey,
+		MetricUpdateFrequency:       DefaultMetricUpdateFrequency,
+	}
+	if len(configBytes) > 0 {
+		if err := json.Unmarshal(configBytes, &parsedConfig); err != nil {
+			return nil, fmt.Errorf("failed to parse db config: %w", err)
+		}
+	}
+
+	log.Info("creating new pebbledb",
+		zap.Reflect("config", parsedConfig),
+	)
+
+	cache := pebble.NewCache(parsedConfig.CacheSize)
+	// The DB holds its own reference to the cache, so this reference can be
+	// released as soon as the DB is opened.
+	defer cache.Unref()
+
+	maxConcurrentCompactions := parsedConfig.MaxConcurrentCompactions
+	opts := &pebble.Options{
+		Cache:                       cache,
+		BytesPerSync:                parsedConfig.BytesPerSync,
+		WALBytesPerSync:             parsedConfig.WALBytesPerSync,
+		MemTableStopWritesThreshold: parsedConfig.MemTableStopWritesThreshold,
+		MemTableSize:                parsedConfig.MemTableSize,
+		MaxOpenFiles:                parsedConfig.MaxOpenFiles,
+		MaxConcurrentCompactions:    func() int { return maxConcurrentCompactions },
+		L0CompactionThreshold:       parsedConfig.L0CompactionThreshold,
+		L0StopWritesThreshold:       parsedConfig.L0StopWritesThreshold,
+		Logger:                      &logger{log: log},
+		Levels:                      make([]pebble.LevelOptions, numLevels),
+	}
+	filterPolicy := bloom.FilterPolicy(parsedConfig.FilterBitsPerKey)
+	for i := range opts.Levels {
+		opts.Levels[i].FilterPolicy = filterPolicy
+		opts.Levels[i].FilterType = pebble.TableFilter
+	}
+
+	wrappedDB := &Database{
+		openIterators: make(map[*iter]struct{}),
+		openSnapshots: make(map[*snapshot]struct{}),
+		writeOptions:  pebble.NoSync,
+		closeCh:       make(chan struct{}),
+	}
+	if parsedConfig.Sync {
+		wrappedDB.writeOptions = pebble.Sync
+	}
+
+	if parsedConfig.MetricUpdateFrequency > 0 {
+		metrics, err := newMetrics(namespace, reg)
+		if err != nil {
+			return nil, err
+		}
+		wrappedDB.metrics = metrics
+		opts.EventListener = wrappedDB.metrics.eventListener()
+	}
+
+	db, err := pebble.Open(file, opts)
+	if err != nil {
+		return nil, err
+	}
+	wrappedDB.pebbleDB = db
+
+	if parsedConfig.MetricUpdateFrequency > 0 {
+		wrappedDB.closeWg.Add(1)
+		go func() {
+			t := time.NewTicker(parsedConfig.MetricUpdateFrequency)
+			defer func() {
+				t.Stop()
+				wrappedDB.closeWg.Done()
+			}()
+
+			for {
+				if err := wrappedDB.updateMetrics(); err != nil {
+					log.Warn("failed to update pebbledb metrics",
+						zap.Error(err),
+					)
+				}
+
+				select {
+				case <-t.C:
+				case <-wrappedDB.closeCh:
+					return
+				}
+			}
+		}()
+	}
+	return wrappedDB, nil
+}
+
+// Has returns if the key is set in the database
+func (db *Database) Has(key []byte) (bool, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return false, database.ErrClosed
+	}
+
+	_, closer, err := db.pebbleDB.Get(key)
+	if err == pebble.ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, updateError(err)
+	}
+	return true, closer.Close()
+}
+
+// Get returns the value the key maps to in the database
+func (db *Database) Get(key []byte) ([]byte, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return nil, database.ErrClosed
+	}
+
+	value, closer, err := db.pebbleDB.Get(key)
+	if err != nil {
+		return nil, updateError(err)
+	}
+	// [value] is only valid until [closer] is closed, so it must be copied.
+	value = utils.CopyBytes(value)
+	return value, closer.Close()
+}
+
+// Put sets the value of the provided key to the provided value
+func (db *Database) Put(key []byte, value []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+	return updateError(db.pebbleDB.Set(key, value, db.writeOptions))
+}
+
+// Delete removes the key from the database
+func (db *Database) Delete(key []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+	return updateError(db.pebbleDB.Delete(key, db.writeOptions))
+}
+
+// NewBatch creates a write/delete-only buffer that is atomically committed to
+// the database when write is called
+func (db *Database) NewBatch() database.Batch {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	b := &batch{db: db}
+	if !db.closed {
+		b.batch = db.pebbleDB.NewBatch()
+	} else {
+		// The batch will fail to be written, but it must still be usable to
+		// buffer writes.
+		b.batch = new(pebble.Batch)
+	}
+	return b
+}
+
+// NewIterator creates a lexicographically ordered iterator over the database
+func (db *Database) NewIterator() database.Iterator {
+	return db.newIterator(nil, &pebble.IterOptions{})
+}
+
+// NewIteratorWithStart creates a lexicographically ordered iterator over the
+// database starting at the provided key
+func (db *Database) NewIteratorWithStart(start []byte) database.Iterator {
+	return db.newIterator(nil, startOptions(start))
+}
+
+// NewIteratorWithPrefix creates a lexicographically ordered iterator over the
+// database ignoring keys that do not start with the provided prefix
+func (db *Database) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return db.newIterator(nil, startAndPrefixOptions(nil, prefix))
+}
+
+// NewIteratorWithStartAndPrefix creates a lexicographically ordered iterator
+// over the database starting at start and ignoring keys that do not start with
+// the provided prefix
+func (db *Database) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	return db.newIterator(nil, startAndPrefixOptions(start, prefix))
+}
+
+// newIterator creates an iterator over [snap], or over the current state of
+// the database if [snap] is nil.
+func (db *Database) newIterator(snap *snapshot, opts *pebble.IterOptions) *iter {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.closed || (snap != nil && snap.closed) {
+		return &iter{
+			db:     db,
+			closed: true,
+			err:    database.ErrClosed,
+		}
+	}
+
+	// The bounds are copied so that the caller may modify them after the
+	// iterator is created.
+	opts.LowerBound = utils.CopyBytes(opts.LowerBound)
+	opts.UpperBound = utils.CopyBytes(opts.UpperBound)
+	it := &iter{
+		db:       db,
+		snapshot: snap,
+	}
+	if snap != nil {
+		it.iter = snap.snap.NewIter(opts)
+	} else {
+		it.iter = db.pebbleDB.NewIter(opts)
+	}
+	db.openIterators[it] = struct{}{}
+	return it
+}
+
+// NewSnapshot returns a read-only, point-in-time view of the database
+func (db *Database) NewSnapshot() (database.Snapshot, error) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.closed {
+		return nil, database.ErrClosed
+	}
+	snap := &snapshot{
+		db:   db,
+		snap: db.pebbleDB.NewSnapshot(),
+	}
+	db.openSnapshots[snap] = struct{}{}
+	return snap, nil
+}
+
+// Compact the underlying DB for the given key range.
+//
+// A nil start is treated as a key before all keys in the DB.
+// And a nil limit is treated as a key after all keys in the DB.
+// Therefore if both are nil then it will compact entire DB.
+func (db *Database) Compact(start []byte, limit []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+
+	if limit == nil {
+		// Pebble treats a nil [limit] as a key before all keys, so the key
+		// immediately after the last key in the database is used as the
+		// [limit] instead. This keeps the last key in the compacted range
+		// regardless of whether pebble includes [limit] in the range.
+		lastKey, err := db.lastKey()
+		if err != nil {
+			return updateError(err)
+		}
+		if lastKey == nil {
+			// The database is empty, so there is nothing to compact.
+			return nil
+		}
+		limit = make([]byte, len(lastKey)+1)
+		copy(limit, lastKey)
+	}
+
+	if pebble.DefaultComparer.Compare(start, limit) >= 0 {
+		// Pebble requires [start] to be strictly less than [limit].
+		return nil
+	}
+	return updateError(db.pebbleDB.Compact(start, limit, true /* parallelize */))
+}
+
+// lastKey returns the largest key in the database, including the keys that
+// were deleted but are still stored, or nil if the database is empty.
+func (db *Database) lastKey() ([]byte, error) {
+	var lastKey []byte
+	it := db.pebbleDB.NewIter(&pebble.IterOptions{})
+	if it.Last() {
+		lastKey = append([]byte{}, it.Key()...)
+	}
+	if err := it.Close(); err != nil {
+		return nil, err
+	}
+
+	// Deleted keys are skipped by the iterator, but their tombstones are still
+	// stored in the sstables until they are compacted.
+	levels, err := db.pebbleDB.SSTables()
+	if err != nil {
+		return nil, err
+	}
+	for _, tables := range levels {
+		for _, table := range tables {
+			if largest := table.Largest.UserKey; lastKey == nil || pebble.DefaultComparer.Compare(largest, lastKey) > 0 {
+				lastKey = append([]byte{}, largest...)
+			}
+		}
+	}
+	return lastKey, nil
+}
+
+func (db *Database) Close() error {
+	db.closeOnce.Do(func() {
+		close(db.closeCh)
+	})
+	// The metrics goroutine must exit before the lock is grabbed, as it reads
+	// the database under the lock.
+	db.closeWg.Wait()
+
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+	db.closed = true
+
+	// Pebble reports leaked iterators and snapshots as an error on close, so
+	// any that haven't been released yet are released here.
+	for it := range db.openIterators {
+		it.release()
+	}
+	db.openIterators = nil
+	for snap := range db.openSnapshots {
+		snap.release()
+	}
+	db.openSnapshots = nil
+	return updateError(db.pebbleDB.Close())
+}
+
+func (db *Database) HealthCheck() (interface{}, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return nil, database.ErrClosed
+	}
+	return nil, nil
+}
+
+// batch is a wrapper around a pebble batch to contain sizes.
+type batch struct {
+	batch *pebble.Batch
+	db    *Database
+	size  int
+
+	// written is set to true once the batch has been committed. Pebble does
+	// not allow a batch to be committed more than once.
+	written bool
+}
+
+// Put the value into the batch for later writing
+func (b *batch) Put(key, value []byte) error {
+	b.size += len(key) + len(value) + pebbleByteOverhead
+	return b.batch.Set(key, value, nil)
+}
+
+// Delete the key during writing
+func (b *batch) Delete(key []byte) error {
+	b.size += len(key) + pebbleByteOverhead
+	return b.batch.Delete(key, nil)
+}
+
+// Size retrieves the amount of data queued up for writing.
+func (b *batch) Size() int { return b.size }
+
+// Write flushes any accumulated data to disk.
+func (b *batch) Write() error {
+	b.db.lock.RLock()
+	defer b.db.lock.RUnlock()
+
+	if b.db.closed {
+		return database.ErrClosed
+	}
+
+	if b.written {
+		// Pebble doesn't allow a batch to be committed twice, so the contents
+		// are copied into a fresh batch.
+		newBatch := b.db.pebbleDB.NewBatch()
+		if err := b.Replay(&batchWriter{batch: newBatch}); err != nil {
+			return err
+		}
+		b.batch = newBatch
+	}
+
+	b.written = true
+	return updateError(b.batch.Commit(b.db.writeOptions))
+}
+
+// Reset resets the batch for reuse.
+func (b *batch) Reset() {
+	b.batch.Reset()
+	b.written = false
+	b.size = 0
+}
+
+// Replay the batch contents.
+func (b *batch) Replay(w database.KeyValueWriterDeleter) error {
+	reader := b.batch.Reader()
+	for {
+		kind, key, value, ok := reader.Next()
+		if !ok {
+			return nil
+		}
+		switch kind {
+		case pebble.InternalKeyKindSet:
+			if err := w.Put(key, value); err != nil {
+				return err
+			}
+		case pebble.InternalKeyKindDelete:
+			if err := w.Delete(key); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unexpected pebble batch operation %s", kind)
+		}
+	}
+}
+
+// Inner returns itself
+func (b *batch) Inner() database.Batch { return b }
+
+// batchWriter adapts a pebble batch to the database.KeyValueWriterDeleter
+// interface.
+type batchWriter struct {
+	batch *pebble.Batch
+}
+
+func (w *batchWriter) Put(key, value []byte) error {
+	return w.batch.Set(key, value, nil)
+}
+
+func (w *batchWriter) Delete(key []byte) error {
+	return w.batch.Delete(key, nil)
+}
+
+type iter struct {
+	db   *Database
+	iter *pebble.Iterator
+	// snapshot is the snapshot this iterator reads from, or nil if it reads
+	// from the database directly.
+	snapshot *snapshot
+
+	initialized bool
+	// closed is set when the iterator has been released, either explicitly or
+	// by the database being closed.
+	closed bool
+
+	key, val []byte
+	err      error
+}
+
+func (it *iter) Next() bool {
+	it.db.lock.RLock()
+	defer it.db.lock.RUnlock()
+
+	switch {
+	case it.db.closed:
+		// Short-circuit and set an error if the underlying database has been
+		// closed.
+		it.key = nil
+		it.val = nil
+		it.err = database.ErrClosed
+		return false
+	case it.closed:
+		it.key = nil
+		it.val = nil
+		return false
+	}
+
+	var hasNext bool
+	if !it.initialized {
+		hasNext = it.iter.First()
+		it.initialized = true
+	} else {
+		hasNext = it.iter.Next()
+	}
+
+	if hasNext {
+		it.key = utils.CopyBytes(it.iter.Key())
+		it.val = utils.CopyBytes(it.iter.Value())
+	} else {
+		it.key = nil
+		it.val = nil
+	}
+	return hasNext
+}
+
+func (it *iter) Error() error {
+	if it.err != nil {
+		return it.err
+	}
+
+	it.db.lock.RLock()
+	defer it.db.lock.RUnlock()
+
+	if it.closed {
+		return nil
+	}
+	return updateError(it.iter.Error())
+}
+
+func (it *iter) Key() []byte { return it.key }
+
+func (it *iter) Value() []byte { return it.val }
+
+func (it *iter) Release() {
+	it.db.lock.Lock()
+	defer it.db.lock.Unlock()
+
+	if it.closed {
+		return
+	}
+	delete(it.db.openIterators, it)
+	it.release()
+}
+
+// release closes the underlying pebble iterator.
+//
+// Assumes the database lock is held.
+func (it *iter) release() {
+	it.closed = true
+	if err := it.iter.Close(); err != nil && it.err == nil {
+		it.err = updateError(err)
+	}
+}
+
+// snapshot is a wrapper around a pebble snapshot.
+type snapshot struct {
+	db   *Database
+	snap *pebble.Snapshot
+	// closed is set when the snapshot has been released, either explicitly or
+	// by the database being closed.
+	//
+	// Protected by the database lock.
+	closed bool
+}
+
+// Has returns if the key was set in the database when the snapshot was taken
+func (s *snapshot) Has(key []byte) (bool, error) {
+	s.db.lock.RLock()
+	defer s.db.lock.RUnlock()
+
+	if s.db.closed || s.closed {
+		return false, database.ErrClosed
+	}
+
+	_, closer, err := s.snap.Get(key)
+	if err == pebble.ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, updateError(err)
+	}
+	return true, closer.Close()
+}
+
+// Get returns the value the key mapped to in the database when the snapshot
+// was taken
+func (s *snapshot) Get(key []byte) ([]byte, error) {
+	s.db.lock.RLock()
+	defer s.db.lock.RUnlock()
+
+	if s.db.closed || s.closed {
+		return nil, database.ErrClosed
+	}
+
+	value, closer, err := s.snap.Get(key)
+	if err != nil {
+		return nil, updateError(err)
+	}
+	// [value] is only valid until [closer] is closed, so it must be copied.
+	value = utils.CopyBytes(value)
+	return value, closer.Close()
+}
+
+// NewIterator creates a lexicographically ordered iterator over the snapshot
+func (s *snapshot) NewIterator() database.Iterator {
+	return s.db.newIterator(s, &pebble.IterOptions{})
+}
+
+// NewIteratorWithStart creates a lexicographically ordered iterator over the
+// snapshot starting at the provided key
+func (s *snapshot) NewIteratorWithStart(start []byte) database.Iterator {
+	return s.db.newIterator(s, startOptions(start))
+}
+
+// NewIteratorWithPrefix creates a lexicographically ordered iterator over the
+// snapshot ignoring keys that do not start with the provided prefix
+func (s *snapshot) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return s.db.newIterator(s, startAndPrefixOptions(nil, prefix))
+}
+
+// NewIteratorWithStartAndPrefix creates a lexicographically ordered iterator
+// over the snapshot starting at start and ignoring keys that do not start with
+// the provided prefix
+func (s *snapshot) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	return s.db.newIterator(s, startAndPrefixOptions(start, prefix))
+}
+
+// Release releases the snapshot along with any iterators that are still
+// reading from it.
+func (s *snapshot) Release() {
+	s.db.lock.Lock()
+	defer s.db.lock.Unlock()
+
+	if s.closed || s.db.closed {
+		return
+	}
+	// Once the snapshot is closed, pebble no longer guarantees that the
+	// snapshot's view is retained, so its iterators must be released as well.
+	for it := range s.db.openIterators {
+		if it.snapshot != s {
+			continue
+		}
+		delete(s.db.openIterators, it)
+		it.release()
+		it.err = database.ErrClosed
+	}
+	delete(s.db.openSnapshots, s)
+	s.release()
+}
+
+// release closes the underlying pebble snapshot.
+//
+// Assumes the database lock is held.
+func (s *snapshot) release() {
+	s.closed = true
+	_ = s.snap.Close() // Never actually returns an error
+}
+
+func startOptions(start []byte) *pebble.IterOptions {
+	return &pebble.IterOptions{
+		LowerBound: start,
+	}
+}
+
+func startAndPrefixOptions(start, prefix []byte) *pebble.IterOptions {
+	lowerBound := prefix
+	if pebble.DefaultComparer.Compare(start, prefix) == 1 {
+		lowerBound = start
+	}
+	return &pebble.IterOptions{
+		LowerBound: lowerBound,
+		UpperBound: prefixToUpperBound(prefix),
+	}
+}
+
+// prefixToUpperBound returns the smallest key that is larger than every key
+// with the provided prefix. If no such key exists, nil is returned.
+func prefixToUpperBound(prefix []byte) []byte {
+	for i := len(prefix) - 1; i >= 0; i-- {
+		if prefix[i] != 0xFF {
+			upperBound := make([]byte, i+1)
+			copy(upperBound, prefix)
+			upperBound[i]++
+			return upperBound
+		}
+	}
+	return nil
+}
+
+func updateError(err error) error {
+	switch err {
+	case pebble.ErrClosed:
+		return database.ErrClosed
+	case pebble.ErrNotFound:
+		return database.ErrNotFound
+	default:
+		return err
+	}
+}
+
+// logger adapts the node's logger to pebble's logger interface.
+type logger struct {
+	log logging.Logger
+}
+
+func (l *logger) Infof(format string, args ...interface{}) {
+	l.log.Debug(fmt.Sprintf(format, args...))
+}
+
+func (l *logger) Fatalf(format string, args ...interface{}) {
+	l.log.Fatal(fmt.Sprintf(format, args...))
+	panic(fmt.Sprintf(format, args...))
+}
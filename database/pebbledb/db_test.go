@@ -0,0 +1,97 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pebbledb
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dim4egster/qmallgo/database"
+	"github.com/dim4egster/qmallgo/utils/logging"
+)
+
+func TestInterface(t *testing.T) {
+	for _, test := range database.Tests {
+		folder := t.TempDir()
+		db, err := New(folder, nil, logging.NoLog{}, "", prometheus.NewRegistry())
+		if err != nil {
+			t.Fatalf("pebbledb.New(%q, logging.NoLog{}) errored with %s", folder, err)
+		}
+
+		defer db.Close()
+
+		test(t, db)
+
+		// The database may have been closed by the test, so we don't care if it
+		// errors here.
+		_ = db.Close()
+	}
+}
+
+func TestSnapshotInterface(t *testing.T) {
+	for _, test := range database.SnapshotTests {
+		folder := t.TempDir()
+		db, err := New(folder, nil, logging.NoLog{}, "", prometheus.NewRegistry())
+		if err != nil {
+			t.Fatalf("pebbledb.New(%q, logging.NoLog{}) errored with %s", folder, err)
+		}
+
+		test(t, db)
+
+		// The database may have been closed by the test, so we don't care if it
+		// errors here.
+		_ = db.Close()
+	}
+}
+
+func BenchmarkInterface(b *testing.B) {
+	for _, size := range database.BenchmarkSizes {
+		keys, values := database.SetupBenchmark(b, size[0], size[1], size[2])
+		for _, bench := range database.Benchmarks {
+			folder := b.TempDir()
+
+			db, err := New(folder, nil, logging.NoLog{}, "", prometheus.NewRegistry())
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			bench(b, db, "pebbledb", keys, values)
+
+			// The database may have been closed by the test, so we don't care if it
+			// errors here.
+			_ = db.Close()
+		}
+	}
+}
+
+func TestCompactNilLimit(t *testing.T) {
+	require := require.New(t)
+
+	dbIntf, err := New(t.TempDir(), nil, logging.NoLog{}, "", prometheus.NewRegistry())
+	require.NoError(err)
+	db := dbIntf.(*Database)
+	defer db.Close()
+
+	// Each write is flushed into its own file, so compacting any key range
+	// that excludes the last key leaves the files containing it behind.
+	for _, key := range [][]byte{{1}, {2}} {
+		require.NoError(db.Put(key, key))
+		require.NoError(db.pebbleDB.Flush())
+	}
+	for _, key := range [][]byte{{1}, {2}} {
+		require.NoError(db.Delete(key))
+		require.NoError(db.pebbleDB.Flush())
+	}
+
+	require.NoError(db.Compact(nil, nil))
+
+	numFiles := int64(0)
+	for _, level := range db.pebbleDB.Metrics().Levels {
+		numFiles += level.NumFiles
+	}
+	require.Zero(numFiles)
+}
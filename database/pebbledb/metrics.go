@@ -0,0 +1,289 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pebbledb
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/cockroachdb/pebble"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/dim4egster/qmallgo/utils/wrappers"
+)
+
+var levelLabels = []string{"level"}
+
+type metrics struct {
+	// total number of writes that have been delayed due to compaction
+	writesDelayedCount prometheus.Counter
+	// total amount of time (in ns) that writes that have been delayed due to
+	// compaction
+	writesDelayedDuration prometheus.Counter
+	// set to 1 if there is currently at least one write that is being delayed
+	// due to compaction
+	writeIsDelayed prometheus.Gauge
+
+	// number of currently alive snapshots
+	aliveSnapshots prometheus.Gauge
+
+	// total number of bytes of cached data blocks
+	blockCacheSize prometheus.Gauge
+	// total number of block cache hits
+	blockCacheHits prometheus.Counter
+	// total number of block cache misses
+	blockCacheMisses prometheus.Counter
+	// current number of open tables
+	openTables prometheus.Gauge
+
+	// number of bytes allocated by memtables
+	memTableSize prometheus.Gauge
+	// number of memtables
+	memTableCount prometheus.Gauge
+
+	// current read amplification of the LSM
+	readAmp prometheus.Gauge
+	// estimated number of bytes that need to be compacted to reach a stable
+	// state
+	compactionDebt prometheus.Gauge
+	// number of compactions that are currently in progress
+	compactionsInProgress prometheus.Gauge
+
+	// number of tables per level
+	levelTableCount *prometheus.GaugeVec
+	// size of each level
+	levelSize *prometheus.GaugeVec
+	// amount of bytes read while compacting each level
+	levelReads *prometheus.CounterVec
+	// amount of bytes written while compacting each level
+	levelWrites *prometheus.CounterVec
+
+	// total number of flushes performed
+	flushes prometheus.Counter
+	// total number of compactions performed
+	compactions prometheus.Counter
+
+	// time that the current write stall started. Pebble serializes write stall
+	// events, so this doesn't need to be protected by a lock.
+	writeStallStart time.Time
+
+	priorStats *pebble.Metrics
+}
+
+func newMetrics(namespace string, reg prometheus.Registerer) (metrics, error) {
+	m := metrics{
+		writesDelayedCount: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "writes_delayed",
+			Help:      "number of cumulative writes that have been delayed due to compaction",
+		}),
+		writesDelayedDuration: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "writes_delayed_duration",
+			Help:      "amount of time (in ns) that writes have been delayed due to compaction",
+		}),
+		writeIsDelayed: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "write_delayed",
+			Help:      "1 if there is currently a write that is being delayed due to compaction",
+		}),
+
+		aliveSnapshots: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "alive_snapshots",
+			Help:      "number of currently alive snapshots",
+		}),
+
+		blockCacheSize: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "block_cache_size",
+			Help:      "total size of cached blocks",
+		}),
+		blockCacheHits: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "block_cache_hits",
+			Help:      "cumulative number of block cache hits",
+		}),
+		blockCacheMisses: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "block_cache_misses",
+			Help:      "cumulative number of block cache misses",
+		}),
+		openTables: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "open_tables",
+			Help:      "number of currently opened tables",
+		}),
+
+		memTableSize: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "mem_table_size",
+			Help:      "number of bytes allocated by memtables",
+		}),
+		memTableCount: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "mem_table_count",
+			Help:      "number of memtables",
+		}),
+
+		readAmp: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "read_amp",
+			Help:      "current read amplification of the LSM",
+		}),
+		compactionDebt: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "compaction_debt",
+			Help:      "estimated number of bytes that need to be compacted to reach a stable state",
+		}),
+		compactionsInProgress: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "compactions_in_progress",
+			Help:      "number of compactions that are currently in progress",
+		}),
+
+		levelTableCount: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "table_count",
+				Help:      "number of tables allocated by level",
+			},
+			levelLabels,
+		),
+		levelSize: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "size",
+				Help:      "amount of bytes allocated by level",
+			},
+			levelLabels,
+		),
+		levelReads: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "reads",
+				Help:      "amount of bytes read during compaction by level",
+			},
+			levelLabels,
+		),
+		levelWrites: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "writes",
+				Help:      "amount of bytes written during compaction and flushes by level",
+			},
+			levelLabels,
+		),
+
+		flushes: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "flushes",
+			Help:      "total number of memtable flushes performed",
+		}),
+		compactions: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "comps",
+			Help:      "total number of compactions performed",
+		}),
+
+		priorStats: &pebble.Metrics{},
+	}
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		reg.Register(m.writesDelayedCount),
+		reg.Register(m.writesDelayedDuration),
+		reg.Register(m.writeIsDelayed),
+
+		reg.Register(m.aliveSnapshots),
+
+		reg.Register(m.blockCacheSize),
+		reg.Register(m.blockCacheHits),
+		reg.Register(m.blockCacheMisses),
+		reg.Register(m.openTables),
+
+		reg.Register(m.memTableSize),
+		reg.Register(m.memTableCount),
+
+		reg.Register(m.readAmp),
+		reg.Register(m.compactionDebt),
+		reg.Register(m.compactionsInProgress),
+
+		reg.Register(m.levelTableCount),
+		reg.Register(m.levelSize),
+		reg.Register(m.levelReads),
+		reg.Register(m.levelWrites),
+
+		reg.Register(m.flushes),
+		reg.Register(m.compactions),
+	)
+	return m, errs.Err
+}
+
+// eventListener returns the pebble event listener used to track write stalls.
+// Unlike the other metrics, write stalls are reported by pebble as events
+// rather than being polled.
+func (m *metrics) eventListener() *pebble.EventListener {
+	return &pebble.EventListener{
+		WriteStallBegin: func(pebble.WriteStallBeginInfo) {
+			m.writeStallStart = time.Now()
+			m.writesDelayedCount.Inc()
+			m.writeIsDelayed.Set(1)
+		},
+		WriteStallEnd: func() {
+			m.writesDelayedDuration.Add(float64(time.Since(m.writeStallStart)))
+			m.writeIsDelayed.Set(0)
+		},
+	}
+}
+
+func (db *Database) updateMetrics() error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return nil
+	}
+
+	metrics := &db.metrics
+	priorStats := metrics.priorStats
+
+	// Retrieve the database stats
+	currentStats := db.pebbleDB.Metrics()
+
+	metrics.aliveSnapshots.Set(float64(currentStats.Snapshots.Count))
+
+	metrics.blockCacheSize.Set(float64(currentStats.BlockCache.Size))
+	metrics.blockCacheHits.Add(float64(currentStats.BlockCache.Hits - priorStats.BlockCache.Hits))
+	metrics.blockCacheMisses.Add(float64(currentStats.BlockCache.Misses - priorStats.BlockCache.Misses))
+	metrics.openTables.Set(float64(currentStats.TableCache.Count))
+
+	metrics.memTableSize.Set(float64(currentStats.MemTable.Size))
+	metrics.memTableCount.Set(float64(currentStats.MemTable.Count))
+
+	metrics.readAmp.Set(float64(currentStats.ReadAmp()))
+	metrics.compactionDebt.Set(float64(currentStats.Compact.EstimatedDebt))
+	metrics.compactionsInProgress.Set(float64(currentStats.Compact.NumInProgress))
+
+	for level, levelStats := range currentStats.Levels {
+		levelStr := strconv.Itoa(level)
+		priorLevelStats := priorStats.Levels[level]
+
+		metrics.levelTableCount.WithLabelValues(levelStr).Set(float64(levelStats.NumFiles))
+		metrics.levelSize.WithLabelValues(levelStr).Set(float64(levelStats.Size))
+		metrics.levelReads.WithLabelValues(levelStr).Add(float64(levelStats.BytesRead - priorLevelStats.BytesRead))
+		metrics.levelWrites.WithLabelValues(levelStr).Add(float64(
+			levelStats.BytesCompacted + levelStats.BytesFlushed - priorLevelStats.BytesCompacted - priorLevelStats.BytesFlushed,
+		))
+	}
+
+	metrics.flushes.Add(float64(currentStats.Flush.Count - priorStats.Flush.Count))
+	metrics.compactions.Add(float64(currentStats.Compact.Count - priorStats.Compact.Count))
+
+	// update the priorStats to update the counters correctly next time this
+	// method is called
+	metrics.priorStats = currentStats
+	return nil
+}
@@ -17,9 +17,11 @@ const (
 )
 
 var (
-	_ database.Database = &Database{}
-	_ database.Batch    = &batch{}
-	_ database.Iterator = &iterator{}
+	_ database.Database    = &Database{}
+	_ database.Snapshotter = &Database{}
+	_ database.Snapshot    = &snapshot{}
+	_ database.Batch       = &batch{}
+	_ database.Iterator    = &iterator{}
 )
 
 // Database partitions a database into a sub-database by prefixing all keys with
@@ -166,6 +168,30 @@ func (db *Database) NewIteratorWithStartAndPrefix(start, prefix []byte) database
 	return it
 }
 
+// NewSnapshot returns a snapshot of the underlying database restricted to this
+// database's prefix. If the underlying database doesn't support snapshots,
+// [database.ErrSnapshotNotSupported] is returned.
+func (db *Database) NewSnapshot() (database.Snapshot, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return nil, database.ErrClosed
+	}
+	snapshotter, ok := db.db.(database.Snapshotter)
+	if !ok {
+		return nil, database.ErrSnapshotNotSupported
+	}
+	snap, err := snapshotter.NewSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot{
+		Snapshot: snap,
+		db:       db,
+	}, nil
+}
+
 func (db *Database) Compact(start, limit []byte) error {
 	db.lock.RLock()
 	defer db.lock.RUnlock()
@@ -311,6 +337,61 @@ func (b *batch) Replay(w database.KeyValueWriterDeleter) error {
 	return nil
 }
 
+type snapshot struct {
+	database.Snapshot
+	db *Database
+}
+
+// [key] may be modified after this method returns.
+func (s *snapshot) Has(key []byte) (bool, error) {
+	if s.db.isClosed() {
+		return false, database.ErrClosed
+	}
+	prefixedKey := s.db.prefix(key)
+	has, err := s.Snapshot.Has(prefixedKey)
+	s.db.bufferPool.Put(prefixedKey)
+	return has, err
+}
+
+// [key] may be modified after this method returns.
+func (s *snapshot) Get(key []byte) ([]byte, error) {
+	if s.db.isClosed() {
+		return nil, database.ErrClosed
+	}
+	prefixedKey := s.db.prefix(key)
+	val, err := s.Snapshot.Get(prefixedKey)
+	s.db.bufferPool.Put(prefixedKey)
+	return val, err
+}
+
+func (s *snapshot) NewIterator() database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(nil, nil)
+}
+
+func (s *snapshot) NewIteratorWithStart(start []byte) database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(start, nil)
+}
+
+func (s *snapshot) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(nil, prefix)
+}
+
+// It is safe to modify [start] and [prefix] after this method returns.
+func (s *snapshot) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	if s.db.isClosed() {
+		return &nodb.Iterator{Err: database.ErrClosed}
+	}
+	prefixedStart := s.db.prefix(start)
+	prefixedPrefix := s.db.prefix(prefix)
+	it := &iterator{
+		Iterator: s.Snapshot.NewIteratorWithStartAndPrefix(prefixedStart, prefixedPrefix),
+		db:       s.db,
+	}
+	s.db.bufferPool.Put(prefixedStart)
+	s.db.bufferPool.Put(prefixedPrefix)
+	return it
+}
+
 type iterator struct {
 	database.Iterator
 	db *Database
@@ -28,9 +28,11 @@ const (
 )
 
 var (
-	_ database.Database = &DatabaseClient{}
-	_ database.Batch    = &batch{}
-	_ database.Iterator = &iterator{}
+	_ database.Database    = &DatabaseClient{}
+	_ database.Snapshotter = &DatabaseClient{}
+	_ database.Snapshot    = &snapshot{}
+	_ database.Batch       = &batch{}
+	_ database.Iterator    = &iterator{}
 )
 
 // DatabaseClient is an implementation of database that talks over RPC.
@@ -121,6 +123,21 @@ func (db *DatabaseClient) NewIteratorWithStartAndPrefix(start, prefix []byte) da
 	}
 }
 
+// NewSnapshot returns a snapshot of the remote database
+func (db *DatabaseClient) NewSnapshot() (database.Snapshot, error) {
+	resp, err := db.client.NewSnapshot(context.Background(), &rpcdbpb.NewSnapshotRequest{})
+	if err != nil {
+		return nil, err
+	}
+	if err := errCodeToError[resp.Err]; err != nil {
+		return nil, err
+	}
+	return &snapshot{
+		db: db,
+		id: resp.Id,
+	}, nil
+}
+
 // Compact attempts to optimize the space utilization in the provided range
 func (db *DatabaseClient) Compact(start, limit []byte) error {
 	resp, err := db.client.Compact(context.Background(), &rpcdbpb.CompactRequest{
@@ -252,6 +269,73 @@ func (b *batch) Replay(w database.KeyValueWriterDeleter) error {
 
 func (b *batch) Inner() database.Batch { return b }
 
+type snapshot struct {
+	db *DatabaseClient
+	id uint64
+}
+
+// Has attempts to return if the snapshot has a key with the provided value.
+func (s *snapshot) Has(key []byte) (bool, error) {
+	resp, err := s.db.client.SnapshotHas(context.Background(), &rpcdbpb.SnapshotHasRequest{
+		SnapshotId: s.id,
+		Key:        key,
+	})
+	if err != nil {
+		return false, err
+	}
+	return resp.Has, errCodeToError[resp.Err]
+}
+
+// Get attempts to return the value that was mapped to the key that was
+// provided when the snapshot was taken
+func (s *snapshot) Get(key []byte) ([]byte, error) {
+	resp, err := s.db.client.SnapshotGet(context.Background(), &rpcdbpb.SnapshotGetRequest{
+		SnapshotId: s.id,
+		Key:        key,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Value, errCodeToError[resp.Err]
+}
+
+func (s *snapshot) NewIterator() database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(nil, nil)
+}
+
+func (s *snapshot) NewIteratorWithStart(start []byte) database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(start, nil)
+}
+
+func (s *snapshot) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(nil, prefix)
+}
+
+// NewIteratorWithStartAndPrefix returns a new iterator over the snapshot
+func (s *snapshot) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	resp, err := s.db.client.SnapshotNewIteratorWithStartAndPrefix(context.Background(), &rpcdbpb.SnapshotNewIteratorWithStartAndPrefixRequest{
+		SnapshotId: s.id,
+		Start:      start,
+		Prefix:     prefix,
+	})
+	if err != nil {
+		return &nodb.Iterator{Err: err}
+	}
+	return &iterator{
+		db: s.db,
+		id: resp.Id,
+	}
+}
+
+// Release frees any resources held by the snapshot
+func (s *snapshot) Release() {
+	// Any error here would be reported by subsequent calls on the snapshot, so
+	// it is safe to drop.
+	_, _ = s.db.client.SnapshotRelease(context.Background(), &rpcdbpb.SnapshotReleaseRequest{
+		SnapshotId: s.id,
+	})
+}
+
 type iterator struct {
 	db *DatabaseClient
 	id uint64
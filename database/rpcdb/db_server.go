@@ -12,6 +12,7 @@ import (
 	"google.golang.org/protobuf/types/known/emptypb"
 
 	"github.com/dim4egster/qmallgo/database"
+	"github.com/dim4egster/qmallgo/database/nodb"
 
 	rpcdbpb "github.com/dim4egster/qmallgo/proto/pb/rpcdb"
 )
@@ -39,6 +40,12 @@ type DatabaseServer struct {
 	iteratorLock   sync.RWMutex
 	nextIteratorID uint64
 	iterators      map[uint64]database.Iterator
+
+	// snapshotLock protects [nextSnapshotID] and [snapshots] from concurrent
+	// modifications.
+	snapshotLock   sync.RWMutex
+	nextSnapshotID uint64
+	snapshots      map[uint64]database.Snapshot
 }
 
 // NewServer returns a database instance that is managed remotely
@@ -47,6 +54,7 @@ func NewServer(db database.Database) *DatabaseServer {
 		db:        db,
 		batches:   make(map[int64]database.Batch),
 		iterators: make(map[uint64]database.Iterator),
+		snapshots: make(map[uint64]database.Snapshot),
 	}
 }
 
@@ -152,14 +160,17 @@ func (db *DatabaseServer) WriteBatch(_ context.Context, req *rpcdbpb.WriteBatchR
 // ID
 func (db *DatabaseServer) NewIteratorWithStartAndPrefix(_ context.Context, req *rpcdbpb.NewIteratorWithStartAndPrefixRequest) (*rpcdbpb.NewIteratorWithStartAndPrefixResponse, error) {
 	it := db.db.NewIteratorWithStartAndPrefix(req.Start, req.Prefix)
+	return &rpcdbpb.NewIteratorWithStartAndPrefixResponse{Id: db.addIterator(it)}, nil
+}
 
+func (db *DatabaseServer) addIterator(it database.Iterator) uint64 {
 	db.iteratorLock.Lock()
 	defer db.iteratorLock.Unlock()
 
 	id := db.nextIteratorID
 	db.iterators[id] = it
 	db.nextIteratorID++
-	return &rpcdbpb.NewIteratorWithStartAndPrefixResponse{Id: id}, nil
+	return id
 }
 
 // IteratorNext attempts to call next on the requested iterator
@@ -214,3 +225,87 @@ func (db *DatabaseServer) IteratorRelease(_ context.Context, req *rpcdbpb.Iterat
 	it.Release()
 	return &rpcdbpb.IteratorReleaseResponse{Err: errorToErrCode[err]}, errorToRPCError(err)
 }
+
+// NewSnapshot allocates a snapshot of the managed database and returns the
+// snapshot ID
+func (db *DatabaseServer) NewSnapshot(context.Context, *rpcdbpb.NewSnapshotRequest) (*rpcdbpb.NewSnapshotResponse, error) {
+	snapshotter, ok := db.db.(database.Snapshotter)
+	if !ok {
+		return &rpcdbpb.NewSnapshotResponse{
+			Err: errorToErrCode[database.ErrSnapshotNotSupported],
+		}, nil
+	}
+	snap, err := snapshotter.NewSnapshot()
+	if err != nil {
+		return &rpcdbpb.NewSnapshotResponse{Err: errorToErrCode[err]}, errorToRPCError(err)
+	}
+
+	db.snapshotLock.Lock()
+	defer db.snapshotLock.Unlock()
+
+	id := db.nextSnapshotID
+	db.snapshots[id] = snap
+	db.nextSnapshotID++
+	return &rpcdbpb.NewSnapshotResponse{Id: id}, nil
+}
+
+// SnapshotHas delegates the Has call to the requested snapshot. Released
+// snapshots are reported as closed.
+func (db *DatabaseServer) SnapshotHas(_ context.Context, req *rpcdbpb.SnapshotHasRequest) (*rpcdbpb.HasResponse, error) {
+	snap, exists := db.getSnapshot(req.SnapshotId)
+	if !exists {
+		return &rpcdbpb.HasResponse{Err: errorToErrCode[database.ErrClosed]}, nil
+	}
+	has, err := snap.Has(req.Key)
+	return &rpcdbpb.HasResponse{
+		Has: has,
+		Err: errorToErrCode[err],
+	}, errorToRPCError(err)
+}
+
+// SnapshotGet delegates the Get call to the requested snapshot. Released
+// snapshots are reported as closed.
+func (db *DatabaseServer) SnapshotGet(_ context.Context, req *rpcdbpb.SnapshotGetRequest) (*rpcdbpb.GetResponse, error) {
+	snap, exists := db.getSnapshot(req.SnapshotId)
+	if !exists {
+		return &rpcdbpb.GetResponse{Err: errorToErrCode[database.ErrClosed]}, nil
+	}
+	value, err := snap.Get(req.Key)
+	return &rpcdbpb.GetResponse{
+		Value: value,
+		Err:   errorToErrCode[err],
+	}, errorToRPCError(err)
+}
+
+// SnapshotNewIteratorWithStartAndPrefix allocates an iterator over the
+// requested snapshot and returns the iterator ID
+func (db *DatabaseServer) SnapshotNewIteratorWithStartAndPrefix(_ context.Context, req *rpcdbpb.SnapshotNewIteratorWithStartAndPrefixRequest) (*rpcdbpb.NewIteratorWithStartAndPrefixResponse, error) {
+	var it database.Iterator
+	if snap, exists := db.getSnapshot(req.SnapshotId); exists {
+		it = snap.NewIteratorWithStartAndPrefix(req.Start, req.Prefix)
+	} else {
+		it = &nodb.Iterator{Err: database.ErrClosed}
+	}
+	return &rpcdbpb.NewIteratorWithStartAndPrefixResponse{Id: db.addIterator(it)}, nil
+}
+
+// SnapshotRelease releases the resources allocated to a snapshot
+func (db *DatabaseServer) SnapshotRelease(_ context.Context, req *rpcdbpb.SnapshotReleaseRequest) (*rpcdbpb.SnapshotReleaseResponse, error) {
+	db.snapshotLock.Lock()
+	snap, exists := db.snapshots[req.SnapshotId]
+	delete(db.snapshots, req.SnapshotId)
+	db.snapshotLock.Unlock()
+
+	if exists {
+		snap.Release()
+	}
+	return &rpcdbpb.SnapshotReleaseResponse{}, nil
+}
+
+func (db *DatabaseServer) getSnapshot(id uint64) (database.Snapshot, bool) {
+	db.snapshotLock.RLock()
+	defer db.snapshotLock.RUnlock()
+
+	snap, exists := db.snapshots[id]
+	return snap, exists
+}
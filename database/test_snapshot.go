@@ -0,0 +1,308 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// SnapshotTests is a list of all snapshot tests. The provided database must
+// implement [Snapshotter].
+var SnapshotTests = []func(t *testing.T, db Database){
+	TestSnapshotIsolation,
+	TestSnapshotIterator,
+	TestSnapshotIteratorStartPrefix,
+	TestSnapshotIteratorIsolation,
+	TestSnapshotMemorySafety,
+	TestSnapshotRelease,
+	TestSnapshotClosed,
+	TestNewSnapshotClosed,
+}
+
+func newSnapshot(t *testing.T, db Database) Snapshot {
+	snapshotter, ok := db.(Snapshotter)
+	require.True(t, ok, "database doesn't implement Snapshotter")
+
+	snap, err := snapshotter.NewSnapshot()
+	require.NoError(t, err)
+	return snap
+}
+
+// TestSnapshotIsolation tests to make sure that writes made after a snapshot
+// was taken aren't visible through the snapshot.
+func TestSnapshotIsolation(t *testing.T, db Database) {
+	require := require.New(t)
+
+	key1 := []byte("hello1")
+	value1 := []byte("world1")
+	key2 := []byte("hello2")
+	value2 := []byte("world2")
+	key3 := []byte("hello3")
+	value3 := []byte("world3")
+
+	require.NoError(db.Put(key1, value1))
+	require.NoError(db.Put(key2, value2))
+
+	snap := newSnapshot(t, db)
+	defer snap.Release()
+
+	require.NoError(db.Delete(key1))
+	require.NoError(db.Put(key2, value3))
+	require.NoError(db.Put(key3, value3))
+
+	has, err := snap.Has(key1)
+	require.NoError(err)
+	require.True(has)
+
+	v, err := snap.Get(key1)
+	require.NoError(err)
+	require.Equal(value1, v)
+
+	v, err = snap.Get(key2)
+	require.NoError(err)
+	require.Equal(value2, v)
+
+	has, err = snap.Has(key3)
+	require.NoError(err)
+	require.False(has)
+
+	_, err = snap.Get(key3)
+	require.Equal(ErrNotFound, err)
+
+	// The database itself should observe the latest writes.
+	has, err = db.Has(key1)
+	require.NoError(err)
+	require.False(has)
+
+	v, err = db.Get(key2)
+	require.NoError(err)
+	require.Equal(value3, v)
+}
+
+// TestSnapshotIterator tests to make sure that iterating over a snapshot
+// returns the contents of the database at the time the snapshot was taken.
+func TestSnapshotIterator(t *testing.T, db Database) {
+	require := require.New(t)
+
+	key1 := []byte("hello1")
+	value1 := []byte("world1")
+	key2 := []byte("hello2")
+	value2 := []byte("world2")
+
+	require.NoError(db.Put(key1, value1))
+	require.NoError(db.Put(key2, value2))
+
+	snap := newSnapshot(t, db)
+	defer snap.Release()
+
+	require.NoError(db.Delete(key1))
+	require.NoError(db.Put([]byte("hello0"), value1))
+
+	iterator := snap.NewIterator()
+	defer iterator.Release()
+
+	require.True(iterator.Next())
+	require.Equal(key1, iterator.Key())
+	require.Equal(value1, iterator.Value())
+
+	require.True(iterator.Next())
+	require.Equal(key2, iterator.Key())
+	require.Equal(value2, iterator.Value())
+
+	require.False(iterator.Next())
+	require.Nil(iterator.Key())
+	require.Nil(iterator.Value())
+	require.NoError(iterator.Error())
+}
+
+// TestSnapshotIteratorStartPrefix tests to make sure that iterators over a
+// snapshot respect the provided start and prefix.
+func TestSnapshotIteratorStartPrefix(t *testing.T, db Database) {
+	require := require.New(t)
+
+	key1 := []byte("hello1")
+	value1 := []byte("world1")
+	key2 := []byte("z")
+	value2 := []byte("world2")
+	key3 := []byte("hello3")
+	value3 := []byte("world3")
+
+	require.NoError(db.Put(key1, value1))
+	require.NoError(db.Put(key2, value2))
+	require.NoError(db.Put(key3, value3))
+
+	snap := newSnapshot(t, db)
+	defer snap.Release()
+
+	require.NoError(db.Put([]byte("hello2"), value2))
+
+	iterator := snap.NewIteratorWithStartAndPrefix(key1, []byte("h"))
+	defer iterator.Release()
+
+	require.True(iterator.Next())
+	require.Equal(key1, iterator.Key())
+	require.Equal(value1, iterator.Value())
+
+	require.True(iterator.Next())
+	require.Equal(key3, iterator.Key())
+	require.Equal(value3, iterator.Value())
+
+	require.False(iterator.Next())
+	require.NoError(iterator.Error())
+
+	startIterator := snap.NewIteratorWithStart(key3)
+	defer startIterator.Release()
+
+	require.True(startIterator.Next())
+	require.Equal(key3, startIterator.Key())
+
+	require.True(startIterator.Next())
+	require.Equal(key2, startIterator.Key())
+
+	require.False(startIterator.Next())
+	require.NoError(startIterator.Error())
+
+	prefixIterator := snap.NewIteratorWithPrefix([]byte("z"))
+	defer prefixIterator.Release()
+
+	require.True(prefixIterator.Next())
+	require.Equal(key2, prefixIterator.Key())
+
+	require.False(prefixIterator.Next())
+	require.NoError(prefixIterator.Error())
+}
+
+// TestSnapshotIteratorIsolation tests to make sure that writes made while
+// iterating over a snapshot aren't visible to the iterator.
+func TestSnapshotIteratorIsolation(t *testing.T, db Database) {
+	require := require.New(t)
+
+	key1 := []byte("hello1")
+	value1 := []byte("world1")
+	key2 := []byte("hello2")
+	value2 := []byte("world2")
+
+	require.NoError(db.Put(key1, value1))
+
+	snap := newSnapshot(t, db)
+	defer snap.Release()
+
+	iterator := snap.NewIterator()
+	defer iterator.Release()
+
+	require.True(iterator.Next())
+	require.Equal(key1, iterator.Key())
+
+	batch := db.NewBatch()
+	require.NoError(batch.Put(key2, value2))
+	require.NoError(batch.Delete(key1))
+	require.NoError(batch.Write())
+
+	require.False(iterator.Next())
+	require.NoError(iterator.Error())
+
+	v, err := snap.Get(key1)
+	require.NoError(err)
+	require.Equal(value1, v)
+}
+
+// TestSnapshotMemorySafety tests to make sure that values returned by a
+// snapshot can be modified without corrupting the snapshot.
+func TestSnapshotMemorySafety(t *testing.T, db Database) {
+	require := require.New(t)
+
+	key := []byte("hello")
+	value := []byte("world")
+
+	require.NoError(db.Put(key, value))
+
+	snap := newSnapshot(t, db)
+	defer snap.Release()
+
+	v, err := snap.Get(key)
+	require.NoError(err)
+	v[0] = 'x'
+
+	v, err = snap.Get(key)
+	require.NoError(err)
+	require.Equal(value, v)
+}
+
+// TestSnapshotRelease tests to make sure that a released snapshot can't be
+// read from.
+func TestSnapshotRelease(t *testing.T, db Database) {
+	require := require.New(t)
+
+	key := []byte("hello")
+	value := []byte("world")
+
+	require.NoError(db.Put(key, value))
+
+	snap := newSnapshot(t, db)
+	snap.Release()
+
+	_, err := snap.Has(key)
+	require.Equal(ErrClosed, err)
+
+	_, err = snap.Get(key)
+	require.Equal(ErrClosed, err)
+
+	iterator := snap.NewIterator()
+	defer iterator.Release()
+
+	require.False(iterator.Next())
+	require.Equal(ErrClosed, iterator.Error())
+
+	// Releasing a snapshot multiple times should not panic.
+	snap.Release()
+
+	// The database should be unaffected.
+	v, err := db.Get(key)
+	require.NoError(err)
+	require.Equal(value, v)
+}
+
+// TestSnapshotClosed tests to make sure that a snapshot can't be read from
+// after its database has been closed.
+func TestSnapshotClosed(t *testing.T, db Database) {
+	require := require.New(t)
+
+	key := []byte("hello")
+	value := []byte("world")
+
+	require.NoError(db.Put(key, value))
+
+	snap := newSnapshot(t, db)
+	defer snap.Release()
+
+	require.NoError(db.Close())
+
+	_, err := snap.Has(key)
+	require.Equal(ErrClosed, err)
+
+	_, err = snap.Get(key)
+	require.Equal(ErrClosed, err)
+
+	iterator := snap.NewIterator()
+	defer iterator.Release()
+
+	require.False(iterator.Next())
+	require.Equal(ErrClosed, iterator.Error())
+}
+
+// TestNewSnapshotClosed tests to make sure that a snapshot can't be taken of a
+// closed database.
+func TestNewSnapshotClosed(t *testing.T, db Database) {
+	require := require.New(t)
+
+	snapshotter, ok := db.(Snapshotter)
+	require.True(ok)
+
+	require.NoError(db.Close())
+
+	_, err := snapshotter.NewSnapshot()
+	require.Equal(ErrClosed, err)
+}
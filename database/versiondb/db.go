@@ -19,10 +19,12 @@ const (
 )
 
 var (
-	_ database.Database = &Database{}
-	_ Commitable        = &Database{}
-	_ database.Batch    = &batch{}
-	_ database.Iterator = &iterator{}
+	_ database.Database    = &Database{}
+	_ database.Snapshotter = &Database{}
+	_ database.Snapshot    = &snapshot{}
+	_ Commitable           = &Database{}
+	_ database.Batch       = &batch{}
+	_ database.Iterator    = &iterator{}
 )
 
 // Commitable defines the interface that specifies that something may be
@@ -128,11 +130,21 @@ func (db *Database) NewIteratorWithStartAndPrefix(start, prefix []byte) database
 	if db.mem == nil {
 		return &nodb.Iterator{Err: database.ErrClosed}
 	}
+	return db.newIterator(db.mem, db.db, start, prefix)
+}
 
+// newIterator returns an iterator that merges the pending changes in [mem]
+// with the contents of [base].
+func (db *Database) newIterator(
+	mem map[string]valueDelete,
+	base database.Iteratee,
+	start []byte,
+	prefix []byte,
+) database.Iterator {
 	startString := string(start)
 	prefixString := string(prefix)
-	keys := make([]string, 0, len(db.mem))
-	for key := range db.mem {
+	keys := make([]string, 0, len(mem))
+	for key := range mem {
 		if strings.HasPrefix(key, prefixString) && key >= startString {
 			keys = append(keys, key)
 		}
@@ -140,17 +152,48 @@ func (db *Database) NewIteratorWithStartAndPrefix(start, prefix []byte) database
 	sort.Strings(keys) // Keys need to be in sorted order
 	values := make([]valueDelete, len(keys))
 	for i, key := range keys {
-		values[i] = db.mem[key]
+		values[i] = mem[key]
 	}
 
 	return &iterator{
 		db:       db,
-		Iterator: db.db.NewIteratorWithStartAndPrefix(start, prefix),
+		Iterator: base.NewIteratorWithStartAndPrefix(start, prefix),
 		keys:     keys,
 		values:   values,
 	}
 }
 
+// NewSnapshot returns a snapshot of both the uncommitted changes and the
+// underlying database. If the underlying database doesn't support snapshots,
+// [database.ErrSnapshotNotSupported] is returned.
+func (db *Database) NewSnapshot() (database.Snapshot, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.mem == nil {
+		return nil, database.ErrClosed
+	}
+	snapshotter, ok := db.db.(database.Snapshotter)
+	if !ok {
+		return nil, database.ErrSnapshotNotSupported
+	}
+	snap, err := snapshotter.NewSnapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	// Values are never modified in place, so only the map needs to be copied.
+	mem := make(map[string]valueDelete, len(db.mem))
+	for key, value := range db.mem {
+		mem[key] = value
+	}
+	return &snapshot{
+		db:   db,
+		mem:  mem,
+		snap: snap,
+	}, nil
+}
+
 func (db *Database) Compact(start, limit []byte) error {
 	db.lock.Lock()
 	defer db.lock.Unlock()
@@ -350,6 +393,75 @@ func (b *batch) Replay(w database.KeyValueWriterDeleter) error {
 // Inner returns itself
 func (b *batch) Inner() database.Batch { return b }
 
+// snapshot is a point-in-time view of both the in memory database and a
+// snapshot of the underlying database.
+type snapshot struct {
+	db *Database
+	// lock protects [mem], which is set to nil once the snapshot is released.
+	lock sync.RWMutex
+	mem  map[string]valueDelete
+	snap database.Snapshot
+}
+
+func (s *snapshot) Has(key []byte) (bool, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.mem == nil || s.db.isClosed() {
+		return false, database.ErrClosed
+	}
+	if val, has := s.mem[string(key)]; has {
+		return !val.delete, nil
+	}
+	return s.snap.Has(key)
+}
+
+func (s *snapshot) Get(key []byte) ([]byte, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.mem == nil || s.db.isClosed() {
+		return nil, database.ErrClosed
+	}
+	if val, has := s.mem[string(key)]; has {
+		if val.delete {
+			return nil, database.ErrNotFound
+		}
+		return utils.CopyBytes(val.value), nil
+	}
+	return s.snap.Get(key)
+}
+
+func (s *snapshot) NewIterator() database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(nil, nil)
+}
+
+func (s *snapshot) NewIteratorWithStart(start []byte) database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(start, nil)
+}
+
+func (s *snapshot) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(nil, prefix)
+}
+
+func (s *snapshot) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.mem == nil || s.db.isClosed() {
+		return &nodb.Iterator{Err: database.ErrClosed}
+	}
+	return s.db.newIterator(s.mem, s.snap, start, prefix)
+}
+
+func (s *snapshot) Release() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.mem = nil
+	s.snap.Release()
+}
+
 // iterator walks over both the in memory database and the underlying database
 // at the same time.
 type iterator struct {
@@ -7,6 +7,8 @@ import (
 	"bytes"
 	"testing"
 
+	"github.com/stretchr/testify/require"
+
 	"github.com/dim4egster/qmallgo/database"
 	"github.com/dim4egster/qmallgo/database/memdb"
 )
@@ -18,6 +20,51 @@ func TestInterface(t *testing.T) {
 	}
 }
 
+func TestSnapshotInterface(t *testing.T) {
+	for _, test := range database.SnapshotTests {
+		baseDB := memdb.New()
+		test(t, New(baseDB))
+	}
+}
+
+func TestSnapshotCommitted(t *testing.T) {
+	require := require.New(t)
+
+	baseDB := memdb.New()
+	db := New(baseDB)
+
+	key1 := []byte("hello1")
+	value1 := []byte("world1")
+	key2 := []byte("hello2")
+	value2 := []byte("world2")
+
+	require.NoError(db.Put(key1, value1))
+	require.NoError(db.Commit())
+	require.NoError(db.Put(key2, value2))
+
+	snap, err := db.NewSnapshot()
+	require.NoError(err)
+	defer snap.Release()
+
+	require.NoError(db.Delete(key1))
+	require.NoError(db.Delete(key2))
+	require.NoError(db.Commit())
+
+	iterator := snap.NewIterator()
+	defer iterator.Release()
+
+	require.True(iterator.Next())
+	require.Equal(key1, iterator.Key())
+	require.Equal(value1, iterator.Value())
+
+	require.True(iterator.Next())
+	require.Equal(key2, iterator.Key())
+	require.Equal(value2, iterator.Value())
+
+	require.False(iterator.Next())
+	require.NoError(iterator.Error())
+}
+
 func TestIterate(t *testing.T) {
 	baseDB := memdb.New()
 	db := New(baseDB)
@@ -0,0 +1,194 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gindexer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/dim4egster/qmallgo/api/auth"
+	"github.com/dim4egster/qmallgo/database"
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/indexer"
+	"github.com/dim4egster/qmallgo/vms/rpcchainvm/grpcutils"
+
+	indexpb "github.com/dim4egster/qmallgo/proto/pb/index"
+)
+
+var (
+	errInvalidIndex = errors.New("invalid index")
+	errUnknownIndex = errors.New("unknown index")
+
+	_ indexpb.IndexServer = &Server{}
+)
+
+// Server serves the index API over gRPC.
+type Server struct {
+	indexpb.UnsafeIndexServer
+	getIndex func(index string) (indexer.Index, bool)
+}
+
+// NewServer returns a gRPC server that serves requests to the index [index],
+// e.g. "X/tx", with the index returned by [getIndex].
+func NewServer(getIndex func(index string) (indexer.Index, bool)) *Server {
+	return &Server{
+		getIndex: getIndex,
+	}
+}
+
+func (s *Server) GetLastAccepted(ctx context.Context, req *indexpb.GetLastAcceptedRequest) (*indexpb.GetLastAcceptedResponse, error) {
+	index, err := s.index(ctx, req.Index)
+	if err != nil {
+		return nil, err
+	}
+	container, err := index.GetLastAccepted()
+	if err != nil {
+		return nil, err
+	}
+	return &indexpb.GetLastAcceptedResponse{
+		Container: newContainer(container),
+	}, nil
+}
+
+func (s *Server) GetContainerByIndex(ctx context.Context, req *indexpb.GetContainerByIndexRequest) (*indexpb.GetContainerByIndexResponse, error) {
+	index, err := s.index(ctx, req.Index)
+	if err != nil {
+		return nil, err
+	}
+	container, err := index.GetContainerByIndex(req.ContainerIndex)
+	if err != nil {
+		return nil, err
+	}
+	return &indexpb.GetContainerByIndexResponse{
+		Container: newContainer(container),
+	}, nil
+}
+
+func (s *Server) GetContainerByID(ctx context.Context, req *indexpb.GetContainerByIDRequest) (*indexpb.GetContainerByIDResponse, error) {
+	index, err := s.index(ctx, req.Index)
+	if err != nil {
+		return nil, err
+	}
+	containerID, err := ids.ToID(req.ContainerId)
+	if err != nil {
+		return nil, err
+	}
+	container, err := index.GetContainerByID(containerID)
+	if err != nil {
+		return nil, err
+	}
+	return &indexpb.GetContainerByIDResponse{
+		Container: newContainer(container),
+	}, nil
+}
+
+func (s *Server) GetIndex(ctx context.Context, req *indexpb.GetIndexRequest) (*indexpb.GetIndexResponse, error) {
+	index, err := s.index(ctx, req.Index)
+	if err != nil {
+		return nil, err
+	}
+	containerID, err := ids.ToID(req.ContainerId)
+	if err != nil {
+		return nil, err
+	}
+	containerIndex, err := index.GetIndex(containerID)
+	if err != nil {
+		return nil, err
+	}
+	return &indexpb.GetIndexResponse{
+		ContainerIndex: containerIndex,
+	}, nil
+}
+
+func (s *Server) IsAccepted(ctx context.Context, req *indexpb.IsAcceptedRequest) (*indexpb.IsAcceptedResponse, error) {
+	index, err := s.index(ctx, req.Index)
+	if err != nil {
+		return nil, err
+	}
+	containerID, err := ids.ToID(req.ContainerId)
+	if err != nil {
+		return nil, err
+	}
+	_, err = index.GetIndex(containerID)
+	if err != nil && err != database.ErrNotFound {
+		return nil, err
+	}
+	return &indexpb.IsAcceptedResponse{
+		IsAccepted: err == nil,
+	}, nil
+}
+
+func (s *Server) StreamContainerRange(req *indexpb.StreamContainerRangeRequest, stream indexpb.Index_StreamContainerRangeServer) error {
+	index, err := s.index(stream.Context(), req.Index)
+	if err != nil {
+		return err
+	}
+
+	lastAccepted, err := index.GetLastAccepted()
+	if err != nil {
+		return err
+	}
+	lastAcceptedIndex, err := index.GetIndex(lastAccepted.ID)
+	if err != nil {
+		return err
+	}
+
+	// [end] is the index after the last container to send.
+	end := lastAcceptedIndex + 1
+	if req.NumToFetch != 0 && req.StartIndex+req.NumToFetch < end {
+		end = req.StartIndex + req.NumToFetch
+	}
+	for next := req.StartIndex; next < end; {
+		numToFetch := end - next
+		if numToFetch > indexer.MaxFetchedByRange {
+			numToFetch = indexer.MaxFetchedByRange
+		}
+		containers, err := index.GetContainerRange(next, numToFetch)
+		if err != nil {
+			return err
+		}
+		if len(containers) == 0 {
+			return nil
+		}
+		for _, container := range containers {
+			if err := stream.Send(newContainer(container)); err != nil {
+				return err
+			}
+		}
+		next += uint64(len(containers))
+	}
+	return nil
+}
+
+// index returns the index named [name] after checking that the request of
+// [ctx] may access its endpoint.
+func (s *Server) index(ctx context.Context, name string) (indexer.Index, error) {
+	if name == "" || path.Clean(name) != name || strings.HasPrefix(name, "/") || strings.Contains(name, "..") {
+		return nil, fmt.Errorf("%w: %q", errInvalidIndex, name)
+	}
+	endpoint := "/ext/index/" + name
+	if !auth.AllowsEndpoint(ctx, endpoint) {
+		return nil, status.Errorf(codes.PermissionDenied, "the provided auth token does not allow access to %s", endpoint)
+	}
+	index, ok := s.getIndex(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", errUnknownIndex, name)
+	}
+	return index, nil
+}
+
+func newContainer(container indexer.Container) *indexpb.Container {
+	return &indexpb.Container{
+		Id:        container.ID[:],
+		Bytes:     container.Bytes,
+		Timestamp: grpcutils.TimestampFromTime(time.Unix(0, container.Timestamp)),
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gindexer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/dim4egster/qmallgo/database"
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/indexer"
+	"github.com/dim4egster/qmallgo/snow"
+	"github.com/dim4egster/qmallgo/vms/rpcchainvm/grpcutils"
+
+	indexpb "github.com/dim4egster/qmallgo/proto/pb/index"
+)
+
+const bufSize = 1024 * 1024
+
+var _ indexer.Index = &testIndex{}
+
+// testIndex is an index of [containers]
+type testIndex struct {
+	containers   []indexer.Container
+	rangeQueries int
+}
+
+func (*testIndex) Accept(*snow.ConsensusContext, ids.ID, []byte) error {
+	return nil
+}
+
+func (i *testIndex) GetContainerRange(startIndex uint64, numToFetch uint64) ([]indexer.Container, error) {
+	i.rangeQueries++
+	if numToFetch > indexer.MaxFetchedByRange {
+		return nil, errors.New("too many containers requested")
+	}
+	end := startIndex + numToFetch
+	if end > uint64(len(i.containers)) {
+		end = uint64(len(i.containers))
+	}
+	return i.containers[startIndex:end], nil
+}
+
+func (i *testIndex) GetContainerByIndex(index uint64) (indexer.Container, error) {
+	return i.containers[index], nil
+}
+
+func (i *testIndex) GetLastAccepted() (indexer.Container, error) {
+	return i.containers[len(i.containers)-1], nil
+}
+
+func (i *testIndex) GetIndex(containerID ids.ID) (uint64, error) {
+	for index, container := range i.containers {
+		if container.ID == containerID {
+			return uint64(index), nil
+		}
+	}
+	return 0, database.ErrNotFound
+}
+
+func (i *testIndex) GetContainerByID(containerID ids.ID) (indexer.Container, error) {
+	index, err := i.GetIndex(containerID)
+	if err != nil {
+		return indexer.Container{}, err
+	}
+	return i.containers[index], nil
+}
+
+func (*testIndex) ContainersFrom(uint64, uint64) ([]indexer.Container, <-chan struct{}, error) {
+	return nil, nil, errors.New("not implemented")
+}
+
+func (i *testIndex) NextIndex() uint64 {
+	return uint64(len(i.containers))
+}
+
+func (*testIndex) Close() error {
+	return nil
+}
+
+func newTestIndexClient(t *testing.T, index indexer.Index) indexpb.IndexClient {
+	listener := bufconn.Listen(bufSize)
+	serverCloser := grpcutils.ServerCloser{}
+	serverFunc := func(opts []grpc.ServerOption) *grpc.Server {
+		server := grpc.NewServer(opts...)
+		indexpb.RegisterIndexServer(server, NewServer(func(name string) (indexer.Index, bool) {
+			return index, name == "X/tx"
+		}))
+		serverCloser.Add(server)
+		return server
+	}
+	go grpcutils.Serve(listener, serverFunc)
+
+	dialer := grpc.WithContextDialer(
+		func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	)
+	dopts := grpcutils.DefaultDialOptions
+	dopts = append(dopts, dialer)
+	conn, err := grpcutils.Dial("", dopts...)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		serverCloser.Stop()
+		_ = conn.Close()
+		_ = listener.Close()
+	})
+	return indexpb.NewIndexClient(conn)
+}
+
+func TestStreamContainerRange(t *testing.T) {
+	index := &testIndex{
+		containers: make([]indexer.Container, 2*indexer.MaxFetchedByRange+10),
+	}
+	for i := range index.containers {
+		index.containers[i] = indexer.Container{
+			ID:        ids.GenerateTestID(),
+			Bytes:     []byte{byte(i)},
+			Timestamp: int64(i),
+		}
+	}
+	indexClient := newTestIndexClient(t, index)
+
+	tests := []struct {
+		name         string
+		startIndex   uint64
+		numToFetch   uint64
+		expectedEnd  uint64
+		rangeQueries int
+	}{
+		{
+			name:         "all containers",
+			startIndex:   0,
+			numToFetch:   0,
+			expectedEnd:  uint64(len(index.containers)),
+			rangeQueries: 3,
+		},
+		{
+			name:         "bounded range",
+			startIndex:   5,
+			numToFetch:   indexer.MaxFetchedByRange + 1,
+			expectedEnd:  indexer.MaxFetchedByRange + 6,
+			rangeQueries: 2,
+		},
+		{
+			name:         "range past the last accepted container",
+			startIndex:   uint64(len(index.containers)) - 2,
+			numToFetch:   10,
+			expectedEnd:  uint64(len(index.containers)),
+			rangeQueries: 1,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			index.rangeQueries = 0
+			stream, err := indexClient.StreamContainerRange(context.Background(), &indexpb.StreamContainerRangeRequest{
+				Index:      "X/tx",
+				StartIndex: test.startIndex,
+				NumToFetch: test.numToFetch,
+			})
+			require.NoError(err)
+
+			next := test.startIndex
+			for {
+				container, err := stream.Recv()
+				if err == io.EOF {
+					break
+				}
+				require.NoError(err)
+				require.Equal(index.containers[next].ID[:], container.Id)
+				next++
+			}
+			require.Equal(test.expectedEnd, next)
+			require.Equal(test.rangeQueries, index.rangeQueries)
+		})
+	}
+}
+
+func TestInvalidIndex(t *testing.T) {
+	indexClient := newTestIndexClient(t, &testIndex{})
+	for _, index := range []string{"", "/X/tx", "X/../../info", "X//tx"} {
+		_, err := indexClient.IsAccepted(context.Background(), &indexpb.IsAcceptedRequest{
+			Index:       index,
+			ContainerId: ids.Empty[:],
+		})
+		require.ErrorContains(t, err, errInvalidIndex.Error())
+	}
+
+	_, err := indexClient.IsAccepted(context.Background(), &indexpb.IsAcceptedRequest{
+		Index:       "P/block",
+		ContainerId: ids.Empty[:],
+	})
+	require.ErrorContains(t, err, errUnknownIndex.Error())
+}
@@ -36,6 +36,7 @@ var (
 	containerToIDPrefix    = []byte{0x02}
 	errNoneAccepted        = errors.New("no containers have been accepted")
 	errNumToFetchZero      = fmt.Errorf("numToFetch must be in [1,%d]", MaxFetchedByRange)
+	errIndexClosed         = errors.New("index closed")
 
 	_ Index = &index{}
 )
@@ -51,6 +52,13 @@ type Index interface {
 	GetLastAccepted() (Container, error)
 	GetIndex(id ids.ID) (uint64, error)
 	GetContainerByID(id ids.ID) (Container, error)
+	// ContainersFrom returns up to [maxToFetch] containers starting at
+	// [startIndex]. If no container has been accepted at [startIndex] yet, no
+	// containers are returned and the returned channel is closed once another
+	// container is accepted.
+	ContainersFrom(startIndex, maxToFetch uint64) ([]Container, <-chan struct{}, error)
+	// NextIndex returns the index that the next accepted container will have.
+	NextIndex() uint64
 	io.Closer
 }
 
@@ -70,6 +78,11 @@ type index struct {
 	// Container ID --> Index
 	containerToIndex database.Database
 	log              logging.Logger
+
+	// accepted is closed, and replaced, whenever a container is accepted or
+	// the index is closed
+	accepted chan struct{}
+	closed   bool
 }
 
 // Returns a new, thread-safe Index.
@@ -79,7 +92,7 @@ func newIndex(
 	log logging.Logger,
 	codec codec.Manager,
 	clock mockable.Clock,
-) (Index, error) {
+) (*index, error) {
 	vDB := versiondb.New(baseDB)
 	indexToContainer := prefixdb.New(indexToContainerPrefix, vDB)
 	containerToIndex := prefixdb.New(containerToIDPrefix, vDB)
@@ -92,6 +105,7 @@ func newIndex(
 		indexToContainer: indexToContainer,
 		containerToIndex: containerToIndex,
 		log:              log,
+		accepted:         make(chan struct{}),
 	}
 
 	// Get next accepted index from db
@@ -115,6 +129,14 @@ func newIndex(
 
 // Close this index
 func (i *index) Close() error {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	if !i.closed {
+		i.closed = true
+		close(i.accepted)
+	}
+
 	errs := wrappers.Errs{}
 	errs.Add(
 		i.indexToContainer.Close(),
@@ -177,7 +199,21 @@ func (i *index) Accept(ctx *snow.ConsensusContext, containerID ids.ID, container
 	}
 
 	// Atomically commit [i.vDB], [i.indexToContainer], [i.containerToIndex] to [i.baseDB]
-	return i.vDB.Commit()
+	if err := i.vDB.Commit(); err != nil {
+		return err
+	}
+
+	// Notify any subscribers that a new container is available
+	close(i.accepted)
+	i.accepted = make(chan struct{})
+	return nil
+}
+
+func (i *index) NextIndex() uint64 {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	return i.nextAcceptedIndex
 }
 
 // Returns the ID of the [index]th accepted container and the container itself.
@@ -258,6 +294,33 @@ func (i *index) GetContainerRange(startIndex, numToFetch uint64) ([]Container, e
 	return containers, nil
 }
 
+// ContainersFrom returns the containers at indices [startIndex],
+// [startIndex+1], ..., up to [maxToFetch] containers. If no container has been
+// accepted at [startIndex] yet, no containers are returned. In that case, the
+// returned channel is closed once another container is accepted.
+func (i *index) ContainersFrom(startIndex, maxToFetch uint64) ([]Container, <-chan struct{}, error) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	if i.closed {
+		return nil, nil, errIndexClosed
+	}
+	if startIndex >= i.nextAcceptedIndex {
+		return nil, i.accepted, nil
+	}
+
+	lastIndex := math.Min64(startIndex+maxToFetch-1, i.nextAcceptedIndex-1)
+	containers := make([]Container, 0, lastIndex-startIndex+1)
+	for j := startIndex; j <= lastIndex; j++ {
+		container, err := i.getContainerByIndex(j)
+		if err != nil {
+			return nil, nil, fmt.Errorf("couldn't get container at index %d: %w", j, err)
+		}
+		containers = append(containers, container)
+	}
+	return containers, i.accepted, nil
+}
+
 // Returns database.ErrNotFound if the container is not indexed as accepted
 func (i *index) GetIndex(id ids.ID) (uint64, error) {
 	i.lock.RLock()
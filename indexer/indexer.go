@@ -20,6 +20,7 @@ import (
 	"github.com/dim4egster/qmallgo/database"
 	"github.com/dim4egster/qmallgo/database/prefixdb"
 	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/pubsub"
 	"github.com/dim4egster/qmallgo/snow"
 	"github.com/dim4egster/qmallgo/snow/engine/avalanche"
 	"github.com/dim4egster/qmallgo/snow/engine/common"
@@ -35,6 +36,8 @@ import (
 const (
 	indexNamePrefix = "index-"
 	codecVersion    = uint16(0)
+	// Suffix of the websocket endpoint that streams accepted containers
+	subscribeEndpointSuffix = "/subscribe"
 	// Max size, in bytes, of something serialized by this indexer
 	// Assumes no containers are larger than math.MaxUint32
 	// wrappers.IntLen accounts for the size of the container bytes
@@ -73,6 +76,15 @@ type Config struct {
 // Indexer is threadsafe.
 type Indexer interface {
 	chains.Registrant
+
+	// GetConsensusIndex returns the index of the containers of [chainID] that
+	// are accepted by the consensus acceptor group, if [chainID] is indexed.
+	GetConsensusIndex(chainID ids.ID) (Index, bool)
+
+	// GetDecisionsIndex returns the index of the containers of [chainID] that
+	// are accepted by the decision acceptor group, if [chainID] is indexed.
+	GetDecisionsIndex(chainID ids.ID) (Index, bool)
+
 	// Close will do nothing and return nil after the first call
 	io.Closer
 }
@@ -321,6 +333,28 @@ func (i *indexer) RegisterChain(name string, engine common.Engine) {
 	}
 }
 
+func (i *indexer) GetConsensusIndex(chainID ids.ID) (Index, bool) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	if index, ok := i.blockIndices[chainID]; ok {
+		return index, true
+	}
+	index, ok := i.vtxIndices[chainID]
+	return index, ok
+}
+
+func (i *indexer) GetDecisionsIndex(chainID ids.ID) (Index, bool) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	if index, ok := i.blockIndices[chainID]; ok {
+		return index, true
+	}
+	index, ok := i.txIndices[chainID]
+	return index, ok
+}
+
 func (i *indexer) registerChainHelper(
 	chainID ids.ID,
 	prefixEnd byte,
@@ -357,6 +391,14 @@ func (i *indexer) registerChainHelper(
 		_ = index.Close()
 		return nil, err
 	}
+
+	// Create a websocket endpoint to stream newly accepted containers
+	subscriptionServer := pubsub.NewWithHandler(i.log, newSubscriptionHandler(index, i.log))
+	subscriptionHandler := &common.HTTPHandler{LockOptions: common.NoLock, Handler: subscriptionServer}
+	if err := i.pathAdder.AddRoute(subscriptionHandler, &sync.RWMutex{}, "index/"+name, "/"+endpoint+subscribeEndpointSuffix); err != nil {
+		_ = index.Close()
+		return nil, err
+	}
 	return index, nil
 }
 
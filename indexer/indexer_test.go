@@ -174,9 +174,11 @@ func TestIndexer(t *testing.T) {
 	require.NoError(err)
 	require.True(previouslyIndexed)
 	server := config.APIServer.(*apiServerMock)
-	require.EqualValues(1, server.timesCalled)
+	require.EqualValues(2, server.timesCalled) // block index and its subscription endpoint
 	require.EqualValues("index/chain1", server.bases[0])
 	require.EqualValues("/block", server.endpoints[0])
+	require.EqualValues("index/chain1", server.bases[1])
+	require.EqualValues("/block/subscribe", server.endpoints[1])
 	require.Len(idxr.blockIndices, 1)
 	require.Len(idxr.txIndices, 0)
 	require.Len(idxr.vtxIndices, 0)
@@ -274,10 +276,12 @@ func TestIndexer(t *testing.T) {
 	idxr.RegisterChain("chain2", dagEngine)
 	require.NoError(err)
 	server = config.APIServer.(*apiServerMock)
-	require.EqualValues(3, server.timesCalled) // block index, vtx index, tx index
+	require.EqualValues(6, server.timesCalled) // block index, vtx index, tx index and their subscription endpoints
 	require.Contains(server.bases, "index/chain2")
 	require.Contains(server.endpoints, "/vtx")
+	require.Contains(server.endpoints, "/vtx/subscribe")
 	require.Contains(server.endpoints, "/tx")
+	require.Contains(server.endpoints, "/tx/subscribe")
 	require.Len(idxr.blockIndices, 1)
 	require.Len(idxr.txIndices, 1)
 	require.Len(idxr.vtxIndices, 1)
@@ -0,0 +1,136 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package indexer
+
+import (
+	stdjson "encoding/json"
+	"errors"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/dim4egster/qmallgo/pubsub"
+	"github.com/dim4egster/qmallgo/utils/formatting"
+	"github.com/dim4egster/qmallgo/utils/json"
+	"github.com/dim4egster/qmallgo/utils/logging"
+)
+
+var (
+	errMissingSubscribe  = errors.New("missing subscribe command")
+	errAlreadySubscribed = errors.New("connection is already subscribed")
+
+	_ pubsub.Handler = &subscriptionHandler{}
+)
+
+// SubscribeArgs are the arguments of a subscription to an index.
+type SubscribeArgs struct {
+	// StartIndex is the index of the first container to send. Containers that
+	// were accepted before the subscription was made are sent before any newly
+	// accepted containers, so a client that was disconnected can resume from
+	// the index after the last container it received.
+	StartIndex json.Uint64         `json:"startIndex"`
+	Encoding   formatting.Encoding `json:"encoding"`
+}
+
+// SubscribeCommand is sent by clients to subscribe to an index.
+type SubscribeCommand struct {
+	Subscribe *SubscribeArgs `json:"subscribe"`
+}
+
+// SubscriptionMessage is sent to subscribers for every accepted container, in
+// order of acceptance. If the subscription fails, a message containing only
+// [Error] is sent and the connection is closed.
+type SubscriptionMessage struct {
+	Container *FormattedContainer `json:"container,omitempty"`
+	Error     string              `json:"error,omitempty"`
+}
+
+// subscriptionHandler streams the containers of an index to websocket
+// subscribers.
+type subscriptionHandler struct {
+	index *index
+	log   logging.Logger
+
+	lock       sync.Mutex
+	subscribed map[pubsub.Conn]struct{}
+}
+
+func newSubscriptionHandler(index *index, log logging.Logger) *subscriptionHandler {
+	return &subscriptionHandler{
+		index:      index,
+		log:        log,
+		subscribed: make(map[pubsub.Conn]struct{}),
+	}
+}
+
+func (s *subscriptionHandler) HandleCommand(conn pubsub.Conn, cmdBytes []byte) error {
+	cmd := SubscribeCommand{}
+	if err := stdjson.Unmarshal(cmdBytes, &cmd); err != nil {
+		return err
+	}
+	if cmd.Subscribe == nil {
+		return errMissingSubscribe
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.subscribed[conn]; ok {
+		return errAlreadySubscribed
+	}
+	s.subscribed[conn] = struct{}{}
+
+	go s.stream(conn, *cmd.Subscribe)
+	return nil
+}
+
+// stream sends every container starting at [args.StartIndex] to [conn] until
+// [conn] or the index is closed.
+func (s *subscriptionHandler) stream(conn pubsub.Conn, args SubscribeArgs) {
+	defer func() {
+		s.lock.Lock()
+		delete(s.subscribed, conn)
+		s.lock.Unlock()
+	}()
+
+	nextIndex := uint64(args.StartIndex)
+	for {
+		containers, accepted, err := s.index.ContainersFrom(nextIndex, MaxFetchedByRange)
+		if err != nil {
+			s.log.Debug("closing index subscription",
+				zap.Uint64("nextIndex", nextIndex),
+				zap.Error(err),
+			)
+			conn.Send(&SubscriptionMessage{
+				Error: err.Error(),
+			})
+			return
+		}
+
+		// Wait for another container to be accepted if all the accepted
+		// containers have already been sent.
+		if len(containers) == 0 {
+			select {
+			case <-accepted:
+				continue
+			case <-conn.Done():
+				return
+			}
+		}
+
+		for _, container := range containers {
+			fc, err := newFormattedContainer(container, nextIndex, args.Encoding)
+			if err != nil {
+				conn.Send(&SubscriptionMessage{
+					Error: err.Error(),
+				})
+				return
+			}
+			if !conn.SendWait(&SubscriptionMessage{Container: &fc}) {
+				return
+			}
+			nextIndex++
+		}
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package indexer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/dim4egster/qmallgo/utils/formatting"
+	"github.com/dim4egster/qmallgo/utils/json"
+)
+
+// Time to wait before reconnecting to an index after the subscription was
+// interrupted.
+const resubscribeDelay = time.Second
+
+var errSubscriptionFailed = errors.New("subscription failed")
+
+// Subscribe calls [onContainer] with every container accepted by an index, in
+// order of acceptance, starting with the container at [startIndex].
+// Containers that were accepted before the subscription was made are sent
+// first, followed by containers as they are accepted.
+//
+// If the connection to the index is lost, Subscribe reconnects and resumes from
+// the container after the last one passed to [onContainer].
+//
+// Subscribe returns once [ctx] is done or [onContainer] returns an error.
+// [uri] is the websocket endpoint of the index. For example:
+//   - ws://1.2.3.4:4960/ext/index/C/block/subscribe
+//   - ws://1.2.3.4:4960/ext/index/X/tx/subscribe
+func Subscribe(
+	ctx context.Context,
+	uri string,
+	startIndex uint64,
+	onContainer func(index uint64, container Container) error,
+) error {
+	nextIndex := startIndex
+	for {
+		retry, err := subscribe(ctx, uri, &nextIndex, onContainer)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !retry {
+			return err
+		}
+
+		timer := time.NewTimer(resubscribeDelay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// subscribe streams containers from the index until the connection is
+// interrupted. [nextIndex] is updated after each container is handled.
+// Returns true if the subscription should be retried.
+func subscribe(
+	ctx context.Context,
+	uri string,
+	nextIndex *uint64,
+	onContainer func(index uint64, container Container) error,
+) (bool, error) {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, uri, nil)
+	if err != nil {
+		return true, err
+	}
+	defer conn.Close()
+
+	// Closing the connection once [ctx] is done unblocks any pending reads.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.Close()
+		case <-done:
+		}
+	}()
+
+	err = conn.WriteJSON(&SubscribeCommand{
+		Subscribe: &SubscribeArgs{
+			StartIndex: json.Uint64(*nextIndex),
+			Encoding:   formatting.Hex,
+		},
+	})
+	if err != nil {
+		return true, err
+	}
+
+	for {
+		var msg SubscriptionMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			return true, err
+		}
+		if msg.Error != "" {
+			return true, fmt.Errorf("%w: %s", errSubscriptionFailed, msg.Error)
+		}
+		if msg.Container == nil {
+			continue
+		}
+
+		fc := msg.Container
+		containerBytes, err := formatting.Decode(fc.Encoding, fc.Bytes)
+		if err != nil {
+			return true, fmt.Errorf("couldn't decode container %s: %w", fc.ID, err)
+		}
+		container := Container{
+			ID:        fc.ID,
+			Timestamp: fc.Timestamp.Unix(),
+			Bytes:     containerBytes,
+		}
+		if err := onContainer(uint64(fc.Index), container); err != nil {
+			return false, err
+		}
+		*nextIndex = uint64(fc.Index) + 1
+	}
+}
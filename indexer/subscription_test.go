@@ -0,0 +1,122 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package indexer
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dim4egster/qmallgo/codec"
+	"github.com/dim4egster/qmallgo/codec/linearcodec"
+	"github.com/dim4egster/qmallgo/database/memdb"
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/pubsub"
+	"github.com/dim4egster/qmallgo/snow"
+	"github.com/dim4egster/qmallgo/utils"
+	"github.com/dim4egster/qmallgo/utils/logging"
+	"github.com/dim4egster/qmallgo/utils/timer/mockable"
+)
+
+type indexedContainer struct {
+	index     uint64
+	container Container
+}
+
+func TestSubscribeBackfillThenLive(t *testing.T) {
+	require := require.New(t)
+
+	codec := codec.NewDefaultManager()
+	require.NoError(codec.RegisterCodec(codecVersion, linearcodec.NewDefault()))
+	idx, err := newIndex(memdb.New(), logging.NoLog{}, codec, mockable.Clock{})
+	require.NoError(err)
+	ctx := snow.DefaultConsensusContextTest()
+
+	containerIDs := make([]ids.ID, 4)
+	containerBytes := make([][]byte, 4)
+	for i := range containerIDs {
+		containerIDs[i] = ids.GenerateTestID()
+		containerBytes[i] = utils.RandomBytes(32)
+	}
+	for i := 0; i < 3; i++ {
+		require.NoError(idx.Accept(ctx, containerIDs[i], containerBytes[i]))
+	}
+
+	server := httptest.NewServer(pubsub.NewWithHandler(logging.NoLog{}, newSubscriptionHandler(idx, logging.NoLog{})))
+	defer server.Close()
+	uri := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	subscriptionCtx, cancel := context.WithCancel(context.Background())
+	received := make(chan indexedContainer, 4)
+	subscribeErr := make(chan error, 1)
+	go func() {
+		subscribeErr <- Subscribe(subscriptionCtx, uri, 1, func(index uint64, container Container) error {
+			received <- indexedContainer{
+				index:     index,
+				container: container,
+			}
+			return nil
+		})
+	}()
+
+	receive := func(expectedIndex uint64) {
+		select {
+		case got := <-received:
+			require.Equal(expectedIndex, got.index)
+			require.Equal(containerIDs[expectedIndex], got.container.ID)
+			require.Equal(containerBytes[expectedIndex], got.container.Bytes)
+		case <-time.After(5 * time.Second):
+			require.FailNow("timed out waiting for container", "index %d", expectedIndex)
+		}
+	}
+
+	// Containers that were accepted before subscribing should be sent first.
+	receive(1)
+	receive(2)
+
+	// Newly accepted containers should be sent as they are accepted.
+	require.NoError(idx.Accept(ctx, containerIDs[3], containerBytes[3]))
+	receive(3)
+
+	cancel()
+	require.ErrorIs(<-subscribeErr, context.Canceled)
+	require.NoError(idx.Close())
+}
+
+func TestContainersFromNotifiesOnAccept(t *testing.T) {
+	require := require.New(t)
+
+	codec := codec.NewDefaultManager()
+	require.NoError(codec.RegisterCodec(codecVersion, linearcodec.NewDefault()))
+	idx, err := newIndex(memdb.New(), logging.NoLog{}, codec, mockable.Clock{})
+	require.NoError(err)
+	ctx := snow.DefaultConsensusContextTest()
+
+	containers, accepted, err := idx.ContainersFrom(0, MaxFetchedByRange)
+	require.NoError(err)
+	require.Empty(containers)
+
+	select {
+	case <-accepted:
+		require.FailNow("notified before a container was accepted")
+	default:
+	}
+
+	containerID := ids.GenerateTestID()
+	require.NoError(idx.Accept(ctx, containerID, utils.RandomBytes(32)))
+	<-accepted
+
+	containers, _, err = idx.ContainersFrom(0, MaxFetchedByRange)
+	require.NoError(err)
+	require.Len(containers, 1)
+	require.Equal(containerID, containers[0].ID)
+
+	require.NoError(idx.Close())
+	_, _, err = idx.ContainersFrom(0, MaxFetchedByRange)
+	require.ErrorIs(err, errIndexClosed)
+}
@@ -7,9 +7,13 @@ import (
 	"fmt"
 	"path/filepath"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	"go.uber.org/zap"
 
 	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/indexer"
+	"github.com/dim4egster/qmallgo/ipcs/socket"
 	"github.com/dim4egster/qmallgo/snow"
 	"github.com/dim4egster/qmallgo/utils/logging"
 	"github.com/dim4egster/qmallgo/utils/wrappers"
@@ -24,10 +28,24 @@ const (
 	ipcDecisionsIdentifier = "decisions"
 )
 
+// Indices provides the indices that back the replay of the events of chains.
+type Indices interface {
+	// GetConsensusIndex returns the index of the containers of [chainID] that
+	// are accepted by the consensus acceptor group, if [chainID] is indexed.
+	GetConsensusIndex(chainID ids.ID) (indexer.Index, bool)
+
+	// GetDecisionsIndex returns the index of the containers of [chainID] that
+	// are accepted by the decision acceptor group, if [chainID] is indexed.
+	GetDecisionsIndex(chainID ids.ID) (indexer.Index, bool)
+}
+
 type context struct {
 	log       logging.Logger
 	networkID uint32
 	path      string
+	config    socket.Config
+	indices   Indices
+	metrics   *metrics
 }
 
 // ChainIPCs maintains IPCs for a set of chains
@@ -39,13 +57,32 @@ type ChainIPCs struct {
 }
 
 // NewChainIPCs creates a new *ChainIPCs that writes consensus and decision
-// events to IPC sockets
-func NewChainIPCs(log logging.Logger, path string, networkID uint32, consensusAcceptorGroup, decisionAcceptorGroup snow.AcceptorGroup, defaultChainIDs []ids.ID) (*ChainIPCs, error) {
+// events to IPC sockets. Readers are buffered according to [config]. Readers
+// can request the replay of the events of the chains indexed by [indices].
+func NewChainIPCs(
+	log logging.Logger,
+	path string,
+	networkID uint32,
+	consensusAcceptorGroup,
+	decisionAcceptorGroup snow.AcceptorGroup,
+	defaultChainIDs []ids.ID,
+	config socket.Config,
+	indices Indices,
+	registerer prometheus.Registerer,
+) (*ChainIPCs, error) {
+	metrics, err := newMetrics("ipcs", registerer)
+	if err != nil {
+		return nil, err
+	}
+
 	cipcs := &ChainIPCs{
 		context: context{
 			log:       log,
 			networkID: networkID,
 			path:      path,
+			config:    config,
+			indices:   indices,
+			metrics:   metrics,
 		},
 		chains:                 make(map[ids.ID]*EventSockets),
 		consensusAcceptorGroup: consensusAcceptorGroup,
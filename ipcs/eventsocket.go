@@ -5,17 +5,35 @@ package ipcs
 
 import (
 	"errors"
+	"fmt"
 	"os"
+	"sync"
 	"syscall"
 
+	"github.com/prometheus/client_golang/prometheus"
+
+	"go.uber.org/zap"
+
 	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/indexer"
 	"github.com/dim4egster/qmallgo/ipcs/socket"
 	"github.com/dim4egster/qmallgo/snow"
 	"github.com/dim4egster/qmallgo/utils/logging"
+	"github.com/dim4egster/qmallgo/utils/math"
+	"github.com/dim4egster/qmallgo/utils/timer/mockable"
 	"github.com/dim4egster/qmallgo/utils/wrappers"
 )
 
-var _ snow.Acceptor = &EventSockets{}
+var (
+	errUnexpectedFrame  = errors.New("unexpected frame")
+	errChainNotIndexed  = errors.New("chain isn't indexed")
+	errReplayInProgress = errors.New("replay already in progress")
+	errReplayFailed     = errors.New("replay failed")
+
+	_ snow.Acceptor  = &EventSockets{}
+	_ snow.Acceptor  = &eventSocket{}
+	_ socket.Handler = &eventSocket{}
+)
 
 // EventSockets is a set of named eventSockets
 type EventSockets struct {
@@ -25,12 +43,22 @@ type EventSockets struct {
 
 // newEventSockets creates a *ChainIPCs with both consensus and decisions IPCs
 func newEventSockets(ctx context, chainID ids.ID, consensusAcceptorGroup, decisionAcceptorGroup snow.AcceptorGroup) (*EventSockets, error) {
-	consensusIPC, err := newEventIPCSocket(ctx, chainID, ipcConsensusIdentifier, consensusAcceptorGroup)
+	getConsensusIndex, getDecisionsIndex := noIndex, noIndex
+	if ctx.indices != nil {
+		getConsensusIndex = func() (indexer.Index, bool) {
+			return ctx.indices.GetConsensusIndex(chainID)
+		}
+		getDecisionsIndex = func() (indexer.Index, bool) {
+			return ctx.indices.GetDecisionsIndex(chainID)
+		}
+	}
+
+	consensusIPC, err := newEventIPCSocket(ctx, chainID, ipcConsensusIdentifier, consensusAcceptorGroup, getConsensusIndex)
 	if err != nil {
 		return nil, err
 	}
 
-	decisionsIPC, err := newEventIPCSocket(ctx, chainID, ipcDecisionsIdentifier, decisionAcceptorGroup)
+	decisionsIPC, err := newEventIPCSocket(ctx, chainID, ipcDecisionsIdentifier, decisionAcceptorGroup, getDecisionsIndex)
 	if err != nil {
 		return nil, err
 	}
@@ -41,6 +69,8 @@ func newEventSockets(ctx context, chainID ids.ID, consensusAcceptorGroup, decisi
 	}, nil
 }
 
+func noIndex() (indexer.Index, bool) { return nil, false }
+
 // Accept delivers a message to the underlying eventSockets
 func (ipcs *EventSockets) Accept(ctx *snow.ConsensusContext, containerID ids.ID, container []byte) error {
 	if ipcs.consensusSocket != nil {
@@ -87,13 +117,45 @@ func (ipcs *EventSockets) DecisionsURL() string {
 type eventSocket struct {
 	url          string
 	log          logging.Logger
+	chainID      ids.ID
+	clock        mockable.Clock
 	socket       *socket.Socket
 	unregisterFn func() error
+
+	// getIndex returns the index that events are replayed from, if the chain
+	// is indexed
+	getIndex func() (indexer.Index, bool)
+	replayed prometheus.Counter
+
+	lock sync.Mutex
+	// nextSequence is the sequence number of the next event if the chain
+	// isn't indexed
+	nextSequence uint64
+	// published is one more than the sequence number of the last event that
+	// was sent
+	published uint64
+	readers   map[*socket.Conn]*reader
+}
+
+// reader is the state of a client of an eventSocket
+type reader struct {
+	// replaying is true while events are being replayed to the reader. Live
+	// events aren't sent to the reader while it is replaying.
+	replaying bool
+	// nextSequence is the sequence number of the next event to send to the
+	// reader
+	nextSequence uint64
 }
 
 // newEventIPCSocket creates a *eventSocket for the given chain and
 // EventDispatcher that writes to a local IPC socket
-func newEventIPCSocket(ctx context, chainID ids.ID, name string, acceptorGroup snow.AcceptorGroup) (*eventSocket, error) {
+func newEventIPCSocket(
+	ctx context,
+	chainID ids.ID,
+	name string,
+	acceptorGroup snow.AcceptorGroup,
+	getIndex func() (indexer.Index, bool),
+) (*eventSocket, error) {
 	var (
 		url     = ipcURL(ctx, chainID, name)
 		ipcName = ipcIdentifierPrefix + "-" + name
@@ -105,13 +167,17 @@ func newEventIPCSocket(ctx context, chainID ids.ID, name string, acceptorGroup s
 	}
 
 	eis := &eventSocket{
-		log:    ctx.log,
-		url:    url,
-		socket: socket.NewSocket(url, ctx.log),
+		log:     ctx.log,
+		url:     url,
+		chainID: chainID,
 		unregisterFn: func() error {
 			return acceptorGroup.DeregisterAcceptor(chainID, ipcName)
 		},
+		getIndex: getIndex,
+		replayed: ctx.metrics.replayedCounter(chainID, name),
+		readers:  make(map[*socket.Conn]*reader),
 	}
+	eis.socket = socket.NewSocketWithConfig(url, ctx.log, ctx.config, ctx.metrics.socketMetrics(chainID, name), eis)
 
 	if err := eis.socket.Listen(); err != nil {
 		if err := eis.socket.Close(); err != nil {
@@ -131,11 +197,179 @@ func newEventIPCSocket(ctx context, chainID ids.ID, name string, acceptorGroup s
 }
 
 // Accept delivers a message to the eventSocket
-func (eis *eventSocket) Accept(_ *snow.ConsensusContext, _ ids.ID, container []byte) error {
-	eis.socket.Send(container)
+func (eis *eventSocket) Accept(_ *snow.ConsensusContext, containerID ids.ID, container []byte) error {
+	frame := &Frame{
+		Type:        FrameEvent,
+		ChainID:     eis.chainID,
+		ContainerID: containerID,
+		Timestamp:   eis.clock.Time().UnixNano(),
+		Payload:     container,
+	}
+
+	eis.lock.Lock()
+	frame.Sequence = eis.sequence(containerID)
+	eis.published = frame.Sequence + 1
+	liveReaders := make([]*socket.Conn, 0, len(eis.readers))
+	for conn, r := range eis.readers {
+		// Skip readers that are replaying, and readers that were already sent
+		// this event by a replay.
+		if r.replaying || frame.Sequence < r.nextSequence {
+			continue
+		}
+		r.nextSequence = frame.Sequence + 1
+		liveReaders = append(liveReaders, conn)
+	}
+	eis.lock.Unlock()
+
+	// Frames are sent without holding the lock because sending to a slow
+	// reader may disconnect it.
+	frameBytes := frame.Bytes()
+	for _, conn := range liveReaders {
+		conn.Send(frameBytes)
+	}
 	return nil
 }
 
+// sequence returns the sequence number of the accepted [containerID].
+//
+// If the chain is indexed, the sequence number is the index of the container.
+// Acceptors are called one at a time, so the container has either already
+// been indexed or will be given the next index.
+//
+// Assumes [eis.lock] is held.
+func (eis *eventSocket) sequence(containerID ids.ID) uint64 {
+	if index, ok := eis.getIndex(); ok {
+		if i, err := index.GetIndex(containerID); err == nil {
+			return i
+		}
+		return index.NextIndex()
+	}
+
+	sequence := eis.nextSequence
+	eis.nextSequence++
+	return sequence
+}
+
+func (eis *eventSocket) Connected(conn *socket.Conn) {
+	eis.lock.Lock()
+	defer eis.lock.Unlock()
+
+	eis.readers[conn] = &reader{}
+}
+
+func (eis *eventSocket) Disconnected(conn *socket.Conn) {
+	eis.lock.Lock()
+	defer eis.lock.Unlock()
+
+	delete(eis.readers, conn)
+}
+
+// HandleMessage handles the replay requests sent by readers
+func (eis *eventSocket) HandleMessage(conn *socket.Conn, msg []byte) {
+	request, err := ParseFrame(msg)
+	if err != nil {
+		eis.sendError(conn, err)
+		return
+	}
+	if request.Type != FrameReplay {
+		eis.sendError(conn, fmt.Errorf("%w: %s", errUnexpectedFrame, request.Type))
+		return
+	}
+
+	index, ok := eis.getIndex()
+	if !ok {
+		eis.sendError(conn, errChainNotIndexed)
+		return
+	}
+
+	eis.lock.Lock()
+	r, ok := eis.readers[conn]
+	if !ok {
+		// The reader disconnected
+		eis.lock.Unlock()
+		return
+	}
+	if r.replaying {
+		eis.lock.Unlock()
+		eis.sendError(conn, errReplayInProgress)
+		return
+	}
+	r.replaying = true
+	r.nextSequence = request.Sequence
+	eis.lock.Unlock()
+
+	go eis.replay(conn, r, index)
+}
+
+// replay sends the indexed events to [conn] until [r] has caught up with the
+// live events.
+func (eis *eventSocket) replay(conn *socket.Conn, r *reader, index indexer.Index) {
+	for {
+		eis.lock.Lock()
+		nextSequence := r.nextSequence
+		// Containers indexed before they were published, including the ones
+		// indexed before this socket was opened, are replayed as well.
+		published := math.Max64(eis.published, index.NextIndex())
+		if nextSequence >= published {
+			// Every published event has been replayed, so the reader can
+			// receive live events from now on.
+			r.replaying = false
+			eis.lock.Unlock()
+			return
+		}
+		eis.lock.Unlock()
+
+		containers, accepted, err := index.ContainersFrom(nextSequence, math.Min64(published-nextSequence, indexer.MaxFetchedByRange))
+		if err != nil {
+			eis.log.Debug("failed to replay events",
+				zap.Stringer("chainID", eis.chainID),
+				zap.Uint64("sequence", nextSequence),
+				zap.Error(err),
+			)
+			eis.sendError(conn, fmt.Errorf("%w: %s", errReplayFailed, err))
+			_ = conn.Close()
+			return
+		}
+		if len(containers) == 0 {
+			// The last published event hasn't been indexed yet.
+			select {
+			case <-accepted:
+				continue
+			case <-conn.Done():
+				return
+			}
+		}
+
+		for i, container := range containers {
+			frame := &Frame{
+				Type:        FrameEvent,
+				Sequence:    nextSequence + uint64(i),
+				ChainID:     eis.chainID,
+				ContainerID: container.ID,
+				Timestamp:   container.Timestamp,
+				Payload:     container.Bytes,
+			}
+			if !conn.SendWait(frame.Bytes()) {
+				return
+			}
+			eis.replayed.Inc()
+		}
+
+		eis.lock.Lock()
+		r.nextSequence = nextSequence + uint64(len(containers))
+		eis.lock.Unlock()
+	}
+}
+
+func (eis *eventSocket) sendError(conn *socket.Conn, err error) {
+	frame := &Frame{
+		Type:    FrameError,
+		ChainID: eis.chainID,
+		Payload: []byte(err.Error()),
+	}
+	conn.Send(frame.Bytes())
+}
+
 // stop unregisters the event handler and closes the eventSocket
 func (eis *eventSocket) stop() error {
 	eis.log.Info("closing Chain IPC")
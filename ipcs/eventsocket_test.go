@@ -0,0 +1,167 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ipcs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dim4egster/qmallgo/database"
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/indexer"
+	"github.com/dim4egster/qmallgo/ipcs/socket"
+	"github.com/dim4egster/qmallgo/snow"
+	"github.com/dim4egster/qmallgo/utils/logging"
+)
+
+var _ indexer.Index = &testIndex{}
+
+// testIndex is an in-memory indexer.Index
+type testIndex struct {
+	indexer.Index
+
+	containers []indexer.Container
+	accepted   chan struct{}
+}
+
+func (i *testIndex) add(container indexer.Container) {
+	i.containers = append(i.containers, container)
+	close(i.accepted)
+	i.accepted = make(chan struct{})
+}
+
+func (i *testIndex) GetIndex(containerID ids.ID) (uint64, error) {
+	for index, container := range i.containers {
+		if container.ID == containerID {
+			return uint64(index), nil
+		}
+	}
+	return 0, database.ErrNotFound
+}
+
+func (i *testIndex) NextIndex() uint64 {
+	return uint64(len(i.containers))
+}
+
+func (i *testIndex) ContainersFrom(startIndex, maxToFetch uint64) ([]indexer.Container, <-chan struct{}, error) {
+	if startIndex >= uint64(len(i.containers)) {
+		return nil, i.accepted, nil
+	}
+	end := startIndex + maxToFetch
+	if end > uint64(len(i.containers)) {
+		end = uint64(len(i.containers))
+	}
+	return i.containers[startIndex:end], i.accepted, nil
+}
+
+func newTestEventSocket(t *testing.T, index indexer.Index) (*eventSocket, *Reader) {
+	require := require.New(t)
+
+	m, err := newMetrics("ipcs", prometheus.NewRegistry())
+	require.NoError(err)
+
+	ctx := context{
+		log:       logging.NoLog{},
+		networkID: 1,
+		path:      t.TempDir(),
+		config: socket.Config{
+			BufferSize: socket.DefaultBufferSize,
+			Policy:     socket.PolicyDrop,
+		},
+		metrics: m,
+	}
+	getIndex := noIndex
+	if index != nil {
+		getIndex = func() (indexer.Index, bool) { return index, true }
+	}
+	eis, err := newEventIPCSocket(ctx, ids.GenerateTestID(), ipcConsensusIdentifier, snow.NewAcceptorGroup(logging.NoLog{}), getIndex)
+	require.NoError(err)
+	t.Cleanup(func() { _ = eis.stop() })
+
+	r, err := Dial(eis.URL())
+	require.NoError(err)
+	t.Cleanup(func() { _ = r.Close() })
+
+	require.Eventually(func() bool {
+		eis.lock.Lock()
+		defer eis.lock.Unlock()
+		return len(eis.readers) == 1
+	}, time.Second, time.Millisecond)
+	return eis, r
+}
+
+func TestEventSocketLiveEvents(t *testing.T) {
+	require := require.New(t)
+
+	eis, r := newTestEventSocket(t, nil)
+
+	containerIDs := []ids.ID{ids.GenerateTestID(), ids.GenerateTestID()}
+	for _, containerID := range containerIDs {
+		require.NoError(eis.Accept(nil, containerID, containerID[:]))
+	}
+
+	for i, containerID := range containerIDs {
+		frame, err := r.Recv()
+		require.NoError(err)
+		require.Equal(FrameEvent, frame.Type)
+		require.Equal(uint64(i), frame.Sequence)
+		require.Equal(eis.chainID, frame.ChainID)
+		require.Equal(containerID, frame.ContainerID)
+		require.Equal(containerID[:], frame.Payload)
+	}
+
+	// The chain isn't indexed, so events can't be replayed.
+	require.NoError(r.RequestReplay(0))
+	frame, err := r.Recv()
+	require.NoError(err)
+	require.Equal(FrameError, frame.Type)
+	require.Equal(errChainNotIndexed.Error(), string(frame.Payload))
+}
+
+func TestEventSocketReplay(t *testing.T) {
+	require := require.New(t)
+
+	index := &testIndex{accepted: make(chan struct{})}
+	for i := 0; i < 3; i++ {
+		containerID := ids.GenerateTestID()
+		index.add(indexer.Container{
+			ID:        containerID,
+			Bytes:     containerID[:],
+			Timestamp: int64(i),
+		})
+	}
+	eis, r := newTestEventSocket(t, index)
+
+	require.NoError(r.RequestReplay(1))
+	for i := 1; i < 3; i++ {
+		frame, err := r.Recv()
+		require.NoError(err)
+		require.Equal(FrameEvent, frame.Type)
+		require.Equal(uint64(i), frame.Sequence)
+		require.Equal(index.containers[i].ID, frame.ContainerID)
+		require.Equal(index.containers[i].Timestamp, frame.Timestamp)
+	}
+
+	// Once the replay has caught up, live events are sent with the index of
+	// the container as their sequence number.
+	require.Eventually(func() bool {
+		eis.lock.Lock()
+		defer eis.lock.Unlock()
+		for _, reader := range eis.readers {
+			return !reader.replaying
+		}
+		return false
+	}, time.Second, time.Millisecond)
+
+	containerID := ids.GenerateTestID()
+	require.NoError(eis.Accept(nil, containerID, containerID[:]))
+	frame, err := r.Recv()
+	require.NoError(err)
+	require.Equal(uint64(3), frame.Sequence)
+	require.Equal(containerID, frame.ContainerID)
+}
@@ -0,0 +1,127 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ipcs
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/utils/hashing"
+	"github.com/dim4egster/qmallgo/utils/wrappers"
+)
+
+// FrameVersion is the version of the protocol spoken over event sockets.
+const FrameVersion byte = 1
+
+// frameHeaderLen is the length of a frame without its payload.
+const frameHeaderLen = 2*wrappers.ByteLen + 2*wrappers.LongLen + 2*hashing.HashLen + wrappers.IntLen
+
+var (
+	errUnknownFrameVersion = errors.New("unknown frame version")
+	errUnknownFrameType    = errors.New("unknown frame type")
+)
+
+// FrameType is the type of the message carried by a Frame.
+type FrameType byte
+
+const (
+	// FrameEvent carries a container accepted by the chain. Sent by the node.
+	FrameEvent FrameType = iota + 1
+	// FrameReplay requests that the events starting at the frame's sequence
+	// number are sent again. Sent by readers.
+	FrameReplay
+	// FrameError reports why a request of a reader failed. The payload is the
+	// error message. Sent by the node.
+	FrameError
+)
+
+func (t FrameType) String() string {
+	switch t {
+	case FrameEvent:
+		return "event"
+	case FrameReplay:
+		return "replay"
+	case FrameError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// Frame is a message sent over an event socket.
+//
+// Frames are encoded as:
+//
+//	+--------------+-----------+
+//	| version      : byte      |
+//	+--------------+-----------+
+//	| type         : byte      |
+//	+--------------+-----------+
+//	| sequence     : uint64    |
+//	+--------------+-----------+
+//	| chain ID     : [32]byte  |
+//	+--------------+-----------+
+//	| container ID : [32]byte  |
+//	+--------------+-----------+
+//	| timestamp    : int64     |
+//	+--------------+-----------+
+//	| payload      : []byte    |
+//	+--------------+-----------+
+//
+// The timestamp is the unix time, in nanoseconds, at which the container was
+// accepted. The payload is prefixed with its uint32 length.
+type Frame struct {
+	Type FrameType
+	// Sequence is the position of the event in the order of acceptance. If
+	// the chain is indexed, it is the index of the container in the indexer.
+	Sequence    uint64
+	ChainID     ids.ID
+	ContainerID ids.ID
+	Timestamp   int64
+	Payload     []byte
+}
+
+// Bytes returns the binary representation of this frame.
+func (f *Frame) Bytes() []byte {
+	p := wrappers.Packer{Bytes: make([]byte, frameHeaderLen+len(f.Payload))}
+	p.PackByte(FrameVersion)
+	p.PackByte(byte(f.Type))
+	p.PackLong(f.Sequence)
+	p.PackFixedBytes(f.ChainID[:])
+	p.PackFixedBytes(f.ContainerID[:])
+	p.PackLong(uint64(f.Timestamp))
+	p.PackBytes(f.Payload)
+	return p.Bytes
+}
+
+// ParseFrame parses the binary representation of a frame.
+func ParseFrame(b []byte) (*Frame, error) {
+	p := wrappers.Packer{Bytes: b}
+	version := p.UnpackByte()
+	if p.Errored() {
+		return nil, p.Err
+	}
+	if version != FrameVersion {
+		return nil, fmt.Errorf("%w: %d", errUnknownFrameVersion, version)
+	}
+
+	f := &Frame{
+		Type:     FrameType(p.UnpackByte()),
+		Sequence: p.UnpackLong(),
+	}
+	copy(f.ChainID[:], p.UnpackFixedBytes(hashing.HashLen))
+	copy(f.ContainerID[:], p.UnpackFixedBytes(hashing.HashLen))
+	f.Timestamp = int64(p.UnpackLong())
+	f.Payload = p.UnpackBytes()
+	if p.Errored() {
+		return nil, p.Err
+	}
+	switch f.Type {
+	case FrameEvent, FrameReplay, FrameError:
+		return f, nil
+	default:
+		return nil, fmt.Errorf("%w: %d", errUnknownFrameType, f.Type)
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ipcs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dim4egster/qmallgo/ids"
+)
+
+func TestFrameRoundTrip(t *testing.T) {
+	require := require.New(t)
+
+	frame := &Frame{
+		Type:        FrameEvent,
+		Sequence:    12,
+		ChainID:     ids.GenerateTestID(),
+		ContainerID: ids.GenerateTestID(),
+		Timestamp:   1234,
+		Payload:     []byte("container"),
+	}
+	parsed, err := ParseFrame(frame.Bytes())
+	require.NoError(err)
+	require.Equal(frame, parsed)
+}
+
+func TestParseFrameErrors(t *testing.T) {
+	require := require.New(t)
+
+	frameBytes := (&Frame{Type: FrameReplay}).Bytes()
+
+	_, err := ParseFrame(nil)
+	require.Error(err)
+
+	_, err = ParseFrame(frameBytes[:len(frameBytes)-1])
+	require.Error(err)
+
+	badVersion := append([]byte{}, frameBytes...)
+	badVersion[0] = FrameVersion + 1
+	_, err = ParseFrame(badVersion)
+	require.ErrorIs(err, errUnknownFrameVersion)
+
+	badType := append([]byte{}, frameBytes...)
+	badType[1] = 0
+	_, err = ParseFrame(badType)
+	require.ErrorIs(err, errUnknownFrameType)
+}
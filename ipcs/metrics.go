@@ -0,0 +1,85 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ipcs
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/ipcs/socket"
+	"github.com/dim4egster/qmallgo/utils/wrappers"
+)
+
+var socketLabels = []string{"chain", "events"}
+
+type metrics struct {
+	sent         *prometheus.CounterVec
+	dropped      *prometheus.CounterVec
+	disconnected *prometheus.CounterVec
+	replayed     *prometheus.CounterVec
+	readers      *prometheus.GaugeVec
+}
+
+func newMetrics(namespace string, reg prometheus.Registerer) (*metrics, error) {
+	m := &metrics{
+		sent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "sent",
+			Help:      "Number of frames queued to be written to readers",
+		}, socketLabels),
+		dropped: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "dropped",
+			Help:      "Number of frames dropped because a reader's buffer was full",
+		}, socketLabels),
+		disconnected: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "slow_readers_disconnected",
+			Help:      "Number of readers disconnected because their buffer was full",
+		}, socketLabels),
+		replayed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "replayed",
+			Help:      "Number of frames replayed from the index to readers",
+		}, socketLabels),
+		readers: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "readers",
+			Help:      "Number of connected readers",
+		}, socketLabels),
+	}
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		reg.Register(m.sent),
+		reg.Register(m.dropped),
+		reg.Register(m.disconnected),
+		reg.Register(m.replayed),
+		reg.Register(m.readers),
+	)
+	return m, errs.Err
+}
+
+// socketMetrics returns the metrics of the [eventType] socket of [chainID]
+func (m *metrics) socketMetrics(chainID ids.ID, eventType string) socket.Metrics {
+	labels := prometheus.Labels{
+		"chain":  chainID.String(),
+		"events": eventType,
+	}
+	return socket.Metrics{
+		Sent:         m.sent.With(labels),
+		Dropped:      m.dropped.With(labels),
+		Disconnected: m.disconnected.With(labels),
+		Clients:      m.readers.With(labels),
+	}
+}
+
+// replayedCounter returns the counter of frames replayed by the
+// [eventType] socket of [chainID]
+func (m *metrics) replayedCounter(chainID ids.ID, eventType string) prometheus.Counter {
+	return m.replayed.With(prometheus.Labels{
+		"chain":  chainID.String(),
+		"events": eventType,
+	})
+}
@@ -0,0 +1,46 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ipcs
+
+import (
+	"github.com/dim4egster/qmallgo/ipcs/socket"
+)
+
+// Reader reads the frames written to an event socket.
+type Reader struct {
+	client *socket.Client
+}
+
+// Dial connects a Reader to the event socket at [url].
+func Dial(url string) (*Reader, error) {
+	client, err := socket.Dial(url)
+	if err != nil {
+		return nil, err
+	}
+	return &Reader{client: client}, nil
+}
+
+// Recv waits for the next frame written to the socket.
+func (r *Reader) Recv() (*Frame, error) {
+	msg, err := r.client.Recv()
+	if err != nil {
+		return nil, err
+	}
+	return ParseFrame(msg)
+}
+
+// RequestReplay requests the events starting at [sequence] to be sent again.
+// Live events aren't sent until the replayed events have caught up with them.
+func (r *Reader) RequestReplay(sequence uint64) error {
+	request := &Frame{
+		Type:     FrameReplay,
+		Sequence: sequence,
+	}
+	return r.client.Send(request.Bytes())
+}
+
+// Close closes the connection to the socket.
+func (r *Reader) Close() error {
+	return r.client.Close()
+}
@@ -10,31 +10,118 @@ import (
 	"io"
 	"net"
 	"os"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"syscall"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	"go.uber.org/zap"
 
 	"github.com/dim4egster/qmallgo/utils/logging"
+	"github.com/dim4egster/qmallgo/utils/units"
 	"github.com/dim4egster/qmallgo/utils/wrappers"
 )
 
+const (
+	// DefaultBufferSize is the default number of messages that may be pending
+	// for a client of a Socket.
+	DefaultBufferSize = 1024
+
+	// maxRequestSize is the maximum size of a message that a client may send
+	// to a Socket.
+	maxRequestSize = 4 * units.KiB
+)
+
 var (
 	// ErrMessageTooLarge is returned when reading a message that is larger than
 	// our max size
 	ErrMessageTooLarge = errors.New("message too large")
 
+	errUnknownPolicy = errors.New("unknown slow consumer policy")
+
 	_ error = errReadTimeout{}
 )
 
+// Policy is the action taken when a message is sent to a client whose buffer
+// of pending messages is full.
+type Policy byte
+
+const (
+	// PolicyDrop drops the message for the slow client.
+	PolicyDrop Policy = iota
+	// PolicyDisconnect disconnects the slow client.
+	PolicyDisconnect
+)
+
+func (p Policy) String() string {
+	switch p {
+	case PolicyDrop:
+		return "drop"
+	case PolicyDisconnect:
+		return "disconnect"
+	default:
+		return "unknown"
+	}
+}
+
+func (p Policy) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprintf("%q", p)), nil
+}
+
+// PolicyFromString returns the policy named [s].
+func PolicyFromString(s string) (Policy, error) {
+	for _, p := range []Policy{PolicyDrop, PolicyDisconnect} {
+		if strings.EqualFold(s, p.String()) {
+			return p, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %q", errUnknownPolicy, s)
+}
+
+// Config configures how a Socket buffers messages for its clients.
+type Config struct {
+	// BufferSize is the number of messages that may be pending for a client.
+	BufferSize int `json:"bufferSize"`
+	// Policy is applied when a client's buffer is full.
+	Policy Policy `json:"policy"`
+}
+
+// Metrics tracks the messages sent by a Socket.
+type Metrics struct {
+	// Sent counts the messages queued to be written to clients.
+	Sent prometheus.Counter
+	// Dropped counts the messages dropped because a client was too slow.
+	Dropped prometheus.Counter
+	// Disconnected counts the clients disconnected because they were too
+	// slow.
+	Disconnected prometheus.Counter
+	// Clients is the number of connected clients.
+	Clients prometheus.Gauge
+}
+
+// Handler is notified of the clients of a Socket and of the messages they
+// send.
+type Handler interface {
+	// Connected is called once [conn] is able to send and receive messages.
+	Connected(conn *Conn)
+	// HandleMessage is called with every message received from [conn].
+	HandleMessage(conn *Conn, msg []byte)
+	// Disconnected is called once [conn] is closed.
+	Disconnected(conn *Conn)
+}
+
 // Socket manages sending messages over a socket to many subscribed clients
 type Socket struct {
 	log      logging.Logger
 	addr     string
+	config   Config
+	metrics  Metrics
+	handler  Handler
 	accept   acceptFn
 	connLock *sync.RWMutex
-	conns    map[net.Conn]struct{}
+	conns    map[*Conn]struct{}
 	quitCh   chan struct{}
 	doneCh   chan struct{}
 	listener net.Listener // the current listener
@@ -43,12 +130,37 @@ type Socket struct {
 // NewSocket creates a new socket object for the given address. It does not open
 // the socket until Listen is called.
 func NewSocket(addr string, log logging.Logger) *Socket {
+	return NewSocketWithConfig(
+		addr,
+		log,
+		Config{
+			BufferSize: DefaultBufferSize,
+			Policy:     PolicyDrop,
+		},
+		Metrics{
+			Sent:         prometheus.NewCounter(prometheus.CounterOpts{}),
+			Dropped:      prometheus.NewCounter(prometheus.CounterOpts{}),
+			Disconnected: prometheus.NewCounter(prometheus.CounterOpts{}),
+			Clients:      prometheus.NewGauge(prometheus.GaugeOpts{}),
+		},
+		nil,
+	)
+}
+
+// NewSocketWithConfig creates a new socket object for the given address that
+// buffers messages for its clients according to [config]. If [handler] is
+// non-nil, it is notified of the clients and of the messages they send. It
+// does not open the socket until Listen is called.
+func NewSocketWithConfig(addr string, log logging.Logger, config Config, metrics Metrics, handler Handler) *Socket {
 	return &Socket{
 		log:      log,
 		addr:     addr,
+		config:   config,
+		metrics:  metrics,
+		handler:  handler,
 		accept:   accept,
 		connLock: &sync.RWMutex{},
-		conns:    map[net.Conn]struct{}{},
+		conns:    map[*Conn]struct{}{},
 		quitCh:   make(chan struct{}),
 		doneCh:   make(chan struct{}),
 	}
@@ -78,40 +190,17 @@ func (s *Socket) Listen() error {
 	return nil
 }
 
-// Send writes the given message to all connection clients
+// Send queues the given message to be written to all connection clients
 func (s *Socket) Send(msg []byte) {
-	var conns []net.Conn
-
-	// Get a copy of connections
 	s.connLock.RLock()
-	if len(s.conns) > 0 {
-		conns = make([]net.Conn, len(s.conns))
-		i := 0
-		for conn := range s.conns {
-			conns[i] = conn
-			i++
-		}
+	conns := make([]*Conn, 0, len(s.conns))
+	for conn := range s.conns {
+		conns = append(conns, conn)
 	}
 	s.connLock.RUnlock()
 
-	// Write to each connection
-	if len(conns) == 0 {
-		return
-	}
-
-	// Prefix the message with an 8 byte length
-	lenBytes := [8]byte{}
-	binary.BigEndian.PutUint64(lenBytes[:], uint64(len(msg)))
 	for _, conn := range conns {
-		for _, byteSlice := range [][]byte{lenBytes[:], msg} {
-			if _, err := conn.Write(byteSlice); err != nil {
-				s.removeConn(conn)
-				s.log.Debug("failed to write message",
-					zap.Stringer("remoteAddress", conn.RemoteAddr()),
-					zap.Error(err),
-				)
-			}
-		}
+		conn.Send(msg)
 	}
 }
 
@@ -138,9 +227,7 @@ func (s *Socket) Close() error {
 	// Close all connections that were open at the time of shutdown
 	errs := wrappers.Errs{Err: err}
 	for conn := range conns {
-		if conn != nil {
-			errs.Add(conn.Close())
-		}
+		errs.Add(conn.close())
 	}
 	return errs.Err
 }
@@ -149,12 +236,158 @@ func (s *Socket) Running() bool {
 	return s.listener != nil
 }
 
-func (s *Socket) removeConn(c net.Conn) {
+// addConn starts serving [netConn]
+func (s *Socket) addConn(netConn net.Conn) *Conn {
+	c := &Conn{
+		s:      s,
+		conn:   netConn,
+		send:   make(chan []byte, s.config.BufferSize),
+		closed: make(chan struct{}),
+	}
+
+	s.connLock.Lock()
+	if s.conns == nil {
+		// The socket was closed
+		s.connLock.Unlock()
+		_ = netConn.Close()
+		close(c.closed)
+		return c
+	}
+	s.conns[c] = struct{}{}
+	s.connLock.Unlock()
+
+	s.metrics.Clients.Inc()
+	if s.handler != nil {
+		s.handler.Connected(c)
+	}
+	go c.writeLoop()
+	go c.readLoop()
+	return c
+}
+
+func (s *Socket) removeConn(c *Conn) {
 	s.connLock.Lock()
 	delete(s.conns, c)
 	s.connLock.Unlock()
 }
 
+// Conn is a connection to a client of a Socket
+type Conn struct {
+	s    *Socket
+	conn net.Conn
+
+	// Buffered channel of outbound messages
+	send chan []byte
+
+	// closed is closed once the connection is closed
+	closed    chan struct{}
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// Send queues [msg] to be written to the client. If the client has too many
+// pending messages, the Socket's Policy is applied. Returns true if [msg] was
+// queued.
+func (c *Conn) Send(msg []byte) bool {
+	select {
+	case <-c.closed:
+		return false
+	default:
+	}
+
+	select {
+	case c.send <- msg:
+		c.s.metrics.Sent.Inc()
+		return true
+	default:
+	}
+
+	switch c.s.config.Policy {
+	case PolicyDisconnect:
+		c.s.metrics.Disconnected.Inc()
+		c.s.log.Debug("disconnecting slow client",
+			zap.Stringer("remoteAddress", c.conn.RemoteAddr()),
+		)
+		_ = c.close()
+	default:
+		c.s.metrics.Dropped.Inc()
+	}
+	return false
+}
+
+// SendWait queues [msg] to be written to the client, waiting for pending
+// messages to be written if necessary. Returns false if the connection was
+// closed before [msg] could be queued.
+func (c *Conn) SendWait(msg []byte) bool {
+	select {
+	case c.send <- msg:
+		c.s.metrics.Sent.Inc()
+		return true
+	case <-c.closed:
+		return false
+	}
+}
+
+// Done returns a channel that is closed once the connection is closed
+func (c *Conn) Done() <-chan struct{} {
+	return c.closed
+}
+
+// Close closes the connection
+func (c *Conn) Close() error {
+	return c.close()
+}
+
+func (c *Conn) close() error {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		c.closeErr = c.conn.Close()
+		c.s.removeConn(c)
+		c.s.metrics.Clients.Dec()
+		if c.s.handler != nil {
+			c.s.handler.Disconnected(c)
+		}
+	})
+	return c.closeErr
+}
+
+// writeLoop writes the queued messages to the client, each prefixed with an 8
+// byte length
+func (c *Conn) writeLoop() {
+	for {
+		select {
+		case msg := <-c.send:
+			if err := writeMessage(c.conn, msg); err != nil {
+				c.s.log.Debug("failed to write message",
+					zap.Stringer("remoteAddress", c.conn.RemoteAddr()),
+					zap.Error(err),
+				)
+				_ = c.close()
+				return
+			}
+		case <-c.closed:
+			return
+		}
+	}
+}
+
+// readLoop passes the messages sent by the client to the Socket's handler
+func (c *Conn) readLoop() {
+	defer func() {
+		_ = c.close()
+	}()
+
+	for {
+		msg, err := readMessage(c.conn, maxRequestSize)
+		if err != nil {
+			return
+		}
+		if c.s.handler != nil {
+			c.s.handler.HandleMessage(c, msg)
+		}
+	}
+}
+
 // Client is a read-only connection to a socket
 type Client struct {
 	net.Conn
@@ -164,24 +397,53 @@ type Client struct {
 // Recv waits for a message from the socket. It's guaranteed to either return a
 // complete message or an error
 func (c *Client) Recv() ([]byte, error) {
+	return readMessage(c.Conn, uint64(atomic.LoadInt64(&c.maxMessageSize)))
+}
+
+// Send writes [msg] to the socket
+func (c *Client) Send(msg []byte) error {
+	return writeMessage(c.Conn, msg)
+}
+
+// SetMaxMessageSize sets the maximum size to allow for messages
+func (c *Client) SetMaxMessageSize(s int64) {
+	atomic.StoreInt64(&c.maxMessageSize, s)
+}
+
+// Close closes the underlying socket connection
+func (c *Client) Close() error {
+	return c.Conn.Close()
+}
+
+// writeMessage writes [msg] to [conn] prefixed with an 8 byte length
+func writeMessage(conn net.Conn, msg []byte) error {
+	buf := make([]byte, wrappers.LongLen+len(msg))
+	binary.BigEndian.PutUint64(buf, uint64(len(msg)))
+	copy(buf[wrappers.LongLen:], msg)
+	_, err := conn.Write(buf)
+	return err
+}
+
+// readMessage reads a message prefixed with an 8 byte length from [conn]
+func readMessage(conn net.Conn, maxMessageSize uint64) ([]byte, error) {
 	// Read length
 	var sz uint64
-	if err := binary.Read(c.Conn, binary.BigEndian, &sz); err != nil {
+	if err := binary.Read(conn, binary.BigEndian, &sz); err != nil {
 		if isTimeoutError(err) {
-			return nil, errReadTimeout{c.Conn.RemoteAddr()}
+			return nil, errReadTimeout{conn.RemoteAddr()}
 		}
 		return nil, err
 	}
 
-	if sz > uint64(atomic.LoadInt64(&c.maxMessageSize)) {
+	if sz > maxMessageSize {
 		return nil, ErrMessageTooLarge
 	}
 
 	// Create buffer for entire message and read it all in
 	msg := make([]byte, sz)
-	if _, err := io.ReadFull(c.Conn, msg); err != nil {
+	if _, err := io.ReadFull(conn, msg); err != nil {
 		if isTimeoutError(err) {
-			return nil, errReadTimeout{c.Conn.RemoteAddr()}
+			return nil, errReadTimeout{conn.RemoteAddr()}
 		}
 		return nil, err
 	}
@@ -189,16 +451,6 @@ func (c *Client) Recv() ([]byte, error) {
 	return msg, nil
 }
 
-// SetMaxMessageSize sets the maximum size to allow for messages
-func (c *Client) SetMaxMessageSize(s int64) {
-	atomic.StoreInt64(&c.maxMessageSize, s)
-}
-
-// Close closes the underlying socket connection
-func (c *Client) Close() error {
-	return c.Conn.Close()
-}
-
 // errReadTimeout is returned a socket read times out
 type errReadTimeout struct {
 	addr net.Addr
@@ -222,6 +474,7 @@ func accept(s *Socket, l net.Listener) {
 		s.log.Error("socket accept error",
 			zap.Error(err),
 		)
+		return
 	}
 	if conn, ok := conn.(*net.TCPConn); ok {
 		if err := conn.SetLinger(0); err != nil {
@@ -235,9 +488,7 @@ func accept(s *Socket, l net.Listener) {
 			)
 		}
 	}
-	s.connLock.Lock()
-	s.conns[conn] = struct{}{}
-	s.connLock.Unlock()
+	s.addConn(conn)
 }
 
 // isTimeoutError checks if an error is a timeout as per the net.Error interface
@@ -4,8 +4,11 @@
 package socket
 
 import (
+	"errors"
 	"net"
 	"testing"
+
+	"github.com/dim4egster/qmallgo/utils/logging"
 )
 
 func TestSocketSendAndReceive(t *testing.T) {
@@ -17,7 +20,7 @@ func TestSocketSendAndReceive(t *testing.T) {
 	)
 
 	// Create socket and client; wait for client to connect
-	socket := NewSocket(socketName, nil)
+	socket := NewSocket(socketName, logging.NoLog{})
 	socket.accept, connCh = newTestAcceptFn(t)
 	if err := socket.Listen(); err != nil {
 		t.Fatal("Failed to listen on socket:", err.Error())
@@ -67,10 +70,23 @@ func newTestAcceptFn(t *testing.T) (acceptFn, chan net.Conn) {
 			t.Error(err)
 		}
 
-		s.connLock.Lock()
-		s.conns[conn] = struct{}{}
-		s.connLock.Unlock()
+		s.addConn(conn)
 
 		connCh <- conn
 	}, connCh
 }
+
+func TestPolicyFromString(t *testing.T) {
+	for _, policy := range []Policy{PolicyDrop, PolicyDisconnect} {
+		parsed, err := PolicyFromString(policy.String())
+		if err != nil {
+			t.Fatal("Failed to parse policy:", err)
+		}
+		if parsed != policy {
+			t.Fatalf("Parsed %s, expected %s", parsed, policy)
+		}
+	}
+	if _, err := PolicyFromString("block"); !errors.Is(err, errUnknownPolicy) {
+		t.Fatal("Should have failed to parse unknown policy, got:", err)
+	}
+}
@@ -0,0 +1,236 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// dbmigrate copies every versioned database of a node from one database
+// backend to another, verifies the copy, and optionally prunes old versioned
+// databases.
+//
+// The node must not be running while the tool is used.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/spf13/pflag"
+
+	"go.uber.org/zap"
+
+	"github.com/dim4egster/qmallgo/database/leveldb"
+	"github.com/dim4egster/qmallgo/database/manager"
+	"github.com/dim4egster/qmallgo/database/migrate"
+	"github.com/dim4egster/qmallgo/database/pebbledb"
+	"github.com/dim4egster/qmallgo/utils/logging"
+	"github.com/dim4egster/qmallgo/utils/perms"
+	"github.com/dim4egster/qmallgo/utils/wrappers"
+	"github.com/dim4egster/qmallgo/version"
+)
+
+const (
+	srcDBTypeKey         = "source-db-type"
+	srcDBDirKey          = "source-db-dir"
+	srcDBConfigFileKey   = "source-db-config-file"
+	dstDBTypeKey         = "dest-db-type"
+	dstDBDirKey          = "dest-db-dir"
+	dstDBConfigFileKey   = "dest-db-config-file"
+	batchSizeKey         = "batch-size"
+	progressFrequencyKey = "progress-frequency"
+	verifyKey            = "verify"
+	pruneKey             = "prune"
+)
+
+var (
+	errMissingSrcDir = errors.New("source database directory must be specified")
+	errSameDir       = errors.New("source and destination database directories must differ")
+)
+
+func main() {
+	fs := pflag.NewFlagSet("dbmigrate", pflag.ContinueOnError)
+	fs.String(srcDBTypeKey, leveldb.Name, "Database type of the source database")
+	fs.String(srcDBDirKey, "", "Path to the source database directory, including the network name")
+	fs.String(srcDBConfigFileKey, "", "Path to the source database config file")
+	fs.String(dstDBTypeKey, pebbledb.Name, "Database type of the destination database")
+	fs.String(dstDBDirKey, "", "Path to the destination database directory, including the network name. If empty, no migration is performed")
+	fs.String(dstDBConfigFileKey, "", "Path to the destination database config file")
+	fs.Int(batchSizeKey, migrate.DefaultBatchSize, "Number of bytes to buffer before writing to the destination database")
+	fs.Duration(progressFrequencyKey, migrate.DefaultProgressFrequency, "Frequency to log the progress of the migration")
+	fs.Bool(verifyKey, true, "Verify that the destination database matches the source database after migrating")
+	fs.Bool(pruneKey, false, fmt.Sprintf("Remove versioned databases older than %s from the source database directory", version.PrevDatabase))
+
+	if err := fs.Parse(os.Args[1:]); err != nil {
+		if errors.Is(err, pflag.ErrHelp) {
+			os.Exit(0)
+		}
+		fmt.Printf("couldn't parse flags: %s\n", err)
+		os.Exit(1)
+	}
+
+	log := logging.NewLogger(
+		false,
+		"dbmigrate",
+		logging.NewWrappedCore(logging.Info, os.Stdout, logging.Colors.ConsoleEncoder()),
+	)
+	if err := run(fs, log); err != nil {
+		log.Fatal("dbmigrate failed",
+			zap.Error(err),
+		)
+		os.Exit(1)
+	}
+}
+
+func run(fs *pflag.FlagSet, log logging.Logger) error {
+	errs := wrappers.Errs{}
+	srcDBType, err := fs.GetString(srcDBTypeKey)
+	errs.Add(err)
+	srcDBDir, err := fs.GetString(srcDBDirKey)
+	errs.Add(err)
+	srcDBConfigFile, err := fs.GetString(srcDBConfigFileKey)
+	errs.Add(err)
+	dstDBType, err := fs.GetString(dstDBTypeKey)
+	errs.Add(err)
+	dstDBDir, err := fs.GetString(dstDBDirKey)
+	errs.Add(err)
+	dstDBConfigFile, err := fs.GetString(dstDBConfigFileKey)
+	errs.Add(err)
+	batchSize, err := fs.GetInt(batchSizeKey)
+	errs.Add(err)
+	progressFrequency, err := fs.GetDuration(progressFrequencyKey)
+	errs.Add(err)
+	verify, err := fs.GetBool(verifyKey)
+	errs.Add(err)
+	prune, err := fs.GetBool(pruneKey)
+	errs.Add(err)
+	if errs.Errored() {
+		return errs.Err
+	}
+
+	if srcDBDir == "" {
+		return errMissingSrcDir
+	}
+
+	if dstDBDir != "" {
+		if filepath.Clean(srcDBDir) == filepath.Clean(dstDBDir) {
+			return errSameDir
+		}
+		err := migrateDBs(
+			log,
+			srcDBType,
+			srcDBDir,
+			srcDBConfigFile,
+			dstDBType,
+			dstDBDir,
+			dstDBConfigFile,
+			migrate.Config{
+				BatchSize:         batchSize,
+				ProgressFrequency: progressFrequency,
+				CheckpointPath:    filepath.Join(dstDBDir, migrate.CheckpointFileName),
+			},
+			verify,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !prune {
+		return nil
+	}
+
+	// The node only ever reads from the current and previous database
+	// versions, so anything older can be safely removed.
+	removed, err := migrate.Prune(srcDBDir, version.PrevDatabase)
+	for _, path := range removed {
+		log.Info("pruned database",
+			zap.String("path", path),
+		)
+	}
+	return err
+}
+
+func migrateDBs(
+	log logging.Logger,
+	srcDBType string,
+	srcDBDir string,
+	srcDBConfigFile string,
+	dstDBType string,
+	dstDBDir string,
+	dstDBConfigFile string,
+	config migrate.Config,
+	verify bool,
+) error {
+	srcConfig, err := readConfig(srcDBConfigFile)
+	if err != nil {
+		return err
+	}
+	dstConfig, err := readConfig(dstDBConfigFile)
+	if err != nil {
+		return err
+	}
+	newDstDB, ok := migrate.Factories[dstDBType]
+	if !ok {
+		return fmt.Errorf("unknown destination database type %q", dstDBType)
+	}
+
+	var srcManager manager.Manager
+	switch srcDBType {
+	case leveldb.Name:
+		srcManager, err = manager.NewLevelDB(srcDBDir, srcConfig, log, version.CurrentDatabase, "src", prometheus.NewRegistry())
+	case pebbledb.Name:
+		srcManager, err = manager.NewPebbleDB(srcDBDir, srcConfig, log, version.CurrentDatabase, "src", prometheus.NewRegistry())
+	default:
+		err = fmt.Errorf("unknown source database type %q", srcDBType)
+	}
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := srcManager.Close(); err != nil {
+			log.Error("failed to close source database",
+				zap.Error(err),
+			)
+		}
+	}()
+
+	if err := os.MkdirAll(dstDBDir, perms.ReadWriteExecute); err != nil {
+		return err
+	}
+
+	migrator, err := migrate.New(log, config)
+	if err != nil {
+		return err
+	}
+
+	for _, srcDB := range srcManager.GetDatabases() {
+		dstPath := filepath.Join(dstDBDir, srcDB.Version.String())
+		dstDB, err := newDstDB(dstPath, dstConfig, log, "dst", prometheus.NewRegistry())
+		if err != nil {
+			return fmt.Errorf("couldn't create db at %s: %w", dstPath, err)
+		}
+
+		err = migrator.Migrate(srcDB.Version, srcDB.Database, dstDB)
+		if err == nil && verify {
+			log.Info("verifying database",
+				zap.Stringer("dbVersion", srcDB.Version),
+			)
+			err = migrate.Verify(srcDB.Database, dstDB)
+		}
+		if closeErr := dstDB.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("failed to migrate database %s: %w", srcDB.Version, err)
+		}
+	}
+	return nil
+}
+
+func readConfig(path string) ([]byte, error) {
+	if path == "" {
+		return nil, nil
+	}
+	return os.ReadFile(path)
+}
@@ -0,0 +1,393 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// partialtx moves partially signed P-chain and X-chain transactions through
+// an offline signing workflow. Partial txs are exported by the wallet with
+// p.NewPartialTx or x.NewPartialTx and stored as hex encoded files.
+//
+// Usage:
+//
+//	partialtx status [--chain=P|X] <file>
+//	partialtx sign   [--chain=P|X] --key-file=<keys> --out=<file> <file>
+//	partialtx merge  [--chain=P|X] --out=<file> <file>...
+//	partialtx issue  [--chain=P|X] --uri=<node uri> <file>
+//
+// Only the issue command needs access to a node.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	stdcontext "context"
+
+	"github.com/spf13/pflag"
+
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/utils/crypto"
+	"github.com/dim4egster/qmallgo/utils/formatting"
+	"github.com/dim4egster/qmallgo/utils/perms"
+	"github.com/dim4egster/qmallgo/vms/avm"
+	"github.com/dim4egster/qmallgo/vms/platformvm"
+	"github.com/dim4egster/qmallgo/vms/secp256k1fx"
+	"github.com/dim4egster/qmallgo/wallet/chain/p"
+	"github.com/dim4egster/qmallgo/wallet/chain/x"
+	"github.com/dim4egster/qmallgo/wallet/subnet/primary/common"
+)
+
+const (
+	statusCommand = "status"
+	signCommand   = "sign"
+	mergeCommand  = "merge"
+	issueCommand  = "issue"
+
+	chainKey   = "chain"
+	keyFileKey = "key-file"
+	outKey     = "out"
+	uriKey     = "uri"
+
+	issueTimeout = 30 * time.Second
+)
+
+var (
+	errMissingCommand = errors.New("missing command")
+	errUnknownCommand = errors.New("unknown command")
+	errUnknownChain   = errors.New("unknown chain")
+	errWrongNumArgs   = errors.New("wrong number of partial tx files")
+	errMissingFlag    = errors.New("missing flag")
+	errNoKeys         = errors.New("no keys in key file")
+
+	_ chain = pChain{}
+	_ chain = xChain{}
+)
+
+func main() {
+	if err := run(stdcontext.Background(), os.Args[1:], os.Stdout); err != nil {
+		if errors.Is(err, pflag.ErrHelp) {
+			os.Exit(0)
+		}
+		fmt.Fprintf(os.Stderr, "partialtx failed: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// run executes the command described by [args] and writes its output to
+// [out].
+func run(ctx stdcontext.Context, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errMissingCommand
+	}
+	command := args[0]
+
+	fs := pflag.NewFlagSet("partialtx "+command, pflag.ContinueOnError)
+	fs.String(chainKey, "P", "Chain the tx is issued on, either P or X")
+	switch command {
+	case statusCommand:
+	case signCommand:
+		fs.String(keyFileKey, "", "Path to a file with one private key per line")
+		fs.String(outKey, "", "Path to write the signed partial tx to")
+	case mergeCommand:
+		fs.String(outKey, "", "Path to write the merged partial tx to")
+	case issueCommand:
+		fs.String(uriKey, "http://127.0.0.1:9650", "URI of the node to issue the tx to")
+	default:
+		return fmt.Errorf("%w %q", errUnknownCommand, command)
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	chainAlias, err := fs.GetString(chainKey)
+	if err != nil {
+		return err
+	}
+	c, err := getChain(chainAlias)
+	if err != nil {
+		return err
+	}
+
+	paths := fs.Args()
+	switch command {
+	case statusCommand:
+		if len(paths) != 1 {
+			return errWrongNumArgs
+		}
+		ptx, err := readPartialTx(c, paths[0])
+		if err != nil {
+			return err
+		}
+		return writeStatus(out, ptx)
+	case signCommand:
+		if len(paths) != 1 {
+			return errWrongNumArgs
+		}
+		keyFile, err := getRequiredString(fs, keyFileKey)
+		if err != nil {
+			return err
+		}
+		outPath, err := getRequiredString(fs, outKey)
+		if err != nil {
+			return err
+		}
+		kc, err := readKeys(keyFile)
+		if err != nil {
+			return err
+		}
+		ptx, err := readPartialTx(c, paths[0])
+		if err != nil {
+			return err
+		}
+		if err := ptx.sign(ctx, kc); err != nil {
+			return fmt.Errorf("couldn't sign tx: %w", err)
+		}
+		if err := writePartialTx(outPath, ptx); err != nil {
+			return err
+		}
+		return writeStatus(out, ptx)
+	case mergeCommand:
+		if len(paths) == 0 {
+			return errWrongNumArgs
+		}
+		outPath, err := getRequiredString(fs, outKey)
+		if err != nil {
+			return err
+		}
+		ptxs := make([]partialTx, len(paths))
+		for i, path := range paths {
+			ptxs[i], err = readPartialTx(c, path)
+			if err != nil {
+				return err
+			}
+		}
+		merged, err := c.merge(ptxs)
+		if err != nil {
+			return fmt.Errorf("couldn't merge partial txs: %w", err)
+		}
+		if err := writePartialTx(outPath, merged); err != nil {
+			return err
+		}
+		return writeStatus(out, merged)
+	default: // issueCommand
+		if len(paths) != 1 {
+			return errWrongNumArgs
+		}
+		uri, err := getRequiredString(fs, uriKey)
+		if err != nil {
+			return err
+		}
+		ptx, err := readPartialTx(c, paths[0])
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := stdcontext.WithTimeout(ctx, issueTimeout)
+		defer cancel()
+		txID, err := ptx.issue(ctx, uri)
+		if err != nil {
+			return fmt.Errorf("couldn't issue tx: %w", err)
+		}
+		_, err = fmt.Fprintf(out, "issued tx %s\n", txID)
+		return err
+	}
+}
+
+func getRequiredString(fs *pflag.FlagSet, key string) (string, error) {
+	value, err := fs.GetString(key)
+	if err != nil {
+		return "", err
+	}
+	if value == "" {
+		return "", fmt.Errorf("%w --%s", errMissingFlag, key)
+	}
+	return value, nil
+}
+
+// readKeys returns a keychain of the keys in [path]. Each non-empty line of
+// the file must be a private key formatted as "PrivateKey-...".
+func readKeys(path string) (*secp256k1fx.Keychain, error) {
+	keysBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read key file: %w", err)
+	}
+
+	kc := secp256k1fx.NewKeychain()
+	scanner := bufio.NewScanner(bytes.NewReader(keysBytes))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		key := &crypto.PrivateKeySECP256K1R{}
+		if err := key.UnmarshalJSON([]byte(strconv.Quote(line))); err != nil {
+			return nil, fmt.Errorf("couldn't parse key: %w", err)
+		}
+		kc.Add(key)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(kc.Keys) == 0 {
+		return nil, errNoKeys
+	}
+	return kc, nil
+}
+
+func readPartialTx(c chain, path string) (partialTx, error) {
+	ptxHex, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read partial tx: %w", err)
+	}
+	ptxBytes, err := formatting.Decode(formatting.Hex, strings.TrimSpace(string(ptxHex)))
+	if err != nil {
+		return nil, fmt.Errorf("couldn't decode partial tx %s: %w", path, err)
+	}
+	return c.parse(ptxBytes)
+}
+
+func writePartialTx(path string, ptx partialTx) error {
+	ptxBytes, err := ptx.Bytes()
+	if err != nil {
+		return err
+	}
+	ptxHex, err := formatting.Encode(formatting.Hex, ptxBytes)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(ptxHex), perms.ReadWrite)
+}
+
+// writeStatus writes the signatures [ptx] holds and is missing to [out].
+func writeStatus(out io.Writer, ptx partialTx) error {
+	statuses, err := ptx.SigningStatus()
+	if err != nil {
+		return err
+	}
+
+	complete := true
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "tx %s\n", ptx.txID())
+	for credIndex, status := range statuses {
+		fmt.Fprintf(b, "credential %d:\n", credIndex)
+		for sigIndex, signer := range status.Signers {
+			sigStatus := "signed"
+			if !status.Signed[sigIndex] {
+				sigStatus = "missing"
+				complete = false
+			}
+			fmt.Fprintf(b, "  %s %s\n", signer, sigStatus)
+		}
+	}
+	if complete {
+		b.WriteString("fully signed\n")
+	} else {
+		b.WriteString("missing signatures\n")
+	}
+	_, err = io.WriteString(out, b.String())
+	return err
+}
+
+// chain handles the partial txs of a chain.
+type chain interface {
+	parse(b []byte) (partialTx, error)
+	merge(ptxs []partialTx) (partialTx, error)
+}
+
+// partialTx is a partially signed tx of a chain.
+type partialTx interface {
+	Bytes() ([]byte, error)
+	SigningStatus() ([]*common.CredentialStatus, error)
+
+	txID() ids.ID
+	sign(ctx stdcontext.Context, kc *secp256k1fx.Keychain) error
+	issue(ctx stdcontext.Context, uri string) (ids.ID, error)
+}
+
+func getChain(alias string) (chain, error) {
+	switch strings.ToUpper(alias) {
+	case "P":
+		return pChain{}, nil
+	case "X":
+		return xChain{}, nil
+	default:
+		return nil, fmt.Errorf("%w %q", errUnknownChain, alias)
+	}
+}
+
+type pChain struct{}
+
+func (pChain) parse(b []byte) (partialTx, error) {
+	ptx, err := p.ParsePartialTx(b)
+	if err != nil {
+		return nil, err
+	}
+	return pPartialTx{ptx}, nil
+}
+
+func (pChain) merge(ptxs []partialTx) (partialTx, error) {
+	pPtxs := make([]*p.PartialTx, len(ptxs))
+	for i, ptx := range ptxs {
+		pPtxs[i] = ptx.(pPartialTx).PartialTx
+	}
+	merged, err := p.MergePartialTxs(pPtxs...)
+	if err != nil {
+		return nil, err
+	}
+	return pPartialTx{merged}, nil
+}
+
+type pPartialTx struct {
+	*p.PartialTx
+}
+
+func (ptx pPartialTx) txID() ids.ID { return ptx.Tx.ID() }
+
+func (ptx pPartialTx) sign(ctx stdcontext.Context, kc *secp256k1fx.Keychain) error {
+	return p.NewSigner(kc, ptx.PartialTx).Sign(ctx, ptx.Tx)
+}
+
+func (ptx pPartialTx) issue(ctx stdcontext.Context, uri string) (ids.ID, error) {
+	return platformvm.NewClient(uri).IssueTx(ctx, ptx.Tx.Bytes())
+}
+
+type xChain struct{}
+
+func (xChain) parse(b []byte) (partialTx, error) {
+	ptx, err := x.ParsePartialTx(b)
+	if err != nil {
+		return nil, err
+	}
+	return xPartialTx{ptx}, nil
+}
+
+func (xChain) merge(ptxs []partialTx) (partialTx, error) {
+	xPtxs := make([]*x.PartialTx, len(ptxs))
+	for i, ptx := range ptxs {
+		xPtxs[i] = ptx.(xPartialTx).PartialTx
+	}
+	merged, err := x.MergePartialTxs(xPtxs...)
+	if err != nil {
+		return nil, err
+	}
+	return xPartialTx{merged}, nil
+}
+
+type xPartialTx struct {
+	*x.PartialTx
+}
+
+func (ptx xPartialTx) txID() ids.ID { return ptx.Tx.ID() }
+
+func (ptx xPartialTx) sign(ctx stdcontext.Context, kc *secp256k1fx.Keychain) error {
+	return x.NewSigner(kc, ptx.PartialTx).Sign(ctx, ptx.Tx)
+}
+
+func (ptx xPartialTx) issue(ctx stdcontext.Context, uri string) (ids.ID, error) {
+	return avm.NewClient(uri, "X").IssueTx(ctx, ptx.Tx.Bytes())
+}
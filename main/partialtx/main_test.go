@@ -0,0 +1,226 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	stdcontext "context"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dim4egster/qmallgo/database"
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/utils/constants"
+	"github.com/dim4egster/qmallgo/utils/crypto"
+	"github.com/dim4egster/qmallgo/utils/units"
+	"github.com/dim4egster/qmallgo/vms/components/avax"
+	"github.com/dim4egster/qmallgo/vms/secp256k1fx"
+	"github.com/dim4egster/qmallgo/wallet/chain/p"
+	"github.com/dim4egster/qmallgo/wallet/chain/x"
+
+	avmtxs "github.com/dim4egster/qmallgo/vms/avm/txs"
+	pvmtxs "github.com/dim4egster/qmallgo/vms/platformvm/txs"
+)
+
+type testBackend struct {
+	utxo *avax.UTXO
+}
+
+func (b *testBackend) GetUTXO(_ stdcontext.Context, _, utxoID ids.ID) (*avax.UTXO, error) {
+	if b.utxo.InputID() != utxoID {
+		return nil, database.ErrNotFound
+	}
+	return b.utxo, nil
+}
+
+func (*testBackend) GetTx(stdcontext.Context, ids.ID) (*pvmtxs.Tx, error) {
+	return nil, database.ErrNotFound
+}
+
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		chain string
+		// export returns the hex decoded partial tx and the bytes of the tx
+		// signed by all of [kc] in one go.
+		export func(*require.Assertions, *testBackend, []ids.ShortID, *secp256k1fx.Keychain) ([]byte, []byte)
+	}{
+		{
+			chain: "P",
+			export: func(require *require.Assertions, backend *testBackend, addrs []ids.ShortID, kc *secp256k1fx.Keychain) ([]byte, []byte) {
+				utx := &pvmtxs.CreateSubnetTx{
+					BaseTx: pvmtxs.BaseTx{BaseTx: avax.BaseTx{
+						NetworkID:    constants.UnitTestID,
+						BlockchainID: constants.PlatformChainID,
+						Ins:          []*avax.TransferableInput{newInput(backend.utxo)},
+					}},
+					Owner: &secp256k1fx.OutputOwners{
+						Threshold: 1,
+						Addrs:     addrs[:1],
+					},
+				}
+				ptx, err := p.NewPartialTx(stdcontext.Background(), backend, utx)
+				require.NoError(err)
+				ptxBytes, err := ptx.Bytes()
+				require.NoError(err)
+
+				tx, err := p.NewSigner(kc, backend).SignUnsigned(stdcontext.Background(), utx)
+				require.NoError(err)
+				return ptxBytes, tx.Bytes()
+			},
+		},
+		{
+			chain: "X",
+			export: func(require *require.Assertions, backend *testBackend, _ []ids.ShortID, kc *secp256k1fx.Keychain) ([]byte, []byte) {
+				utx := &avmtxs.BaseTx{BaseTx: avax.BaseTx{
+					NetworkID:    constants.UnitTestID,
+					BlockchainID: ids.GenerateTestID(),
+					Ins:          []*avax.TransferableInput{newInput(backend.utxo)},
+				}}
+				ptx, err := x.NewPartialTx(stdcontext.Background(), backend, utx)
+				require.NoError(err)
+				ptxBytes, err := ptx.Bytes()
+				require.NoError(err)
+
+				tx, err := x.NewSigner(kc, backend).SignUnsigned(stdcontext.Background(), utx)
+				require.NoError(err)
+				return ptxBytes, tx.Bytes()
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.chain, func(t *testing.T) {
+			require := require.New(t)
+			ctx := stdcontext.Background()
+			dir := t.TempDir()
+
+			// The funds are held by a 2-of-2 multisig.
+			keys := make([]*crypto.PrivateKeySECP256K1R, 2)
+			addrs := make([]ids.ShortID, len(keys))
+			for i := range keys {
+				keyIntf, err := (&crypto.FactorySECP256K1R{}).NewPrivateKey()
+				require.NoError(err)
+				keys[i] = keyIntf.(*crypto.PrivateKeySECP256K1R)
+				addrs[i] = keys[i].PublicKey().Address()
+			}
+			backend := &testBackend{utxo: &avax.UTXO{
+				UTXOID: avax.UTXOID{TxID: ids.GenerateTestID()},
+				Asset:  avax.Asset{ID: ids.GenerateTestID()},
+				Out: &secp256k1fx.TransferOutput{
+					Amt: units.Avax,
+					OutputOwners: secp256k1fx.OutputOwners{
+						Threshold: 2,
+						Addrs:     addrs,
+					},
+				},
+			}}
+
+			// Export the unsigned tx on the online machine.
+			c, err := getChain(test.chain)
+			require.NoError(err)
+			ptxBytes, expectedTxBytes := test.export(require, backend, addrs, secp256k1fx.NewKeychain(keys...))
+			ptx, err := c.parse(ptxBytes)
+			require.NoError(err)
+			unsignedPath := filepath.Join(dir, "unsigned.tx")
+			require.NoError(writePartialTx(unsignedPath, ptx))
+
+			out := &bytes.Buffer{}
+			require.NoError(run(ctx, []string{statusCommand, "--chain", test.chain, unsignedPath}, out))
+			require.Contains(out.String(), "missing signatures")
+
+			// Each key holder signs the tx offline.
+			signedPaths := make([]string, len(keys))
+			for i, key := range keys {
+				keyPath := filepath.Join(dir, "key"+key.PublicKey().Address().String())
+				require.NoError(os.WriteFile(keyPath, []byte(key.String()+"\n"), 0o600))
+
+				signedPaths[i] = filepath.Join(dir, "signed.tx."+key.PublicKey().Address().String())
+				out.Reset()
+				require.NoError(run(ctx, []string{
+					signCommand,
+					"--chain", test.chain,
+					"--key-file", keyPath,
+					"--out", signedPaths[i],
+					unsignedPath,
+				}, out))
+				require.Contains(out.String(), addrs[i].String()+" signed")
+				require.Contains(out.String(), "missing signatures")
+			}
+
+			// The signatures are merged into a tx that can be issued.
+			mergedPath := filepath.Join(dir, "merged.tx")
+			out.Reset()
+			require.NoError(run(ctx, append([]string{
+				mergeCommand,
+				"--chain", test.chain,
+				"--out", mergedPath,
+			}, signedPaths...), out))
+			require.Contains(out.String(), "fully signed")
+
+			merged, err := readPartialTx(c, mergedPath)
+			require.NoError(err)
+			var mergedTxBytes []byte
+			switch merged := merged.(type) {
+			case pPartialTx:
+				mergedTxBytes = merged.Tx.Bytes()
+			case xPartialTx:
+				mergedTxBytes = merged.Tx.Bytes()
+			}
+			require.Equal(expectedTxBytes, mergedTxBytes)
+		})
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	ctx := stdcontext.Background()
+	tests := []struct {
+		name        string
+		args        []string
+		expectedErr error
+	}{
+		{
+			name:        "no command",
+			expectedErr: errMissingCommand,
+		},
+		{
+			name:        "unknown command",
+			args:        []string{"export"},
+			expectedErr: errUnknownCommand,
+		},
+		{
+			name:        "unknown chain",
+			args:        []string{statusCommand, "--chain", "C", "tx"},
+			expectedErr: errUnknownChain,
+		},
+		{
+			name:        "no files",
+			args:        []string{mergeCommand, "--out", "tx"},
+			expectedErr: errWrongNumArgs,
+		},
+		{
+			name:        "no key file",
+			args:        []string{signCommand, "--out", "signed.tx", "tx"},
+			expectedErr: errMissingFlag,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.ErrorIs(t, run(ctx, test.args, &bytes.Buffer{}), test.expectedErr)
+		})
+	}
+}
+
+func newInput(utxo *avax.UTXO) *avax.TransferableInput {
+	return &avax.TransferableInput{
+		UTXOID: utxo.UTXOID,
+		Asset:  utxo.Asset,
+		In: &secp256k1fx.TransferInput{
+			Amt:   units.Avax,
+			Input: secp256k1fx.Input{SigIndices: []uint32{0, 1}},
+		},
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// signer is the reference external signer of the keystore services. It serves
+// the keys of a password encrypted keychain file, so that the node never loads
+// them.
+//
+// The node launches the signer when it is started with
+// --keystore-external-signer-path, passing it the arguments given with
+// --keystore-external-signer-args. The signer can also be run by hand with
+// --import-keys to create the keychain file.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"github.com/dim4egster/qmallgo/utils/crypto"
+	"github.com/dim4egster/qmallgo/utils/crypto/keychain/filekeychain"
+	"github.com/dim4egster/qmallgo/utils/crypto/keychain/gkeychain"
+)
+
+const (
+	keychainFileKey = "keychain-file"
+	passwordFileKey = "password-file"
+	importKeysKey   = "import-keys"
+
+	// passwordEnvVar holds the password of the keychain file if
+	// --password-file isn't set.
+	passwordEnvVar = "SIGNER_KEYCHAIN_PASSWORD"
+)
+
+var (
+	errMissingKeychainFile = errors.New("keychain file must be specified")
+	errMissingPassword     = fmt.Errorf("either --%s or %s must be set", passwordFileKey, passwordEnvVar)
+)
+
+func main() {
+	fs := pflag.NewFlagSet("signer", pflag.ContinueOnError)
+	fs.String(keychainFileKey, "", "Path to the password encrypted keychain file")
+	fs.String(passwordFileKey, "", fmt.Sprintf("Path to a file holding the password of the keychain file. If empty, the password is read from %s", passwordEnvVar))
+	fs.StringSlice(importKeysKey, nil, "Private keys to encrypt into the keychain file, overwriting it, before exiting. Example: PrivateKey-...")
+
+	if err := fs.Parse(os.Args[1:]); err != nil {
+		if errors.Is(err, pflag.ErrHelp) {
+			os.Exit(0)
+		}
+		// Stdout is reserved for the plugin handshake.
+		fmt.Fprintf(os.Stderr, "couldn't parse flags: %s\n", err)
+		os.Exit(1)
+	}
+
+	if err := run(fs); err != nil {
+		fmt.Fprintf(os.Stderr, "signer failed: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(fs *pflag.FlagSet) error {
+	path, err := fs.GetString(keychainFileKey)
+	if err != nil {
+		return err
+	}
+	if path == "" {
+		return errMissingKeychainFile
+	}
+	path = filepath.Clean(path)
+
+	password, err := readPassword(fs)
+	if err != nil {
+		return err
+	}
+
+	keys, err := fs.GetStringSlice(importKeysKey)
+	if err != nil {
+		return err
+	}
+	if len(keys) != 0 {
+		return importKeys(path, password, keys)
+	}
+
+	kc, err := filekeychain.Load(path, password)
+	if err != nil {
+		return err
+	}
+	gkeychain.Serve(kc)
+	return nil
+}
+
+func readPassword(fs *pflag.FlagSet) (string, error) {
+	passwordFile, err := fs.GetString(passwordFileKey)
+	if err != nil {
+		return "", err
+	}
+	if passwordFile == "" {
+		password, ok := os.LookupEnv(passwordEnvVar)
+		if !ok {
+			return "", errMissingPassword
+		}
+		return password, nil
+	}
+	passwordBytes, err := os.ReadFile(filepath.Clean(passwordFile))
+	if err != nil {
+		return "", fmt.Errorf("couldn't read password file: %w", err)
+	}
+	return strings.TrimSpace(string(passwordBytes)), nil
+}
+
+func importKeys(path string, password string, keyStrs []string) error {
+	keys := make([]*crypto.PrivateKeySECP256K1R, len(keyStrs))
+	for i, keyStr := range keyStrs {
+		keys[i] = &crypto.PrivateKeySECP256K1R{}
+		if err := keys[i].UnmarshalJSON([]byte(strconv.Quote(keyStr))); err != nil {
+			return fmt.Errorf("couldn't parse private key %d: %w", i, err)
+		}
+	}
+	return filekeychain.Save(path, password, keys)
+}
@@ -72,6 +72,7 @@ func TestBuildVersion(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, parsedMsg)
 	require.Equal(t, Version, parsedMsg.Op())
+	require.Equal(t, len(msg.Bytes()), parsedMsg.NumBytes())
 
 	networkIDIntf, err := parsedMsg.Get(NetworkID)
 	require.NoError(t, err)
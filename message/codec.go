@@ -58,10 +58,12 @@ type codec struct {
 
 	clock mockable.Clock
 
-	compressTimeMetrics   map[Op]metric.Averager
-	decompressTimeMetrics map[Op]metric.Averager
-	compressor            compression.Compressor
-	maxMessageTimeout     time.Duration
+	compressTimeMetrics    map[Op]metric.Averager
+	decompressTimeMetrics  map[Op]metric.Averager
+	compressRatioMetrics   map[Op]metric.Averager
+	decompressRatioMetrics map[Op]metric.Averager
+	compressor             compression.Compressor
+	maxMessageTimeout      time.Duration
 }
 
 func NewCodecWithMemoryPool(namespace string, metrics prometheus.Registerer, maxMessageSize int64, maxMessageTimeout time.Duration) (Codec, error) {
@@ -75,10 +77,12 @@ func NewCodecWithMemoryPool(namespace string, metrics prometheus.Registerer, max
 				return make([]byte, 0, constants.DefaultByteSliceCap)
 			},
 		},
-		compressTimeMetrics:   make(map[Op]metric.Averager, len(ExternalOps)),
-		decompressTimeMetrics: make(map[Op]metric.Averager, len(ExternalOps)),
-		compressor:            cpr,
-		maxMessageTimeout:     maxMessageTimeout,
+		compressTimeMetrics:    make(map[Op]metric.Averager, len(ExternalOps)),
+		decompressTimeMetrics:  make(map[Op]metric.Averager, len(ExternalOps)),
+		compressRatioMetrics:   make(map[Op]metric.Averager, len(ExternalOps)),
+		decompressRatioMetrics: make(map[Op]metric.Averager, len(ExternalOps)),
+		compressor:             cpr,
+		maxMessageTimeout:      maxMessageTimeout,
 	}
 
 	errs := wrappers.Errs{}
@@ -101,6 +105,20 @@ func NewCodecWithMemoryPool(namespace string, metrics prometheus.Registerer, max
 			metrics,
 			&errs,
 		)
+		c.compressRatioMetrics[op] = metric.NewAveragerWithErrs(
+			namespace,
+			fmt.Sprintf("%s_compress_ratio", op),
+			fmt.Sprintf("ratio of compressed to uncompressed size of sent %s messages", op),
+			metrics,
+			&errs,
+		)
+		c.decompressRatioMetrics[op] = metric.NewAveragerWithErrs(
+			namespace,
+			fmt.Sprintf("%s_decompress_ratio", op),
+			fmt.Sprintf("ratio of compressed to uncompressed size of received %s messages", op),
+			metrics,
+			&errs,
+		)
 	}
 	return c, errs.Err
 }
@@ -175,6 +193,9 @@ func (c *codec) Pack(
 		return nil, fmt.Errorf("couldn't compress payload of %s message: %w", op, err)
 	}
 	c.compressTimeMetrics[op].Observe(float64(time.Since(startTime)))
+	if len(payloadBytes) != 0 {
+		c.compressRatioMetrics[op].Observe(float64(len(compressedPayloadBytes)) / float64(len(payloadBytes)))
+	}
 	msg.bytesSavedCompression = len(payloadBytes) - len(compressedPayloadBytes) // may be negative
 	// Remove the uncompressed payload (keep just the message type and isCompressed)
 	msg.bytes = msg.bytes[:wrappers.BoolLen+wrappers.ByteLen]
@@ -218,6 +239,9 @@ func (c *codec) Parse(bytes []byte, nodeID ids.NodeID, onFinishedHandling func()
 			return nil, fmt.Errorf("couldn't decompress payload of %s message: %w", op, err)
 		}
 		c.decompressTimeMetrics[op].Observe(float64(time.Since(startTime)))
+		if len(payloadBytes) != 0 {
+			c.decompressRatioMetrics[op].Observe(float64(len(compressedPayloadBytes)) / float64(len(payloadBytes)))
+		}
 		// Replace the compressed payload with the decompressed payload.
 		// Remove the compressed payload and isCompressed; keep just the message type
 		p.Bytes = p.Bytes[:wrappers.ByteLen]
@@ -255,6 +279,7 @@ func (c *codec) Parse(bytes []byte, nodeID ids.NodeID, onFinishedHandling func()
 		inboundMessage: inboundMessage{
 			op:                    op,
 			bytesSavedCompression: bytesSaved,
+			numBytes:              len(bytes),
 			nodeID:                nodeID,
 			expirationTime:        expirationTime,
 			onFinishedHandling:    onFinishedHandling,
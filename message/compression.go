@@ -0,0 +1,66 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package message
+
+import (
+	"github.com/dim4egster/qmallgo/utils/compression"
+)
+
+// PeerCompression describes the compression types that a peer is able to
+// decompress, as advertised in its Version message.
+//
+// The zero value describes a peer that only supports gzip.
+type PeerCompression struct {
+	// Types the peer is able to decompress. [compression.TypeNone] and
+	// [compression.TypeGzip] are supported by every peer, regardless of
+	// whether they are included.
+	Types []compression.Type
+	// ZstdDictionaryID is the ID of the zstd dictionary that the peer is able
+	// to decompress with, or 0 if the peer doesn't have a dictionary.
+	ZstdDictionaryID uint32
+}
+
+// Supports returns true if the peer is able to decompress messages compressed
+// with [compressionType].
+func (p PeerCompression) Supports(compressionType compression.Type) bool {
+	switch compressionType {
+	case compression.TypeNone, compression.TypeGzip:
+		return true
+	}
+	for _, t := range p.Types {
+		if t == compressionType {
+			return true
+		}
+	}
+	return false
+}
+
+// Recompress returns [msg] compressed with a compression type that [peer] is
+// able to decompress. If [peer] can already decompress [msg], [msg] is
+// returned.
+func Recompress(msg OutboundMessage, peer PeerCompression) (OutboundMessage, error) {
+	protoMsg, ok := msg.(*outboundMessageWithProto)
+	if !ok {
+		// Messages built with the packer are only ever compressed with gzip.
+		return msg, nil
+	}
+	recompressedMsg, err := protoMsg.builder.recompress(protoMsg, peer)
+	if err != nil {
+		return nil, err
+	}
+	return recompressedMsg, nil
+}
+
+// usesZstdDictionary returns true if messages of type [op] should be
+// compressed with the zstd dictionary, if one is configured. These are the
+// messages that carry containers, whose encodings share enough structure to
+// benefit from a dictionary.
+func usesZstdDictionary(op Op) bool {
+	switch op {
+	case Put, Ancestors, PushQuery:
+		return true
+	default:
+		return false
+	}
+}
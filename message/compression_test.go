@@ -0,0 +1,151 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package message
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/utils/compression"
+	"github.com/dim4egster/qmallgo/utils/units"
+
+	p2ppb "github.com/dim4egster/qmallgo/proto/pb/p2p"
+)
+
+func TestPeerCompressionSupports(t *testing.T) {
+	require := require.New(t)
+
+	legacy := PeerCompression{}
+	require.True(legacy.Supports(compression.TypeNone))
+	require.True(legacy.Supports(compression.TypeGzip))
+	require.False(legacy.Supports(compression.TypeZstd))
+
+	zstd := PeerCompression{Types: []compression.Type{compression.TypeZstd}}
+	require.True(zstd.Supports(compression.TypeGzip))
+	require.True(zstd.Supports(compression.TypeZstd))
+}
+
+func newTestPutMessage(chainID ids.ID) *p2ppb.Message {
+	return &p2ppb.Message{
+		Message: &p2ppb.Message_Put{
+			Put: &p2ppb.Put{
+				ChainId:   chainID[:],
+				RequestId: 12345,
+				Container: bytes.Repeat([]byte("container bytes "), 16),
+			},
+		},
+	}
+}
+
+func TestZstdDictionaryRoundTrip(t *testing.T) {
+	require := require.New(t)
+
+	dictionary := bytes.Repeat([]byte("container bytes "), 64)
+	sender, err := newMsgBuilderProtobuf("test", prometheus.NewRegistry(), units.MiB, dictionary, 5*time.Second)
+	require.NoError(err)
+	receiver, err := newMsgBuilderProtobuf("test", prometheus.NewRegistry(), units.MiB, dictionary, 5*time.Second)
+	require.NoError(err)
+
+	chainID := ids.GenerateTestID()
+	outMsg, err := sender.createOutbound(Put, newTestPutMessage(chainID), compression.TypeZstd, false)
+	require.NoError(err)
+	require.Equal(compression.TypeZstd, outMsg.compressionType)
+	require.Equal(compression.ZstdDictionaryID(dictionary), outMsg.zstdDictionaryID)
+	require.Positive(outMsg.BytesSavedCompression())
+
+	inMsg, err := receiver.parseInbound(outMsg.Bytes(), ids.EmptyNodeID, func() {})
+	require.NoError(err)
+	require.Equal(Put, inMsg.Op())
+
+	container, err := inMsg.Get(ContainerBytes)
+	require.NoError(err)
+	require.Equal(bytes.Repeat([]byte("container bytes "), 16), container)
+
+	// Messages that don't carry containers aren't compressed with the
+	// dictionary.
+	outMsg, err = sender.createOutbound(
+		AppGossip,
+		&p2ppb.Message{
+			Message: &p2ppb.Message_AppGossip{
+				AppGossip: &p2ppb.AppGossip{
+					ChainId:  chainID[:],
+					AppBytes: bytes.Repeat([]byte{0}, 100),
+				},
+			},
+		},
+		compression.TypeZstd,
+		false,
+	)
+	require.NoError(err)
+	require.Equal(compression.TypeZstd, outMsg.compressionType)
+	require.Zero(outMsg.zstdDictionaryID)
+}
+
+func TestRecompress(t *testing.T) {
+	require := require.New(t)
+
+	dictionary := bytes.Repeat([]byte("container bytes "), 64)
+	sender, err := newMsgBuilderProtobuf("test", prometheus.NewRegistry(), units.MiB, dictionary, 5*time.Second)
+	require.NoError(err)
+	receiver, err := newMsgBuilderProtobuf("test", prometheus.NewRegistry(), units.MiB, nil, 5*time.Second)
+	require.NoError(err)
+
+	chainID := ids.GenerateTestID()
+	outMsg, err := sender.createOutbound(Put, newTestPutMessage(chainID), compression.TypeZstd, true)
+	require.NoError(err)
+
+	tests := []struct {
+		desc             string
+		peer             PeerCompression
+		expectedType     compression.Type
+		expectedDictID   uint32
+		expectedSameMsgs bool
+	}{
+		{
+			desc:             "peer with dictionary",
+			peer:             PeerCompression{Types: compression.Types, ZstdDictionaryID: outMsg.zstdDictionaryID},
+			expectedType:     compression.TypeZstd,
+			expectedDictID:   outMsg.zstdDictionaryID,
+			expectedSameMsgs: true,
+		},
+		{
+			desc:         "peer without dictionary",
+			peer:         PeerCompression{Types: compression.Types},
+			expectedType: compression.TypeZstd,
+		},
+		{
+			desc:         "legacy peer",
+			peer:         PeerCompression{},
+			expectedType: compression.TypeGzip,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			msg, err := Recompress(outMsg, test.peer)
+			require.NoError(err)
+			require.Equal(test.expectedSameMsgs, msg == OutboundMessage(outMsg))
+			require.True(msg.BypassThrottling())
+			require.Equal(Put, msg.Op())
+
+			protoMsg := msg.(*outboundMessageWithProto)
+			require.Equal(test.expectedType, protoMsg.compressionType)
+			require.Equal(test.expectedDictID, protoMsg.zstdDictionaryID)
+
+			if test.expectedDictID != 0 {
+				return
+			}
+
+			// A peer without the dictionary must be able to parse the message.
+			inMsg, err := receiver.parseInbound(msg.Bytes(), ids.EmptyNodeID, func() {})
+			require.NoError(err)
+			require.Equal(Put, inMsg.Op())
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 
+	"github.com/dim4egster/qmallgo/utils/compression"
 	"github.com/dim4egster/qmallgo/utils/constants"
 )
 
@@ -39,15 +40,25 @@ func NewCreator(metrics prometheus.Registerer, parentNamespace string, compressi
 	}, nil
 }
 
-func NewCreatorWithProto(metrics prometheus.Registerer, parentNamespace string, compressionEnabled bool, maxInboundMessageTimeout time.Duration) (Creator, error) {
+// NewCreatorWithProto returns a Creator that builds protobuf messages. Outbound
+// messages are compressed with [compressionType]. If [zstdDictionary] is
+// non-empty, it is used when compressing container carrying messages with
+// zstd.
+func NewCreatorWithProto(
+	metrics prometheus.Registerer,
+	parentNamespace string,
+	compressionType compression.Type,
+	zstdDictionary []byte,
+	maxInboundMessageTimeout time.Duration,
+) (Creator, error) {
 	// different namespace, not to be in conflict with packer
 	namespace := fmt.Sprintf("%s_proto_codec", parentNamespace)
-	builder, err := newMsgBuilderProtobuf(namespace, metrics, int64(constants.DefaultMaxMessageSize), maxInboundMessageTimeout)
+	builder, err := newMsgBuilderProtobuf(namespace, metrics, int64(constants.DefaultMaxMessageSize), zstdDictionary, maxInboundMessageTimeout)
 	if err != nil {
 		return nil, err
 	}
 	return &creator{
-		OutboundMsgBuilder: newOutboundBuilderWithProto(compressionEnabled, builder),
+		OutboundMsgBuilder: newOutboundBuilderWithProto(compressionType, builder),
 		InboundMsgBuilder:  newInboundBuilderWithProto(builder),
 		InternalMsgBuilder: NewInternalBuilder(),
 	}, nil
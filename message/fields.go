@@ -12,29 +12,31 @@ type Field uint32
 
 // Fields that may be packed. These values are not sent over the wire.
 const (
-	VersionStr          Field = iota // Used in handshake
-	NetworkID                        // Used in handshake
-	NodeID                           // TODO: remove NodeID. Used in handshake
-	MyTime                           // Used in handshake
-	IP                               // Used in handshake
-	ChainID                          // Used for dispatching
-	RequestID                        // Used for all messages
-	Deadline                         // Used for request messages
-	ContainerID                      // Used for querying
-	ContainerBytes                   // Used for gossiping
-	ContainerIDs                     // Used for querying
-	MultiContainerBytes              // Used in Ancestors
-	SigBytes                         // Used in handshake / peer gossiping
-	VersionTime                      // Used in handshake / peer gossiping
-	Peers                            // Used in peer gossiping
-	TrackedSubnets                   // Used in handshake / peer gossiping
-	AppBytes                         // Used at application level
-	VMMessage                        // Used internally
-	Uptime                           // Used for Pong
-	SummaryBytes                     // Used for state sync
-	SummaryHeights                   // Used for state sync
-	SummaryIDs                       // Used for state sync
-	VersionStruct                    // Used internally
+	VersionStr            Field = iota // Used in handshake
+	NetworkID                          // Used in handshake
+	NodeID                             // TODO: remove NodeID. Used in handshake
+	MyTime                             // Used in handshake
+	IP                                 // Used in handshake
+	ChainID                            // Used for dispatching
+	RequestID                          // Used for all messages
+	Deadline                           // Used for request messages
+	ContainerID                        // Used for querying
+	ContainerBytes                     // Used for gossiping
+	ContainerIDs                       // Used for querying
+	MultiContainerBytes                // Used in Ancestors
+	SigBytes                           // Used in handshake / peer gossiping
+	VersionTime                        // Used in handshake / peer gossiping
+	Peers                              // Used in peer gossiping
+	TrackedSubnets                     // Used in handshake / peer gossiping
+	AppBytes                           // Used at application level
+	VMMessage                          // Used internally
+	Uptime                             // Used for Pong
+	SummaryBytes                       // Used for state sync
+	SummaryHeights                     // Used for state sync
+	SummaryIDs                         // Used for state sync
+	VersionStruct                      // Used internally
+	SupportedCompressions              // Used in handshake
+	ZstdDictionaryID                   // Used in handshake
 )
 
 // Packer returns the packer function that can be used to pack this field.
@@ -145,6 +147,10 @@ func (f Field) String() string {
 		return "SummaryIDs"
 	case VersionStruct:
 		return "VersionStruct"
+	case SupportedCompressions:
+		return "SupportedCompressions"
+	case ZstdDictionaryID:
+		return "ZstdDictionaryID"
 	default:
 		return "Unknown Field"
 	}
@@ -19,7 +19,7 @@ func Test_newInboundBuilderWithProto(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
 
-	mb, err := newMsgBuilderProtobuf("test", prometheus.NewRegistry(), int64(constants.DefaultMaxMessageSize), 5*time.Second)
+	mb, err := newMsgBuilderProtobuf("test", prometheus.NewRegistry(), int64(constants.DefaultMaxMessageSize), nil, 5*time.Second)
 	require.NoError(err)
 
 	builder := newInboundBuilderWithProto(mb)
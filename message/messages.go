@@ -4,9 +4,11 @@
 package message
 
 import (
+	"context"
 	"crypto/x509"
 	"errors"
 	"fmt"
+	"math"
 	"net"
 	"strings"
 	"sync"
@@ -33,6 +35,8 @@ var (
 	_ OutboundMessage = &outboundMessageWithProto{}
 
 	errUnknownMessageTypeForOp = errors.New("unknown message type for Op")
+	errUnknownCompressionType  = errors.New("unknown compression type")
+	errUnexpectedCompression   = errors.New("unexpected compression of message")
 
 	errInvalidIPAddrLen = errors.New("invalid IP address field length (expected 16-byte)")
 	errInvalidCert      = errors.New("invalid TLS certificate field")
@@ -43,16 +47,22 @@ type InboundMessage interface {
 	fmt.Stringer
 
 	BytesSavedCompression() int
+	NumBytes() int
 	Op() Op
 	Get(Field) (interface{}, error)
 	NodeID() ids.NodeID
 	ExpirationTime() time.Time
 	OnFinishedHandling()
+
+	// Context returns the context this message is being handled in. If the
+	// message is being traced, the context carries the message's span.
+	Context() context.Context
 }
 
 type inboundMessage struct {
 	op                    Op
 	bytesSavedCompression int
+	numBytes              int
 	nodeID                ids.NodeID
 	expirationTime        time.Time
 	onFinishedHandling    func()
@@ -69,6 +79,10 @@ func (inMsg *inboundMessage) BytesSavedCompression() int {
 	return inMsg.bytesSavedCompression
 }
 
+// NumBytes returns the number of bytes this message was received in over the
+// network. 0 for messages that weren't received over the network.
+func (inMsg *inboundMessage) NumBytes() int { return inMsg.numBytes }
+
 // NodeID returns the node that the msg was sent by.
 func (inMsg *inboundMessage) NodeID() ids.NodeID { return inMsg.nodeID }
 
@@ -84,6 +98,29 @@ func (inMsg *inboundMessage) OnFinishedHandling() {
 	}
 }
 
+// Context returns the background context. Use [InboundWithContext] to attach
+// a context to a message.
+func (*inboundMessage) Context() context.Context { return context.Background() }
+
+type inboundMessageWithContext struct {
+	InboundMessage
+
+	ctx context.Context
+}
+
+// InboundWithContext returns [msg] with its context replaced by [ctx].
+func InboundWithContext(ctx context.Context, msg InboundMessage) InboundMessage {
+	if msgWithContext, ok := msg.(*inboundMessageWithContext); ok {
+		msg = msgWithContext.InboundMessage
+	}
+	return &inboundMessageWithContext{
+		InboundMessage: msg,
+		ctx:            ctx,
+	}
+}
+
+func (inMsg *inboundMessageWithContext) Context() context.Context { return inMsg.ctx }
+
 type inboundMessageWithPacker struct {
 	inboundMessage
 
@@ -184,6 +221,19 @@ func getField(m *p2ppb.Message, field Field) (interface{}, error) {
 			return msg.Sig, nil
 		case TrackedSubnets:
 			return msg.TrackedSubnets, nil
+		case SupportedCompressions:
+			// Unknown compression types may be advertised by peers running
+			// newer versions, so they are ignored rather than rejected.
+			supported := make([]compression.Type, 0, len(msg.SupportedCompressions))
+			for _, t := range msg.SupportedCompressions {
+				compressionType := compression.Type(t)
+				if t <= math.MaxUint8 && compressionType.Valid() {
+					supported = append(supported, compressionType)
+				}
+			}
+			return supported, nil
+		case ZstdDictionaryID:
+			return msg.ZstdDictionaryId, nil
 		}
 
 	case *p2ppb.Message_PeerList:
@@ -507,39 +557,81 @@ type outboundMessageWithProto struct {
 	outboundMessage
 
 	msg *p2ppb.Message
+
+	// builder is the builder that created this message. It is used to
+	// recompress the message for peers that can't decompress it.
+	builder *msgBuilderProtobuf
+	// compressionType is the compression type that [bytes] were compressed
+	// with.
+	compressionType compression.Type
+	// zstdDictionaryID is the ID of the dictionary that [bytes] were
+	// compressed with, or 0 if no dictionary was used.
+	zstdDictionaryID uint32
+	// uncompressedBytes is only populated if [compressionType] isn't supported
+	// by every peer, so that the message can be recompressed.
+	uncompressedBytes []byte
 }
 
 func (outMsg *outboundMessageWithProto) AddRef()       {}
 func (outMsg *outboundMessageWithProto) DecRef()       {}
 func (outMsg *outboundMessageWithProto) IsProto() bool { return true }
 
-// TODO: add other compression algorithms with extended interface
 type msgBuilderProtobuf struct {
 	gzipCompressor compression.Compressor
-	clock          mockable.Clock
-
-	compressTimeMetrics   map[Op]metric.Averager
-	decompressTimeMetrics map[Op]metric.Averager
+	zstdCompressor compression.Compressor
+	// zstdDictCompressor compresses messages using the configured zstd
+	// dictionary. It is nil if no dictionary was provided.
+	zstdDictCompressor compression.Compressor
+	zstdDictionaryID   uint32
+	clock              mockable.Clock
+
+	compressTimeMetrics    map[Op]metric.Averager
+	decompressTimeMetrics  map[Op]metric.Averager
+	compressRatioMetrics   map[Op]metric.Averager
+	decompressRatioMetrics map[Op]metric.Averager
 
 	maxMessageTimeout time.Duration
 }
 
 // NOTE: the metrics registration paths are the same as "NewCodecWithMemoryPool"!
 // To avoid conflicts, use the different namespace if created at the same time.
-func newMsgBuilderProtobuf(namespace string, metrics prometheus.Registerer, maxMessageSize int64, maxMessageTimeout time.Duration) (*msgBuilderProtobuf, error) {
-	cpr, err := compression.NewGzipCompressor(maxMessageSize)
+//
+// If [zstdDictionary] is non-empty, it is used to compress the payloads of
+// container carrying messages with zstd.
+func newMsgBuilderProtobuf(
+	namespace string,
+	metrics prometheus.Registerer,
+	maxMessageSize int64,
+	zstdDictionary []byte,
+	maxMessageTimeout time.Duration,
+) (*msgBuilderProtobuf, error) {
+	gzipCompressor, err := compression.NewGzipCompressor(maxMessageSize)
+	if err != nil {
+		return nil, err
+	}
+	zstdCompressor, err := compression.NewZstdCompressor(maxMessageSize)
 	if err != nil {
 		return nil, err
 	}
 
 	mb := &msgBuilderProtobuf{
-		gzipCompressor: cpr,
+		gzipCompressor: gzipCompressor,
+		zstdCompressor: zstdCompressor,
 
-		compressTimeMetrics:   make(map[Op]metric.Averager, len(ExternalOps)),
-		decompressTimeMetrics: make(map[Op]metric.Averager, len(ExternalOps)),
+		compressTimeMetrics:    make(map[Op]metric.Averager, len(ExternalOps)),
+		decompressTimeMetrics:  make(map[Op]metric.Averager, len(ExternalOps)),
+		compressRatioMetrics:   make(map[Op]metric.Averager, len(ExternalOps)),
+		decompressRatioMetrics: make(map[Op]metric.Averager, len(ExternalOps)),
 
 		maxMessageTimeout: maxMessageTimeout,
 	}
+	if len(zstdDictionary) > 0 {
+		mb.zstdDictCompressor, err = compression.NewZstdCompressorWithDictionary(maxMessageSize, zstdDictionary)
+		if err != nil {
+			return nil, err
+		}
+		mb.zstdDictionaryID = compression.ZstdDictionaryID(zstdDictionary)
+	}
 
 	errs := wrappers.Errs{}
 	for _, op := range ExternalOps {
@@ -561,47 +653,133 @@ func newMsgBuilderProtobuf(namespace string, metrics prometheus.Registerer, maxM
 			metrics,
 			&errs,
 		)
+		mb.compressRatioMetrics[op] = metric.NewAveragerWithErrs(
+			namespace,
+			fmt.Sprintf("%s_compress_ratio", op),
+			fmt.Sprintf("ratio of compressed to uncompressed size of sent %s messages", op),
+			metrics,
+			&errs,
+		)
+		mb.decompressRatioMetrics[op] = metric.NewAveragerWithErrs(
+			namespace,
+			fmt.Sprintf("%s_decompress_ratio", op),
+			fmt.Sprintf("ratio of compressed to uncompressed size of received %s messages", op),
+			metrics,
+			&errs,
+		)
 	}
 	return mb, errs.Err
 }
 
-// NOTE THAT the passed message must be verified beforehand.
-// NOTE THAT the passed message will be modified if compression is enabled.
-// TODO: find a way to not in-place modify the message
-func (mb *msgBuilderProtobuf) marshal(m *p2ppb.Message, gzipCompress bool) ([]byte, int, time.Duration, error) {
-	uncompressedMsgBytes, err := proto.Marshal(m)
-	if err != nil {
-		return nil, 0, 0, err
+// supportedCompressions returns the compression types that this builder is
+// able to decompress, as advertised in the Version message.
+func (mb *msgBuilderProtobuf) supportedCompressions() []uint32 {
+	supported := make([]uint32, len(compression.Types))
+	for i, compressionType := range compression.Types {
+		supported[i] = uint32(compressionType)
+	}
+	return supported
+}
+
+// compress returns an outbound message whose bytes are [uncompressedBytes]
+// compressed with [compressionType]. [uncompressedBytes] must be the
+// marshalled bytes of [msg].
+//
+// The compressed bytes are wrapped in another message, rather than adding a
+// compression field to every message.
+func (mb *msgBuilderProtobuf) compress(
+	op Op,
+	msg *p2ppb.Message,
+	uncompressedBytes []byte,
+	compressionType compression.Type,
+	useDictionary bool,
+	bypassThrottling bool,
+) (*outboundMessageWithProto, error) {
+	outMsg := &outboundMessageWithProto{
+		outboundMessage: outboundMessage{
+			op:               op,
+			bytes:            uncompressedBytes,
+			bypassThrottling: bypassThrottling,
+		},
+		msg:             msg,
+		builder:         mb,
+		compressionType: compression.TypeNone,
 	}
 
-	if !gzipCompress {
-		return uncompressedMsgBytes, 0, 0, nil
+	var compressor compression.Compressor
+	switch compressionType {
+	case compression.TypeNone:
+		return outMsg, nil
+	case compression.TypeGzip:
+		compressor = mb.gzipCompressor
+	case compression.TypeZstd:
+		compressor = mb.zstdCompressor
+		if useDictionary && mb.zstdDictCompressor != nil {
+			compressor = mb.zstdDictCompressor
+			outMsg.zstdDictionaryID = mb.zstdDictionaryID
+		}
+		// Peers running older versions are unable to decompress zstd, so the
+		// uncompressed bytes are kept around to recompress the message.
+		outMsg.uncompressedBytes = uncompressedBytes
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnknownCompressionType, compressionType)
 	}
 
-	// If compression is enabled, we marshal twice:
-	// 1. the original message
-	// 2. the message with compressed bytes
-	//
-	// This recursive packing allows us to avoid an extra compression on/off
-	// field in the message.
 	startTime := time.Now()
-	compressedBytes, err := mb.gzipCompressor.Compress(uncompressedMsgBytes)
+	compressedBytes, err := compressor.Compress(uncompressedBytes)
 	if err != nil {
-		return nil, 0, 0, err
+		return nil, err
 	}
 	compressTook := time.Since(startTime)
 
-	// Original message can be discarded for the compressed message.
-	m.Message = &p2ppb.Message_CompressedGzip{
-		CompressedGzip: compressedBytes,
+	compressedMsg := &p2ppb.Message{}
+	if compressionType == compression.TypeGzip {
+		compressedMsg.Message = &p2ppb.Message_CompressedGzip{
+			CompressedGzip: compressedBytes,
+		}
+	} else {
+		compressedMsg.Message = &p2ppb.Message_CompressedZstd{
+			CompressedZstd: compressedBytes,
+		}
 	}
-	compressedMsgBytes, err := proto.Marshal(m)
+	compressedMsgBytes, err := proto.Marshal(compressedMsg)
 	if err != nil {
-		return nil, 0, 0, err
+		return nil, err
 	}
 
-	bytesSaved := len(uncompressedMsgBytes) - len(compressedMsgBytes)
-	return compressedMsgBytes, bytesSaved, compressTook, nil
+	mb.compressTimeMetrics[op].Observe(float64(compressTook))
+	mb.compressRatioMetrics[op].Observe(float64(len(compressedMsgBytes)) / float64(len(uncompressedBytes)))
+
+	outMsg.msg = compressedMsg
+	outMsg.bytes = compressedMsgBytes
+	outMsg.bytesSavedCompression = len(uncompressedBytes) - len(compressedMsgBytes)
+	outMsg.compressionType = compressionType
+	return outMsg, nil
+}
+
+// recompress returns [msg] compressed with a compression type that [peer] is
+// able to decompress. If [peer] can already decompress [msg], [msg] is
+// returned.
+func (mb *msgBuilderProtobuf) recompress(msg *outboundMessageWithProto, peer PeerCompression) (*outboundMessageWithProto, error) {
+	if peer.Supports(msg.compressionType) &&
+		(msg.zstdDictionaryID == 0 || msg.zstdDictionaryID == peer.ZstdDictionaryID) {
+		return msg, nil
+	}
+
+	// If the peer supports zstd, the message was compressed with a dictionary
+	// the peer doesn't have.
+	compressionType := compression.TypeGzip
+	if peer.Supports(compression.TypeZstd) {
+		compressionType = compression.TypeZstd
+	}
+	return mb.compress(
+		msg.op,
+		nil,
+		msg.uncompressedBytes,
+		compressionType,
+		false,
+		msg.bypassThrottling,
+	)
 }
 
 func (mb *msgBuilderProtobuf) unmarshal(b []byte) (Op, *p2ppb.Message, bool, int, time.Duration, error) {
@@ -610,15 +788,30 @@ func (mb *msgBuilderProtobuf) unmarshal(b []byte) (Op, *p2ppb.Message, bool, int
 		return 0, nil, false, 0, 0, err
 	}
 
-	compressed := m.GetCompressedGzip()
-	if len(compressed) == 0 {
+	var (
+		compressed   []byte
+		decompressor compression.Compressor
+	)
+	switch {
+	case len(m.GetCompressedGzip()) != 0:
+		compressed = m.GetCompressedGzip()
+		decompressor = mb.gzipCompressor
+	case len(m.GetCompressedZstd()) != 0:
+		compressed = m.GetCompressedZstd()
+		// The dictionary compressor is able to decompress messages compressed
+		// both with and without the dictionary.
+		decompressor = mb.zstdCompressor
+		if mb.zstdDictCompressor != nil {
+			decompressor = mb.zstdDictCompressor
+		}
+	default:
 		// The message wasn't compressed
 		op, err := msgToOp(m)
 		return op, m, false, 0, 0, err
 	}
 
 	startTime := time.Now()
-	decompressed, err := mb.gzipCompressor.Decompress(compressed)
+	decompressed, err := decompressor.Decompress(compressed)
 	if err != nil {
 		return 0, nil, true, 0, 0, err
 	}
@@ -629,8 +822,15 @@ func (mb *msgBuilderProtobuf) unmarshal(b []byte) (Op, *p2ppb.Message, bool, int
 	}
 
 	op, err := msgToOp(m)
+	if err != nil {
+		return 0, nil, true, 0, 0, err
+	}
+	if !op.Compressible() {
+		return 0, nil, true, 0, 0, fmt.Errorf("%w: %s", errUnexpectedCompression, op)
+	}
+
 	bytesSavedCompression := len(decompressed) - len(compressed)
-	return op, m, true, bytesSavedCompression, decompressTook, err
+	return op, m, true, bytesSavedCompression, decompressTook, nil
 }
 
 func msgToOp(m *p2ppb.Message) (Op, error) {
@@ -684,26 +884,24 @@ func msgToOp(m *p2ppb.Message) (Op, error) {
 	}
 }
 
-// NOTE THAT the passed message will be updated if compression is enabled.
-// TODO: find a way to not in-place modify the message
-func (mb *msgBuilderProtobuf) createOutbound(op Op, msg *p2ppb.Message, gzipCompress bool, bypassThrottling bool) (*outboundMessageWithProto, error) {
-	b, saved, compressTook, err := mb.marshal(msg, gzipCompress)
+// createOutbound returns an outbound message containing [msg]. If [op] is
+// compressible, the message is compressed with [compressionType].
+func (mb *msgBuilderProtobuf) createOutbound(op Op, msg *p2ppb.Message, compressionType compression.Type, bypassThrottling bool) (*outboundMessageWithProto, error) {
+	uncompressedBytes, err := proto.Marshal(msg)
 	if err != nil {
 		return nil, err
 	}
-	if gzipCompress {
-		mb.compressTimeMetrics[op].Observe(float64(compressTook))
+	if !op.Compressible() {
+		compressionType = compression.TypeNone
 	}
-
-	return &outboundMessageWithProto{
-		outboundMessage: outboundMessage{
-			op:                    op,
-			bytes:                 b,
-			bytesSavedCompression: saved,
-			bypassThrottling:      bypassThrottling,
-		},
-		msg: msg,
-	}, nil
+	return mb.compress(
+		op,
+		msg,
+		uncompressedBytes,
+		compressionType,
+		usesZstdDictionary(op),
+		bypassThrottling,
+	)
 }
 
 func (mb *msgBuilderProtobuf) parseInbound(bytes []byte, nodeID ids.NodeID, onFinishedHandling func()) (*inboundMessageWithProto, error) {
@@ -713,6 +911,7 @@ func (mb *msgBuilderProtobuf) parseInbound(bytes []byte, nodeID ids.NodeID, onFi
 	}
 	if wasCompressed {
 		mb.decompressTimeMetrics[op].Observe(float64(decompressTook))
+		mb.decompressRatioMetrics[op].Observe(float64(len(bytes)) / float64(len(bytes)+bytesSavedCompression))
 	}
 
 	var expirationTime time.Time
@@ -728,6 +927,7 @@ func (mb *msgBuilderProtobuf) parseInbound(bytes []byte, nodeID ids.NodeID, onFi
 		inboundMessage: inboundMessage{
 			op:                    op,
 			bytesSavedCompression: bytesSavedCompression,
+			numBytes:              len(bytes),
 			nodeID:                nodeID,
 			expirationTime:        expirationTime,
 			onFinishedHandling:    onFinishedHandling,
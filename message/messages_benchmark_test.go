@@ -16,6 +16,7 @@ import (
 	"google.golang.org/protobuf/proto"
 
 	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/utils/compression"
 	"github.com/dim4egster/qmallgo/utils/ips"
 	"github.com/dim4egster/qmallgo/utils/units"
 
@@ -94,7 +95,7 @@ func BenchmarkMarshalVersion(b *testing.B) {
 	useProto := os.Getenv("USE_PROTO") != ""
 	useProtoBuilder := os.Getenv("USE_PROTO_BUILDER") != ""
 
-	protoCodec, err := newMsgBuilderProtobuf("", prometheus.NewRegistry(), 2*units.MiB, 10*time.Second)
+	protoCodec, err := newMsgBuilderProtobuf("", prometheus.NewRegistry(), 2*units.MiB, nil, 10*time.Second)
 	require.NoError(err)
 
 	b.Logf("marshaling packer %d-byte, proto %d-byte (use proto %v, use proto builder %v)", packerMsgN, protoMsgN, useProto, useProtoBuilder)
@@ -109,7 +110,7 @@ func BenchmarkMarshalVersion(b *testing.B) {
 		}
 
 		if useProtoBuilder {
-			_, err = protoCodec.createOutbound(inboundMsg.op, &protoMsg, false, false)
+			_, err = protoCodec.createOutbound(inboundMsg.op, &protoMsg, compression.TypeNone, false)
 		} else {
 			_, err = proto.Marshal(&protoMsg)
 		}
@@ -185,7 +186,7 @@ func BenchmarkUnmarshalVersion(b *testing.B) {
 	}
 
 	useProtoBuilder := os.Getenv("USE_PROTO_BUILDER") != ""
-	protoCodec, err := newMsgBuilderProtobuf("", prometheus.NewRegistry(), 2*units.MiB, 10*time.Second)
+	protoCodec, err := newMsgBuilderProtobuf("", prometheus.NewRegistry(), 2*units.MiB, nil, 10*time.Second)
 	require.NoError(err)
 
 	b.StartTimer()
@@ -18,6 +18,7 @@ import (
 
 	"github.com/dim4egster/qmallgo/ids"
 	"github.com/dim4egster/qmallgo/staking"
+	"github.com/dim4egster/qmallgo/utils/compression"
 	"github.com/dim4egster/qmallgo/utils/ips"
 	"github.com/dim4egster/qmallgo/utils/units"
 
@@ -142,14 +143,15 @@ func TestProtoMarshalSizeAncestors(t *testing.T) {
 		"test",
 		prometheus.NewRegistry(),
 		units.MiB,
+		nil,
 		5*time.Second,
 	)
 	require.NoError(err)
 
-	b, _, _, err := mb.marshal(&protoMsg, compressible)
+	outMsg, err := mb.createOutbound(inboundMsg.op, &protoMsg, compression.TypeGzip, false)
 	require.NoError(err)
 
-	protoMsgN := len(b)
+	protoMsgN := len(outMsg.Bytes())
 	t.Logf("marshaled; packer %d-byte, proto %d-byte", packerMsgN, protoMsgN)
 
 	require.GreaterOrEqual(packerMsgN, protoMsgN)
@@ -164,6 +166,7 @@ func TestNewOutboundInboundMessageWithProto(t *testing.T) {
 		"test",
 		prometheus.NewRegistry(),
 		units.MiB,
+		nil,
 		5*time.Second,
 	)
 	require.NoError(err)
@@ -187,7 +190,7 @@ func TestNewOutboundInboundMessageWithProto(t *testing.T) {
 		desc                string
 		op                  Op
 		msg                 *p2ppb.Message
-		gzipCompress        bool
+		compressionType     compression.Type
 		bypassThrottling    bool
 		bytesSaved          bool                  // if true, outbound message saved bytes must be non-zero
 		expectedOutboundErr error                 // expected error for creating outbound message
@@ -204,7 +207,7 @@ func TestNewOutboundInboundMessageWithProto(t *testing.T) {
 					},
 				},
 			},
-			gzipCompress:        false,
+			compressionType:     compression.TypeNone,
 			bypassThrottling:    true,
 			bytesSaved:          false,
 			expectedOutboundErr: nil,
@@ -221,7 +224,7 @@ func TestNewOutboundInboundMessageWithProto(t *testing.T) {
 					Ping: &p2ppb.Ping{},
 				},
 			},
-			gzipCompress:        false,
+			compressionType:     compression.TypeNone,
 			bypassThrottling:    true,
 			bytesSaved:          false,
 			expectedOutboundErr: nil,
@@ -240,7 +243,7 @@ func TestNewOutboundInboundMessageWithProto(t *testing.T) {
 					},
 				},
 			},
-			gzipCompress:        false,
+			compressionType:     compression.TypeNone,
 			bypassThrottling:    true,
 			bytesSaved:          false,
 			expectedOutboundErr: nil,
@@ -263,7 +266,7 @@ func TestNewOutboundInboundMessageWithProto(t *testing.T) {
 					},
 				},
 			},
-			gzipCompress:        false,
+			compressionType:     compression.TypeNone,
 			bypassThrottling:    true,
 			bytesSaved:          false,
 			expectedOutboundErr: nil,
@@ -287,7 +290,7 @@ func TestNewOutboundInboundMessageWithProto(t *testing.T) {
 					},
 				},
 			},
-			gzipCompress:        false,
+			compressionType:     compression.TypeNone,
 			bypassThrottling:    true,
 			bytesSaved:          false,
 			expectedOutboundErr: nil,
@@ -311,7 +314,7 @@ func TestNewOutboundInboundMessageWithProto(t *testing.T) {
 					},
 				},
 			},
-			gzipCompress:        false,
+			compressionType:     compression.TypeNone,
 			bypassThrottling:    true,
 			bytesSaved:          false,
 			expectedOutboundErr: nil,
@@ -335,7 +338,7 @@ func TestNewOutboundInboundMessageWithProto(t *testing.T) {
 					},
 				},
 			},
-			gzipCompress:        false,
+			compressionType:     compression.TypeNone,
 			bypassThrottling:    true,
 			bytesSaved:          false,
 			expectedOutboundErr: nil,
@@ -359,7 +362,7 @@ func TestNewOutboundInboundMessageWithProto(t *testing.T) {
 					},
 				},
 			},
-			gzipCompress:        false,
+			compressionType:     compression.TypeNone,
 			bypassThrottling:    true,
 			bytesSaved:          false,
 			expectedOutboundErr: nil,
@@ -382,7 +385,7 @@ func TestNewOutboundInboundMessageWithProto(t *testing.T) {
 					},
 				},
 			},
-			gzipCompress:        true,
+			compressionType:     compression.TypeGzip,
 			bypassThrottling:    true,
 			bytesSaved:          true,
 			expectedOutboundErr: nil,
@@ -406,7 +409,7 @@ func TestNewOutboundInboundMessageWithProto(t *testing.T) {
 					},
 				},
 			},
-			gzipCompress:        false,
+			compressionType:     compression.TypeNone,
 			bypassThrottling:    true,
 			bytesSaved:          false,
 			expectedOutboundErr: nil,
@@ -430,7 +433,7 @@ func TestNewOutboundInboundMessageWithProto(t *testing.T) {
 					},
 				},
 			},
-			gzipCompress:        false,
+			compressionType:     compression.TypeNone,
 			bypassThrottling:    true,
 			bytesSaved:          false,
 			expectedOutboundErr: nil,
@@ -453,7 +456,7 @@ func TestNewOutboundInboundMessageWithProto(t *testing.T) {
 					},
 				},
 			},
-			gzipCompress:        true,
+			compressionType:     compression.TypeGzip,
 			bypassThrottling:    true,
 			bytesSaved:          true,
 			expectedOutboundErr: nil,
@@ -477,7 +480,7 @@ func TestNewOutboundInboundMessageWithProto(t *testing.T) {
 					},
 				},
 			},
-			gzipCompress:        false,
+			compressionType:     compression.TypeNone,
 			bypassThrottling:    true,
 			bytesSaved:          false,
 			expectedOutboundErr: nil,
@@ -502,7 +505,7 @@ func TestNewOutboundInboundMessageWithProto(t *testing.T) {
 					},
 				},
 			},
-			gzipCompress:        true,
+			compressionType:     compression.TypeGzip,
 			bypassThrottling:    true,
 			bytesSaved:          true,
 			expectedOutboundErr: nil,
@@ -527,7 +530,7 @@ func TestNewOutboundInboundMessageWithProto(t *testing.T) {
 					},
 				},
 			},
-			gzipCompress:        false,
+			compressionType:     compression.TypeNone,
 			bypassThrottling:    true,
 			bytesSaved:          false,
 			expectedOutboundErr: nil,
@@ -551,7 +554,7 @@ func TestNewOutboundInboundMessageWithProto(t *testing.T) {
 					},
 				},
 			},
-			gzipCompress:        false,
+			compressionType:     compression.TypeNone,
 			bypassThrottling:    true,
 			bytesSaved:          false,
 			expectedOutboundErr: nil,
@@ -580,7 +583,7 @@ func TestNewOutboundInboundMessageWithProto(t *testing.T) {
 					},
 				},
 			},
-			gzipCompress:        false,
+			compressionType:     compression.TypeNone,
 			bypassThrottling:    true,
 			bytesSaved:          false,
 			expectedOutboundErr: nil,
@@ -614,7 +617,7 @@ func TestNewOutboundInboundMessageWithProto(t *testing.T) {
 					},
 				},
 			},
-			gzipCompress:        false,
+			compressionType:     compression.TypeNone,
 			bypassThrottling:    true,
 			bytesSaved:          false,
 			expectedOutboundErr: nil,
@@ -641,7 +644,7 @@ func TestNewOutboundInboundMessageWithProto(t *testing.T) {
 					},
 				},
 			},
-			gzipCompress:        false,
+			compressionType:     compression.TypeNone,
 			bypassThrottling:    true,
 			bytesSaved:          false,
 			expectedOutboundErr: nil,
@@ -668,7 +671,7 @@ func TestNewOutboundInboundMessageWithProto(t *testing.T) {
 					},
 				},
 			},
-			gzipCompress:        true,
+			compressionType:     compression.TypeGzip,
 			bypassThrottling:    true,
 			bytesSaved:          true,
 			expectedOutboundErr: nil,
@@ -702,7 +705,7 @@ func TestNewOutboundInboundMessageWithProto(t *testing.T) {
 					},
 				},
 			},
-			gzipCompress:        true,
+			compressionType:     compression.TypeGzip,
 			bypassThrottling:    true,
 			bytesSaved:          true,
 			expectedOutboundErr: nil,
@@ -729,7 +732,7 @@ func TestNewOutboundInboundMessageWithProto(t *testing.T) {
 					},
 				},
 			},
-			gzipCompress:        true,
+			compressionType:     compression.TypeGzip,
 			bypassThrottling:    true,
 			bytesSaved:          true,
 			expectedOutboundErr: nil,
@@ -813,7 +816,7 @@ func TestNewOutboundInboundMessageWithProto(t *testing.T) {
 					},
 				},
 			},
-			gzipCompress:        false,
+			compressionType:     compression.TypeNone,
 			bypassThrottling:    true,
 			bytesSaved:          false,
 			expectedOutboundErr: nil,
@@ -838,7 +841,7 @@ func TestNewOutboundInboundMessageWithProto(t *testing.T) {
 					},
 				},
 			},
-			gzipCompress:        true,
+			compressionType:     compression.TypeGzip,
 			bypassThrottling:    true,
 			bytesSaved:          true,
 			expectedOutboundErr: nil,
@@ -862,7 +865,7 @@ func TestNewOutboundInboundMessageWithProto(t *testing.T) {
 					},
 				},
 			},
-			gzipCompress:        false,
+			compressionType:     compression.TypeNone,
 			bypassThrottling:    true,
 			bytesSaved:          false,
 			expectedOutboundErr: nil,
@@ -885,7 +888,7 @@ func TestNewOutboundInboundMessageWithProto(t *testing.T) {
 					},
 				},
 			},
-			gzipCompress:        true,
+			compressionType:     compression.TypeGzip,
 			bypassThrottling:    true,
 			bytesSaved:          true,
 			expectedOutboundErr: nil,
@@ -907,7 +910,7 @@ func TestNewOutboundInboundMessageWithProto(t *testing.T) {
 					},
 				},
 			},
-			gzipCompress:        false,
+			compressionType:     compression.TypeNone,
 			bypassThrottling:    true,
 			bytesSaved:          false,
 			expectedOutboundErr: nil,
@@ -928,7 +931,7 @@ func TestNewOutboundInboundMessageWithProto(t *testing.T) {
 					},
 				},
 			},
-			gzipCompress:        true,
+			compressionType:     compression.TypeGzip,
 			bypassThrottling:    true,
 			bytesSaved:          true,
 			expectedOutboundErr: nil,
@@ -950,7 +953,7 @@ func TestNewOutboundInboundMessageWithProto(t *testing.T) {
 					},
 				},
 			},
-			gzipCompress:        false,
+			compressionType:     compression.TypeNone,
 			bypassThrottling:    true,
 			bytesSaved:          false,
 			expectedOutboundErr: nil,
@@ -973,7 +976,7 @@ func TestNewOutboundInboundMessageWithProto(t *testing.T) {
 					},
 				},
 			},
-			gzipCompress:        false,
+			compressionType:     compression.TypeNone,
 			bypassThrottling:    true,
 			bytesSaved:          false,
 			expectedOutboundErr: nil,
@@ -996,7 +999,7 @@ func TestNewOutboundInboundMessageWithProto(t *testing.T) {
 					},
 				},
 			},
-			gzipCompress:        true,
+			compressionType:     compression.TypeGzip,
 			bypassThrottling:    true,
 			bytesSaved:          true,
 			expectedOutboundErr: nil,
@@ -1020,7 +1023,7 @@ func TestNewOutboundInboundMessageWithProto(t *testing.T) {
 					},
 				},
 			},
-			gzipCompress:        false,
+			compressionType:     compression.TypeNone,
 			bypassThrottling:    true,
 			bytesSaved:          false,
 			expectedOutboundErr: nil,
@@ -1045,7 +1048,7 @@ func TestNewOutboundInboundMessageWithProto(t *testing.T) {
 					},
 				},
 			},
-			gzipCompress:        true,
+			compressionType:     compression.TypeGzip,
 			bypassThrottling:    true,
 			bytesSaved:          false,
 			expectedOutboundErr: nil,
@@ -1069,7 +1072,7 @@ func TestNewOutboundInboundMessageWithProto(t *testing.T) {
 					},
 				},
 			},
-			gzipCompress:        false,
+			compressionType:     compression.TypeNone,
 			bypassThrottling:    true,
 			bytesSaved:          false,
 			expectedOutboundErr: nil,
@@ -1092,7 +1095,7 @@ func TestNewOutboundInboundMessageWithProto(t *testing.T) {
 					},
 				},
 			},
-			gzipCompress:        true,
+			compressionType:     compression.TypeGzip,
 			bypassThrottling:    true,
 			bytesSaved:          true,
 			expectedOutboundErr: nil,
@@ -1109,7 +1112,7 @@ func TestNewOutboundInboundMessageWithProto(t *testing.T) {
 			// copy before we in-place update via marshal
 			oldProtoMsgS := tv.msg.String()
 
-			encodedMsg, err := mb.createOutbound(tv.op, tv.msg, tv.gzipCompress, tv.bypassThrottling)
+			encodedMsg, err := mb.createOutbound(tv.op, tv.msg, tv.compressionType, tv.bypassThrottling)
 			require.ErrorIs(err, tv.expectedOutboundErr, fmt.Errorf("unexpected error %v (%T)", err, err))
 			if tv.expectedOutboundErr != nil {
 				return
@@ -1131,6 +1134,7 @@ func TestNewOutboundInboundMessageWithProto(t *testing.T) {
 
 			parsedMsg, err := mb.parseInbound(encodedMsg.Bytes(), ids.EmptyNodeID, func() {})
 			require.NoError(err)
+			require.Equal(len(encodedMsg.Bytes()), parsedMsg.NumBytes())
 
 			// before/after compression, the message should be the same
 			require.Equal(parsedMsg.msg.String(), oldProtoMsgS)
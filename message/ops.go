@@ -3,6 +3,13 @@
 
 package message
 
+import (
+	"errors"
+	"fmt"
+)
+
+var errUnknownOp = errors.New("unknown op")
+
 // Op is an opcode
 type Op byte
 
@@ -324,3 +331,22 @@ func (op Op) String() string {
 		return "Unknown Op"
 	}
 }
+
+// MarshalText encodes [op] as its name, e.g. "push_query".
+func (op Op) MarshalText() ([]byte, error) {
+	return []byte(op.String()), nil
+}
+
+// UnmarshalText sets [op] to the op named [text], e.g. "push_query".
+func (op *Op) UnmarshalText(text []byte) error {
+	name := string(text)
+	for _, ops := range [][]Op{HandshakeOps, ConsensusOps} {
+		for _, candidate := range ops {
+			if candidate.String() == name {
+				*op = candidate
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("%w: %q", errUnknownOp, name)
+}
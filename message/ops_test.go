@@ -0,0 +1,29 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package message
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpText(t *testing.T) {
+	require := require.New(t)
+
+	for _, ops := range [][]Op{HandshakeOps, ConsensusOps} {
+		for _, op := range ops {
+			text, err := op.MarshalText()
+			require.NoError(err)
+
+			var parsed Op
+			require.NoError(parsed.UnmarshalText(text))
+			require.Equal(op, parsed)
+		}
+	}
+
+	var op Op
+	err := op.UnmarshalText([]byte("not_an_op"))
+	require.ErrorIs(err, errUnknownOp)
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/utils/compression"
 	"github.com/dim4egster/qmallgo/utils/ips"
 
 	p2ppb "github.com/dim4egster/qmallgo/proto/pb/p2p"
@@ -15,17 +16,17 @@ import (
 var _ OutboundMsgBuilder = &outMsgBuilderWithProto{}
 
 type outMsgBuilderWithProto struct {
-	compress bool // set to "true" if compression is enabled
+	compressionType compression.Type
 
 	protoBuilder *msgBuilderProtobuf
 }
 
 // Use "message.NewCreatorWithProto" to import this function
 // since we do not expose "msgBuilderProtobuf" yet
-func newOutboundBuilderWithProto(enableCompression bool, protoBuilder *msgBuilderProtobuf) OutboundMsgBuilder {
+func newOutboundBuilderWithProto(compressionType compression.Type, protoBuilder *msgBuilderProtobuf) OutboundMsgBuilder {
 	return &outMsgBuilderWithProto{
-		compress:     enableCompression,
-		protoBuilder: protoBuilder,
+		compressionType: compressionType,
+		protoBuilder:    protoBuilder,
 	}
 }
 
@@ -56,10 +57,13 @@ func (b *outMsgBuilderWithProto) Version(
 					MyVersionTime:  myVersionTime,
 					Sig:            sig,
 					TrackedSubnets: subnetIDBytes,
+
+					SupportedCompressions: b.protoBuilder.supportedCompressions(),
+					ZstdDictionaryId:      b.protoBuilder.zstdDictionaryID,
 				},
 			},
 		},
-		b.compress && Version.Compressible(),
+		b.compressionType,
 		true,
 	)
 }
@@ -87,7 +91,7 @@ func (b *outMsgBuilderWithProto) PeerList(peers []ips.ClaimedIPPort, bypassThrot
 				},
 			},
 		},
-		b.compress && PeerList.Compressible(),
+		b.compressionType,
 		bypassThrottling,
 	)
 }
@@ -100,7 +104,7 @@ func (b *outMsgBuilderWithProto) Ping() (OutboundMessage, error) {
 				Ping: &p2ppb.Ping{},
 			},
 		},
-		b.compress && Ping.Compressible(),
+		b.compressionType,
 		false,
 	)
 }
@@ -115,7 +119,7 @@ func (b *outMsgBuilderWithProto) Pong(uptimePercentage uint8) (OutboundMessage,
 				},
 			},
 		},
-		b.compress && Pong.Compressible(),
+		b.compressionType,
 		false,
 	)
 }
@@ -136,7 +140,7 @@ func (b *outMsgBuilderWithProto) GetStateSummaryFrontier(
 				},
 			},
 		},
-		b.compress && GetStateSummaryFrontier.Compressible(),
+		b.compressionType,
 		false,
 	)
 }
@@ -157,7 +161,7 @@ func (b *outMsgBuilderWithProto) StateSummaryFrontier(
 				},
 			},
 		},
-		b.compress && StateSummaryFrontier.Compressible(),
+		b.compressionType,
 		false,
 	)
 }
@@ -180,7 +184,7 @@ func (b *outMsgBuilderWithProto) GetAcceptedStateSummary(
 				},
 			},
 		},
-		b.compress && GetAcceptedStateSummary.Compressible(),
+		b.compressionType,
 		false,
 	)
 }
@@ -203,7 +207,7 @@ func (b *outMsgBuilderWithProto) AcceptedStateSummary(
 				},
 			},
 		},
-		b.compress && AcceptedStateSummary.Compressible(),
+		b.compressionType,
 		false,
 	)
 }
@@ -224,7 +228,7 @@ func (b *outMsgBuilderWithProto) GetAcceptedFrontier(
 				},
 			},
 		},
-		b.compress && GetAcceptedFrontier.Compressible(),
+		b.compressionType,
 		false,
 	)
 }
@@ -247,7 +251,7 @@ func (b *outMsgBuilderWithProto) AcceptedFrontier(
 				},
 			},
 		},
-		b.compress && AcceptedFrontier.Compressible(),
+		b.compressionType,
 		false,
 	)
 }
@@ -272,7 +276,7 @@ func (b *outMsgBuilderWithProto) GetAccepted(
 				},
 			},
 		},
-		b.compress && GetAccepted.Compressible(),
+		b.compressionType,
 		false,
 	)
 }
@@ -295,7 +299,7 @@ func (b *outMsgBuilderWithProto) Accepted(
 				},
 			},
 		},
-		b.compress && Accepted.Compressible(),
+		b.compressionType,
 		false,
 	)
 }
@@ -318,7 +322,7 @@ func (b *outMsgBuilderWithProto) GetAncestors(
 				},
 			},
 		},
-		b.compress && GetAncestors.Compressible(),
+		b.compressionType,
 		false,
 	)
 }
@@ -339,7 +343,7 @@ func (b *outMsgBuilderWithProto) Ancestors(
 				},
 			},
 		},
-		b.compress && Ancestors.Compressible(),
+		b.compressionType,
 		false,
 	)
 }
@@ -362,7 +366,7 @@ func (b *outMsgBuilderWithProto) Get(
 				},
 			},
 		},
-		b.compress && Get.Compressible(),
+		b.compressionType,
 		false,
 	)
 }
@@ -383,7 +387,7 @@ func (b *outMsgBuilderWithProto) Put(
 				},
 			},
 		},
-		b.compress && Put.Compressible(),
+		b.compressionType,
 		false,
 	)
 }
@@ -406,7 +410,7 @@ func (b *outMsgBuilderWithProto) PushQuery(
 				},
 			},
 		},
-		b.compress && PushQuery.Compressible(),
+		b.compressionType,
 		false,
 	)
 }
@@ -429,7 +433,7 @@ func (b *outMsgBuilderWithProto) PullQuery(
 				},
 			},
 		},
-		b.compress && PullQuery.Compressible(),
+		b.compressionType,
 		false,
 	)
 }
@@ -452,7 +456,7 @@ func (b *outMsgBuilderWithProto) Chits(
 				},
 			},
 		},
-		b.compress && Chits.Compressible(),
+		b.compressionType,
 		false,
 	)
 }
@@ -476,7 +480,7 @@ func (b *outMsgBuilderWithProto) AppRequest(
 				},
 			},
 		},
-		b.compress && AppRequest.Compressible(),
+		b.compressionType,
 		false,
 	)
 }
@@ -494,7 +498,7 @@ func (b *outMsgBuilderWithProto) AppResponse(chainID ids.ID, requestID uint32, m
 				},
 			},
 		},
-		b.compress && AppResponse.Compressible(),
+		b.compressionType,
 		false,
 	)
 }
@@ -511,7 +515,7 @@ func (b *outMsgBuilderWithProto) AppGossip(chainID ids.ID, msg []byte) (Outbound
 				},
 			},
 		},
-		b.compress && AppGossip.Compressible(),
+		b.compressionType,
 		false,
 	)
 }
@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/utils/compression"
 	"github.com/dim4egster/qmallgo/utils/constants"
 )
 
@@ -19,10 +20,10 @@ func Test_newOutboundBuilderWithProto(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
 
-	mb, err := newMsgBuilderProtobuf("test", prometheus.NewRegistry(), int64(constants.DefaultMaxMessageSize), 5*time.Second)
+	mb, err := newMsgBuilderProtobuf("test", prometheus.NewRegistry(), int64(constants.DefaultMaxMessageSize), nil, 5*time.Second)
 	require.NoError(err)
 
-	builder := newOutboundBuilderWithProto(true /*compress*/, mb)
+	builder := newOutboundBuilderWithProto(compression.TypeGzip, mb)
 
 	outMsg, err := builder.GetAcceptedStateSummary(ids.GenerateTestID(), uint32(12345), time.Hour, []uint64{1000, 2000})
 	require.NoError(err)
@@ -10,10 +10,13 @@ import (
 
 	"github.com/dim4egster/qmallgo/ids"
 	"github.com/dim4egster/qmallgo/network/dialer"
+	"github.com/dim4egster/qmallgo/network/recorder"
 	"github.com/dim4egster/qmallgo/network/throttling"
 	"github.com/dim4egster/qmallgo/snow/networking/tracker"
 	"github.com/dim4egster/qmallgo/snow/uptime"
 	"github.com/dim4egster/qmallgo/snow/validators"
+	"github.com/dim4egster/qmallgo/trace"
+	"github.com/dim4egster/qmallgo/utils/compression"
 	"github.com/dim4egster/qmallgo/utils/ips"
 )
 
@@ -109,6 +112,12 @@ type Config struct {
 
 	TLSKeyLogFile string `json:"tlsKeyLogFile"`
 
+	// RecorderEnabled records the messages sent to and received from peers,
+	// and the peers connecting and disconnecting, into
+	// [RecorderConfig.Directory] when set to true.
+	RecorderEnabled bool            `json:"recorderEnabled"`
+	RecorderConfig  recorder.Config `json:"recorderConfig"`
+
 	Namespace          string            `json:"namespace"`
 	MyNodeID           ids.NodeID        `json:"myNodeID"`
 	MyIPPort           ips.DynamicIPPort `json:"myIP"`
@@ -121,6 +130,16 @@ type Config struct {
 	// true.
 	CompressionEnabled bool `json:"compressionEnabled"`
 
+	// CompressionType is the compression type used to compress available
+	// outbound protobuf messages. Peers that are unable to decompress
+	// [CompressionType] are sent messages compressed with gzip.
+	CompressionType compression.Type `json:"compressionType"`
+
+	// ZstdDictionary is used to compress container carrying messages when
+	// [CompressionType] is zstd. Peers that don't have the same dictionary are
+	// sent messages compressed without it.
+	ZstdDictionary []byte `json:"-"`
+
 	// TLSKey is this node's TLS key that is used to sign IPs.
 	TLSKey crypto.Signer `json:"-"`
 
@@ -163,6 +182,12 @@ type Config struct {
 	// Tracks the CPU/disk usage caused by processing messages of each peer.
 	ResourceTracker tracker.ResourceTracker `json:"-"`
 
+	// Traces the handling of messages received from peers.
+	Tracer trace.Tracer `json:"-"`
+
+	// Records the messages sent to and received from peers, if non-nil.
+	Recorder recorder.Recorder `json:"-"`
+
 	// Specifies how much CPU usage each peer can cause before
 	// we rate-limit them.
 	CPUTargeter tracker.Targeter `json:"-"`
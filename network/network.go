@@ -223,6 +223,8 @@ func NewNetwork(
 		PongTimeout:          config.PingPongTimeout,
 		MaxClockDifference:   config.MaxClockDifference,
 		ResourceTracker:      config.ResourceTracker,
+		Tracer:               config.Tracer,
+		Recorder:             config.Recorder,
 	}
 
 	onCloseCtx, cancel := context.WithCancel(context.Background())
@@ -23,6 +23,8 @@ import (
 	"github.com/dim4egster/qmallgo/snow/networking/tracker"
 	"github.com/dim4egster/qmallgo/snow/uptime"
 	"github.com/dim4egster/qmallgo/snow/validators"
+	"github.com/dim4egster/qmallgo/trace"
+	"github.com/dim4egster/qmallgo/utils/compression"
 	"github.com/dim4egster/qmallgo/utils/constants"
 	"github.com/dim4egster/qmallgo/utils/ips"
 	"github.com/dim4egster/qmallgo/utils/logging"
@@ -116,6 +118,7 @@ var (
 
 		MaximumInboundMessageTimeout: 30 * time.Second,
 		ResourceTracker:              newDefaultResourceTracker(),
+		Tracer:                       trace.Noop,
 		CPUTargeter:                  nil, // Set in init
 		DiskTargeter:                 nil, // Set in init
 	}
@@ -184,7 +187,8 @@ func newMessageCreator(t *testing.T) (message.Creator, message.Creator) {
 	mcProto, err := message.NewCreatorWithProto(
 		prometheus.NewRegistry(),
 		"",
-		true,
+		compression.TypeGzip,
+		nil,
 		10*time.Second,
 	)
 	require.NoError(t, err)
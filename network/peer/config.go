@@ -8,10 +8,12 @@ import (
 
 	"github.com/dim4egster/qmallgo/ids"
 	"github.com/dim4egster/qmallgo/message"
+	"github.com/dim4egster/qmallgo/network/recorder"
 	"github.com/dim4egster/qmallgo/network/throttling"
 	"github.com/dim4egster/qmallgo/snow/networking/router"
 	"github.com/dim4egster/qmallgo/snow/networking/tracker"
 	"github.com/dim4egster/qmallgo/snow/validators"
+	"github.com/dim4egster/qmallgo/trace"
 	"github.com/dim4egster/qmallgo/utils/logging"
 	"github.com/dim4egster/qmallgo/utils/timer/mockable"
 	"github.com/dim4egster/qmallgo/version"
@@ -48,6 +50,12 @@ type Config struct {
 
 	// Tracks CPU/disk usage caused by each peer.
 	ResourceTracker tracker.ResourceTracker
+
+	// Traces the handling of messages received from each peer.
+	Tracer trace.Tracer
+
+	// Records the messages sent to and received from each peer, if non-nil.
+	Recorder recorder.Recorder
 }
 
 func (c *Config) GetMessageCreator() message.Creator {
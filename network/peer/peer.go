@@ -15,16 +15,23 @@ import (
 	"sync/atomic"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+
 	"go.uber.org/zap"
 
 	"github.com/dim4egster/qmallgo/ids"
 	"github.com/dim4egster/qmallgo/message"
+	"github.com/dim4egster/qmallgo/network/recorder"
 	"github.com/dim4egster/qmallgo/utils"
+	"github.com/dim4egster/qmallgo/utils/compression"
 	"github.com/dim4egster/qmallgo/utils/constants"
 	"github.com/dim4egster/qmallgo/utils/ips"
 	"github.com/dim4egster/qmallgo/utils/json"
 	"github.com/dim4egster/qmallgo/utils/wrappers"
 	"github.com/dim4egster/qmallgo/version"
+
+	oteltrace "go.opentelemetry.io/otel/trace"
 )
 
 var (
@@ -121,6 +128,10 @@ type peer struct {
 	// trackedSubnets is the subset of subnetIDs the peer sent us in the Version
 	// message that we are also tracking.
 	trackedSubnets ids.Set
+	// compression describes the compression types the peer told us in the
+	// Version message that it is able to decompress. It should only be read
+	// after [gotVersion] is true.
+	compression message.PeerCompression
 
 	observedUptimeLock sync.RWMutex
 	// [observedUptimeLock] must be held while accessing [observedUptime]
@@ -386,6 +397,22 @@ func (p *peer) readMessages() {
 			return
 		}
 
+		// The message's span ends once the message has finished being
+		// handled, which may happen after it has been passed to the router.
+		msgCtx, span := p.Tracer.Start(
+			context.Background(),
+			"peer.readMessage",
+			oteltrace.WithAttributes(
+				attribute.Stringer("nodeID", p.id),
+				attribute.Int64("size", int64(msgLen)),
+			),
+		)
+		releaseThrottler := onFinishedHandling
+		onFinishedHandling = func() {
+			span.End()
+			releaseThrottler()
+		}
+
 		// Track the time it takes from now until the time the message is
 		// handled (in the event this message is handled at the network level)
 		// or the time the message is handed to the router (in the event this
@@ -416,6 +443,8 @@ func (p *peer) readMessages() {
 			p.Metrics.FailedToParse.Inc()
 
 			// Couldn't parse the message. Read the next one.
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to parse message")
 			onFinishedHandling()
 			p.ResourceTracker.StopProcessing(p.id, p.Clock.Time())
 			continue
@@ -426,9 +455,12 @@ func (p *peer) readMessages() {
 		atomic.StoreInt64(&p.lastReceived, now)
 		p.Metrics.Received(msg, msgLen)
 
+		span.SetAttributes(attribute.Stringer("op", msg.Op()))
+		msg = message.InboundWithContext(msgCtx, msg)
+
 		// Handle the message. Note that when we are done handling this message,
 		// we must call [msg.OnFinishedHandling()].
-		p.handle(msg)
+		p.handle(msg, isProto, msgBytes)
 		p.ResourceTracker.StopProcessing(p.id, p.Clock.Time())
 	}
 }
@@ -475,6 +507,24 @@ func (p *peer) writeMessages() {
 }
 
 func (p *peer) writeMessage(writer io.Writer, msg message.OutboundMessage) {
+	// Until the peer's Version message is received, assume that the peer is
+	// only able to decompress gzip.
+	peerCompression := message.PeerCompression{}
+	if p.gotVersion.GetValue() {
+		peerCompression = p.compression
+	}
+	compatibleMsg, err := message.Recompress(msg, peerCompression)
+	if err != nil {
+		p.Log.Verbo("error recompressing message",
+			zap.Stringer("nodeID", p.id),
+			zap.Stringer("messageOp", msg.Op()),
+			zap.Error(err),
+		)
+		msg.DecRef()
+		return
+	}
+	msg = compatibleMsg
+
 	msgBytes := msg.Bytes()
 	p.Log.Verbo("sending message",
 		zap.Stringer("nodeID", p.id),
@@ -512,6 +562,10 @@ func (p *peer) writeMessage(writer io.Writer, msg message.OutboundMessage) {
 		return
 	}
 
+	if p.Recorder != nil {
+		p.Recorder.Record(recorder.Outbound, p.id, isProto, msgBytes)
+	}
+
 	now := p.Clock.Time().Unix()
 	atomic.StoreInt64(&p.Config.LastSent, now)
 	atomic.StoreInt64(&p.lastSent, now)
@@ -560,7 +614,7 @@ func (p *peer) sendPings() {
 	}
 }
 
-func (p *peer) handle(msg message.InboundMessage) {
+func (p *peer) handle(msg message.InboundMessage, isProto bool, msgBytes []byte) {
 	op := msg.Op()
 	switch op { // Network-related message types
 	case message.Ping:
@@ -591,6 +645,12 @@ func (p *peer) handle(msg message.InboundMessage) {
 		return
 	}
 
+	// Only the messages passed to the router are recorded, so that replaying
+	// the recording into a router reproduces what the chains were sent.
+	if p.Recorder != nil {
+		p.Recorder.Record(recorder.Inbound, p.id, isProto, msgBytes)
+	}
+
 	// Consensus and app-level messages
 	p.Router.HandleInbound(msg)
 }
@@ -838,6 +898,15 @@ func (p *peer) handleVersion(msg message.InboundMessage) {
 		return
 	}
 
+	// Peers running older versions don't advertise the compression types they
+	// support. These peers are only able to decompress gzip.
+	if supportedCompressions, err := msg.Get(message.SupportedCompressions); err == nil {
+		p.compression.Types = supportedCompressions.([]compression.Type)
+	}
+	if zstdDictionaryID, err := msg.Get(message.ZstdDictionaryID); err == nil {
+		p.compression.ZstdDictionaryID = zstdDictionaryID.(uint32)
+	}
+
 	p.gotVersion.SetValue(true)
 
 	peerlistMsg, err := p.Network.Peers()
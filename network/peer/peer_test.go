@@ -23,6 +23,8 @@ import (
 	"github.com/dim4egster/qmallgo/snow/networking/tracker"
 	"github.com/dim4egster/qmallgo/snow/validators"
 	"github.com/dim4egster/qmallgo/staking"
+	"github.com/dim4egster/qmallgo/trace"
+	"github.com/dim4egster/qmallgo/utils/compression"
 	"github.com/dim4egster/qmallgo/utils/constants"
 	"github.com/dim4egster/qmallgo/utils/ips"
 	"github.com/dim4egster/qmallgo/utils/logging"
@@ -58,7 +60,8 @@ func newMessageCreator(t *testing.T) (message.Creator, message.Creator) {
 	mcProto, err := message.NewCreatorWithProto(
 		prometheus.NewRegistry(),
 		"",
-		true,
+		compression.TypeGzip,
+		nil,
 		10*time.Second,
 	)
 	require.NoError(t, err)
@@ -106,6 +109,7 @@ func makeRawTestPeers(t *testing.T) (*rawTestPeer, *rawTestPeer) {
 		PongTimeout:             constants.DefaultPingPongTimeout,
 		MaxClockDifference:      time.Minute,
 		ResourceTracker:         resourceTracker,
+		Tracer:                  trace.Noop,
 	}
 	peerConfig0 := sharedConfig
 	peerConfig1 := sharedConfig
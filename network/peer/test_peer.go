@@ -18,6 +18,8 @@ import (
 	"github.com/dim4egster/qmallgo/snow/networking/tracker"
 	"github.com/dim4egster/qmallgo/snow/validators"
 	"github.com/dim4egster/qmallgo/staking"
+	"github.com/dim4egster/qmallgo/trace"
+	"github.com/dim4egster/qmallgo/utils/compression"
 	"github.com/dim4egster/qmallgo/utils/constants"
 	"github.com/dim4egster/qmallgo/utils/ips"
 	"github.com/dim4egster/qmallgo/utils/logging"
@@ -80,7 +82,8 @@ func StartTestPeer(
 	mcWithProto, err := message.NewCreatorWithProto(
 		prometheus.NewRegistry(),
 		"",
-		true,
+		compression.TypeGzip,
+		nil,
 		10*time.Second,
 	)
 	if err != nil {
@@ -131,6 +134,7 @@ func StartTestPeer(
 			PongTimeout:          constants.DefaultPingPongTimeout,
 			MaxClockDifference:   time.Minute,
 			ResourceTracker:      resourceTracker,
+			Tracer:               trace.Noop,
 		},
 		conn,
 		cert,
@@ -0,0 +1,102 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package recorder
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/dim4egster/qmallgo/utils/constants"
+	"github.com/dim4egster/qmallgo/utils/wrappers"
+)
+
+// maxRecordSize bounds the size of a record so that a corrupted recording
+// can't cause huge allocations.
+const maxRecordSize = 1024 + constants.DefaultMaxMessageSize
+
+var errRecordTooLarge = errors.New("record too large")
+
+// Reader reads the records of a recording in the order they were recorded.
+type Reader struct {
+	reader  *bufio.Reader
+	closers []io.Closer
+}
+
+func NewReader(r io.Reader) *Reader {
+	return &Reader{
+		reader: bufio.NewReader(r),
+	}
+}
+
+// Open returns a Reader of the recording written to [dir] by a Recorder. The
+// records of rotated files are read before the records of the current file.
+func Open(dir string) (*Reader, error) {
+	// Rotated files are named with the time they were rotated at, so sorting
+	// them by name sorts them from oldest to newest.
+	paths, err := filepath.Glob(filepath.Join(dir, fileName+"-*"+fileExt))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	paths = append(paths, filepath.Join(dir, fileName+fileExt))
+
+	var (
+		readers = make([]io.Reader, 0, len(paths))
+		closers = make([]io.Closer, 0, len(paths))
+	)
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			for _, closer := range closers {
+				_ = closer.Close()
+			}
+			return nil, err
+		}
+		readers = append(readers, f)
+		closers = append(closers, f)
+	}
+	return &Reader{
+		reader:  bufio.NewReader(io.MultiReader(readers...)),
+		closers: closers,
+	}, nil
+}
+
+// Next returns the next record. Returns [io.EOF] once all the records were
+// read.
+func (r *Reader) Next() (*Record, error) {
+	var sizeBytes [wrappers.IntLen]byte
+	if _, err := io.ReadFull(r.reader, sizeBytes[:]); err != nil {
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(sizeBytes[:])
+	if size > maxRecordSize {
+		return nil, fmt.Errorf("%w: %d bytes", errRecordTooLarge, size)
+	}
+	recordBytes := make([]byte, size)
+	if _, err := io.ReadFull(r.reader, recordBytes); err != nil {
+		if err == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return unmarshal(recordBytes)
+}
+
+// Close the files opened by [Open].
+func (r *Reader) Close() error {
+	errs := wrappers.Errs{}
+	for _, closer := range r.closers {
+		errs.Add(closer.Close())
+	}
+	return errs.Err
+}
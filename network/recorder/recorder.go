@@ -0,0 +1,271 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package recorder
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"go.uber.org/zap"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/utils/logging"
+	"github.com/dim4egster/qmallgo/utils/timer/mockable"
+	"github.com/dim4egster/qmallgo/utils/wrappers"
+	"github.com/dim4egster/qmallgo/version"
+)
+
+const (
+	fileName  = "network"
+	fileExt   = ".rec"
+	idLen     = len(ids.ID{})
+	nodeIDLen = len(ids.NodeID{})
+)
+
+const (
+	// Inbound messages were received from the node.
+	Inbound Type = iota
+	// Outbound messages were sent to the node.
+	Outbound
+	// Connected records that the node connected.
+	Connected
+	// Disconnected records that the node disconnected.
+	Disconnected
+)
+
+var (
+	errUnknownType = errors.New("unknown record type")
+
+	_ Recorder = &recorder{}
+)
+
+// Type of a record.
+type Type byte
+
+func (t Type) String() string {
+	switch t {
+	case Inbound:
+		return "inbound"
+	case Outbound:
+		return "outbound"
+	case Connected:
+		return "connected"
+	case Disconnected:
+		return "disconnected"
+	default:
+		return "unknown"
+	}
+}
+
+// Record is a message that was sent to or received from a node, or a change
+// in the node's connection.
+type Record struct {
+	Timestamp time.Time
+	Type      Type
+	NodeID    ids.NodeID
+	// IsProto is true if [Bytes] is a protobuf message. Only set for Inbound
+	// and Outbound records.
+	IsProto bool
+	// Bytes of the message, as sent over the network. Only set for Inbound
+	// and Outbound records.
+	Bytes []byte
+	// NodeVersion is the version the node connected with. Only set for
+	// Connected records.
+	NodeVersion *version.Application
+	// SubnetID is the subnet the node connected on. Only set for Connected
+	// records.
+	SubnetID ids.ID
+}
+
+// Recorder records the messages sent to and received from peers, along with
+// the peers connecting and disconnecting.
+type Recorder interface {
+	// Record that [msgBytes] was sent in [direction] to or from [nodeID].
+	// [direction] must be either Inbound or Outbound.
+	Record(direction Type, nodeID ids.NodeID, isProto bool, msgBytes []byte)
+
+	// Connected records that [nodeID], running [nodeVersion], connected on
+	// [subnetID].
+	Connected(nodeID ids.NodeID, nodeVersion *version.Application, subnetID ids.ID)
+
+	// Disconnected records that [nodeID] disconnected.
+	Disconnected(nodeID ids.NodeID)
+
+	// Close the recording.
+	Close() error
+}
+
+type Config struct {
+	// Directory the recording is written to.
+	Directory string `json:"directory"`
+	// MaxSize is the size, in megabytes, a recording file can grow to before
+	// a new file is started.
+	MaxSize int `json:"maxSize"`
+	// MaxFiles is the number of old recording files to retain. 0 retains all
+	// of them.
+	MaxFiles int `json:"maxFiles"`
+}
+
+type recorder struct {
+	log logging.Logger
+
+	lock sync.Mutex
+	// Useful for faking time in tests
+	clock  mockable.Clock
+	writer io.WriteCloser
+
+	// failedWrites counts the records that couldn't be written
+	failedWrites prometheus.Counter
+	// warnedFailedWrite is true once a failed write has been logged
+	warnedFailedWrite bool
+}
+
+// New returns a Recorder that writes to rotating files in [config.Directory].
+func New(
+	config Config,
+	log logging.Logger,
+	namespace string,
+	registerer prometheus.Registerer,
+) (Recorder, error) {
+	r := &recorder{
+		log: log,
+		writer: &lumberjack.Logger{
+			Filename:   filepath.Join(config.Directory, fileName+fileExt),
+			MaxSize:    config.MaxSize,  // megabytes
+			MaxBackups: config.MaxFiles, // files
+		},
+		failedWrites: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "recorder_failed_writes",
+			Help:      "Number of network records that couldn't be written to the recording",
+		}),
+	}
+	return r, registerer.Register(r.failedWrites)
+}
+
+func (r *recorder) Record(direction Type, nodeID ids.NodeID, isProto bool, msgBytes []byte) {
+	r.write(&Record{
+		Type:    direction,
+		NodeID:  nodeID,
+		IsProto: isProto,
+		Bytes:   msgBytes,
+	})
+}
+
+func (r *recorder) Connected(nodeID ids.NodeID, nodeVersion *version.Application, subnetID ids.ID) {
+	r.write(&Record{
+		Type:        Connected,
+		NodeID:      nodeID,
+		NodeVersion: nodeVersion,
+		SubnetID:    subnetID,
+	})
+}
+
+func (r *recorder) Disconnected(nodeID ids.NodeID) {
+	r.write(&Record{
+		Type:   Disconnected,
+		NodeID: nodeID,
+	})
+}
+
+// write [record], timestamped with the current time.
+func (r *recorder) write(record *Record) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	record.Timestamp = r.clock.Time()
+	// A record is written in a single write so that it isn't split over two
+	// files. Failing to record doesn't affect the node, so the failure is
+	// only counted, and logged the first time it happens.
+	if _, err := r.writer.Write(marshal(record)); err != nil {
+		r.failedWrites.Inc()
+		if !r.warnedFailedWrite {
+			r.warnedFailedWrite = true
+			r.log.Warn("failed to write network record",
+				zap.Stringer("type", record.Type),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+func (r *recorder) Close() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.writer.Close()
+}
+
+// marshal returns the length prefixed bytes of [record].
+func marshal(record *Record) []byte {
+	size := wrappers.IntLen + // record length
+		wrappers.LongLen + // timestamp
+		wrappers.ByteLen + // type
+		nodeIDLen // node ID
+	switch record.Type {
+	case Inbound, Outbound:
+		size += wrappers.BoolLen + // is proto
+			wrappers.IntLen + len(record.Bytes) // message bytes
+	case Connected:
+		size += 3*wrappers.IntLen + // node version
+			idLen // subnet ID
+	}
+
+	p := wrappers.Packer{
+		MaxSize: size,
+		Bytes:   make([]byte, 0, size),
+	}
+	p.PackInt(uint32(size - wrappers.IntLen))
+	p.PackLong(uint64(record.Timestamp.UnixNano()))
+	p.PackByte(byte(record.Type))
+	p.PackFixedBytes(record.NodeID[:])
+	switch record.Type {
+	case Inbound, Outbound:
+		p.PackBool(record.IsProto)
+		p.PackBytes(record.Bytes)
+	case Connected:
+		p.PackInt(uint32(record.NodeVersion.Major))
+		p.PackInt(uint32(record.NodeVersion.Minor))
+		p.PackInt(uint32(record.NodeVersion.Patch))
+		p.PackFixedBytes(record.SubnetID[:])
+	}
+	return p.Bytes
+}
+
+// unmarshal parses a record, without its length prefix, from [b].
+func unmarshal(b []byte) (*Record, error) {
+	p := wrappers.Packer{Bytes: b}
+	record := &Record{
+		Timestamp: time.Unix(0, int64(p.UnpackLong())),
+		Type:      Type(p.UnpackByte()),
+	}
+	copy(record.NodeID[:], p.UnpackFixedBytes(nodeIDLen))
+	switch record.Type {
+	case Inbound, Outbound:
+		record.IsProto = p.UnpackBool()
+		record.Bytes = p.UnpackBytes()
+	case Connected:
+		record.NodeVersion = &version.Application{
+			Major: int(p.UnpackInt()),
+			Minor: int(p.UnpackInt()),
+			Patch: int(p.UnpackInt()),
+		}
+		copy(record.SubnetID[:], p.UnpackFixedBytes(idLen))
+	case Disconnected:
+	default:
+		return nil, fmt.Errorf("%w: %d", errUnknownType, record.Type)
+	}
+	if p.Errored() {
+		return nil, p.Err
+	}
+	return record, nil
+}
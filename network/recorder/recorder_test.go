@@ -0,0 +1,171 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package recorder
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/utils/logging"
+	"github.com/dim4egster/qmallgo/utils/units"
+	"github.com/dim4egster/qmallgo/version"
+)
+
+func TestRecordRoundTrip(t *testing.T) {
+	require := require.New(t)
+
+	expected := &Record{
+		Timestamp: time.Unix(0, 123456789),
+		Type:      Outbound,
+		NodeID:    ids.GenerateTestNodeID(),
+		IsProto:   true,
+		Bytes:     []byte("message"),
+	}
+
+	reader := NewReader(bytes.NewReader(marshal(expected)))
+	record, err := reader.Next()
+	require.NoError(err)
+	require.Equal(expected, record)
+
+	_, err = reader.Next()
+	require.ErrorIs(err, io.EOF)
+
+	// Truncated records are reported.
+	recordBytes := marshal(expected)
+	reader = NewReader(bytes.NewReader(recordBytes[:len(recordBytes)-1]))
+	_, err = reader.Next()
+	require.ErrorIs(err, io.ErrUnexpectedEOF)
+}
+
+func TestConnectionRecordsRoundTrip(t *testing.T) {
+	require := require.New(t)
+
+	nodeID := ids.GenerateTestNodeID()
+	expectedRecords := []*Record{
+		{
+			Timestamp:   time.Unix(0, 123456789),
+			Type:        Connected,
+			NodeID:      nodeID,
+			NodeVersion: &version.Application{Major: 1, Minor: 2, Patch: 3},
+			SubnetID:    ids.GenerateTestID(),
+		},
+		{
+			Timestamp: time.Unix(0, 987654321),
+			Type:      Disconnected,
+			NodeID:    nodeID,
+		},
+	}
+
+	recordsBytes := []byte{}
+	for _, expected := range expectedRecords {
+		recordsBytes = append(recordsBytes, marshal(expected)...)
+	}
+
+	reader := NewReader(bytes.NewReader(recordsBytes))
+	for _, expected := range expectedRecords {
+		record, err := reader.Next()
+		require.NoError(err)
+		require.Equal(expected, record)
+	}
+	_, err := reader.Next()
+	require.ErrorIs(err, io.EOF)
+}
+
+func TestRecorder(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	rIntf, err := New(
+		Config{
+			Directory: dir,
+			MaxSize:   1,
+		},
+		logging.NoLog{},
+		"",
+		prometheus.NewRegistry(),
+	)
+	require.NoError(err)
+	r := rIntf.(*recorder)
+	now := time.Unix(1000, 0)
+	r.clock.Set(now)
+
+	nodeID := ids.GenerateTestNodeID()
+	// The second message doesn't fit in the first file, so the recording is
+	// rotated.
+	msgs := [][]byte{
+		bytes.Repeat([]byte{1}, 600*units.KiB),
+		bytes.Repeat([]byte{2}, 600*units.KiB),
+		[]byte("small"),
+	}
+	r.Record(Inbound, nodeID, false, msgs[0])
+	r.Record(Outbound, nodeID, true, msgs[1])
+	r.Record(Inbound, nodeID, true, msgs[2])
+	require.NoError(r.Close())
+
+	rotated, err := filepath.Glob(filepath.Join(dir, fileName+"-*"+fileExt))
+	require.NoError(err)
+	require.Len(rotated, 1)
+
+	reader, err := Open(dir)
+	require.NoError(err)
+	defer func() {
+		require.NoError(reader.Close())
+	}()
+
+	expectedTypes := []Type{Inbound, Outbound, Inbound}
+	for i, msg := range msgs {
+		record, err := reader.Next()
+		require.NoError(err)
+		require.Equal(now.UnixNano(), record.Timestamp.UnixNano())
+		require.Equal(expectedTypes[i], record.Type)
+		require.Equal(nodeID, record.NodeID)
+		require.Equal(i != 0, record.IsProto)
+		require.Equal(msg, record.Bytes)
+	}
+	_, err = reader.Next()
+	require.ErrorIs(err, io.EOF)
+}
+
+func TestRecorderFailedWrites(t *testing.T) {
+	require := require.New(t)
+
+	rIntf, err := New(
+		Config{Directory: t.TempDir()},
+		logging.NoLog{},
+		"",
+		prometheus.NewRegistry(),
+	)
+	require.NoError(err)
+	r := rIntf.(*recorder)
+	require.NoError(r.writer.Close())
+	r.writer = failingWriter{}
+
+	nodeID := ids.GenerateTestNodeID()
+	r.Record(Inbound, nodeID, true, []byte("msg"))
+	r.Disconnected(nodeID)
+	require.Equal(float64(2), testutil.ToFloat64(r.failedWrites))
+	require.True(r.warnedFailedWrite)
+}
+
+var errWriteFailed = errors.New("write failed")
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errWriteFailed
+}
+
+func (failingWriter) Close() error {
+	return nil
+}
@@ -7,16 +7,20 @@ import (
 	"crypto/tls"
 	"time"
 
+	"github.com/dim4egster/qmallgo/api/server"
 	"github.com/dim4egster/qmallgo/chains"
 	"github.com/dim4egster/qmallgo/genesis"
 	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/ipcs/socket"
 	"github.com/dim4egster/qmallgo/nat"
 	"github.com/dim4egster/qmallgo/network"
 	"github.com/dim4egster/qmallgo/snow/consensus/avalanche"
 	"github.com/dim4egster/qmallgo/snow/networking/benchlist"
+	"github.com/dim4egster/qmallgo/snow/networking/handler"
 	"github.com/dim4egster/qmallgo/snow/networking/router"
 	"github.com/dim4egster/qmallgo/snow/networking/sender"
 	"github.com/dim4egster/qmallgo/snow/networking/tracker"
+	"github.com/dim4egster/qmallgo/trace"
 	"github.com/dim4egster/qmallgo/utils/crypto/bls"
 	"github.com/dim4egster/qmallgo/utils/dynamicip"
 	"github.com/dim4egster/qmallgo/utils/ips"
@@ -30,6 +34,8 @@ type IPCConfig struct {
 	IPCAPIEnabled      bool     `json:"ipcAPIEnabled"`
 	IPCPath            string   `json:"ipcPath"`
 	IPCDefaultChainIDs []string `json:"ipcDefaultChainIDs"`
+	// IPCSocketConfig configures how events are buffered for IPC readers
+	IPCSocketConfig socket.Config `json:"ipcSocketConfig"`
 }
 
 type APIAuthConfig struct {
@@ -51,7 +57,9 @@ type HTTPConfig struct {
 	HTTPSKey     []byte `json:"-"`
 	HTTPSCert    []byte `json:"-"`
 
-	APIAllowedOrigins []string `json:"apiAllowedOrigins"`
+	APIAllowedOrigins     []string `json:"apiAllowedOrigins"`
+	APIMaxBatchSize       int      `json:"apiMaxBatchSize"`
+	APIMaxRequestBodySize int64    `json:"apiMaxRequestBodySize"`
 
 	ShutdownTimeout time.Duration `json:"shutdownTimeout"`
 	ShutdownWait    time.Duration `json:"shutdownWait"`
@@ -68,6 +76,16 @@ type APIConfig struct {
 	KeystoreAPIEnabled bool `json:"keystoreAPIEnabled"`
 	MetricsAPIEnabled  bool `json:"metricsAPIEnabled"`
 	HealthAPIEnabled   bool `json:"healthAPIEnabled"`
+	GRPCAPIEnabled     bool `json:"grpcAPIEnabled"`
+
+	// Path of the external signer the keystore services sign through, and the
+	// arguments it's launched with. If empty, the keystore services sign with
+	// the keys of the keystore users.
+	ExternalSignerPath string   `json:"externalSignerPath"`
+	ExternalSignerArgs []string `json:"externalSignerArgs"`
+
+	// Limits the request rate of each API client
+	RateLimitConfig server.RateLimitConfig `json:"rateLimitConfig"`
 }
 
 type IPConfig struct {
@@ -89,6 +107,10 @@ type StakingConfig struct {
 	StakingKeyPath        string          `json:"stakingKeyPath"`
 	StakingCertPath       string          `json:"stakingCertPath"`
 	StakingSignerPath     string          `json:"stakingSignerPath"`
+	// Maximum number of blocks walked back to find when the current
+	// validators started validating, when the P-chain first records their
+	// history
+	ValidatorHistoryBackfillDepth uint64 `json:"validatorHistoryBackfillDepth"`
 }
 
 type StateSyncConfig struct {
@@ -183,12 +205,17 @@ type Config struct {
 	// Metrics
 	MeterVMEnabled bool `json:"meterVMEnabled"`
 
+	// Tracing
+	TraceConfig trace.Config `json:"traceConfig"`
+
 	// Router that is used to handle incoming consensus messages
 	ConsensusRouter          router.Router       `json:"-"`
 	RouterHealthConfig       router.HealthConfig `json:"routerHealthConfig"`
 	ConsensusShutdownTimeout time.Duration       `json:"consensusShutdownTimeout"`
 	// Gossip a container in the accepted frontier every [ConsensusGossipFrequency]
 	ConsensusGossipFrequency time.Duration `json:"consensusGossipFreq"`
+	// Lanes that each chain's incoming messages are queued in
+	ConsensusMessageLanes handler.LanesConfig `json:"consensusMessageLanes"`
 
 	// Subnet Whitelist
 	WhitelistedSubnets ids.Set `json:"whitelistedSubnets"`
@@ -219,6 +246,11 @@ type Config struct {
 	// Larger halflife --> disk usage metrics change more slowly.
 	SystemTrackerDiskHalflife time.Duration `json:"systemTrackerDiskHalflife"`
 
+	// Halflife to use for the peer health tracker.
+	// Larger halflife --> peer response latencies, failure rates and
+	// bandwidths change more slowly.
+	SystemTrackerHealthHalflife time.Duration `json:"systemTrackerHealthHalflife"`
+
 	CPUTargeterConfig tracker.TargeterConfig `json:"cpuTargeterConfig"`
 
 	DiskTargeterConfig tracker.TargeterConfig `json:"diskTargeterConfig"`
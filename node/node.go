@@ -11,6 +11,7 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
@@ -27,7 +28,9 @@ import (
 	"github.com/dim4egster/qmallgo/api/admin"
 	"github.com/dim4egster/qmallgo/api/auth"
 	"github.com/dim4egster/qmallgo/api/health"
+	"github.com/dim4egster/qmallgo/api/health/ghealth"
 	"github.com/dim4egster/qmallgo/api/info"
+	"github.com/dim4egster/qmallgo/api/info/ginfo"
 	"github.com/dim4egster/qmallgo/api/keystore"
 	"github.com/dim4egster/qmallgo/api/metrics"
 	"github.com/dim4egster/qmallgo/api/server"
@@ -37,15 +40,18 @@ import (
 	"github.com/dim4egster/qmallgo/database/leveldb"
 	"github.com/dim4egster/qmallgo/database/manager"
 	"github.com/dim4egster/qmallgo/database/memdb"
+	"github.com/dim4egster/qmallgo/database/pebbledb"
 	"github.com/dim4egster/qmallgo/database/prefixdb"
 	"github.com/dim4egster/qmallgo/genesis"
 	"github.com/dim4egster/qmallgo/ids"
 	"github.com/dim4egster/qmallgo/indexer"
+	"github.com/dim4egster/qmallgo/indexer/gindexer"
 	"github.com/dim4egster/qmallgo/ipcs"
 	"github.com/dim4egster/qmallgo/message"
 	"github.com/dim4egster/qmallgo/network"
 	"github.com/dim4egster/qmallgo/network/dialer"
 	"github.com/dim4egster/qmallgo/network/peer"
+	"github.com/dim4egster/qmallgo/network/recorder"
 	"github.com/dim4egster/qmallgo/network/throttling"
 	"github.com/dim4egster/qmallgo/snow"
 	"github.com/dim4egster/qmallgo/snow/engine/common"
@@ -55,8 +61,11 @@ import (
 	"github.com/dim4egster/qmallgo/snow/networking/tracker"
 	"github.com/dim4egster/qmallgo/snow/uptime"
 	"github.com/dim4egster/qmallgo/snow/validators"
+	"github.com/dim4egster/qmallgo/trace"
 	"github.com/dim4egster/qmallgo/utils"
 	"github.com/dim4egster/qmallgo/utils/constants"
+	"github.com/dim4egster/qmallgo/utils/crypto/keychain"
+	"github.com/dim4egster/qmallgo/utils/crypto/keychain/gkeychain"
 	"github.com/dim4egster/qmallgo/utils/filesystem"
 	"github.com/dim4egster/qmallgo/utils/hashing"
 	"github.com/dim4egster/qmallgo/utils/ips"
@@ -70,20 +79,29 @@ import (
 	"github.com/dim4egster/qmallgo/utils/wrappers"
 	"github.com/dim4egster/qmallgo/version"
 	"github.com/dim4egster/qmallgo/vms/avm"
+	"github.com/dim4egster/qmallgo/vms/avm/gavm"
+	"github.com/dim4egster/qmallgo/vms/components/index"
 	"github.com/dim4egster/qmallgo/vms/nftfx"
 	"github.com/dim4egster/qmallgo/vms/platformvm"
 	"github.com/dim4egster/qmallgo/vms/platformvm/config"
+	"github.com/dim4egster/qmallgo/vms/platformvm/gplatformvm"
 	"github.com/dim4egster/qmallgo/vms/platformvm/signer"
 	"github.com/dim4egster/qmallgo/vms/propertyfx"
 	"github.com/dim4egster/qmallgo/vms/registry"
 	"github.com/dim4egster/qmallgo/vms/secp256k1fx"
 
 	ipcsapi "github.com/dim4egster/qmallgo/api/ipcs"
+	avmpb "github.com/dim4egster/qmallgo/proto/pb/avm"
+	healthpb "github.com/dim4egster/qmallgo/proto/pb/health"
+	indexpb "github.com/dim4egster/qmallgo/proto/pb/index"
+	infopb "github.com/dim4egster/qmallgo/proto/pb/info"
+	platformvmpb "github.com/dim4egster/qmallgo/proto/pb/platformvm"
 )
 
 var (
 	genesisHashKey  = []byte("genesisID")
 	indexerDBPrefix = []byte{0x00}
+	authDBPrefix    = []byte("auth")
 
 	errInvalidTLSKey = errors.New("invalid TLS key")
 	errShuttingDown  = errors.New("server shutting down")
@@ -110,6 +128,11 @@ type Node struct {
 
 	// Handles calls to Keystore API
 	keystore keystore.Keystore
+	// Signs for the keystore services if an external signer is configured.
+	// Nil otherwise.
+	keychain keychain.Keychain
+	// Stops the external signer. Nil if no external signer is configured.
+	stopKeychain func()
 
 	// Manages shared memory
 	sharedMemory *atomic.Memory
@@ -117,6 +140,14 @@ type Node struct {
 	// Monitors node health and runs health checks
 	health health.Health
 
+	// Serves the info API. Nil if the info API is disabled.
+	infoService *info.Info
+
+	// Hold the API services of the bootstrapped P-chain and X-chain, which
+	// the gRPC API calls.
+	platformServices *platformvm.Services
+	avmServices      *avm.Services
+
 	// Build and parse messages, for both network layer and chain manager
 	msgCreator          message.Creator
 	msgCreatorWithProto message.Creator
@@ -129,6 +160,10 @@ type Node struct {
 
 	uptimeCalculator uptime.LockedCalculator
 
+	// Records spans of message handling and VM calls. Noop if tracing is
+	// disabled.
+	tracer trace.Tracer
+
 	// dispatcher for events as they happen in consensus
 	DecisionAcceptorGroup  snow.AcceptorGroup
 	ConsensusAcceptorGroup snow.AcceptorGroup
@@ -237,6 +272,21 @@ func (n *Node) initNetworking(primaryNetVdrs validators.Set) error {
 		)
 	}
 
+	if n.Config.NetworkConfig.RecorderEnabled {
+		n.Config.NetworkConfig.Recorder, err = recorder.New(
+			n.Config.NetworkConfig.RecorderConfig,
+			n.Log,
+			n.networkNamespace,
+			n.MetricsRegisterer,
+		)
+		if err != nil {
+			return fmt.Errorf("couldn't initialize the network recorder: %w", err)
+		}
+		n.Log.Warn("network recording is enabled",
+			zap.String("directory", n.Config.NetworkConfig.RecorderConfig.Directory),
+		)
+	}
+
 	tlsConfig := peer.TLSConfig(n.Config.StakingTLSCert, n.tlsKeyLogWriterCloser)
 
 	// Configure benchlist
@@ -248,6 +298,9 @@ func (n *Node) initNetworking(primaryNetVdrs validators.Set) error {
 	n.uptimeCalculator = uptime.NewLockedCalculator()
 
 	consensusRouter := n.Config.ConsensusRouter
+	if n.Config.NetworkConfig.Recorder != nil {
+		consensusRouter = router.Record(consensusRouter, n.Config.NetworkConfig.Recorder)
+	}
 	if !n.Config.EnableStaking {
 		if err := primaryNetVdrs.AddWeight(n.ID, n.Config.DisabledStakingWeight); err != nil {
 			return err
@@ -302,6 +355,7 @@ func (n *Node) initNetworking(primaryNetVdrs validators.Set) error {
 	n.Config.NetworkConfig.ResourceTracker = n.resourceTracker
 	n.Config.NetworkConfig.CPUTargeter = n.cpuTargeter
 	n.Config.NetworkConfig.DiskTargeter = n.diskTargeter
+	n.Config.NetworkConfig.Tracer = n.tracer
 
 	n.Net, err = network.NewNetwork(
 		&n.Config.NetworkConfig,
@@ -434,6 +488,16 @@ func (n *Node) Dispatch() error {
 		}
 	}
 
+	if n.Config.NetworkConfig.Recorder != nil {
+		err := n.Config.NetworkConfig.Recorder.Close()
+		if err != nil {
+			n.Log.Error("closing network recording failed",
+				zap.String("directory", n.Config.NetworkConfig.RecorderConfig.Directory),
+				zap.Error(err),
+			)
+		}
+	}
+
 	// Wait until the node is done shutting down before returning
 	n.DoneShuttingDown.Wait()
 	return err
@@ -454,13 +518,16 @@ func (n *Node) initDatabase() error {
 	switch n.Config.DatabaseConfig.Name {
 	case leveldb.Name:
 		dbManager, err = manager.NewLevelDB(n.Config.DatabaseConfig.Path, n.Config.DatabaseConfig.Config, n.Log, version.CurrentDatabase, "db_internal", n.MetricsRegisterer)
+	case pebbledb.Name:
+		dbManager, err = manager.NewPebbleDB(n.Config.DatabaseConfig.Path, n.Config.DatabaseConfig.Config, n.Log, version.CurrentDatabase, "db_internal", n.MetricsRegisterer)
 	case memdb.Name:
 		dbManager = manager.NewMemDB(version.CurrentDatabase)
 	default:
 		err = fmt.Errorf(
-			"db-type was %q but should have been one of {%s, %s}",
+			"db-type was %q but should have been one of {%s, %s, %s}",
 			n.Config.DatabaseConfig.Name,
 			leveldb.Name,
+			pebbledb.Name,
 			memdb.Name,
 		)
 	}
@@ -536,11 +603,23 @@ func (n *Node) initIPCs() error {
 	}
 
 	var err error
-	n.IPCs, err = ipcs.NewChainIPCs(n.Log, n.Config.IPCPath, n.Config.NetworkID, n.ConsensusAcceptorGroup, n.DecisionAcceptorGroup, chainIDs)
+	n.IPCs, err = ipcs.NewChainIPCs(
+		n.Log,
+		n.Config.IPCPath,
+		n.Config.NetworkID,
+		n.ConsensusAcceptorGroup,
+		n.DecisionAcceptorGroup,
+		chainIDs,
+		n.Config.IPCSocketConfig,
+		n.indexer,
+		n.MetricsRegisterer,
+	)
 	return err
 }
 
 // Initialize [n.indexer].
+// Must be called before [n.IPCs] are initialized, since they replay events
+// from the index.
 // Should only be called after [n.DB], [n.DecisionAcceptorGroup],
 // [n.ConsensusAcceptorGroup], [n.Log], [n.APIServer], [n.chainManager] are
 // initialized
@@ -583,26 +662,37 @@ func (n *Node) initChains(genesisBytes []byte) {
 }
 
 // initAPIServer initializes the server that handles HTTP calls
+// Assumes n.DB and n.MetricsRegisterer are already set
 func (n *Node) initAPIServer() error {
 	n.Log.Info("initializing API server")
 	n.APIServer = server.New()
 
-	if !n.Config.APIRequireAuthToken {
-		n.APIServer.Initialize(
-			n.Log,
-			n.LogFactory,
-			n.Config.HTTPHost,
-			n.Config.HTTPPort,
-			n.Config.APIAllowedOrigins,
-			n.Config.ShutdownTimeout,
-			n.ID,
-		)
-		return nil
+	var (
+		wrappers []server.Wrapper
+		a        auth.Auth
+	)
+	if n.Config.APIRequireAuthToken {
+		var err error
+		a, err = auth.New(n.Log, "auth", n.Config.APIAuthPassword, n.Config.APIMaxRequestBodySize, prefixdb.New(authDBPrefix, n.DB))
+		if err != nil {
+			return err
+		}
+		wrappers = append(wrappers, a)
 	}
 
-	a, err := auth.New(n.Log, "auth", n.Config.APIAuthPassword)
-	if err != nil {
-		return err
+	// The rate limiter wraps the auth handler so that unauthorized requests
+	// are also limited. Requests with a valid auth token are also limited by
+	// that token.
+	if n.Config.RateLimitConfig.Enabled {
+		var tokenVerifier server.TokenVerifier
+		if a != nil {
+			tokenVerifier = a
+		}
+		rateLimiter, err := server.NewRateLimiter(n.Log, n.Config.RateLimitConfig, n.Config.APIMaxRequestBodySize, tokenVerifier, "api", n.MetricsRegisterer)
+		if err != nil {
+			return err
+		}
+		wrappers = append(wrappers, rateLimiter)
 	}
 
 	n.APIServer.Initialize(
@@ -611,11 +701,17 @@ func (n *Node) initAPIServer() error {
 		n.Config.HTTPHost,
 		n.Config.HTTPPort,
 		n.Config.APIAllowedOrigins,
+		n.Config.APIMaxBatchSize,
+		n.Config.APIMaxRequestBodySize,
 		n.Config.ShutdownTimeout,
 		n.ID,
-		a,
+		wrappers...,
 	)
 
+	if !n.Config.APIRequireAuthToken {
+		return nil
+	}
+
 	// only create auth service if token authorization is required
 	n.Log.Info("API authorization is enabled. Auth tokens must be passed in the header of API requests, except requests to the auth service.")
 	authService, err := a.CreateHandler()
@@ -668,10 +764,17 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 		cChainID,
 	)
 
+	// Tracks how quickly and reliably peers respond to requests
+	healthTracker, err := tracker.NewHealthTracker(n.MetricsRegisterer, n.Config.SystemTrackerHealthHalflife)
+	if err != nil {
+		return err
+	}
+
 	// Manages network timeouts
 	timeoutManager, err := timeout.NewManager(
 		&n.Config.AdaptiveTimeoutConfig,
 		n.benchlistManager,
+		healthTracker,
 		"requests",
 		n.MetricsRegisterer,
 	)
@@ -701,6 +804,7 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 	n.chainManager = chains.New(&chains.ManagerConfig{
 		StakingEnabled:                          n.Config.EnableStaking,
 		StakingCert:                             n.Config.StakingTLSCert,
+		StakingBLSKey:                           n.Config.StakingSigningKey,
 		Log:                                     n.Log,
 		LogFactory:                              n.LogFactory,
 		VMManager:                               n.Config.VMManager,
@@ -717,6 +821,7 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 		NetworkID:                               n.Config.NetworkID,
 		Server:                                  n.APIServer,
 		Keystore:                                n.keystore,
+		Keychain:                                n.keychain,
 		AtomicMemory:                            n.sharedMemory,
 		AVAXAssetID:                             avaxAssetID,
 		XChainID:                                xChainID,
@@ -729,9 +834,12 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 		ShutdownNodeFunc:                        n.Shutdown,
 		MeterVMEnabled:                          n.Config.MeterVMEnabled,
 		Metrics:                                 n.MetricsGatherer,
+		TracingEnabled:                          n.Config.TraceConfig.Enabled,
+		Tracer:                                  n.tracer,
 		SubnetConfigs:                           n.Config.SubnetConfigs,
 		ChainConfigs:                            n.Config.ChainConfigs,
 		ConsensusGossipFrequency:                n.Config.ConsensusGossipFrequency,
+		ConsensusMessageLanes:                   n.Config.ConsensusMessageLanes,
 		GossipConfig:                            n.Config.GossipConfig,
 		BootstrapMaxTimeGetAncestors:            n.Config.BootstrapMaxTimeGetAncestors,
 		BootstrapAncestorsMaxContainersSent:     n.Config.BootstrapAncestorsMaxContainersSent,
@@ -740,6 +848,7 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 		ApricotPhase4MinPChainHeight:            version.GetApricotPhase4MinPChainHeight(n.Config.NetworkID),
 		BlueberryTime:                           version.GetBlueberryTime(n.Config.NetworkID),
 		ResourceTracker:                         n.resourceTracker,
+		HealthTracker:                           healthTracker,
 		StateSyncBeacons:                        n.Config.StateSyncIDs,
 	})
 
@@ -761,6 +870,9 @@ func (n *Node) initVMs() error {
 		vdrs = validators.NewManager()
 	}
 
+	n.platformServices = platformvm.NewServices()
+	n.avmServices = avm.NewServices()
+
 	vmRegisterer := registry.NewVMRegisterer(registry.VMRegistererConfig{
 		APIServer: n.APIServer,
 		Log:       n.Log,
@@ -795,15 +907,19 @@ func (n *Node) initVMs() error {
 				MinStakeDuration:              n.Config.MinStakeDuration,
 				MaxStakeDuration:              n.Config.MaxStakeDuration,
 				RewardConfig:                  n.Config.RewardConfig,
+				ValidatorHistoryBackfillDepth: n.Config.ValidatorHistoryBackfillDepth,
 				ApricotPhase3Time:             version.GetApricotPhase3Time(n.Config.NetworkID),
 				ApricotPhase5Time:             version.GetApricotPhase5Time(n.Config.NetworkID),
 				BlueberryTime:                 version.GetBlueberryTime(n.Config.NetworkID),
 			},
+			Services: n.platformServices,
 		}),
 		vmRegisterer.Register(constants.AVMID, &avm.Factory{
 			TxFee:            n.Config.TxFee,
 			CreateAssetTxFee: n.Config.CreateAssetTxFee,
 			BlueberryTime:    version.GetBlueberryTime(n.Config.NetworkID),
+			Services:         n.avmServices,
+			TxIndex:          n.getTxIndex,
 		}),
 		vmRegisterer.Register(constants.EVMID, &coreth.Factory{}),
 		n.Config.VMManager.RegisterFactory(secp256k1fx.ID, &secp256k1fx.Factory{}),
@@ -849,6 +965,17 @@ func (n *Node) initKeystoreAPI() error {
 	n.Log.Info("initializing keystore")
 	keystoreDB := n.DBManager.NewPrefixDBManager([]byte("keystore"))
 	n.keystore = keystore.New(n.Log, keystoreDB)
+	if n.Config.ExternalSignerPath != "" {
+		n.Log.Info("launching external signer",
+			zap.String("path", n.Config.ExternalSignerPath),
+		)
+		kc, stop, err := gkeychain.Launch(n.Config.ExternalSignerPath, n.Config.ExternalSignerArgs...)
+		if err != nil {
+			return fmt.Errorf("couldn't launch external signer: %w", err)
+		}
+		n.keychain = kc
+		n.stopKeychain = stop
+	}
 	keystoreHandler, err := n.keystore.CreateHandler()
 	if err != nil {
 		return err
@@ -866,11 +993,8 @@ func (n *Node) initKeystoreAPI() error {
 }
 
 // initMetricsAPI initializes the Metrics API
-// Assumes n.APIServer is already set
+// Assumes n.APIServer, n.MetricsRegisterer and n.MetricsGatherer are already set
 func (n *Node) initMetricsAPI() error {
-	n.MetricsRegisterer = prometheus.NewRegistry()
-	n.MetricsGatherer = metrics.NewMultiGatherer()
-
 	if !n.Config.MetricsAPIEnabled {
 		n.Log.Info("skipping metrics API initialization because it has been disabled")
 		return nil
@@ -967,7 +1091,7 @@ func (n *Node) initInfoAPI() error {
 	n.Log.Info("initializing info API")
 
 	primaryValidators, _ := n.vdrs.GetValidators(constants.PrimaryNetworkID)
-	service, err := info.NewService(
+	n.infoService = info.New(
 		info.Parameters{
 			Version:                       version.CurrentApp,
 			NodeID:                        n.ID,
@@ -992,10 +1116,103 @@ func (n *Node) initInfoAPI() error {
 		primaryValidators,
 		n.benchlistManager,
 	)
+	handler, err := n.infoService.CreateHandler()
 	if err != nil {
 		return err
 	}
-	return n.APIServer.AddRoute(service, &sync.RWMutex{}, "info", "")
+	return n.APIServer.AddRoute(handler, &sync.RWMutex{}, "info", "")
+}
+
+// initGRPCAPI registers the gRPC services that mirror the JSON-RPC APIs. The
+// gRPC services call the services of the JSON-RPC APIs directly.
+// Assumes n.APIServer, n.health, n.indexer and the chain aliases are already
+// initialized
+func (n *Node) initGRPCAPI() error {
+	if !n.Config.GRPCAPIEnabled {
+		n.Log.Info("skipping gRPC API initialization because it has been disabled")
+		return nil
+	}
+
+	n.Log.Info("initializing gRPC API")
+	if n.Config.InfoAPIEnabled {
+		infopb.RegisterInfoServer(n.APIServer, ginfo.NewServer(n.infoService))
+	}
+	if n.Config.HealthAPIEnabled {
+		healthpb.RegisterHealthServer(n.APIServer, ghealth.NewServer(n.health))
+	}
+	if n.Config.IndexAPIEnabled {
+		indexpb.RegisterIndexServer(n.APIServer, gindexer.NewServer(n.getIndex))
+	}
+
+	// The chains add their services once they are bootstrapped, so requests
+	// to them fail until then.
+	xChainID, err := n.chainManager.Lookup("X")
+	if err != nil {
+		return fmt.Errorf("couldn't look up the X-chain: %w", err)
+	}
+	platformvmpb.RegisterPlatformVMServer(n.APIServer, gplatformvm.NewServer(n.platformServices, constants.PlatformChainID))
+	avmpb.RegisterAVMServer(n.APIServer, gavm.NewServer(n.avmServices, xChainID))
+	return nil
+}
+
+// getIndex returns the index named [name], e.g. "X/tx", if it exists.
+func (n *Node) getIndex(name string) (indexer.Index, bool) {
+	chainAlias, indexType, ok := strings.Cut(name, "/")
+	if !ok {
+		return nil, false
+	}
+	chainID, err := n.chainManager.Lookup(chainAlias)
+	if err != nil {
+		return nil, false
+	}
+	consensusIndex, ok := n.indexer.GetConsensusIndex(chainID)
+	if !ok {
+		return nil, false
+	}
+	decisionsIndex, _ := n.indexer.GetDecisionsIndex(chainID)
+
+	// Linear chains only have a block index, which is both their consensus
+	// and decisions index.
+	isLinear := consensusIndex == decisionsIndex
+	switch indexType {
+	case "block":
+		return consensusIndex, isLinear
+	case "vtx":
+		return consensusIndex, !isLinear
+	case "tx":
+		return decisionsIndex, !isLinear
+	default:
+		return nil, false
+	}
+}
+
+// getTxIndex returns the index of the accepted txs of the DAG chain [chainID],
+// if the chain is indexed.
+func (n *Node) getTxIndex(chainID ids.ID) (index.ContainerIndex, bool) {
+	txIndex, ok := n.indexer.GetDecisionsIndex(chainID)
+	if !ok {
+		return nil, false
+	}
+	return &containerIndex{index: txIndex}, true
+}
+
+var _ index.ContainerIndex = &containerIndex{}
+
+// containerIndex exposes an indexer.Index to the VMs
+type containerIndex struct {
+	index indexer.Index
+}
+
+func (c *containerIndex) GetAccepted(containerID ids.ID) (uint64, time.Time, error) {
+	containerIndex, err := c.index.GetIndex(containerID)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	container, err := c.index.GetContainerByID(containerID)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	return containerIndex, time.Unix(0, container.Timestamp), nil
 }
 
 // initHealthAPI initializes the Health API service
@@ -1236,6 +1453,15 @@ func (n *Node) Initialize(
 		return fmt.Errorf("problem initializing node beacons: %w", err)
 	}
 
+	n.MetricsRegisterer = prometheus.NewRegistry()
+	n.MetricsGatherer = metrics.NewMultiGatherer()
+
+	// The database is needed by the API server to persist auth token
+	// revocations
+	if err := n.initDatabase(); err != nil { // Set up the node's database
+		return fmt.Errorf("problem initializing database: %w", err)
+	}
+
 	if err := n.initAPIServer(); err != nil { // Start the API Server
 		return fmt.Errorf("couldn't initialize API server: %w", err)
 	}
@@ -1244,16 +1470,20 @@ func (n *Node) Initialize(
 		return fmt.Errorf("couldn't initialize metrics API: %w", err)
 	}
 
-	if err := n.initDatabase(); err != nil { // Set up the node's database
-		return fmt.Errorf("problem initializing database: %w", err)
-	}
-
 	if err := n.initKeystoreAPI(); err != nil { // Start the Keystore API
 		return fmt.Errorf("couldn't initialize keystore API: %w", err)
 	}
 
 	n.initSharedMemory() // Initialize shared memory
 
+	n.tracer, err = trace.New(n.Config.TraceConfig)
+	if err != nil {
+		return fmt.Errorf("couldn't initialize tracer: %w", err)
+	}
+	if n.Config.TraceConfig.Enabled {
+		n.Config.ConsensusRouter = router.Trace(n.Config.ConsensusRouter, n.tracer)
+	}
+
 	// message.Creator is shared between networking, chainManager and the engine.
 	// It must be initiated before networking (initNetworking), chain manager (initChainManager)
 	// and the engine (initChains) but after the metrics (initMetricsAPI)
@@ -1271,7 +1501,8 @@ func (n *Node) Initialize(
 	n.msgCreatorWithProto, err = message.NewCreatorWithProto(
 		n.MetricsRegisterer,
 		n.networkNamespace,
-		n.Config.NetworkConfig.CompressionEnabled,
+		n.Config.NetworkConfig.CompressionType,
+		n.Config.NetworkConfig.ZstdDictionary,
 		n.Config.NetworkConfig.MaximumInboundMessageTimeout,
 	)
 	if err != nil {
@@ -1314,6 +1545,9 @@ func (n *Node) Initialize(
 	if err := n.initInfoAPI(); err != nil { // Start the Info API
 		return fmt.Errorf("couldn't initialize info API: %w", err)
 	}
+	if err := n.initIndexer(); err != nil {
+		return fmt.Errorf("couldn't initialize indexer: %w", err)
+	}
 	if err := n.initIPCs(); err != nil { // Start the IPCs
 		return fmt.Errorf("couldn't initialize IPCs: %w", err)
 	}
@@ -1326,8 +1560,8 @@ func (n *Node) Initialize(
 	if err := n.initAPIAliases(n.Config.GenesisBytes); err != nil {
 		return fmt.Errorf("couldn't initialize API aliases: %w", err)
 	}
-	if err := n.initIndexer(); err != nil {
-		return fmt.Errorf("couldn't initialize indexer: %w", err)
+	if err := n.initGRPCAPI(); err != nil { // Start the gRPC API
+		return fmt.Errorf("couldn't initialize gRPC API: %w", err)
 	}
 
 	n.health.Start(n.Config.HealthCheckFreq)
@@ -1404,6 +1638,9 @@ func (n *Node) shutdown() {
 	// Make sure all plugin subprocesses are killed
 	n.Log.Info("cleaning up plugin subprocesses")
 	plugin.CleanupClients()
+	if n.stopKeychain != nil {
+		n.stopKeychain()
+	}
 
 	if n.DBManager != nil {
 		if err := n.DBManager.Close(); err != nil {
@@ -1413,6 +1650,14 @@ func (n *Node) shutdown() {
 		}
 	}
 
+	if n.tracer != nil {
+		if err := n.tracer.Close(); err != nil {
+			n.Log.Warn("error during tracer shutdown",
+				zap.Error(err),
+			)
+		}
+	}
+
 	n.DoneShuttingDown.Done()
 	n.Log.Info("finished node shutdown")
 }
@@ -0,0 +1,353 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: avm/avm.proto
+
+package avm
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// AVMClient is the client API for AVM service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AVMClient interface {
+	GetTx(ctx context.Context, in *GetTxRequest, opts ...grpc.CallOption) (*GetTxResponse, error)
+	GetTxStatus(ctx context.Context, in *GetTxStatusRequest, opts ...grpc.CallOption) (*GetTxStatusResponse, error)
+	GetBalance(ctx context.Context, in *GetBalanceRequest, opts ...grpc.CallOption) (*GetBalanceResponse, error)
+	GetAllBalances(ctx context.Context, in *GetAllBalancesRequest, opts ...grpc.CallOption) (*GetAllBalancesResponse, error)
+	GetAssetDescription(ctx context.Context, in *GetAssetDescriptionRequest, opts ...grpc.CallOption) (*GetAssetDescriptionResponse, error)
+	GetUTXOs(ctx context.Context, in *GetUTXOsRequest, opts ...grpc.CallOption) (*GetUTXOsResponse, error)
+	// StreamUTXOs streams every UTXO referenced by [addresses], fetching them
+	// from the chain [page_size] at a time.
+	StreamUTXOs(ctx context.Context, in *StreamUTXOsRequest, opts ...grpc.CallOption) (AVM_StreamUTXOsClient, error)
+}
+
+type aVMClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAVMClient(cc grpc.ClientConnInterface) AVMClient {
+	return &aVMClient{cc}
+}
+
+func (c *aVMClient) GetTx(ctx context.Context, in *GetTxRequest, opts ...grpc.CallOption) (*GetTxResponse, error) {
+	out := new(GetTxResponse)
+	err := c.cc.Invoke(ctx, "/avm.AVM/GetTx", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aVMClient) GetTxStatus(ctx context.Context, in *GetTxStatusRequest, opts ...grpc.CallOption) (*GetTxStatusResponse, error) {
+	out := new(GetTxStatusResponse)
+	err := c.cc.Invoke(ctx, "/avm.AVM/GetTxStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aVMClient) GetBalance(ctx context.Context, in *GetBalanceRequest, opts ...grpc.CallOption) (*GetBalanceResponse, error) {
+	out := new(GetBalanceResponse)
+	err := c.cc.Invoke(ctx, "/avm.AVM/GetBalance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aVMClient) GetAllBalances(ctx context.Context, in *GetAllBalancesRequest, opts ...grpc.CallOption) (*GetAllBalancesResponse, error) {
+	out := new(GetAllBalancesResponse)
+	err := c.cc.Invoke(ctx, "/avm.AVM/GetAllBalances", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aVMClient) GetAssetDescription(ctx context.Context, in *GetAssetDescriptionRequest, opts ...grpc.CallOption) (*GetAssetDescriptionResponse, error) {
+	out := new(GetAssetDescriptionResponse)
+	err := c.cc.Invoke(ctx, "/avm.AVM/GetAssetDescription", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aVMClient) GetUTXOs(ctx context.Context, in *GetUTXOsRequest, opts ...grpc.CallOption) (*GetUTXOsResponse, error) {
+	out := new(GetUTXOsResponse)
+	err := c.cc.Invoke(ctx, "/avm.AVM/GetUTXOs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aVMClient) StreamUTXOs(ctx context.Context, in *StreamUTXOsRequest, opts ...grpc.CallOption) (AVM_StreamUTXOsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &AVM_ServiceDesc.Streams[0], "/avm.AVM/StreamUTXOs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &aVMStreamUTXOsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type AVM_StreamUTXOsClient interface {
+	Recv() (*UTXO, error)
+	grpc.ClientStream
+}
+
+type aVMStreamUTXOsClient struct {
+	grpc.ClientStream
+}
+
+func (x *aVMStreamUTXOsClient) Recv() (*UTXO, error) {
+	m := new(UTXO)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// AVMServer is the server API for AVM service.
+// All implementations must embed UnimplementedAVMServer
+// for forward compatibility
+type AVMServer interface {
+	GetTx(context.Context, *GetTxRequest) (*GetTxResponse, error)
+	GetTxStatus(context.Context, *GetTxStatusRequest) (*GetTxStatusResponse, error)
+	GetBalance(context.Context, *GetBalanceRequest) (*GetBalanceResponse, error)
+	GetAllBalances(context.Context, *GetAllBalancesRequest) (*GetAllBalancesResponse, error)
+	GetAssetDescription(context.Context, *GetAssetDescriptionRequest) (*GetAssetDescriptionResponse, error)
+	GetUTXOs(context.Context, *GetUTXOsRequest) (*GetUTXOsResponse, error)
+	// StreamUTXOs streams every UTXO referenced by [addresses], fetching them
+	// from the chain [page_size] at a time.
+	StreamUTXOs(*StreamUTXOsRequest, AVM_StreamUTXOsServer) error
+	mustEmbedUnimplementedAVMServer()
+}
+
+// UnimplementedAVMServer must be embedded to have forward compatible implementations.
+type UnimplementedAVMServer struct {
+}
+
+func (UnimplementedAVMServer) GetTx(context.Context, *GetTxRequest) (*GetTxResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTx not implemented")
+}
+func (UnimplementedAVMServer) GetTxStatus(context.Context, *GetTxStatusRequest) (*GetTxStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTxStatus not implemented")
+}
+func (UnimplementedAVMServer) GetBalance(context.Context, *GetBalanceRequest) (*GetBalanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBalance not implemented")
+}
+func (UnimplementedAVMServer) GetAllBalances(context.Context, *GetAllBalancesRequest) (*GetAllBalancesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAllBalances not implemented")
+}
+func (UnimplementedAVMServer) GetAssetDescription(context.Context, *GetAssetDescriptionRequest) (*GetAssetDescriptionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAssetDescription not implemented")
+}
+func (UnimplementedAVMServer) GetUTXOs(context.Context, *GetUTXOsRequest) (*GetUTXOsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUTXOs not implemented")
+}
+func (UnimplementedAVMServer) StreamUTXOs(*StreamUTXOsRequest, AVM_StreamUTXOsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamUTXOs not implemented")
+}
+func (UnimplementedAVMServer) mustEmbedUnimplementedAVMServer() {}
+
+// UnsafeAVMServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AVMServer will
+// result in compilation errors.
+type UnsafeAVMServer interface {
+	mustEmbedUnimplementedAVMServer()
+}
+
+func RegisterAVMServer(s grpc.ServiceRegistrar, srv AVMServer) {
+	s.RegisterService(&AVM_ServiceDesc, srv)
+}
+
+func _AVM_GetTx_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTxRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AVMServer).GetTx(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/avm.AVM/GetTx",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AVMServer).GetTx(ctx, req.(*GetTxRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AVM_GetTxStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTxStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AVMServer).GetTxStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/avm.AVM/GetTxStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AVMServer).GetTxStatus(ctx, req.(*GetTxStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AVM_GetBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AVMServer).GetBalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/avm.AVM/GetBalance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AVMServer).GetBalance(ctx, req.(*GetBalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AVM_GetAllBalances_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAllBalancesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AVMServer).GetAllBalances(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/avm.AVM/GetAllBalances",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AVMServer).GetAllBalances(ctx, req.(*GetAllBalancesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AVM_GetAssetDescription_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAssetDescriptionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AVMServer).GetAssetDescription(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/avm.AVM/GetAssetDescription",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AVMServer).GetAssetDescription(ctx, req.(*GetAssetDescriptionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AVM_GetUTXOs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUTXOsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AVMServer).GetUTXOs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/avm.AVM/GetUTXOs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AVMServer).GetUTXOs(ctx, req.(*GetUTXOsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AVM_StreamUTXOs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamUTXOsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AVMServer).StreamUTXOs(m, &aVMStreamUTXOsServer{stream})
+}
+
+type AVM_StreamUTXOsServer interface {
+	Send(*UTXO) error
+	grpc.ServerStream
+}
+
+type aVMStreamUTXOsServer struct {
+	grpc.ServerStream
+}
+
+func (x *aVMStreamUTXOsServer) Send(m *UTXO) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// AVM_ServiceDesc is the grpc.ServiceDesc for AVM service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AVM_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "avm.AVM",
+	HandlerType: (*AVMServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetTx",
+			Handler:    _AVM_GetTx_Handler,
+		},
+		{
+			MethodName: "GetTxStatus",
+			Handler:    _AVM_GetTxStatus_Handler,
+		},
+		{
+			MethodName: "GetBalance",
+			Handler:    _AVM_GetBalance_Handler,
+		},
+		{
+			MethodName: "GetAllBalances",
+			Handler:    _AVM_GetAllBalances_Handler,
+		},
+		{
+			MethodName: "GetAssetDescription",
+			Handler:    _AVM_GetAssetDescription_Handler,
+		},
+		{
+			MethodName: "GetUTXOs",
+			Handler:    _AVM_GetUTXOs_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamUTXOs",
+			Handler:       _AVM_StreamUTXOs_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "avm/avm.proto",
+}
@@ -0,0 +1,1008 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.0
+// 	protoc        (unknown)
+// source: info/info.proto
+
+package info
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetNodeVersionResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version         string            `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	DatabaseVersion string            `protobuf:"bytes,2,opt,name=database_version,json=databaseVersion,proto3" json:"database_version,omitempty"`
+	GitCommit       string            `protobuf:"bytes,3,opt,name=git_commit,json=gitCommit,proto3" json:"git_commit,omitempty"`
+	VmVersions      map[string]string `protobuf:"bytes,4,rep,name=vm_versions,json=vmVersions,proto3" json:"vm_versions,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *GetNodeVersionResponse) Reset() {
+	*x = GetNodeVersionResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_info_info_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetNodeVersionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNodeVersionResponse) ProtoMessage() {}
+
+func (x *GetNodeVersionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_info_info_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNodeVersionResponse.ProtoReflect.Descriptor instead.
+func (*GetNodeVersionResponse) Descriptor() ([]byte, []int) {
+	return file_info_info_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetNodeVersionResponse) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *GetNodeVersionResponse) GetDatabaseVersion() string {
+	if x != nil {
+		return x.DatabaseVersion
+	}
+	return ""
+}
+
+func (x *GetNodeVersionResponse) GetGitCommit() string {
+	if x != nil {
+		return x.GitCommit
+	}
+	return ""
+}
+
+func (x *GetNodeVersionResponse) GetVmVersions() map[string]string {
+	if x != nil {
+		return x.VmVersions
+	}
+	return nil
+}
+
+type GetNodeIDResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	NodeId []byte `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	// BLS public key and proof of possession of the node, if it has a BLS key
+	BlsPublicKey         []byte `protobuf:"bytes,2,opt,name=bls_public_key,json=blsPublicKey,proto3" json:"bls_public_key,omitempty"`
+	BlsProofOfPossession []byte `protobuf:"bytes,3,opt,name=bls_proof_of_possession,json=blsProofOfPossession,proto3" json:"bls_proof_of_possession,omitempty"`
+}
+
+func (x *GetNodeIDResponse) Reset() {
+	*x = GetNodeIDResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_info_info_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetNodeIDResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNodeIDResponse) ProtoMessage() {}
+
+func (x *GetNodeIDResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_info_info_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNodeIDResponse.ProtoReflect.Descriptor instead.
+func (*GetNodeIDResponse) Descriptor() ([]byte, []int) {
+	return file_info_info_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetNodeIDResponse) GetNodeId() []byte {
+	if x != nil {
+		return x.NodeId
+	}
+	return nil
+}
+
+func (x *GetNodeIDResponse) GetBlsPublicKey() []byte {
+	if x != nil {
+		return x.BlsPublicKey
+	}
+	return nil
+}
+
+func (x *GetNodeIDResponse) GetBlsProofOfPossession() []byte {
+	if x != nil {
+		return x.BlsProofOfPossession
+	}
+	return nil
+}
+
+type GetNodeIPResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ip string `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
+}
+
+func (x *GetNodeIPResponse) Reset() {
+	*x = GetNodeIPResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_info_info_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetNodeIPResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNodeIPResponse) ProtoMessage() {}
+
+func (x *GetNodeIPResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_info_info_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNodeIPResponse.ProtoReflect.Descriptor instead.
+func (*GetNodeIPResponse) Descriptor() ([]byte, []int) {
+	return file_info_info_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetNodeIPResponse) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+type GetNetworkIDResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	NetworkId uint32 `protobuf:"varint,1,opt,name=network_id,json=networkId,proto3" json:"network_id,omitempty"`
+}
+
+func (x *GetNetworkIDResponse) Reset() {
+	*x = GetNetworkIDResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_info_info_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetNetworkIDResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNetworkIDResponse) ProtoMessage() {}
+
+func (x *GetNetworkIDResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_info_info_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNetworkIDResponse.ProtoReflect.Descriptor instead.
+func (*GetNetworkIDResponse) Descriptor() ([]byte, []int) {
+	return file_info_info_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetNetworkIDResponse) GetNetworkId() uint32 {
+	if x != nil {
+		return x.NetworkId
+	}
+	return 0
+}
+
+type GetNetworkNameResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	NetworkName string `protobuf:"bytes,1,opt,name=network_name,json=networkName,proto3" json:"network_name,omitempty"`
+}
+
+func (x *GetNetworkNameResponse) Reset() {
+	*x = GetNetworkNameResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_info_info_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetNetworkNameResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNetworkNameResponse) ProtoMessage() {}
+
+func (x *GetNetworkNameResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_info_info_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNetworkNameResponse.ProtoReflect.Descriptor instead.
+func (*GetNetworkNameResponse) Descriptor() ([]byte, []int) {
+	return file_info_info_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetNetworkNameResponse) GetNetworkName() string {
+	if x != nil {
+		return x.NetworkName
+	}
+	return ""
+}
+
+type GetBlockchainIDRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Alias string `protobuf:"bytes,1,opt,name=alias,proto3" json:"alias,omitempty"`
+}
+
+func (x *GetBlockchainIDRequest) Reset() {
+	*x = GetBlockchainIDRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_info_info_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetBlockchainIDRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBlockchainIDRequest) ProtoMessage() {}
+
+func (x *GetBlockchainIDRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_info_info_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBlockchainIDRequest.ProtoReflect.Descriptor instead.
+func (*GetBlockchainIDRequest) Descriptor() ([]byte, []int) {
+	return file_info_info_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetBlockchainIDRequest) GetAlias() string {
+	if x != nil {
+		return x.Alias
+	}
+	return ""
+}
+
+type GetBlockchainIDResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BlockchainId []byte `protobuf:"bytes,1,opt,name=blockchain_id,json=blockchainId,proto3" json:"blockchain_id,omitempty"`
+}
+
+func (x *GetBlockchainIDResponse) Reset() {
+	*x = GetBlockchainIDResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_info_info_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetBlockchainIDResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBlockchainIDResponse) ProtoMessage() {}
+
+func (x *GetBlockchainIDResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_info_info_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBlockchainIDResponse.ProtoReflect.Descriptor instead.
+func (*GetBlockchainIDResponse) Descriptor() ([]byte, []int) {
+	return file_info_info_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetBlockchainIDResponse) GetBlockchainId() []byte {
+	if x != nil {
+		return x.BlockchainId
+	}
+	return nil
+}
+
+type IsBootstrappedRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// ID or alias of the chain
+	Chain string `protobuf:"bytes,1,opt,name=chain,proto3" json:"chain,omitempty"`
+}
+
+func (x *IsBootstrappedRequest) Reset() {
+	*x = IsBootstrappedRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_info_info_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IsBootstrappedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IsBootstrappedRequest) ProtoMessage() {}
+
+func (x *IsBootstrappedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_info_info_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IsBootstrappedRequest.ProtoReflect.Descriptor instead.
+func (*IsBootstrappedRequest) Descriptor() ([]byte, []int) {
+	return file_info_info_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *IsBootstrappedRequest) GetChain() string {
+	if x != nil {
+		return x.Chain
+	}
+	return ""
+}
+
+type IsBootstrappedResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	IsBootstrapped bool `protobuf:"varint,1,opt,name=is_bootstrapped,json=isBootstrapped,proto3" json:"is_bootstrapped,omitempty"`
+}
+
+func (x *IsBootstrappedResponse) Reset() {
+	*x = IsBootstrappedResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_info_info_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IsBootstrappedResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IsBootstrappedResponse) ProtoMessage() {}
+
+func (x *IsBootstrappedResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_info_info_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IsBootstrappedResponse.ProtoReflect.Descriptor instead.
+func (*IsBootstrappedResponse) Descriptor() ([]byte, []int) {
+	return file_info_info_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *IsBootstrappedResponse) GetIsBootstrapped() bool {
+	if x != nil {
+		return x.IsBootstrapped
+	}
+	return false
+}
+
+type GetTxFeeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TxFee                         uint64 `protobuf:"varint,1,opt,name=tx_fee,json=txFee,proto3" json:"tx_fee,omitempty"`
+	CreateAssetTxFee              uint64 `protobuf:"varint,2,opt,name=create_asset_tx_fee,json=createAssetTxFee,proto3" json:"create_asset_tx_fee,omitempty"`
+	CreateSubnetTxFee             uint64 `protobuf:"varint,3,opt,name=create_subnet_tx_fee,json=createSubnetTxFee,proto3" json:"create_subnet_tx_fee,omitempty"`
+	TransformSubnetTxFee          uint64 `protobuf:"varint,4,opt,name=transform_subnet_tx_fee,json=transformSubnetTxFee,proto3" json:"transform_subnet_tx_fee,omitempty"`
+	CreateBlockchainTxFee         uint64 `protobuf:"varint,5,opt,name=create_blockchain_tx_fee,json=createBlockchainTxFee,proto3" json:"create_blockchain_tx_fee,omitempty"`
+	AddPrimaryNetworkValidatorFee uint64 `protobuf:"varint,6,opt,name=add_primary_network_validator_fee,json=addPrimaryNetworkValidatorFee,proto3" json:"add_primary_network_validator_fee,omitempty"`
+	AddPrimaryNetworkDelegatorFee uint64 `protobuf:"varint,7,opt,name=add_primary_network_delegator_fee,json=addPrimaryNetworkDelegatorFee,proto3" json:"add_primary_network_delegator_fee,omitempty"`
+	AddSubnetValidatorFee         uint64 `protobuf:"varint,8,opt,name=add_subnet_validator_fee,json=addSubnetValidatorFee,proto3" json:"add_subnet_validator_fee,omitempty"`
+	AddSubnetDelegatorFee         uint64 `protobuf:"varint,9,opt,name=add_subnet_delegator_fee,json=addSubnetDelegatorFee,proto3" json:"add_subnet_delegator_fee,omitempty"`
+}
+
+func (x *GetTxFeeResponse) Reset() {
+	*x = GetTxFeeResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_info_info_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetTxFeeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTxFeeResponse) ProtoMessage() {}
+
+func (x *GetTxFeeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_info_info_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTxFeeResponse.ProtoReflect.Descriptor instead.
+func (*GetTxFeeResponse) Descriptor() ([]byte, []int) {
+	return file_info_info_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetTxFeeResponse) GetTxFee() uint64 {
+	if x != nil {
+		return x.TxFee
+	}
+	return 0
+}
+
+func (x *GetTxFeeResponse) GetCreateAssetTxFee() uint64 {
+	if x != nil {
+		return x.CreateAssetTxFee
+	}
+	return 0
+}
+
+func (x *GetTxFeeResponse) GetCreateSubnetTxFee() uint64 {
+	if x != nil {
+		return x.CreateSubnetTxFee
+	}
+	return 0
+}
+
+func (x *GetTxFeeResponse) GetTransformSubnetTxFee() uint64 {
+	if x != nil {
+		return x.TransformSubnetTxFee
+	}
+	return 0
+}
+
+func (x *GetTxFeeResponse) GetCreateBlockchainTxFee() uint64 {
+	if x != nil {
+		return x.CreateBlockchainTxFee
+	}
+	return 0
+}
+
+func (x *GetTxFeeResponse) GetAddPrimaryNetworkValidatorFee() uint64 {
+	if x != nil {
+		return x.AddPrimaryNetworkValidatorFee
+	}
+	return 0
+}
+
+func (x *GetTxFeeResponse) GetAddPrimaryNetworkDelegatorFee() uint64 {
+	if x != nil {
+		return x.AddPrimaryNetworkDelegatorFee
+	}
+	return 0
+}
+
+func (x *GetTxFeeResponse) GetAddSubnetValidatorFee() uint64 {
+	if x != nil {
+		return x.AddSubnetValidatorFee
+	}
+	return 0
+}
+
+func (x *GetTxFeeResponse) GetAddSubnetDelegatorFee() uint64 {
+	if x != nil {
+		return x.AddSubnetDelegatorFee
+	}
+	return 0
+}
+
+type UptimeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RewardingStakePercentage  float64 `protobuf:"fixed64,1,opt,name=rewarding_stake_percentage,json=rewardingStakePercentage,proto3" json:"rewarding_stake_percentage,omitempty"`
+	WeightedAveragePercentage float64 `protobuf:"fixed64,2,opt,name=weighted_average_percentage,json=weightedAveragePercentage,proto3" json:"weighted_average_percentage,omitempty"`
+}
+
+func (x *UptimeResponse) Reset() {
+	*x = UptimeResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_info_info_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UptimeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UptimeResponse) ProtoMessage() {}
+
+func (x *UptimeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_info_info_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UptimeResponse.ProtoReflect.Descriptor instead.
+func (*UptimeResponse) Descriptor() ([]byte, []int) {
+	return file_info_info_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *UptimeResponse) GetRewardingStakePercentage() float64 {
+	if x != nil {
+		return x.RewardingStakePercentage
+	}
+	return 0
+}
+
+func (x *UptimeResponse) GetWeightedAveragePercentage() float64 {
+	if x != nil {
+		return x.WeightedAveragePercentage
+	}
+	return 0
+}
+
+var File_info_info_proto protoreflect.FileDescriptor
+
+var file_info_info_proto_rawDesc = []byte{
+	0x0a, 0x0f, 0x69, 0x6e, 0x66, 0x6f, 0x2f, 0x69, 0x6e, 0x66, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x12, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x22, 0x8a, 0x02, 0x0a, 0x16, 0x47, 0x65, 0x74, 0x4e, 0x6f, 0x64, 0x65,
+	0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x29, 0x0a, 0x10, 0x64, 0x61, 0x74,
+	0x61, 0x62, 0x61, 0x73, 0x65, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0f, 0x64, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x56, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x67, 0x69, 0x74, 0x5f, 0x63, 0x6f, 0x6d, 0x6d,
+	0x69, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x67, 0x69, 0x74, 0x43, 0x6f, 0x6d,
+	0x6d, 0x69, 0x74, 0x12, 0x4d, 0x0a, 0x0b, 0x76, 0x6d, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2c, 0x2e, 0x69, 0x6e, 0x66, 0x6f, 0x2e,
+	0x47, 0x65, 0x74, 0x4e, 0x6f, 0x64, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x56, 0x6d, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0a, 0x76, 0x6d, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x73, 0x1a, 0x3d, 0x0a, 0x0f, 0x56, 0x6d, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38,
+	0x01, 0x22, 0x89, 0x01, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x4e, 0x6f, 0x64, 0x65, 0x49, 0x44, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x6e, 0x6f, 0x64, 0x65, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x64,
+	0x12, 0x24, 0x0a, 0x0e, 0x62, 0x6c, 0x73, 0x5f, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x5f, 0x6b,
+	0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0c, 0x62, 0x6c, 0x73, 0x50, 0x75, 0x62,
+	0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79, 0x12, 0x35, 0x0a, 0x17, 0x62, 0x6c, 0x73, 0x5f, 0x70, 0x72,
+	0x6f, 0x6f, 0x66, 0x5f, 0x6f, 0x66, 0x5f, 0x70, 0x6f, 0x73, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x14, 0x62, 0x6c, 0x73, 0x50, 0x72, 0x6f, 0x6f,
+	0x66, 0x4f, 0x66, 0x50, 0x6f, 0x73, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x23, 0x0a,
+	0x11, 0x47, 0x65, 0x74, 0x4e, 0x6f, 0x64, 0x65, 0x49, 0x50, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02,
+	0x69, 0x70, 0x22, 0x35, 0x0a, 0x14, 0x47, 0x65, 0x74, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b,
+	0x49, 0x44, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x6e, 0x65,
+	0x74, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09,
+	0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x49, 0x64, 0x22, 0x3b, 0x0a, 0x16, 0x47, 0x65, 0x74,
+	0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x4e, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x6e, 0x65, 0x74, 0x77, 0x6f,
+	0x72, 0x6b, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x2e, 0x0a, 0x16, 0x47, 0x65, 0x74, 0x42, 0x6c, 0x6f,
+	0x63, 0x6b, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x49, 0x44, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x14, 0x0a, 0x05, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x22, 0x3e, 0x0a, 0x17, 0x47, 0x65, 0x74, 0x42, 0x6c, 0x6f,
+	0x63, 0x6b, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x23, 0x0a, 0x0d, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0c, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x63,
+	0x68, 0x61, 0x69, 0x6e, 0x49, 0x64, 0x22, 0x2d, 0x0a, 0x15, 0x49, 0x73, 0x42, 0x6f, 0x6f, 0x74,
+	0x73, 0x74, 0x72, 0x61, 0x70, 0x70, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x14, 0x0a, 0x05, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x63, 0x68, 0x61, 0x69, 0x6e, 0x22, 0x41, 0x0a, 0x16, 0x49, 0x73, 0x42, 0x6f, 0x6f, 0x74, 0x73,
+	0x74, 0x72, 0x61, 0x70, 0x70, 0x65, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x27, 0x0a, 0x0f, 0x69, 0x73, 0x5f, 0x62, 0x6f, 0x6f, 0x74, 0x73, 0x74, 0x72, 0x61, 0x70, 0x70,
+	0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x69, 0x73, 0x42, 0x6f, 0x6f, 0x74,
+	0x73, 0x74, 0x72, 0x61, 0x70, 0x70, 0x65, 0x64, 0x22, 0xff, 0x03, 0x0a, 0x10, 0x47, 0x65, 0x74,
+	0x54, 0x78, 0x46, 0x65, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x15, 0x0a,
+	0x06, 0x74, 0x78, 0x5f, 0x66, 0x65, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x74,
+	0x78, 0x46, 0x65, 0x65, 0x12, 0x2d, 0x0a, 0x13, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x5f, 0x61,
+	0x73, 0x73, 0x65, 0x74, 0x5f, 0x74, 0x78, 0x5f, 0x66, 0x65, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x10, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x41, 0x73, 0x73, 0x65, 0x74, 0x54, 0x78,
+	0x46, 0x65, 0x65, 0x12, 0x2f, 0x0a, 0x14, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x5f, 0x73, 0x75,
+	0x62, 0x6e, 0x65, 0x74, 0x5f, 0x74, 0x78, 0x5f, 0x66, 0x65, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x11, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x54,
+	0x78, 0x46, 0x65, 0x65, 0x12, 0x35, 0x0a, 0x17, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x6f, 0x72,
+	0x6d, 0x5f, 0x73, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x5f, 0x74, 0x78, 0x5f, 0x66, 0x65, 0x65, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x14, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x6f, 0x72, 0x6d,
+	0x53, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x54, 0x78, 0x46, 0x65, 0x65, 0x12, 0x37, 0x0a, 0x18, 0x63,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x63, 0x68, 0x61, 0x69, 0x6e,
+	0x5f, 0x74, 0x78, 0x5f, 0x66, 0x65, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x15, 0x63,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x54,
+	0x78, 0x46, 0x65, 0x65, 0x12, 0x48, 0x0a, 0x21, 0x61, 0x64, 0x64, 0x5f, 0x70, 0x72, 0x69, 0x6d,
+	0x61, 0x72, 0x79, 0x5f, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x76, 0x61, 0x6c, 0x69,
+	0x64, 0x61, 0x74, 0x6f, 0x72, 0x5f, 0x66, 0x65, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x1d, 0x61, 0x64, 0x64, 0x50, 0x72, 0x69, 0x6d, 0x61, 0x72, 0x79, 0x4e, 0x65, 0x74, 0x77, 0x6f,
+	0x72, 0x6b, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x46, 0x65, 0x65, 0x12, 0x48,
+	0x0a, 0x21, 0x61, 0x64, 0x64, 0x5f, 0x70, 0x72, 0x69, 0x6d, 0x61, 0x72, 0x79, 0x5f, 0x6e, 0x65,
+	0x74, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x6f, 0x72, 0x5f,
+	0x66, 0x65, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x1d, 0x61, 0x64, 0x64, 0x50, 0x72,
+	0x69, 0x6d, 0x61, 0x72, 0x79, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x44, 0x65, 0x6c, 0x65,
+	0x67, 0x61, 0x74, 0x6f, 0x72, 0x46, 0x65, 0x65, 0x12, 0x37, 0x0a, 0x18, 0x61, 0x64, 0x64, 0x5f,
+	0x73, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x5f, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72,
+	0x5f, 0x66, 0x65, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x04, 0x52, 0x15, 0x61, 0x64, 0x64, 0x53,
+	0x75, 0x62, 0x6e, 0x65, 0x74, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x46, 0x65,
+	0x65, 0x12, 0x37, 0x0a, 0x18, 0x61, 0x64, 0x64, 0x5f, 0x73, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x5f,
+	0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x6f, 0x72, 0x5f, 0x66, 0x65, 0x65, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x15, 0x61, 0x64, 0x64, 0x53, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x44, 0x65,
+	0x6c, 0x65, 0x67, 0x61, 0x74, 0x6f, 0x72, 0x46, 0x65, 0x65, 0x22, 0x8e, 0x01, 0x0a, 0x0e, 0x55,
+	0x70, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3c, 0x0a,
+	0x1a, 0x72, 0x65, 0x77, 0x61, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x5f, 0x73, 0x74, 0x61, 0x6b, 0x65,
+	0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x18, 0x72, 0x65, 0x77, 0x61, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x53, 0x74, 0x61, 0x6b,
+	0x65, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x67, 0x65, 0x12, 0x3e, 0x0a, 0x1b, 0x77,
+	0x65, 0x69, 0x67, 0x68, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x5f,
+	0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x19, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x65, 0x64, 0x41, 0x76, 0x65, 0x72, 0x61, 0x67,
+	0x65, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x67, 0x65, 0x32, 0xe7, 0x04, 0x0a, 0x04,
+	0x49, 0x6e, 0x66, 0x6f, 0x12, 0x46, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x4e, 0x6f, 0x64, 0x65, 0x56,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x1c,
+	0x2e, 0x69, 0x6e, 0x66, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x4e, 0x6f, 0x64, 0x65, 0x56, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3c, 0x0a, 0x09,
+	0x47, 0x65, 0x74, 0x4e, 0x6f, 0x64, 0x65, 0x49, 0x44, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74,
+	0x79, 0x1a, 0x17, 0x2e, 0x69, 0x6e, 0x66, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x4e, 0x6f, 0x64, 0x65,
+	0x49, 0x44, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3c, 0x0a, 0x09, 0x47, 0x65,
+	0x74, 0x4e, 0x6f, 0x64, 0x65, 0x49, 0x50, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a,
+	0x17, 0x2e, 0x69, 0x6e, 0x66, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x4e, 0x6f, 0x64, 0x65, 0x49, 0x50,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x42, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x4e,
+	0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x49, 0x44, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79,
+	0x1a, 0x1a, 0x2e, 0x69, 0x6e, 0x66, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x4e, 0x65, 0x74, 0x77, 0x6f,
+	0x72, 0x6b, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x46, 0x0a, 0x0e,
+	0x47, 0x65, 0x74, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x16,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x1c, 0x2e, 0x69, 0x6e, 0x66, 0x6f, 0x2e, 0x47, 0x65,
+	0x74, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x4e, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4e, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
+	0x63, 0x68, 0x61, 0x69, 0x6e, 0x49, 0x44, 0x12, 0x1c, 0x2e, 0x69, 0x6e, 0x66, 0x6f, 0x2e, 0x47,
+	0x65, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x49, 0x44, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x69, 0x6e, 0x66, 0x6f, 0x2e, 0x47, 0x65, 0x74,
+	0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4b, 0x0a, 0x0e, 0x49, 0x73, 0x42, 0x6f, 0x6f, 0x74, 0x73, 0x74,
+	0x72, 0x61, 0x70, 0x70, 0x65, 0x64, 0x12, 0x1b, 0x2e, 0x69, 0x6e, 0x66, 0x6f, 0x2e, 0x49, 0x73,
+	0x42, 0x6f, 0x6f, 0x74, 0x73, 0x74, 0x72, 0x61, 0x70, 0x70, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x69, 0x6e, 0x66, 0x6f, 0x2e, 0x49, 0x73, 0x42, 0x6f, 0x6f,
+	0x74, 0x73, 0x74, 0x72, 0x61, 0x70, 0x70, 0x65, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x3a, 0x0a, 0x08, 0x47, 0x65, 0x74, 0x54, 0x78, 0x46, 0x65, 0x65, 0x12, 0x16, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x16, 0x2e, 0x69, 0x6e, 0x66, 0x6f, 0x2e, 0x47, 0x65, 0x74,
+	0x54, 0x78, 0x46, 0x65, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x36, 0x0a,
+	0x06, 0x55, 0x70, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a,
+	0x14, 0x2e, 0x69, 0x6e, 0x66, 0x6f, 0x2e, 0x55, 0x70, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x2d, 0x5a, 0x2b, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
+	0x63, 0x6f, 0x6d, 0x2f, 0x64, 0x69, 0x6d, 0x34, 0x65, 0x67, 0x73, 0x74, 0x65, 0x72, 0x2f, 0x71,
+	0x6d, 0x61, 0x6c, 0x6c, 0x67, 0x6f, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x70, 0x62, 0x2f,
+	0x69, 0x6e, 0x66, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_info_info_proto_rawDescOnce sync.Once
+	file_info_info_proto_rawDescData = file_info_info_proto_rawDesc
+)
+
+func file_info_info_proto_rawDescGZIP() []byte {
+	file_info_info_proto_rawDescOnce.Do(func() {
+		file_info_info_proto_rawDescData = protoimpl.X.CompressGZIP(file_info_info_proto_rawDescData)
+	})
+	return file_info_info_proto_rawDescData
+}
+
+var file_info_info_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_info_info_proto_goTypes = []interface{}{
+	(*GetNodeVersionResponse)(nil),  // 0: info.GetNodeVersionResponse
+	(*GetNodeIDResponse)(nil),       // 1: info.GetNodeIDResponse
+	(*GetNodeIPResponse)(nil),       // 2: info.GetNodeIPResponse
+	(*GetNetworkIDResponse)(nil),    // 3: info.GetNetworkIDResponse
+	(*GetNetworkNameResponse)(nil),  // 4: info.GetNetworkNameResponse
+	(*GetBlockchainIDRequest)(nil),  // 5: info.GetBlockchainIDRequest
+	(*GetBlockchainIDResponse)(nil), // 6: info.GetBlockchainIDResponse
+	(*IsBootstrappedRequest)(nil),   // 7: info.IsBootstrappedRequest
+	(*IsBootstrappedResponse)(nil),  // 8: info.IsBootstrappedResponse
+	(*GetTxFeeResponse)(nil),        // 9: info.GetTxFeeResponse
+	(*UptimeResponse)(nil),          // 10: info.UptimeResponse
+	nil,                             // 11: info.GetNodeVersionResponse.VmVersionsEntry
+	(*emptypb.Empty)(nil),           // 12: google.protobuf.Empty
+}
+var file_info_info_proto_depIdxs = []int32{
+	11, // 0: info.GetNodeVersionResponse.vm_versions:type_name -> info.GetNodeVersionResponse.VmVersionsEntry
+	12, // 1: info.Info.GetNodeVersion:input_type -> google.protobuf.Empty
+	12, // 2: info.Info.GetNodeID:input_type -> google.protobuf.Empty
+	12, // 3: info.Info.GetNodeIP:input_type -> google.protobuf.Empty
+	12, // 4: info.Info.GetNetworkID:input_type -> google.protobuf.Empty
+	12, // 5: info.Info.GetNetworkName:input_type -> google.protobuf.Empty
+	5,  // 6: info.Info.GetBlockchainID:input_type -> info.GetBlockchainIDRequest
+	7,  // 7: info.Info.IsBootstrapped:input_type -> info.IsBootstrappedRequest
+	12, // 8: info.Info.GetTxFee:input_type -> google.protobuf.Empty
+	12, // 9: info.Info.Uptime:input_type -> google.protobuf.Empty
+	0,  // 10: info.Info.GetNodeVersion:output_type -> info.GetNodeVersionResponse
+	1,  // 11: info.Info.GetNodeID:output_type -> info.GetNodeIDResponse
+	2,  // 12: info.Info.GetNodeIP:output_type -> info.GetNodeIPResponse
+	3,  // 13: info.Info.GetNetworkID:output_type -> info.GetNetworkIDResponse
+	4,  // 14: info.Info.GetNetworkName:output_type -> info.GetNetworkNameResponse
+	6,  // 15: info.Info.GetBlockchainID:output_type -> info.GetBlockchainIDResponse
+	8,  // 16: info.Info.IsBootstrapped:output_type -> info.IsBootstrappedResponse
+	9,  // 17: info.Info.GetTxFee:output_type -> info.GetTxFeeResponse
+	10, // 18: info.Info.Uptime:output_type -> info.UptimeResponse
+	10, // [10:19] is the sub-list for method output_type
+	1,  // [1:10] is the sub-list for method input_type
+	1,  // [1:1] is the sub-list for extension type_name
+	1,  // [1:1] is the sub-list for extension extendee
+	0,  // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_info_info_proto_init() }
+func file_info_info_proto_init() {
+	if File_info_info_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_info_info_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetNodeVersionResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_info_info_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetNodeIDResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_info_info_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetNodeIPResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_info_info_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetNetworkIDResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_info_info_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetNetworkNameResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_info_info_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetBlockchainIDRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_info_info_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetBlockchainIDResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_info_info_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IsBootstrappedRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_info_info_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IsBootstrappedResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_info_info_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetTxFeeResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_info_info_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UptimeResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_info_info_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_info_info_proto_goTypes,
+		DependencyIndexes: file_info_info_proto_depIdxs,
+		MessageInfos:      file_info_info_proto_msgTypes,
+	}.Build()
+	File_info_info_proto = out.File
+	file_info_info_proto_rawDesc = nil
+	file_info_info_proto_goTypes = nil
+	file_info_info_proto_depIdxs = nil
+}
@@ -0,0 +1,142 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: keychain/keychain.proto
+
+package keychain
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// KeychainClient is the client API for Keychain service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type KeychainClient interface {
+	Addresses(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*AddressesResponse, error)
+	SignHash(ctx context.Context, in *SignHashRequest, opts ...grpc.CallOption) (*SignHashResponse, error)
+}
+
+type keychainClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewKeychainClient(cc grpc.ClientConnInterface) KeychainClient {
+	return &keychainClient{cc}
+}
+
+func (c *keychainClient) Addresses(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*AddressesResponse, error) {
+	out := new(AddressesResponse)
+	err := c.cc.Invoke(ctx, "/keychain.Keychain/Addresses", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keychainClient) SignHash(ctx context.Context, in *SignHashRequest, opts ...grpc.CallOption) (*SignHashResponse, error) {
+	out := new(SignHashResponse)
+	err := c.cc.Invoke(ctx, "/keychain.Keychain/SignHash", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// KeychainServer is the server API for Keychain service.
+// All implementations must embed UnimplementedKeychainServer
+// for forward compatibility
+type KeychainServer interface {
+	Addresses(context.Context, *emptypb.Empty) (*AddressesResponse, error)
+	SignHash(context.Context, *SignHashRequest) (*SignHashResponse, error)
+	mustEmbedUnimplementedKeychainServer()
+}
+
+// UnimplementedKeychainServer must be embedded to have forward compatible implementations.
+type UnimplementedKeychainServer struct {
+}
+
+func (UnimplementedKeychainServer) Addresses(context.Context, *emptypb.Empty) (*AddressesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Addresses not implemented")
+}
+func (UnimplementedKeychainServer) SignHash(context.Context, *SignHashRequest) (*SignHashResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SignHash not implemented")
+}
+func (UnimplementedKeychainServer) mustEmbedUnimplementedKeychainServer() {}
+
+// UnsafeKeychainServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to KeychainServer will
+// result in compilation errors.
+type UnsafeKeychainServer interface {
+	mustEmbedUnimplementedKeychainServer()
+}
+
+func RegisterKeychainServer(s grpc.ServiceRegistrar, srv KeychainServer) {
+	s.RegisterService(&Keychain_ServiceDesc, srv)
+}
+
+func _Keychain_Addresses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeychainServer).Addresses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/keychain.Keychain/Addresses",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeychainServer).Addresses(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Keychain_SignHash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignHashRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeychainServer).SignHash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/keychain.Keychain/SignHash",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeychainServer).SignHash(ctx, req.(*SignHashRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Keychain_ServiceDesc is the grpc.ServiceDesc for Keychain service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Keychain_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "keychain.Keychain",
+	HandlerType: (*KeychainServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Addresses",
+			Handler:    _Keychain_Addresses_Handler,
+		},
+		{
+			MethodName: "SignHash",
+			Handler:    _Keychain_SignHash_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "keychain/keychain.proto",
+}
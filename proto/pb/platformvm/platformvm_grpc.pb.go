@@ -0,0 +1,426 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: platformvm/platformvm.proto
+
+package platformvm
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// PlatformVMClient is the client API for PlatformVM service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PlatformVMClient interface {
+	GetHeight(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*GetHeightResponse, error)
+	GetBalance(ctx context.Context, in *GetBalanceRequest, opts ...grpc.CallOption) (*GetBalanceResponse, error)
+	GetTx(ctx context.Context, in *GetTxRequest, opts ...grpc.CallOption) (*GetTxResponse, error)
+	GetTxStatus(ctx context.Context, in *GetTxStatusRequest, opts ...grpc.CallOption) (*GetTxStatusResponse, error)
+	GetBlock(ctx context.Context, in *GetBlockRequest, opts ...grpc.CallOption) (*GetBlockResponse, error)
+	GetTimestamp(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*GetTimestampResponse, error)
+	GetCurrentSupply(ctx context.Context, in *GetCurrentSupplyRequest, opts ...grpc.CallOption) (*GetCurrentSupplyResponse, error)
+	GetUTXOs(ctx context.Context, in *GetUTXOsRequest, opts ...grpc.CallOption) (*GetUTXOsResponse, error)
+	// StreamUTXOs streams every UTXO referenced by [addresses], fetching them
+	// from the chain [page_size] at a time.
+	StreamUTXOs(ctx context.Context, in *StreamUTXOsRequest, opts ...grpc.CallOption) (PlatformVM_StreamUTXOsClient, error)
+}
+
+type platformVMClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPlatformVMClient(cc grpc.ClientConnInterface) PlatformVMClient {
+	return &platformVMClient{cc}
+}
+
+func (c *platformVMClient) GetHeight(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*GetHeightResponse, error) {
+	out := new(GetHeightResponse)
+	err := c.cc.Invoke(ctx, "/platformvm.PlatformVM/GetHeight", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *platformVMClient) GetBalance(ctx context.Context, in *GetBalanceRequest, opts ...grpc.CallOption) (*GetBalanceResponse, error) {
+	out := new(GetBalanceResponse)
+	err := c.cc.Invoke(ctx, "/platformvm.PlatformVM/GetBalance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *platformVMClient) GetTx(ctx context.Context, in *GetTxRequest, opts ...grpc.CallOption) (*GetTxResponse, error) {
+	out := new(GetTxResponse)
+	err := c.cc.Invoke(ctx, "/platformvm.PlatformVM/GetTx", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *platformVMClient) GetTxStatus(ctx context.Context, in *GetTxStatusRequest, opts ...grpc.CallOption) (*GetTxStatusResponse, error) {
+	out := new(GetTxStatusResponse)
+	err := c.cc.Invoke(ctx, "/platformvm.PlatformVM/GetTxStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *platformVMClient) GetBlock(ctx context.Context, in *GetBlockRequest, opts ...grpc.CallOption) (*GetBlockResponse, error) {
+	out := new(GetBlockResponse)
+	err := c.cc.Invoke(ctx, "/platformvm.PlatformVM/GetBlock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *platformVMClient) GetTimestamp(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*GetTimestampResponse, error) {
+	out := new(GetTimestampResponse)
+	err := c.cc.Invoke(ctx, "/platformvm.PlatformVM/GetTimestamp", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *platformVMClient) GetCurrentSupply(ctx context.Context, in *GetCurrentSupplyRequest, opts ...grpc.CallOption) (*GetCurrentSupplyResponse, error) {
+	out := new(GetCurrentSupplyResponse)
+	err := c.cc.Invoke(ctx, "/platformvm.PlatformVM/GetCurrentSupply", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *platformVMClient) GetUTXOs(ctx context.Context, in *GetUTXOsRequest, opts ...grpc.CallOption) (*GetUTXOsResponse, error) {
+	out := new(GetUTXOsResponse)
+	err := c.cc.Invoke(ctx, "/platformvm.PlatformVM/GetUTXOs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *platformVMClient) StreamUTXOs(ctx context.Context, in *StreamUTXOsRequest, opts ...grpc.CallOption) (PlatformVM_StreamUTXOsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &PlatformVM_ServiceDesc.Streams[0], "/platformvm.PlatformVM/StreamUTXOs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &platformVMStreamUTXOsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type PlatformVM_StreamUTXOsClient interface {
+	Recv() (*UTXO, error)
+	grpc.ClientStream
+}
+
+type platformVMStreamUTXOsClient struct {
+	grpc.ClientStream
+}
+
+func (x *platformVMStreamUTXOsClient) Recv() (*UTXO, error) {
+	m := new(UTXO)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// PlatformVMServer is the server API for PlatformVM service.
+// All implementations must embed UnimplementedPlatformVMServer
+// for forward compatibility
+type PlatformVMServer interface {
+	GetHeight(context.Context, *emptypb.Empty) (*GetHeightResponse, error)
+	GetBalance(context.Context, *GetBalanceRequest) (*GetBalanceResponse, error)
+	GetTx(context.Context, *GetTxRequest) (*GetTxResponse, error)
+	GetTxStatus(context.Context, *GetTxStatusRequest) (*GetTxStatusResponse, error)
+	GetBlock(context.Context, *GetBlockRequest) (*GetBlockResponse, error)
+	GetTimestamp(context.Context, *emptypb.Empty) (*GetTimestampResponse, error)
+	GetCurrentSupply(context.Context, *GetCurrentSupplyRequest) (*GetCurrentSupplyResponse, error)
+	GetUTXOs(context.Context, *GetUTXOsRequest) (*GetUTXOsResponse, error)
+	// StreamUTXOs streams every UTXO referenced by [addresses], fetching them
+	// from the chain [page_size] at a time.
+	StreamUTXOs(*StreamUTXOsRequest, PlatformVM_StreamUTXOsServer) error
+	mustEmbedUnimplementedPlatformVMServer()
+}
+
+// UnimplementedPlatformVMServer must be embedded to have forward compatible implementations.
+type UnimplementedPlatformVMServer struct {
+}
+
+func (UnimplementedPlatformVMServer) GetHeight(context.Context, *emptypb.Empty) (*GetHeightResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHeight not implemented")
+}
+func (UnimplementedPlatformVMServer) GetBalance(context.Context, *GetBalanceRequest) (*GetBalanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBalance not implemented")
+}
+func (UnimplementedPlatformVMServer) GetTx(context.Context, *GetTxRequest) (*GetTxResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTx not implemented")
+}
+func (UnimplementedPlatformVMServer) GetTxStatus(context.Context, *GetTxStatusRequest) (*GetTxStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTxStatus not implemented")
+}
+func (UnimplementedPlatformVMServer) GetBlock(context.Context, *GetBlockRequest) (*GetBlockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBlock not implemented")
+}
+func (UnimplementedPlatformVMServer) GetTimestamp(context.Context, *emptypb.Empty) (*GetTimestampResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTimestamp not implemented")
+}
+func (UnimplementedPlatformVMServer) GetCurrentSupply(context.Context, *GetCurrentSupplyRequest) (*GetCurrentSupplyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCurrentSupply not implemented")
+}
+func (UnimplementedPlatformVMServer) GetUTXOs(context.Context, *GetUTXOsRequest) (*GetUTXOsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUTXOs not implemented")
+}
+func (UnimplementedPlatformVMServer) StreamUTXOs(*StreamUTXOsRequest, PlatformVM_StreamUTXOsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamUTXOs not implemented")
+}
+func (UnimplementedPlatformVMServer) mustEmbedUnimplementedPlatformVMServer() {}
+
+// UnsafePlatformVMServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PlatformVMServer will
+// result in compilation errors.
+type UnsafePlatformVMServer interface {
+	mustEmbedUnimplementedPlatformVMServer()
+}
+
+func RegisterPlatformVMServer(s grpc.ServiceRegistrar, srv PlatformVMServer) {
+	s.RegisterService(&PlatformVM_ServiceDesc, srv)
+}
+
+func _PlatformVM_GetHeight_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlatformVMServer).GetHeight(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/platformvm.PlatformVM/GetHeight",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlatformVMServer).GetHeight(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PlatformVM_GetBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlatformVMServer).GetBalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/platformvm.PlatformVM/GetBalance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlatformVMServer).GetBalance(ctx, req.(*GetBalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PlatformVM_GetTx_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTxRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlatformVMServer).GetTx(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/platformvm.PlatformVM/GetTx",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlatformVMServer).GetTx(ctx, req.(*GetTxRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PlatformVM_GetTxStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTxStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlatformVMServer).GetTxStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/platformvm.PlatformVM/GetTxStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlatformVMServer).GetTxStatus(ctx, req.(*GetTxStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PlatformVM_GetBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlatformVMServer).GetBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/platformvm.PlatformVM/GetBlock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlatformVMServer).GetBlock(ctx, req.(*GetBlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PlatformVM_GetTimestamp_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlatformVMServer).GetTimestamp(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/platformvm.PlatformVM/GetTimestamp",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlatformVMServer).GetTimestamp(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PlatformVM_GetCurrentSupply_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCurrentSupplyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlatformVMServer).GetCurrentSupply(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/platformvm.PlatformVM/GetCurrentSupply",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlatformVMServer).GetCurrentSupply(ctx, req.(*GetCurrentSupplyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PlatformVM_GetUTXOs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUTXOsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlatformVMServer).GetUTXOs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/platformvm.PlatformVM/GetUTXOs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlatformVMServer).GetUTXOs(ctx, req.(*GetUTXOsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PlatformVM_StreamUTXOs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamUTXOsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PlatformVMServer).StreamUTXOs(m, &platformVMStreamUTXOsServer{stream})
+}
+
+type PlatformVM_StreamUTXOsServer interface {
+	Send(*UTXO) error
+	grpc.ServerStream
+}
+
+type platformVMStreamUTXOsServer struct {
+	grpc.ServerStream
+}
+
+func (x *platformVMStreamUTXOsServer) Send(m *UTXO) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// PlatformVM_ServiceDesc is the grpc.ServiceDesc for PlatformVM service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PlatformVM_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "platformvm.PlatformVM",
+	HandlerType: (*PlatformVMServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetHeight",
+			Handler:    _PlatformVM_GetHeight_Handler,
+		},
+		{
+			MethodName: "GetBalance",
+			Handler:    _PlatformVM_GetBalance_Handler,
+		},
+		{
+			MethodName: "GetTx",
+			Handler:    _PlatformVM_GetTx_Handler,
+		},
+		{
+			MethodName: "GetTxStatus",
+			Handler:    _PlatformVM_GetTxStatus_Handler,
+		},
+		{
+			MethodName: "GetBlock",
+			Handler:    _PlatformVM_GetBlock_Handler,
+		},
+		{
+			MethodName: "GetTimestamp",
+			Handler:    _PlatformVM_GetTimestamp_Handler,
+		},
+		{
+			MethodName: "GetCurrentSupply",
+			Handler:    _PlatformVM_GetCurrentSupply_Handler,
+		},
+		{
+			MethodName: "GetUTXOs",
+			Handler:    _PlatformVM_GetUTXOs_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamUTXOs",
+			Handler:       _PlatformVM_StreamUTXOs_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "platformvm/platformvm.proto",
+}
@@ -7,6 +7,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -18,17 +20,35 @@ import (
 )
 
 var (
-	ErrFilterNotInitialized        = errors.New("filter not initialized")
-	ErrAddressLimit                = errors.New("address limit exceeded")
-	ErrInvalidFilterParam          = errors.New("invalid bloom filter params")
-	ErrInvalidCommand              = errors.New("invalid command")
-	_                       Filter = &connection{}
+	ErrFilterNotInitialized = errors.New("filter not initialized")
+	ErrAddressLimit         = errors.New("address limit exceeded")
+	ErrInvalidFilterParam   = errors.New("invalid bloom filter params")
+	ErrInvalidCommand       = errors.New("invalid command")
+
+	_ Filter    = &connection{}
+	_ TxMatcher = &connection{}
+	_ Conn      = &connection{}
 )
 
 type Filter interface {
 	Check(addr []byte) bool
 }
 
+// Conn is a websocket connection served by a Server.
+type Conn interface {
+	// Send queues [msg] to be written to the connection. Returns false if the
+	// connection is closed or if too many messages are already pending.
+	Send(msg interface{}) bool
+
+	// SendWait queues [msg] to be written to the connection, waiting for
+	// pending messages to be written if necessary. Returns false if the
+	// connection was closed before [msg] could be queued.
+	SendWait(msg interface{}) bool
+
+	// Done returns a channel that is closed once the connection is closed.
+	Done() <-chan struct{}
+}
+
 // connection is a representation of the websocket connection.
 type connection struct {
 	s *Server
@@ -41,13 +61,28 @@ type connection struct {
 
 	fp *FilterParam
 
+	txFilterLock sync.RWMutex
+	// txFilter is the JSON filter set by the client, if any
+	txFilter *TxFilter
+
 	active uint32
+
+	// done is closed once the connection is deactivated
+	done      chan struct{}
+	closeOnce sync.Once
 }
 
 func (c *connection) Check(addr []byte) bool {
 	return c.fp.Check(addr)
 }
 
+func (c *connection) Match(attrs *TxAttributes) bool {
+	c.txFilterLock.RLock()
+	defer c.txFilterLock.RUnlock()
+
+	return c.txFilter != nil && c.txFilter.Match(attrs)
+}
+
 func (c *connection) isActive() bool {
 	active := atomic.LoadUint32(&c.active)
 	return active != 0
@@ -55,6 +90,13 @@ func (c *connection) isActive() bool {
 
 func (c *connection) deactivate() {
 	atomic.StoreUint32(&c.active, 0)
+	c.closeOnce.Do(func() {
+		close(c.done)
+	})
+}
+
+func (c *connection) Done() <-chan struct{} {
+	return c.done
 }
 
 func (c *connection) Send(msg interface{}) bool {
@@ -69,6 +111,18 @@ func (c *connection) Send(msg interface{}) bool {
 	return false
 }
 
+func (c *connection) SendWait(msg interface{}) bool {
+	if !c.isActive() {
+		return false
+	}
+	select {
+	case c.send <- msg:
+		return true
+	case <-c.done:
+		return false
+	}
+}
+
 // readPump pumps messages from the websocket connection to the hub.
 //
 // The application runs readPump in a per-connection goroutine. The application
@@ -162,6 +216,20 @@ func (c *connection) readMessage() error {
 	if err != nil {
 		return err
 	}
+	if c.s.handler != nil {
+		cmdBytes, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		if err := c.s.handler.HandleCommand(c, cmdBytes); err != nil {
+			c.Send(&errorMsg{
+				Error: err.Error(),
+			})
+			return err
+		}
+		return nil
+	}
+
 	cmd := &Command{}
 	err = json.NewDecoder(r).Decode(cmd)
 	if err != nil {
@@ -175,6 +243,8 @@ func (c *connection) readMessage() error {
 		c.handleNewSet(cmd.NewSet)
 	case cmd.AddAddresses != nil:
 		err = c.handleAddAddresses(cmd.AddAddresses)
+	case cmd.SetFilter != nil:
+		err = c.handleSetFilter(cmd.SetFilter)
 	default:
 		err = ErrInvalidCommand
 	}
@@ -213,3 +283,16 @@ func (c *connection) handleAddAddresses(cmd *AddAddresses) error {
 	c.s.subscribedConnections.Add(c)
 	return nil
 }
+
+func (c *connection) handleSetFilter(cmd *TxFilter) error {
+	if err := cmd.parse(); err != nil {
+		return fmt.Errorf("tx filter parse failed %w", err)
+	}
+
+	c.txFilterLock.Lock()
+	c.txFilter = cmd
+	c.txFilterLock.Unlock()
+
+	c.s.subscribedConnections.Add(c)
+	return nil
+}
@@ -33,6 +33,7 @@ type Command struct {
 	NewBloom     *NewBloom     `json:"newBloom,omitempty"`
 	NewSet       *NewSet       `json:"newSet,omitempty"`
 	AddAddresses *AddAddresses `json:"addAddresses,omitempty"`
+	SetFilter    *TxFilter     `json:"setFilter,omitempty"`
 }
 
 func (c *Command) String() string {
@@ -43,6 +44,8 @@ func (c *Command) String() string {
 		return "newSet"
 	case c.AddAddresses != nil:
 		return "addAddresses"
+	case c.SetFilter != nil:
+		return "setFilter"
 	default:
 		return "unknown"
 	}
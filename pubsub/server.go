@@ -55,6 +55,13 @@ var upgrader = websocket.Upgrader{
 	CheckOrigin:     func(*http.Request) bool { return true },
 }
 
+// Handler processes the commands received over the connections of a Server.
+type Handler interface {
+	// HandleCommand is called with every message received over [conn]. If an
+	// error is returned, it is sent to the client and [conn] is closed.
+	HandleCommand(conn Conn, cmd []byte) error
+}
+
 // Server maintains the set of active clients and sends messages to the clients.
 type Server struct {
 	log  logging.Logger
@@ -63,6 +70,9 @@ type Server struct {
 	conns map[*connection]struct{}
 	// subscribedConnections the connections that have activated subscriptions
 	subscribedConnections *connections
+	// handler processes the commands sent by clients. If nil, clients are
+	// expected to send address filter [Command]s.
+	handler Handler
 }
 
 func New(networkID uint32, log logging.Logger) *Server {
@@ -73,6 +83,14 @@ func New(networkID uint32, log logging.Logger) *Server {
 	}
 }
 
+// NewWithHandler returns a Server that passes the commands sent by clients to
+// [handler] rather than treating them as address filter [Command]s.
+func NewWithHandler(log logging.Logger, handler Handler) *Server {
+	s := New(0, log)
+	s.handler = handler
+	return s
+}
+
 func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	wsConn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
@@ -87,6 +105,7 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		send:   make(chan interface{}, maxPendingMessages),
 		fp:     NewFilterParam(),
 		active: 1,
+		done:   make(chan struct{}),
 	}
 	s.addConnection(conn)
 }
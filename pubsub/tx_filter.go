@@ -0,0 +1,265 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pubsub
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/dim4egster/qmallgo/api"
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/utils/formatting"
+	"github.com/dim4egster/qmallgo/utils/formatting/address"
+	"github.com/dim4egster/qmallgo/utils/json"
+)
+
+const (
+	// maxTxFilterDepth is the maximum nesting of and/or/not expressions
+	// allowed in a TxFilter.
+	maxTxFilterDepth = 8
+
+	// maxTxFilterTerms is the maximum number of expressions allowed in a
+	// TxFilter.
+	maxTxFilterTerms = 256
+)
+
+var (
+	ErrTxFilterTooDeep    = errors.New("tx filter nested too deeply")
+	ErrTxFilterTooLarge   = errors.New("tx filter has too many terms")
+	ErrNullTxFilter       = errors.New("tx filter is null")
+	ErrInvalidThreshold   = errors.New("minThreshold > maxThreshold")
+	ErrInvalidMemoPrefix  = errors.New("invalid memo prefix")
+	errInvalidTxFilterArg = errors.New("invalid tx filter address")
+
+	_ Filterer = &txFilterer{}
+)
+
+// TxMatcher is implemented by the connections that can be matched against the
+// attributes of a transaction.
+type TxMatcher interface {
+	Match(attrs *TxAttributes) bool
+}
+
+// TxAttributes describes a transaction in terms of the fields a TxFilter can
+// match on.
+type TxAttributes struct {
+	// TxType is the name of the transaction's type, such as "BaseTx" or
+	// "AddValidatorTx".
+	TxType string
+	// AssetIDs are the assets consumed or produced by the transaction.
+	AssetIDs ids.Set
+	// Addresses are the addresses that own the transaction's outputs.
+	Addresses [][]byte
+	// Thresholds are the signature thresholds of the transaction's output
+	// owners.
+	Thresholds []uint32
+	// Memo is the memo of the transaction.
+	Memo []byte
+}
+
+// TxFilter is a JSON filter that is evaluated against the attributes of every
+// published transaction.
+//
+// A transaction matches the filter if it matches every term that is set. A
+// filter with no terms set matches every transaction. The terms are:
+//   - and: the transaction matches all of the sub-filters.
+//   - or: the transaction matches at least one of the sub-filters.
+//   - not: the transaction doesn't match the sub-filter.
+//   - txTypes: the transaction is of one of the types.
+//   - assetIDs: the transaction consumes or produces one of the assets.
+//   - addresses: one of the addresses owns an output of the transaction.
+//   - minThreshold/maxThreshold: an output owner of the transaction has a
+//     threshold in the inclusive range.
+//   - memoPrefix: the memo of the transaction starts with the hex encoded
+//     bytes.
+type TxFilter struct {
+	And []*TxFilter `json:"and,omitempty"`
+	Or  []*TxFilter `json:"or,omitempty"`
+	Not *TxFilter   `json:"not,omitempty"`
+
+	TxTypes      []string     `json:"txTypes,omitempty"`
+	AssetIDs     []ids.ID     `json:"assetIDs,omitempty"`
+	Addresses    []string     `json:"addresses,omitempty"`
+	MinThreshold *json.Uint32 `json:"minThreshold,omitempty"`
+	MaxThreshold *json.Uint32 `json:"maxThreshold,omitempty"`
+	MemoPrefix   string       `json:"memoPrefix,omitempty"`
+
+	// The following fields are populated by parse.
+	txTypes    map[string]struct{}
+	assetIDs   ids.Set
+	addresses  map[string]struct{}
+	memoPrefix []byte
+}
+
+// parse validates the filter and converts its terms into the formats used
+// during matching.
+func (f *TxFilter) parse() error {
+	terms := 0
+	return f.parseAtDepth(0, &terms)
+}
+
+func (f *TxFilter) parseAtDepth(depth int, terms *int) error {
+	if f == nil {
+		return ErrNullTxFilter
+	}
+	if depth > maxTxFilterDepth {
+		return ErrTxFilterTooDeep
+	}
+	*terms++
+	if *terms > maxTxFilterTerms {
+		return ErrTxFilterTooLarge
+	}
+
+	for _, sub := range f.And {
+		if err := sub.parseAtDepth(depth+1, terms); err != nil {
+			return err
+		}
+	}
+	for _, sub := range f.Or {
+		if err := sub.parseAtDepth(depth+1, terms); err != nil {
+			return err
+		}
+	}
+	if f.Not != nil {
+		if err := f.Not.parseAtDepth(depth+1, terms); err != nil {
+			return err
+		}
+	}
+
+	if len(f.TxTypes) > 0 {
+		f.txTypes = make(map[string]struct{}, len(f.TxTypes))
+		for _, txType := range f.TxTypes {
+			f.txTypes[txType] = struct{}{}
+		}
+	}
+	if len(f.AssetIDs) > 0 {
+		f.assetIDs = ids.NewSet(len(f.AssetIDs))
+		f.assetIDs.Add(f.AssetIDs...)
+	}
+	if len(f.Addresses) > MaxAddresses {
+		return ErrAddressLimit
+	}
+	if len(f.Addresses) > 0 {
+		f.addresses = make(map[string]struct{}, len(f.Addresses))
+		for _, addrStr := range f.Addresses {
+			_, _, addrBytes, err := address.Parse(addrStr)
+			if err != nil {
+				return fmt.Errorf("%w %q: %s", errInvalidTxFilterArg, addrStr, err)
+			}
+			f.addresses[string(addrBytes)] = struct{}{}
+		}
+	}
+	if f.MinThreshold != nil && f.MaxThreshold != nil && *f.MinThreshold > *f.MaxThreshold {
+		return ErrInvalidThreshold
+	}
+	if len(f.MemoPrefix) > 0 {
+		memoPrefix, err := formatting.Decode(formatting.HexNC, f.MemoPrefix)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidMemoPrefix, err)
+		}
+		f.memoPrefix = memoPrefix
+	}
+	return nil
+}
+
+// Match returns true if [attrs] satisfies every term of the filter. The filter
+// must have been parsed.
+func (f *TxFilter) Match(attrs *TxAttributes) bool {
+	for _, sub := range f.And {
+		if !sub.Match(attrs) {
+			return false
+		}
+	}
+	if len(f.Or) > 0 && !f.matchAny(attrs) {
+		return false
+	}
+	if f.Not != nil && f.Not.Match(attrs) {
+		return false
+	}
+	if f.txTypes != nil {
+		if _, ok := f.txTypes[attrs.TxType]; !ok {
+			return false
+		}
+	}
+	if f.assetIDs != nil && !f.assetIDs.Overlaps(attrs.AssetIDs) {
+		return false
+	}
+	if f.addresses != nil && !f.matchAddresses(attrs.Addresses) {
+		return false
+	}
+	if (f.MinThreshold != nil || f.MaxThreshold != nil) && !f.matchThresholds(attrs.Thresholds) {
+		return false
+	}
+	return bytes.HasPrefix(attrs.Memo, f.memoPrefix)
+}
+
+func (f *TxFilter) matchAny(attrs *TxAttributes) bool {
+	for _, sub := range f.Or {
+		if sub.Match(attrs) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *TxFilter) matchAddresses(addrs [][]byte) bool {
+	for _, addr := range addrs {
+		if _, ok := f.addresses[string(addr)]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *TxFilter) matchThresholds(thresholds []uint32) bool {
+	for _, threshold := range thresholds {
+		if f.MinThreshold != nil && threshold < uint32(*f.MinThreshold) {
+			continue
+		}
+		if f.MaxThreshold != nil && threshold > uint32(*f.MaxThreshold) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+type txFilterer struct {
+	attrs *TxAttributes
+	msg   interface{}
+}
+
+// NewTxFilterer returns a Filterer that notifies the connections whose address
+// filter contains one of [attrs.Addresses] or whose TxFilter matches [attrs].
+// [msg] is sent to the notified connections.
+func NewTxFilterer(attrs *TxAttributes, msg interface{}) Filterer {
+	return &txFilterer{
+		attrs: attrs,
+		msg:   msg,
+	}
+}
+
+func (f *txFilterer) Filter(filters []Filter) ([]bool, interface{}) {
+	resp := make([]bool, len(filters))
+	for i, filter := range filters {
+		if matcher, ok := filter.(TxMatcher); ok && matcher.Match(f.attrs) {
+			resp[i] = true
+			continue
+		}
+		for _, addr := range f.attrs.Addresses {
+			if filter.Check(addr) {
+				resp[i] = true
+				break
+			}
+		}
+	}
+	return resp, f.msg
+}
+
+// NewTxIDFilterer returns a Filterer that sends the ID of the transaction
+// described by [attrs] to the notified connections.
+func NewTxIDFilterer(txID ids.ID, attrs *TxAttributes) Filterer {
+	return NewTxFilterer(attrs, api.JSONTxID{TxID: txID})
+}
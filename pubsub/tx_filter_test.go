@@ -0,0 +1,162 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pubsub
+
+import (
+	stdjson "encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/utils/constants"
+	"github.com/dim4egster/qmallgo/utils/formatting/address"
+	"github.com/dim4egster/qmallgo/utils/json"
+)
+
+func parseTxFilter(t *testing.T, filterJSON string) *TxFilter {
+	filter := &TxFilter{}
+	require.NoError(t, stdjson.Unmarshal([]byte(filterJSON), filter))
+	require.NoError(t, filter.parse())
+	return filter
+}
+
+func TestTxFilterMatch(t *testing.T) {
+	assetID := ids.ID{1}
+	otherAssetID := ids.ID{2}
+	addrID := ids.ShortID{3}
+	addrStr, err := address.Format("X", constants.GetHRP(5), addrID[:])
+	require.NoError(t, err)
+
+	attrs := &TxAttributes{
+		TxType:     "BaseTx",
+		AssetIDs:   ids.Set{assetID: struct{}{}},
+		Addresses:  [][]byte{addrID[:]},
+		Thresholds: []uint32{2},
+		Memo:       []byte{0xde, 0xad, 0xbe, 0xef},
+	}
+
+	tests := []struct {
+		name     string
+		filter   string
+		expected bool
+	}{
+		{"empty", `{}`, true},
+		{"tx type", `{"txTypes":["ExportTx","BaseTx"]}`, true},
+		{"wrong tx type", `{"txTypes":["ExportTx"]}`, false},
+		{"asset", `{"assetIDs":["` + assetID.String() + `"]}`, true},
+		{"wrong asset", `{"assetIDs":["` + otherAssetID.String() + `"]}`, false},
+		{"address", `{"addresses":["` + addrStr + `"]}`, true},
+		{"threshold range", `{"minThreshold":"2","maxThreshold":"3"}`, true},
+		{"threshold too low", `{"minThreshold":"3"}`, false},
+		{"threshold too high", `{"maxThreshold":"1"}`, false},
+		{"memo prefix", `{"memoPrefix":"0xdead"}`, true},
+		{"wrong memo prefix", `{"memoPrefix":"0xbeef"}`, false},
+		{"implicit and", `{"txTypes":["BaseTx"],"memoPrefix":"0xbeef"}`, false},
+		{"or", `{"or":[{"txTypes":["ExportTx"]},{"memoPrefix":"0xde"}]}`, true},
+		{"and", `{"and":[{"txTypes":["BaseTx"]},{"txTypes":["ExportTx"]}]}`, false},
+		{"not", `{"not":{"txTypes":["ExportTx"]}}`, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filter := parseTxFilter(t, test.filter)
+			require.Equal(t, test.expected, filter.Match(attrs))
+		})
+	}
+}
+
+func TestTxFilterParseErrors(t *testing.T) {
+	minThreshold := json.Uint32(3)
+	maxThreshold := json.Uint32(2)
+	tests := []struct {
+		name        string
+		filter      *TxFilter
+		expectedErr error
+	}{
+		{
+			name:        "invalid address",
+			filter:      &TxFilter{Addresses: []string{"not an address"}},
+			expectedErr: errInvalidTxFilterArg,
+		},
+		{
+			name:        "invalid memo prefix",
+			filter:      &TxFilter{MemoPrefix: "dead"},
+			expectedErr: ErrInvalidMemoPrefix,
+		},
+		{
+			name:        "invalid threshold range",
+			filter:      &TxFilter{MinThreshold: &minThreshold, MaxThreshold: &maxThreshold},
+			expectedErr: ErrInvalidThreshold,
+		},
+		{
+			name:        "too deep",
+			filter:      nestedTxFilter(maxTxFilterDepth + 1),
+			expectedErr: ErrTxFilterTooDeep,
+		},
+		{
+			name:        "too many terms",
+			filter:      &TxFilter{Or: make([]*TxFilter, maxTxFilterTerms)},
+			expectedErr: ErrTxFilterTooLarge,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for i := range test.filter.Or {
+				test.filter.Or[i] = &TxFilter{}
+			}
+			require.ErrorIs(t, test.filter.parse(), test.expectedErr)
+		})
+	}
+}
+
+func TestTxFilterParseNullSubFilters(t *testing.T) {
+	tests := []string{
+		`{"and":[null]}`,
+		`{"or":[{},null]}`,
+		`{"not":{"and":[null]}}`,
+		`{"not":{"or":[null]}}`,
+	}
+	for _, filterJSON := range tests {
+		t.Run(filterJSON, func(t *testing.T) {
+			filter := &TxFilter{}
+			require.NoError(t, stdjson.Unmarshal([]byte(filterJSON), filter))
+			require.ErrorIs(t, filter.parse(), ErrNullTxFilter)
+		})
+	}
+
+	// A null "not" is indistinguishable from an unset "not".
+	parseTxFilter(t, `{"not":null}`)
+}
+
+func TestTxFiltererMatchesAddressesOrTxFilter(t *testing.T) {
+	require := require.New(t)
+
+	addr := []byte("addr")
+	attrs := &TxAttributes{
+		TxType:    "AddValidatorTx",
+		Addresses: [][]byte{addr},
+	}
+
+	addressFilter := NewFilterParam()
+	require.NoError(addressFilter.Add(addr))
+
+	matchingConn := &connection{fp: NewFilterParam()}
+	matchingConn.txFilter = parseTxFilter(t, `{"txTypes":["AddValidatorTx"]}`)
+
+	otherConn := &connection{fp: NewFilterParam()}
+	otherConn.txFilter = parseTxFilter(t, `{"txTypes":["CreateSubnetTx"]}`)
+
+	filterer := NewTxFilterer(attrs, "msg")
+	notify, msg := filterer.Filter([]Filter{addressFilter, matchingConn, otherConn})
+	require.Equal([]bool{true, true, false}, notify)
+	require.Equal("msg", msg)
+}
+
+func nestedTxFilter(depth int) *TxFilter {
+	filter := &TxFilter{}
+	for i := 0; i < depth; i++ {
+		filter = &TxFilter{Not: filter}
+	}
+	return filter
+}
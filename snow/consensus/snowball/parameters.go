@@ -51,6 +51,16 @@ type Parameters struct {
 	// send a Push Query to this many validators and a Pull Query to the other
 	// k - MixedQueryNumPushVdr validators. Must be in [0, K].
 	MixedQueryNumPushNonVdr int `json:"mixedQueryNumPushNonVdr" yaml:"mixedQueryNumPushNonVdr"`
+
+	// If true, validators are sampled by their stake scaled by how quickly and
+	// reliably they respond, rather than by their stake alone.
+	AdaptiveSampling bool `json:"adaptiveSampling" yaml:"adaptiveSampling"`
+
+	// When sampling adaptively, every validator keeps at least this portion of
+	// its stake as its sampling weight. Lower values query slow validators
+	// less, but weaken the stake-proportional sampling. Must be in (0, 1] if
+	// AdaptiveSampling is enabled.
+	AdaptiveSamplingMinScore float64 `json:"adaptiveSamplingMinScore" yaml:"adaptiveSamplingMinScore"`
 }
 
 // Verify returns nil if the parameters describe a valid initialization.
@@ -80,6 +90,8 @@ func (p Parameters) Verify() error {
 		return fmt.Errorf("mixedQueryNumPushVdr (%d) > K (%d)", p.MixedQueryNumPushVdr, p.K)
 	case p.MixedQueryNumPushNonVdr > p.K:
 		return fmt.Errorf("mixedQueryNumPushNonVdr (%d) > K (%d)", p.MixedQueryNumPushNonVdr, p.K)
+	case p.AdaptiveSampling && (p.AdaptiveSamplingMinScore <= 0 || p.AdaptiveSamplingMinScore > 1):
+		return fmt.Errorf("adaptiveSamplingMinScore = %f: fails the condition that: 0 < adaptiveSamplingMinScore <= 1", p.AdaptiveSamplingMinScore)
 	default:
 		return nil
 	}
@@ -230,3 +230,31 @@ func TestParametersInvalidMaxItemProcessingTime(t *testing.T) {
 		t.Fatalf("Should have failed due to invalid max item processing time")
 	}
 }
+
+func TestParametersInvalidAdaptiveSamplingMinScore(t *testing.T) {
+	p := Parameters{
+		K:                        1,
+		Alpha:                    1,
+		BetaVirtuous:             1,
+		BetaRogue:                1,
+		ConcurrentRepolls:        1,
+		OptimalProcessing:        1,
+		MaxOutstandingItems:      1,
+		MaxItemProcessingTime:    1,
+		AdaptiveSamplingMinScore: 0,
+	}
+
+	if err := p.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	p.AdaptiveSampling = true
+	if err := p.Verify(); err == nil {
+		t.Fatalf("Should have failed due to invalid adaptive sampling min score")
+	}
+
+	p.AdaptiveSamplingMinScore = 0.5
+	if err := p.Verify(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -15,8 +15,11 @@ import (
 	"github.com/dim4egster/qmallgo/chains/atomic"
 	"github.com/dim4egster/qmallgo/ids"
 	"github.com/dim4egster/qmallgo/snow/validators"
+	"github.com/dim4egster/qmallgo/trace"
 	"github.com/dim4egster/qmallgo/utils"
+	"github.com/dim4egster/qmallgo/utils/crypto/keychain"
 	"github.com/dim4egster/qmallgo/utils/logging"
+	"github.com/dim4egster/qmallgo/vms/platformvm/warp"
 )
 
 type SubnetLookup interface {
@@ -55,6 +58,21 @@ type Context struct {
 	ValidatorState    validators.State  // interface for P-Chain validators
 	StakingLeafSigner crypto.Signer     // block signer
 	StakingCertLeaf   *x509.Certificate // block certificate
+
+	// Keychain signs for the keystore services in place of the keys of the
+	// keystore users if the node was started with an external signer. Nil
+	// otherwise.
+	Keychain keychain.Keychain
+
+	// WarpSigner signs the warp messages sent by this chain with the BLS key
+	// of this node.
+	WarpSigner warp.Signer
+
+	// Tracer is used to record spans for the operations of this chain.
+	Tracer trace.Tracer
+	// TraceContext is the context of the message that this chain is currently
+	// handling. Spans started by the VM should use it as their parent.
+	TraceContext *trace.CurrentContext
 }
 
 // Expose gatherer interface for unit testing.
@@ -127,6 +145,9 @@ func DefaultContextTest() *Context {
 		Log:       logging.NoLog{},
 		BCLookup:  ids.NewAliaser(),
 		Metrics:   metrics.NewOptionalGatherer(),
+
+		Tracer:       trace.Noop,
+		TraceContext: &trace.CurrentContext{},
 	}
 }
 
@@ -22,4 +22,8 @@ type Config struct {
 	Validators validators.Set
 	Params     snowball.Parameters
 	Consensus  snowman.Consensus
+
+	// HealthScorer is used to sample validators if [Params.AdaptiveSampling]
+	// is enabled.
+	HealthScorer validators.HealthScorer
 }
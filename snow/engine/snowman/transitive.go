@@ -18,6 +18,7 @@ import (
 	"github.com/dim4egster/qmallgo/snow/consensus/snowman/poll"
 	"github.com/dim4egster/qmallgo/snow/engine/common"
 	"github.com/dim4egster/qmallgo/snow/events"
+	"github.com/dim4egster/qmallgo/snow/validators"
 	"github.com/dim4egster/qmallgo/utils/wrappers"
 	"github.com/dim4egster/qmallgo/version"
 )
@@ -48,6 +49,9 @@ type Transitive struct {
 	// track outstanding preference requests
 	polls poll.Set
 
+	// samples the validators to query
+	sampler validators.Sampler
+
 	// blocks that have we have sent get requests for but haven't yet received
 	blkReqs common.Requests
 
@@ -87,6 +91,20 @@ func newTransitive(config Config) (*Transitive, error) {
 	if err != nil {
 		return nil, err
 	}
+	var sampler validators.Sampler = config.Validators
+	if config.Params.AdaptiveSampling {
+		sampler, err = validators.NewAdaptiveSampler(
+			config.Validators,
+			config.HealthScorer,
+			config.Params.AdaptiveSamplingMinScore,
+			"",
+			config.Ctx.Registerer,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	factory := poll.NewEarlyTermNoTraversalFactory(config.Params.Alpha)
 	t := &Transitive{
 		Config:                      config,
@@ -103,6 +121,7 @@ func newTransitive(config Config) (*Transitive, error) {
 			"",
 			config.Ctx.Registerer,
 		),
+		sampler: sampler,
 	}
 
 	return t, t.metrics.Initialize("", config.Ctx.Registerer)
@@ -664,7 +683,7 @@ func (t *Transitive) pullQuery(blkID ids.ID) {
 		zap.Stringer("validators", t.Validators),
 	)
 	// The validators we will query
-	vdrs, err := t.Validators.Sample(t.Params.K)
+	vdrs, err := t.sampler.Sample(t.Params.K)
 	if err != nil {
 		t.Ctx.Log.Error("dropped query for block",
 			zap.String("reason", "insufficient number of validators"),
@@ -693,7 +712,7 @@ func (t *Transitive) sendMixedQuery(blk snowman.Block) {
 	t.Ctx.Log.Verbo("sampling from validators",
 		zap.Stringer("validators", t.Validators),
 	)
-	vdrs, err := t.Validators.Sample(t.Params.K)
+	vdrs, err := t.sampler.Sample(t.Params.K)
 	if err != nil {
 		t.Ctx.Log.Error("dropped query for block",
 			zap.String("reason", "insufficient number of validators"),
@@ -3047,11 +3047,11 @@ func TestSendMixedQuery(t *testing.T) {
 				for i := 0; i < te.Config.Params.K; i++ {
 					vdr := ids.GenerateTestNodeID()
 					vdrs.Add(vdr)
-					vdrsList = append(vdrsList, validators.NewValidator(vdr, 1))
+					vdrsList = append(vdrsList, validators.NewValidator(vdr, nil, 1))
 				}
 				if tt.isVdr {
 					vdrs.Add(te.Ctx.NodeID)
-					vdrsList = append(vdrsList, validators.NewValidator(te.Ctx.NodeID, 1))
+					vdrsList = append(vdrsList, validators.NewValidator(te.Ctx.NodeID, nil, 1))
 				}
 				if err := vdrSet.Set(vdrsList); err != nil {
 					t.Fatal(err)
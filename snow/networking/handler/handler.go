@@ -5,10 +5,11 @@ package handler
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
-	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
 
 	"go.uber.org/zap"
 
@@ -23,6 +24,8 @@ import (
 	"github.com/dim4egster/qmallgo/utils/constants"
 	"github.com/dim4egster/qmallgo/utils/timer/mockable"
 	"github.com/dim4egster/qmallgo/version"
+
+	oteltrace "go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -47,6 +50,10 @@ type Handler interface {
 	Consensus() common.Engine
 
 	SetOnStopped(onStopped func())
+	// SetTime sets the time used to expire and time messages. Used to replay
+	// recorded traffic. Must only be called while no messages are being
+	// handled.
+	SetTime(now time.Time)
 	Start(recoverPanic bool)
 	Push(msg message.InboundMessage)
 	Stop()
@@ -108,6 +115,7 @@ func New(
 	preemptTimeouts chan struct{},
 	gossipFrequency time.Duration,
 	resourceTracker tracker.ResourceTracker,
+	lanes LanesConfig,
 ) (Handler, error) {
 	h := &handler{
 		ctx:              ctx,
@@ -130,11 +138,11 @@ func New(
 		return nil, fmt.Errorf("initializing handler metrics errored with: %w", err)
 	}
 	cpuTracker := resourceTracker.CPUTracker()
-	h.syncMessageQueue, err = NewMessageQueue(h.ctx.Log, h.validators, cpuTracker, "handler", h.ctx.Registerer, message.SynchronousOps)
+	h.syncMessageQueue, err = NewMessageQueue(h.ctx.Log, h.validators, cpuTracker, &h.clock, "handler", h.ctx.Registerer, message.SynchronousOps, lanes.Sync)
 	if err != nil {
 		return nil, fmt.Errorf("initializing sync message queue errored with: %w", err)
 	}
-	h.asyncMessageQueue, err = NewMessageQueue(h.ctx.Log, h.validators, cpuTracker, "handler_async", h.ctx.Registerer, message.AsynchronousOps)
+	h.asyncMessageQueue, err = NewMessageQueue(h.ctx.Log, h.validators, cpuTracker, &h.clock, "handler_async", h.ctx.Registerer, message.AsynchronousOps, lanes.Async)
 	if err != nil {
 		return nil, fmt.Errorf("initializing async message queue errored with: %w", err)
 	}
@@ -160,6 +168,8 @@ func (h *handler) Consensus() common.Engine          { return h.engine }
 
 func (h *handler) SetOnStopped(onStopped func()) { h.onStopped = onStopped }
 
+func (h *handler) SetTime(now time.Time) { h.clock.Set(now) }
+
 func (h *handler) selectStartingGear() (common.Engine, error) {
 	if h.stateSyncer == nil {
 		return h.bootstrapper, nil
@@ -230,6 +240,13 @@ func (h *handler) HealthCheck() (interface{}, error) {
 
 // Push the message onto the handler's queue
 func (h *handler) Push(msg message.InboundMessage) {
+	// The queue span is ended once the message is popped from the queue.
+	ctx, _ := h.ctx.Tracer.Start(msg.Context(), "handler.queue", oteltrace.WithAttributes(
+		attribute.Stringer("chainID", h.ctx.ChainID),
+		attribute.Stringer("op", msg.Op()),
+	))
+	msg = message.InboundWithContext(ctx, msg)
+
 	switch msg.Op() {
 	case message.AppRequest, message.AppGossip, message.AppRequestFailed, message.AppResponse:
 		h.asyncMessageQueue.Push(msg)
@@ -296,7 +313,7 @@ func (h *handler) dispatchSync() {
 	for {
 		// Get the next message we should process. If the handler is shutting
 		// down, we may fail to pop a message.
-		msg, ok := h.popUnexpiredMsg(h.syncMessageQueue, h.metrics.expired)
+		msg, ok := h.popMsg(h.syncMessageQueue)
 		if !ok {
 			return
 		}
@@ -323,7 +340,7 @@ func (h *handler) dispatchAsync() {
 	for {
 		// Get the next message we should process. If the handler is shutting
 		// down, we may fail to pop a message.
-		msg, ok := h.popUnexpiredMsg(h.asyncMessageQueue, h.metrics.asyncExpired)
+		msg, ok := h.popMsg(h.asyncMessageQueue)
 		if !ok {
 			return
 		}
@@ -400,6 +417,9 @@ func (h *handler) handleSyncMsg(msg message.InboundMessage) error {
 		return err
 	}
 
+	endSpan := h.traceEngine(msg, engine, true)
+	defer endSpan()
+
 	// Invariant: msg.Get(message.RequestID) must never error. The [ChainRouter]
 	//            should have already successfully called this function.
 	// Invariant: Response messages can never be dropped here. This is because
@@ -876,6 +896,11 @@ func (h *handler) executeAsyncMsg(msg message.InboundMessage) error {
 		return err
 	}
 
+	// The chain's lock isn't held while handling async messages, so the
+	// chain's current trace context must not be modified.
+	endSpan := h.traceEngine(msg, engine, false)
+	defer endSpan()
+
 	switch op {
 	case message.AppRequest:
 		requestIDIntf, err := msg.Get(message.RequestID)
@@ -983,6 +1008,9 @@ func (h *handler) handleChanMsg(msg message.InboundMessage) error {
 		return err
 	}
 
+	endSpan := h.traceEngine(msg, engine, true)
+	defer endSpan()
+
 	switch op := msg.Op(); op {
 	case message.Notify:
 		vmMsgIntf, err := msg.Get(message.VMMessage)
@@ -1021,29 +1049,40 @@ func (h *handler) getEngine() (common.Engine, error) {
 	}
 }
 
-func (h *handler) popUnexpiredMsg(queue MessageQueue, expired prometheus.Counter) (message.InboundMessage, bool) {
-	for {
-		// Get the next message we should process. If the handler is shutting
-		// down, we may fail to pop a message.
-		msg, ok := queue.Pop()
-		if !ok {
-			return nil, false
-		}
+// traceEngine starts a span for [engine] handling [msg] and returns the
+// function that ends it. If [setCurrent] is true, the span is recorded as the
+// chain's current trace context until it is ended. This must only be done
+// while holding the chain's lock.
+func (h *handler) traceEngine(msg message.InboundMessage, engine common.Engine, setCurrent bool) func() {
+	op := msg.Op()
+	engineName := strings.TrimPrefix(fmt.Sprintf("%T", engine), "*")
+	ctx, span := h.ctx.Tracer.Start(msg.Context(), fmt.Sprintf("%s.%s", engineName, op), oteltrace.WithAttributes(
+		attribute.Stringer("chainID", h.ctx.ChainID),
+		attribute.Stringer("nodeID", msg.NodeID()),
+		attribute.Stringer("op", op),
+	))
+	if !setCurrent {
+		return func() { span.End() }
+	}
 
-		// If this message's deadline has passed, don't process it.
-		if expirationTime := msg.ExpirationTime(); !expirationTime.IsZero() && h.clock.Time().After(expirationTime) {
-			h.ctx.Log.Verbo("dropping message",
-				zap.String("reason", "timeout"),
-				zap.Stringer("nodeID", msg.NodeID()),
-				zap.Stringer("messageString", msg),
-			)
-			expired.Inc()
-			msg.OnFinishedHandling()
-			continue
-		}
+	previousCtx := h.ctx.TraceContext.Set(ctx)
+	return func() {
+		h.ctx.TraceContext.Set(previousCtx)
+		span.End()
+	}
+}
 
-		return msg, true
+// popMsg returns the next message of [queue]. Expired messages are dropped by
+// [queue].
+func (*handler) popMsg(queue MessageQueue) (message.InboundMessage, bool) {
+	// Get the next message we should process. If the handler is shutting
+	// down, we may fail to pop a message.
+	msg, ok := queue.Pop()
+	if !ok {
+		return nil, false
 	}
+	oteltrace.SpanFromContext(msg.Context()).End()
+	return msg, true
 }
 
 func (h *handler) closeDispatcher() {
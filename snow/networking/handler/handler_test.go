@@ -46,6 +46,7 @@ func TestHandlerDropsTimedOutMessages(t *testing.T) {
 		nil,
 		time.Second,
 		resourceTracker,
+		DefaultLanesConfig,
 	)
 	require.NoError(t, err)
 	handler := handlerIntf.(*handler)
@@ -124,6 +125,7 @@ func TestHandlerClosesOnError(t *testing.T) {
 		nil,
 		time.Second,
 		resourceTracker,
+		DefaultLanesConfig,
 	)
 	require.NoError(t, err)
 	handler := handlerIntf.(*handler)
@@ -193,6 +195,7 @@ func TestHandlerDropsGossipDuringBootstrapping(t *testing.T) {
 		nil,
 		1,
 		resourceTracker,
+		DefaultLanesConfig,
 	)
 	require.NoError(t, err)
 	handler := handlerIntf.(*handler)
@@ -254,6 +257,7 @@ func TestHandlerDispatchInternal(t *testing.T) {
 		nil,
 		time.Second,
 		resourceTracker,
+		DefaultLanesConfig,
 	)
 	require.NoError(t, err)
 
@@ -0,0 +1,196 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package handler
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dim4egster/qmallgo/message"
+)
+
+// otherLaneName is the name of the lane that holds the messages whose op isn't
+// assigned to a configured lane.
+const otherLaneName = "other"
+
+var (
+	errNoLanes        = errors.New("no lanes")
+	errNoLaneName     = errors.New("lane has no name")
+	errDuplicateLane  = errors.New("duplicate lane name")
+	errZeroLaneWeight = errors.New("lane has zero weight")
+	errDuplicateOp    = errors.New("op is assigned to multiple lanes")
+	errUnexpectedOp   = errors.New("op isn't handled by the message queue")
+
+	// DefaultSyncLanes keeps consensus queries from queueing behind bulk
+	// bootstrapping traffic.
+	DefaultSyncLanes = []LaneConfig{
+		{
+			Name:   "consensus",
+			Weight: 4,
+			Ops: []message.Op{
+				message.Get,
+				message.Put,
+				message.PushQuery,
+				message.PullQuery,
+				message.Chits,
+				message.GetFailed,
+				message.QueryFailed,
+				message.Connected,
+				message.Disconnected,
+			},
+		},
+		{
+			Name:   "bootstrap",
+			Weight: 2,
+			Ops: []message.Op{
+				message.GetAcceptedFrontier,
+				message.AcceptedFrontier,
+				message.GetAccepted,
+				message.Accepted,
+				message.GetAncestors,
+				message.Ancestors,
+				message.GetAcceptedFrontierFailed,
+				message.GetAcceptedFailed,
+				message.GetAncestorsFailed,
+			},
+		},
+		{
+			Name:   "sync",
+			Weight: 1,
+			Ops: []message.Op{
+				message.GetStateSummaryFrontier,
+				message.StateSummaryFrontier,
+				message.GetAcceptedStateSummary,
+				message.AcceptedStateSummary,
+				message.GetStateSummaryFrontierFailed,
+				message.GetAcceptedStateSummaryFailed,
+			},
+		},
+	}
+
+	// DefaultAsyncLanes keeps app requests and responses from queueing behind
+	// app gossip.
+	DefaultAsyncLanes = []LaneConfig{
+		{
+			Name:   "app",
+			Weight: 2,
+			Ops: []message.Op{
+				message.AppRequest,
+				message.AppResponse,
+				message.AppRequestFailed,
+			},
+		},
+		{
+			Name:   "app_gossip",
+			Weight: 1,
+			Ops: []message.Op{
+				message.AppGossip,
+			},
+		},
+	}
+
+	DefaultLanesConfig = LanesConfig{
+		Sync:  DefaultSyncLanes,
+		Async: DefaultAsyncLanes,
+	}
+)
+
+// LanesConfig describes the lanes of the handler's message queues.
+type LanesConfig struct {
+	// Lanes of the queue of synchronous messages.
+	Sync []LaneConfig `json:"sync"`
+	// Lanes of the queue of asynchronous messages.
+	Async []LaneConfig `json:"async"`
+}
+
+// Verify returns an error if the lanes can't be used by the handler.
+func (c *LanesConfig) Verify() error {
+	if _, _, err := newLanes(c.Sync, message.SynchronousOps); err != nil {
+		return fmt.Errorf("invalid sync lanes: %w", err)
+	}
+	if _, _, err := newLanes(c.Async, message.AsynchronousOps); err != nil {
+		return fmt.Errorf("invalid async lanes: %w", err)
+	}
+	return nil
+}
+
+// LaneConfig describes a class of messages that are queued together.
+type LaneConfig struct {
+	// Name of the lane. Used in the names of the lane's metrics.
+	Name string `json:"name"`
+	// Weight is the number of messages popped from the lane before moving on
+	// to the next lane that has messages.
+	Weight int `json:"weight"`
+	// Ops whose messages are queued in the lane.
+	Ops []message.Op `json:"ops"`
+}
+
+type lane struct {
+	name   string
+	weight int
+	msgs   []message.InboundMessage
+}
+
+// newLanes returns the lanes described by [configs] and a mapping from each op
+// in [ops] to its lane. Ops that aren't assigned to a lane are queued in an
+// additional lane with a weight of 1.
+func newLanes(configs []LaneConfig, ops []message.Op) ([]*lane, map[message.Op]*lane, error) {
+	var (
+		lanes     = make([]*lane, 0, len(configs)+1)
+		laneNames = make(map[string]struct{}, len(configs)+1)
+		opToLane  = make(map[message.Op]*lane, len(ops))
+		queuedOps = make(map[message.Op]struct{}, len(ops))
+	)
+	for _, op := range ops {
+		queuedOps[op] = struct{}{}
+	}
+	for _, config := range configs {
+		if config.Name == "" {
+			return nil, nil, errNoLaneName
+		}
+		if _, ok := laneNames[config.Name]; ok {
+			return nil, nil, fmt.Errorf("%w: %s", errDuplicateLane, config.Name)
+		}
+		if config.Weight <= 0 {
+			return nil, nil, fmt.Errorf("%w: %s", errZeroLaneWeight, config.Name)
+		}
+		l := &lane{
+			name:   config.Name,
+			weight: config.Weight,
+		}
+		for _, op := range config.Ops {
+			if _, ok := queuedOps[op]; !ok {
+				return nil, nil, fmt.Errorf("%w: %s", errUnexpectedOp, op)
+			}
+			if _, ok := opToLane[op]; ok {
+				return nil, nil, fmt.Errorf("%w: %s", errDuplicateOp, op)
+			}
+			opToLane[op] = l
+		}
+		lanes = append(lanes, l)
+		laneNames[config.Name] = struct{}{}
+	}
+
+	var other *lane
+	for _, op := range ops {
+		if _, ok := opToLane[op]; ok {
+			continue
+		}
+		if other == nil {
+			if _, ok := laneNames[otherLaneName]; ok {
+				return nil, nil, fmt.Errorf("%w: %s", errDuplicateLane, otherLaneName)
+			}
+			other = &lane{
+				name:   otherLaneName,
+				weight: 1,
+			}
+			lanes = append(lanes, other)
+		}
+		opToLane[op] = other
+	}
+	if len(lanes) == 0 {
+		return nil, nil, errNoLanes
+	}
+	return lanes, opToLane, nil
+}
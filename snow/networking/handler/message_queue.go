@@ -16,6 +16,8 @@ import (
 	"github.com/dim4egster/qmallgo/snow/validators"
 	"github.com/dim4egster/qmallgo/utils/logging"
 	"github.com/dim4egster/qmallgo/utils/timer/mockable"
+
+	oteltrace "go.opentelemetry.io/otel/trace"
 )
 
 var _ MessageQueue = &messageQueue{}
@@ -30,7 +32,8 @@ type MessageQueue interface {
 	// Get and remove a message.
 	//
 	// If there are no available messages, this function will block until a
-	// message becomes available or the queue is [Shutdown].
+	// message becomes available or the queue is [Shutdown]. Messages whose
+	// deadline has passed are dropped rather than returned.
 	Pop() (message.InboundMessage, bool)
 
 	// Returns the number of messages currently on the queue
@@ -40,12 +43,14 @@ type MessageQueue interface {
 	Shutdown()
 }
 
-// TODO: Use a better data structure for this.
-// We can do something better than pushing to the back of a queue. A multi-level
-// queue?
+// messageQueue queues messages in lanes that are served in a weighted
+// round-robin. A lane with messages is served at least once in every window of
+// [sum of the lane weights] pops, so no lane is starved. Expired messages are
+// dropped when they are pushed or reached.
 type messageQueue struct {
+	// Shared with the handler so that both agree on which messages expired.
 	// Useful for faking time in tests
-	clock   mockable.Clock
+	clock   *mockable.Clock
 	metrics messageQueueMetrics
 
 	log logging.Logger
@@ -56,28 +61,46 @@ type messageQueue struct {
 
 	cond   *sync.Cond
 	closed bool
-	// Node ID --> Messages this node has in [msgs]
+	// Node ID --> Messages this node has in [lanes]
 	nodeToUnprocessedMsgs map[ids.NodeID]int
-	// Unprocessed messages
-	msgs []message.InboundMessage
+	// Number of messages in [lanes]
+	numMsgs int
+
+	lanes    []*lane
+	opToLane map[message.Op]*lane
+	// Index of the lane currently being served
+	currentLane int
+	// Messages that may still be popped from the current lane before moving
+	// on to the next lane
+	credits int
 }
 
 func NewMessageQueue(
 	log logging.Logger,
 	vdrs validators.Set,
 	cpuTracker tracker.Tracker,
+	clock *mockable.Clock,
 	metricsNamespace string,
 	metricsRegisterer prometheus.Registerer,
 	ops []message.Op,
+	laneConfigs []LaneConfig,
 ) (MessageQueue, error) {
+	lanes, opToLane, err := newLanes(laneConfigs, ops)
+	if err != nil {
+		return nil, err
+	}
 	m := &messageQueue{
 		log:                   log,
 		vdrs:                  vdrs,
 		cpuTracker:            cpuTracker,
+		clock:                 clock,
 		cond:                  sync.NewCond(&sync.Mutex{}),
 		nodeToUnprocessedMsgs: make(map[ids.NodeID]int),
+		lanes:                 lanes,
+		opToLane:              opToLane,
+		credits:               lanes[0].weight,
 	}
-	return m, m.metrics.initialize(metricsNamespace, metricsRegisterer, ops)
+	return m, m.metrics.initialize(metricsNamespace, metricsRegisterer, ops, lanes)
 }
 
 func (m *messageQueue) Push(msg message.InboundMessage) {
@@ -89,19 +112,28 @@ func (m *messageQueue) Push(msg message.InboundMessage) {
 		return
 	}
 
+	l := m.opToLane[msg.Op()]
+	if m.expired(msg) {
+		m.drop(l, msg)
+		return
+	}
+
 	// Add the message to the queue
-	m.msgs = append(m.msgs, msg)
+	l.msgs = append(l.msgs, msg)
+	m.numMsgs++
 	m.nodeToUnprocessedMsgs[msg.NodeID()]++
 
 	// Update metrics
 	m.metrics.nodesWithMessages.Set(float64(len(m.nodeToUnprocessedMsgs)))
 	m.metrics.len.Inc()
+	m.metrics.lanes[l.name].Inc()
 	m.metrics.ops[msg.Op()].Inc()
 
 	// Signal a waiting thread
 	m.cond.Signal()
 }
 
+// Pop serves the lanes in a weighted round-robin. Within a lane, messages are
 // FIFO, but skip over messages whose senders whose messages have caused us to
 // use excessive CPU recently.
 func (m *messageQueue) Pop() (message.InboundMessage, bool) {
@@ -109,56 +141,119 @@ func (m *messageQueue) Pop() (message.InboundMessage, bool) {
 	defer m.cond.L.Unlock()
 
 	for {
-		if m.closed {
-			return nil, false
+		for {
+			if m.closed {
+				return nil, false
+			}
+			if m.numMsgs != 0 {
+				break
+			}
+			m.cond.Wait()
 		}
-		if len(m.msgs) != 0 {
-			break
+
+		if msg, ok := m.popLane(m.nextLane()); ok {
+			return msg, true
 		}
-		m.cond.Wait()
+		// Every message of the lane was expired and dropped.
 	}
+}
 
-	n := len(m.msgs)
-	i := 0
+// nextLane returns the lane to pop the next message from.
+//
+// Assumes there is at least one message in [m.lanes]
+func (m *messageQueue) nextLane() *lane {
 	for {
+		l := m.lanes[m.currentLane]
+		if m.credits > 0 && len(l.msgs) != 0 {
+			m.credits--
+			return l
+		}
+		m.currentLane = (m.currentLane + 1) % len(m.lanes)
+		m.credits = m.lanes[m.currentLane].weight
+	}
+}
+
+// popLane removes and returns the next message of [l]. Returns false if [l]
+// only held expired messages, which were dropped.
+func (m *messageQueue) popLane(l *lane) (message.InboundMessage, bool) {
+	n := len(l.msgs)
+	i := 0
+	for len(l.msgs) != 0 {
 		if i == n {
 			m.log.Debug("canPop is false for all unprocessed messages",
+				zap.String("lane", l.name),
 				zap.Int("numMessages", n),
 			)
 		}
-		msg := m.msgs[0]
-		m.msgs[0] = nil
-		nodeID := msg.NodeID()
+		msg := m.removeFront(l)
+		if m.expired(msg) {
+			m.remove(l, msg)
+			m.drop(l, msg)
+			n--
+			continue
+		}
 		// See if it's OK to process [msg] next
-		if m.canPop(msg) || i == n { // i should never == n but handle anyway as a fail-safe
-			if cap(m.msgs) == 1 {
-				m.msgs = nil // Give back memory if possible
-			} else {
-				m.msgs = m.msgs[1:]
-			}
-			m.nodeToUnprocessedMsgs[nodeID]--
-			if m.nodeToUnprocessedMsgs[nodeID] == 0 {
-				delete(m.nodeToUnprocessedMsgs, nodeID)
-			}
-			m.metrics.nodesWithMessages.Set(float64(len(m.nodeToUnprocessedMsgs)))
-			m.metrics.len.Dec()
-			m.metrics.ops[msg.Op()].Dec()
+		if m.canPop(msg) || i >= n { // i should never == n but handle anyway as a fail-safe
+			m.remove(l, msg)
 			return msg, true
 		}
 		// [msg.nodeID] is causing excessive CPU usage.
-		// Push [msg] to back of [m.msgs] and handle it later.
-		m.msgs = append(m.msgs, msg)
-		m.msgs = m.msgs[1:]
+		// Push [msg] to back of [l.msgs] and handle it later.
+		l.msgs = append(l.msgs, msg)
 		i++
 		m.metrics.numExcessiveCPU.Inc()
 	}
+	return nil, false
+}
+
+// removeFront removes the first message of [l.msgs] without updating the
+// bookkeeping of the queue.
+func (*messageQueue) removeFront(l *lane) message.InboundMessage {
+	msg := l.msgs[0]
+	l.msgs[0] = nil
+	if cap(l.msgs) == 1 {
+		l.msgs = nil // Give back memory if possible
+	} else {
+		l.msgs = l.msgs[1:]
+	}
+	return msg
+}
+
+// remove updates the bookkeeping of the queue after [msg] was removed from
+// [l.msgs].
+func (m *messageQueue) remove(l *lane, msg message.InboundMessage) {
+	nodeID := msg.NodeID()
+	m.numMsgs--
+	m.nodeToUnprocessedMsgs[nodeID]--
+	if m.nodeToUnprocessedMsgs[nodeID] == 0 {
+		delete(m.nodeToUnprocessedMsgs, nodeID)
+	}
+	m.metrics.nodesWithMessages.Set(float64(len(m.nodeToUnprocessedMsgs)))
+	m.metrics.len.Dec()
+	m.metrics.lanes[l.name].Dec()
+	m.metrics.ops[msg.Op()].Dec()
+}
+
+// drop marks the expired [msg] of [l] as handled. [msg] must not be in the
+// queue.
+func (m *messageQueue) drop(l *lane, msg message.InboundMessage) {
+	m.log.Verbo("dropping message",
+		zap.String("reason", "timeout"),
+		zap.String("lane", l.name),
+		zap.Stringer("nodeID", msg.NodeID()),
+		zap.Stringer("messageString", msg),
+	)
+	m.metrics.expired.Inc()
+	m.metrics.laneExpired[l.name].Inc()
+	oteltrace.SpanFromContext(msg.Context()).End()
+	msg.OnFinishedHandling()
 }
 
 func (m *messageQueue) Len() int {
 	m.cond.L.Lock()
 	defer m.cond.L.Unlock()
 
-	return len(m.msgs)
+	return m.numMsgs
 }
 
 func (m *messageQueue) Shutdown() {
@@ -166,10 +261,14 @@ func (m *messageQueue) Shutdown() {
 	defer m.cond.L.Unlock()
 
 	// Remove all the current messages from the queue
-	for _, msg := range m.msgs {
-		msg.OnFinishedHandling()
+	for _, l := range m.lanes {
+		for _, msg := range l.msgs {
+			msg.OnFinishedHandling()
+		}
+		l.msgs = nil
+		m.metrics.lanes[l.name].Set(0)
 	}
-	m.msgs = nil
+	m.numMsgs = 0
 	m.nodeToUnprocessedMsgs = nil
 
 	// Update metrics
@@ -181,7 +280,13 @@ func (m *messageQueue) Shutdown() {
 	m.cond.Broadcast()
 }
 
-// canPop will return true for at least one message in [m.msgs]
+// expired returns true if the deadline to handle [msg] has passed.
+func (m *messageQueue) expired(msg message.InboundMessage) bool {
+	expirationTime := msg.ExpirationTime()
+	return !expirationTime.IsZero() && m.clock.Time().After(expirationTime)
+}
+
+// canPop will return true for at least one message in [m.lanes]
 func (m *messageQueue) canPop(msg message.InboundMessage) bool {
 	// Always pop connected and disconnected messages.
 	if op := msg.Op(); op == message.Connected || op == message.Disconnected {
@@ -190,7 +295,7 @@ func (m *messageQueue) canPop(msg message.InboundMessage) bool {
 
 	// If the deadline to handle [msg] has passed, always pop it.
 	// It will be dropped immediately.
-	if expirationTime := msg.ExpirationTime(); !expirationTime.IsZero() && m.clock.Time().After(expirationTime) {
+	if m.expired(msg) {
 		return true
 	}
 	// Every node has some allowed CPU allocation depending on
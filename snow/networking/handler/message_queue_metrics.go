@@ -17,12 +17,16 @@ type messageQueueMetrics struct {
 	len               prometheus.Gauge
 	nodesWithMessages prometheus.Gauge
 	numExcessiveCPU   prometheus.Counter
+	lanes             map[string]prometheus.Gauge
+	expired           prometheus.Counter
+	laneExpired       map[string]prometheus.Counter
 }
 
 func (m *messageQueueMetrics) initialize(
 	metricsNamespace string,
 	metricsRegisterer prometheus.Registerer,
 	ops []message.Op,
+	lanes []*lane,
 ) error {
 	namespace := fmt.Sprintf("%s_%s", metricsNamespace, "unprocessed_msgs")
 	m.len = prometheus.NewGauge(prometheus.GaugeOpts{
@@ -41,6 +45,12 @@ func (m *messageQueueMetrics) initialize(
 		Help:      "Times we deferred handling a message from a node because the node was using excessive CPU",
 	})
 
+	m.expired = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "expired",
+		Help:      "Incoming messages dropped because the message deadline expired",
+	})
+
 	errs := wrappers.Errs{}
 	m.ops = make(map[message.Op]prometheus.Gauge, len(ops))
 
@@ -55,10 +65,33 @@ func (m *messageQueueMetrics) initialize(
 		errs.Add(metricsRegisterer.Register(opMetric))
 	}
 
+	m.lanes = make(map[string]prometheus.Gauge, len(lanes))
+	m.laneExpired = make(map[string]prometheus.Counter, len(lanes))
+
+	for _, l := range lanes {
+		laneMetric := prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      fmt.Sprintf("%s_lane_len", l.name),
+			Help:      fmt.Sprintf("Messages in the %s lane of the message queue", l.name),
+		})
+		expiredMetric := prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      fmt.Sprintf("%s_lane_expired", l.name),
+			Help:      fmt.Sprintf("Messages dropped from the %s lane of the message queue because their deadline passed", l.name),
+		})
+		m.lanes[l.name] = laneMetric
+		m.laneExpired[l.name] = expiredMetric
+		errs.Add(
+			metricsRegisterer.Register(laneMetric),
+			metricsRegisterer.Register(expiredMetric),
+		)
+	}
+
 	errs.Add(
 		metricsRegisterer.Register(m.len),
 		metricsRegisterer.Register(m.nodesWithMessages),
 		metricsRegisterer.Register(m.numExcessiveCPU),
+		metricsRegisterer.Register(m.expired),
 	)
 	return errs.Err
 }
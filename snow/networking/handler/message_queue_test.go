@@ -4,6 +4,7 @@
 package handler
 
 import (
+	"encoding/json"
 	"fmt"
 	"testing"
 	"time"
@@ -11,6 +12,7 @@ import (
 	"github.com/golang/mock/gomock"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 
 	"github.com/stretchr/testify/require"
 
@@ -18,7 +20,9 @@ import (
 	"github.com/dim4egster/qmallgo/message"
 	"github.com/dim4egster/qmallgo/snow/networking/tracker"
 	"github.com/dim4egster/qmallgo/snow/validators"
+	"github.com/dim4egster/qmallgo/utils/compression"
 	"github.com/dim4egster/qmallgo/utils/logging"
+	"github.com/dim4egster/qmallgo/utils/timer/mockable"
 )
 
 func TestQueue(t *testing.T) {
@@ -33,7 +37,7 @@ func TestQueue(t *testing.T) {
 			vdr1ID, vdr2ID := ids.GenerateTestNodeID(), ids.GenerateTestNodeID()
 			require.NoError(vdrs.AddWeight(vdr1ID, 1))
 			require.NoError(vdrs.AddWeight(vdr2ID, 1))
-			mIntf, err := NewMessageQueue(logging.NoLog{}, vdrs, cpuTracker, "", prometheus.NewRegistry(), message.SynchronousOps)
+			mIntf, err := NewMessageQueue(logging.NoLog{}, vdrs, cpuTracker, &mockable.Clock{}, "", prometheus.NewRegistry(), message.SynchronousOps, nil)
 			require.NoError(err)
 			u := mIntf.(*messageQueue)
 			currentTime := time.Now()
@@ -44,7 +48,7 @@ func TestQueue(t *testing.T) {
 			if !useProto {
 				mc, err = message.NewCreator(metrics, "dummyNamespace", true, 10*time.Second)
 			} else {
-				mc, err = message.NewCreatorWithProto(metrics, "dummyNamespace", true, 10*time.Second)
+				mc, err = message.NewCreatorWithProto(metrics, "dummyNamespace", compression.TypeGzip, nil, 10*time.Second)
 			}
 			require.NoError(err)
 
@@ -156,3 +160,217 @@ func TestQueue(t *testing.T) {
 		})
 	}
 }
+
+func TestQueueLanes(t *testing.T) {
+	require := require.New(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cpuTracker := tracker.NewMockTracker(ctrl)
+	cpuTracker.EXPECT().Usage(gomock.Any(), gomock.Any()).Return(0.0).AnyTimes()
+	vdrs := validators.NewSet()
+	vdrID := ids.GenerateTestNodeID()
+	require.NoError(vdrs.AddWeight(vdrID, 1))
+
+	lanes := []LaneConfig{
+		{
+			Name:   "consensus",
+			Weight: 2,
+			Ops:    []message.Op{message.PushQuery},
+		},
+		{
+			Name:   "bootstrap",
+			Weight: 1,
+			Ops:    []message.Op{message.Ancestors},
+		},
+	}
+	registry := prometheus.NewRegistry()
+	mIntf, err := NewMessageQueue(logging.NoLog{}, vdrs, cpuTracker, &mockable.Clock{}, "", registry, message.SynchronousOps, lanes)
+	require.NoError(err)
+	u := mIntf.(*messageQueue)
+	require.Len(u.lanes, 3)
+	require.Equal(otherLaneName, u.lanes[2].name)
+
+	mc, err := message.NewCreator(prometheus.NewRegistry(), "dummyNamespace", true, 10*time.Second)
+	require.NoError(err)
+
+	// Bulk traffic is queued before the queries.
+	for i := uint32(0); i < 3; i++ {
+		u.Push(mc.InboundAncestors(ids.Empty, i, nil, vdrID))
+	}
+	for i := uint32(0); i < 3; i++ {
+		u.Push(mc.InboundPushQuery(ids.Empty, i, time.Second, nil, vdrID))
+	}
+	u.Push(mc.InboundPut(ids.Empty, 0, nil, vdrID))
+	require.Equal(7, u.Len())
+	require.Len(u.lanes[0].msgs, 3)
+	require.Len(u.lanes[1].msgs, 3)
+	require.Len(u.lanes[2].msgs, 1)
+
+	// The lanes are served in a weighted round-robin.
+	expectedOps := []message.Op{
+		message.PushQuery,
+		message.PushQuery,
+		message.Ancestors,
+		message.Put,
+		message.PushQuery,
+		message.Ancestors,
+		message.Ancestors,
+	}
+	for _, expectedOp := range expectedOps {
+		msg, ok := u.Pop()
+		require.True(ok)
+		require.Equal(expectedOp, msg.Op())
+	}
+	require.Zero(u.Len())
+	require.Empty(u.nodeToUnprocessedMsgs)
+}
+
+func TestQueueDropsExpiredMessages(t *testing.T) {
+	require := require.New(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cpuTracker := tracker.NewMockTracker(ctrl)
+	cpuTracker.EXPECT().Usage(gomock.Any(), gomock.Any()).Return(0.0).AnyTimes()
+	vdrs := validators.NewSet()
+	vdrID := ids.GenerateTestNodeID()
+	require.NoError(vdrs.AddWeight(vdrID, 1))
+
+	lanes := []LaneConfig{
+		{
+			Name:   "consensus",
+			Weight: 1,
+			Ops:    []message.Op{message.PushQuery},
+		},
+	}
+	clock := &mockable.Clock{}
+	now := time.Now()
+	clock.Set(now)
+	mIntf, err := NewMessageQueue(logging.NoLog{}, vdrs, cpuTracker, clock, "", prometheus.NewRegistry(), message.SynchronousOps, lanes)
+	require.NoError(err)
+	u := mIntf.(*messageQueue)
+
+	mc, err := message.NewCreator(prometheus.NewRegistry(), "dummyNamespace", true, 10*time.Second)
+	require.NoError(err)
+	mc.SetTime(now)
+
+	// A query that expired before it was pushed is dropped immediately.
+	clock.Set(now.Add(2 * time.Second))
+	u.Push(mc.InboundPushQuery(ids.Empty, 0, time.Second, nil, vdrID))
+	require.Zero(u.Len())
+
+	// A query that expires while it is queued is dropped on Pop.
+	clock.Set(now)
+	u.Push(mc.InboundPushQuery(ids.Empty, 1, time.Second, nil, vdrID))
+	u.Push(mc.InboundPushQuery(ids.Empty, 2, 3*time.Second, nil, vdrID))
+	// Expired messages are dropped from every lane.
+	u.Push(mc.InboundGetAcceptedFrontier(ids.Empty, 3, time.Second, vdrID))
+	u.Push(mc.InboundGetAcceptedFrontier(ids.Empty, 4, 3*time.Second, vdrID))
+	require.Equal(4, u.Len())
+
+	clock.Set(now.Add(2 * time.Second))
+	msg, ok := u.Pop()
+	require.True(ok)
+	require.Equal(message.PushQuery, msg.Op())
+	requestID, err := msg.Get(message.RequestID)
+	require.NoError(err)
+	require.EqualValues(2, requestID)
+
+	msg, ok = u.Pop()
+	require.True(ok)
+	require.Equal(message.GetAcceptedFrontier, msg.Op())
+	requestID, err = msg.Get(message.RequestID)
+	require.NoError(err)
+	require.EqualValues(4, requestID)
+	require.Zero(u.Len())
+	require.Empty(u.nodeToUnprocessedMsgs)
+
+	// Every drop is counted once, in total and by lane.
+	require.Equal(3.0, testutil.ToFloat64(u.metrics.expired))
+	require.Equal(2.0, testutil.ToFloat64(u.metrics.laneExpired["consensus"]))
+	require.Equal(1.0, testutil.ToFloat64(u.metrics.laneExpired[otherLaneName]))
+}
+
+func TestLanesConfigJSON(t *testing.T) {
+	require := require.New(t)
+
+	var config LanesConfig
+	require.NoError(json.Unmarshal([]byte(`{"sync":[{"name":"queries","weight":3,"ops":["push_query","pull_query"]}]}`), &config))
+	require.Equal([]LaneConfig{{
+		Name:   "queries",
+		Weight: 3,
+		Ops:    []message.Op{message.PushQuery, message.PullQuery},
+	}}, config.Sync)
+	require.Nil(config.Async)
+	require.NoError(config.Verify())
+
+	require.Error(json.Unmarshal([]byte(`{"sync":[{"name":"queries","weight":3,"ops":["not_an_op"]}]}`), &config))
+
+	// Ops of the other queue are rejected.
+	config = LanesConfig{
+		Async: []LaneConfig{{Name: "lane", Weight: 1, Ops: []message.Op{message.Put}}},
+	}
+	require.ErrorIs(config.Verify(), errUnexpectedOp)
+	require.NoError(DefaultLanesConfig.Verify())
+}
+
+func TestNewMessageQueueInvalidLanes(t *testing.T) {
+	tests := []struct {
+		name        string
+		lanes       []LaneConfig
+		expectedErr error
+	}{
+		{
+			name:        "no name",
+			lanes:       []LaneConfig{{Weight: 1}},
+			expectedErr: errNoLaneName,
+		},
+		{
+			name: "duplicate name",
+			lanes: []LaneConfig{
+				{Name: "lane", Weight: 1},
+				{Name: "lane", Weight: 1},
+			},
+			expectedErr: errDuplicateLane,
+		},
+		{
+			name: "conflicts with the other lane",
+			lanes: []LaneConfig{
+				{Name: otherLaneName, Weight: 1, Ops: []message.Op{message.Put}},
+			},
+			expectedErr: errDuplicateLane,
+		},
+		{
+			name:        "zero weight",
+			lanes:       []LaneConfig{{Name: "lane"}},
+			expectedErr: errZeroLaneWeight,
+		},
+		{
+			name: "duplicate op",
+			lanes: []LaneConfig{
+				{Name: "lane0", Weight: 1, Ops: []message.Op{message.Put}},
+				{Name: "lane1", Weight: 1, Ops: []message.Op{message.Put}},
+			},
+			expectedErr: errDuplicateOp,
+		},
+		{
+			name: "op of the other queue",
+			lanes: []LaneConfig{
+				{Name: "lane", Weight: 1, Ops: []message.Op{message.AppGossip}},
+			},
+			expectedErr: errUnexpectedOp,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewMessageQueue(logging.NoLog{}, validators.NewSet(), nil, &mockable.Clock{}, "", prometheus.NewRegistry(), message.SynchronousOps, test.lanes)
+			require.ErrorIs(t, err, test.expectedErr)
+		})
+	}
+
+	_, err := NewMessageQueue(logging.NoLog{}, validators.NewSet(), nil, &mockable.Clock{}, "", prometheus.NewRegistry(), nil, nil)
+	require.ErrorIs(t, err, errNoLanes)
+}
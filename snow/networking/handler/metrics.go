@@ -14,29 +14,12 @@ import (
 )
 
 type metrics struct {
-	expired      prometheus.Counter
-	asyncExpired prometheus.Counter
-	messages     map[message.Op]metric.Averager
+	messages map[message.Op]metric.Averager
 }
 
 func newMetrics(namespace string, reg prometheus.Registerer) (*metrics, error) {
 	errs := wrappers.Errs{}
 
-	expired := prometheus.NewCounter(prometheus.CounterOpts{
-		Namespace: namespace,
-		Name:      "expired",
-		Help:      "Incoming sync messages dropped because the message deadline expired",
-	})
-	asyncExpired := prometheus.NewCounter(prometheus.CounterOpts{
-		Namespace: namespace,
-		Name:      "async_expired",
-		Help:      "Incoming async messages dropped because the message deadline expired",
-	})
-	errs.Add(
-		reg.Register(expired),
-		reg.Register(asyncExpired),
-	)
-
 	messages := make(map[message.Op]metric.Averager, len(message.ConsensusOps))
 	for _, op := range message.ConsensusOps {
 		opStr := op.String()
@@ -50,8 +33,6 @@ func newMetrics(namespace string, reg prometheus.Registerer) (*metrics, error) {
 	}
 
 	return &metrics{
-		expired:      expired,
-		asyncExpired: asyncExpired,
-		messages:     messages,
+		messages: messages,
 	}, errs.Err
 }
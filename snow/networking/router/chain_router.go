@@ -303,12 +303,21 @@ func (cr *ChainRouter) HandleInbound(msg message.InboundMessage) {
 	latency := cr.clock.Time().Sub(req.time)
 
 	// Tell the timeout manager we got a response
-	cr.timeoutManager.RegisterResponse(nodeID, chainID, uniqueRequestID, req.op, latency)
+	cr.timeoutManager.RegisterResponse(nodeID, chainID, uniqueRequestID, req.op, latency, msg.NumBytes())
 
 	// Pass the response to the chain
 	chain.Push(msg)
 }
 
+// SetTime sets the time used to measure how long requests took to be responded
+// to. Used to replay recorded traffic.
+func (cr *ChainRouter) SetTime(now time.Time) {
+	cr.lock.Lock()
+	defer cr.lock.Unlock()
+
+	cr.clock.Set(now)
+}
+
 // Shutdown shuts down this router
 func (cr *ChainRouter) Shutdown() {
 	cr.log.Info("shutting down chain router")
@@ -42,6 +42,7 @@ func TestShutdown(t *testing.T) {
 			TimeoutHalflife:    5 * time.Minute,
 		},
 		benchlist,
+		tracker.NewNoHealthTracker(),
 		"",
 		prometheus.NewRegistry(),
 	)
@@ -67,6 +68,7 @@ func TestShutdown(t *testing.T) {
 		nil,
 		time.Second,
 		resourceTracker,
+		handler.DefaultLanesConfig,
 	)
 	require.NoError(t, err)
 
@@ -134,6 +136,7 @@ func TestShutdownTimesOut(t *testing.T) {
 			TimeoutHalflife:    5 * time.Minute,
 		},
 		benchlist,
+		tracker.NewNoHealthTracker(),
 		"",
 		metrics,
 	)
@@ -170,6 +173,7 @@ func TestShutdownTimesOut(t *testing.T) {
 		nil,
 		time.Second,
 		resourceTracker,
+		handler.DefaultLanesConfig,
 	)
 	require.NoError(t, err)
 
@@ -241,6 +245,7 @@ func TestRouterTimeout(t *testing.T) {
 			TimeoutHalflife:    5 * time.Minute,
 		},
 		benchlist.NewNoBenchlist(),
+		tracker.NewNoHealthTracker(),
 		"",
 		prometheus.NewRegistry(),
 	)
@@ -278,6 +283,7 @@ func TestRouterTimeout(t *testing.T) {
 		nil,
 		time.Second,
 		resourceTracker,
+		handler.DefaultLanesConfig,
 	)
 	require.NoError(t, err)
 
@@ -361,6 +367,7 @@ func TestRouterClearTimeouts(t *testing.T) {
 			TimeoutHalflife:    5 * time.Minute,
 		},
 		benchlist.NewNoBenchlist(),
+		tracker.NewNoHealthTracker(),
 		"",
 		prometheus.NewRegistry(),
 	)
@@ -393,6 +400,7 @@ func TestRouterClearTimeouts(t *testing.T) {
 		nil,
 		time.Second,
 		resourceTracker,
+		handler.DefaultLanesConfig,
 	)
 	require.NoError(t, err)
 
@@ -472,6 +480,7 @@ func TestValidatorOnlyMessageDrops(t *testing.T) {
 			TimeoutHalflife:    5 * time.Minute,
 		},
 		benchlist.NewNoBenchlist(),
+		tracker.NewNoHealthTracker(),
 		"",
 		prometheus.NewRegistry(),
 	)
@@ -508,6 +517,7 @@ func TestValidatorOnlyMessageDrops(t *testing.T) {
 		nil,
 		time.Second,
 		resourceTracker,
+		handler.DefaultLanesConfig,
 	)
 	require.NoError(t, err)
 
@@ -0,0 +1,38 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package router
+
+import (
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/network/recorder"
+	"github.com/dim4egster/qmallgo/version"
+)
+
+var _ Router = &recordedRouter{}
+
+type recordedRouter struct {
+	Router
+
+	recorder recorder.Recorder
+}
+
+// Record returns a Router that records the peers connecting to and
+// disconnecting from [router]. Together with the inbound messages recorded by
+// the peers, the recording can be replayed with a Replayer.
+func Record(router Router, recorder recorder.Recorder) Router {
+	return &recordedRouter{
+		Router:   router,
+		recorder: recorder,
+	}
+}
+
+func (r *recordedRouter) Connected(nodeID ids.NodeID, nodeVersion *version.Application, subnetID ids.ID) {
+	r.recorder.Connected(nodeID, nodeVersion, subnetID)
+	r.Router.Connected(nodeID, nodeVersion, subnetID)
+}
+
+func (r *recordedRouter) Disconnected(nodeID ids.NodeID) {
+	r.recorder.Disconnected(nodeID)
+	r.Router.Disconnected(nodeID)
+}
@@ -0,0 +1,187 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package router
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/dim4egster/qmallgo/message"
+	"github.com/dim4egster/qmallgo/network/recorder"
+	"github.com/dim4egster/qmallgo/snow/networking/handler"
+	"github.com/dim4egster/qmallgo/snow/networking/timeout"
+)
+
+var (
+	errHandlerStopped = errors.New("handler stopped")
+
+	_ handler.Handler        = &replayHandler{}
+	_ message.InboundMessage = &replayedMessage{}
+)
+
+// Replayer feeds recorded network traffic into a chain through a router, as
+// the network would have. Time, as seen by the message parsers, the router,
+// the chain's handler and the timeout manager, is set to the time each record
+// was recorded at. Before a record is replayed, the requests that timed out by
+// then are failed and every message pushed to the chain is handled, so
+// replaying a recording into a chain built the same way always drops, fails
+// and handles the same messages.
+//
+// Messages the engine sends to itself, and the timeouts and gossip requests of
+// the handler, are still driven by the wall clock.
+type Replayer struct {
+	router   *ChainRouter
+	timeouts timeout.Manager
+	handler  *replayHandler
+	// Parse the recorded messages that were sent without protobuf.
+	parser message.Parser
+	// Parse the recorded messages that were sent with protobuf.
+	protoParser message.Parser
+}
+
+// NewReplayer returns a Replayer that feeds recorded traffic into [h] through
+// [router]. [h] is added to [router] and must not have been added before.
+// [router] must use [timeouts], which must not be dispatched. [h] must be
+// started before replaying.
+func NewReplayer(
+	router *ChainRouter,
+	timeouts timeout.Manager,
+	h handler.Handler,
+	parser message.Parser,
+	protoParser message.Parser,
+) *Replayer {
+	replayHandler := &replayHandler{Handler: h}
+	router.AddChain(replayHandler)
+	return &Replayer{
+		router:      router,
+		timeouts:    timeouts,
+		handler:     replayHandler,
+		parser:      parser,
+		protoParser: protoParser,
+	}
+}
+
+// Replay every record read from [reader] until it is exhausted.
+func (r *Replayer) Replay(reader *recorder.Reader) error {
+	for {
+		record, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := r.Step(record); err != nil {
+			return err
+		}
+	}
+}
+
+// Step advances the time to when [record] was recorded, passes [record] to
+// the router and waits for the chain to finish handling the messages it was
+// pushed. Returns true if [record] was passed to the router. Outbound records
+// only advance the time.
+func (r *Replayer) Step(record *recorder.Record) (bool, error) {
+	if err := r.setTime(record.Timestamp); err != nil {
+		return false, err
+	}
+
+	switch record.Type {
+	case recorder.Inbound:
+		parser := r.parser
+		if record.IsProto {
+			parser = r.protoParser
+		}
+		msg, err := parser.Parse(record.Bytes, record.NodeID, nil)
+		if err != nil {
+			return false, fmt.Errorf("failed to parse message from %s at %s: %w", record.NodeID, record.Timestamp, err)
+		}
+		r.router.HandleInbound(msg)
+	case recorder.Connected:
+		r.router.Connected(record.NodeID, record.NodeVersion, record.SubnetID)
+	case recorder.Disconnected:
+		r.router.Disconnected(record.NodeID)
+	default:
+		return false, nil
+	}
+	return true, r.handler.wait()
+}
+
+// setTime sets the time to [now] and waits for the failures of the requests
+// that timed out by [now] to be handled.
+func (r *Replayer) setTime(now time.Time) error {
+	// The handler's time must not change while it's handling messages, such
+	// as the messages pushed when the chain was added.
+	if err := r.handler.wait(); err != nil {
+		return err
+	}
+
+	r.parser.SetTime(now)
+	r.protoParser.SetTime(now)
+	r.router.SetTime(now)
+	r.handler.SetTime(now)
+	r.timeouts.SetTime(now)
+	return r.handler.wait()
+}
+
+// replayHandler tracks the messages pushed to the handler so that the replay
+// can wait for them to be handled.
+type replayHandler struct {
+	handler.Handler
+
+	lock sync.Mutex
+	// Closed once the corresponding message was handled, in the order the
+	// messages were pushed.
+	pending []chan struct{}
+}
+
+func (h *replayHandler) Push(msg message.InboundMessage) {
+	handled := make(chan struct{})
+
+	h.lock.Lock()
+	h.pending = append(h.pending, handled)
+	h.lock.Unlock()
+
+	h.Handler.Push(&replayedMessage{
+		InboundMessage: msg,
+		onHandled: func() {
+			close(handled)
+		},
+	})
+}
+
+// wait until the messages pushed to the handler were handled, including the
+// messages pushed while waiting.
+func (h *replayHandler) wait() error {
+	for {
+		h.lock.Lock()
+		if len(h.pending) == 0 {
+			h.lock.Unlock()
+			return nil
+		}
+		handled := h.pending[0]
+		h.pending = h.pending[1:]
+		h.lock.Unlock()
+
+		select {
+		case <-handled:
+		case <-h.Stopped():
+			return errHandlerStopped
+		}
+	}
+}
+
+type replayedMessage struct {
+	message.InboundMessage
+
+	onHandled func()
+}
+
+func (m *replayedMessage) OnFinishedHandling() {
+	m.InboundMessage.OnFinishedHandling()
+	m.onHandled()
+}
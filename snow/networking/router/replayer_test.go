@@ -0,0 +1,253 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package router
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/message"
+	"github.com/dim4egster/qmallgo/network/recorder"
+	"github.com/dim4egster/qmallgo/snow"
+	"github.com/dim4egster/qmallgo/snow/choices"
+	"github.com/dim4egster/qmallgo/snow/consensus/snowball"
+	"github.com/dim4egster/qmallgo/snow/consensus/snowman"
+	"github.com/dim4egster/qmallgo/snow/engine/common"
+	"github.com/dim4egster/qmallgo/snow/engine/snowman/block"
+	"github.com/dim4egster/qmallgo/snow/engine/snowman/getter"
+	"github.com/dim4egster/qmallgo/snow/networking/benchlist"
+	"github.com/dim4egster/qmallgo/snow/networking/handler"
+	"github.com/dim4egster/qmallgo/snow/networking/timeout"
+	"github.com/dim4egster/qmallgo/snow/networking/tracker"
+	"github.com/dim4egster/qmallgo/snow/validators"
+	"github.com/dim4egster/qmallgo/utils/compression"
+	"github.com/dim4egster/qmallgo/utils/constants"
+	"github.com/dim4egster/qmallgo/utils/logging"
+	"github.com/dim4egster/qmallgo/utils/math/meter"
+	"github.com/dim4egster/qmallgo/utils/resource"
+	"github.com/dim4egster/qmallgo/utils/timer"
+	"github.com/dim4egster/qmallgo/version"
+
+	smeng "github.com/dim4egster/qmallgo/snow/engine/snowman"
+)
+
+var errUnknownBlock = errors.New("unknown block")
+
+// Test that replaying a recording into a snowman engine fails the queries that
+// timed out on the replay clock and accepts the block voted for in response to
+// the following query.
+func TestReplayerSnowman(t *testing.T) {
+	require := require.New(t)
+
+	metrics := prometheus.NewRegistry()
+	mc, err := message.NewCreator(metrics, "dummyNamespace", true, 10*time.Second)
+	require.NoError(err)
+	mcProto, err := message.NewCreatorWithProto(metrics, "dummyNamespace", compression.TypeGzip, nil, 10*time.Second)
+	require.NoError(err)
+
+	tm, err := timeout.NewManager(
+		&timer.AdaptiveTimeoutConfig{
+			InitialTimeout:     10 * time.Second,
+			MinimumTimeout:     10 * time.Second,
+			MaximumTimeout:     10 * time.Second,
+			TimeoutCoefficient: 1,
+			TimeoutHalflife:    5 * time.Minute,
+		},
+		benchlist.NewNoBenchlist(),
+		tracker.NewNoHealthTracker(),
+		"",
+		prometheus.NewRegistry(),
+	)
+	require.NoError(err)
+
+	chainRouter := &ChainRouter{}
+	err = chainRouter.Initialize(ids.EmptyNodeID, logging.NoLog{}, message.NewInternalBuilder(), tm, time.Second, ids.Set{}, ids.Set{}, nil, HealthConfig{}, "", prometheus.NewRegistry())
+	require.NoError(err)
+
+	ctx := snow.DefaultConsensusContextTest()
+	vdrs := validators.NewSet()
+	vdr := ids.GenerateTestNodeID()
+	require.NoError(vdrs.AddWeight(vdr, 1))
+
+	resourceTracker, err := tracker.NewResourceTracker(prometheus.NewRegistry(), resource.NoUsage, meter.ContinuousFactory{}, time.Second)
+	require.NoError(err)
+	h, err := handler.New(
+		mc,
+		ctx,
+		vdrs,
+		nil,
+		nil,
+		time.Hour,
+		resourceTracker,
+		handler.DefaultLanesConfig,
+	)
+	require.NoError(err)
+
+	gBlk := &snowman.TestBlock{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Accepted,
+	}}
+	blk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV: gBlk.ID(),
+		HeightV: 1,
+		BytesV:  []byte{1},
+	}
+
+	vm := &block.TestVM{}
+	vm.T = t
+	vm.Default(true)
+	vm.CantSetState = false
+	vm.CantSetPreference = false
+	vm.CantConnected = false
+	vm.CantShutdown = false
+	vm.LastAcceptedF = func() (ids.ID, error) { return gBlk.ID(), nil }
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		switch blkID {
+		case gBlk.ID():
+			return gBlk, nil
+		case blk.ID():
+			return blk, nil
+		default:
+			return nil, errUnknownBlock
+		}
+	}
+	vm.ParseBlockF = func(b []byte) (snowman.Block, error) {
+		require.Equal(blk.Bytes(), b)
+		return blk, nil
+	}
+
+	// The queries are registered with the router, as the network sender
+	// would.
+	var queryRequestIDs []uint32
+	sender := &common.SenderTest{T: t}
+	sender.Default(true)
+	sender.SendChitsF = func(ids.NodeID, uint32, []ids.ID) {}
+	registerQuery := func(nodeIDs ids.NodeIDSet, requestID uint32) {
+		for nodeID := range nodeIDs {
+			chainRouter.RegisterRequest(nodeID, ctx.ChainID, requestID, message.Chits)
+		}
+		queryRequestIDs = append(queryRequestIDs, requestID)
+	}
+	sender.SendPushQueryF = func(nodeIDs ids.NodeIDSet, requestID uint32, _ []byte) {
+		registerQuery(nodeIDs, requestID)
+	}
+	sender.SendPullQueryF = func(nodeIDs ids.NodeIDSet, requestID uint32, _ ids.ID) {
+		registerQuery(nodeIDs, requestID)
+	}
+
+	commonCfg := common.DefaultConfigTest()
+	commonCfg.Ctx = ctx
+	commonCfg.Sender = sender
+	getHandler, err := getter.New(vm, commonCfg)
+	require.NoError(err)
+
+	engine, err := smeng.New(smeng.Config{
+		AllGetsServer: getHandler,
+		Ctx:           ctx,
+		VM:            vm,
+		Sender:        sender,
+		Validators:    vdrs,
+		Params: snowball.Parameters{
+			K:                       1,
+			Alpha:                   1,
+			BetaVirtuous:            1,
+			BetaRogue:               2,
+			ConcurrentRepolls:       1,
+			OptimalProcessing:       100,
+			MaxOutstandingItems:     1,
+			MaxItemProcessingTime:   1,
+			MixedQueryNumPushNonVdr: 1,
+		},
+		Consensus: &snowman.Topological{},
+	})
+	require.NoError(err)
+	h.SetConsensus(engine)
+
+	bootstrapper := &common.BootstrapperTest{
+		BootstrapableTest: common.BootstrapableTest{
+			T: t,
+		},
+		EngineTest: common.EngineTest{
+			T: t,
+		},
+	}
+	bootstrapper.Default(false)
+	bootstrapper.ContextF = func() *snow.ConsensusContext { return ctx }
+	bootstrapper.StartF = engine.Start
+	h.SetBootstrapper(bootstrapper)
+
+	replayer := NewReplayer(chainRouter, tm, h, mc, mcProto)
+
+	h.Start(false)
+	defer func() {
+		h.Stop()
+		<-h.Stopped()
+	}()
+
+	start := time.Unix(1000, 0)
+	inbound := func(timestamp time.Time, msg message.OutboundMessage) *recorder.Record {
+		return &recorder.Record{
+			Timestamp: timestamp,
+			Type:      recorder.Inbound,
+			NodeID:    vdr,
+			IsProto:   msg.IsProto(),
+			Bytes:     msg.Bytes(),
+		}
+	}
+
+	handled, err := replayer.Step(&recorder.Record{
+		Timestamp:   start,
+		Type:        recorder.Connected,
+		NodeID:      vdr,
+		NodeVersion: version.CurrentApp,
+		SubnetID:    constants.PrimaryNetworkID,
+	})
+	require.NoError(err)
+	require.True(handled)
+
+	// Issuing the pushed block queries the validator.
+	pushQuery, err := mc.PushQuery(ctx.ChainID, 0, time.Second, blk.Bytes())
+	require.NoError(err)
+	handled, err = replayer.Step(inbound(start.Add(time.Second), pushQuery))
+	require.NoError(err)
+	require.True(handled)
+	require.Len(queryRequestIDs, 1)
+
+	// The validator never responds, so the query fails once the recording
+	// reaches its deadline and the block is queried again.
+	handled, err = replayer.Step(&recorder.Record{
+		Timestamp: start.Add(time.Minute),
+		Type:      recorder.Outbound,
+		NodeID:    vdr,
+	})
+	require.NoError(err)
+	require.False(handled)
+	require.Len(queryRequestIDs, 2)
+	require.Equal(choices.Processing, blk.Status())
+
+	// A response to the failed query is dropped by the router.
+	chits, err := mcProto.Chits(ctx.ChainID, queryRequestIDs[0], []ids.ID{blk.ID()})
+	require.NoError(err)
+	handled, err = replayer.Step(inbound(start.Add(time.Minute+time.Second), chits))
+	require.NoError(err)
+	require.True(handled)
+	require.Equal(choices.Processing, blk.Status())
+
+	chits, err = mcProto.Chits(ctx.ChainID, queryRequestIDs[1], []ids.ID{blk.ID()})
+	require.NoError(err)
+	handled, err = replayer.Step(inbound(start.Add(time.Minute+2*time.Second), chits))
+	require.NoError(err)
+	require.True(handled)
+	require.Equal(choices.Accepted, blk.Status())
+}
@@ -0,0 +1,53 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package router
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/message"
+	"github.com/dim4egster/qmallgo/trace"
+
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+var _ Router = &tracedRouter{}
+
+type tracedRouter struct {
+	Router
+
+	tracer trace.Tracer
+}
+
+// Trace returns a Router that records a span for every inbound message routed
+// by [router]. The span is a child of the message's context and is passed on
+// to the chain that handles the message.
+func Trace(router Router, tracer trace.Tracer) Router {
+	return &tracedRouter{
+		Router: router,
+		tracer: tracer,
+	}
+}
+
+func (r *tracedRouter) HandleInbound(msg message.InboundMessage) {
+	attrs := []attribute.KeyValue{
+		attribute.Stringer("nodeID", msg.NodeID()),
+		attribute.Stringer("op", msg.Op()),
+	}
+	if chainIDIntf, err := msg.Get(message.ChainID); err == nil {
+		if chainID, err := ids.ToID(chainIDIntf.([]byte)); err == nil {
+			attrs = append(attrs, attribute.Stringer("chainID", chainID))
+		}
+	}
+
+	ctx, span := r.tracer.Start(
+		msg.Context(),
+		"router.HandleInbound",
+		oteltrace.WithAttributes(attrs...),
+	)
+	defer span.End()
+
+	r.Router.HandleInbound(message.InboundWithContext(ctx, msg))
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/dim4egster/qmallgo/snow/networking/timeout"
 	"github.com/dim4egster/qmallgo/snow/networking/tracker"
 	"github.com/dim4egster/qmallgo/snow/validators"
+	"github.com/dim4egster/qmallgo/utils/compression"
 	"github.com/dim4egster/qmallgo/utils/logging"
 	"github.com/dim4egster/qmallgo/utils/math/meter"
 	"github.com/dim4egster/qmallgo/utils/resource"
@@ -51,6 +52,7 @@ func TestTimeout(t *testing.T) {
 			TimeoutCoefficient: 1.25,
 		},
 		benchlist,
+		tracker.NewNoHealthTracker(),
 		"",
 		prometheus.NewRegistry(),
 	)
@@ -62,7 +64,7 @@ func TestTimeout(t *testing.T) {
 	metrics := prometheus.NewRegistry()
 	mc, err := message.NewCreator(metrics, "dummyNamespace", true, 10*time.Second)
 	require.NoError(t, err)
-	mcProto, err := message.NewCreatorWithProto(metrics, "dummyNamespace", true, 10*time.Second)
+	mcProto, err := message.NewCreatorWithProto(metrics, "dummyNamespace", compression.TypeGzip, nil, 10*time.Second)
 	require.NoError(t, err)
 
 	err = chainRouter.Initialize(ids.EmptyNodeID, logging.NoLog{}, mc, tm, time.Second, ids.Set{}, ids.Set{}, nil, router.HealthConfig{}, "", prometheus.NewRegistry())
@@ -89,6 +91,7 @@ func TestTimeout(t *testing.T) {
 		nil,
 		time.Hour,
 		resourceTracker,
+		handler.DefaultLanesConfig,
 	)
 	require.NoError(t, err)
 
@@ -144,6 +147,7 @@ func TestReliableMessages(t *testing.T) {
 			TimeoutCoefficient: 1.25,
 		},
 		benchlist,
+		tracker.NewNoHealthTracker(),
 		"",
 		prometheus.NewRegistry(),
 	)
@@ -156,7 +160,7 @@ func TestReliableMessages(t *testing.T) {
 	metrics := prometheus.NewRegistry()
 	mc, err := message.NewCreator(metrics, "dummyNamespace", true, 10*time.Second)
 	require.NoError(t, err)
-	mcProto, err := message.NewCreatorWithProto(metrics, "dummyNamespace", true, 10*time.Second)
+	mcProto, err := message.NewCreatorWithProto(metrics, "dummyNamespace", compression.TypeGzip, nil, 10*time.Second)
 	require.NoError(t, err)
 
 	err = chainRouter.Initialize(ids.EmptyNodeID, logging.NoLog{}, mc, tm, time.Second, ids.Set{}, ids.Set{}, nil, router.HealthConfig{}, "", prometheus.NewRegistry())
@@ -181,6 +185,7 @@ func TestReliableMessages(t *testing.T) {
 		nil,
 		1,
 		resourceTracker,
+		handler.DefaultLanesConfig,
 	)
 	require.NoError(t, err)
 
@@ -243,6 +248,7 @@ func TestReliableMessagesToMyself(t *testing.T) {
 			TimeoutCoefficient: 1.25,
 		},
 		benchlist,
+		tracker.NewNoHealthTracker(),
 		"",
 		prometheus.NewRegistry(),
 	)
@@ -255,7 +261,7 @@ func TestReliableMessagesToMyself(t *testing.T) {
 	metrics := prometheus.NewRegistry()
 	mc, err := message.NewCreator(metrics, "dummyNamespace", true, 10*time.Second)
 	require.NoError(t, err)
-	mcProto, err := message.NewCreatorWithProto(metrics, "dummyNamespace", true, 10*time.Second)
+	mcProto, err := message.NewCreatorWithProto(metrics, "dummyNamespace", compression.TypeGzip, nil, 10*time.Second)
 	require.NoError(t, err)
 
 	err = chainRouter.Initialize(ids.EmptyNodeID, logging.NoLog{}, mc, tm, time.Second, ids.Set{}, ids.Set{}, nil, router.HealthConfig{}, "", prometheus.NewRegistry())
@@ -280,6 +286,7 @@ func TestReliableMessagesToMyself(t *testing.T) {
 		nil,
 		time.Second,
 		resourceTracker,
+		handler.DefaultLanesConfig,
 	)
 	require.NoError(t, err)
 
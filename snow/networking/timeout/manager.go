@@ -13,6 +13,7 @@ import (
 	"github.com/dim4egster/qmallgo/message"
 	"github.com/dim4egster/qmallgo/snow"
 	"github.com/dim4egster/qmallgo/snow/networking/benchlist"
+	"github.com/dim4egster/qmallgo/snow/networking/tracker"
 	"github.com/dim4egster/qmallgo/utils/timer"
 )
 
@@ -25,6 +26,10 @@ type Manager interface {
 	Dispatch()
 	// TimeoutDuration returns the current timeout duration.
 	TimeoutDuration() time.Duration
+	// SetTime sets the time, as seen by the manager, to [now] and executes
+	// the handlers of the requests that timed out by [now]. Used to replay
+	// recorded traffic, in which case Dispatch isn't called.
+	SetTime(now time.Time)
 	// IsBenched returns true if messages to [nodeID] regarding [chainID]
 	// should not be sent over the network and should immediately fail.
 	IsBenched(nodeID ids.NodeID, chainID ids.ID) bool
@@ -52,12 +57,14 @@ type Manager interface {
 	// for the given chain. The response corresponds to the given
 	// requestID we sent them. [latency] is the time between us
 	// sending them the request and receiving their response.
+	// [numBytes] is the size of their response.
 	RegisterResponse(
 		nodeID ids.NodeID,
 		chainID ids.ID,
 		requestID ids.ID,
 		op message.Op,
 		latency time.Duration,
+		numBytes int,
 	)
 	// Mark that we no longer expect a response to this request we sent.
 	// Does not modify the timeout.
@@ -67,6 +74,7 @@ type Manager interface {
 func NewManager(
 	timeoutConfig *timer.AdaptiveTimeoutConfig,
 	benchlistMgr benchlist.Manager,
+	healthTracker tracker.HealthTracker,
 	metricsNamespace string,
 	metricsRegister prometheus.Registerer,
 ) (Manager, error) {
@@ -79,15 +87,17 @@ func NewManager(
 		return nil, fmt.Errorf("couldn't create timeout manager: %w", err)
 	}
 	return &manager{
-		benchlistMgr: benchlistMgr,
-		tm:           tm,
+		benchlistMgr:  benchlistMgr,
+		healthTracker: healthTracker,
+		tm:            tm,
 	}, nil
 }
 
 type manager struct {
-	tm           timer.AdaptiveTimeoutManager
-	benchlistMgr benchlist.Manager
-	metrics      metrics
+	tm            timer.AdaptiveTimeoutManager
+	benchlistMgr  benchlist.Manager
+	healthTracker tracker.HealthTracker
+	metrics       metrics
 }
 
 func (m *manager) Dispatch() {
@@ -98,6 +108,10 @@ func (m *manager) TimeoutDuration() time.Duration {
 	return m.tm.TimeoutDuration()
 }
 
+func (m *manager) SetTime(now time.Time) {
+	m.tm.SetTime(now)
+}
+
 // IsBenched returns true if messages to [nodeID] regarding [chainID]
 // should not be sent over the network and should immediately fail.
 func (m *manager) IsBenched(nodeID ids.NodeID, chainID ids.ID) bool {
@@ -125,8 +139,10 @@ func (m *manager) RegisterRequest(
 	timeoutHandler func(),
 ) {
 	newTimeoutHandler := func() {
-		// If this request timed out, tell the benchlist manager
+		// If this request timed out, tell the benchlist manager and the health
+		// tracker
 		m.benchlistMgr.RegisterFailure(chainID, nodeID)
+		m.healthTracker.RegisterFailure(nodeID)
 		timeoutHandler()
 	}
 	m.tm.Put(requestID, op, newTimeoutHandler)
@@ -140,9 +156,11 @@ func (m *manager) RegisterResponse(
 	requestID ids.ID,
 	op message.Op,
 	latency time.Duration,
+	numBytes int,
 ) {
 	m.metrics.Observe(nodeID, chainID, op, latency)
 	m.benchlistMgr.RegisterResponse(chainID, nodeID)
+	m.healthTracker.RegisterResponse(nodeID, latency, numBytes)
 	m.tm.Remove(requestID)
 }
 
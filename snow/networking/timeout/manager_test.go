@@ -13,6 +13,7 @@ import (
 	"github.com/dim4egster/qmallgo/ids"
 	"github.com/dim4egster/qmallgo/message"
 	"github.com/dim4egster/qmallgo/snow/networking/benchlist"
+	"github.com/dim4egster/qmallgo/snow/networking/tracker"
 	"github.com/dim4egster/qmallgo/utils/timer"
 )
 
@@ -27,6 +28,7 @@ func TestManagerFire(t *testing.T) {
 			TimeoutHalflife:    5 * time.Minute,
 		},
 		benchlist,
+		tracker.NewNoHealthTracker(),
 		"",
 		prometheus.NewRegistry(),
 	)
@@ -54,6 +56,7 @@ func TestManagerCancel(t *testing.T) {
 			TimeoutHalflife:    5 * time.Minute,
 		},
 		benchlist,
+		tracker.NewNoHealthTracker(),
 		"",
 		prometheus.NewRegistry(),
 	)
@@ -70,7 +73,7 @@ func TestManagerCancel(t *testing.T) {
 	id := ids.GenerateTestID()
 	manager.RegisterRequest(ids.NodeID{}, ids.ID{}, message.PullQuery, id, func() { *fired = true })
 
-	manager.RegisterResponse(ids.NodeID{}, ids.ID{}, id, message.Get, 1*time.Second)
+	manager.RegisterResponse(ids.NodeID{}, ids.ID{}, id, message.Get, 1*time.Second, 0)
 
 	manager.RegisterRequest(ids.NodeID{}, ids.ID{}, message.PullQuery, ids.GenerateTestID(), wg.Done)
 
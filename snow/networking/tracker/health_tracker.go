@@ -0,0 +1,230 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package tracker
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/utils/linkedhashmap"
+	"github.com/dim4egster/qmallgo/utils/timer/mockable"
+	"github.com/dim4egster/qmallgo/utils/wrappers"
+
+	safemath "github.com/dim4egster/qmallgo/utils/math"
+)
+
+// A node that hasn't been observed for [staleHalflives] halflives is
+// forgotten, and is considered healthy again.
+const staleHalflives = 10
+
+var _ HealthTracker = &healthTracker{}
+
+// HealthTracker tracks how quickly and reliably peers respond to the requests
+// we send them, and the bandwidth they respond with.
+type HealthTracker interface {
+	// RegisterResponse registers that [nodeID] responded to a request after
+	// [latency] with a response of [numBytes] bytes. Responses of 0 bytes
+	// don't count towards the bandwidth of [nodeID].
+	RegisterResponse(nodeID ids.NodeID, latency time.Duration, numBytes int)
+	// RegisterFailure registers that a request to [nodeID] failed.
+	RegisterFailure(nodeID ids.NodeID)
+	// Score returns the health of [nodeID] in [0, 1]. A node that responds to
+	// all requests at least as quickly, and with at least as much bandwidth, as
+	// the average node has a score of 1.
+	// Nodes without recent observations have a score of 1.
+	Score(nodeID ids.NodeID) float64
+}
+
+type nodeHealth struct {
+	latency     safemath.Averager
+	failureRate safemath.Averager
+	// Bandwidth (bytes/s) of the responses
+	bandwidth   safemath.Averager
+	lastUpdated time.Time
+}
+
+type healthTracker struct {
+	lock sync.Mutex
+	// Useful for faking time in tests
+	clock    mockable.Clock
+	halflife time.Duration
+	// Average latency of the responses of all nodes.
+	latency safemath.Averager
+	// Average bandwidth (bytes/s) of the responses of all nodes.
+	bandwidth safemath.Averager
+	// Each element is the [*nodeHealth] of a node, ordered by the last time
+	// the node was observed.
+	nodes   linkedhashmap.LinkedHashmap
+	metrics *healthTrackerMetrics
+}
+
+func NewHealthTracker(
+	reg prometheus.Registerer,
+	halflife time.Duration,
+) (HealthTracker, error) {
+	t := &healthTracker{
+		halflife:  halflife,
+		latency:   safemath.NewUninitializedAverager(halflife),
+		bandwidth: safemath.NewUninitializedAverager(halflife),
+		nodes:     linkedhashmap.New(),
+	}
+	var err error
+	t.metrics, err = newHealthTrackerMetrics("health_tracker", reg)
+	if err != nil {
+		return nil, fmt.Errorf("initializing healthTracker metrics errored with: %w", err)
+	}
+	return t, nil
+}
+
+func (t *healthTracker) RegisterResponse(nodeID ids.NodeID, latency time.Duration, numBytes int) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	now := t.clock.Time()
+	t.prune(now)
+
+	health := t.getNodeHealth(nodeID, now)
+	health.latency.Observe(float64(latency), now)
+	health.failureRate.Observe(0, now)
+	t.latency.Observe(float64(latency), now)
+	t.metrics.latency.Set(t.latency.Read())
+
+	if numBytes <= 0 || latency <= 0 {
+		return
+	}
+	bandwidth := float64(numBytes) / latency.Seconds()
+	health.bandwidth.Observe(bandwidth, now)
+	t.bandwidth.Observe(bandwidth, now)
+	t.metrics.bandwidth.Set(t.bandwidth.Read())
+}
+
+func (t *healthTracker) RegisterFailure(nodeID ids.NodeID) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	now := t.clock.Time()
+	t.prune(now)
+
+	health := t.getNodeHealth(nodeID, now)
+	health.failureRate.Observe(1, now)
+	t.metrics.failures.Inc()
+}
+
+func (t *healthTracker) Score(nodeID ids.NodeID) float64 {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	healthIntf, exists := t.nodes.Get(nodeID)
+	if !exists {
+		return 1
+	}
+	health := healthIntf.(*nodeHealth)
+	if t.clock.Time().Sub(health.lastUpdated) > staleHalflives*t.halflife {
+		return 1
+	}
+
+	reliability := 1 - health.failureRate.Read()
+	latencyScore := 1.0
+	if latency := health.latency.Read(); latency > 0 {
+		latencyScore = math.Min(1, t.latency.Read()/latency)
+	}
+	bandwidthScore := 1.0
+	if bandwidth := health.bandwidth.Read(); bandwidth > 0 {
+		bandwidthScore = math.Min(1, bandwidth/t.bandwidth.Read())
+	}
+	return math.Max(0, math.Min(1, reliability*latencyScore*bandwidthScore))
+}
+
+// getNodeHealth returns the health of [nodeID] and marks it as observed at
+// [now].
+// Assumes [t.lock] is held
+func (t *healthTracker) getNodeHealth(nodeID ids.NodeID, now time.Time) *nodeHealth {
+	healthIntf, exists := t.nodes.Get(nodeID)
+	var health *nodeHealth
+	if exists {
+		health = healthIntf.(*nodeHealth)
+	} else {
+		health = &nodeHealth{
+			latency:     safemath.NewUninitializedAverager(t.halflife),
+			failureRate: safemath.NewUninitializedAverager(t.halflife),
+			bandwidth:   safemath.NewUninitializedAverager(t.halflife),
+		}
+	}
+	health.lastUpdated = now
+	// Putting the node moves it to the newest position of [t.nodes].
+	t.nodes.Put(nodeID, health)
+	t.metrics.nodes.Set(float64(t.nodes.Len()))
+	return health
+}
+
+// prune removes the nodes that haven't been observed for [staleHalflives]
+// halflives.
+// Assumes [t.lock] is held
+func (t *healthTracker) prune(now time.Time) {
+	for {
+		oldest, healthIntf, exists := t.nodes.Oldest()
+		if !exists {
+			break
+		}
+		if now.Sub(healthIntf.(*nodeHealth).lastUpdated) <= staleHalflives*t.halflife {
+			break
+		}
+		t.nodes.Delete(oldest)
+	}
+	t.metrics.nodes.Set(float64(t.nodes.Len()))
+}
+
+type healthTrackerMetrics struct {
+	nodes     prometheus.Gauge
+	latency   prometheus.Gauge
+	bandwidth prometheus.Gauge
+	failures  prometheus.Counter
+}
+
+func newHealthTrackerMetrics(namespace string, reg prometheus.Registerer) (*healthTrackerMetrics, error) {
+	m := &healthTrackerMetrics{
+		nodes: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "nodes",
+			Help:      "Number of nodes whose health is tracked",
+		}),
+		latency: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "latency",
+			Help:      "Average latency (ns) of the responses of all nodes",
+		}),
+		bandwidth: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "bandwidth",
+			Help:      "Average bandwidth (bytes/s) of the responses of all nodes",
+		}),
+		failures: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "failures",
+			Help:      "Number of requests to nodes that failed",
+		}),
+	}
+	errs := wrappers.Errs{}
+	errs.Add(
+		reg.Register(m.nodes),
+		reg.Register(m.latency),
+		reg.Register(m.bandwidth),
+		reg.Register(m.failures),
+	)
+	return m, errs.Err
+}
+
+type noHealthTracker struct{}
+
+// NewNoHealthTracker returns a HealthTracker that considers all nodes healthy.
+func NewNoHealthTracker() HealthTracker { return noHealthTracker{} }
+
+func (noHealthTracker) RegisterResponse(ids.NodeID, time.Duration, int) {}
+func (noHealthTracker) RegisterFailure(ids.NodeID)                      {}
+func (noHealthTracker) Score(ids.NodeID) float64                        { return 1 }
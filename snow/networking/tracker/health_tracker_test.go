@@ -0,0 +1,79 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package tracker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dim4egster/qmallgo/ids"
+)
+
+func TestHealthTracker(t *testing.T) {
+	require := require.New(t)
+
+	halflife := time.Minute
+	trackerIntf, err := NewHealthTracker(prometheus.NewRegistry(), halflife)
+	require.NoError(err)
+	tracker := trackerIntf.(*healthTracker)
+	now := time.Now()
+	tracker.clock.Set(now)
+
+	fastNodeID := ids.GenerateTestNodeID()
+	slowNodeID := ids.GenerateTestNodeID()
+	unreliableNodeID := ids.GenerateTestNodeID()
+
+	// Unknown nodes are healthy.
+	require.Equal(1.0, tracker.Score(fastNodeID))
+
+	// The average latency is 200ms.
+	tracker.RegisterResponse(fastNodeID, 100*time.Millisecond, 0)
+	tracker.RegisterResponse(slowNodeID, 300*time.Millisecond, 0)
+	tracker.RegisterResponse(unreliableNodeID, 200*time.Millisecond, 0)
+	tracker.RegisterFailure(unreliableNodeID)
+
+	require.Equal(1.0, tracker.Score(fastNodeID))
+	require.InDelta(2.0/3.0, tracker.Score(slowNodeID), 1e-9)
+	require.InDelta(0.5, tracker.Score(unreliableNodeID), 1e-9)
+	require.Equal(3, tracker.nodes.Len())
+
+	// Nodes that haven't been observed recently are forgotten.
+	tracker.clock.Set(now.Add(staleHalflives*halflife + time.Second))
+	require.Equal(1.0, tracker.Score(slowNodeID))
+	require.Equal(1.0, tracker.Score(unreliableNodeID))
+
+	tracker.RegisterFailure(fastNodeID)
+	require.Equal(1, tracker.nodes.Len())
+	require.Zero(tracker.Score(fastNodeID))
+}
+
+func TestHealthTrackerBandwidth(t *testing.T) {
+	require := require.New(t)
+
+	trackerIntf, err := NewHealthTracker(prometheus.NewRegistry(), time.Minute)
+	require.NoError(err)
+	tracker := trackerIntf.(*healthTracker)
+	tracker.clock.Set(time.Now())
+
+	fastNodeID := ids.GenerateTestNodeID()
+	slowNodeID := ids.GenerateTestNodeID()
+	emptyNodeID := ids.GenerateTestNodeID()
+
+	// Both nodes respond as quickly, but the slow node responds with a
+	// quarter of the bytes. The average bandwidth is 5000 bytes/s.
+	tracker.RegisterResponse(fastNodeID, 100*time.Millisecond, 800)
+	tracker.RegisterResponse(slowNodeID, 100*time.Millisecond, 200)
+
+	require.Equal(1.0, tracker.Score(fastNodeID))
+	require.InDelta(0.4, tracker.Score(slowNodeID), 1e-9)
+
+	// Empty responses don't count towards the bandwidth.
+	tracker.RegisterResponse(emptyNodeID, 100*time.Millisecond, 0)
+	require.Equal(1.0, tracker.Score(emptyNodeID))
+	require.InDelta(5000, tracker.bandwidth.Read(), 1e-9)
+}
@@ -0,0 +1,168 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/utils/sampler"
+	"github.com/dim4egster/qmallgo/utils/timer/mockable"
+	"github.com/dim4egster/qmallgo/utils/wrappers"
+)
+
+// The health scores used to weight the validators are refreshed at most once
+// per this period, unless the validator set changes.
+const scoreRefreshPeriod = time.Second
+
+var (
+	errInvalidMinScore = errors.New("min score must be in (0, 1]")
+
+	_ Sampler = &set{}
+	_ Sampler = &adaptiveSampler{}
+)
+
+// Sampler samples validators to query.
+type Sampler interface {
+	// Sample returns a collection of validators, potentially with duplicates.
+	// If sampling the requested size isn't possible, an error will be returned.
+	Sample(size int) ([]Validator, error)
+}
+
+// HealthScorer scores how healthy nodes are.
+type HealthScorer interface {
+	// Score returns the health of [nodeID] in [0, 1].
+	Score(nodeID ids.NodeID) float64
+}
+
+type adaptiveSampler struct {
+	// Useful for faking time in tests
+	clock mockable.Clock
+
+	vdrs     Set
+	scorer   HealthScorer
+	minScore float64
+	sampler  sampler.WeightedWithoutReplacement
+
+	// The validators that [sampler] was initialized with, their masked
+	// weights, the version of [vdrs] they were read at and when their scores
+	// were read. The validators are only read again from [vdrs] once its
+	// version changes.
+	initialized   bool
+	sampledVdrs   []Validator
+	maskedWeights []uint64
+	version       uint64
+	lastRefresh   time.Time
+
+	samples              prometheus.Counter
+	effectiveWeightRatio prometheus.Gauge
+}
+
+// NewAdaptiveSampler returns a Sampler that samples [vdrs] by their stake
+// scaled by their health, as reported by [scorer]. Masked validators aren't
+// sampled. Scores are clamped to [minScore, 1], so every validator keeps at
+// least [minScore] of its stake.
+// As a result, validators that hold a portion p of the stake are sampled with
+// probability at most p / (p + minScore * (1 - p)), regardless of the health
+// of the other validators.
+func NewAdaptiveSampler(
+	vdrs Set,
+	scorer HealthScorer,
+	minScore float64,
+	namespace string,
+	reg prometheus.Registerer,
+) (Sampler, error) {
+	if minScore <= 0 || minScore > 1 {
+		return nil, fmt.Errorf("%w: %f", errInvalidMinScore, minScore)
+	}
+	s := &adaptiveSampler{
+		vdrs:     vdrs,
+		scorer:   scorer,
+		minScore: minScore,
+		sampler:  sampler.NewWeightedWithoutReplacement(),
+		samples: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "adaptive_samples",
+			Help:      "Number of validator samples weighted by validator health",
+		}),
+		effectiveWeightRatio: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "adaptive_sampling_effective_weight_ratio",
+			Help:      "Portion of the stake used as sampling weight after scaling by validator health in the last sample",
+		}),
+	}
+	errs := wrappers.Errs{}
+	errs.Add(
+		reg.Register(s.samples),
+		reg.Register(s.effectiveWeightRatio),
+	)
+	return s, errs.Err
+}
+
+func (s *adaptiveSampler) Sample(size int) ([]Validator, error) {
+	if size == 0 {
+		return nil, nil
+	}
+
+	if err := s.refresh(); err != nil {
+		return nil, err
+	}
+	indices, err := s.sampler.Sample(size)
+	if err != nil {
+		return nil, err
+	}
+
+	s.samples.Inc()
+	list := make([]Validator, size)
+	for i, index := range indices {
+		list[i] = s.sampledVdrs[index]
+	}
+	return list, nil
+}
+
+// refresh initializes [s.sampler] with the current scores of the validators if
+// the validator set changed or the scores are older than [scoreRefreshPeriod].
+func (s *adaptiveSampler) refresh() error {
+	version := s.vdrs.Version()
+	now := s.clock.Time()
+	vdrsChanged := !s.initialized || version != s.version
+	if !vdrsChanged && now.Sub(s.lastRefresh) < scoreRefreshPeriod {
+		return nil
+	}
+	if vdrsChanged {
+		s.sampledVdrs, s.maskedWeights, s.version = s.vdrs.MaskedWeights()
+	}
+
+	weights := make([]uint64, len(s.maskedWeights))
+	totalWeight := float64(0)
+	effectiveWeight := float64(0)
+	for i, vdr := range s.sampledVdrs {
+		weight := s.maskedWeights[i]
+		score := math.Max(s.minScore, math.Min(1, s.scorer.Score(vdr.ID())))
+		// Scaling down the weight can't overflow. Every validator with stake
+		// keeps a non-zero weight.
+		weights[i] = uint64(float64(weight) * score)
+		if weights[i] == 0 && weight != 0 {
+			weights[i] = 1
+		}
+		totalWeight += float64(weight)
+		effectiveWeight += float64(weights[i])
+	}
+
+	if err := s.sampler.Initialize(weights); err != nil {
+		s.initialized = false
+		return err
+	}
+	s.initialized = true
+	s.lastRefresh = now
+	if totalWeight != 0 {
+		s.effectiveWeightRatio.Set(effectiveWeight / totalWeight)
+	}
+	return nil
+}
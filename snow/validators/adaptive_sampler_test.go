@@ -0,0 +1,147 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dim4egster/qmallgo/ids"
+)
+
+type testScorer map[ids.NodeID]float64
+
+func (s testScorer) Score(nodeID ids.NodeID) float64 {
+	score, ok := s[nodeID]
+	if !ok {
+		return 1
+	}
+	return score
+}
+
+// countingSet counts how many times the validators are copied out of the set.
+type countingSet struct {
+	*set
+	maskedWeightsCalls int
+}
+
+func (s *countingSet) MaskedWeights() ([]Validator, []uint64, uint64) {
+	s.maskedWeightsCalls++
+	return s.set.MaskedWeights()
+}
+
+func TestAdaptiveSampler(t *testing.T) {
+	require := require.New(t)
+
+	healthyID := ids.GenerateTestNodeID()
+	slowID := ids.GenerateTestNodeID()
+	unreachableID := ids.GenerateTestNodeID()
+	vdrs := NewSet()
+	require.NoError(vdrs.AddWeight(healthyID, 100))
+	require.NoError(vdrs.AddWeight(slowID, 100))
+	require.NoError(vdrs.AddWeight(unreachableID, 100))
+
+	scorer := testScorer{
+		slowID:        0.75,
+		unreachableID: 0,
+	}
+	s, err := NewAdaptiveSampler(vdrs, scorer, 0.5, "", prometheus.NewRegistry())
+	require.NoError(err)
+
+	// The weights are 100, 75 and 50. Sampling all of the weight without
+	// replacement samples each validator in proportion to its weight.
+	sampled, err := s.Sample(225)
+	require.NoError(err)
+	counts := make(map[ids.NodeID]int)
+	for _, vdr := range sampled {
+		counts[vdr.ID()]++
+	}
+	require.Equal(map[ids.NodeID]int{
+		healthyID:     100,
+		slowID:        75,
+		unreachableID: 50,
+	}, counts)
+
+	_, err = s.Sample(226)
+	require.Error(err)
+
+	sampled, err = s.Sample(0)
+	require.NoError(err)
+	require.Empty(sampled)
+}
+
+func TestAdaptiveSamplerMasksValidators(t *testing.T) {
+	require := require.New(t)
+
+	vdrID0 := ids.GenerateTestNodeID()
+	vdrID1 := ids.GenerateTestNodeID()
+	vdrs := NewSet()
+	require.NoError(vdrs.AddWeight(vdrID0, 1))
+	require.NoError(vdrs.AddWeight(vdrID1, 1))
+
+	s, err := NewAdaptiveSampler(vdrs, testScorer{}, 0.5, "", prometheus.NewRegistry())
+	require.NoError(err)
+
+	require.NoError(vdrs.MaskValidator(vdrID0))
+	sampled, err := s.Sample(1)
+	require.NoError(err)
+	require.Equal(vdrID1, sampled[0].ID())
+	_, err = s.Sample(2)
+	require.Error(err)
+
+	require.NoError(vdrs.RevealValidator(vdrID0))
+	_, err = s.Sample(2)
+	require.NoError(err)
+}
+
+func TestAdaptiveSamplerCachesScores(t *testing.T) {
+	require := require.New(t)
+
+	vdrID0 := ids.GenerateTestNodeID()
+	vdrID1 := ids.GenerateTestNodeID()
+	vdrs := &countingSet{set: NewSet().(*set)}
+	require.NoError(vdrs.AddWeight(vdrID0, 100))
+	require.NoError(vdrs.AddWeight(vdrID1, 100))
+
+	scorer := testScorer{}
+	sIntf, err := NewAdaptiveSampler(vdrs, scorer, 0.5, "", prometheus.NewRegistry())
+	require.NoError(err)
+	s := sIntf.(*adaptiveSampler)
+	now := time.Now()
+	s.clock.Set(now)
+
+	_, err = s.Sample(200)
+	require.NoError(err)
+
+	// The scores aren't read again until the refresh period passes.
+	scorer[vdrID0] = 0.5
+	_, err = s.Sample(200)
+	require.NoError(err)
+
+	s.clock.Set(now.Add(scoreRefreshPeriod))
+	_, err = s.Sample(200)
+	require.Error(err)
+	_, err = s.Sample(150)
+	require.NoError(err)
+
+	// The validators are only copied out of the set once, as it didn't change.
+	require.Equal(1, vdrs.maskedWeightsCalls)
+
+	// Changes to the validator set are picked up immediately.
+	require.NoError(vdrs.AddWeight(vdrID1, 50))
+	_, err = s.Sample(200)
+	require.NoError(err)
+	require.Equal(2, vdrs.maskedWeightsCalls)
+}
+
+func TestAdaptiveSamplerInvalidMinScore(t *testing.T) {
+	for _, minScore := range []float64{0, -1, 1.5} {
+		_, err := NewAdaptiveSampler(NewSet(), testScorer{}, minScore, "", prometheus.NewRegistry())
+		require.ErrorIs(t, err, errInvalidMinScore)
+	}
+}
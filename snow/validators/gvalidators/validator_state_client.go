@@ -0,0 +1,75 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gvalidators
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/snow/validators"
+	"github.com/dim4egster/qmallgo/utils/crypto/bls"
+
+	validatorstatepb "github.com/dim4egster/qmallgo/proto/pb/validatorstate"
+)
+
+var _ validators.State = &Client{}
+
+// Client is a validator state that talks over RPC.
+type Client struct {
+	client validatorstatepb.ValidatorStateClient
+}
+
+// NewClient returns a validator state connected to a remote validator state
+func NewClient(client validatorstatepb.ValidatorStateClient) *Client {
+	return &Client{client: client}
+}
+
+func (c *Client) GetMinimumHeight() (uint64, error) {
+	resp, err := c.client.GetMinimumHeight(context.Background(), &emptypb.Empty{})
+	if err != nil {
+		return 0, err
+	}
+	return resp.Height, nil
+}
+
+func (c *Client) GetCurrentHeight() (uint64, error) {
+	resp, err := c.client.GetCurrentHeight(context.Background(), &emptypb.Empty{})
+	if err != nil {
+		return 0, err
+	}
+	return resp.Height, nil
+}
+
+func (c *Client) GetValidatorSet(height uint64, subnetID ids.ID) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+	resp, err := c.client.GetValidatorSet(context.Background(), &validatorstatepb.GetValidatorSetRequest{
+		Height:   height,
+		SubnetId: subnetID[:],
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	vdrs := make(map[ids.NodeID]*validators.GetValidatorOutput, len(resp.Validators))
+	for _, validator := range resp.Validators {
+		nodeID, err := ids.ToNodeID(validator.NodeId)
+		if err != nil {
+			return nil, err
+		}
+		var publicKey *bls.PublicKey
+		if len(validator.PublicKey) > 0 {
+			publicKey, err = bls.PublicKeyFromBytes(validator.PublicKey)
+			if err != nil {
+				return nil, err
+			}
+		}
+		vdrs[nodeID] = &validators.GetValidatorOutput{
+			NodeID:    nodeID,
+			PublicKey: publicKey,
+			Weight:    validator.Weight,
+		}
+	}
+	return vdrs, nil
+}
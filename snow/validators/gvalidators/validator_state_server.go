@@ -0,0 +1,67 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gvalidators
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/snow/validators"
+	"github.com/dim4egster/qmallgo/utils/crypto/bls"
+
+	validatorstatepb "github.com/dim4egster/qmallgo/proto/pb/validatorstate"
+)
+
+var _ validatorstatepb.ValidatorStateServer = &Server{}
+
+// Server is a validator state that is managed over RPC.
+type Server struct {
+	validatorstatepb.UnsafeValidatorStateServer
+	state validators.State
+}
+
+// NewServer returns a validator state server connected to [state]
+func NewServer(state validators.State) *Server {
+	return &Server{state: state}
+}
+
+func (s *Server) GetMinimumHeight(context.Context, *emptypb.Empty) (*validatorstatepb.GetMinimumHeightResponse, error) {
+	height, err := s.state.GetMinimumHeight()
+	return &validatorstatepb.GetMinimumHeightResponse{Height: height}, err
+}
+
+func (s *Server) GetCurrentHeight(context.Context, *emptypb.Empty) (*validatorstatepb.GetCurrentHeightResponse, error) {
+	height, err := s.state.GetCurrentHeight()
+	return &validatorstatepb.GetCurrentHeightResponse{Height: height}, err
+}
+
+func (s *Server) GetValidatorSet(_ context.Context, req *validatorstatepb.GetValidatorSetRequest) (*validatorstatepb.GetValidatorSetResponse, error) {
+	subnetID, err := ids.ToID(req.SubnetId)
+	if err != nil {
+		return nil, err
+	}
+
+	vdrs, err := s.state.GetValidatorSet(req.Height, subnetID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &validatorstatepb.GetValidatorSetResponse{
+		Validators: make([]*validatorstatepb.Validator, 0, len(vdrs)),
+	}
+	for nodeID, vdr := range vdrs {
+		nodeID := nodeID
+		validator := &validatorstatepb.Validator{
+			NodeId: nodeID[:],
+			Weight: vdr.Weight,
+		}
+		if vdr.PublicKey != nil {
+			validator.PublicKey = bls.PublicKeyToBytes(vdr.PublicKey)
+		}
+		resp.Validators = append(resp.Validators, validator)
+	}
+	return resp, nil
+}
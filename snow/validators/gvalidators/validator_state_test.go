@@ -0,0 +1,122 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gvalidators
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/snow/validators"
+	"github.com/dim4egster/qmallgo/utils/crypto/bls"
+	"github.com/dim4egster/qmallgo/vms/rpcchainvm/grpcutils"
+
+	validatorstatepb "github.com/dim4egster/qmallgo/proto/pb/validatorstate"
+)
+
+const bufSize = 1024 * 1024
+
+var errCustom = errors.New("custom")
+
+func setupState(t *testing.T, state validators.State) *Client {
+	listener := bufconn.Listen(bufSize)
+	serverCloser := grpcutils.ServerCloser{}
+
+	serverFunc := func(opts []grpc.ServerOption) *grpc.Server {
+		server := grpc.NewServer(opts...)
+		validatorstatepb.RegisterValidatorStateServer(server, NewServer(state))
+		serverCloser.Add(server)
+		return server
+	}
+
+	go grpcutils.Serve(listener, serverFunc)
+
+	dialer := grpc.WithContextDialer(
+		func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	)
+
+	dopts := grpcutils.DefaultDialOptions
+	dopts = append(dopts, dialer)
+	conn, err := grpcutils.Dial("", dopts...)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		serverCloser.Stop()
+		_ = conn.Close()
+		_ = listener.Close()
+	})
+	return NewClient(validatorstatepb.NewValidatorStateClient(conn))
+}
+
+func TestHeights(t *testing.T) {
+	require := require.New(t)
+
+	state := &validators.TestState{
+		T: t,
+		GetMinimumHeightF: func() (uint64, error) {
+			return 5, nil
+		},
+		GetCurrentHeightF: func() (uint64, error) {
+			return 0, errCustom
+		},
+	}
+	client := setupState(t, state)
+
+	height, err := client.GetMinimumHeight()
+	require.NoError(err)
+	require.EqualValues(5, height)
+
+	_, err = client.GetCurrentHeight()
+	require.Error(err)
+}
+
+func TestGetValidatorSet(t *testing.T) {
+	require := require.New(t)
+
+	sk, err := bls.NewSecretKey()
+	require.NoError(err)
+
+	expectedSubnetID := ids.GenerateTestID()
+	vdr0 := &validators.GetValidatorOutput{
+		NodeID:    ids.GenerateTestNodeID(),
+		PublicKey: bls.PublicFromSecretKey(sk),
+		Weight:    1,
+	}
+	vdr1 := &validators.GetValidatorOutput{
+		NodeID: ids.GenerateTestNodeID(),
+		Weight: 2,
+	}
+	state := &validators.TestState{
+		T: t,
+		GetValidatorSetF: func(height uint64, subnetID ids.ID) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+			if height != 10 || subnetID != expectedSubnetID {
+				return nil, errCustom
+			}
+			return map[ids.NodeID]*validators.GetValidatorOutput{
+				vdr0.NodeID: vdr0,
+				vdr1.NodeID: vdr1,
+			}, nil
+		},
+	}
+	client := setupState(t, state)
+
+	vdrs, err := client.GetValidatorSet(10, expectedSubnetID)
+	require.NoError(err)
+	require.Len(vdrs, 2)
+	require.Equal(vdr0.Weight, vdrs[vdr0.NodeID].Weight)
+	require.Equal(bls.PublicKeyToBytes(vdr0.PublicKey), bls.PublicKeyToBytes(vdrs[vdr0.NodeID].PublicKey))
+	require.Equal(vdr1, vdrs[vdr1.NodeID])
+
+	_, err = client.GetValidatorSet(11, expectedSubnetID)
+	require.Error(err)
+}
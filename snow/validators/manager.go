@@ -9,6 +9,7 @@ import (
 	"sync"
 
 	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/utils/crypto/bls"
 )
 
 var _ Manager = &manager{}
@@ -20,6 +21,11 @@ type Manager interface {
 	// Set a subnet's validator set
 	Set(ids.ID, Set) error
 
+	// Add adds weight to a given validator on the given subnet and records its
+	// BLS public key. If the public key is nil, the validator's current public
+	// key is kept.
+	Add(ids.ID, ids.NodeID, *bls.PublicKey, uint64) error
+
 	// AddWeight adds weight to a given validator on the given subnet
 	AddWeight(ids.ID, ids.NodeID, uint64) error
 
@@ -71,23 +77,46 @@ func (m *manager) Set(subnetID ids.ID, newSet Set) error {
 	return oldSet.Set(newSet.List())
 }
 
+func (m *manager) Add(subnetID ids.ID, vdrID ids.NodeID, publicKey *bls.PublicKey, weight uint64) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	vdrs, err := m.getOrCreateSet(subnetID)
+	if err != nil {
+		return err
+	}
+	return vdrs.Add(vdrID, publicKey, weight)
+}
+
 func (m *manager) AddWeight(subnetID ids.ID, vdrID ids.NodeID, weight uint64) error {
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
-	vdrs, ok := m.subnetToVdrs[subnetID]
-	if !ok {
-		vdrs = NewSet()
-		for _, maskedVdrID := range m.maskedVdrs.List() {
-			if err := vdrs.MaskValidator(maskedVdrID); err != nil {
-				return err
-			}
-		}
-		m.subnetToVdrs[subnetID] = vdrs
+	vdrs, err := m.getOrCreateSet(subnetID)
+	if err != nil {
+		return err
 	}
 	return vdrs.AddWeight(vdrID, weight)
 }
 
+// getOrCreateSet returns the validator set of [subnetID], creating it if it
+// doesn't exist.
+// Assumes [m.lock] is held
+func (m *manager) getOrCreateSet(subnetID ids.ID) (Set, error) {
+	if vdrs, ok := m.subnetToVdrs[subnetID]; ok {
+		return vdrs, nil
+	}
+
+	vdrs := NewSet()
+	for _, maskedVdrID := range m.maskedVdrs.List() {
+		if err := vdrs.MaskValidator(maskedVdrID); err != nil {
+			return nil, err
+		}
+	}
+	m.subnetToVdrs[subnetID] = vdrs
+	return vdrs, nil
+}
+
 func (m *manager) RemoveWeight(subnetID ids.ID, vdrID ids.NodeID, weight uint64) error {
 	m.lock.Lock()
 	defer m.lock.Unlock()
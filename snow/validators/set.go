@@ -9,6 +9,7 @@ import (
 	"sync"
 
 	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/utils/crypto/bls"
 	"github.com/dim4egster/qmallgo/utils/formatting"
 	"github.com/dim4egster/qmallgo/utils/sampler"
 
@@ -37,9 +38,16 @@ type Set interface {
 	// validators to the set.
 	Set([]Validator) error
 
+	// Add weight to a staker and record its BLS public key. If the public key
+	// is nil, the staker's current public key is kept.
+	Add(ids.NodeID, *bls.PublicKey, uint64) error
+
 	// AddWeight to a staker.
 	AddWeight(ids.NodeID, uint64) error
 
+	// Get returns the validator with the specified ID, if it is in the set.
+	Get(ids.NodeID) (Validator, bool)
+
 	// GetWeight retrieves the validator weight from the set.
 	GetWeight(ids.NodeID) (uint64, bool)
 
@@ -74,6 +82,14 @@ type Set interface {
 	RegisterCallbackListener(SetCallbackListener)
 
 	RevealValidator(ids.NodeID) error
+
+	// Version returns a number that changes whenever the validators, their
+	// weights or their masking change.
+	Version() uint64
+
+	// MaskedWeights returns copies of the validators and of their weights,
+	// which are 0 for masked validators, along with the version of the set.
+	MaskedWeights() ([]Validator, []uint64, uint64)
 }
 
 type SetCallbackListener interface {
@@ -112,6 +128,8 @@ type set struct {
 	totalWeight       uint64
 	maskedVdrs        ids.NodeIDSet
 	callbackListeners []SetCallbackListener
+	// Incremented whenever a validator or its masked weight changes
+	version uint64
 }
 
 func (s *set) Set(vdrs []Validator) error {
@@ -170,6 +188,7 @@ func (s *set) set(vdrs []Validator) error {
 	s.vdrMap = make(map[ids.NodeID]int, lenVdrs)
 	s.totalWeight = 0
 	s.initialized = false
+	s.version++
 
 	for _, vdr := range vdrs {
 		vdrID := vdr.ID()
@@ -184,8 +203,9 @@ func (s *set) set(vdrs []Validator) error {
 		i := len(s.vdrSlice)
 		s.vdrMap[vdrID] = i
 		s.vdrSlice = append(s.vdrSlice, &validator{
-			nodeID: vdr.ID(),
-			weight: vdr.Weight(),
+			nodeID:    vdr.ID(),
+			publicKey: vdr.PublicKey(),
+			weight:    vdr.Weight(),
 		})
 		s.vdrWeights = append(s.vdrWeights, w)
 		s.vdrMaskedWeights = append(s.vdrMaskedWeights, 0)
@@ -204,6 +224,16 @@ func (s *set) set(vdrs []Validator) error {
 	return nil
 }
 
+func (s *set) Add(vdrID ids.NodeID, publicKey *bls.PublicKey, weight uint64) error {
+	if weight == 0 {
+		return nil // This validator would never be sampled anyway
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.addWeight(vdrID, publicKey, weight)
+}
+
 func (s *set) AddWeight(vdrID ids.NodeID, weight uint64) error {
 	if weight == 0 {
 		return nil // This validator would never be sampled anyway
@@ -211,10 +241,10 @@ func (s *set) AddWeight(vdrID ids.NodeID, weight uint64) error {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
-	return s.addWeight(vdrID, weight)
+	return s.addWeight(vdrID, nil, weight)
 }
 
-func (s *set) addWeight(vdrID ids.NodeID, weight uint64) error {
+func (s *set) addWeight(vdrID ids.NodeID, publicKey *bls.PublicKey, weight uint64) error {
 	var vdr *validator
 	i, nodeExists := s.vdrMap[vdrID]
 	if !nodeExists {
@@ -230,6 +260,9 @@ func (s *set) addWeight(vdrID ids.NodeID, weight uint64) error {
 	} else {
 		vdr = s.vdrSlice[i]
 	}
+	if publicKey != nil {
+		vdr.publicKey = publicKey
+	}
 
 	oldWeight := s.vdrWeights[i]
 	s.vdrWeights[i] += weight
@@ -250,6 +283,7 @@ func (s *set) addWeight(vdrID ids.NodeID, weight uint64) error {
 	}
 	s.totalWeight = newTotalWeight
 	s.initialized = false
+	s.version++
 	return nil
 }
 
@@ -323,6 +357,7 @@ func (s *set) removeWeight(vdrID ids.NodeID, weight uint64) error {
 		s.callWeightChangeCallbacks(vdrID, oldWeight, vdr.weight)
 	}
 	s.initialized = false
+	s.version++
 	return nil
 }
 
@@ -373,6 +408,7 @@ func (s *set) remove(vdrID ids.NodeID) error {
 		s.totalWeight = newTotalWeight
 	}
 	s.initialized = false
+	s.version++
 	return nil
 }
 
@@ -441,6 +477,26 @@ func (s *set) sample(size int) ([]Validator, error) {
 	return list, nil
 }
 
+func (s *set) Version() uint64 {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.version
+}
+
+func (s *set) MaskedWeights() ([]Validator, []uint64, uint64) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	vdrs := make([]Validator, len(s.vdrSlice))
+	for i, vdr := range s.vdrSlice {
+		vdrs[i] = vdr
+	}
+	weights := make([]uint64, len(s.vdrMaskedWeights))
+	copy(weights, s.vdrMaskedWeights)
+	return vdrs, weights, s.version
+}
+
 func (s *set) Weight() uint64 {
 	s.lock.RLock()
 	defer s.lock.RUnlock()
@@ -507,6 +563,7 @@ func (s *set) maskValidator(vdrID ids.NodeID) error {
 	s.vdrMaskedWeights[i] = 0
 	s.totalWeight -= s.vdrWeights[i]
 	s.initialized = false
+	s.version++
 
 	return nil
 }
@@ -539,6 +596,7 @@ func (s *set) revealValidator(vdrID ids.NodeID) error {
 	}
 	s.totalWeight = newTotalWeight
 	s.initialized = false
+	s.version++
 
 	return nil
 }
@@ -10,13 +10,14 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/utils/crypto/bls"
 )
 
 func TestSetSet(t *testing.T) {
-	vdr0 := NewValidator(ids.EmptyNodeID, 1)
-	vdr1 := NewValidator(ids.NodeID{0xFF}, math.MaxInt64-1)
+	vdr0 := NewValidator(ids.EmptyNodeID, nil, 1)
+	vdr1 := NewValidator(ids.NodeID{0xFF}, nil, math.MaxInt64-1)
 	// Should be discarded, because it has a weight of 0
-	vdr2 := NewValidator(ids.NodeID{0xAA}, 0)
+	vdr2 := NewValidator(ids.NodeID{0xAA}, nil, 0)
 
 	s := NewSet()
 	err := s.Set([]Validator{vdr0, vdr1, vdr2})
@@ -37,6 +38,41 @@ func TestSetSet(t *testing.T) {
 	require.Equal(t, vdr1.ID(), sampled[0].ID(), "should have sampled vdr1")
 }
 
+func TestSetAddPublicKey(t *testing.T) {
+	require := require.New(t)
+
+	sk, err := bls.NewSecretKey()
+	require.NoError(err)
+	pk := bls.PublicFromSecretKey(sk)
+
+	nodeID := ids.GenerateTestNodeID()
+	s := NewSet()
+	require.NoError(s.AddWeight(nodeID, 1))
+	vdr, ok := s.Get(nodeID)
+	require.True(ok)
+	require.Nil(vdr.PublicKey())
+
+	// Adding weight with a public key records it.
+	require.NoError(s.Add(nodeID, pk, 2))
+	vdr, ok = s.Get(nodeID)
+	require.True(ok)
+	require.Equal(pk, vdr.PublicKey())
+	require.EqualValues(3, vdr.Weight())
+
+	// Adding weight without a public key keeps the current one.
+	require.NoError(s.AddWeight(nodeID, 1))
+	vdr, ok = s.Get(nodeID)
+	require.True(ok)
+	require.Equal(pk, vdr.PublicKey())
+
+	// The public key is kept when the set is reset.
+	s2 := NewSet()
+	require.NoError(s2.Set(s.List()))
+	vdr, ok = s2.Get(nodeID)
+	require.True(ok)
+	require.Equal(pk, vdr.PublicKey())
+}
+
 func TestSamplerSample(t *testing.T) {
 	vdr0 := ids.GenerateTestNodeID()
 	vdr1 := ids.GenerateTestNodeID()
@@ -7,6 +7,7 @@ import (
 	"sync"
 
 	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/utils/crypto/bls"
 )
 
 var _ State = &lockedState{}
@@ -20,10 +21,19 @@ type State interface {
 	// GetCurrentHeight returns the current height of the P-chain.
 	GetCurrentHeight() (uint64, error)
 
-	// GetValidatorSet returns the weights of the nodeIDs for the provided
-	// subnet at the requested P-chain height.
+	// GetValidatorSet returns the validators of the provided subnet at the
+	// requested P-chain height.
 	// The returned map should not be modified.
-	GetValidatorSet(height uint64, subnetID ids.ID) (map[ids.NodeID]uint64, error)
+	GetValidatorSet(height uint64, subnetID ids.ID) (map[ids.NodeID]*GetValidatorOutput, error)
+}
+
+// GetValidatorOutput is a validator of a subnet at a P-chain height.
+type GetValidatorOutput struct {
+	NodeID ids.NodeID
+	// PublicKey is the BLS public key of the validator, or nil if it didn't
+	// register one.
+	PublicKey *bls.PublicKey
+	Weight    uint64
 }
 
 type lockedState struct {
@@ -52,7 +62,7 @@ func (s *lockedState) GetCurrentHeight() (uint64, error) {
 	return s.s.GetCurrentHeight()
 }
 
-func (s *lockedState) GetValidatorSet(height uint64, subnetID ids.ID) (map[ids.NodeID]uint64, error) {
+func (s *lockedState) GetValidatorSet(height uint64, subnetID ids.ID) (map[ids.NodeID]*GetValidatorOutput, error) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
@@ -69,6 +79,6 @@ func NewNoValidatorsState(state State) State {
 	}
 }
 
-func (*noValidators) GetValidatorSet(uint64, ids.ID) (map[ids.NodeID]uint64, error) {
+func (*noValidators) GetValidatorSet(uint64, ids.ID) (map[ids.NodeID]*GetValidatorOutput, error) {
 	return nil, nil
 }
@@ -27,7 +27,7 @@ type TestState struct {
 
 	GetMinimumHeightF func() (uint64, error)
 	GetCurrentHeightF func() (uint64, error)
-	GetValidatorSetF  func(height uint64, subnetID ids.ID) (map[ids.NodeID]uint64, error)
+	GetValidatorSetF  func(height uint64, subnetID ids.ID) (map[ids.NodeID]*GetValidatorOutput, error)
 }
 
 func (vm *TestState) GetMinimumHeight() (uint64, error) {
@@ -50,7 +50,7 @@ func (vm *TestState) GetCurrentHeight() (uint64, error) {
 	return 0, errCurrentHeight
 }
 
-func (vm *TestState) GetValidatorSet(height uint64, subnetID ids.ID) (map[ids.NodeID]uint64, error) {
+func (vm *TestState) GetValidatorSet(height uint64, subnetID ids.ID) (map[ids.NodeID]*GetValidatorOutput, error) {
 	if vm.GetValidatorSetF != nil {
 		return vm.GetValidatorSetF(height, subnetID)
 	}
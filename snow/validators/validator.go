@@ -7,6 +7,7 @@ import (
 	"math"
 
 	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/utils/crypto/bls"
 
 	safemath "github.com/dim4egster/qmallgo/utils/math"
 )
@@ -18,6 +19,10 @@ type Validator interface {
 	// ID returns the node ID of this validator
 	ID() ids.NodeID
 
+	// PublicKey returns the BLS public key of this validator, or nil if it
+	// didn't register one
+	PublicKey() *bls.PublicKey
+
 	// Weight that can be used for weighted sampling. If this validator is
 	// validating the primary network, returns the amount of QMALL staked.
 	Weight() uint64
@@ -26,12 +31,14 @@ type Validator interface {
 // validator is a struct that contains the base values required by the validator
 // interface.
 type validator struct {
-	nodeID ids.NodeID
-	weight uint64
+	nodeID    ids.NodeID
+	publicKey *bls.PublicKey
+	weight    uint64
 }
 
-func (v *validator) ID() ids.NodeID { return v.nodeID }
-func (v *validator) Weight() uint64 { return v.weight }
+func (v *validator) ID() ids.NodeID            { return v.nodeID }
+func (v *validator) PublicKey() *bls.PublicKey { return v.publicKey }
+func (v *validator) Weight() uint64            { return v.weight }
 
 func (v *validator) addWeight(weight uint64) {
 	newTotalWeight, err := safemath.Add64(weight, v.weight)
@@ -53,11 +60,13 @@ func (v *validator) removeWeight(weight uint64) {
 // interface
 func NewValidator(
 	nodeID ids.NodeID,
+	publicKey *bls.PublicKey,
 	weight uint64,
 ) Validator {
 	return &validator{
-		nodeID: nodeID,
-		weight: weight,
+		nodeID:    nodeID,
+		publicKey: publicKey,
+		weight:    weight,
 	}
 }
 
@@ -65,6 +74,7 @@ func NewValidator(
 func GenerateRandomValidator(weight uint64) Validator {
 	return NewValidator(
 		ids.GenerateTestNodeID(),
+		nil,
 		weight,
 	)
 }
@@ -0,0 +1,55 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package trace
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// CurrentContext records the context of the operation that a chain is
+// currently executing.
+//
+// The engine and VM interfaces don't accept a context. To allow spans started
+// by the VM to be parented by the span of the message that caused them, the
+// context of the message being handled is recorded here.
+//
+// CurrentContext is safe for concurrent use, and a nil *CurrentContext always
+// reports [context.Background].
+type CurrentContext struct {
+	ctx atomic.Value
+}
+
+// contextBox allows contexts of different concrete types to be stored in an
+// atomic.Value.
+type contextBox struct {
+	ctx context.Context
+}
+
+// Get returns the context of the operation currently being executed. If no
+// operation is being executed, [context.Background] is returned.
+func (c *CurrentContext) Get() context.Context {
+	if c == nil {
+		return context.Background()
+	}
+	box, ok := c.ctx.Load().(contextBox)
+	if !ok {
+		return context.Background()
+	}
+	return box.ctx
+}
+
+// Set records [ctx] as the context of the operation currently being executed
+// and returns the previously recorded context, so that it can be restored
+// once the operation has finished.
+func (c *CurrentContext) Set(ctx context.Context) context.Context {
+	if c == nil {
+		return context.Background()
+	}
+	previous, ok := c.ctx.Swap(contextBox{ctx: ctx}).(contextBox)
+	if !ok {
+		return context.Background()
+	}
+	return previous.ctx
+}
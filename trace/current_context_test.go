@@ -0,0 +1,41 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package trace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testContextKey struct{}
+
+func TestCurrentContext(t *testing.T) {
+	require := require.New(t)
+
+	current := &CurrentContext{}
+	require.Equal(context.Background(), current.Get())
+
+	ctx1 := context.WithValue(context.Background(), testContextKey{}, 1)
+	previous := current.Set(ctx1)
+	require.Equal(context.Background(), previous)
+	require.Equal(ctx1, current.Get())
+
+	ctx2 := context.WithValue(context.Background(), testContextKey{}, 2)
+	previous = current.Set(ctx2)
+	require.Equal(ctx1, previous)
+	require.Equal(ctx2, current.Get())
+
+	current.Set(previous)
+	require.Equal(ctx1, current.Get())
+}
+
+func TestNilCurrentContext(t *testing.T) {
+	require := require.New(t)
+
+	var current *CurrentContext
+	require.Equal(context.Background(), current.Get())
+	require.Equal(context.Background(), current.Set(context.TODO()))
+}
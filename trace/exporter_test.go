@@ -0,0 +1,25 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package trace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExporterTypeFromString(t *testing.T) {
+	require := require.New(t)
+
+	exporterType, err := ExporterTypeFromString("grpc")
+	require.NoError(err)
+	require.Equal(GRPC, exporterType)
+
+	exporterType, err = ExporterTypeFromString("HTTP")
+	require.NoError(err)
+	require.Equal(HTTP, exporterType)
+
+	_, err = ExporterTypeFromString("zipkin")
+	require.ErrorIs(err, errUnknownExporterType)
+}
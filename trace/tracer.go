@@ -0,0 +1,97 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package trace
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"go.opentelemetry.io/otel/trace"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+
+	"github.com/dim4egster/qmallgo/utils/constants"
+	"github.com/dim4egster/qmallgo/version"
+)
+
+const tracerExportTimeout = 10 * time.Second
+
+// Noop is a Tracer that doesn't record any spans.
+var Noop Tracer = &noOpTracer{
+	Tracer: trace.NewNoopTracerProvider().Tracer(constants.AppName),
+}
+
+type Config struct {
+	ExporterConfig `json:"exporterConfig"`
+
+	// If true, enable tracing.
+	Enabled bool `json:"enabled"`
+
+	// The fraction of traces to sample.
+	// If >= 1 always samples.
+	// If <= 0 never samples.
+	//
+	// Traces whose root span was started by a remote node or process are
+	// sampled if the remote span was sampled, regardless of this value.
+	TraceSampleRate float64 `json:"traceSampleRate"`
+}
+
+// Tracer creates spans and exports them once they have finished.
+type Tracer interface {
+	trace.Tracer
+	io.Closer
+}
+
+type tracer struct {
+	trace.Tracer
+
+	tp *sdktrace.TracerProvider
+}
+
+func (t *tracer) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), tracerExportTimeout)
+	defer cancel()
+	return t.tp.Shutdown(ctx)
+}
+
+// New returns a new Tracer described by [config]. If tracing isn't enabled,
+// [Noop] is returned.
+func New(config Config) (Tracer, error) {
+	if !config.Enabled {
+		return Noop, nil
+	}
+
+	exporter, err := newExporter(config.ExporterConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter, sdktrace.WithExportTimeout(tracerExportTimeout)),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			attribute.Stringer("version", version.Current),
+			semconv.ServiceNameKey.String(constants.AppName),
+		)),
+		sdktrace.WithSampler(sdktrace.ParentBased(
+			sdktrace.TraceIDRatioBased(config.TraceSampleRate),
+		)),
+	)
+	return &tracer{
+		Tracer: tracerProvider.Tracer(constants.AppName),
+		tp:     tracerProvider,
+	}, nil
+}
+
+type noOpTracer struct {
+	trace.Tracer
+}
+
+func (*noOpTracer) Close() error {
+	return nil
+}
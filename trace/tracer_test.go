@@ -0,0 +1,24 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package trace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDisabled(t *testing.T) {
+	require := require.New(t)
+
+	tracer, err := New(Config{})
+	require.NoError(err)
+	require.Equal(Noop, tracer)
+
+	_, span := tracer.Start(context.Background(), "test")
+	require.False(span.IsRecording())
+	span.End()
+	require.NoError(tracer.Close())
+}
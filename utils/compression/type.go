@@ -0,0 +1,60 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package compression
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var errUnknownCompressionType = errors.New("unknown compression type")
+
+// Type is the compression algorithm used to compress a message.
+type Type byte
+
+const (
+	TypeNone Type = iota + 1
+	TypeGzip
+	TypeZstd
+)
+
+// Types contains all the supported compression types.
+var Types = []Type{
+	TypeNone,
+	TypeGzip,
+	TypeZstd,
+}
+
+func (t Type) String() string {
+	switch t {
+	case TypeNone:
+		return "none"
+	case TypeGzip:
+		return "gzip"
+	case TypeZstd:
+		return "zstd"
+	default:
+		return "unknown"
+	}
+}
+
+// Valid returns true if [t] is a supported compression type.
+func (t Type) Valid() bool {
+	return t >= TypeNone && t <= TypeZstd
+}
+
+func (t Type) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprintf("%q", t)), nil
+}
+
+// TypeFromString returns the compression type named [s].
+func TypeFromString(s string) (Type, error) {
+	for _, t := range Types {
+		if strings.EqualFold(s, t.String()) {
+			return t, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %q", errUnknownCompressionType, s)
+}
@@ -0,0 +1,141 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package compression
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/dim4egster/qmallgo/utils/hashing"
+)
+
+const (
+	// zstdDictMagic is the magic number that prefixes dictionaries in the zstd
+	// dictionary format.
+	zstdDictMagic = 0xEC30A437
+
+	// minRawDictionaryID is the smallest dictionary ID that is assigned to raw
+	// content dictionaries. IDs below this value are reserved by the zstd
+	// format for registered dictionaries.
+	minRawDictionaryID = 1 << 15
+)
+
+var (
+	_ Compressor = &zstdCompressor{}
+
+	ErrInvalidMaxSizeZstdCompressor = errors.New("invalid zstd compressor max size")
+	errEmptyDictionary              = errors.New("empty zstd dictionary")
+)
+
+type zstdCompressor struct {
+	maxSize int64
+
+	// Both the encoder and the decoder are safe for concurrent use when using
+	// EncodeAll and DecodeAll.
+	encoder *zstd.Encoder
+	decoder *zstd.Decoder
+}
+
+// Compress [msg] and returns the compressed bytes.
+func (z *zstdCompressor) Compress(msg []byte) ([]byte, error) {
+	if int64(len(msg)) > z.maxSize {
+		return nil, fmt.Errorf("msg length (%d) > maximum msg length (%d)", len(msg), z.maxSize)
+	}
+	return z.encoder.EncodeAll(msg, nil), nil
+}
+
+// Decompress decompresses [msg].
+func (z *zstdCompressor) Decompress(msg []byte) ([]byte, error) {
+	decompressed, err := z.decoder.DecodeAll(msg, nil)
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(decompressed)) > z.maxSize {
+		return nil, fmt.Errorf("msg length > maximum msg length (%d)", z.maxSize)
+	}
+	return decompressed, nil
+}
+
+// NewZstdCompressor returns a new zstd Compressor.
+func NewZstdCompressor(maxSize int64) (Compressor, error) {
+	return newZstdCompressor(maxSize, nil, nil)
+}
+
+// NewZstdCompressorWithDictionary returns a new zstd Compressor that
+// compresses messages using [dictionary].
+//
+// [dictionary] may either be in the zstd dictionary format, as produced by
+// `zstd --train`, or be raw content. Messages compressed with a dictionary
+// can only be decompressed by a compressor using the same dictionary.
+// Messages compressed without a dictionary can always be decompressed.
+func NewZstdCompressorWithDictionary(maxSize int64, dictionary []byte) (Compressor, error) {
+	if len(dictionary) == 0 {
+		return nil, errEmptyDictionary
+	}
+
+	if isFormattedZstdDictionary(dictionary) {
+		return newZstdCompressor(
+			maxSize,
+			[]zstd.EOption{zstd.WithEncoderDict(dictionary)},
+			[]zstd.DOption{zstd.WithDecoderDicts(dictionary)},
+		)
+	}
+
+	id := ZstdDictionaryID(dictionary)
+	return newZstdCompressor(
+		maxSize,
+		[]zstd.EOption{zstd.WithEncoderDictRaw(id, dictionary)},
+		[]zstd.DOption{zstd.WithDecoderDictRaw(id, dictionary)},
+	)
+}
+
+func newZstdCompressor(maxSize int64, encoderOpts []zstd.EOption, decoderOpts []zstd.DOption) (Compressor, error) {
+	if maxSize <= 0 || maxSize == math.MaxInt64 {
+		return nil, ErrInvalidMaxSizeZstdCompressor
+	}
+
+	encoder, err := zstd.NewWriter(nil, encoderOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Limiting the memory of the decoder prevents decompression bombs from
+	// allocating more than [maxSize] bytes.
+	decoderOpts = append(decoderOpts, zstd.WithDecoderMaxMemory(uint64(maxSize)))
+	decoder, err := zstd.NewReader(nil, decoderOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return &zstdCompressor{
+		maxSize: maxSize,
+		encoder: encoder,
+		decoder: decoder,
+	}, nil
+}
+
+// ZstdDictionaryID returns the ID that messages compressed with [dictionary]
+// are tagged with. Returns 0 if [dictionary] is empty.
+//
+// Dictionaries in the zstd format carry their own ID. Raw content dictionaries
+// are assigned an ID derived from their contents so that every node using the
+// same content agrees on the ID.
+func ZstdDictionaryID(dictionary []byte) uint32 {
+	switch {
+	case len(dictionary) == 0:
+		return 0
+	case isFormattedZstdDictionary(dictionary):
+		return binary.LittleEndian.Uint32(dictionary[4:8])
+	default:
+		hash := hashing.ComputeHash256(dictionary)
+		return minRawDictionaryID + binary.BigEndian.Uint32(hash)%(math.MaxUint32-minRawDictionaryID)
+	}
+}
+
+func isFormattedZstdDictionary(dictionary []byte) bool {
+	return len(dictionary) >= 8 && binary.LittleEndian.Uint32(dictionary) == zstdDictMagic
+}
@@ -0,0 +1,127 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package compression
+
+import (
+	"bytes"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dim4egster/qmallgo/utils/units"
+)
+
+func TestZstdCompressDecompress(t *testing.T) {
+	require := require.New(t)
+
+	data := make([]byte, 4096)
+	for i := 0; i < len(data); i++ {
+		data[i] = byte(rand.Intn(256)) // #nosec G404
+	}
+
+	data2 := bytes.Repeat([]byte("qmallgo"), 1024)
+
+	compressor, err := NewZstdCompressor(2 * units.MiB)
+	require.NoError(err)
+
+	dataCompressed, err := compressor.Compress(data)
+	require.NoError(err)
+
+	data2Compressed, err := compressor.Compress(data2)
+	require.NoError(err)
+	require.Less(len(data2Compressed), len(data2))
+
+	dataDecompressed, err := compressor.Decompress(dataCompressed)
+	require.NoError(err)
+	require.Equal(data, dataDecompressed)
+
+	data2Decompressed, err := compressor.Decompress(data2Compressed)
+	require.NoError(err)
+	require.Equal(data2, data2Decompressed)
+
+	nonZstdData := []byte{1, 2, 3}
+	_, err = compressor.Decompress(nonZstdData)
+	require.Error(err)
+}
+
+func TestZstdSizeLimiting(t *testing.T) {
+	require := require.New(t)
+
+	data := make([]byte, 3*units.MiB)
+	compressor, err := NewZstdCompressor(2 * units.MiB)
+	require.NoError(err)
+
+	_, err = compressor.Compress(data) // should be too large
+	require.Error(err)
+
+	compressor2, err := NewZstdCompressor(4 * units.MiB)
+	require.NoError(err)
+
+	dataCompressed, err := compressor2.Compress(data)
+	require.NoError(err)
+
+	_, err = compressor.Decompress(dataCompressed) // should be too large
+	require.Error(err)
+}
+
+func TestZstdDictionary(t *testing.T) {
+	require := require.New(t)
+
+	dictionary := bytes.Repeat([]byte("container bytes "), 64)
+	data := bytes.Repeat([]byte("container bytes "), 4)
+
+	dictCompressor, err := NewZstdCompressorWithDictionary(2*units.MiB, dictionary)
+	require.NoError(err)
+
+	plainCompressor, err := NewZstdCompressor(2 * units.MiB)
+	require.NoError(err)
+
+	dictCompressed, err := dictCompressor.Compress(data)
+	require.NoError(err)
+
+	plainCompressed, err := plainCompressor.Compress(data)
+	require.NoError(err)
+	require.Less(len(dictCompressed), len(plainCompressed))
+
+	// A compressor with a dictionary can decompress messages compressed with
+	// and without the dictionary.
+	decompressed, err := dictCompressor.Decompress(dictCompressed)
+	require.NoError(err)
+	require.Equal(data, decompressed)
+
+	decompressed, err = dictCompressor.Decompress(plainCompressed)
+	require.NoError(err)
+	require.Equal(data, decompressed)
+
+	// A compressor without the dictionary can't decompress messages
+	// compressed with the dictionary.
+	_, err = plainCompressor.Decompress(dictCompressed)
+	require.Error(err)
+}
+
+func TestZstdDictionaryID(t *testing.T) {
+	require := require.New(t)
+
+	require.Zero(ZstdDictionaryID(nil))
+
+	rawDictionary := []byte("raw dictionary content")
+	rawID := ZstdDictionaryID(rawDictionary)
+	require.GreaterOrEqual(rawID, uint32(minRawDictionaryID))
+	require.Equal(rawID, ZstdDictionaryID([]byte("raw dictionary content")))
+
+	formattedDictionary := []byte{0x37, 0xa4, 0x30, 0xec, 0x01, 0x02, 0x03, 0x04}
+	require.Equal(uint32(0x04030201), ZstdDictionaryID(formattedDictionary))
+}
+
+func TestNewZstdCompressorWithInvalidLimit(t *testing.T) {
+	require := require.New(t)
+
+	_, err := NewZstdCompressor(math.MaxInt64)
+	require.ErrorIs(err, ErrInvalidMaxSizeZstdCompressor)
+
+	_, err = NewZstdCompressorWithDictionary(units.MiB, nil)
+	require.ErrorIs(err, errEmptyDictionary)
+}
@@ -0,0 +1,132 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package filekeychain stores secp256k1 keys in a password encrypted file. It
+// is the reference implementation of an external signer: the keys are only
+// decrypted by the process serving the keychain.
+package filekeychain
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+
+	"github.com/dim4egster/qmallgo/codec"
+	"github.com/dim4egster/qmallgo/codec/linearcodec"
+	"github.com/dim4egster/qmallgo/utils/crypto"
+	"github.com/dim4egster/qmallgo/utils/perms"
+	"github.com/dim4egster/qmallgo/vms/secp256k1fx"
+)
+
+const (
+	codecVersion = 0
+	saltLen      = 16
+)
+
+var (
+	errWrongPassword = errors.New("wrong password or corrupted keychain file")
+
+	c codec.Manager
+)
+
+func init() {
+	c = codec.NewDefaultManager()
+	if err := c.RegisterCodec(codecVersion, linearcodec.NewDefault()); err != nil {
+		panic(err)
+	}
+}
+
+// encryptedFile is the content of a keychain file.
+type encryptedFile struct {
+	Salt       []byte `serialize:"true"`
+	Nonce      []byte `serialize:"true"`
+	Ciphertext []byte `serialize:"true"`
+}
+
+// keys is the plaintext of a keychain file.
+type keys struct {
+	Keys [][]byte `serialize:"true"`
+}
+
+// Save encrypts [privateKeys] with [password] and writes them to [path].
+func Save(path string, password string, privateKeys []*crypto.PrivateKeySECP256K1R) error {
+	plaintext := keys{
+		Keys: make([][]byte, len(privateKeys)),
+	}
+	for i, key := range privateKeys {
+		plaintext.Keys[i] = key.Bytes()
+	}
+	plaintextBytes, err := c.Marshal(codecVersion, &plaintext)
+	if err != nil {
+		return err
+	}
+
+	file := encryptedFile{
+		Salt:  make([]byte, saltLen),
+		Nonce: make([]byte, chacha20poly1305.NonceSizeX),
+	}
+	if _, err := rand.Read(file.Salt); err != nil {
+		return err
+	}
+	if _, err := rand.Read(file.Nonce); err != nil {
+		return err
+	}
+	aead, err := chacha20poly1305.NewX(deriveKey(password, file.Salt))
+	if err != nil {
+		return err
+	}
+	file.Ciphertext = aead.Seal(nil, file.Nonce, plaintextBytes, nil)
+
+	fileBytes, err := c.Marshal(codecVersion, &file)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, fileBytes, perms.ReadWrite)
+}
+
+// Load decrypts the keys stored at [path] with [password].
+func Load(path string, password string) (*secp256k1fx.Keychain, error) {
+	fileBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	file := encryptedFile{}
+	if _, err := c.Unmarshal(fileBytes, &file); err != nil {
+		return nil, fmt.Errorf("couldn't parse keychain file: %w", err)
+	}
+	if len(file.Nonce) != chacha20poly1305.NonceSizeX {
+		return nil, errWrongPassword
+	}
+
+	aead, err := chacha20poly1305.NewX(deriveKey(password, file.Salt))
+	if err != nil {
+		return nil, err
+	}
+	plaintextBytes, err := aead.Open(nil, file.Nonce, file.Ciphertext, nil)
+	if err != nil {
+		return nil, errWrongPassword
+	}
+	plaintext := keys{}
+	if _, err := c.Unmarshal(plaintextBytes, &plaintext); err != nil {
+		return nil, fmt.Errorf("couldn't parse keys: %w", err)
+	}
+
+	factory := crypto.FactorySECP256K1R{}
+	kc := secp256k1fx.NewKeychain()
+	for _, keyBytes := range plaintext.Keys {
+		key, err := factory.ToPrivateKey(keyBytes)
+		if err != nil {
+			return nil, err
+		}
+		kc.Add(key.(*crypto.PrivateKeySECP256K1R))
+	}
+	return kc, nil
+}
+
+func deriveKey(password string, salt []byte) []byte {
+	return argon2.IDKey([]byte(password), salt, 1, 64*1024, 4, chacha20poly1305.KeySize)
+}
@@ -0,0 +1,40 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package filekeychain
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dim4egster/qmallgo/utils/crypto"
+)
+
+func TestSaveLoad(t *testing.T) {
+	require := require.New(t)
+
+	factory := crypto.FactorySECP256K1R{}
+	keys := make([]*crypto.PrivateKeySECP256K1R, 2)
+	for i := range keys {
+		key, err := factory.NewPrivateKey()
+		require.NoError(err)
+		keys[i] = key.(*crypto.PrivateKeySECP256K1R)
+	}
+
+	path := filepath.Join(t.TempDir(), "keychain")
+	require.NoError(Save(path, "password", keys))
+
+	_, err := Load(path, "wrong password")
+	require.ErrorIs(err, errWrongPassword)
+
+	kc, err := Load(path, "password")
+	require.NoError(err)
+	require.Equal(len(keys), kc.Addresses().Len())
+	for _, key := range keys {
+		signer, ok := kc.Get(key.Address())
+		require.True(ok)
+		require.Equal(key.Bytes(), signer.(*crypto.PrivateKeySECP256K1R).Bytes())
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gkeychain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/utils/crypto"
+	"github.com/dim4egster/qmallgo/utils/crypto/keychain"
+
+	keychainpb "github.com/dim4egster/qmallgo/proto/pb/keychain"
+)
+
+// signTimeout bounds every signature request, so a stuck remote keychain
+// can't hang the caller.
+const signTimeout = 30 * time.Second
+
+var (
+	errWrongSigner = errors.New("signature was produced by the wrong key")
+
+	_ keychain.Keychain = &Client{}
+	_ keychain.Signer   = &signer{}
+)
+
+// Client is a keychain whose keys are held by a remote keychain. Every
+// signature is requested over RPC, so the keys are never loaded into this
+// process.
+type Client struct {
+	client      keychainpb.KeychainClient
+	addrs       ids.ShortSet
+	signTimeout time.Duration
+}
+
+// NewClient returns a keychain connected to a remote keychain. The addresses
+// of the remote keychain are fetched once, when the client is created.
+func NewClient(ctx context.Context, client keychainpb.KeychainClient) (*Client, error) {
+	resp, err := client.Addresses(ctx, &emptypb.Empty{})
+	if err != nil {
+		return nil, err
+	}
+
+	addrs := ids.NewShortSet(len(resp.Addresses))
+	for _, addrBytes := range resp.Addresses {
+		addr, err := ids.ToShortID(addrBytes)
+		if err != nil {
+			return nil, err
+		}
+		addrs.Add(addr)
+	}
+	return &Client{
+		client:      client,
+		addrs:       addrs,
+		signTimeout: signTimeout,
+	}, nil
+}
+
+func (c *Client) Get(addr ids.ShortID) (keychain.Signer, bool) {
+	if !c.addrs.Contains(addr) {
+		return nil, false
+	}
+	return &signer{
+		client:  c.client,
+		addr:    addr,
+		timeout: c.signTimeout,
+	}, true
+}
+
+func (c *Client) Addresses() ids.ShortSet {
+	return c.addrs
+}
+
+type signer struct {
+	factory crypto.FactorySECP256K1R
+	client  keychainpb.KeychainClient
+	addr    ids.ShortID
+	timeout time.Duration
+}
+
+func (s *signer) SignHash(hash []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	resp, err := s.client.SignHash(ctx, &keychainpb.SignHashRequest{
+		Address: s.addr[:],
+		Hash:    hash,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The remote keychain isn't trusted to have signed with the right key.
+	pk, err := s.factory.RecoverHashPublicKey(hash, resp.Signature)
+	if err != nil {
+		return nil, err
+	}
+	if addr := pk.Address(); addr != s.addr {
+		return nil, fmt.Errorf("%w: expected %s but got %s", errWrongSigner, s.addr, addr)
+	}
+	return resp.Signature, nil
+}
+
+func (s *signer) Address() ids.ShortID {
+	return s.addr
+}
@@ -0,0 +1,69 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gkeychain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/utils/crypto/keychain"
+	"github.com/dim4egster/qmallgo/utils/hashing"
+
+	keychainpb "github.com/dim4egster/qmallgo/proto/pb/keychain"
+)
+
+var (
+	errUnknownAddress = errors.New("unknown address")
+	errInvalidHash    = errors.New("invalid hash length")
+
+	_ keychainpb.KeychainServer = &Server{}
+)
+
+// Server is a keychain that is managed over RPC.
+type Server struct {
+	keychainpb.UnsafeKeychainServer
+	kc keychain.Keychain
+}
+
+// NewServer returns a keychain server that signs with [kc].
+func NewServer(kc keychain.Keychain) *Server {
+	return &Server{kc: kc}
+}
+
+func (s *Server) Addresses(context.Context, *emptypb.Empty) (*keychainpb.AddressesResponse, error) {
+	addrs := s.kc.Addresses()
+	resp := &keychainpb.AddressesResponse{
+		Addresses: make([][]byte, 0, addrs.Len()),
+	}
+	for addr := range addrs {
+		addr := addr
+		resp.Addresses = append(resp.Addresses, addr[:])
+	}
+	return resp, nil
+}
+
+func (s *Server) SignHash(_ context.Context, req *keychainpb.SignHashRequest) (*keychainpb.SignHashResponse, error) {
+	addr, err := ids.ToShortID(req.Address)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Hash) != hashing.HashLen {
+		return nil, fmt.Errorf("%w: %d", errInvalidHash, len(req.Hash))
+	}
+	signer, ok := s.kc.Get(addr)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", errUnknownAddress, addr)
+	}
+	sig, err := signer.SignHash(req.Hash)
+	if err != nil {
+		return nil, err
+	}
+	return &keychainpb.SignHashResponse{
+		Signature: sig,
+	}, nil
+}
@@ -0,0 +1,138 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gkeychain
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/utils/crypto"
+	"github.com/dim4egster/qmallgo/utils/crypto/keychain/filekeychain"
+	"github.com/dim4egster/qmallgo/utils/hashing"
+	"github.com/dim4egster/qmallgo/vms/secp256k1fx"
+
+	keychainpb "github.com/dim4egster/qmallgo/proto/pb/keychain"
+)
+
+const (
+	keychainPathEnv     = "TEST_KEYCHAIN_PATH"
+	keychainPasswordEnv = "TEST_KEYCHAIN_PASSWORD"
+)
+
+// TestHelperProcess serves the keychain file provided by the test as an
+// external signer.
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("TEST_PROCESS") != "1" {
+		return
+	}
+
+	kc, err := filekeychain.Load(os.Getenv(keychainPathEnv), os.Getenv(keychainPasswordEnv))
+	if err != nil {
+		os.Exit(1)
+	}
+	Serve(kc)
+	os.Exit(0)
+}
+
+func helperProcess(path, password string) *exec.Cmd {
+	cmd := exec.Command(os.Args[0], "-test.run=TestHelperProcess", "--")
+	cmd.Env = append(
+		os.Environ(),
+		"TEST_PROCESS=1",
+		keychainPathEnv+"="+path,
+		keychainPasswordEnv+"="+password,
+	)
+	return cmd
+}
+
+func newTestKeys(t *testing.T, n int) []*crypto.PrivateKeySECP256K1R {
+	factory := crypto.FactorySECP256K1R{}
+	keys := make([]*crypto.PrivateKeySECP256K1R, n)
+	for i := range keys {
+		key, err := factory.NewPrivateKey()
+		require.NoError(t, err)
+		keys[i] = key.(*crypto.PrivateKeySECP256K1R)
+	}
+	return keys
+}
+
+func TestLaunchedKeychain(t *testing.T) {
+	require := require.New(t)
+
+	keys := newTestKeys(t, 2)
+	path := filepath.Join(t.TempDir(), "keychain")
+	require.NoError(filekeychain.Save(path, "password", keys))
+
+	kc, stop, err := launch(helperProcess(path, "password"))
+	require.NoError(err)
+	defer stop()
+
+	require.Equal(secp256k1fx.NewKeychain(keys...).Addresses(), kc.Addresses())
+
+	_, ok := kc.Get(ids.GenerateTestShortID())
+	require.False(ok)
+
+	hash := hashing.ComputeHash256([]byte("message"))
+	for _, key := range keys {
+		signer, ok := kc.Get(key.Address())
+		require.True(ok)
+		require.Equal(key.Address(), signer.Address())
+
+		sig, err := signer.SignHash(hash)
+		require.NoError(err)
+		expectedSig, err := key.SignHash(hash)
+		require.NoError(err)
+		require.Equal(expectedSig, sig)
+	}
+}
+
+func TestServerRejectsUnknownAddress(t *testing.T) {
+	require := require.New(t)
+
+	server := NewServer(secp256k1fx.NewKeychain(newTestKeys(t, 1)...))
+	addr := ids.GenerateTestShortID()
+	_, err := server.SignHash(context.Background(), &keychainpb.SignHashRequest{
+		Address: addr[:],
+		Hash:    hashing.ComputeHash256([]byte("message")),
+	})
+	require.ErrorIs(err, errUnknownAddress)
+}
+
+// stuckKeychainClient never answers a signature request.
+type stuckKeychainClient struct {
+	addr ids.ShortID
+}
+
+func (c *stuckKeychainClient) Addresses(context.Context, *emptypb.Empty, ...grpc.CallOption) (*keychainpb.AddressesResponse, error) {
+	return &keychainpb.AddressesResponse{Addresses: [][]byte{c.addr[:]}}, nil
+}
+
+func (*stuckKeychainClient) SignHash(ctx context.Context, _ *keychainpb.SignHashRequest, _ ...grpc.CallOption) (*keychainpb.SignHashResponse, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestSignHashTimeout(t *testing.T) {
+	require := require.New(t)
+
+	addr := ids.GenerateTestShortID()
+	kc, err := NewClient(context.Background(), &stuckKeychainClient{addr: addr})
+	require.NoError(err)
+	kc.signTimeout = time.Millisecond
+
+	signer, ok := kc.Get(addr)
+	require.True(ok)
+	_, err = signer.SignHash(hashing.ComputeHash256([]byte("message")))
+	require.ErrorIs(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,124 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gkeychain
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os/exec"
+
+	"google.golang.org/grpc"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-plugin"
+
+	"github.com/dim4egster/qmallgo/utils/crypto/keychain"
+	"github.com/dim4egster/qmallgo/utils/subprocess"
+	"github.com/dim4egster/qmallgo/vms/rpcchainvm/grpcutils"
+
+	keychainpb "github.com/dim4egster/qmallgo/proto/pb/keychain"
+)
+
+// protocolVersion should be bumped anytime changes are made which require
+// the keychain plugin to upgrade to be compatible.
+const protocolVersion = 1
+
+var (
+	errWrongKeychain = errors.New("wrong keychain type")
+
+	// Handshake is a common handshake that is shared by plugin and host.
+	Handshake = plugin.HandshakeConfig{
+		ProtocolVersion:  protocolVersion,
+		MagicCookieKey:   "KEYCHAIN_PLUGIN",
+		MagicCookieValue: "signer",
+	}
+
+	// PluginMap is the map of plugins we can dispense.
+	PluginMap = map[string]plugin.Plugin{
+		"keychain": &keychainPlugin{},
+	}
+
+	_ plugin.Plugin     = &keychainPlugin{}
+	_ plugin.GRPCPlugin = &keychainPlugin{}
+)
+
+type keychainPlugin struct {
+	plugin.NetRPCUnsupportedPlugin
+	// Concrete implementation, written in Go. This is only used for plugins
+	// that are written in Go.
+	kc keychain.Keychain
+}
+
+// New will be called by the server side of the plugin to pass into the server
+// side PluginMap for dispatching.
+func New(kc keychain.Keychain) plugin.Plugin {
+	return &keychainPlugin{kc: kc}
+}
+
+// GRPCServer registers a new GRPC server.
+func (p *keychainPlugin) GRPCServer(_ *plugin.GRPCBroker, s *grpc.Server) error {
+	keychainpb.RegisterKeychainServer(s, NewServer(p.kc))
+	return nil
+}
+
+// GRPCClient returns a new GRPC client
+func (*keychainPlugin) GRPCClient(ctx context.Context, _ *plugin.GRPCBroker, c *grpc.ClientConn) (interface{}, error) {
+	return NewClient(ctx, keychainpb.NewKeychainClient(c))
+}
+
+// Serve serves a keychain plugin using sane gRPC server defaults. This should
+// be called by the main function of an external signer.
+func Serve(kc keychain.Keychain) {
+	plugin.Serve(&plugin.ServeConfig{
+		HandshakeConfig: Handshake,
+		Plugins: map[string]plugin.Plugin{
+			"keychain": New(kc),
+		},
+		// ensure proper defaults
+		GRPCServer: grpcutils.NewDefaultServer,
+	})
+}
+
+// Launch starts the external signer at [path] and returns a keychain that
+// signs through it. The returned function must be called to stop the signer
+// once the keychain is no longer needed.
+func Launch(path string, args ...string) (*Client, func(), error) {
+	return launch(subprocess.New(path, args...))
+}
+
+func launch(cmd *exec.Cmd) (*Client, func(), error) {
+	client := plugin.NewClient(&plugin.ClientConfig{
+		HandshakeConfig: Handshake,
+		Plugins:         PluginMap,
+		Cmd:             cmd,
+		AllowedProtocols: []plugin.Protocol{
+			plugin.ProtocolGRPC,
+		},
+		GRPCDialOptions: grpcutils.DefaultDialOptions,
+		Stderr:          io.Discard,
+		Logger: hclog.New(&hclog.LoggerOptions{
+			Output: io.Discard,
+		}),
+	})
+
+	rpcClient, err := client.Client()
+	if err != nil {
+		client.Kill()
+		return nil, nil, err
+	}
+
+	raw, err := rpcClient.Dispense("keychain")
+	if err != nil {
+		client.Kill()
+		return nil, nil, err
+	}
+
+	kc, ok := raw.(*Client)
+	if !ok {
+		client.Kill()
+		return nil, nil, errWrongKeychain
+	}
+	return kc, client.Kill, nil
+}
@@ -0,0 +1,25 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keychain
+
+import (
+	"github.com/dim4egster/qmallgo/ids"
+)
+
+// Signer produces secp256k1 signatures for a single address. The private key
+// may be held in memory or by an external process.
+type Signer interface {
+	// SignHash returns the recoverable signature of [hash].
+	SignHash(hash []byte) ([]byte, error)
+	// Address returns the address the signatures are produced for.
+	Address() ids.ShortID
+}
+
+// Keychain is a collection of signers indexed by their addresses.
+type Keychain interface {
+	// Get returns the signer of [addr], if it is known.
+	Get(addr ids.ShortID) (Signer, bool)
+	// Addresses returns the addresses this keychain can sign for.
+	Addresses() ids.ShortSet
+}
@@ -0,0 +1,144 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package json
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	stdjson "encoding/json"
+)
+
+var (
+	// ErrRequestTooLarge is returned when a request body exceeds the maximum
+	// size it may be read with.
+	ErrRequestTooLarge = errors.New("request body too large")
+
+	errTrailingData   = errors.New("unexpected data after the request")
+	errNotAnObject    = errors.New("request isn't a JSON object")
+	errMalformedBatch = errors.New("malformed batch")
+)
+
+type requestKey struct{}
+
+type methodRequest struct {
+	Method string `json:"method"`
+}
+
+// Request is the body of an HTTP request, parsed as a JSON-RPC request or
+// batch.
+type Request struct {
+	// Body of the HTTP request.
+	Body []byte
+	// IsBatch is true if [Body] is a JSON array.
+	IsBatch bool
+	// Batch holds the requests of the batch, if [Body] is a well-formed batch.
+	Batch []stdjson.RawMessage
+	// Methods called by the request, in order. Only meaningful if [Err] is
+	// nil.
+	Methods []string
+	// Err is non-nil if [Body] isn't empty and isn't a well-formed JSON-RPC
+	// request or batch, in which case the methods it would call are unknown.
+	Err error
+}
+
+// ParseRequest reads and parses the body of [r], or returns the result of the
+// previous call made for [r]. The returned http.Request carries the parsed
+// body, so that handlers it's passed to don't parse it again, and its body
+// can still be read. If the body is larger than [maxBytes],
+// ErrRequestTooLarge is returned.
+//
+// Requests are parsed the way the JSON-RPC codec and the batch handler decode
+// them, except that data after a request and batch elements that aren't
+// objects are reported as errors rather than ignored.
+func ParseRequest(w http.ResponseWriter, r *http.Request, maxBytes int64) (*http.Request, *Request, error) {
+	if request, ok := r.Context().Value(requestKey{}).(*Request); ok {
+		r.Body = io.NopCloser(bytes.NewReader(request.Body))
+		return r, request, nil
+	}
+
+	request := &Request{}
+	if r.Body != nil {
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBytes))
+		if err != nil {
+			// The body is only cut short once [maxBytes] have been read.
+			if int64(len(body)) >= maxBytes {
+				return r, nil, ErrRequestTooLarge
+			}
+			return r, nil, err
+		}
+		request.Body = body
+		request.parse()
+	}
+
+	return WithRequest(r, request), request, nil
+}
+
+// WithRequest returns a copy of [r] whose body is [request]. Calling
+// ParseRequest with the returned http.Request returns [request].
+func WithRequest(r *http.Request, request *Request) *http.Request {
+	r = r.WithContext(context.WithValue(r.Context(), requestKey{}, request))
+	r.Body = io.NopCloser(bytes.NewReader(request.Body))
+	r.ContentLength = int64(len(request.Body))
+	return r
+}
+
+func (r *Request) parse() {
+	body := bytes.TrimSpace(r.Body)
+	if len(body) == 0 {
+		return
+	}
+	if body[0] != '[' {
+		method, err := parseMethod(body)
+		if err != nil {
+			r.Err = err
+			return
+		}
+		if method != "" {
+			r.Methods = []string{method}
+		}
+		return
+	}
+
+	r.IsBatch = true
+	if err := stdjson.Unmarshal(body, &r.Batch); err != nil {
+		r.Batch = nil
+		r.Err = fmt.Errorf("%w: %s", errMalformedBatch, err)
+		return
+	}
+	r.Methods = make([]string, len(r.Batch))
+	for i, request := range r.Batch {
+		method, err := parseMethod(request)
+		if err != nil {
+			r.Methods = nil
+			r.Err = fmt.Errorf("%w: request %d: %s", errMalformedBatch, i, err)
+			return
+		}
+		r.Methods[i] = method
+	}
+}
+
+// parseMethod returns the method called by the JSON-RPC request [b], decoded
+// as the codec decodes it.
+func parseMethod(b []byte) (string, error) {
+	b = bytes.TrimSpace(b)
+	if len(b) == 0 || b[0] != '{' {
+		return "", errNotAnObject
+	}
+	decoder := stdjson.NewDecoder(bytes.NewReader(b))
+	request := methodRequest{}
+	if err := decoder.Decode(&request); err != nil {
+		return "", err
+	}
+	// The codec stops decoding after the first value, so anything after it
+	// would be hidden from the checks made on the method.
+	if len(bytes.TrimSpace(b[decoder.InputOffset():])) != 0 {
+		return "", errTrailingData
+	}
+	return request.Method, nil
+}
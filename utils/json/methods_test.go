@@ -0,0 +1,109 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package json
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRequest(t *testing.T) {
+	tests := []struct {
+		name            string
+		body            string
+		expectedMethods []string
+		expectedBatch   bool
+		expectedErr     error
+	}{
+		{
+			name:            "single request",
+			body:            `{"jsonrpc":"2.0","id":1,"method":"avm.getBalance","params":{}}`,
+			expectedMethods: []string{"avm.getBalance"},
+		},
+		{
+			name:            "batch request",
+			body:            ` [{"method":"avm.getBalance"},{"method":"avm.send"}]`,
+			expectedMethods: []string{"avm.getBalance", "avm.send"},
+			expectedBatch:   true,
+		},
+		{
+			name: "empty body",
+			body: "",
+		},
+		{
+			name:        "not json",
+			body:        "hello",
+			expectedErr: errNotAnObject,
+		},
+		{
+			name:        "trailing data",
+			body:        `{"method":"avm.send"} x`,
+			expectedErr: errTrailingData,
+		},
+		{
+			name:        "second request",
+			body:        `{"method":"avm.getBalance"}{"method":"avm.send"}`,
+			expectedErr: errTrailingData,
+		},
+		{
+			name:          "batch element that isn't an object",
+			body:          `[{"method":"avm.send"},5]`,
+			expectedBatch: true,
+			expectedErr:   errMalformedBatch,
+		},
+		{
+			name:          "batch with trailing data",
+			body:          `[{"method":"avm.send"}] x`,
+			expectedBatch: true,
+			expectedErr:   errMalformedBatch,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			r := httptest.NewRequest(http.MethodPost, "/ext/bc/X", strings.NewReader(test.body))
+			r, request, err := ParseRequest(httptest.NewRecorder(), r, 1024)
+			require.NoError(err)
+			require.ErrorIs(request.Err, test.expectedErr)
+			require.Equal(test.expectedBatch, request.IsBatch)
+			if test.expectedErr == nil {
+				require.Equal(test.expectedMethods, request.Methods)
+			}
+
+			// The body can still be read by the handler.
+			body, err := io.ReadAll(r.Body)
+			require.NoError(err)
+			require.Equal(test.body, string(body))
+
+			// The body is only parsed once.
+			r.Body = nil
+			r, cachedRequest, err := ParseRequest(httptest.NewRecorder(), r, 1024)
+			require.NoError(err)
+			require.Same(request, cachedRequest)
+			body, err = io.ReadAll(r.Body)
+			require.NoError(err)
+			require.Equal(test.body, string(body))
+		})
+	}
+}
+
+func TestParseRequestTooLarge(t *testing.T) {
+	require := require.New(t)
+
+	body := `{"method":"avm.getBalance"}`
+	r := httptest.NewRequest(http.MethodPost, "/ext/bc/X", strings.NewReader(body))
+	_, _, err := ParseRequest(httptest.NewRecorder(), r, int64(len(body)-1))
+	require.ErrorIs(err, ErrRequestTooLarge)
+
+	r = httptest.NewRequest(http.MethodPost, "/ext/bc/X", strings.NewReader(body))
+	_, request, err := ParseRequest(httptest.NewRecorder(), r, int64(len(body)))
+	require.NoError(err)
+	require.Equal([]string{"avm.getBalance"}, request.Methods)
+}
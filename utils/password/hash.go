@@ -10,6 +10,14 @@ import (
 	"golang.org/x/crypto/argon2"
 )
 
+// Parameters of the argon2id key derivation used to hash passwords.
+const (
+	KDFTime    = 1
+	KDFMemory  = 64 * 1024
+	KDFThreads = 4
+	KDFKeyLen  = 32
+)
+
 // Hash of a password
 type Hash struct {
 	Password [32]byte `serialize:"true"` // The salted, hashed password
@@ -22,14 +30,14 @@ func (h *Hash) Set(password string) error {
 		return err
 	}
 	// pw is the salted, hashed password
-	pw := argon2.IDKey([]byte(password), h.Salt[:], 1, 64*1024, 4, 32)
-	copy(h.Password[:], pw[:32])
+	pw := argon2.IDKey([]byte(password), h.Salt[:], KDFTime, KDFMemory, KDFThreads, KDFKeyLen)
+	copy(h.Password[:], pw[:KDFKeyLen])
 	return nil
 }
 
 // Check returns true iff the provided password was the same as the last
 // password set.
 func (h *Hash) Check(password string) bool {
-	pw := argon2.IDKey([]byte(password), h.Salt[:], 1, 64*1024, 4, 32)
+	pw := argon2.IDKey([]byte(password), h.Salt[:], KDFTime, KDFMemory, KDFThreads, KDFKeyLen)
 	return bytes.Equal(pw, h.Password[:])
 }
@@ -0,0 +1,173 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	stdjson "encoding/json"
+
+	rpc "github.com/gorilla/rpc/v2/json2"
+)
+
+var (
+	errNoResponse        = errors.New("no response to request")
+	errUnexpectedReplyID = errors.New("unexpected reply ID")
+
+	_ Batch = &batch{}
+)
+
+// Request is a single JSON-RPC call of a batch.
+type Request struct {
+	Method string
+	Params interface{}
+	// Reply is populated with the result of the call.
+	Reply interface{}
+}
+
+type clientRequest struct {
+	Version string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+	ID      uint64      `json:"id"`
+}
+
+// SendJSONBatchRequest issues [requests] to [uri] in a single JSON-RPC 2.0
+// batch. The returned error is non-nil if the batch couldn't be issued.
+// Otherwise, the i-th element of the returned slice is the error, if any, of
+// the i-th request.
+func SendJSONBatchRequest(
+	ctx context.Context,
+	uri *url.URL,
+	requests []Request,
+	options ...Option,
+) ([]error, error) {
+	clientRequests := make([]clientRequest, len(requests))
+	for i, request := range requests {
+		clientRequests[i] = clientRequest{
+			Version: "2.0",
+			Method:  request.Method,
+			Params:  request.Params,
+			ID:      uint64(i),
+		}
+	}
+	requestBodyBytes, err := stdjson.Marshal(clientRequests)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode client params: %w", err)
+	}
+
+	ops := NewOptions(options)
+	uri.RawQuery = ops.queryParams.Encode()
+
+	request, err := http.NewRequestWithContext(
+		ctx,
+		"POST",
+		uri.String(),
+		bytes.NewBuffer(requestBodyBytes),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	request.Header = ops.headers
+	request.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue request: %w", err)
+	}
+
+	// Return an error for any non successful status code
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		// Drop any error during close to report the original error
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("received status code: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		// Drop any error during close to report the original error
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("failed to read client response: %w", err)
+	}
+	if err := resp.Body.Close(); err != nil {
+		return nil, err
+	}
+
+	// If the batch as a whole was rejected, the server responds with a single
+	// error rather than an array of responses.
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '{' {
+		if err := rpc.DecodeClientResponse(bytes.NewReader(trimmed), nil); err != nil {
+			return nil, err
+		}
+		return nil, errUnexpectedReplyID
+	}
+
+	var responses []stdjson.RawMessage
+	if err := stdjson.Unmarshal(body, &responses); err != nil {
+		return nil, fmt.Errorf("failed to decode client response: %w", err)
+	}
+
+	errs := make([]error, len(requests))
+	for i := range errs {
+		errs[i] = errNoResponse
+	}
+	for _, response := range responses {
+		var header struct {
+			ID *uint64 `json:"id"`
+		}
+		if err := stdjson.Unmarshal(response, &header); err != nil {
+			return nil, fmt.Errorf("failed to decode client response: %w", err)
+		}
+		if header.ID == nil || *header.ID >= uint64(len(requests)) {
+			return nil, errUnexpectedReplyID
+		}
+		i := *header.ID
+		errs[i] = rpc.DecodeClientResponse(bytes.NewReader(response), requests[i].Reply)
+	}
+	return errs, nil
+}
+
+// Batch collects requests to an endpoint that are issued together in a single
+// JSON-RPC batch.
+type Batch interface {
+	// Add queues a call to [method] of the endpoint. [reply] is populated
+	// once the batch is sent.
+	Add(method string, params interface{}, reply interface{})
+	// Len returns the number of queued requests.
+	Len() int
+	// Send issues the queued requests. See SendJSONBatchRequest.
+	Send(ctx context.Context, options ...Option) ([]error, error)
+}
+
+type batch struct {
+	uri, base string
+	requests  []Request
+}
+
+func (b *batch) Add(method string, params interface{}, reply interface{}) {
+	b.requests = append(b.requests, Request{
+		Method: fmt.Sprintf("%s.%s", b.base, method),
+		Params: params,
+		Reply:  reply,
+	})
+}
+
+func (b *batch) Len() int {
+	return len(b.requests)
+}
+
+func (b *batch) Send(ctx context.Context, options ...Option) ([]error, error) {
+	uri, err := url.Parse(b.uri)
+	if err != nil {
+		return nil, err
+	}
+	return SendJSONBatchRequest(ctx, uri, b.requests, options...)
+}
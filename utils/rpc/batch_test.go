@@ -0,0 +1,75 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpc
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	stdjson "encoding/json"
+
+	"github.com/stretchr/testify/require"
+
+	rpc "github.com/gorilla/rpc/v2/json2"
+)
+
+func TestBatch(t *testing.T) {
+	require := require.New(t)
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(err)
+
+		var requests []clientRequest
+		require.NoError(stdjson.Unmarshal(body, &requests))
+		require.Len(requests, 3)
+		require.Equal("test.echo", requests[0].Method)
+
+		// Respond out of order, with an error, and without a response to the
+		// last request.
+		_, err = w.Write([]byte(`[
+			{"jsonrpc":"2.0","error":{"code":-32000,"message":"failed"},"id":1},
+			{"jsonrpc":"2.0","result":"hello","id":0}
+		]`))
+		require.NoError(err)
+	}))
+	defer s.Close()
+
+	requester := NewEndpointRequester(s.URL, "test").(BatchRequester)
+	batch := requester.NewBatch()
+	var replies [3]string
+	for i := range replies {
+		batch.Add("echo", struct{}{}, &replies[i])
+	}
+	require.Equal(3, batch.Len())
+
+	errs, err := batch.Send(context.Background())
+	require.NoError(err)
+	require.Len(errs, 3)
+	require.NoError(errs[0])
+	require.Equal("hello", replies[0])
+	rpcErr, ok := errs[1].(*rpc.Error)
+	require.True(ok)
+	require.Equal("failed", rpcErr.Message)
+	require.ErrorIs(errs[2], errNoResponse)
+}
+
+func TestBatchRejected(t *testing.T) {
+	require := require.New(t)
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, err := w.Write([]byte(`{"jsonrpc":"2.0","error":{"code":-32600,"message":"empty batch"},"id":null}`))
+		require.NoError(err)
+	}))
+	defer s.Close()
+
+	uri, err := url.Parse(s.URL)
+	require.NoError(err)
+	_, err = SendJSONBatchRequest(context.Background(), uri, nil)
+	require.Error(err)
+}
@@ -9,12 +9,21 @@ import (
 	"net/url"
 )
 
-var _ EndpointRequester = &avalancheEndpointRequester{}
+var (
+	_ EndpointRequester = &avalancheEndpointRequester{}
+	_ BatchRequester    = &avalancheEndpointRequester{}
+)
 
 type EndpointRequester interface {
 	SendRequest(ctx context.Context, method string, params interface{}, reply interface{}, options ...Option) error
 }
 
+// BatchRequester is implemented by EndpointRequesters that can issue multiple
+// requests in a single JSON-RPC batch.
+type BatchRequester interface {
+	NewBatch() Batch
+}
+
 type avalancheEndpointRequester struct {
 	uri, base string
 }
@@ -46,3 +55,10 @@ func (e *avalancheEndpointRequester) SendRequest(
 		options...,
 	)
 }
+
+func (e *avalancheEndpointRequester) NewBatch() Batch {
+	return &batch{
+		uri:  e.uri,
+		base: e.base,
+	}
+}
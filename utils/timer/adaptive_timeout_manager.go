@@ -85,6 +85,10 @@ type AdaptiveTimeoutManager interface {
 	Stop()
 	// Returns the current network timeout duration.
 	TimeoutDuration() time.Duration
+	// Sets the time, as seen by the manager, to [now] and executes the
+	// handlers of the timeouts that occurred by [now]. Used to drive the
+	// manager with a fake clock, in which case Dispatch isn't called.
+	SetTime(now time.Time)
 	// Registers a timeout for the item with the given [id].
 	// If the timeout occurs before the item is Removed, [timeoutHandler] is called.
 	Put(id ids.ID, op message.Op, timeoutHandler func())
@@ -184,6 +188,14 @@ func (tm *adaptiveTimeoutManager) Dispatch() { tm.timer.Dispatch() }
 
 func (tm *adaptiveTimeoutManager) Stop() { tm.timer.Stop() }
 
+func (tm *adaptiveTimeoutManager) SetTime(now time.Time) {
+	tm.lock.Lock()
+	tm.clock.Set(now)
+	tm.lock.Unlock()
+
+	tm.timeout()
+}
+
 func (tm *adaptiveTimeoutManager) Put(id ids.ID, op message.Op, timeoutHandler func()) {
 	tm.lock.Lock()
 	defer tm.lock.Unlock()
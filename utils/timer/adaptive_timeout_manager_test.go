@@ -140,3 +140,33 @@ func TestAdaptiveTimeoutManager(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestAdaptiveTimeoutManagerSetTime(t *testing.T) {
+	require := require.New(t)
+
+	tm, err := NewAdaptiveTimeoutManager(
+		&AdaptiveTimeoutConfig{
+			InitialTimeout:     time.Second,
+			MinimumTimeout:     time.Second,
+			MaximumTimeout:     time.Second,
+			TimeoutCoefficient: 1,
+			TimeoutHalflife:    5 * time.Minute,
+		},
+		"",
+		prometheus.NewRegistry(),
+	)
+	require.NoError(err)
+
+	now := time.Unix(1000, 0)
+	tm.SetTime(now)
+
+	timedOut := false
+	tm.Put(ids.GenerateTestID(), message.PullQuery, func() { timedOut = true })
+
+	// The timeout isn't executed before its deadline.
+	tm.SetTime(now.Add(time.Second - 1))
+	require.False(timedOut)
+
+	tm.SetTime(now.Add(time.Second))
+	require.True(timedOut)
+}
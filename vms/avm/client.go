@@ -33,6 +33,9 @@ type Client interface {
 	ConfirmTx(ctx context.Context, txID ids.ID, freq time.Duration, options ...rpc.Option) (choices.Status, error)
 	// GetTx returns the byte representation of [txID]
 	GetTx(ctx context.Context, txID ids.ID, options ...rpc.Option) ([]byte, error)
+	// GetTxs returns the byte representations of [txIDs]. If supported, the
+	// transactions are fetched in a single batch request.
+	GetTxs(ctx context.Context, txIDs []ids.ID, options ...rpc.Option) ([][]byte, error)
 	// IssueStopVertex issues a stop vertex.
 	IssueStopVertex(ctx context.Context, options ...rpc.Option) error
 	// GetUTXOs returns the byte representation of the UTXOs controlled by [addrs]
@@ -62,6 +65,29 @@ type Client interface {
 	GetBalance(ctx context.Context, addr ids.ShortID, assetID string, includePartial bool, options ...rpc.Option) (*GetBalanceReply, error)
 	// GetAllBalances returns all asset balances for [addr]
 	GetAllBalances(ctx context.Context, addr ids.ShortID, includePartial bool, options ...rpc.Option) ([]Balance, error)
+	// GetBalanceAt returns the balance of [assetID] held by [addr] after the
+	// transaction at [index] of the node's tx index was accepted.
+	// If [includePartial], balance includes partial owned (i.e. in a multisig) funds.
+	GetBalanceAt(
+		ctx context.Context,
+		addr ids.ShortID,
+		assetID string,
+		index uint64,
+		includePartial bool,
+		options ...rpc.Option,
+	) (*GetBalanceAtReply, error)
+	// GetUTXOsAt returns the byte representation of the UTXOs controlled by
+	// [addrs] after the transaction at [index] of the node's tx index was
+	// accepted
+	GetUTXOsAt(
+		ctx context.Context,
+		addrs []ids.ShortID,
+		index uint64,
+		limit uint32,
+		startAddress ids.ShortID,
+		startUTXOID ids.ID,
+		options ...rpc.Option,
+	) ([][]byte, ids.ShortID, ids.ID, error)
 	// CreateAsset creates a new asset and returns its assetID
 	CreateAsset(
 		ctx context.Context,
@@ -249,6 +275,47 @@ func (c *client) GetTx(ctx context.Context, txID ids.ID, options ...rpc.Option)
 	return txBytes, nil
 }
 
+func (c *client) GetTxs(ctx context.Context, txIDs []ids.ID, options ...rpc.Option) ([][]byte, error) {
+	batcher, ok := c.requester.(rpc.BatchRequester)
+	if !ok {
+		txs := make([][]byte, len(txIDs))
+		for i, txID := range txIDs {
+			tx, err := c.GetTx(ctx, txID, options...)
+			if err != nil {
+				return nil, err
+			}
+			txs[i] = tx
+		}
+		return txs, nil
+	}
+
+	batch := batcher.NewBatch()
+	replies := make([]*api.FormattedTx, len(txIDs))
+	for i, txID := range txIDs {
+		replies[i] = &api.FormattedTx{}
+		batch.Add("getTx", &api.GetTxArgs{
+			TxID:     txID,
+			Encoding: formatting.Hex,
+		}, replies[i])
+	}
+	errs, err := batch.Send(ctx, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	txs := make([][]byte, len(txIDs))
+	for i, res := range replies {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("couldn't get tx %s: %w", txIDs[i], errs[i])
+		}
+		txs[i], err = formatting.Decode(res.Encoding, res.Tx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return txs, nil
+}
+
 func (c *client) GetUTXOs(
 	ctx context.Context,
 	addrs []ids.ShortID,
@@ -338,6 +405,66 @@ func (c *client) GetAllBalances(
 	return res.Balances, err
 }
 
+func (c *client) GetBalanceAt(
+	ctx context.Context,
+	addr ids.ShortID,
+	assetID string,
+	index uint64,
+	includePartial bool,
+	options ...rpc.Option,
+) (*GetBalanceAtReply, error) {
+	res := &GetBalanceAtReply{}
+	err := c.requester.SendRequest(ctx, "getBalanceAt", &GetBalanceAtArgs{
+		GetBalanceArgs: GetBalanceArgs{
+			Address:        addr.String(),
+			AssetID:        assetID,
+			IncludePartial: includePartial,
+		},
+		Index: cjson.Uint64(index),
+	}, res, options...)
+	return res, err
+}
+
+func (c *client) GetUTXOsAt(
+	ctx context.Context,
+	addrs []ids.ShortID,
+	index uint64,
+	limit uint32,
+	startAddress ids.ShortID,
+	startUTXOID ids.ID,
+	options ...rpc.Option,
+) ([][]byte, ids.ShortID, ids.ID, error) {
+	res := &api.GetUTXOsReply{}
+	err := c.requester.SendRequest(ctx, "getUTXOsAt", &GetUTXOsAtArgs{
+		Addresses: ids.ShortIDsToStrings(addrs),
+		Index:     cjson.Uint64(index),
+		Limit:     cjson.Uint32(limit),
+		StartIndex: api.Index{
+			Address: startAddress.String(),
+			UTXO:    startUTXOID.String(),
+		},
+		Encoding: formatting.Hex,
+	}, res, options...)
+	if err != nil {
+		return nil, ids.ShortID{}, ids.Empty, err
+	}
+
+	utxos := make([][]byte, len(res.UTXOs))
+	for i, utxo := range res.UTXOs {
+		utxoBytes, err := formatting.Decode(res.Encoding, utxo)
+		if err != nil {
+			return nil, ids.ShortID{}, ids.Empty, err
+		}
+		utxos[i] = utxoBytes
+	}
+	endAddr, err := address.ParseToID(res.EndIndex.Address)
+	if err != nil {
+		return nil, ids.ShortID{}, ids.Empty, err
+	}
+	endUTXOID, err := ids.FromString(res.EndIndex.UTXO)
+	return utxos, endAddr, endUTXOID, err
+}
+
 // ClientHolder describes how much an address owns of an asset
 type ClientHolder struct {
 	Amount  uint64
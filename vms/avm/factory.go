@@ -6,8 +6,10 @@ package avm
 import (
 	"time"
 
+	"github.com/dim4egster/qmallgo/ids"
 	"github.com/dim4egster/qmallgo/snow"
 	"github.com/dim4egster/qmallgo/vms"
+	"github.com/dim4egster/qmallgo/vms/components/index"
 )
 
 var _ vms.Factory = &Factory{}
@@ -18,6 +20,14 @@ type Factory struct {
 
 	// Time of the Blueberry network upgrade
 	BlueberryTime time.Time
+
+	// If non-nil, the API services of the chains created by this factory are
+	// added to Services while the chains are bootstrapped.
+	Services *Services
+
+	// TxIndex returns the node's index of the accepted txs of a chain, which
+	// keys the UTXO history. Returns false if the chain isn't indexed.
+	TxIndex func(chainID ids.ID) (index.ContainerIndex, bool)
 }
 
 func (f *Factory) IsBlueberryActivated(timestamp time.Time) bool {
@@ -0,0 +1,307 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gavm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/dim4egster/qmallgo/api"
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/utils/formatting"
+	"github.com/dim4egster/qmallgo/utils/formatting/address"
+	"github.com/dim4egster/qmallgo/utils/json"
+	"github.com/dim4egster/qmallgo/vms/avm"
+
+	avmpb "github.com/dim4egster/qmallgo/proto/pb/avm"
+)
+
+// defaultPageSize is the number of UTXOs fetched at a time by StreamUTXOs if
+// the request doesn't specify a page size.
+const defaultPageSize = 1024
+
+var (
+	_ avmpb.AVMServer = &Server{}
+	_ Service         = &avm.Service{}
+
+	errUnexpectedTxType = errors.New("unexpected tx type")
+)
+
+// Service is the part of the avm API that is served over gRPC.
+type Service interface {
+	GetTx(r *http.Request, args *api.GetTxArgs, reply *api.GetTxReply) error
+	GetTxStatus(r *http.Request, args *api.JSONTxID, reply *avm.GetTxStatusReply) error
+	GetBalance(r *http.Request, args *avm.GetBalanceArgs, reply *avm.GetBalanceReply) error
+	GetAllBalances(r *http.Request, args *avm.GetAllBalancesArgs, reply *avm.GetAllBalancesReply) error
+	GetAssetDescription(r *http.Request, args *avm.GetAssetDescriptionArgs, reply *avm.GetAssetDescriptionReply) error
+	GetUTXOs(r *http.Request, args *api.GetUTXOsArgs, reply *api.GetUTXOsReply) error
+}
+
+// Server serves the read methods of the avm API over gRPC.
+type Server struct {
+	avmpb.UnsafeAVMServer
+
+	// call calls the function with the API service of the chain, holding the
+	// lock of the chain.
+	call func(func(Service) error) error
+}
+
+// NewServer returns a gRPC server that serves its requests with the API
+// service of the chain [chainID] in [services].
+func NewServer(services *avm.Services, chainID ids.ID) *Server {
+	return newServer(func(f func(Service) error) error {
+		err := services.Call(chainID, func(service *avm.Service) error {
+			return f(service)
+		})
+		if errors.Is(err, avm.ErrNotBootstrapped) {
+			return status.Error(codes.Unavailable, err.Error())
+		}
+		return err
+	})
+}
+
+func newServer(call func(func(Service) error) error) *Server {
+	return &Server{
+		call: call,
+	}
+}
+
+func (s *Server) GetTx(_ context.Context, req *avmpb.GetTxRequest) (*avmpb.GetTxResponse, error) {
+	txID, err := ids.ToID(req.TxId)
+	if err != nil {
+		return nil, err
+	}
+	reply := &api.GetTxReply{}
+	err = s.call(func(service Service) error {
+		return service.GetTx(nil, &api.GetTxArgs{
+			TxID:     txID,
+			Encoding: formatting.Hex,
+		}, reply)
+	})
+	if err != nil {
+		return nil, err
+	}
+	txStr, ok := reply.Tx.(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", errUnexpectedTxType, reply.Tx)
+	}
+	tx, err := formatting.Decode(reply.Encoding, txStr)
+	if err != nil {
+		return nil, err
+	}
+	return &avmpb.GetTxResponse{
+		Tx: tx,
+	}, nil
+}
+
+func (s *Server) GetTxStatus(_ context.Context, req *avmpb.GetTxStatusRequest) (*avmpb.GetTxStatusResponse, error) {
+	txID, err := ids.ToID(req.TxId)
+	if err != nil {
+		return nil, err
+	}
+	reply := &avm.GetTxStatusReply{}
+	err = s.call(func(service Service) error {
+		return service.GetTxStatus(nil, &api.JSONTxID{
+			TxID: txID,
+		}, reply)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &avmpb.GetTxStatusResponse{
+		Status: reply.Status.String(),
+	}, nil
+}
+
+func (s *Server) GetBalance(_ context.Context, req *avmpb.GetBalanceRequest) (*avmpb.GetBalanceResponse, error) {
+	addr, err := ids.ToShortID(req.Address)
+	if err != nil {
+		return nil, err
+	}
+	reply := &avm.GetBalanceReply{}
+	err = s.call(func(service Service) error {
+		return service.GetBalance(nil, &avm.GetBalanceArgs{
+			Address:        addr.String(),
+			AssetID:        req.AssetId,
+			IncludePartial: req.IncludePartial,
+		}, reply)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &avmpb.GetBalanceResponse{
+		Balance: uint64(reply.Balance),
+	}, nil
+}
+
+func (s *Server) GetAllBalances(_ context.Context, req *avmpb.GetAllBalancesRequest) (*avmpb.GetAllBalancesResponse, error) {
+	addr, err := ids.ToShortID(req.Address)
+	if err != nil {
+		return nil, err
+	}
+	reply := &avm.GetAllBalancesReply{}
+	err = s.call(func(service Service) error {
+		return service.GetAllBalances(nil, &avm.GetAllBalancesArgs{
+			JSONAddress:    api.JSONAddress{Address: addr.String()},
+			IncludePartial: req.IncludePartial,
+		}, reply)
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp := &avmpb.GetAllBalancesResponse{
+		Balances: make([]*avmpb.Balance, len(reply.Balances)),
+	}
+	for i, balance := range reply.Balances {
+		resp.Balances[i] = &avmpb.Balance{
+			AssetId: balance.AssetID,
+			Balance: uint64(balance.Balance),
+		}
+	}
+	return resp, nil
+}
+
+func (s *Server) GetAssetDescription(_ context.Context, req *avmpb.GetAssetDescriptionRequest) (*avmpb.GetAssetDescriptionResponse, error) {
+	reply := &avm.GetAssetDescriptionReply{}
+	err := s.call(func(service Service) error {
+		return service.GetAssetDescription(nil, &avm.GetAssetDescriptionArgs{
+			AssetID: req.AssetId,
+		}, reply)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &avmpb.GetAssetDescriptionResponse{
+		AssetId:      reply.AssetID[:],
+		Name:         reply.Name,
+		Symbol:       reply.Symbol,
+		Denomination: uint32(reply.Denomination),
+	}, nil
+}
+
+func (s *Server) GetUTXOs(_ context.Context, req *avmpb.GetUTXOsRequest) (*avmpb.GetUTXOsResponse, error) {
+	addrs, err := toShortIDs(req.Addresses)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		startAddr ids.ShortID
+		startUTXO ids.ID
+	)
+	if len(req.StartAddress) != 0 {
+		startAddr, err = ids.ToShortID(req.StartAddress)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(req.StartUtxoId) != 0 {
+		startUTXO, err = ids.ToID(req.StartUtxoId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	utxos, endAddr, endUTXO, err := s.getUTXOs(addrs, req.SourceChain, req.Limit, startAddr, startUTXO)
+	if err != nil {
+		return nil, err
+	}
+	return &avmpb.GetUTXOsResponse{
+		Utxos:      utxos,
+		EndAddress: endAddr[:],
+		EndUtxoId:  endUTXO[:],
+	}, nil
+}
+
+func (s *Server) StreamUTXOs(req *avmpb.StreamUTXOsRequest, stream avmpb.AVM_StreamUTXOsServer) error {
+	addrs, err := toShortIDs(req.Addresses)
+	if err != nil {
+		return err
+	}
+	pageSize := req.PageSize
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+
+	var (
+		startAddr ids.ShortID
+		startUTXO ids.ID
+	)
+	for {
+		utxos, endAddr, endUTXO, err := s.getUTXOs(addrs, req.SourceChain, pageSize, startAddr, startUTXO)
+		if err != nil {
+			return err
+		}
+		for _, utxo := range utxos {
+			if err := stream.Send(&avmpb.UTXO{Bytes: utxo}); err != nil {
+				return err
+			}
+		}
+		// The service may return fewer UTXOs than requested even if more
+		// remain, so the stream only ends once no more progress is made.
+		if len(utxos) == 0 || (endAddr == startAddr && endUTXO == startUTXO) {
+			return nil
+		}
+
+		// Update the vars to query the next page of UTXOs.
+		startAddr = endAddr
+		startUTXO = endUTXO
+	}
+}
+
+// getUTXOs fetches a page of UTXOs from the API service of the chain.
+func (s *Server) getUTXOs(
+	addrs []ids.ShortID,
+	sourceChain string,
+	limit uint32,
+	startAddr ids.ShortID,
+	startUTXO ids.ID,
+) ([][]byte, ids.ShortID, ids.ID, error) {
+	reply := &api.GetUTXOsReply{}
+	err := s.call(func(service Service) error {
+		return service.GetUTXOs(nil, &api.GetUTXOsArgs{
+			Addresses:   ids.ShortIDsToStrings(addrs),
+			SourceChain: sourceChain,
+			Limit:       json.Uint32(limit),
+			StartIndex: api.Index{
+				Address: startAddr.String(),
+				UTXO:    startUTXO.String(),
+			},
+			Encoding: formatting.Hex,
+		}, reply)
+	})
+	if err != nil {
+		return nil, ids.ShortEmpty, ids.Empty, err
+	}
+
+	utxos := make([][]byte, len(reply.UTXOs))
+	for i, utxo := range reply.UTXOs {
+		utxos[i], err = formatting.Decode(reply.Encoding, utxo)
+		if err != nil {
+			return nil, ids.ShortEmpty, ids.Empty, err
+		}
+	}
+	endAddr, err := address.ParseToID(reply.EndIndex.Address)
+	if err != nil {
+		return nil, ids.ShortEmpty, ids.Empty, err
+	}
+	endUTXO, err := ids.FromString(reply.EndIndex.UTXO)
+	return utxos, endAddr, endUTXO, err
+}
+
+func toShortIDs(addrsBytes [][]byte) ([]ids.ShortID, error) {
+	addrs := make([]ids.ShortID, len(addrsBytes))
+	for i, addrBytes := range addrsBytes {
+		addr, err := ids.ToShortID(addrBytes)
+		if err != nil {
+			return nil, err
+		}
+		addrs[i] = addr
+	}
+	return addrs, nil
+}
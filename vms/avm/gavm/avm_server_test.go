@@ -0,0 +1,209 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gavm
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/dim4egster/qmallgo/api"
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/utils/constants"
+	"github.com/dim4egster/qmallgo/utils/formatting"
+	"github.com/dim4egster/qmallgo/utils/formatting/address"
+	"github.com/dim4egster/qmallgo/utils/json"
+	"github.com/dim4egster/qmallgo/vms/avm"
+	"github.com/dim4egster/qmallgo/vms/rpcchainvm/grpcutils"
+
+	avmpb "github.com/dim4egster/qmallgo/proto/pb/avm"
+)
+
+const (
+	bufSize = 1024 * 1024
+
+	// maxPageSize is the number of UTXOs the service returns at most, even if
+	// more are requested.
+	maxPageSize = 1024
+)
+
+var (
+	_ Service = &testService{}
+
+	errNotImplemented = errors.New("not implemented")
+)
+
+// testService holds [numUTXOs] UTXOs of the address [addr]. The UTXO at
+// position i has ID i+1 so that the empty ID starts from the beginning.
+type testService struct {
+	addr          ids.ShortID
+	numUTXOs      int
+	getUTXOsCalls int
+}
+
+func (*testService) GetTx(*http.Request, *api.GetTxArgs, *api.GetTxReply) error {
+	return errNotImplemented
+}
+
+func (*testService) GetTxStatus(*http.Request, *api.JSONTxID, *avm.GetTxStatusReply) error {
+	return errNotImplemented
+}
+
+func (*testService) GetBalance(*http.Request, *avm.GetBalanceArgs, *avm.GetBalanceReply) error {
+	return errNotImplemented
+}
+
+func (*testService) GetAllBalances(*http.Request, *avm.GetAllBalancesArgs, *avm.GetAllBalancesReply) error {
+	return errNotImplemented
+}
+
+func (*testService) GetAssetDescription(*http.Request, *avm.GetAssetDescriptionArgs, *avm.GetAssetDescriptionReply) error {
+	return errNotImplemented
+}
+
+func (s *testService) GetUTXOs(_ *http.Request, args *api.GetUTXOsArgs, reply *api.GetUTXOsReply) error {
+	s.getUTXOsCalls++
+
+	startUTXO, err := ids.FromString(args.StartIndex.UTXO)
+	if err != nil {
+		return err
+	}
+	start := int(binary.BigEndian.Uint64(startUTXO[:]))
+
+	limit := int(args.Limit)
+	if limit <= 0 || limit > maxPageSize {
+		limit = maxPageSize
+	}
+	end := start + limit
+	if end > s.numUTXOs {
+		end = s.numUTXOs
+	}
+
+	endUTXO := startUTXO
+	reply.UTXOs = nil
+	for i := start; i < end; i++ {
+		utxo := make([]byte, 8)
+		binary.BigEndian.PutUint64(utxo, uint64(i))
+		utxoStr, err := formatting.Encode(args.Encoding, utxo)
+		if err != nil {
+			return err
+		}
+		reply.UTXOs = append(reply.UTXOs, utxoStr)
+		endUTXO = ids.Empty
+		binary.BigEndian.PutUint64(endUTXO[:], uint64(i+1))
+	}
+
+	reply.EndIndex.Address, err = address.Format("X", constants.UnitTestHRP, s.addr[:])
+	if err != nil {
+		return err
+	}
+	reply.EndIndex.UTXO = endUTXO.String()
+	reply.NumFetched = json.Uint64(len(reply.UTXOs))
+	reply.Encoding = args.Encoding
+	return nil
+}
+
+func newTestAVMClient(t *testing.T, service Service) avmpb.AVMClient {
+	listener := bufconn.Listen(bufSize)
+	serverCloser := grpcutils.ServerCloser{}
+	serverFunc := func(opts []grpc.ServerOption) *grpc.Server {
+		server := grpc.NewServer(opts...)
+		avmpb.RegisterAVMServer(server, newServer(func(f func(Service) error) error {
+			return f(service)
+		}))
+		serverCloser.Add(server)
+		return server
+	}
+	go grpcutils.Serve(listener, serverFunc)
+
+	dialer := grpc.WithContextDialer(
+		func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	)
+	dopts := grpcutils.DefaultDialOptions
+	dopts = append(dopts, dialer)
+	conn, err := grpcutils.Dial("", dopts...)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		serverCloser.Stop()
+		_ = conn.Close()
+		_ = listener.Close()
+	})
+	return avmpb.NewAVMClient(conn)
+}
+
+func TestStreamUTXOs(t *testing.T) {
+	tests := []struct {
+		name          string
+		numUTXOs      int
+		pageSize      uint32
+		getUTXOsCalls int
+	}{
+		{
+			name:          "no UTXOs",
+			numUTXOs:      0,
+			pageSize:      0,
+			getUTXOsCalls: 1,
+		},
+		{
+			name:          "default page size",
+			numUTXOs:      2*maxPageSize + 10,
+			pageSize:      0,
+			getUTXOsCalls: 4,
+		},
+		{
+			name:          "page size clamped by the service",
+			numUTXOs:      2*maxPageSize + 10,
+			pageSize:      2 * maxPageSize,
+			getUTXOsCalls: 4,
+		},
+		{
+			name:          "small page size",
+			numUTXOs:      25,
+			pageSize:      10,
+			getUTXOsCalls: 4,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			service := &testService{
+				addr:     ids.GenerateTestShortID(),
+				numUTXOs: test.numUTXOs,
+			}
+			client := newTestAVMClient(t, service)
+
+			stream, err := client.StreamUTXOs(context.Background(), &avmpb.StreamUTXOsRequest{
+				Addresses: [][]byte{service.addr[:]},
+				PageSize:  test.pageSize,
+			})
+			require.NoError(err)
+
+			next := 0
+			for {
+				utxo, err := stream.Recv()
+				if err == io.EOF {
+					break
+				}
+				require.NoError(err)
+				require.Equal(uint64(next), binary.BigEndian.Uint64(utxo.Bytes))
+				next++
+			}
+			require.Equal(test.numUTXOs, next)
+			require.Equal(test.getUTXOsCalls, service.getUTXOsCalls)
+		})
+	}
+}
@@ -7,7 +7,9 @@ import (
 	"encoding/binary"
 	"encoding/json"
 	"fmt"
+	"math"
 	"testing"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 
@@ -23,6 +25,7 @@ import (
 	"github.com/dim4egster/qmallgo/snow"
 	"github.com/dim4egster/qmallgo/snow/engine/common"
 	"github.com/dim4egster/qmallgo/utils/crypto"
+	"github.com/dim4egster/qmallgo/utils/crypto/keychain"
 	"github.com/dim4egster/qmallgo/utils/wrappers"
 	"github.com/dim4egster/qmallgo/version"
 	"github.com/dim4egster/qmallgo/vms/avm/txs"
@@ -486,7 +489,7 @@ func buildPlatformUTXO(utxoID avax.UTXOID, txAssetID avax.Asset, addr ids.ShortI
 }
 
 func signTX(codec codec.Manager, tx *txs.Tx, key *crypto.PrivateKeySECP256K1R) error {
-	return tx.SignSECP256K1Fx(codec, [][]*crypto.PrivateKeySECP256K1R{{key}})
+	return tx.SignSECP256K1Fx(codec, [][]keychain.Signer{{key}})
 }
 
 func buildTX(utxoID avax.UTXOID, txAssetID avax.Asset, address ...ids.ShortID) *txs.Tx {
@@ -516,8 +519,125 @@ func buildTX(utxoID avax.UTXOID, txAssetID avax.Asset, address ...ids.ShortID) *
 	}}
 }
 
+func TestIndexUTXOHistory_Genesis(t *testing.T) {
+	require := require.New(t)
+
+	genesisBytes := BuildGenesisTest(t)
+	issuer := make(chan common.Message, 1)
+	baseDBManager := manager.NewMemDB(version.Semantic1_0_0)
+	ctx := NewContext(t)
+	vm := setupTestVM(t, ctx, baseDBManager, genesisBytes, issuer, Config{IndexUTXOHistory: true})
+	ctx.Lock.Lock()
+	defer func() {
+		require.NoError(vm.Shutdown())
+		ctx.Lock.Unlock()
+	}()
+
+	addr := keys[0].PublicKey().Address()
+	addrs := ids.ShortSet{}
+	addrs.Add(addr)
+	expectedUTXOs, err := avax.GetAllUTXOs(vm.state, addrs)
+	require.NoError(err)
+	require.NotEmpty(expectedUTXOs)
+
+	// Nothing can be queried until the node's tx index accepted a tx
+	_, _, err = vm.utxoHistoryIndexer.UTXOs(addr[:], 0, ids.Empty, math.MaxInt)
+	require.Error(err)
+
+	// Genesis UTXOs exist at every index
+	txID := ids.GenerateTestID()
+	vm.txIndex.(*testTxIndex).indices[txID] = 0
+	require.NoError(vm.indexUTXOHistory(txID, nil, nil))
+
+	utxos, _, err := vm.utxoHistoryIndexer.UTXOs(addr[:], 0, ids.Empty, math.MaxInt)
+	require.NoError(err)
+	require.Len(utxos, len(expectedUTXOs))
+	expectedUTXOIDs := ids.Set{}
+	for _, utxo := range expectedUTXOs {
+		expectedUTXOIDs.Add(utxo.InputID())
+	}
+	for _, utxo := range utxos {
+		require.True(expectedUTXOIDs.Contains(utxo.InputID()))
+	}
+}
+
+func TestIndexUTXOHistory_TxIndex(t *testing.T) {
+	require := require.New(t)
+
+	genesisBytes := BuildGenesisTest(t)
+	issuer := make(chan common.Message, 1)
+	baseDBManager := manager.NewMemDB(version.Semantic1_0_0)
+	ctx := NewContext(t)
+	vm := setupTestVM(t, ctx, baseDBManager, genesisBytes, issuer, Config{IndexUTXOHistory: true})
+	ctx.Lock.Lock()
+	defer func() {
+		require.NoError(vm.Shutdown())
+		ctx.Lock.Unlock()
+	}()
+
+	// The history is keyed by the index and accept time of the node's tx
+	// index
+	txIndex := vm.txIndex.(*testTxIndex)
+	txIndex.acceptedAt = time.Unix(1_000, 0)
+
+	addr := ids.GenerateTestShortID()
+	utxo := &avax.UTXO{
+		UTXOID: avax.UTXOID{TxID: ids.GenerateTestID()},
+		Asset:  avax.Asset{ID: ids.GenerateTestID()},
+		Out: &secp256k1fx.TransferOutput{
+			Amt: 1,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{addr},
+			},
+		},
+	}
+	createTxID := ids.GenerateTestID()
+	txIndex.indices[createTxID] = 7
+	require.NoError(vm.indexUTXOHistory(createTxID, nil, []*avax.UTXO{utxo}))
+
+	acceptedTxID, timestamp, err := vm.utxoHistoryIndexer.Accepted(7)
+	require.NoError(err)
+	require.Equal(createTxID, acceptedTxID)
+	require.Equal(uint64(1_000), timestamp)
+
+	utxos, _, err := vm.utxoHistoryIndexer.UTXOs(addr[:], 7, ids.Empty, math.MaxInt)
+	require.NoError(err)
+	require.Len(utxos, 1)
+
+	utxos, _, err = vm.utxoHistoryIndexer.UTXOs(addr[:], 6, ids.Empty, math.MaxInt)
+	require.NoError(err)
+	require.Empty(utxos)
+
+	// Txs the node's tx index didn't accept can't be indexed
+	require.Error(vm.indexUTXOHistory(ids.GenerateTestID(), nil, nil))
+
+	// The UTXO history can't be indexed without the node's tx index
+	vm.txIndex = nil
+	vm.TxIndex = nil
+	require.ErrorIs(vm.SetState(snow.Bootstrapping), errMissingTxIndex)
+}
+
+// testTxIndex accepts its txs at [indices], all at [acceptedAt]
+type testTxIndex struct {
+	indices    map[ids.ID]uint64
+	acceptedAt time.Time
+}
+
+func (i *testTxIndex) GetAccepted(txID ids.ID) (uint64, time.Time, error) {
+	index, ok := i.indices[txID]
+	if !ok {
+		return 0, time.Time{}, database.ErrNotFound
+	}
+	return index, i.acceptedAt, nil
+}
+
 func setupTestVM(t *testing.T, ctx *snow.Context, baseDBManager manager.Manager, genesisBytes []byte, issuer chan common.Message, config Config) *VM {
-	vm := &VM{}
+	vm := &VM{Factory: Factory{
+		TxIndex: func(ids.ID) (index.ContainerIndex, bool) {
+			return &testTxIndex{indices: map[ids.ID]uint64{}}, true
+		},
+	}}
 	avmConfigBytes, err := json.Marshal(config)
 	require.NoError(t, err)
 	appSender := &common.SenderTest{T: t}
@@ -4,41 +4,68 @@
 package avm
 
 import (
-	"github.com/dim4egster/qmallgo/api"
 	"github.com/dim4egster/qmallgo/pubsub"
 	"github.com/dim4egster/qmallgo/vms/avm/txs"
 	"github.com/dim4egster/qmallgo/vms/components/avax"
+	"github.com/dim4egster/qmallgo/vms/secp256k1fx"
 )
 
-var _ pubsub.Filterer = &filterer{}
-
-type filterer struct {
-	tx *txs.Tx
-}
+var _ txs.Visitor = &txAttributesGetter{}
 
+// NewPubSubFilterer returns a Filterer that notifies the connections that are
+// interested in [tx] of its ID.
 func NewPubSubFilterer(tx *txs.Tx) pubsub.Filterer {
-	return &filterer{tx: tx}
-}
-
-// Apply the filter on the addresses.
-func (f *filterer) Filter(filters []pubsub.Filter) ([]bool, interface{}) {
-	resp := make([]bool, len(filters))
-	for _, utxo := range f.tx.UTXOs() {
-		addressable, ok := utxo.Out.(avax.Addressable)
-		if !ok {
-			continue
+	attrs := &pubsub.TxAttributes{}
+	attrs.AssetIDs.Union(tx.Unsigned.AssetIDs())
+	for _, utxo := range tx.UTXOs() {
+		attrs.AssetIDs.Add(utxo.AssetID())
+		if addressable, ok := utxo.Out.(avax.Addressable); ok {
+			attrs.Addresses = append(attrs.Addresses, addressable.Addresses()...)
 		}
-
-		for _, address := range addressable.Addresses() {
-			for i, c := range filters {
-				if resp[i] {
-					continue
-				}
-				resp[i] = c.Check(address)
+		if owned, ok := utxo.Out.(interface{ Owners() interface{} }); ok {
+			if owners, ok := owned.Owners().(*secp256k1fx.OutputOwners); ok {
+				attrs.Thresholds = append(attrs.Thresholds, owners.Threshold)
 			}
 		}
 	}
-	return resp, api.JSONTxID{
-		TxID: f.tx.ID(),
-	}
+
+	// The visit error is explicitly dropped here because no error is ever
+	// returned from the txAttributesGetter.
+	_ = tx.Unsigned.Visit(&txAttributesGetter{attrs: attrs})
+	return pubsub.NewTxIDFilterer(tx.ID(), attrs)
+}
+
+// txAttributesGetter populates the type and memo of a tx's attributes.
+type txAttributesGetter struct {
+	attrs *pubsub.TxAttributes
+}
+
+func (g *txAttributesGetter) BaseTx(tx *txs.BaseTx) error {
+	g.set("BaseTx", tx)
+	return nil
+}
+
+func (g *txAttributesGetter) CreateAssetTx(tx *txs.CreateAssetTx) error {
+	g.set("CreateAssetTx", &tx.BaseTx)
+	return nil
+}
+
+func (g *txAttributesGetter) OperationTx(tx *txs.OperationTx) error {
+	g.set("OperationTx", &tx.BaseTx)
+	return nil
+}
+
+func (g *txAttributesGetter) ImportTx(tx *txs.ImportTx) error {
+	g.set("ImportTx", &tx.BaseTx)
+	return nil
+}
+
+func (g *txAttributesGetter) ExportTx(tx *txs.ExportTx) error {
+	g.set("ExportTx", &tx.BaseTx)
+	return nil
+}
+
+func (g *txAttributesGetter) set(txType string, tx *txs.BaseTx) {
+	g.attrs.TxType = txType
+	g.attrs.Memo = tx.Memo
 }
@@ -49,3 +49,44 @@ func TestFilter(t *testing.T) {
 	fr, _ := parser.Filter([]pubsub.Filter{&mockFilter{addr: addrBytes}})
 	require.Equal([]bool{true}, fr)
 }
+
+type attrsRecorder struct {
+	mockFilter
+	attrs *pubsub.TxAttributes
+}
+
+func (r *attrsRecorder) Match(attrs *pubsub.TxAttributes) bool {
+	r.attrs = attrs
+	return false
+}
+
+func TestFilterTxAttributes(t *testing.T) {
+	require := require.New(t)
+
+	assetID := ids.ID{2}
+	addrID := ids.ShortID{1}
+	memo := []byte("memo")
+	tx := txs.Tx{Unsigned: &txs.ExportTx{BaseTx: txs.BaseTx{BaseTx: avax.BaseTx{
+		Outs: []*avax.TransferableOutput{
+			{
+				Asset: avax.Asset{ID: assetID},
+				Out: &secp256k1fx.TransferOutput{
+					OutputOwners: secp256k1fx.OutputOwners{
+						Threshold: 1,
+						Addrs:     []ids.ShortID{addrID},
+					},
+				},
+			},
+		},
+		Memo: memo,
+	}}}}
+
+	recorder := &attrsRecorder{}
+	fr, _ := NewPubSubFilterer(&tx).Filter([]pubsub.Filter{recorder})
+	require.Equal([]bool{false}, fr)
+	require.Equal("ExportTx", recorder.attrs.TxType)
+	require.True(recorder.attrs.AssetIDs.Contains(assetID))
+	require.Equal([][]byte{addrID[:]}, recorder.attrs.Addresses)
+	require.Equal([]uint32{1}, recorder.attrs.Thresholds)
+	require.Equal(memo, recorder.attrs.Memo)
+}
@@ -4,6 +4,7 @@
 package avm
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"math"
@@ -15,6 +16,7 @@ import (
 	"github.com/dim4egster/qmallgo/ids"
 	"github.com/dim4egster/qmallgo/snow/choices"
 	"github.com/dim4egster/qmallgo/utils/crypto"
+	"github.com/dim4egster/qmallgo/utils/crypto/keychain"
 	"github.com/dim4egster/qmallgo/utils/formatting"
 	"github.com/dim4egster/qmallgo/utils/json"
 	"github.com/dim4egster/qmallgo/utils/logging"
@@ -502,6 +504,190 @@ func (service *Service) GetAllBalances(r *http.Request, args *GetAllBalancesArgs
 	return nil
 }
 
+// GetBalanceAtArgs are arguments for calling into GetBalanceAt
+type GetBalanceAtArgs struct {
+	GetBalanceArgs
+	Index json.Uint64 `json:"index"`
+}
+
+// GetBalanceAtReply defines the GetBalanceAt replies returned from the API
+type GetBalanceAtReply struct {
+	GetBalanceReply
+	// Timestamp is the unix time at which the node's tx index accepted the
+	// transaction at the requested index
+	Timestamp json.Uint64 `json:"timestamp"`
+}
+
+// GetBalanceAt returns the balance of an asset held by an address after the
+// transaction at [args.Index] of the node's tx index was accepted. Requires
+// the UTXO history to be indexed.
+// If ![args.IncludePartial], returns only the balance held solely
+// (1 out of 1 multisig) by the address and with a locktime before the
+// acceptance of the transaction at [args.Index].
+func (service *Service) GetBalanceAt(_ *http.Request, args *GetBalanceAtArgs, reply *GetBalanceAtReply) error {
+	service.vm.ctx.Log.Debug("AVM: GetBalanceAt called",
+		logging.UserString("address", args.Address),
+		logging.UserString("assetID", args.AssetID),
+		zap.Uint64("index", uint64(args.Index)),
+	)
+
+	addr, err := avax.ParseServiceAddress(service.vm, args.Address)
+	if err != nil {
+		return fmt.Errorf("problem parsing address '%s': %w", args.Address, err)
+	}
+
+	assetID, err := service.vm.lookupAssetID(args.AssetID)
+	if err != nil {
+		return err
+	}
+
+	index := uint64(args.Index)
+	utxos, _, err := service.vm.utxoHistoryIndexer.UTXOs(addr[:], index, ids.Empty, math.MaxInt)
+	if err != nil {
+		return fmt.Errorf("problem retrieving UTXOs at index %d: %w", index, err)
+	}
+	_, timestamp, err := service.vm.utxoHistoryIndexer.Accepted(index)
+	if err != nil {
+		return fmt.Errorf("problem retrieving acceptance time of index %d: %w", index, err)
+	}
+
+	reply.Timestamp = json.Uint64(timestamp)
+	reply.UTXOIDs = make([]avax.UTXOID, 0, len(utxos))
+	for _, utxo := range utxos {
+		if utxo.AssetID() != assetID {
+			continue
+		}
+		// TODO make this not specific to *secp256k1fx.TransferOutput
+		transferable, ok := utxo.Out.(*secp256k1fx.TransferOutput)
+		if !ok {
+			continue
+		}
+		owners := transferable.OutputOwners
+		if !args.IncludePartial && (len(owners.Addrs) != 1 || owners.Locktime > timestamp) {
+			continue
+		}
+		amt, err := safemath.Add64(transferable.Amount(), uint64(reply.Balance))
+		if err != nil {
+			return err
+		}
+		reply.Balance = json.Uint64(amt)
+		reply.UTXOIDs = append(reply.UTXOIDs, utxo.UTXOID)
+	}
+
+	return nil
+}
+
+// GetUTXOsAtArgs are arguments for passing into GetUTXOsAt.
+// Gets the UTXOs that referenced at least one address in [Addresses] after the
+// transaction at [Index] of the node's tx index was accepted.
+// Pagination works as described in api.GetUTXOsArgs.
+type GetUTXOsAtArgs struct {
+	Addresses  []string            `json:"addresses"`
+	Index      json.Uint64         `json:"index"`
+	Limit      json.Uint32         `json:"limit"`
+	StartIndex api.Index           `json:"startIndex"`
+	Encoding   formatting.Encoding `json:"encoding"`
+}
+
+// GetUTXOsAt gets the UTXOs that referenced at least one address in
+// [args.Addresses] after the transaction at [args.Index] of the node's tx index
+// was accepted.
+// Requires the UTXO history to be indexed.
+func (service *Service) GetUTXOsAt(_ *http.Request, args *GetUTXOsAtArgs, reply *api.GetUTXOsReply) error {
+	service.vm.ctx.Log.Debug("AVM: GetUTXOsAt called",
+		logging.UserStrings("addresses", args.Addresses),
+		zap.Uint64("index", uint64(args.Index)),
+	)
+
+	if len(args.Addresses) == 0 {
+		return errNoAddresses
+	}
+	if len(args.Addresses) > maxGetUTXOsAddrs {
+		return fmt.Errorf("number of addresses given, %d, exceeds maximum, %d", len(args.Addresses), maxGetUTXOsAddrs)
+	}
+
+	addrSet, err := avax.ParseServiceAddresses(service.vm, args.Addresses)
+	if err != nil {
+		return err
+	}
+
+	startAddr := ids.ShortEmpty
+	startUTXO := ids.Empty
+	if args.StartIndex.Address != "" || args.StartIndex.UTXO != "" {
+		startAddr, err = avax.ParseServiceAddress(service.vm, args.StartIndex.Address)
+		if err != nil {
+			return fmt.Errorf("couldn't parse start index address %q: %w", args.StartIndex.Address, err)
+		}
+		startUTXO, err = ids.FromString(args.StartIndex.UTXO)
+		if err != nil {
+			return fmt.Errorf("couldn't parse start index utxo: %w", err)
+		}
+	}
+
+	limit := int(args.Limit)
+	if limit <= 0 || int(maxPageSize) < limit {
+		limit = int(maxPageSize)
+	}
+
+	var (
+		utxos     []*avax.UTXO
+		seen      ids.Set
+		endAddr   = startAddr
+		endUTXOID = startUTXO
+		index     = uint64(args.Index)
+	)
+	for _, addr := range addrSet.SortedList() {
+		start := ids.Empty
+		if comp := bytes.Compare(addr[:], startAddr[:]); comp == -1 {
+			continue
+		} else if comp == 0 {
+			start = startUTXO
+		}
+		if len(utxos) >= limit {
+			break
+		}
+
+		addrUTXOs, lastUTXOID, err := service.vm.utxoHistoryIndexer.UTXOs(addr[:], index, start, limit-len(utxos))
+		if err != nil {
+			return fmt.Errorf("problem retrieving UTXOs at index %d: %w", index, err)
+		}
+		endAddr = addr
+		endUTXOID = lastUTXOID
+		for _, utxo := range addrUTXOs {
+			utxoID := utxo.InputID()
+			if seen.Contains(utxoID) {
+				continue
+			}
+			seen.Add(utxoID)
+			utxos = append(utxos, utxo)
+		}
+	}
+
+	reply.UTXOs = make([]string, len(utxos))
+	codec := service.vm.parser.Codec()
+	for i, utxo := range utxos {
+		b, err := codec.Marshal(txs.CodecVersion, utxo)
+		if err != nil {
+			return fmt.Errorf("problem marshalling UTXO: %w", err)
+		}
+		reply.UTXOs[i], err = formatting.Encode(args.Encoding, b)
+		if err != nil {
+			return fmt.Errorf("couldn't encode UTXO %s as string: %w", utxo.InputID(), err)
+		}
+	}
+
+	endAddress, err := service.vm.FormatLocalAddress(endAddr)
+	if err != nil {
+		return fmt.Errorf("problem formatting address: %w", err)
+	}
+
+	reply.EndIndex.Address = endAddress
+	reply.EndIndex.UTXO = endUTXOID.String()
+	reply.NumFetched = json.Uint64(len(utxos))
+	reply.Encoding = args.Encoding
+	return nil
+}
+
 // Holder describes how much an address owns of an asset
 type Holder struct {
 	Amount  json.Uint64 `json:"amount"`
@@ -556,10 +742,10 @@ func (service *Service) CreateAsset(r *http.Request, args *CreateAssetArgs, repl
 	}
 
 	// Parse the change address.
-	if len(kc.Keys) == 0 {
+	if len(kc.Addrs) == 0 {
 		return errNoKeys
 	}
-	changeAddr, err := service.vm.selectChangeAddr(kc.Keys[0].PublicKey().Address(), args.ChangeAddr)
+	changeAddr, err := service.vm.selectChangeAddr(kc.Addrs[0], args.ChangeAddr)
 	if err != nil {
 		return err
 	}
@@ -705,10 +891,10 @@ func (service *Service) CreateNFTAsset(r *http.Request, args *CreateNFTAssetArgs
 	}
 
 	// Parse the change address.
-	if len(kc.Keys) == 0 {
+	if len(kc.Addrs) == 0 {
 		return errNoKeys
 	}
-	changeAddr, err := service.vm.selectChangeAddr(kc.Keys[0].PublicKey().Address(), args.ChangeAddr)
+	changeAddr, err := service.vm.selectChangeAddr(kc.Addrs[0], args.ChangeAddr)
 	if err != nil {
 		return err
 	}
@@ -997,10 +1183,10 @@ func (service *Service) SendMultiple(r *http.Request, args *SendMultipleArgs, re
 	}
 
 	// Parse the change address.
-	if len(kc.Keys) == 0 {
+	if len(kc.Addrs) == 0 {
 		return errNoKeys
 	}
-	changeAddr, err := service.vm.selectChangeAddr(kc.Keys[0].PublicKey().Address(), args.ChangeAddr)
+	changeAddr, err := service.vm.selectChangeAddr(kc.Addrs[0], args.ChangeAddr)
 	if err != nil {
 		return err
 	}
@@ -1153,10 +1339,10 @@ func (service *Service) Mint(r *http.Request, args *MintArgs, reply *api.JSONTxI
 	}
 
 	// Parse the change address.
-	if len(feeKc.Keys) == 0 {
+	if len(feeKc.Addrs) == 0 {
 		return errNoKeys
 	}
-	changeAddr, err := service.vm.selectChangeAddr(feeKc.Keys[0].PublicKey().Address(), args.ChangeAddr)
+	changeAddr, err := service.vm.selectChangeAddr(feeKc.Addrs[0], args.ChangeAddr)
 	if err != nil {
 		return err
 	}
@@ -1268,10 +1454,10 @@ func (service *Service) SendNFT(r *http.Request, args *SendNFTArgs, reply *api.J
 	}
 
 	// Parse the change address.
-	if len(kc.Keys) == 0 {
+	if len(kc.Addrs) == 0 {
 		return errNoKeys
 	}
-	changeAddr, err := service.vm.selectChangeAddr(kc.Keys[0].PublicKey().Address(), args.ChangeAddr)
+	changeAddr, err := service.vm.selectChangeAddr(kc.Addrs[0], args.ChangeAddr)
 	if err != nil {
 		return err
 	}
@@ -1382,10 +1568,10 @@ func (service *Service) MintNFT(r *http.Request, args *MintNFTArgs, reply *api.J
 	}
 
 	// Parse the change address.
-	if len(feeKc.Keys) == 0 {
+	if len(feeKc.Addrs) == 0 {
 		return errNoKeys
 	}
-	changeAddr, err := service.vm.selectChangeAddr(feeKc.Keys[0].PublicKey().Address(), args.ChangeAddr)
+	changeAddr, err := service.vm.selectChangeAddr(feeKc.Addrs[0], args.ChangeAddr)
 	if err != nil {
 		return err
 	}
@@ -1494,7 +1680,7 @@ func (service *Service) Import(_ *http.Request, args *ImportArgs, reply *api.JSO
 		return err
 	}
 
-	atomicUTXOs, _, _, err := service.vm.GetAtomicUTXOs(chainID, kc.Addrs, ids.ShortEmpty, ids.Empty, int(maxPageSize))
+	atomicUTXOs, _, _, err := service.vm.GetAtomicUTXOs(chainID, kc.Addresses(), ids.ShortEmpty, ids.Empty, int(maxPageSize))
 	if err != nil {
 		return fmt.Errorf("problem retrieving user's atomic UTXOs: %w", err)
 	}
@@ -1505,7 +1691,7 @@ func (service *Service) Import(_ *http.Request, args *ImportArgs, reply *api.JSO
 	}
 
 	ins := []*avax.TransferableInput{}
-	keys := [][]*crypto.PrivateKeySECP256K1R{}
+	keys := [][]keychain.Signer{}
 
 	if amountSpent := amountsSpent[service.vm.feeAssetID]; amountSpent < service.vm.TxFee {
 		var localAmountsSpent map[ids.ID]uint64
@@ -1636,10 +1822,10 @@ func (service *Service) Export(_ *http.Request, args *ExportArgs, reply *api.JSO
 	}
 
 	// Parse the change address.
-	if len(kc.Keys) == 0 {
+	if len(kc.Addrs) == 0 {
 		return errNoKeys
 	}
-	changeAddr, err := service.vm.selectChangeAddr(kc.Keys[0].PublicKey().Address(), args.ChangeAddr)
+	changeAddr, err := service.vm.selectChangeAddr(kc.Addrs[0], args.ChangeAddr)
 	if err != nil {
 		return err
 	}
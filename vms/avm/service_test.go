@@ -19,12 +19,14 @@ import (
 	"github.com/dim4egster/qmallgo/api"
 	"github.com/dim4egster/qmallgo/chains/atomic"
 	"github.com/dim4egster/qmallgo/database/manager"
+	"github.com/dim4egster/qmallgo/database/memdb"
 	"github.com/dim4egster/qmallgo/ids"
 	"github.com/dim4egster/qmallgo/snow"
 	"github.com/dim4egster/qmallgo/snow/choices"
 	"github.com/dim4egster/qmallgo/snow/engine/common"
 	"github.com/dim4egster/qmallgo/utils/constants"
 	"github.com/dim4egster/qmallgo/utils/crypto"
+	"github.com/dim4egster/qmallgo/utils/crypto/keychain"
 	"github.com/dim4egster/qmallgo/utils/formatting"
 	"github.com/dim4egster/qmallgo/utils/formatting/address"
 	"github.com/dim4egster/qmallgo/utils/json"
@@ -467,6 +469,112 @@ func TestServiceGetTxs(t *testing.T) {
 	require.Equal(t, getTxsReply.TxIDs, testTxs[10:20])
 }
 
+func TestServiceGetBalanceAt(t *testing.T) {
+	require := require.New(t)
+
+	_, vm, s, _, _ := setup(t, true)
+	defer func() {
+		require.NoError(vm.Shutdown())
+		vm.ctx.Lock.Unlock()
+	}()
+
+	var err error
+	vm.utxoHistoryIndexer, err = index.NewUTXOHistoryIndexer(memdb.New(), vm.parser.Codec(), vm.ctx.Log, false)
+	require.NoError(err)
+
+	assetID := ids.GenerateTestID()
+	addr := ids.GenerateTestShortID()
+	addrStr, err := vm.FormatLocalAddress(addr)
+	require.NoError(err)
+
+	newUTXO := func(amount uint64, locktime uint64, addrs ...ids.ShortID) *avax.UTXO {
+		return &avax.UTXO{
+			UTXOID: avax.UTXOID{TxID: ids.GenerateTestID()},
+			Asset:  avax.Asset{ID: assetID},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: amount,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Locktime:  locktime,
+					Threshold: 1,
+					Addrs:     addrs,
+				},
+			},
+		}
+	}
+
+	// Index 0 creates [first]. Index 1 consumes it and creates a UTXO that is
+	// partially owned by [addr], and a UTXO that is locked until after index 1
+	// was accepted.
+	first := newUTXO(1000, 0, addr)
+	partial := newUTXO(300, 0, addr, ids.GenerateTestShortID())
+	locked := newUTXO(200, 101, addr)
+	require.NoError(vm.utxoHistoryIndexer.Accept(ids.GenerateTestID(), 0, 50, nil, []*avax.UTXO{first}))
+	require.NoError(vm.utxoHistoryIndexer.Accept(ids.GenerateTestID(), 1, 100, []*avax.UTXO{first}, []*avax.UTXO{partial, locked}))
+
+	tests := []struct {
+		index           uint64
+		includePartial  bool
+		expectedBalance uint64
+		expectedUTXOs   int
+	}{
+		{index: 0, expectedBalance: 1000, expectedUTXOs: 1},
+		{index: 1, expectedBalance: 0, expectedUTXOs: 0},
+		{index: 1, includePartial: true, expectedBalance: 500, expectedUTXOs: 2},
+	}
+	for _, test := range tests {
+		reply := &GetBalanceAtReply{}
+		require.NoError(s.GetBalanceAt(nil, &GetBalanceAtArgs{
+			GetBalanceArgs: GetBalanceArgs{
+				Address:        addrStr,
+				AssetID:        assetID.String(),
+				IncludePartial: test.includePartial,
+			},
+			Index: json.Uint64(test.index),
+		}, reply))
+		require.Equal(test.expectedBalance, uint64(reply.Balance), "index %d", test.index)
+		require.Len(reply.UTXOIDs, test.expectedUTXOs, "index %d", test.index)
+	}
+
+	// Indices that haven't been accepted can't be queried
+	err = s.GetBalanceAt(nil, &GetBalanceAtArgs{
+		GetBalanceArgs: GetBalanceArgs{
+			Address: addrStr,
+			AssetID: assetID.String(),
+		},
+		Index: 2,
+	}, &GetBalanceAtReply{})
+	require.Error(err)
+
+	// The UTXOs at index 1 are fetched one page at a time
+	utxosArgs := &GetUTXOsAtArgs{
+		Addresses: []string{addrStr},
+		Index:     1,
+		Limit:     1,
+		Encoding:  formatting.Hex,
+	}
+	fetched := ids.Set{}
+	for i := 0; i < 2; i++ {
+		reply := &api.GetUTXOsReply{}
+		require.NoError(s.GetUTXOsAt(nil, utxosArgs, reply))
+		require.Len(reply.UTXOs, 1)
+
+		utxoBytes, err := formatting.Decode(formatting.Hex, reply.UTXOs[0])
+		require.NoError(err)
+		utxo := &avax.UTXO{}
+		_, err = vm.parser.Codec().Unmarshal(utxoBytes, utxo)
+		require.NoError(err)
+		fetched.Add(utxo.InputID())
+
+		utxosArgs.StartIndex = reply.EndIndex
+	}
+	require.True(fetched.Contains(partial.InputID()))
+	require.True(fetched.Contains(locked.InputID()))
+
+	reply := &api.GetUTXOsReply{}
+	require.NoError(s.GetUTXOsAt(nil, utxosArgs, reply))
+	require.Empty(reply.UTXOs)
+}
+
 func TestServiceGetAllBalances(t *testing.T) {
 	_, vm, s, _, _ := setup(t, true)
 	defer func() {
@@ -931,7 +1039,7 @@ func TestServiceGetTxJSON_OperationTxWithNftxMintOp(t *testing.T) {
 	}
 
 	mintNFTTx := buildOperationTxWithOp(buildNFTxMintOp(createAssetTx, key, 2, 1))
-	err = mintNFTTx.SignNFTFx(vm.parser.Codec(), [][]*crypto.PrivateKeySECP256K1R{{key}})
+	err = mintNFTTx.SignNFTFx(vm.parser.Codec(), [][]keychain.Signer{{key}})
 	require.NoError(t, err)
 
 	txID, err := vm.IssueTx(mintNFTTx.Bytes())
@@ -1039,7 +1147,7 @@ func TestServiceGetTxJSON_OperationTxWithMultipleNftxMintOp(t *testing.T) {
 	mintOp2 := buildNFTxMintOp(createAssetTx, key, 3, 2)
 	mintNFTTx := buildOperationTxWithOp(mintOp1, mintOp2)
 
-	err = mintNFTTx.SignNFTFx(vm.parser.Codec(), [][]*crypto.PrivateKeySECP256K1R{{key}, {key}})
+	err = mintNFTTx.SignNFTFx(vm.parser.Codec(), [][]keychain.Signer{{key}, {key}})
 	require.NoError(t, err)
 
 	txID, err := vm.IssueTx(mintNFTTx.Bytes())
@@ -1143,7 +1251,7 @@ func TestServiceGetTxJSON_OperationTxWithSecpMintOp(t *testing.T) {
 	}
 
 	mintSecpOpTx := buildOperationTxWithOp(buildSecpMintOp(createAssetTx, key, 0))
-	err = mintSecpOpTx.SignSECP256K1Fx(vm.parser.Codec(), [][]*crypto.PrivateKeySECP256K1R{{key}})
+	err = mintSecpOpTx.SignSECP256K1Fx(vm.parser.Codec(), [][]keychain.Signer{{key}})
 	require.NoError(t, err)
 
 	txID, err := vm.IssueTx(mintSecpOpTx.Bytes())
@@ -1253,7 +1361,7 @@ func TestServiceGetTxJSON_OperationTxWithMultipleSecpMintOp(t *testing.T) {
 	op2 := buildSecpMintOp(createAssetTx, key, 1)
 	mintSecpOpTx := buildOperationTxWithOp(op1, op2)
 
-	err = mintSecpOpTx.SignSECP256K1Fx(vm.parser.Codec(), [][]*crypto.PrivateKeySECP256K1R{{key}, {key}})
+	err = mintSecpOpTx.SignSECP256K1Fx(vm.parser.Codec(), [][]keychain.Signer{{key}, {key}})
 	require.NoError(t, err)
 
 	txID, err := vm.IssueTx(mintSecpOpTx.Bytes())
@@ -1357,7 +1465,7 @@ func TestServiceGetTxJSON_OperationTxWithPropertyFxMintOp(t *testing.T) {
 		t.Fatal(err)
 	}
 	mintPropertyFxOpTx := buildOperationTxWithOp(buildPropertyFxMintOp(createAssetTx, key, 4))
-	err = mintPropertyFxOpTx.SignPropertyFx(vm.parser.Codec(), [][]*crypto.PrivateKeySECP256K1R{{key}})
+	err = mintPropertyFxOpTx.SignPropertyFx(vm.parser.Codec(), [][]keychain.Signer{{key}})
 	require.NoError(t, err)
 
 	txID, err := vm.IssueTx(mintPropertyFxOpTx.Bytes())
@@ -1466,7 +1574,7 @@ func TestServiceGetTxJSON_OperationTxWithPropertyFxMintOpMultiple(t *testing.T)
 	op2 := buildPropertyFxMintOp(createAssetTx, key, 5)
 	mintPropertyFxOpTx := buildOperationTxWithOp(op1, op2)
 
-	err = mintPropertyFxOpTx.SignPropertyFx(vm.parser.Codec(), [][]*crypto.PrivateKeySECP256K1R{{key}, {key}})
+	err = mintPropertyFxOpTx.SignPropertyFx(vm.parser.Codec(), [][]keychain.Signer{{key}, {key}})
 	require.NoError(t, err)
 
 	txID, err := vm.IssueTx(mintPropertyFxOpTx.Bytes())
@@ -1514,7 +1622,7 @@ func newAvaxBaseTxWithOutputs(t *testing.T, genesisBytes []byte, vm *VM) *txs.Tx
 	avaxTx := GetAVAXTxFromGenesisTest(genesisBytes, t)
 	key := keys[0]
 	tx := buildBaseTx(avaxTx, vm, key)
-	if err := tx.SignSECP256K1Fx(vm.parser.Codec(), [][]*crypto.PrivateKeySECP256K1R{{key}}); err != nil {
+	if err := tx.SignSECP256K1Fx(vm.parser.Codec(), [][]keychain.Signer{{key}}); err != nil {
 		t.Fatal(err)
 	}
 	return tx
@@ -1524,7 +1632,7 @@ func newAvaxExportTxWithOutputs(t *testing.T, genesisBytes []byte, vm *VM) *txs.
 	avaxTx := GetAVAXTxFromGenesisTest(genesisBytes, t)
 	key := keys[0]
 	tx := buildExportTx(avaxTx, vm, key)
-	if err := tx.SignSECP256K1Fx(vm.parser.Codec(), [][]*crypto.PrivateKeySECP256K1R{{key}}); err != nil {
+	if err := tx.SignSECP256K1Fx(vm.parser.Codec(), [][]keychain.Signer{{key}}); err != nil {
 		t.Fatal(err)
 	}
 	return tx
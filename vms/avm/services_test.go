@@ -0,0 +1,49 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dim4egster/qmallgo/api"
+	"github.com/dim4egster/qmallgo/snow"
+	"github.com/dim4egster/qmallgo/snow/choices"
+)
+
+func TestServices(t *testing.T) {
+	require := require.New(t)
+
+	genesisBytes, _, vm, _ := GenesisVM(t)
+	services := NewServices()
+	vm.Services = services
+
+	getTxStatus := func(service *Service) error {
+		reply := &GetTxStatusReply{}
+		if err := service.GetTxStatus(nil, &api.JSONTxID{
+			TxID: GetAVAXTxFromGenesisTest(genesisBytes, t).ID(),
+		}, reply); err != nil {
+			return err
+		}
+		require.Equal(choices.Accepted, reply.Status)
+		return nil
+	}
+
+	// The service is only added once the chain is bootstrapped.
+	require.NoError(vm.SetState(snow.Bootstrapping))
+	vm.ctx.Lock.Unlock()
+	require.ErrorIs(services.Call(vm.ctx.ChainID, getTxStatus), ErrNotBootstrapped)
+
+	vm.ctx.Lock.Lock()
+	require.NoError(vm.SetState(snow.NormalOp))
+	vm.ctx.Lock.Unlock()
+	require.NoError(services.Call(vm.ctx.ChainID, getTxStatus))
+
+	// The service is removed once the chain is shut down.
+	vm.ctx.Lock.Lock()
+	require.NoError(vm.Shutdown())
+	vm.ctx.Lock.Unlock()
+	require.ErrorIs(services.Call(vm.ctx.ChainID, getTxStatus), ErrNotBootstrapped)
+}
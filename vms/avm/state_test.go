@@ -10,7 +10,7 @@ import (
 	"github.com/dim4egster/qmallgo/ids"
 	"github.com/dim4egster/qmallgo/snow/choices"
 	"github.com/dim4egster/qmallgo/snow/engine/common"
-	"github.com/dim4egster/qmallgo/utils/crypto"
+	"github.com/dim4egster/qmallgo/utils/crypto/keychain"
 	"github.com/dim4egster/qmallgo/utils/units"
 	"github.com/dim4egster/qmallgo/vms/avm/txs"
 	"github.com/dim4egster/qmallgo/vms/components/avax"
@@ -70,7 +70,7 @@ func TestSetsAndGets(t *testing.T) {
 			},
 		}},
 	}}}
-	if err := tx.SignSECP256K1Fx(vm.parser.Codec(), [][]*crypto.PrivateKeySECP256K1R{{keys[0]}}); err != nil {
+	if err := tx.SignSECP256K1Fx(vm.parser.Codec(), [][]keychain.Signer{{keys[0]}}); err != nil {
 		t.Fatal(err)
 	}
 
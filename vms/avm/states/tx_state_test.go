@@ -14,6 +14,7 @@ import (
 	"github.com/dim4egster/qmallgo/database/memdb"
 	"github.com/dim4egster/qmallgo/ids"
 	"github.com/dim4egster/qmallgo/utils/crypto"
+	"github.com/dim4egster/qmallgo/utils/crypto/keychain"
 	"github.com/dim4egster/qmallgo/utils/units"
 	"github.com/dim4egster/qmallgo/vms/avm/fxs"
 	"github.com/dim4egster/qmallgo/vms/avm/txs"
@@ -73,7 +74,7 @@ func TestTxState(t *testing.T) {
 		},
 	}
 
-	err = tx.SignSECP256K1Fx(parser.Codec(), [][]*crypto.PrivateKeySECP256K1R{{keys[0]}})
+	err = tx.SignSECP256K1Fx(parser.Codec(), [][]keychain.Signer{{keys[0]}})
 	require.NoError(err)
 
 	err = s.PutTx(ids.Empty, tx)
@@ -17,6 +17,7 @@ import (
 	"github.com/dim4egster/qmallgo/snow/engine/common"
 	"github.com/dim4egster/qmallgo/utils/constants"
 	"github.com/dim4egster/qmallgo/utils/crypto"
+	"github.com/dim4egster/qmallgo/utils/crypto/keychain"
 	"github.com/dim4egster/qmallgo/version"
 	"github.com/dim4egster/qmallgo/vms/avm/fxs"
 	"github.com/dim4egster/qmallgo/vms/avm/txs"
@@ -57,7 +58,7 @@ func TestBaseTxSemanticVerify(t *testing.T) {
 			}},
 		},
 	}}
-	if err := tx.SignSECP256K1Fx(vm.parser.Codec(), [][]*crypto.PrivateKeySECP256K1R{{keys[0]}}); err != nil {
+	if err := tx.SignSECP256K1Fx(vm.parser.Codec(), [][]keychain.Signer{{keys[0]}}); err != nil {
 		t.Fatal(err)
 	}
 
@@ -168,7 +169,7 @@ func TestBaseTxSemanticVerifyWrongAssetID(t *testing.T) {
 			}},
 		},
 	}}
-	if err := tx.SignSECP256K1Fx(vm.parser.Codec(), [][]*crypto.PrivateKeySECP256K1R{{keys[0]}}); err != nil {
+	if err := tx.SignSECP256K1Fx(vm.parser.Codec(), [][]keychain.Signer{{keys[0]}}); err != nil {
 		t.Fatal(err)
 	}
 
@@ -249,7 +250,7 @@ func TestBaseTxSemanticVerifyUnauthorizedFx(t *testing.T) {
 			}},
 		},
 	}}
-	if err := tx.SignSECP256K1Fx(vm.parser.Codec(), [][]*crypto.PrivateKeySECP256K1R{{keys[0]}}); err != nil {
+	if err := tx.SignSECP256K1Fx(vm.parser.Codec(), [][]keychain.Signer{{keys[0]}}); err != nil {
 		t.Fatal(err)
 	}
 
@@ -352,7 +353,7 @@ func TestBaseTxSemanticVerifyMissingUTXO(t *testing.T) {
 			}},
 		},
 	}}
-	if err := tx.SignSECP256K1Fx(vm.parser.Codec(), [][]*crypto.PrivateKeySECP256K1R{{keys[0]}}); err != nil {
+	if err := tx.SignSECP256K1Fx(vm.parser.Codec(), [][]keychain.Signer{{keys[0]}}); err != nil {
 		t.Fatal(err)
 	}
 
@@ -398,7 +399,7 @@ func TestBaseTxSemanticVerifyInvalidUTXO(t *testing.T) {
 			}},
 		},
 	}}
-	if err := tx.SignSECP256K1Fx(vm.parser.Codec(), [][]*crypto.PrivateKeySECP256K1R{{keys[0]}}); err != nil {
+	if err := tx.SignSECP256K1Fx(vm.parser.Codec(), [][]keychain.Signer{{keys[0]}}); err != nil {
 		t.Fatal(err)
 	}
 
@@ -449,7 +450,7 @@ func TestBaseTxSemanticVerifyPendingInvalidUTXO(t *testing.T) {
 			}},
 		},
 	}}
-	if err := pendingTx.SignSECP256K1Fx(vm.parser.Codec(), [][]*crypto.PrivateKeySECP256K1R{{keys[0]}}); err != nil {
+	if err := pendingTx.SignSECP256K1Fx(vm.parser.Codec(), [][]keychain.Signer{{keys[0]}}); err != nil {
 		t.Fatal(err)
 	}
 
@@ -493,7 +494,7 @@ func TestBaseTxSemanticVerifyPendingInvalidUTXO(t *testing.T) {
 			}},
 		},
 	}}
-	if err := tx.SignSECP256K1Fx(vm.parser.Codec(), [][]*crypto.PrivateKeySECP256K1R{{keys[0]}}); err != nil {
+	if err := tx.SignSECP256K1Fx(vm.parser.Codec(), [][]keychain.Signer{{keys[0]}}); err != nil {
 		t.Fatal(err)
 	}
 
@@ -544,7 +545,7 @@ func TestBaseTxSemanticVerifyPendingWrongAssetID(t *testing.T) {
 			}},
 		},
 	}}
-	if err := pendingTx.SignSECP256K1Fx(vm.parser.Codec(), [][]*crypto.PrivateKeySECP256K1R{{keys[0]}}); err != nil {
+	if err := pendingTx.SignSECP256K1Fx(vm.parser.Codec(), [][]keychain.Signer{{keys[0]}}); err != nil {
 		t.Fatal(err)
 	}
 
@@ -589,7 +590,7 @@ func TestBaseTxSemanticVerifyPendingWrongAssetID(t *testing.T) {
 		},
 	}}
 
-	if err := tx.SignSECP256K1Fx(vm.parser.Codec(), [][]*crypto.PrivateKeySECP256K1R{{keys[0]}}); err != nil {
+	if err := tx.SignSECP256K1Fx(vm.parser.Codec(), [][]keychain.Signer{{keys[0]}}); err != nil {
 		t.Fatal(err)
 	}
 
@@ -685,7 +686,7 @@ func TestBaseTxSemanticVerifyPendingUnauthorizedFx(t *testing.T) {
 			}},
 		},
 	}}
-	if err := pendingTx.SignSECP256K1Fx(vm.parser.Codec(), [][]*crypto.PrivateKeySECP256K1R{{keys[0]}}); err != nil {
+	if err := pendingTx.SignSECP256K1Fx(vm.parser.Codec(), [][]keychain.Signer{{keys[0]}}); err != nil {
 		t.Fatal(err)
 	}
 
@@ -832,7 +833,7 @@ func TestBaseTxSemanticVerifyPendingInvalidSignature(t *testing.T) {
 			}},
 		},
 	}}
-	if err := pendingTx.SignSECP256K1Fx(vm.parser.Codec(), [][]*crypto.PrivateKeySECP256K1R{{keys[0]}}); err != nil {
+	if err := pendingTx.SignSECP256K1Fx(vm.parser.Codec(), [][]keychain.Signer{{keys[0]}}); err != nil {
 		t.Fatal(err)
 	}
 
@@ -1002,7 +1003,7 @@ func TestBaseTxSemanticVerifyInvalidFxOutput(t *testing.T) {
 			}},
 		},
 	}}
-	if err := tx.SignSECP256K1Fx(vm.parser.Codec(), [][]*crypto.PrivateKeySECP256K1R{{keys[0]}}); err != nil {
+	if err := tx.SignSECP256K1Fx(vm.parser.Codec(), [][]keychain.Signer{{keys[0]}}); err != nil {
 		t.Fatal(err)
 	}
 
@@ -1056,7 +1057,7 @@ func TestExportTxSemanticVerify(t *testing.T) {
 		}},
 	}}
 
-	if err := rawTx.SignSECP256K1Fx(vm.parser.Codec(), [][]*crypto.PrivateKeySECP256K1R{{keys[0]}}); err != nil {
+	if err := rawTx.SignSECP256K1Fx(vm.parser.Codec(), [][]keychain.Signer{{keys[0]}}); err != nil {
 		t.Fatal(err)
 	}
 
@@ -1119,7 +1120,7 @@ func TestExportTxSemanticVerifyUnknownCredFx(t *testing.T) {
 			},
 		}},
 	}}
-	if err := rawTx.SignSECP256K1Fx(vm.parser.Codec(), [][]*crypto.PrivateKeySECP256K1R{{keys[0]}}); err != nil {
+	if err := rawTx.SignSECP256K1Fx(vm.parser.Codec(), [][]keychain.Signer{{keys[0]}}); err != nil {
 		t.Fatal(err)
 	}
 
@@ -1184,7 +1185,7 @@ func TestExportTxSemanticVerifyMissingUTXO(t *testing.T) {
 		}},
 	}}
 
-	if err := rawTx.SignSECP256K1Fx(vm.parser.Codec(), [][]*crypto.PrivateKeySECP256K1R{{keys[0]}}); err != nil {
+	if err := rawTx.SignSECP256K1Fx(vm.parser.Codec(), [][]keychain.Signer{{keys[0]}}); err != nil {
 		t.Fatal(err)
 	}
 
@@ -1264,7 +1265,7 @@ func TestExportTxSemanticVerifyInvalidAssetID(t *testing.T) {
 			},
 		}},
 	}}
-	if err := rawTx.SignSECP256K1Fx(vm.parser.Codec(), [][]*crypto.PrivateKeySECP256K1R{
+	if err := rawTx.SignSECP256K1Fx(vm.parser.Codec(), [][]keychain.Signer{
 		{
 			keys[0],
 		},
@@ -1392,7 +1393,7 @@ func TestExportTxSemanticVerifyInvalidFx(t *testing.T) {
 			},
 		}},
 	}}
-	if err := rawTx.SignSECP256K1Fx(vm.parser.Codec(), [][]*crypto.PrivateKeySECP256K1R{{keys[0]}}); err != nil {
+	if err := rawTx.SignSECP256K1Fx(vm.parser.Codec(), [][]keychain.Signer{{keys[0]}}); err != nil {
 		t.Fatal(err)
 	}
 
@@ -1456,7 +1457,7 @@ func TestExportTxSemanticVerifyInvalidTransfer(t *testing.T) {
 			},
 		}},
 	}}
-	if err := rawTx.SignSECP256K1Fx(vm.parser.Codec(), [][]*crypto.PrivateKeySECP256K1R{{keys[1]}}); err != nil {
+	if err := rawTx.SignSECP256K1Fx(vm.parser.Codec(), [][]keychain.Signer{{keys[1]}}); err != nil {
 		t.Fatal(err)
 	}
 
@@ -1553,7 +1554,7 @@ func TestExportTxSemanticVerifyTransferCustomAssetBeforeBlueberry(t *testing.T)
 
 	err := rawTx.SignSECP256K1Fx(
 		vm.parser.Codec(),
-		[][]*crypto.PrivateKeySECP256K1R{
+		[][]keychain.Signer{
 			{keys[0]},
 			{keys[0]},
 		},
@@ -1655,7 +1656,7 @@ func TestExportTxSemanticVerifyTransferCustomAssetAfterBlueberry(t *testing.T) {
 
 	err := rawTx.SignSECP256K1Fx(
 		vm.parser.Codec(),
-		[][]*crypto.PrivateKeySECP256K1R{
+		[][]keychain.Signer{
 			{keys[0]},
 			{keys[0]},
 		},
@@ -11,7 +11,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/dim4egster/qmallgo/ids"
-	"github.com/dim4egster/qmallgo/utils/crypto"
+	"github.com/dim4egster/qmallgo/utils/crypto/keychain"
 	"github.com/dim4egster/qmallgo/vms/components/avax"
 	"github.com/dim4egster/qmallgo/vms/components/verify"
 	"github.com/dim4egster/qmallgo/vms/secp256k1fx"
@@ -176,7 +176,7 @@ func TestBaseTxSerialization(t *testing.T) {
 		0x5d, 0x73, 0x6d, 0x94, 0xfc, 0x80, 0xbc, 0x73, 0x5f, 0x51,
 		0xc8, 0x06, 0xd7, 0x43, 0x00,
 	}
-	if err := tx.SignSECP256K1Fx(c, [][]*crypto.PrivateKeySECP256K1R{{keys[0], keys[0]}, {keys[0], keys[0]}}); err != nil {
+	if err := tx.SignSECP256K1Fx(c, [][]keychain.Signer{{keys[0], keys[0]}, {keys[0], keys[0]}}); err != nil {
 		t.Fatal(err)
 	}
 	require.Equal(t, tx.ID().String(), "QnTUuie2qe6BKyYrC2jqd73bJ828QNhYnZbdA2HWsnVRPjBfV")
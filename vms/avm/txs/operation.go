@@ -11,7 +11,7 @@ import (
 	"github.com/dim4egster/qmallgo/codec"
 	"github.com/dim4egster/qmallgo/ids"
 	"github.com/dim4egster/qmallgo/utils"
-	"github.com/dim4egster/qmallgo/utils/crypto"
+	"github.com/dim4egster/qmallgo/utils/crypto/keychain"
 	"github.com/dim4egster/qmallgo/vms/avm/fxs"
 	"github.com/dim4egster/qmallgo/vms/components/avax"
 	"github.com/dim4egster/qmallgo/vms/components/verify"
@@ -75,7 +75,7 @@ func IsSortedAndUniqueOperations(ops []*Operation, c codec.Manager) bool {
 
 type innerSortOperationsWithSigners struct {
 	ops     []*Operation
-	signers [][]*crypto.PrivateKeySECP256K1R
+	signers [][]keychain.Signer
 	codec   codec.Manager
 }
 
@@ -99,6 +99,6 @@ func (ops *innerSortOperationsWithSigners) Swap(i, j int) {
 	ops.signers[j], ops.signers[i] = ops.signers[i], ops.signers[j]
 }
 
-func SortOperationsWithSigners(ops []*Operation, signers [][]*crypto.PrivateKeySECP256K1R, codec codec.Manager) {
+func SortOperationsWithSigners(ops []*Operation, signers [][]keychain.Signer, codec codec.Manager) {
 	sort.Sort(&innerSortOperationsWithSigners{ops: ops, signers: signers, codec: codec})
 }
@@ -148,6 +148,9 @@ func (tx *UniqueTx) Accept() error {
 	if err := tx.vm.addressTxsIndexer.Accept(tx.ID(), inputUTXOs, outputUTXOs); err != nil {
 		return fmt.Errorf("error indexing tx: %w", err)
 	}
+	if err := tx.vm.indexUTXOHistory(txID, inputUTXOs, outputUTXOs); err != nil {
+		return fmt.Errorf("error indexing UTXO history of tx: %w", err)
+	}
 
 	// Remove spent utxos
 	for _, utxo := range inputUTXOIDs {
@@ -21,6 +21,7 @@ import (
 	"github.com/dim4egster/qmallgo/cache"
 	"github.com/dim4egster/qmallgo/database"
 	"github.com/dim4egster/qmallgo/database/manager"
+	"github.com/dim4egster/qmallgo/database/prefixdb"
 	"github.com/dim4egster/qmallgo/database/versiondb"
 	"github.com/dim4egster/qmallgo/ids"
 	"github.com/dim4egster/qmallgo/pubsub"
@@ -29,7 +30,7 @@ import (
 	"github.com/dim4egster/qmallgo/snow/consensus/snowstorm"
 	"github.com/dim4egster/qmallgo/snow/engine/avalanche/vertex"
 	"github.com/dim4egster/qmallgo/snow/engine/common"
-	"github.com/dim4egster/qmallgo/utils/crypto"
+	"github.com/dim4egster/qmallgo/utils/crypto/keychain"
 	"github.com/dim4egster/qmallgo/utils/json"
 	"github.com/dim4egster/qmallgo/utils/timer"
 	"github.com/dim4egster/qmallgo/utils/timer/mockable"
@@ -60,6 +61,9 @@ var (
 	errGenesisAssetMustHaveState = errors.New("genesis asset must have non-empty state")
 	errBootstrapping             = errors.New("chain is currently bootstrapping")
 	errInsufficientFunds         = errors.New("insufficient funds")
+	errMissingTxIndex            = errors.New("UTXO history indexing requires the node's tx index (--index-enabled)")
+
+	utxoHistoryPrefix = []byte("utxoHistory")
 
 	_ vertex.DAGVM = &VM{}
 )
@@ -107,7 +111,12 @@ type VM struct {
 
 	walletService WalletService
 
-	addressTxsIndexer index.AddressTxsIndexer
+	addressTxsIndexer  index.AddressTxsIndexer
+	utxoHistoryIndexer index.UTXOHistoryIndexer
+	// txIndex keys the UTXO history. It is nil if the UTXO history isn't
+	// indexed.
+	txIndex            index.ContainerIndex
+	utxoHistoryEnabled bool
 
 	uniqueTxs cache.Deduplicator
 }
@@ -128,6 +137,7 @@ func (vm *VM) Disconnected(nodeID ids.NodeID) error {
 
 type Config struct {
 	IndexTransactions    bool `json:"index-transactions"`
+	IndexUTXOHistory     bool `json:"index-utxo-history"`
 	IndexAllowIncomplete bool `json:"index-allow-incomplete"`
 }
 
@@ -209,6 +219,23 @@ func (vm *VM) Initialize(
 
 	vm.state = state
 
+	// The UTXO history must be indexing before the genesis UTXOs are created.
+	utxoHistoryDB := prefixdb.New(utxoHistoryPrefix, vm.db)
+	vm.utxoHistoryEnabled = avmConfig.IndexUTXOHistory
+	if avmConfig.IndexUTXOHistory {
+		vm.ctx.Log.Info("UTXO history indexing is enabled")
+		vm.utxoHistoryIndexer, err = index.NewUTXOHistoryIndexer(utxoHistoryDB, vm.parser.Codec(), vm.ctx.Log, avmConfig.IndexAllowIncomplete)
+		if err != nil {
+			return fmt.Errorf("failed to initialize UTXO history indexer: %w", err)
+		}
+	} else {
+		vm.ctx.Log.Info("UTXO history indexing is disabled")
+		vm.utxoHistoryIndexer, err = index.NewNoUTXOHistoryIndexer(utxoHistoryDB, avmConfig.IndexAllowIncomplete)
+		if err != nil {
+			return fmt.Errorf("failed to initialize disabled UTXO history indexer: %w", err)
+		}
+	}
+
 	if err := vm.initGenesis(genesisBytes); err != nil {
 		return err
 	}
@@ -248,6 +275,20 @@ func (vm *VM) Initialize(
 
 // onBootstrapStarted is called by the consensus engine when it starts bootstrapping this chain
 func (vm *VM) onBootstrapStarted() error {
+	if vm.Services != nil {
+		vm.Services.remove(vm.ctx.ChainID)
+	}
+	// The node's tx index is registered after the VM is initialized.
+	if vm.utxoHistoryEnabled && vm.txIndex == nil {
+		if vm.TxIndex == nil {
+			return errMissingTxIndex
+		}
+		txIndex, ok := vm.TxIndex(vm.ctx.ChainID)
+		if !ok {
+			return errMissingTxIndex
+		}
+		vm.txIndex = txIndex
+	}
 	for _, fx := range vm.fxs {
 		if err := fx.Fx.Bootstrapping(); err != nil {
 			return err
@@ -263,6 +304,9 @@ func (vm *VM) onNormalOperationsStarted() error {
 		}
 	}
 	vm.bootstrapped = true
+	if vm.Services != nil {
+		vm.Services.add(vm.ctx.ChainID, &Service{vm: vm})
+	}
 	return nil
 }
 
@@ -282,6 +326,10 @@ func (vm *VM) Shutdown() error {
 		return nil
 	}
 
+	if vm.Services != nil {
+		vm.Services.remove(vm.ctx.ChainID)
+	}
+
 	// There is a potential deadlock if the timer is about to execute a timeout.
 	// So, the lock must be released before stopping the timer.
 	vm.ctx.Lock.Unlock()
@@ -481,12 +529,27 @@ func (vm *VM) initState(tx txs.Tx) error {
 	if err := vm.state.PutStatus(txID, choices.Accepted); err != nil {
 		return err
 	}
-	for _, utxo := range tx.UTXOs() {
+	utxos := tx.UTXOs()
+	for _, utxo := range utxos {
 		if err := vm.state.PutUTXO(utxo); err != nil {
 			return err
 		}
 	}
-	return nil
+	return vm.utxoHistoryIndexer.Genesis(utxos)
+}
+
+// indexUTXOHistory records in the UTXO history that [txID] consumed
+// [inputUTXOs] and created [outputUTXOs], at the index and time the node's tx
+// index accepted it.
+func (vm *VM) indexUTXOHistory(txID ids.ID, inputUTXOs, outputUTXOs []*avax.UTXO) error {
+	if vm.txIndex == nil {
+		return nil
+	}
+	txIndex, acceptedAt, err := vm.txIndex.GetAccepted(txID)
+	if err != nil {
+		return fmt.Errorf("couldn't get the index of tx %s: %w", txID, err)
+	}
+	return vm.utxoHistoryIndexer.Accept(txID, txIndex, uint64(acceptedAt.Unix()), inputUTXOs, outputUTXOs)
 }
 
 func (vm *VM) parseTx(bytes []byte) (*UniqueTx, error) {
@@ -655,7 +718,7 @@ func (vm *VM) verifyOperation(tx *txs.OperationTx, op *txs.Operation, cred verif
 
 // LoadUser returns:
 // 1) The UTXOs that reference one or more addresses controlled by the given user
-// 2) A keychain that contains this user's keys
+// 2) A keychain that signs for this user, through the external signer if any
 // If [addrsToUse] has positive length, returns UTXOs that reference one or more
 // addresses controlled by the given user that are also in [addrsToUse].
 func (vm *VM) LoadUser(
@@ -664,7 +727,7 @@ func (vm *VM) LoadUser(
 	addrsToUse ids.ShortSet,
 ) (
 	[]*avax.UTXO,
-	*secp256k1fx.Keychain,
+	*keystore.Keychain,
 	error,
 ) {
 	user, err := keystore.NewUserFromKeystore(vm.ctx.Keystore, username, password)
@@ -675,7 +738,7 @@ func (vm *VM) LoadUser(
 	// error
 	defer user.Close()
 
-	kc, err := keystore.GetKeychain(user, addrsToUse)
+	kc, err := keystore.NewKeychain(vm.ctx.Keychain, user, addrsToUse)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -690,19 +753,19 @@ func (vm *VM) LoadUser(
 
 func (vm *VM) Spend(
 	utxos []*avax.UTXO,
-	kc *secp256k1fx.Keychain,
+	kc keychain.Keychain,
 	amounts map[ids.ID]uint64,
 ) (
 	map[ids.ID]uint64,
 	[]*avax.TransferableInput,
-	[][]*crypto.PrivateKeySECP256K1R,
+	[][]keychain.Signer,
 	error,
 ) {
 	amountsSpent := make(map[ids.ID]uint64, len(amounts))
 	time := vm.clock.Unix()
 
 	ins := []*avax.TransferableInput{}
-	keys := [][]*crypto.PrivateKeySECP256K1R{}
+	keys := [][]keychain.Signer{}
 	for _, utxo := range utxos {
 		assetID := utxo.AssetID()
 		amount := amounts[assetID]
@@ -713,7 +776,7 @@ func (vm *VM) Spend(
 			continue
 		}
 
-		inputIntf, signers, err := kc.Spend(utxo.Out, time)
+		inputIntf, signers, err := secp256k1fx.Spend(kc, utxo.Out, time)
 		if err != nil {
 			// this utxo can't be spent with the current keys right now
 			continue
@@ -756,19 +819,19 @@ func (vm *VM) Spend(
 
 func (vm *VM) SpendNFT(
 	utxos []*avax.UTXO,
-	kc *secp256k1fx.Keychain,
+	kc keychain.Keychain,
 	assetID ids.ID,
 	groupID uint32,
 	to ids.ShortID,
 ) (
 	[]*txs.Operation,
-	[][]*crypto.PrivateKeySECP256K1R,
+	[][]keychain.Signer,
 	error,
 ) {
 	time := vm.clock.Unix()
 
 	ops := []*txs.Operation{}
-	keys := [][]*crypto.PrivateKeySECP256K1R{}
+	keys := [][]keychain.Signer{}
 
 	for _, utxo := range utxos {
 		// makes sure that the variable isn't overwritten with the next iteration
@@ -792,7 +855,7 @@ func (vm *VM) SpendNFT(
 			// wrong group id
 			continue
 		}
-		indices, signers, ok := kc.Match(&out.OutputOwners, time)
+		indices, signers, ok := secp256k1fx.Match(kc, &out.OutputOwners, time)
 		if !ok {
 			// unable to spend the output
 			continue
@@ -830,23 +893,23 @@ func (vm *VM) SpendNFT(
 
 func (vm *VM) SpendAll(
 	utxos []*avax.UTXO,
-	kc *secp256k1fx.Keychain,
+	kc keychain.Keychain,
 ) (
 	map[ids.ID]uint64,
 	[]*avax.TransferableInput,
-	[][]*crypto.PrivateKeySECP256K1R,
+	[][]keychain.Signer,
 	error,
 ) {
 	amountsSpent := make(map[ids.ID]uint64)
 	time := vm.clock.Unix()
 
 	ins := []*avax.TransferableInput{}
-	keys := [][]*crypto.PrivateKeySECP256K1R{}
+	keys := [][]keychain.Signer{}
 	for _, utxo := range utxos {
 		assetID := utxo.AssetID()
 		amountSpent := amountsSpent[assetID]
 
-		inputIntf, signers, err := kc.Spend(utxo.Out, time)
+		inputIntf, signers, err := secp256k1fx.Spend(kc, utxo.Out, time)
 		if err != nil {
 			// this utxo can't be spent with the current keys right now
 			continue
@@ -879,18 +942,18 @@ func (vm *VM) SpendAll(
 
 func (vm *VM) Mint(
 	utxos []*avax.UTXO,
-	kc *secp256k1fx.Keychain,
+	kc keychain.Keychain,
 	amounts map[ids.ID]uint64,
 	to ids.ShortID,
 ) (
 	[]*txs.Operation,
-	[][]*crypto.PrivateKeySECP256K1R,
+	[][]keychain.Signer,
 	error,
 ) {
 	time := vm.clock.Unix()
 
 	ops := []*txs.Operation{}
-	keys := [][]*crypto.PrivateKeySECP256K1R{}
+	keys := [][]keychain.Signer{}
 
 	for _, utxo := range utxos {
 		// makes sure that the variable isn't overwritten with the next iteration
@@ -907,7 +970,7 @@ func (vm *VM) Mint(
 			continue
 		}
 
-		inIntf, signers, err := kc.Spend(out, time)
+		inIntf, signers, err := secp256k1fx.Spend(kc, out, time)
 		if err != nil {
 			continue
 		}
@@ -952,19 +1015,19 @@ func (vm *VM) Mint(
 
 func (vm *VM) MintNFT(
 	utxos []*avax.UTXO,
-	kc *secp256k1fx.Keychain,
+	kc keychain.Keychain,
 	assetID ids.ID,
 	payload []byte,
 	to ids.ShortID,
 ) (
 	[]*txs.Operation,
-	[][]*crypto.PrivateKeySECP256K1R,
+	[][]keychain.Signer,
 	error,
 ) {
 	time := vm.clock.Unix()
 
 	ops := []*txs.Operation{}
-	keys := [][]*crypto.PrivateKeySECP256K1R{}
+	keys := [][]keychain.Signer{}
 
 	for _, utxo := range utxos {
 		// makes sure that the variable isn't overwritten with the next iteration
@@ -985,7 +1048,7 @@ func (vm *VM) MintNFT(
 			continue
 		}
 
-		indices, signers, ok := kc.Match(&out.OutputOwners, time)
+		indices, signers, ok := secp256k1fx.Match(kc, &out.OutputOwners, time)
 		if !ok {
 			// unable to spend the output
 			continue
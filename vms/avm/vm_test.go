@@ -27,6 +27,7 @@ import (
 	"github.com/dim4egster/qmallgo/utils/cb58"
 	"github.com/dim4egster/qmallgo/utils/constants"
 	"github.com/dim4egster/qmallgo/utils/crypto"
+	"github.com/dim4egster/qmallgo/utils/crypto/keychain"
 	"github.com/dim4egster/qmallgo/utils/formatting"
 	"github.com/dim4egster/qmallgo/utils/formatting/address"
 	"github.com/dim4egster/qmallgo/utils/json"
@@ -377,7 +378,7 @@ func NewTxWithAsset(t *testing.T, genesisBytes []byte, vm *VM, assetName string)
 			}},
 		},
 	}}
-	if err := newTx.SignSECP256K1Fx(vm.parser.Codec(), [][]*crypto.PrivateKeySECP256K1R{{keys[0]}}); err != nil {
+	if err := newTx.SignSECP256K1Fx(vm.parser.Codec(), [][]keychain.Signer{{keys[0]}}); err != nil {
 		t.Fatal(err)
 	}
 	return newTx
@@ -420,7 +421,7 @@ func setupIssueTx(t testing.TB) (chan common.Message, *VM, *snow.Context, []*txs
 			}},
 		},
 	}}
-	if err := firstTx.SignSECP256K1Fx(vm.parser.Codec(), [][]*crypto.PrivateKeySECP256K1R{{key}}); err != nil {
+	if err := firstTx.SignSECP256K1Fx(vm.parser.Codec(), [][]keychain.Signer{{key}}); err != nil {
 		t.Fatal(err)
 	}
 
@@ -455,7 +456,7 @@ func setupIssueTx(t testing.TB) (chan common.Message, *VM, *snow.Context, []*txs
 			}},
 		},
 	}}
-	if err := secondTx.SignSECP256K1Fx(vm.parser.Codec(), [][]*crypto.PrivateKeySECP256K1R{{key}}); err != nil {
+	if err := secondTx.SignSECP256K1Fx(vm.parser.Codec(), [][]keychain.Signer{{key}}); err != nil {
 		t.Fatal(err)
 	}
 	return issuer, vm, ctx, []*txs.Tx{avaxTx, firstTx, secondTx}
@@ -721,7 +722,7 @@ func TestIssueNFT(t *testing.T) {
 			},
 		}},
 	}}
-	if err := mintNFTTx.SignNFTFx(vm.parser.Codec(), [][]*crypto.PrivateKeySECP256K1R{{keys[0]}}); err != nil {
+	if err := mintNFTTx.SignNFTFx(vm.parser.Codec(), [][]keychain.Signer{{keys[0]}}); err != nil {
 		t.Fatal(err)
 	}
 
@@ -871,7 +872,7 @@ func TestIssueProperty(t *testing.T) {
 	}}
 
 	codec := vm.parser.Codec()
-	err = mintPropertyTx.SignPropertyFx(codec, [][]*crypto.PrivateKeySECP256K1R{
+	err = mintPropertyTx.SignPropertyFx(codec, [][]keychain.Signer{
 		{keys[0]},
 	})
 	if err != nil {
@@ -897,7 +898,7 @@ func TestIssueProperty(t *testing.T) {
 		}},
 	}}
 
-	err = burnPropertyTx.SignPropertyFx(codec, [][]*crypto.PrivateKeySECP256K1R{
+	err = burnPropertyTx.SignPropertyFx(codec, [][]keychain.Signer{
 		{},
 	})
 	if err != nil {
@@ -1044,7 +1045,7 @@ func TestIssueTxWithAnotherAsset(t *testing.T) {
 			},
 		},
 	}}
-	if err := newTx.SignSECP256K1Fx(vm.parser.Codec(), [][]*crypto.PrivateKeySECP256K1R{{keys[0]}, {keys[0]}}); err != nil {
+	if err := newTx.SignSECP256K1Fx(vm.parser.Codec(), [][]keychain.Signer{{keys[0]}, {keys[0]}}); err != nil {
 		t.Fatal(err)
 	}
 
@@ -1281,7 +1282,7 @@ func TestTxVerifyAfterVerifyAncestorTx(t *testing.T) {
 			},
 		}},
 	}}}
-	if err := firstTxDescendant.SignSECP256K1Fx(vm.parser.Codec(), [][]*crypto.PrivateKeySECP256K1R{{key}}); err != nil {
+	if err := firstTxDescendant.SignSECP256K1Fx(vm.parser.Codec(), [][]keychain.Signer{{key}}); err != nil {
 		t.Fatal(err)
 	}
 
@@ -1459,7 +1460,7 @@ func TestImportTxSerialization(t *testing.T) {
 		0x1f, 0x49, 0x9b, 0x0a, 0x4f, 0xbf, 0x95, 0xfc, 0x31, 0x39,
 		0x46, 0x4e, 0xa1, 0xaf, 0x00,
 	}
-	if err := tx.SignSECP256K1Fx(vm.parser.Codec(), [][]*crypto.PrivateKeySECP256K1R{{keys[0], keys[0]}, {keys[0], keys[0]}}); err != nil {
+	if err := tx.SignSECP256K1Fx(vm.parser.Codec(), [][]keychain.Signer{{keys[0], keys[0]}, {keys[0], keys[0]}}); err != nil {
 		t.Fatal(err)
 	}
 	require.Equal(t, tx.ID().String(), "pCW7sVBytzdZ1WrqzGY1DvA2S9UaMr72xpUMxVyx1QHBARNYx")
@@ -1566,7 +1567,7 @@ func TestIssueImportTx(t *testing.T) {
 			},
 		}},
 	}}
-	if err := tx.SignSECP256K1Fx(vm.parser.Codec(), [][]*crypto.PrivateKeySECP256K1R{{key}}); err != nil {
+	if err := tx.SignSECP256K1Fx(vm.parser.Codec(), [][]keychain.Signer{{key}}); err != nil {
 		t.Fatal(err)
 	}
 
@@ -1724,7 +1725,7 @@ func TestForceAcceptImportTx(t *testing.T) {
 		}},
 	}}
 
-	if err := tx.SignSECP256K1Fx(vm.parser.Codec(), [][]*crypto.PrivateKeySECP256K1R{{key}}); err != nil {
+	if err := tx.SignSECP256K1Fx(vm.parser.Codec(), [][]keychain.Signer{{key}}); err != nil {
 		t.Fatal(err)
 	}
 
@@ -1826,7 +1827,7 @@ func TestIssueExportTx(t *testing.T) {
 			},
 		}},
 	}}
-	if err := tx.SignSECP256K1Fx(vm.parser.Codec(), [][]*crypto.PrivateKeySECP256K1R{{key}}); err != nil {
+	if err := tx.SignSECP256K1Fx(vm.parser.Codec(), [][]keychain.Signer{{key}}); err != nil {
 		t.Fatal(err)
 	}
 
@@ -1961,7 +1962,7 @@ func TestClearForceAcceptedExportTx(t *testing.T) {
 			},
 		}},
 	}}
-	if err := tx.SignSECP256K1Fx(vm.parser.Codec(), [][]*crypto.PrivateKeySECP256K1R{{key}}); err != nil {
+	if err := tx.SignSECP256K1Fx(vm.parser.Codec(), [][]keychain.Signer{{key}}); err != nil {
 		t.Fatal(err)
 	}
 
@@ -144,10 +144,10 @@ func (w *WalletService) SendMultiple(r *http.Request, args *SendMultipleArgs, re
 	}
 
 	// Parse the change address.
-	if len(kc.Keys) == 0 {
+	if len(kc.Addrs) == 0 {
 		return errNoKeys
 	}
-	changeAddr, err := w.vm.selectChangeAddr(kc.Keys[0].PublicKey().Address(), args.ChangeAddr)
+	changeAddr, err := w.vm.selectChangeAddr(kc.Addrs[0], args.ChangeAddr)
 	if err != nil {
 		return err
 	}
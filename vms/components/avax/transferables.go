@@ -12,7 +12,6 @@ import (
 	"github.com/dim4egster/qmallgo/ids"
 	"github.com/dim4egster/qmallgo/snow"
 	"github.com/dim4egster/qmallgo/utils"
-	"github.com/dim4egster/qmallgo/utils/crypto"
 	"github.com/dim4egster/qmallgo/vms/components/verify"
 )
 
@@ -173,12 +172,12 @@ func IsSortedAndUniqueTransferableInputs(ins []*TransferableInput) bool {
 	return utils.IsSortedAndUnique(innerSortTransferableInputs(ins))
 }
 
-type innerSortTransferableInputsWithSigners struct {
+type innerSortTransferableInputsWithSigners[T any] struct {
 	ins     []*TransferableInput
-	signers [][]*crypto.PrivateKeySECP256K1R
+	signers [][]T
 }
 
-func (ins *innerSortTransferableInputsWithSigners) Less(i, j int) bool {
+func (ins *innerSortTransferableInputsWithSigners[T]) Less(i, j int) bool {
 	iID, iIndex := ins.ins[i].InputSource()
 	jID, jIndex := ins.ins[j].InputSource()
 
@@ -191,22 +190,22 @@ func (ins *innerSortTransferableInputsWithSigners) Less(i, j int) bool {
 		return false
 	}
 }
-func (ins *innerSortTransferableInputsWithSigners) Len() int { return len(ins.ins) }
-func (ins *innerSortTransferableInputsWithSigners) Swap(i, j int) {
+func (ins *innerSortTransferableInputsWithSigners[T]) Len() int { return len(ins.ins) }
+func (ins *innerSortTransferableInputsWithSigners[T]) Swap(i, j int) {
 	ins.ins[j], ins.ins[i] = ins.ins[i], ins.ins[j]
 	ins.signers[j], ins.signers[i] = ins.signers[i], ins.signers[j]
 }
 
 // SortTransferableInputsWithSigners sorts the inputs and signers based on the
 // input's utxo ID
-func SortTransferableInputsWithSigners(ins []*TransferableInput, signers [][]*crypto.PrivateKeySECP256K1R) {
-	sort.Sort(&innerSortTransferableInputsWithSigners{ins: ins, signers: signers})
+func SortTransferableInputsWithSigners[T any](ins []*TransferableInput, signers [][]T) {
+	sort.Sort(&innerSortTransferableInputsWithSigners[T]{ins: ins, signers: signers})
 }
 
 // IsSortedAndUniqueTransferableInputsWithSigners returns true if the inputs are
 // sorted and unique
-func IsSortedAndUniqueTransferableInputsWithSigners(ins []*TransferableInput, signers [][]*crypto.PrivateKeySECP256K1R) bool {
-	return utils.IsSortedAndUnique(&innerSortTransferableInputsWithSigners{ins: ins, signers: signers})
+func IsSortedAndUniqueTransferableInputsWithSigners[T any](ins []*TransferableInput, signers [][]T) bool {
+	return utils.IsSortedAndUnique(&innerSortTransferableInputsWithSigners[T]{ins: ins, signers: signers})
 }
 
 // VerifyTx verifies that the inputs and outputs flowcheck, including a fee.
@@ -0,0 +1,321 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package index
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/dim4egster/qmallgo/codec"
+	"github.com/dim4egster/qmallgo/database"
+	"github.com/dim4egster/qmallgo/database/prefixdb"
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/utils/hashing"
+	"github.com/dim4egster/qmallgo/utils/logging"
+	"github.com/dim4egster/qmallgo/utils/wrappers"
+	"github.com/dim4egster/qmallgo/vms/components/avax"
+)
+
+const (
+	codecVersion = 0
+
+	// utxoRecordHeaderLen is the length of a UTXO record without the UTXO
+	utxoRecordHeaderLen = 2 * wrappers.LongLen
+
+	// acceptedRecordLen is the length of the record of an accepted tx
+	acceptedRecordLen = hashing.HashLen + wrappers.LongLen
+)
+
+var (
+	nextIndexKey       = []byte("nextIndex")
+	acceptedPrefix     = []byte("accepted")
+	utxosPrefix        = []byte("utxos")
+	errHistoryDisabled = errors.New("UTXO history indexing is disabled")
+	errIndexNotReached = errors.New("index hasn't been accepted yet")
+
+	_ UTXOHistoryIndexer = &utxoHistoryIndexer{}
+	_ UTXOHistoryIndexer = &noUTXOHistoryIndexer{}
+)
+
+// ContainerIndex is the node's index of the accepted containers of a chain,
+// such as the index served at /ext/index/X/tx.
+type ContainerIndex interface {
+	// GetAccepted returns the index of the accepted container [containerID]
+	// and the time at which it was accepted.
+	GetAccepted(containerID ids.ID) (uint64, time.Time, error)
+}
+
+// UTXOHistoryIndexer maintains the history of the UTXOs owned by addresses.
+//
+// The history is keyed by the indices that the node's ContainerIndex assigned
+// to the accepted transactions. The state at index N is the state after the
+// transaction at index N was accepted. Genesis UTXOs exist at every index.
+type UTXOHistoryIndexer interface {
+	// Genesis records that the genesis [utxos] were created.
+	Genesis(utxos []*avax.UTXO) error
+
+	// Accept is called when [txID] is accepted at [index], at unix time
+	// [timestamp]. Records that [inputUTXOs] were consumed, and [outputUTXOs]
+	// were created, at [index].
+	// If the error is non-nil, do not persist [txID] to disk as accepted in the VM
+	Accept(
+		txID ids.ID,
+		index uint64,
+		timestamp uint64,
+		inputUTXOs []*avax.UTXO,
+		outputUTXOs []*avax.UTXO,
+	) error
+
+	// Accepted returns the ID and the acceptance time of the transaction
+	// accepted at [index].
+	Accepted(index uint64) (ids.ID, uint64, error)
+
+	// UTXOs returns the UTXOs that referenced [address] at [index].
+	// The UTXOs are sorted by ID. Only UTXOs whose IDs are greater than
+	// [startUTXOID] are returned.
+	// Returns at most [limit] UTXOs, and the ID of the last UTXO searched.
+	UTXOs(address []byte, index uint64, startUTXOID ids.ID, limit int) ([]*avax.UTXO, ids.ID, error)
+}
+
+type utxoHistoryIndexer struct {
+	log   logging.Logger
+	codec codec.Manager
+
+	db         database.Database
+	acceptedDB database.Database
+	utxosDB    database.Database
+}
+
+// NewUTXOHistoryIndexer returns a new UTXOHistoryIndexer.
+// The returned indexer ignores UTXOs that are not avax.Addressable.
+func NewUTXOHistoryIndexer(
+	db database.Database,
+	codec codec.Manager,
+	log logging.Logger,
+	allowIncompleteIndices bool,
+) (UTXOHistoryIndexer, error) {
+	i := &utxoHistoryIndexer{
+		log:        log,
+		codec:      codec,
+		db:         db,
+		acceptedDB: prefixdb.New(acceptedPrefix, db),
+		utxosDB:    prefixdb.New(utxosPrefix, db),
+	}
+	return i, checkIndexStatus(db, true, allowIncompleteIndices)
+}
+
+func (i *utxoHistoryIndexer) Genesis(utxos []*avax.UTXO) error {
+	for _, utxo := range utxos {
+		if err := i.create(utxo, 0); err != nil {
+			return fmt.Errorf("failed to index creation of genesis UTXO %s: %w", utxo.InputID(), err)
+		}
+	}
+	return nil
+}
+
+// Accept persists the UTXOs [txID] consumed and created.
+// The database structure is:
+// "nextIndex" => 3               Index after the last accepted transaction
+// "accepted"
+// |  1        => txID1|time1
+// |  2        => txID2|time2
+// "utxos"
+// |  [address]
+// |  |  [utxoID] => created|consumed|utxo
+//
+// [created] and [consumed] are one more than the index the UTXO was created
+// and consumed at. [created] is 0 for genesis UTXOs, and [consumed] is 0 if
+// the UTXO hasn't been consumed.
+// See interface documentation UTXOHistoryIndexer.Accept
+func (i *utxoHistoryIndexer) Accept(
+	txID ids.ID,
+	index uint64,
+	timestamp uint64,
+	inputUTXOs []*avax.UTXO,
+	outputUTXOs []*avax.UTXO,
+) error {
+	for _, utxo := range inputUTXOs {
+		if err := i.consume(utxo, index+1); err != nil {
+			return fmt.Errorf("failed to index consumption of UTXO %s by tx %s: %w", utxo.InputID(), txID, err)
+		}
+	}
+	for _, utxo := range outputUTXOs {
+		if err := i.create(utxo, index+1); err != nil {
+			return fmt.Errorf("failed to index creation of UTXO %s by tx %s: %w", utxo.InputID(), txID, err)
+		}
+	}
+
+	p := wrappers.Packer{Bytes: make([]byte, acceptedRecordLen)}
+	p.PackFixedBytes(txID[:])
+	p.PackLong(timestamp)
+	if err := i.acceptedDB.Put(database.PackUInt64(index), p.Bytes); err != nil {
+		return fmt.Errorf("failed to index tx %s: %w", txID, err)
+	}
+
+	// A tx that was already indexed may be accepted again after a restart.
+	nextIndex, err := i.nextIndex()
+	if err != nil || index < nextIndex {
+		return err
+	}
+	return database.PutUInt64(i.db, nextIndexKey, index+1)
+}
+
+// create records that [utxo] was created. [created] is one more than the index
+// it was created at, or 0 for genesis UTXOs.
+func (i *utxoHistoryIndexer) create(utxo *avax.UTXO, created uint64) error {
+	out, ok := utxo.Out.(avax.Addressable)
+	if !ok {
+		i.log.Verbo("skipping UTXO for history indexing",
+			zap.Stringer("utxoID", utxo.InputID()),
+		)
+		return nil
+	}
+
+	utxoBytes, err := i.codec.Marshal(codecVersion, utxo)
+	if err != nil {
+		return err
+	}
+	p := wrappers.Packer{Bytes: make([]byte, utxoRecordHeaderLen+len(utxoBytes))}
+	p.PackLong(created)
+	p.PackLong(0)
+	p.PackFixedBytes(utxoBytes)
+
+	utxoID := utxo.InputID()
+	for _, address := range out.Addresses() {
+		addressDB := prefixdb.New(address, i.utxosDB)
+		if err := addressDB.Put(utxoID[:], p.Bytes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// consume records that [utxo] was consumed. [consumed] is one more than the
+// index it was consumed at.
+func (i *utxoHistoryIndexer) consume(utxo *avax.UTXO, consumed uint64) error {
+	out, ok := utxo.Out.(avax.Addressable)
+	if !ok {
+		return nil
+	}
+
+	utxoID := utxo.InputID()
+	for _, address := range out.Addresses() {
+		addressDB := prefixdb.New(address, i.utxosDB)
+		record, err := addressDB.Get(utxoID[:])
+		if err == database.ErrNotFound {
+			// The UTXO was created before the index was enabled.
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if len(record) < utxoRecordHeaderLen {
+			return fmt.Errorf("malformed record of UTXO %s", utxoID)
+		}
+
+		p := wrappers.Packer{Bytes: record, Offset: wrappers.LongLen}
+		p.PackLong(consumed)
+		if err := addressDB.Put(utxoID[:], p.Bytes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nextIndex returns the index after the last accepted transaction, or 0 if no
+// transaction was accepted.
+func (i *utxoHistoryIndexer) nextIndex() (uint64, error) {
+	nextIndex, err := database.GetUInt64(i.db, nextIndexKey)
+	if err == database.ErrNotFound {
+		return 0, nil
+	}
+	return nextIndex, err
+}
+
+func (i *utxoHistoryIndexer) Accepted(index uint64) (ids.ID, uint64, error) {
+	record, err := i.acceptedDB.Get(database.PackUInt64(index))
+	if err != nil {
+		return ids.Empty, 0, err
+	}
+
+	p := wrappers.Packer{Bytes: record}
+	txID, err := ids.ToID(p.UnpackFixedBytes(hashing.HashLen))
+	if err != nil {
+		return ids.Empty, 0, err
+	}
+	timestamp := p.UnpackLong()
+	return txID, timestamp, p.Err
+}
+
+func (i *utxoHistoryIndexer) UTXOs(address []byte, index uint64, startUTXOID ids.ID, limit int) ([]*avax.UTXO, ids.ID, error) {
+	nextIndex, err := i.nextIndex()
+	if err != nil {
+		return nil, ids.Empty, err
+	}
+	if index >= nextIndex {
+		return nil, ids.Empty, fmt.Errorf("%w: index %d >= next index %d", errIndexNotReached, index, nextIndex)
+	}
+
+	addressDB := prefixdb.New(address, i.utxosDB)
+	iter := addressDB.NewIteratorWithStart(startUTXOID[:])
+	defer iter.Release()
+
+	var (
+		utxos  []*avax.UTXO
+		lastID = startUTXOID
+	)
+	for len(utxos) < limit && iter.Next() {
+		utxoID, err := ids.ToID(iter.Key())
+		if err != nil {
+			return nil, ids.Empty, err
+		}
+		if utxoID == startUTXOID {
+			continue
+		}
+		lastID = utxoID
+
+		p := wrappers.Packer{Bytes: iter.Value()}
+		created := p.UnpackLong()
+		consumed := p.UnpackLong()
+		if p.Errored() {
+			return nil, ids.Empty, fmt.Errorf("malformed record of UTXO %s: %w", utxoID, p.Err)
+		}
+		if created > index+1 || (consumed != 0 && consumed <= index+1) {
+			// The UTXO didn't exist at [index]
+			continue
+		}
+
+		utxo := &avax.UTXO{}
+		if _, err := i.codec.Unmarshal(p.Bytes[p.Offset:], utxo); err != nil {
+			return nil, ids.Empty, fmt.Errorf("couldn't parse UTXO %s: %w", utxoID, err)
+		}
+		utxos = append(utxos, utxo)
+	}
+	return utxos, lastID, iter.Error()
+}
+
+type noUTXOHistoryIndexer struct{}
+
+func NewNoUTXOHistoryIndexer(db database.Database, allowIncomplete bool) (UTXOHistoryIndexer, error) {
+	return &noUTXOHistoryIndexer{}, checkIndexStatus(db, false, allowIncomplete)
+}
+
+func (*noUTXOHistoryIndexer) Genesis([]*avax.UTXO) error {
+	return nil
+}
+
+func (*noUTXOHistoryIndexer) Accept(ids.ID, uint64, uint64, []*avax.UTXO, []*avax.UTXO) error {
+	return nil
+}
+
+func (*noUTXOHistoryIndexer) Accepted(uint64) (ids.ID, uint64, error) {
+	return ids.Empty, 0, errHistoryDisabled
+}
+
+func (*noUTXOHistoryIndexer) UTXOs([]byte, uint64, ids.ID, int) ([]*avax.UTXO, ids.ID, error) {
+	return nil, ids.Empty, errHistoryDisabled
+}
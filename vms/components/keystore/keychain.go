@@ -0,0 +1,85 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keystore
+
+import (
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/utils/crypto/keychain"
+)
+
+var _ keychain.Keychain = &filteredKeychain{}
+
+// Keychain is the keychain a keystore service signs with.
+type Keychain struct {
+	keychain.Keychain
+
+	// Addrs are the addresses [Keychain] signs for. The first address is the
+	// default change address.
+	Addrs []ids.ShortID
+}
+
+// NewKeychain returns the keychain a keystore service of [u] signs with.
+//
+// If [external] is nil, the keys of [u] are loaded as GetKeychain loads them.
+// Otherwise the node signs through an external signer, so [u] only
+// authenticates the caller and [external] signs, restricted to [addresses] if
+// it is non-empty.
+func NewKeychain(external keychain.Keychain, u User, addresses ids.ShortSet) (*Keychain, error) {
+	if external == nil {
+		kc, err := GetKeychain(u, addresses)
+		if err != nil {
+			return nil, err
+		}
+		addrs := make([]ids.ShortID, len(kc.Keys))
+		for i, key := range kc.Keys {
+			addrs[i] = key.PublicKey().Address()
+		}
+		return &Keychain{
+			Keychain: kc,
+			Addrs:    addrs,
+		}, nil
+	}
+
+	if addresses.Len() == 0 {
+		addrs := external.Addresses().List()
+		ids.SortShortIDs(addrs)
+		return &Keychain{
+			Keychain: external,
+			Addrs:    addrs,
+		}, nil
+	}
+
+	kc := &filteredKeychain{
+		kc:    external,
+		addrs: ids.NewShortSet(addresses.Len()),
+	}
+	for addr := range addresses {
+		if _, ok := external.Get(addr); ok {
+			kc.addrs.Add(addr)
+		}
+	}
+	addrs := kc.addrs.List()
+	ids.SortShortIDs(addrs)
+	return &Keychain{
+		Keychain: kc,
+		Addrs:    addrs,
+	}, nil
+}
+
+// filteredKeychain only signs for a subset of the addresses of [kc].
+type filteredKeychain struct {
+	kc    keychain.Keychain
+	addrs ids.ShortSet
+}
+
+func (f *filteredKeychain) Get(addr ids.ShortID) (keychain.Signer, bool) {
+	if !f.addrs.Contains(addr) {
+		return nil, false
+	}
+	return f.kc.Get(addr)
+}
+
+func (f *filteredKeychain) Addresses() ids.ShortSet {
+	return f.addrs
+}
@@ -0,0 +1,65 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keystore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dim4egster/qmallgo/database/encdb"
+	"github.com/dim4egster/qmallgo/database/memdb"
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/vms/secp256k1fx"
+)
+
+func TestNewKeychain(t *testing.T) {
+	require := require.New(t)
+
+	db, err := encdb.New([]byte(testPassword), memdb.New())
+	require.NoError(err)
+	u := NewUserFromDB(db)
+
+	userKeys, err := NewKeys(u, 2)
+	require.NoError(err)
+	userAddrs := []ids.ShortID{
+		userKeys[0].PublicKey().Address(),
+		userKeys[1].PublicKey().Address(),
+	}
+
+	// Without an external signer, the keys of the user sign.
+	kc, err := NewKeychain(nil, u, nil)
+	require.NoError(err)
+	require.Equal(userAddrs, kc.Addrs)
+	_, ok := kc.Get(userAddrs[1])
+	require.True(ok)
+
+	external := secp256k1fx.NewKeychain()
+	externalKeys := make([]ids.ShortID, 3)
+	for i := range externalKeys {
+		key, err := external.New()
+		require.NoError(err)
+		externalKeys[i] = key.PublicKey().Address()
+	}
+
+	// With an external signer, only the external signer signs.
+	kc, err = NewKeychain(external, u, nil)
+	require.NoError(err)
+	require.Len(kc.Addrs, 3)
+	require.Equal(external.Addresses(), kc.Addresses())
+	_, ok = kc.Get(userAddrs[0])
+	require.False(ok)
+
+	// The external signer is restricted to the requested addresses it knows.
+	kc, err = NewKeychain(external, u, ids.ShortSet{
+		externalKeys[0]: struct{}{},
+		userAddrs[0]:    struct{}{},
+	})
+	require.NoError(err)
+	require.Equal([]ids.ShortID{externalKeys[0]}, kc.Addrs)
+	_, ok = kc.Get(externalKeys[0])
+	require.True(ok)
+	_, ok = kc.Get(externalKeys[1])
+	require.False(ok)
+}
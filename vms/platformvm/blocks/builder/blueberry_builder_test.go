@@ -63,7 +63,7 @@ func TestBlueberryFork(t *testing.T) {
 			ids.GenerateTestNodeID(),
 			ids.GenerateTestShortID(),
 			reward.PercentDenominator,
-			[]*crypto.PrivateKeySECP256K1R{preFundedKeys[i]},
+			secp256k1fx.NewKeychain(preFundedKeys[i]),
 			ids.ShortEmpty,
 		)
 		require.NoError(err)
@@ -110,7 +110,7 @@ func TestBlueberryFork(t *testing.T) {
 		constants.AVMID,
 		nil,
 		"chain name",
-		[]*crypto.PrivateKeySECP256K1R{preFundedKeys[0], preFundedKeys[1]},
+		secp256k1fx.NewKeychain(preFundedKeys[0], preFundedKeys[1]),
 		ids.ShortEmpty,
 	)
 	require.NoError(err)
@@ -20,6 +20,7 @@ import (
 	"github.com/dim4egster/qmallgo/database/prefixdb"
 	"github.com/dim4egster/qmallgo/database/versiondb"
 	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/pubsub"
 	"github.com/dim4egster/qmallgo/snow"
 	"github.com/dim4egster/qmallgo/snow/engine/common"
 	"github.com/dim4egster/qmallgo/snow/uptime"
@@ -188,6 +189,7 @@ func newEnvironment(t *testing.T) *environment {
 		res.state,
 		&res.backend,
 		window,
+		pubsub.New(0, logging.NoLog{}),
 	)
 
 	res.Builder = New(
@@ -215,7 +217,7 @@ func addSubnet(env *environment) {
 			preFundedKeys[1].PublicKey().Address(),
 			preFundedKeys[2].PublicKey().Address(),
 		},
-		[]*crypto.PrivateKeySECP256K1R{preFundedKeys[0]},
+		secp256k1fx.NewKeychain(preFundedKeys[0]),
 		preFundedKeys[0].PublicKey().Address(),
 	)
 	if err != nil {
@@ -10,9 +10,9 @@ import (
 
 	"github.com/dim4egster/qmallgo/ids"
 	"github.com/dim4egster/qmallgo/utils/constants"
-	"github.com/dim4egster/qmallgo/utils/crypto"
 	"github.com/dim4egster/qmallgo/vms/platformvm/message"
 	"github.com/dim4egster/qmallgo/vms/platformvm/txs"
+	"github.com/dim4egster/qmallgo/vms/secp256k1fx"
 
 	txbuilder "github.com/dim4egster/qmallgo/vms/platformvm/txs/builder"
 )
@@ -24,7 +24,7 @@ func getValidTx(txBuilder txbuilder.Builder, t *testing.T) *txs.Tx {
 		constants.AVMID,
 		nil,
 		"chain name",
-		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+		secp256k1fx.NewKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 		ids.ShortEmpty,
 	)
 	if err != nil {
@@ -12,7 +12,6 @@ import (
 	"github.com/dim4egster/qmallgo/chains/atomic"
 	"github.com/dim4egster/qmallgo/database/prefixdb"
 	"github.com/dim4egster/qmallgo/ids"
-	"github.com/dim4egster/qmallgo/utils/crypto"
 	"github.com/dim4egster/qmallgo/vms/components/avax"
 	"github.com/dim4egster/qmallgo/vms/platformvm/blocks"
 	"github.com/dim4egster/qmallgo/vms/platformvm/status"
@@ -71,7 +70,7 @@ func TestAtomicTxImports(t *testing.T) {
 	tx, err := env.txBuilder.NewImportTx(
 		env.ctx.XChainID,
 		recipientKey.PublicKey().Address(),
-		[]*crypto.PrivateKeySECP256K1R{recipientKey},
+		secp256k1fx.NewKeychain(recipientKey),
 		ids.ShortEmpty, // change addr
 	)
 	require.NoError(err)
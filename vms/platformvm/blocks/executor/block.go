@@ -13,6 +13,8 @@ import (
 	"github.com/dim4egster/qmallgo/snow/choices"
 	"github.com/dim4egster/qmallgo/snow/consensus/snowman"
 	"github.com/dim4egster/qmallgo/vms/platformvm/blocks"
+	"github.com/dim4egster/qmallgo/vms/platformvm/state"
+	"github.com/dim4egster/qmallgo/vms/platformvm/txs"
 )
 
 var (
@@ -37,7 +39,39 @@ func (b *Block) Verify() error {
 }
 
 func (b *Block) Accept() error {
-	return b.Visit(b.manager.acceptor)
+	// The state of the parent is freed once this block is accepted, so the
+	// decided txs are looked up first.
+	decidedTxs, err := b.decidedTxs()
+	if err != nil {
+		return err
+	}
+	if err := b.Visit(b.manager.acceptor); err != nil {
+		return err
+	}
+	for _, tx := range decidedTxs {
+		b.manager.pubsub.Publish(NewPubSubFilterer(tx))
+	}
+	return nil
+}
+
+// decidedTxs returns the txs whose outcome is known once this block is
+// accepted. The tx of a proposal block is only decided once its commit or
+// abort block is accepted.
+func (b *Block) decidedTxs() ([]*txs.Tx, error) {
+	switch b.Block.(type) {
+	case *blocks.ApricotProposalBlock, *blocks.BlueberryProposalBlock:
+		return nil, nil
+	case *blocks.ApricotCommitBlock, *blocks.BlueberryCommitBlock,
+		*blocks.ApricotAbortBlock, *blocks.BlueberryAbortBlock:
+		parentID := b.Parent()
+		parentState, ok := b.manager.blkIDToState[parentID]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", state.ErrMissingParentState, parentID)
+		}
+		return parentState.statelessBlock.Txs(), nil
+	default:
+		return b.Txs(), nil
+	}
 }
 
 func (b *Block) Reject() error {
@@ -5,17 +5,22 @@ package executor
 
 import (
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/dim4egster/qmallgo/api"
 	"github.com/dim4egster/qmallgo/database"
 	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/pubsub"
 	"github.com/dim4egster/qmallgo/snow/choices"
 	"github.com/dim4egster/qmallgo/snow/consensus/snowman"
 	"github.com/dim4egster/qmallgo/vms/platformvm/blocks"
 	"github.com/dim4egster/qmallgo/vms/platformvm/state"
+	"github.com/dim4egster/qmallgo/vms/platformvm/txs"
+	"github.com/dim4egster/qmallgo/vms/secp256k1fx"
 )
 
 func TestStatus(t *testing.T) {
@@ -254,3 +259,96 @@ func TestBlockOptions(t *testing.T) {
 		})
 	}
 }
+
+var (
+	_ blocks.Visitor = &noopAcceptor{}
+	_ publisher      = &testPublisher{}
+)
+
+// noopAcceptor accepts blocks without changing any state.
+type noopAcceptor struct{}
+
+func (*noopAcceptor) BlueberryAbortBlock(*blocks.BlueberryAbortBlock) error       { return nil }
+func (*noopAcceptor) BlueberryCommitBlock(*blocks.BlueberryCommitBlock) error     { return nil }
+func (*noopAcceptor) BlueberryProposalBlock(*blocks.BlueberryProposalBlock) error { return nil }
+func (*noopAcceptor) BlueberryStandardBlock(*blocks.BlueberryStandardBlock) error { return nil }
+func (*noopAcceptor) ApricotAbortBlock(*blocks.ApricotAbortBlock) error           { return nil }
+func (*noopAcceptor) ApricotCommitBlock(*blocks.ApricotCommitBlock) error         { return nil }
+func (*noopAcceptor) ApricotProposalBlock(*blocks.ApricotProposalBlock) error     { return nil }
+func (*noopAcceptor) ApricotStandardBlock(*blocks.ApricotStandardBlock) error     { return nil }
+func (*noopAcceptor) ApricotAtomicBlock(*blocks.ApricotAtomicBlock) error         { return nil }
+
+// testPublisher records the IDs of the published txs.
+type testPublisher struct {
+	txIDs []ids.ID
+}
+
+func (p *testPublisher) Publish(filterer pubsub.Filterer) {
+	_, msg := filterer.Filter(nil)
+	p.txIDs = append(p.txIDs, msg.(api.JSONTxID).TxID)
+}
+
+func TestAcceptPublishesDecidedTxs(t *testing.T) {
+	tests := []struct {
+		name    string
+		optionF func(parentID ids.ID) (blocks.Block, error)
+	}{
+		{
+			name: "apricot commit",
+			optionF: func(parentID ids.ID) (blocks.Block, error) {
+				return blocks.NewApricotCommitBlock(parentID, 2)
+			},
+		},
+		{
+			name: "apricot abort",
+			optionF: func(parentID ids.ID) (blocks.Block, error) {
+				return blocks.NewApricotAbortBlock(parentID, 2)
+			},
+		},
+		{
+			name: "blueberry commit",
+			optionF: func(parentID ids.ID) (blocks.Block, error) {
+				return blocks.NewBlueberryCommitBlock(time.Unix(1, 0), parentID, 2)
+			},
+		},
+		{
+			name: "blueberry abort",
+			optionF: func(parentID ids.ID) (blocks.Block, error) {
+				return blocks.NewBlueberryAbortBlock(time.Unix(1, 0), parentID, 2)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			tx := &txs.Tx{Unsigned: &txs.AddDelegatorTx{
+				DelegationRewardsOwner: &secp256k1fx.OutputOwners{},
+			}}
+			require.NoError(tx.Sign(txs.Codec, nil))
+			proposalBlk, err := blocks.NewApricotProposalBlock(ids.GenerateTestID(), 1, tx)
+			require.NoError(err)
+			optionBlk, err := tt.optionF(proposalBlk.ID())
+			require.NoError(err)
+
+			publisher := &testPublisher{}
+			manager := &manager{
+				backend: &backend{
+					blkIDToState: map[ids.ID]*blockState{
+						proposalBlk.ID(): {statelessBlock: proposalBlk},
+					},
+				},
+				acceptor: &noopAcceptor{},
+				pubsub:   publisher,
+			}
+
+			// The tx isn't published until it is known whether it was
+			// committed or aborted.
+			require.NoError(manager.NewBlock(proposalBlk).Accept())
+			require.Empty(publisher.txIDs)
+
+			require.NoError(manager.NewBlock(optionBlk).Accept())
+			require.Equal([]ids.ID{tx.ID()}, publisher.txIDs)
+		})
+	}
+}
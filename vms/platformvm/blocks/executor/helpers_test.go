@@ -21,6 +21,7 @@ import (
 	"github.com/dim4egster/qmallgo/database/prefixdb"
 	"github.com/dim4egster/qmallgo/database/versiondb"
 	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/pubsub"
 	"github.com/dim4egster/qmallgo/snow"
 	"github.com/dim4egster/qmallgo/snow/engine/common"
 	"github.com/dim4egster/qmallgo/snow/uptime"
@@ -223,6 +224,7 @@ func newEnvironment(t *testing.T, ctrl *gomock.Controller) *environment {
 			res.state,
 			res.backend,
 			window,
+			pubsub.New(0, logging.NoLog{}),
 		)
 		addSubnet(res)
 	} else {
@@ -232,6 +234,7 @@ func newEnvironment(t *testing.T, ctrl *gomock.Controller) *environment {
 			res.mockedState,
 			res.backend,
 			window,
+			pubsub.New(0, logging.NoLog{}),
 		)
 		// we do not add any subnet to state, since we can mock
 		// whatever we need
@@ -250,7 +253,7 @@ func addSubnet(env *environment) {
 			preFundedKeys[1].PublicKey().Address(),
 			preFundedKeys[2].PublicKey().Address(),
 		},
-		[]*crypto.PrivateKeySECP256K1R{preFundedKeys[0]},
+		secp256k1fx.NewKeychain(preFundedKeys[0]),
 		preFundedKeys[0].PublicKey().Address(),
 	)
 	if err != nil {
@@ -506,7 +509,7 @@ func addPendingValidator(
 		nodeID,
 		rewardAddress,
 		reward.PercentDenominator,
-		keys,
+		secp256k1fx.NewKeychain(keys...),
 		ids.ShortEmpty,
 	)
 	if err != nil {
@@ -5,6 +5,7 @@ package executor
 
 import (
 	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/pubsub"
 	"github.com/dim4egster/qmallgo/snow/consensus/snowman"
 	"github.com/dim4egster/qmallgo/utils/window"
 	"github.com/dim4egster/qmallgo/vms/platformvm/blocks"
@@ -32,6 +33,7 @@ func NewManager(
 	s state.State,
 	txExecutorBackend *executor.Backend,
 	recentlyAccepted window.Window[ids.ID],
+	pubsubServer *pubsub.Server,
 ) Manager {
 	backend := &backend{
 		Mempool:      mempool,
@@ -54,6 +56,7 @@ func NewManager(
 			bootstrapped:     txExecutorBackend.Bootstrapped,
 		},
 		rejector: &rejector{backend: backend},
+		pubsub:   pubsubServer,
 	}
 }
 
@@ -62,6 +65,14 @@ type manager struct {
 	verifier blocks.Visitor
 	acceptor blocks.Visitor
 	rejector blocks.Visitor
+
+	// pubsub is notified of the txs in accepted blocks
+	pubsub publisher
+}
+
+// publisher notifies the subscribed connections of txs.
+type publisher interface {
+	Publish(pubsub.Filterer)
 }
 
 func (m *manager) GetBlock(blkID ids.ID) (snowman.Block, error) {
@@ -569,7 +569,7 @@ func TestBlueberryProposalBlockUpdateStakers(t *testing.T) {
 					staker.nodeID,
 					staker.rewardAddress,
 					reward.PercentDenominator,
-					[]*crypto.PrivateKeySECP256K1R{preFundedKeys[0]},
+					secp256k1fx.NewKeychain(preFundedKeys[0]),
 					ids.ShortEmpty,
 				)
 				require.NoError(err)
@@ -591,7 +591,7 @@ func TestBlueberryProposalBlockUpdateStakers(t *testing.T) {
 					uint64(subStaker.endTime.Unix()),
 					subStaker.nodeID, // validator ID
 					testSubnet1.ID(), // Subnet ID
-					[]*crypto.PrivateKeySECP256K1R{preFundedKeys[0], preFundedKeys[1]},
+					secp256k1fx.NewKeychain(preFundedKeys[0], preFundedKeys[1]),
 					ids.ShortEmpty,
 				)
 				require.NoError(err)
@@ -619,7 +619,7 @@ func TestBlueberryProposalBlockUpdateStakers(t *testing.T) {
 					staker0.nodeID,
 					staker0.rewardAddress,
 					reward.PercentDenominator,
-					[]*crypto.PrivateKeySECP256K1R{preFundedKeys[0], preFundedKeys[1]},
+					secp256k1fx.NewKeychain(preFundedKeys[0], preFundedKeys[1]),
 					ids.ShortEmpty,
 				)
 				require.NoError(err)
@@ -714,7 +714,7 @@ func TestBlueberryProposalBlockRemoveSubnetValidator(t *testing.T) {
 		uint64(subnetVdr1EndTime.Unix()),   // end time
 		subnetValidatorNodeID,              // Node ID
 		testSubnet1.ID(),                   // Subnet ID
-		[]*crypto.PrivateKeySECP256K1R{preFundedKeys[0], preFundedKeys[1]},
+		secp256k1fx.NewKeychain(preFundedKeys[0], preFundedKeys[1]),
 		ids.ShortEmpty,
 	)
 	require.NoError(err)
@@ -739,7 +739,7 @@ func TestBlueberryProposalBlockRemoveSubnetValidator(t *testing.T) {
 		uint64(subnetVdr1EndTime.Add(time.Second).Add(defaultMinStakingDuration).Unix()), // end time
 		subnetVdr2NodeID, // Node ID
 		testSubnet1.ID(), // Subnet ID
-		[]*crypto.PrivateKeySECP256K1R{preFundedKeys[0], preFundedKeys[1]},
+		secp256k1fx.NewKeychain(preFundedKeys[0], preFundedKeys[1]),
 		ids.ShortEmpty,
 	)
 	require.NoError(err)
@@ -769,7 +769,7 @@ func TestBlueberryProposalBlockRemoveSubnetValidator(t *testing.T) {
 		ids.GenerateTestNodeID(),
 		ids.GenerateTestShortID(),
 		reward.PercentDenominator,
-		[]*crypto.PrivateKeySECP256K1R{preFundedKeys[0], preFundedKeys[1]},
+		secp256k1fx.NewKeychain(preFundedKeys[0], preFundedKeys[1]),
 		ids.ShortEmpty,
 	)
 	require.NoError(err)
@@ -850,7 +850,7 @@ func TestBlueberryProposalBlockWhitelistedSubnet(t *testing.T) {
 				uint64(subnetVdr1EndTime.Unix()),   // end time
 				subnetValidatorNodeID,              // Node ID
 				testSubnet1.ID(),                   // Subnet ID
-				[]*crypto.PrivateKeySECP256K1R{preFundedKeys[0], preFundedKeys[1]},
+				secp256k1fx.NewKeychain(preFundedKeys[0], preFundedKeys[1]),
 				ids.ShortEmpty,
 			)
 			require.NoError(err)
@@ -878,7 +878,7 @@ func TestBlueberryProposalBlockWhitelistedSubnet(t *testing.T) {
 				ids.GenerateTestNodeID(),
 				ids.GenerateTestShortID(),
 				reward.PercentDenominator,
-				[]*crypto.PrivateKeySECP256K1R{preFundedKeys[0], preFundedKeys[1]},
+				secp256k1fx.NewKeychain(preFundedKeys[0], preFundedKeys[1]),
 				ids.ShortEmpty,
 			)
 			require.NoError(err)
@@ -963,7 +963,7 @@ func TestBlueberryProposalBlockDelegatorStakerWeight(t *testing.T) {
 		ids.GenerateTestNodeID(),
 		ids.GenerateTestShortID(),
 		reward.PercentDenominator,
-		[]*crypto.PrivateKeySECP256K1R{preFundedKeys[0], preFundedKeys[1]},
+		secp256k1fx.NewKeychain(preFundedKeys[0], preFundedKeys[1]),
 		ids.ShortEmpty,
 	)
 	require.NoError(err)
@@ -1024,11 +1024,7 @@ func TestBlueberryProposalBlockDelegatorStakerWeight(t *testing.T) {
 		uint64(pendingDelegatorEndTime.Unix()),
 		nodeID,
 		preFundedKeys[0].PublicKey().Address(),
-		[]*crypto.PrivateKeySECP256K1R{
-			preFundedKeys[0],
-			preFundedKeys[1],
-			preFundedKeys[4],
-		},
+		secp256k1fx.NewKeychain(preFundedKeys[0], preFundedKeys[1], preFundedKeys[4]),
 		ids.ShortEmpty,
 	)
 	require.NoError(err)
@@ -1053,7 +1049,7 @@ func TestBlueberryProposalBlockDelegatorStakerWeight(t *testing.T) {
 		ids.GenerateTestNodeID(),
 		ids.GenerateTestShortID(),
 		reward.PercentDenominator,
-		[]*crypto.PrivateKeySECP256K1R{preFundedKeys[0], preFundedKeys[1]},
+		secp256k1fx.NewKeychain(preFundedKeys[0], preFundedKeys[1]),
 		ids.ShortEmpty,
 	)
 	require.NoError(err)
@@ -1144,7 +1140,7 @@ func TestBlueberryProposalBlockDelegatorStakers(t *testing.T) {
 		ids.GenerateTestNodeID(),
 		ids.GenerateTestShortID(),
 		reward.PercentDenominator,
-		[]*crypto.PrivateKeySECP256K1R{preFundedKeys[0], preFundedKeys[1]},
+		secp256k1fx.NewKeychain(preFundedKeys[0], preFundedKeys[1]),
 		ids.ShortEmpty,
 	)
 	require.NoError(err)
@@ -1204,11 +1200,7 @@ func TestBlueberryProposalBlockDelegatorStakers(t *testing.T) {
 		uint64(pendingDelegatorEndTime.Unix()),
 		nodeID,
 		preFundedKeys[0].PublicKey().Address(),
-		[]*crypto.PrivateKeySECP256K1R{
-			preFundedKeys[0],
-			preFundedKeys[1],
-			preFundedKeys[4],
-		},
+		secp256k1fx.NewKeychain(preFundedKeys[0], preFundedKeys[1], preFundedKeys[4]),
 		ids.ShortEmpty,
 	)
 	require.NoError(err)
@@ -1233,7 +1225,7 @@ func TestBlueberryProposalBlockDelegatorStakers(t *testing.T) {
 		ids.GenerateTestNodeID(),
 		ids.GenerateTestShortID(),
 		reward.PercentDenominator,
-		[]*crypto.PrivateKeySECP256K1R{preFundedKeys[0], preFundedKeys[1]},
+		secp256k1fx.NewKeychain(preFundedKeys[0], preFundedKeys[1]),
 		ids.ShortEmpty,
 	)
 	require.NoError(err)
@@ -0,0 +1,146 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package executor
+
+import (
+	"github.com/dim4egster/qmallgo/pubsub"
+	"github.com/dim4egster/qmallgo/vms/components/avax"
+	"github.com/dim4egster/qmallgo/vms/platformvm/fx"
+	"github.com/dim4egster/qmallgo/vms/platformvm/stakeable"
+	"github.com/dim4egster/qmallgo/vms/platformvm/txs"
+	"github.com/dim4egster/qmallgo/vms/secp256k1fx"
+)
+
+var _ txs.Visitor = &txAttributesGetter{}
+
+// NewPubSubFilterer returns a Filterer that notifies the connections that are
+// interested in [tx] of its ID.
+func NewPubSubFilterer(tx *txs.Tx) pubsub.Filterer {
+	g := &txAttributesGetter{
+		attrs: &pubsub.TxAttributes{},
+	}
+	// The visit error is explicitly dropped here because no error is ever
+	// returned from the txAttributesGetter.
+	_ = tx.Unsigned.Visit(g)
+	return pubsub.NewTxIDFilterer(tx.ID(), g.attrs)
+}
+
+// txAttributesGetter populates the attributes of a tx that can be matched by
+// pubsub filters.
+type txAttributesGetter struct {
+	attrs *pubsub.TxAttributes
+}
+
+func (g *txAttributesGetter) AddValidatorTx(tx *txs.AddValidatorTx) error {
+	g.baseTx("AddValidatorTx", &tx.BaseTx)
+	g.outputs(tx.StakeOuts)
+	g.owner(tx.RewardsOwner)
+	return nil
+}
+
+func (g *txAttributesGetter) AddSubnetValidatorTx(tx *txs.AddSubnetValidatorTx) error {
+	g.baseTx("AddSubnetValidatorTx", &tx.BaseTx)
+	return nil
+}
+
+func (g *txAttributesGetter) AddDelegatorTx(tx *txs.AddDelegatorTx) error {
+	g.baseTx("AddDelegatorTx", &tx.BaseTx)
+	g.outputs(tx.StakeOuts)
+	g.owner(tx.DelegationRewardsOwner)
+	return nil
+}
+
+func (g *txAttributesGetter) CreateChainTx(tx *txs.CreateChainTx) error {
+	g.baseTx("CreateChainTx", &tx.BaseTx)
+	return nil
+}
+
+func (g *txAttributesGetter) CreateSubnetTx(tx *txs.CreateSubnetTx) error {
+	g.baseTx("CreateSubnetTx", &tx.BaseTx)
+	g.owner(tx.Owner)
+	return nil
+}
+
+func (g *txAttributesGetter) ImportTx(tx *txs.ImportTx) error {
+	g.baseTx("ImportTx", &tx.BaseTx)
+	g.inputs(tx.ImportedInputs)
+	return nil
+}
+
+func (g *txAttributesGetter) ExportTx(tx *txs.ExportTx) error {
+	g.baseTx("ExportTx", &tx.BaseTx)
+	g.outputs(tx.ExportedOutputs)
+	return nil
+}
+
+func (g *txAttributesGetter) AdvanceTimeTx(*txs.AdvanceTimeTx) error {
+	g.attrs.TxType = "AdvanceTimeTx"
+	return nil
+}
+
+func (g *txAttributesGetter) RewardValidatorTx(*txs.RewardValidatorTx) error {
+	g.attrs.TxType = "RewardValidatorTx"
+	return nil
+}
+
+func (g *txAttributesGetter) RemoveSubnetValidatorTx(tx *txs.RemoveSubnetValidatorTx) error {
+	g.baseTx("RemoveSubnetValidatorTx", &tx.BaseTx)
+	return nil
+}
+
+func (g *txAttributesGetter) TransformSubnetTx(tx *txs.TransformSubnetTx) error {
+	g.baseTx("TransformSubnetTx", &tx.BaseTx)
+	g.attrs.AssetIDs.Add(tx.AssetID)
+	return nil
+}
+
+func (g *txAttributesGetter) AddPermissionlessValidatorTx(tx *txs.AddPermissionlessValidatorTx) error {
+	g.baseTx("AddPermissionlessValidatorTx", &tx.BaseTx)
+	g.outputs(tx.StakeOuts)
+	g.owner(tx.ValidatorRewardsOwner)
+	g.owner(tx.DelegatorRewardsOwner)
+	return nil
+}
+
+func (g *txAttributesGetter) AddPermissionlessDelegatorTx(tx *txs.AddPermissionlessDelegatorTx) error {
+	g.baseTx("AddPermissionlessDelegatorTx", &tx.BaseTx)
+	g.outputs(tx.StakeOuts)
+	g.owner(tx.DelegationRewardsOwner)
+	return nil
+}
+
+func (g *txAttributesGetter) baseTx(txType string, tx *txs.BaseTx) {
+	g.attrs.TxType = txType
+	g.attrs.Memo = tx.Memo
+	g.inputs(tx.Ins)
+	g.outputs(tx.Outs)
+}
+
+func (g *txAttributesGetter) inputs(ins []*avax.TransferableInput) {
+	for _, in := range ins {
+		g.attrs.AssetIDs.Add(in.AssetID())
+	}
+}
+
+func (g *txAttributesGetter) outputs(outs []*avax.TransferableOutput) {
+	for _, out := range outs {
+		g.attrs.AssetIDs.Add(out.AssetID())
+		innerOut := out.Out
+		if lockOut, ok := innerOut.(*stakeable.LockOut); ok {
+			innerOut = lockOut.TransferableOut
+		}
+		if owned, ok := innerOut.(fx.Owned); ok {
+			g.owner(owned.Owners())
+		}
+	}
+}
+
+func (g *txAttributesGetter) owner(owner interface{}) {
+	owners, ok := owner.(*secp256k1fx.OutputOwners)
+	if !ok {
+		return
+	}
+	g.attrs.Addresses = append(g.attrs.Addresses, owners.Addresses()...)
+	g.attrs.Thresholds = append(g.attrs.Thresholds, owners.Threshold)
+}
@@ -0,0 +1,100 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dim4egster/qmallgo/api"
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/pubsub"
+	"github.com/dim4egster/qmallgo/vms/components/avax"
+	"github.com/dim4egster/qmallgo/vms/platformvm/stakeable"
+	"github.com/dim4egster/qmallgo/vms/platformvm/txs"
+	"github.com/dim4egster/qmallgo/vms/secp256k1fx"
+)
+
+var _ pubsub.TxMatcher = &attrsRecorder{}
+
+// attrsRecorder records the attributes it is matched against.
+type attrsRecorder struct {
+	attrs *pubsub.TxAttributes
+}
+
+func (*attrsRecorder) Check([]byte) bool { return false }
+
+func (r *attrsRecorder) Match(attrs *pubsub.TxAttributes) bool {
+	r.attrs = attrs
+	return true
+}
+
+func TestPubSubFiltererAddValidatorTx(t *testing.T) {
+	require := require.New(t)
+
+	assetID := ids.GenerateTestID()
+	stakeAddr := ids.GenerateTestShortID()
+	rewardAddr := ids.GenerateTestShortID()
+	memo := []byte("staking")
+
+	tx := &txs.Tx{Unsigned: &txs.AddValidatorTx{
+		BaseTx: txs.BaseTx{BaseTx: avax.BaseTx{
+			Memo: memo,
+		}},
+		StakeOuts: []*avax.TransferableOutput{{
+			Asset: avax.Asset{ID: assetID},
+			Out: &stakeable.LockOut{
+				Locktime: 1,
+				TransferableOut: &secp256k1fx.TransferOutput{
+					Amt: 1,
+					OutputOwners: secp256k1fx.OutputOwners{
+						Threshold: 1,
+						Addrs:     []ids.ShortID{stakeAddr},
+					},
+				},
+			},
+		}},
+		RewardsOwner: &secp256k1fx.OutputOwners{
+			Threshold: 2,
+			Addrs:     []ids.ShortID{rewardAddr},
+		},
+	}}
+	require.NoError(tx.Sign(txs.Codec, nil))
+
+	recorder := &attrsRecorder{}
+	notify, msg := NewPubSubFilterer(tx).Filter([]pubsub.Filter{recorder})
+	require.Equal([]bool{true}, notify)
+	require.Equal(api.JSONTxID{TxID: tx.ID()}, msg)
+
+	attrs := recorder.attrs
+	require.Equal("AddValidatorTx", attrs.TxType)
+	require.True(attrs.AssetIDs.Contains(assetID))
+	require.Equal([][]byte{stakeAddr[:], rewardAddr[:]}, attrs.Addresses)
+	require.Equal([]uint32{1, 2}, attrs.Thresholds)
+	require.Equal(memo, attrs.Memo)
+}
+
+func TestPubSubFiltererCreateSubnetTx(t *testing.T) {
+	require := require.New(t)
+
+	ownerAddr := ids.GenerateTestShortID()
+	tx := &txs.Tx{Unsigned: &txs.CreateSubnetTx{
+		Owner: &secp256k1fx.OutputOwners{
+			Threshold: 1,
+			Addrs:     []ids.ShortID{ownerAddr},
+		},
+	}}
+	require.NoError(tx.Sign(txs.Codec, nil))
+
+	addressFilter := pubsub.NewFilterParam()
+	require.NoError(addressFilter.Add(ownerAddr[:]))
+	otherFilter := pubsub.NewFilterParam()
+
+	recorder := &attrsRecorder{}
+	notify, _ := NewPubSubFilterer(tx).Filter([]pubsub.Filter{addressFilter, otherFilter, recorder})
+	require.Equal([]bool{true, false, true}, notify)
+	require.Equal("CreateSubnetTx", recorder.attrs.TxType)
+	require.Equal([]uint32{1}, recorder.attrs.Thresholds)
+}
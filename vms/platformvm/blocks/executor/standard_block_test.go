@@ -16,6 +16,7 @@ import (
 	"github.com/dim4egster/qmallgo/ids"
 	"github.com/dim4egster/qmallgo/utils/constants"
 	"github.com/dim4egster/qmallgo/utils/crypto"
+	"github.com/dim4egster/qmallgo/utils/crypto/keychain"
 	"github.com/dim4egster/qmallgo/vms/components/avax"
 	"github.com/dim4egster/qmallgo/vms/platformvm/blocks"
 	"github.com/dim4egster/qmallgo/vms/platformvm/state"
@@ -178,7 +179,7 @@ func TestBlueberryStandardBlockTimeVerification(t *testing.T) {
 		Owner: &secp256k1fx.OutputOwners{},
 	}
 	tx := &txs.Tx{Unsigned: utx}
-	require.NoError(tx.Sign(txs.Codec, [][]*crypto.PrivateKeySECP256K1R{{}}))
+	require.NoError(tx.Sign(txs.Codec, [][]keychain.Signer{{}}))
 
 	{
 		// wrong version
@@ -514,7 +515,7 @@ func TestBlueberryStandardBlockUpdateStakers(t *testing.T) {
 					uint64(staker.endTime.Unix()),
 					staker.nodeID,    // validator ID
 					testSubnet1.ID(), // Subnet ID
-					[]*crypto.PrivateKeySECP256K1R{preFundedKeys[0], preFundedKeys[1]},
+					secp256k1fx.NewKeychain(preFundedKeys[0], preFundedKeys[1]),
 					ids.ShortEmpty,
 				)
 				require.NoError(err)
@@ -603,7 +604,7 @@ func TestBlueberryStandardBlockRemoveSubnetValidator(t *testing.T) {
 		uint64(subnetVdr1EndTime.Unix()),   // end time
 		subnetValidatorNodeID,              // Node ID
 		testSubnet1.ID(),                   // Subnet ID
-		[]*crypto.PrivateKeySECP256K1R{preFundedKeys[0], preFundedKeys[1]},
+		secp256k1fx.NewKeychain(preFundedKeys[0], preFundedKeys[1]),
 		ids.ShortEmpty,
 	)
 	require.NoError(err)
@@ -628,7 +629,7 @@ func TestBlueberryStandardBlockRemoveSubnetValidator(t *testing.T) {
 		uint64(subnetVdr1EndTime.Add(time.Second).Add(defaultMinStakingDuration).Unix()), // end time
 		subnetVdr2NodeID, // Node ID
 		testSubnet1.ID(), // Subnet ID
-		[]*crypto.PrivateKeySECP256K1R{preFundedKeys[0], preFundedKeys[1]},
+		secp256k1fx.NewKeychain(preFundedKeys[0], preFundedKeys[1]),
 		ids.ShortEmpty,
 	)
 	require.NoError(err)
@@ -700,7 +701,7 @@ func TestBlueberryStandardBlockWhitelistedSubnet(t *testing.T) {
 				uint64(subnetVdr1EndTime.Unix()),   // end time
 				subnetValidatorNodeID,              // Node ID
 				testSubnet1.ID(),                   // Subnet ID
-				[]*crypto.PrivateKeySECP256K1R{preFundedKeys[0], preFundedKeys[1]},
+				secp256k1fx.NewKeychain(preFundedKeys[0], preFundedKeys[1]),
 				ids.ShortEmpty,
 			)
 			require.NoError(err)
@@ -796,11 +797,7 @@ func TestBlueberryStandardBlockDelegatorStakerWeight(t *testing.T) {
 		uint64(pendingDelegatorEndTime.Unix()),
 		nodeID,
 		preFundedKeys[0].PublicKey().Address(),
-		[]*crypto.PrivateKeySECP256K1R{
-			preFundedKeys[0],
-			preFundedKeys[1],
-			preFundedKeys[4],
-		},
+		secp256k1fx.NewKeychain(preFundedKeys[0], preFundedKeys[1], preFundedKeys[4]),
 		ids.ShortEmpty,
 	)
 	require.NoError(err)
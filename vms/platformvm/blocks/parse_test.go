@@ -12,6 +12,7 @@ import (
 	"github.com/dim4egster/qmallgo/codec"
 	"github.com/dim4egster/qmallgo/ids"
 	"github.com/dim4egster/qmallgo/utils/crypto"
+	"github.com/dim4egster/qmallgo/utils/crypto/keychain"
 	"github.com/dim4egster/qmallgo/vms/components/avax"
 	"github.com/dim4egster/qmallgo/vms/platformvm/txs"
 	"github.com/dim4egster/qmallgo/vms/secp256k1fx"
@@ -294,7 +295,7 @@ func testAtomicTx() (*txs.Tx, error) {
 			},
 		}},
 	}
-	signers := [][]*crypto.PrivateKeySECP256K1R{{preFundedKeys[0]}}
+	signers := [][]keychain.Signer{{preFundedKeys[0]}}
 	return txs.NewSigned(utx, txs.Codec, signers)
 }
 
@@ -338,7 +339,7 @@ func testDecisionTxs() ([]*txs.Tx, error) {
 			SubnetAuth:  &secp256k1fx.Input{SigIndices: []uint32{1}},
 		}
 
-		signers := [][]*crypto.PrivateKeySECP256K1R{{preFundedKeys[0]}}
+		signers := [][]keychain.Signer{{preFundedKeys[0]}}
 		tx, err := txs.NewSigned(utx, txs.Codec, signers)
 		if err != nil {
 			return nil, err
@@ -353,6 +354,6 @@ func testProposalTx() (*txs.Tx, error) {
 		TxID: ids.ID{'r', 'e', 'w', 'a', 'r', 'd', 'I', 'D'},
 	}
 
-	signers := [][]*crypto.PrivateKeySECP256K1R{{preFundedKeys[0]}}
+	signers := [][]keychain.Signer{{preFundedKeys[0]}}
 	return txs.NewSigned(utx, txs.Codec, signers)
 }
@@ -203,6 +203,12 @@ type Client interface {
 	// GetValidatorsAt returns the weights of the validator set of a provided subnet
 	// at the specified height.
 	GetValidatorsAt(ctx context.Context, subnetID ids.ID, height uint64, options ...rpc.Option) (map[ids.NodeID]uint64, error)
+	// GetValidatorSetDiffs returns the changes of the validator set of a
+	// provided subnet at every height in [startHeight, endHeight].
+	GetValidatorSetDiffs(ctx context.Context, subnetID ids.ID, startHeight uint64, endHeight uint64, options ...rpc.Option) ([]ValidatorSetDiff, error)
+	// GetValidatorHistory returns every period during which [nodeID]
+	// validated a subnet.
+	GetValidatorHistory(ctx context.Context, nodeID ids.NodeID, options ...rpc.Option) ([]APIStakingPeriod, error)
 	// GetBlock returns the block with the given id.
 	GetBlock(ctx context.Context, blockID ids.ID, options ...rpc.Option) ([]byte, error)
 }
@@ -798,6 +804,24 @@ func (c *client) GetValidatorsAt(ctx context.Context, subnetID ids.ID, height ui
 	return res.Validators, err
 }
 
+func (c *client) GetValidatorSetDiffs(ctx context.Context, subnetID ids.ID, startHeight uint64, endHeight uint64, options ...rpc.Option) ([]ValidatorSetDiff, error) {
+	res := &GetValidatorSetDiffsReply{}
+	err := c.requester.SendRequest(ctx, "getValidatorSetDiffs", &GetValidatorSetDiffsArgs{
+		SubnetID:    subnetID,
+		StartHeight: json.Uint64(startHeight),
+		EndHeight:   json.Uint64(endHeight),
+	}, res, options...)
+	return res.Diffs, err
+}
+
+func (c *client) GetValidatorHistory(ctx context.Context, nodeID ids.NodeID, options ...rpc.Option) ([]APIStakingPeriod, error) {
+	res := &GetValidatorHistoryReply{}
+	err := c.requester.SendRequest(ctx, "getValidatorHistory", &GetValidatorHistoryArgs{
+		NodeID: nodeID,
+	}, res, options...)
+	return res.Periods, err
+}
+
 func (c *client) GetBlock(ctx context.Context, blockID ids.ID, options ...rpc.Option) ([]byte, error) {
 	response := &api.FormattedBlock{}
 	if err := c.requester.SendRequest(ctx, "getBlock", &api.GetBlockArgs{
@@ -87,6 +87,10 @@ type Config struct {
 	// Config for the minting function
 	RewardConfig reward.Config
 
+	// Maximum number of blocks walked back to find when the current
+	// validators started validating, when their history is first recorded
+	ValidatorHistoryBackfillDepth uint64
+
 	// Time of the AP3 network upgrade
 	ApricotPhase3Time time.Time
 
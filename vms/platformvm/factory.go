@@ -14,6 +14,10 @@ var _ vms.Factory = &Factory{}
 // Factory can create new instances of the Platform Chain
 type Factory struct {
 	config.Config
+
+	// If non-nil, the API services of the chains created by this factory are
+	// added to Services while the chains are bootstrapped.
+	Services *Services
 }
 
 // New returns a new instance of the Platform Chain
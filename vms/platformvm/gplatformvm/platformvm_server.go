@@ -0,0 +1,346 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gplatformvm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/dim4egster/qmallgo/api"
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/utils/formatting"
+	"github.com/dim4egster/qmallgo/utils/formatting/address"
+	"github.com/dim4egster/qmallgo/utils/json"
+	"github.com/dim4egster/qmallgo/vms/platformvm"
+	"github.com/dim4egster/qmallgo/vms/rpcchainvm/grpcutils"
+
+	platformvmpb "github.com/dim4egster/qmallgo/proto/pb/platformvm"
+)
+
+// defaultPageSize is the number of UTXOs fetched at a time by StreamUTXOs if
+// the request doesn't specify a page size.
+const defaultPageSize = 1024
+
+var (
+	_ platformvmpb.PlatformVMServer = &Server{}
+	_ Service                       = &platformvm.Service{}
+
+	errUnexpectedEncodedType = errors.New("unexpected encoded type")
+)
+
+// Service is the part of the platform API that is served over gRPC.
+type Service interface {
+	GetHeight(r *http.Request, args *struct{}, reply *platformvm.GetHeightResponse) error
+	GetBalance(r *http.Request, args *platformvm.GetBalanceRequest, reply *platformvm.GetBalanceResponse) error
+	GetTx(r *http.Request, args *api.GetTxArgs, reply *api.GetTxReply) error
+	GetTxStatus(r *http.Request, args *platformvm.GetTxStatusArgs, reply *platformvm.GetTxStatusResponse) error
+	GetBlock(r *http.Request, args *api.GetBlockArgs, reply *api.GetBlockResponse) error
+	GetTimestamp(r *http.Request, args *struct{}, reply *platformvm.GetTimestampReply) error
+	GetCurrentSupply(r *http.Request, args *platformvm.GetCurrentSupplyArgs, reply *platformvm.GetCurrentSupplyReply) error
+	GetUTXOs(r *http.Request, args *api.GetUTXOsArgs, reply *api.GetUTXOsReply) error
+}
+
+// Server serves the read methods of the platform API over gRPC.
+type Server struct {
+	platformvmpb.UnsafePlatformVMServer
+
+	// call calls the function with the API service of the chain, holding the
+	// lock of the chain.
+	call func(func(Service) error) error
+}
+
+// NewServer returns a gRPC server that serves its requests with the API
+// service of the chain [chainID] in [services].
+func NewServer(services *platformvm.Services, chainID ids.ID) *Server {
+	return newServer(func(f func(Service) error) error {
+		err := services.Call(chainID, func(service *platformvm.Service) error {
+			return f(service)
+		})
+		if errors.Is(err, platformvm.ErrNotBootstrapped) {
+			return status.Error(codes.Unavailable, err.Error())
+		}
+		return err
+	})
+}
+
+func newServer(call func(func(Service) error) error) *Server {
+	return &Server{
+		call: call,
+	}
+}
+
+func (s *Server) GetHeight(context.Context, *emptypb.Empty) (*platformvmpb.GetHeightResponse, error) {
+	reply := &platformvm.GetHeightResponse{}
+	err := s.call(func(service Service) error {
+		return service.GetHeight(nil, &struct{}{}, reply)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &platformvmpb.GetHeightResponse{
+		Height: uint64(reply.Height),
+	}, nil
+}
+
+func (s *Server) GetBalance(_ context.Context, req *platformvmpb.GetBalanceRequest) (*platformvmpb.GetBalanceResponse, error) {
+	addrs, err := toShortIDs(req.Addresses)
+	if err != nil {
+		return nil, err
+	}
+	reply := &platformvm.GetBalanceResponse{}
+	err = s.call(func(service Service) error {
+		return service.GetBalance(nil, &platformvm.GetBalanceRequest{
+			Addresses: ids.ShortIDsToStrings(addrs),
+		}, reply)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &platformvmpb.GetBalanceResponse{
+		Balance:            uint64(reply.Balance),
+		Unlocked:           uint64(reply.Unlocked),
+		LockedStakeable:    uint64(reply.LockedStakeable),
+		LockedNotStakeable: uint64(reply.LockedNotStakeable),
+	}, nil
+}
+
+func (s *Server) GetTx(_ context.Context, req *platformvmpb.GetTxRequest) (*platformvmpb.GetTxResponse, error) {
+	txID, err := ids.ToID(req.TxId)
+	if err != nil {
+		return nil, err
+	}
+	reply := &api.GetTxReply{}
+	err = s.call(func(service Service) error {
+		return service.GetTx(nil, &api.GetTxArgs{
+			TxID:     txID,
+			Encoding: formatting.Hex,
+		}, reply)
+	})
+	if err != nil {
+		return nil, err
+	}
+	tx, err := decode(reply.Encoding, reply.Tx)
+	if err != nil {
+		return nil, err
+	}
+	return &platformvmpb.GetTxResponse{
+		Tx: tx,
+	}, nil
+}
+
+func (s *Server) GetTxStatus(_ context.Context, req *platformvmpb.GetTxStatusRequest) (*platformvmpb.GetTxStatusResponse, error) {
+	txID, err := ids.ToID(req.TxId)
+	if err != nil {
+		return nil, err
+	}
+	reply := &platformvm.GetTxStatusResponse{}
+	err = s.call(func(service Service) error {
+		return service.GetTxStatus(nil, &platformvm.GetTxStatusArgs{
+			TxID: txID,
+		}, reply)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &platformvmpb.GetTxStatusResponse{
+		Status: reply.Status.String(),
+		Reason: reply.Reason,
+	}, nil
+}
+
+func (s *Server) GetBlock(_ context.Context, req *platformvmpb.GetBlockRequest) (*platformvmpb.GetBlockResponse, error) {
+	blockID, err := ids.ToID(req.BlockId)
+	if err != nil {
+		return nil, err
+	}
+	reply := &api.GetBlockResponse{}
+	err = s.call(func(service Service) error {
+		return service.GetBlock(nil, &api.GetBlockArgs{
+			BlockID:  blockID,
+			Encoding: formatting.Hex,
+		}, reply)
+	})
+	if err != nil {
+		return nil, err
+	}
+	block, err := decode(reply.Encoding, reply.Block)
+	if err != nil {
+		return nil, err
+	}
+	return &platformvmpb.GetBlockResponse{
+		Block: block,
+	}, nil
+}
+
+func (s *Server) GetTimestamp(context.Context, *emptypb.Empty) (*platformvmpb.GetTimestampResponse, error) {
+	reply := &platformvm.GetTimestampReply{}
+	err := s.call(func(service Service) error {
+		return service.GetTimestamp(nil, &struct{}{}, reply)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &platformvmpb.GetTimestampResponse{
+		Timestamp: grpcutils.TimestampFromTime(reply.Timestamp),
+	}, nil
+}
+
+func (s *Server) GetCurrentSupply(_ context.Context, req *platformvmpb.GetCurrentSupplyRequest) (*platformvmpb.GetCurrentSupplyResponse, error) {
+	subnetID := ids.Empty
+	if len(req.SubnetId) != 0 {
+		var err error
+		subnetID, err = ids.ToID(req.SubnetId)
+		if err != nil {
+			return nil, err
+		}
+	}
+	reply := &platformvm.GetCurrentSupplyReply{}
+	err := s.call(func(service Service) error {
+		return service.GetCurrentSupply(nil, &platformvm.GetCurrentSupplyArgs{
+			SubnetID: subnetID,
+		}, reply)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &platformvmpb.GetCurrentSupplyResponse{
+		Supply: uint64(reply.Supply),
+	}, nil
+}
+
+func (s *Server) GetUTXOs(_ context.Context, req *platformvmpb.GetUTXOsRequest) (*platformvmpb.GetUTXOsResponse, error) {
+	addrs, err := toShortIDs(req.Addresses)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		startAddr ids.ShortID
+		startUTXO ids.ID
+	)
+	if len(req.StartAddress) != 0 {
+		startAddr, err = ids.ToShortID(req.StartAddress)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(req.StartUtxoId) != 0 {
+		startUTXO, err = ids.ToID(req.StartUtxoId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	utxos, endAddr, endUTXO, err := s.getUTXOs(addrs, req.SourceChain, req.Limit, startAddr, startUTXO)
+	if err != nil {
+		return nil, err
+	}
+	return &platformvmpb.GetUTXOsResponse{
+		Utxos:      utxos,
+		EndAddress: endAddr[:],
+		EndUtxoId:  endUTXO[:],
+	}, nil
+}
+
+func (s *Server) StreamUTXOs(req *platformvmpb.StreamUTXOsRequest, stream platformvmpb.PlatformVM_StreamUTXOsServer) error {
+	addrs, err := toShortIDs(req.Addresses)
+	if err != nil {
+		return err
+	}
+	pageSize := req.PageSize
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+
+	var (
+		startAddr ids.ShortID
+		startUTXO ids.ID
+	)
+	for {
+		utxos, endAddr, endUTXO, err := s.getUTXOs(addrs, req.SourceChain, pageSize, startAddr, startUTXO)
+		if err != nil {
+			return err
+		}
+		for _, utxo := range utxos {
+			if err := stream.Send(&platformvmpb.UTXO{Bytes: utxo}); err != nil {
+				return err
+			}
+		}
+		// The service may return fewer UTXOs than requested even if more
+		// remain, so the stream only ends once no more progress is made.
+		if len(utxos) == 0 || (endAddr == startAddr && endUTXO == startUTXO) {
+			return nil
+		}
+
+		// Update the vars to query the next page of UTXOs.
+		startAddr = endAddr
+		startUTXO = endUTXO
+	}
+}
+
+// getUTXOs fetches a page of UTXOs from the API service of the chain.
+func (s *Server) getUTXOs(
+	addrs []ids.ShortID,
+	sourceChain string,
+	limit uint32,
+	startAddr ids.ShortID,
+	startUTXO ids.ID,
+) ([][]byte, ids.ShortID, ids.ID, error) {
+	reply := &api.GetUTXOsReply{}
+	err := s.call(func(service Service) error {
+		return service.GetUTXOs(nil, &api.GetUTXOsArgs{
+			Addresses:   ids.ShortIDsToStrings(addrs),
+			SourceChain: sourceChain,
+			Limit:       json.Uint32(limit),
+			StartIndex: api.Index{
+				Address: startAddr.String(),
+				UTXO:    startUTXO.String(),
+			},
+			Encoding: formatting.Hex,
+		}, reply)
+	})
+	if err != nil {
+		return nil, ids.ShortEmpty, ids.Empty, err
+	}
+
+	utxos := make([][]byte, len(reply.UTXOs))
+	for i, utxo := range reply.UTXOs {
+		utxos[i], err = formatting.Decode(reply.Encoding, utxo)
+		if err != nil {
+			return nil, ids.ShortEmpty, ids.Empty, err
+		}
+	}
+	endAddr, err := address.ParseToID(reply.EndIndex.Address)
+	if err != nil {
+		return nil, ids.ShortEmpty, ids.Empty, err
+	}
+	endUTXO, err := ids.FromString(reply.EndIndex.UTXO)
+	return utxos, endAddr, endUTXO, err
+}
+
+// decode decodes a tx or a block the service encoded as a string.
+func decode(encoding formatting.Encoding, encoded interface{}) ([]byte, error) {
+	encodedStr, ok := encoded.(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", errUnexpectedEncodedType, encoded)
+	}
+	return formatting.Decode(encoding, encodedStr)
+}
+
+func toShortIDs(addrsBytes [][]byte) ([]ids.ShortID, error) {
+	addrs := make([]ids.ShortID, len(addrsBytes))
+	for i, addrBytes := range addrsBytes {
+		addr, err := ids.ToShortID(addrBytes)
+		if err != nil {
+			return nil, err
+		}
+		addrs[i] = addr
+	}
+	return addrs, nil
+}
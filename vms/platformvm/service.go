@@ -4,9 +4,11 @@
 package platformvm
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 	"time"
 
 	stdmath "math"
@@ -28,6 +30,7 @@ import (
 	"github.com/dim4egster/qmallgo/vms/platformvm/blocks"
 	"github.com/dim4egster/qmallgo/vms/platformvm/reward"
 	"github.com/dim4egster/qmallgo/vms/platformvm/stakeable"
+	"github.com/dim4egster/qmallgo/vms/platformvm/state"
 	"github.com/dim4egster/qmallgo/vms/platformvm/status"
 	"github.com/dim4egster/qmallgo/vms/platformvm/txs"
 	"github.com/dim4egster/qmallgo/vms/platformvm/txs/builder"
@@ -47,6 +50,9 @@ const (
 	// Minimum amount of delay to allow a transaction to be issued through the
 	// API
 	minAddStakerDelay = 2 * executor.SyncBound
+
+	// Max number of heights whose validator set diffs can be fetched at once
+	maxValidatorSetDiffsHeights = 1024
 )
 
 var (
@@ -68,6 +74,8 @@ var (
 	errMissingPrivateKey        = errors.New("argument 'privateKey' not given")
 	errStartAfterEndTime        = errors.New("start time must be before end time")
 	errStartTimeInThePast       = errors.New("start time in the past")
+	errStartHeightAfterEnd      = errors.New("start height must not be after end height")
+	errTooManyHeights           = fmt.Errorf("at most %d heights can be requested", maxValidatorSetDiffsHeights)
 )
 
 // Service defines the API calls that can be made to the platform chain
@@ -1075,16 +1083,16 @@ func (service *Service) AddValidator(_ *http.Request, args *AddValidatorArgs, re
 	defer user.Close()
 
 	// Get the user's keys
-	privKeys, err := keystore.GetKeychain(user, fromAddrs)
+	privKeys, err := keystore.NewKeychain(service.vm.ctx.Keychain, user, fromAddrs)
 	if err != nil {
 		return fmt.Errorf("couldn't get addresses controlled by the user: %w", err)
 	}
 
 	// Parse the change address.
-	if len(privKeys.Keys) == 0 {
+	if len(privKeys.Addrs) == 0 {
 		return errNoKeys
 	}
-	changeAddr := privKeys.Keys[0].PublicKey().Address() // By default, use a key controlled by the user
+	changeAddr := privKeys.Addrs[0] // By default, use a key controlled by the user
 	if args.ChangeAddr != "" {
 		changeAddr, err = avax.ParseServiceAddress(service.addrManager, args.ChangeAddr)
 		if err != nil {
@@ -1100,7 +1108,7 @@ func (service *Service) AddValidator(_ *http.Request, args *AddValidatorArgs, re
 		nodeID,                               // Node ID
 		rewardAddress,                        // Reward Address
 		uint32(10000*args.DelegationFeeRate), // Shares
-		privKeys.Keys,                        // Keys providing the staked tokens
+		privKeys,                             // Keychain providing the staked tokens
 		changeAddr,
 	)
 	if err != nil {
@@ -1176,17 +1184,17 @@ func (service *Service) AddDelegator(_ *http.Request, args *AddDelegatorArgs, re
 	}
 	defer user.Close()
 
-	privKeys, err := keystore.GetKeychain(user, fromAddrs)
+	privKeys, err := keystore.NewKeychain(service.vm.ctx.Keychain, user, fromAddrs)
 	if err != nil {
 		return fmt.Errorf("couldn't get addresses controlled by the user: %w", err)
 	}
 
 	// Parse the change address. Assumes that if the user has no keys,
 	// this operation will fail so the change address can be anything.
-	if len(privKeys.Keys) == 0 {
+	if len(privKeys.Addrs) == 0 {
 		return errNoKeys
 	}
-	changeAddr := privKeys.Keys[0].PublicKey().Address() // By default, use a key controlled by the user
+	changeAddr := privKeys.Addrs[0] // By default, use a key controlled by the user
 	if args.ChangeAddr != "" {
 		changeAddr, err = avax.ParseServiceAddress(service.addrManager, args.ChangeAddr)
 		if err != nil {
@@ -1201,7 +1209,7 @@ func (service *Service) AddDelegator(_ *http.Request, args *AddDelegatorArgs, re
 		uint64(args.EndTime),   // End time
 		nodeID,                 // Node ID
 		rewardAddress,          // Reward Address
-		privKeys.Keys,          // Private keys
+		privKeys,               // Keychain
 		changeAddr,             // Change address
 	)
 	if err != nil {
@@ -1274,16 +1282,16 @@ func (service *Service) AddSubnetValidator(_ *http.Request, args *AddSubnetValid
 	}
 	defer user.Close()
 
-	keys, err := keystore.GetKeychain(user, fromAddrs)
+	keys, err := keystore.NewKeychain(service.vm.ctx.Keychain, user, fromAddrs)
 	if err != nil {
 		return fmt.Errorf("couldn't get addresses controlled by the user: %w", err)
 	}
 
 	// Parse the change address.
-	if len(keys.Keys) == 0 {
+	if len(keys.Addrs) == 0 {
 		return errNoKeys
 	}
-	changeAddr := keys.Keys[0].PublicKey().Address() // By default, use a key controlled by the user
+	changeAddr := keys.Addrs[0] // By default, use a key controlled by the user
 	if args.ChangeAddr != "" {
 		changeAddr, err = avax.ParseServiceAddress(service.addrManager, args.ChangeAddr)
 		if err != nil {
@@ -1298,7 +1306,7 @@ func (service *Service) AddSubnetValidator(_ *http.Request, args *AddSubnetValid
 		uint64(args.EndTime),   // End time
 		args.NodeID,            // Node ID
 		subnetID,               // Subnet ID
-		keys.Keys,
+		keys,
 		changeAddr,
 	)
 	if err != nil {
@@ -1348,17 +1356,17 @@ func (service *Service) CreateSubnet(_ *http.Request, args *CreateSubnetArgs, re
 	}
 	defer user.Close()
 
-	privKeys, err := keystore.GetKeychain(user, fromAddrs)
+	privKeys, err := keystore.NewKeychain(service.vm.ctx.Keychain, user, fromAddrs)
 	if err != nil {
 		return fmt.Errorf("couldn't get addresses controlled by the user: %w", err)
 	}
 
 	// Parse the change address. Assumes that if the user has no keys,
 	// this operation will fail so the change address can be anything.
-	if len(privKeys.Keys) == 0 {
+	if len(privKeys.Addrs) == 0 {
 		return errNoKeys
 	}
-	changeAddr := privKeys.Keys[0].PublicKey().Address() // By default, use a key controlled by the user
+	changeAddr := privKeys.Addrs[0] // By default, use a key controlled by the user
 	if args.ChangeAddr != "" {
 		changeAddr, err = avax.ParseServiceAddress(service.addrManager, args.ChangeAddr)
 		if err != nil {
@@ -1370,7 +1378,7 @@ func (service *Service) CreateSubnet(_ *http.Request, args *CreateSubnetArgs, re
 	tx, err := service.vm.txBuilder.NewCreateSubnetTx(
 		uint32(args.Threshold), // Threshold
 		controlKeys.List(),     // Control Addresses
-		privKeys.Keys,          // Private keys
+		privKeys,               // Keychain
 		changeAddr,
 	)
 	if err != nil {
@@ -1439,17 +1447,17 @@ func (service *Service) ExportAVAX(_ *http.Request, args *ExportAVAXArgs, respon
 	}
 	defer user.Close()
 
-	privKeys, err := keystore.GetKeychain(user, fromAddrs)
+	privKeys, err := keystore.NewKeychain(service.vm.ctx.Keychain, user, fromAddrs)
 	if err != nil {
 		return fmt.Errorf("couldn't get addresses controlled by the user: %w", err)
 	}
 
 	// Parse the change address. Assumes that if the user has no keys,
 	// this operation will fail so the change address can be anything.
-	if len(privKeys.Keys) == 0 {
+	if len(privKeys.Addrs) == 0 {
 		return errNoKeys
 	}
-	changeAddr := privKeys.Keys[0].PublicKey().Address() // By default, use a key controlled by the user
+	changeAddr := privKeys.Addrs[0] // By default, use a key controlled by the user
 	if args.ChangeAddr != "" {
 		changeAddr, err = avax.ParseServiceAddress(service.addrManager, args.ChangeAddr)
 		if err != nil {
@@ -1462,7 +1470,7 @@ func (service *Service) ExportAVAX(_ *http.Request, args *ExportAVAXArgs, respon
 		uint64(args.Amount), // Amount
 		chainID,             // ID of the chain to send the funds to
 		to,                  // Address
-		privKeys.Keys,       // Private keys
+		privKeys,            // Keychain
 		changeAddr,          // Change address
 	)
 	if err != nil {
@@ -1522,17 +1530,17 @@ func (service *Service) ImportAVAX(_ *http.Request, args *ImportAVAXArgs, respon
 	}
 	defer user.Close()
 
-	privKeys, err := keystore.GetKeychain(user, fromAddrs)
+	privKeys, err := keystore.NewKeychain(service.vm.ctx.Keychain, user, fromAddrs)
 	if err != nil { // Get keys
 		return fmt.Errorf("couldn't get keys controlled by the user: %w", err)
 	}
 
 	// Parse the change address. Assumes that if the user has no keys,
 	// this operation will fail so the change address can be anything.
-	if len(privKeys.Keys) == 0 {
+	if len(privKeys.Addrs) == 0 {
 		return errNoKeys
 	}
-	changeAddr := privKeys.Keys[0].PublicKey().Address() // By default, use a key controlled by the user
+	changeAddr := privKeys.Addrs[0] // By default, use a key controlled by the user
 	if args.ChangeAddr != "" {
 		changeAddr, err = avax.ParseServiceAddress(service.addrManager, args.ChangeAddr)
 		if err != nil {
@@ -1543,7 +1551,7 @@ func (service *Service) ImportAVAX(_ *http.Request, args *ImportAVAXArgs, respon
 	tx, err := service.vm.txBuilder.NewImportTx(
 		chainID,
 		to,
-		privKeys.Keys,
+		privKeys,
 		changeAddr,
 	)
 	if err != nil {
@@ -1639,17 +1647,17 @@ func (service *Service) CreateBlockchain(_ *http.Request, args *CreateBlockchain
 	}
 	defer user.Close()
 
-	keys, err := keystore.GetKeychain(user, fromAddrs)
+	keys, err := keystore.NewKeychain(service.vm.ctx.Keychain, user, fromAddrs)
 	if err != nil {
 		return fmt.Errorf("couldn't get addresses controlled by the user: %w", err)
 	}
 
 	// Parse the change address. Assumes that if the user has no keys,
 	// this operation will fail so the change address can be anything.
-	if len(keys.Keys) == 0 {
+	if len(keys.Addrs) == 0 {
 		return errNoKeys
 	}
-	changeAddr := keys.Keys[0].PublicKey().Address() // By default, use a key controlled by the user
+	changeAddr := keys.Addrs[0] // By default, use a key controlled by the user
 	if args.ChangeAddr != "" {
 		changeAddr, err = avax.ParseServiceAddress(service.addrManager, args.ChangeAddr)
 		if err != nil {
@@ -1664,7 +1672,7 @@ func (service *Service) CreateBlockchain(_ *http.Request, args *CreateBlockchain
 		vmID,
 		fxIDs,
 		args.Name,
-		keys.Keys,
+		keys,
 		changeAddr, // Change address
 	)
 	if err != nil {
@@ -2400,11 +2408,258 @@ func (service *Service) GetValidatorsAt(_ *http.Request, args *GetValidatorsAtAr
 		zap.Stringer("subnetID", args.SubnetID),
 	)
 
-	var err error
-	reply.Validators, err = service.vm.GetValidatorSet(height, args.SubnetID)
+	vdrs, err := service.vm.GetValidatorSet(height, args.SubnetID)
+	if err != nil {
+		return fmt.Errorf("couldn't get validator set: %w", err)
+	}
+	reply.Validators = make(map[ids.NodeID]uint64, len(vdrs))
+	for nodeID, vdr := range vdrs {
+		reply.Validators[nodeID] = vdr.Weight
+	}
+	return nil
+}
+
+// GetValidatorSetDiffsArgs are the arguments for calling GetValidatorSetDiffs
+type GetValidatorSetDiffsArgs struct {
+	SubnetID    ids.ID      `json:"subnetID"`
+	StartHeight json.Uint64 `json:"startHeight"`
+	EndHeight   json.Uint64 `json:"endHeight"`
+}
+
+// ValidatorChange is the change of the weight of a validator at a height.
+type ValidatorChange struct {
+	NodeID         ids.NodeID  `json:"nodeID"`
+	PreviousWeight json.Uint64 `json:"previousWeight"`
+	Weight         json.Uint64 `json:"weight"`
+	// PublicKey is the BLS public key of the validator, if it registered one
+	PublicKey string `json:"publicKey,omitempty"`
+}
+
+// ValidatorSetDiff is the change of a validator set at a height.
+type ValidatorSetDiff struct {
+	Height        json.Uint64       `json:"height"`
+	Added         []ValidatorChange `json:"added"`
+	Removed       []ValidatorChange `json:"removed"`
+	WeightChanged []ValidatorChange `json:"weightChanged"`
+}
+
+// GetValidatorSetDiffsReply is the response from GetValidatorSetDiffs
+type GetValidatorSetDiffsReply struct {
+	// Diffs are ordered by increasing height. Heights at which the validator
+	// set didn't change are omitted.
+	Diffs []ValidatorSetDiff `json:"diffs"`
+}
+
+// GetValidatorSetDiffs returns the changes of the validator set of a provided
+// subnet at every height in [args.StartHeight, args.EndHeight]. The diff at a
+// height is the change from the validator set at the previous height.
+func (service *Service) GetValidatorSetDiffs(_ *http.Request, args *GetValidatorSetDiffsArgs, reply *GetValidatorSetDiffsReply) error {
+	startHeight := uint64(args.StartHeight)
+	endHeight := uint64(args.EndHeight)
+	service.vm.ctx.Log.Debug("Platform: GetValidatorSetDiffs called",
+		zap.Stringer("subnetID", args.SubnetID),
+		zap.Uint64("startHeight", startHeight),
+		zap.Uint64("endHeight", endHeight),
+	)
+
+	if startHeight > endHeight {
+		return errStartHeightAfterEnd
+	}
+	if endHeight-startHeight >= maxValidatorSetDiffsHeights {
+		return errTooManyHeights
+	}
+
+	endSet, err := service.vm.GetValidatorSet(endHeight, args.SubnetID)
 	if err != nil {
 		return fmt.Errorf("couldn't get validator set: %w", err)
 	}
+	// Copy the set because it may be cached by the VM
+	vdrSet := make(map[ids.NodeID]uint64, len(endSet))
+	for nodeID, vdr := range endSet {
+		vdrSet[nodeID] = vdr.Weight
+	}
+
+	publicKeys := newPublicKeyGetter(service.vm.state)
+	diffs := []ValidatorSetDiff{}
+	for height := endHeight; ; height-- {
+		weightDiffs, err := service.vm.state.GetValidatorWeightDiffs(height, args.SubnetID)
+		if err != nil {
+			return fmt.Errorf("couldn't get validator weight diffs at height %d: %w", height, err)
+		}
+
+		if len(weightDiffs) > 0 {
+			diff := ValidatorSetDiff{
+				Height:        json.Uint64(height),
+				Added:         []ValidatorChange{},
+				Removed:       []ValidatorChange{},
+				WeightChanged: []ValidatorChange{},
+			}
+			for nodeID, weightDiff := range weightDiffs {
+				weight := vdrSet[nodeID]
+				var previousWeight uint64
+				if weightDiff.Decrease {
+					previousWeight, err = math.Add64(weight, weightDiff.Amount)
+				} else {
+					previousWeight, err = math.Sub64(weight, weightDiff.Amount)
+				}
+				if err != nil {
+					return err
+				}
+				if previousWeight == 0 {
+					delete(vdrSet, nodeID)
+				} else {
+					vdrSet[nodeID] = previousWeight
+				}
+
+				publicKey, err := publicKeys.get(nodeID, height)
+				if err != nil {
+					return err
+				}
+				change := ValidatorChange{
+					NodeID:         nodeID,
+					PreviousWeight: json.Uint64(previousWeight),
+					Weight:         json.Uint64(weight),
+					PublicKey:      publicKey,
+				}
+				switch {
+				case previousWeight == 0:
+					diff.Added = append(diff.Added, change)
+				case weight == 0:
+					diff.Removed = append(diff.Removed, change)
+				default:
+					diff.WeightChanged = append(diff.WeightChanged, change)
+				}
+			}
+			sortValidatorChanges(diff.Added)
+			sortValidatorChanges(diff.Removed)
+			sortValidatorChanges(diff.WeightChanged)
+			diffs = append(diffs, diff)
+		}
+
+		if height == startHeight {
+			break
+		}
+	}
+
+	// The diffs were collected from the end height down
+	reply.Diffs = make([]ValidatorSetDiff, len(diffs))
+	for i, diff := range diffs {
+		reply.Diffs[len(diffs)-1-i] = diff
+	}
+	return nil
+}
+
+func sortValidatorChanges(changes []ValidatorChange) {
+	sort.Slice(changes, func(i, j int) bool {
+		return bytes.Compare(changes[i].NodeID[:], changes[j].NodeID[:]) < 0
+	})
+}
+
+// publicKeyGetter looks up the BLS public keys of validators from their
+// staking history.
+type publicKeyGetter struct {
+	state     state.State
+	histories map[ids.NodeID][]*state.StakingPeriod
+}
+
+func newPublicKeyGetter(s state.State) *publicKeyGetter {
+	return &publicKeyGetter{
+		state:     s,
+		histories: make(map[ids.NodeID][]*state.StakingPeriod),
+	}
+}
+
+// get returns the BLS public key that [nodeID] registered on the primary
+// network for the staking period that covers [height], or the empty string if
+// it didn't register one.
+func (g *publicKeyGetter) get(nodeID ids.NodeID, height uint64) (string, error) {
+	history, ok := g.histories[nodeID]
+	if !ok {
+		var err error
+		history, err = g.state.GetValidatorHistory(nodeID)
+		if err != nil {
+			return "", fmt.Errorf("couldn't get validator history of %s: %w", nodeID, err)
+		}
+		g.histories[nodeID] = history
+	}
+
+	for _, period := range history {
+		if period.SubnetID != constants.PrimaryNetworkID || len(period.PublicKey) == 0 {
+			continue
+		}
+		if period.StartHeight <= height && (period.EndHeight == 0 || height <= period.EndHeight) {
+			return formatting.Encode(formatting.HexNC, period.PublicKey)
+		}
+	}
+	return "", nil
+}
+
+// GetValidatorHistoryArgs are the arguments for calling GetValidatorHistory
+type GetValidatorHistoryArgs struct {
+	NodeID ids.NodeID `json:"nodeID"`
+}
+
+// APIStakingPeriod is a period during which a node validated a subnet.
+type APIStakingPeriod struct {
+	TxID      ids.ID      `json:"txID"`
+	SubnetID  ids.ID      `json:"subnetID"`
+	Weight    json.Uint64 `json:"weight"`
+	StartTime json.Uint64 `json:"startTime"`
+	EndTime   json.Uint64 `json:"endTime"`
+	// StartHeight is the height of the block that added the validator
+	StartHeight json.Uint64 `json:"startHeight"`
+	// EndHeight is the height of the block that removed the validator. It is
+	// omitted while the node is validating.
+	EndHeight *json.Uint64 `json:"endHeight,omitempty"`
+	// PublicKey is the BLS public key registered by the validator, if any
+	PublicKey string `json:"publicKey,omitempty"`
+	// Partial is true if the validator started validating before the oldest
+	// block its history was backfilled from. StartHeight is then the height
+	// of that block.
+	Partial bool `json:"partial,omitempty"`
+}
+
+// GetValidatorHistoryReply is the response from GetValidatorHistory
+type GetValidatorHistoryReply struct {
+	// Periods are ordered by increasing start height
+	Periods []APIStakingPeriod `json:"periods"`
+}
+
+// GetValidatorHistory returns every period during which a node validated a
+// subnet.
+func (service *Service) GetValidatorHistory(_ *http.Request, args *GetValidatorHistoryArgs, reply *GetValidatorHistoryReply) error {
+	service.vm.ctx.Log.Debug("Platform: GetValidatorHistory called",
+		zap.Stringer("nodeID", args.NodeID),
+	)
+
+	history, err := service.vm.state.GetValidatorHistory(args.NodeID)
+	if err != nil {
+		return fmt.Errorf("couldn't get validator history: %w", err)
+	}
+
+	reply.Periods = make([]APIStakingPeriod, len(history))
+	for i, period := range history {
+		apiPeriod := APIStakingPeriod{
+			TxID:        period.TxID,
+			SubnetID:    period.SubnetID,
+			Weight:      json.Uint64(period.Weight),
+			StartTime:   json.Uint64(period.StartTime),
+			EndTime:     json.Uint64(period.EndTime),
+			StartHeight: json.Uint64(period.StartHeight),
+			Partial:     period.Partial,
+		}
+		if period.EndHeight != 0 {
+			endHeight := json.Uint64(period.EndHeight)
+			apiPeriod.EndHeight = &endHeight
+		}
+		if len(period.PublicKey) != 0 {
+			apiPeriod.PublicKey, err = formatting.Encode(formatting.HexNC, period.PublicKey)
+			if err != nil {
+				return fmt.Errorf("couldn't encode public key: %w", err)
+			}
+		}
+		reply.Periods[i] = apiPeriod
+	}
 	return nil
 }
 
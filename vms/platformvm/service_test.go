@@ -235,7 +235,7 @@ func TestGetTxStatus(t *testing.T) {
 	oldSharedMemory := mutableSharedMemory.SharedMemory
 	mutableSharedMemory.SharedMemory = sm
 
-	tx, err := service.vm.txBuilder.NewImportTx(xChainID, ids.ShortEmpty, []*crypto.PrivateKeySECP256K1R{recipientKey}, ids.ShortEmpty)
+	tx, err := service.vm.txBuilder.NewImportTx(xChainID, ids.ShortEmpty, secp256k1fx.NewKeychain(recipientKey), ids.ShortEmpty)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -303,7 +303,7 @@ func TestGetTx(t *testing.T) {
 					constants.AVMID,
 					nil,
 					"chain name",
-					[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+					secp256k1fx.NewKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 					keys[0].PublicKey().Address(), // change addr
 				)
 			},
@@ -318,7 +318,7 @@ func TestGetTx(t *testing.T) {
 					ids.GenerateTestNodeID(),
 					ids.GenerateTestShortID(),
 					0,
-					[]*crypto.PrivateKeySECP256K1R{keys[0]},
+					secp256k1fx.NewKeychain(keys[0]),
 					keys[0].PublicKey().Address(), // change addr
 				)
 			},
@@ -330,7 +330,7 @@ func TestGetTx(t *testing.T) {
 					100,
 					service.vm.ctx.XChainID,
 					ids.GenerateTestShortID(),
-					[]*crypto.PrivateKeySECP256K1R{keys[0]},
+					secp256k1fx.NewKeychain(keys[0]),
 					keys[0].PublicKey().Address(), // change addr
 				)
 			},
@@ -537,7 +537,7 @@ func TestGetStake(t *testing.T) {
 		delegatorEndTime,
 		delegatorNodeID,
 		ids.GenerateTestShortID(),
-		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		secp256k1fx.NewKeychain(keys[0]),
 		keys[0].PublicKey().Address(), // change addr
 	)
 	require.NoError(err)
@@ -585,7 +585,7 @@ func TestGetStake(t *testing.T) {
 		pendingStakerNodeID,
 		ids.GenerateTestShortID(),
 		0,
-		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		secp256k1fx.NewKeychain(keys[0]),
 		keys[0].PublicKey().Address(), // change addr
 	)
 	require.NoError(err)
@@ -690,7 +690,7 @@ func TestGetCurrentValidators(t *testing.T) {
 		delegatorEndTime,
 		validatorNodeID,
 		ids.GenerateTestShortID(),
-		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		secp256k1fx.NewKeychain(keys[0]),
 		keys[0].PublicKey().Address(), // change addr
 	)
 	if err != nil {
@@ -748,6 +748,62 @@ func TestGetCurrentValidators(t *testing.T) {
 	}
 }
 
+func TestGetValidatorSetDiffsAndHistory(t *testing.T) {
+	require := require.New(t)
+	service, _ := defaultService(t)
+	service.vm.ctx.Lock.Lock()
+	defer func() {
+		require.NoError(service.vm.Shutdown())
+		service.vm.ctx.Lock.Unlock()
+	}()
+
+	genesis, _ := defaultGenesis()
+
+	// The genesis validators are added at height 0
+	diffsReply := GetValidatorSetDiffsReply{}
+	require.NoError(service.GetValidatorSetDiffs(nil, &GetValidatorSetDiffsArgs{
+		SubnetID: constants.PrimaryNetworkID,
+	}, &diffsReply))
+	require.Len(diffsReply.Diffs, 1)
+
+	diff := diffsReply.Diffs[0]
+	require.EqualValues(0, diff.Height)
+	require.Empty(diff.Removed)
+	require.Empty(diff.WeightChanged)
+	require.Len(diff.Added, len(genesis.Validators))
+	for _, change := range diff.Added {
+		require.EqualValues(0, change.PreviousWeight)
+		require.EqualValues(defaultWeight, change.Weight)
+	}
+
+	historyReply := GetValidatorHistoryReply{}
+	nodeID := genesis.Validators[0].NodeID
+	require.NoError(service.GetValidatorHistory(nil, &GetValidatorHistoryArgs{
+		NodeID: nodeID,
+	}, &historyReply))
+	require.Len(historyReply.Periods, 1)
+
+	period := historyReply.Periods[0]
+	require.Equal(constants.PrimaryNetworkID, period.SubnetID)
+	require.EqualValues(defaultWeight, period.Weight)
+	require.Equal(genesis.Validators[0].StartTime, period.StartTime)
+	require.Equal(genesis.Validators[0].EndTime, period.EndTime)
+	require.EqualValues(0, period.StartHeight)
+	require.Nil(period.EndHeight)
+
+	err := service.GetValidatorSetDiffs(nil, &GetValidatorSetDiffsArgs{
+		SubnetID:    constants.PrimaryNetworkID,
+		StartHeight: 1,
+	}, &GetValidatorSetDiffsReply{})
+	require.ErrorIs(err, errStartHeightAfterEnd)
+
+	err = service.GetValidatorSetDiffs(nil, &GetValidatorSetDiffsArgs{
+		SubnetID:  constants.PrimaryNetworkID,
+		EndHeight: maxValidatorSetDiffsHeights,
+	}, &GetValidatorSetDiffsReply{})
+	require.ErrorIs(err, errTooManyHeights)
+}
+
 func TestGetTimestamp(t *testing.T) {
 	require := require.New(t)
 	service, _ := defaultService(t)
@@ -798,7 +854,7 @@ func TestGetBlock(t *testing.T) {
 				constants.AVMID,
 				nil,
 				"chain name",
-				[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+				secp256k1fx.NewKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 				keys[0].PublicKey().Address(), // change addr
 			)
 			if err != nil {
@@ -0,0 +1,70 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/dim4egster/qmallgo/ids"
+)
+
+// ErrNotBootstrapped is returned when calling the API of a chain that isn't
+// done bootstrapping.
+var ErrNotBootstrapped = errors.New("chain is not done bootstrapping")
+
+// Services holds the API services of the bootstrapped chains created by a
+// Factory, so that the API can be called in-process without going through
+// JSON-RPC.
+type Services struct {
+	lock     sync.RWMutex
+	services map[ids.ID]*Service
+}
+
+func NewServices() *Services {
+	return &Services{
+		services: make(map[ids.ID]*Service),
+	}
+}
+
+// Call calls [f] with the API service of the chain [chainID] while holding the
+// lock of the chain, as the JSON-RPC API does. Returns ErrNotBootstrapped if
+// the chain isn't done bootstrapping.
+func (s *Services) Call(chainID ids.ID, f func(*Service) error) error {
+	service, ok := s.get(chainID)
+	if !ok {
+		return ErrNotBootstrapped
+	}
+
+	service.vm.ctx.Lock.Lock()
+	defer service.vm.ctx.Lock.Unlock()
+
+	// The chain may have been shut down while waiting for its lock.
+	if current, ok := s.get(chainID); !ok || current != service {
+		return ErrNotBootstrapped
+	}
+	return f(service)
+}
+
+func (s *Services) get(chainID ids.ID) (*Service, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	service, ok := s.services[chainID]
+	return service, ok
+}
+
+func (s *Services) add(chainID ids.ID, service *Service) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.services[chainID] = service
+}
+
+func (s *Services) remove(chainID ids.ID) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.services, chainID)
+}
@@ -512,6 +512,21 @@ func (mr *MockStateMockRecorder) GetUptime(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUptime", reflect.TypeOf((*MockState)(nil).GetUptime), arg0)
 }
 
+// GetValidatorHistory mocks base method.
+func (m *MockState) GetValidatorHistory(arg0 ids.NodeID) ([]*StakingPeriod, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetValidatorHistory", arg0)
+	ret0, _ := ret[0].([]*StakingPeriod)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetValidatorHistory indicates an expected call of GetValidatorHistory.
+func (mr *MockStateMockRecorder) GetValidatorHistory(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetValidatorHistory", reflect.TypeOf((*MockState)(nil).GetValidatorHistory), arg0)
+}
+
 // GetValidatorWeightDiffs mocks base method.
 func (m *MockState) GetValidatorWeightDiffs(arg0 uint64, arg1 ids.ID) (map[ids.NodeID]*ValidatorWeightDiff, error) {
 	m.ctrl.T.Helper()
@@ -60,6 +60,7 @@ var (
 	subnetValidatorPrefix   = []byte("subnetValidator")
 	subnetDelegatorPrefix   = []byte("subnetDelegator")
 	validatorDiffsPrefix    = []byte("validatorDiffs")
+	validatorHistoryPrefix  = []byte("validatorHistory")
 	txPrefix                = []byte("tx")
 	rewardUTXOsPrefix       = []byte("rewardUTXOs")
 	utxoPrefix              = []byte("utxo")
@@ -73,6 +74,9 @@ var (
 	currentSupplyKey = []byte("current supply")
 	lastAcceptedKey  = []byte("last accepted")
 	initializedKey   = []byte("initialized")
+	// Set once the history of the validators that were added before the
+	// history was recorded has been backfilled.
+	validatorHistoryBackfilledKey = []byte("validator history backfilled")
 )
 
 // Chain collects all methods to manage the state of the chain for block
@@ -124,6 +128,10 @@ type State interface {
 
 	GetValidatorWeightDiffs(height uint64, subnetID ids.ID) (map[ids.NodeID]*ValidatorWeightDiff, error)
 
+	// GetValidatorHistory returns every period during which [nodeID]
+	// validated a subnet, ordered by the height at which the period started.
+	GetValidatorHistory(nodeID ids.NodeID) ([]*StakingPeriod, error)
+
 	// Return the current validator set of [subnetID].
 	ValidatorSet(subnetID ids.ID) (validators.Set, error)
 
@@ -177,10 +185,13 @@ type stateBlk struct {
  * | | '-. subnetDelegator
  * | |   '-. list
  * | |     '-- txID -> nil
- * | '-. diffs
- * |   '-. height+subnet
- * |     '-. list
- * |       '-- nodeID -> weightChange
+ * | |-. diffs
+ * | | '-. height+subnet
+ * | |   '-. list
+ * | |     '-- nodeID -> weightChange
+ * | '-. history
+ * |   '-. nodeID
+ * |     '-- txID -> staking period
  * |-. blocks
  * | '-- blockID -> block bytes
  * |-. txs
@@ -246,6 +257,7 @@ type state struct {
 
 	validatorDiffsCache cache.Cacher // cache of heightWithSubnet -> map[ids.ShortID]*ValidatorWeightDiff
 	validatorDiffsDB    database.Database
+	validatorHistoryDB  database.Database
 
 	addedTxs map[ids.ID]*txAndStatus // map of txID -> {*txs.Tx, Status}
 	txCache  cache.Cacher            // cache of txID -> {*txs.Tx, Status} if the entry is nil, it is not in the database
@@ -395,6 +407,7 @@ func new(
 	pendingSubnetDelegatorBaseDB := prefixdb.New(subnetDelegatorPrefix, pendingValidatorsDB)
 
 	validatorDiffsDB := prefixdb.New(validatorDiffsPrefix, validatorsDB)
+	validatorHistoryDB := prefixdb.New(validatorHistoryPrefix, validatorsDB)
 
 	validatorDiffsCache, err := metercacher.New(
 		"validator_diffs_cache",
@@ -506,6 +519,7 @@ func new(
 		pendingSubnetDelegatorList:   linkeddb.NewDefault(pendingSubnetDelegatorBaseDB),
 		validatorDiffsDB:             validatorDiffsDB,
 		validatorDiffsCache:          validatorDiffsCache,
+		validatorHistoryDB:           validatorHistoryDB,
 
 		addedTxs: make(map[ids.ID]*txAndStatus),
 		txDB:     prefixdb.New(txPrefix, baseDB),
@@ -942,7 +956,11 @@ func (s *state) ValidatorSet(subnetID ids.ID) (validators.Set, error) {
 	for nodeID, validator := range s.currentStakers.validators[subnetID] {
 		staker := validator.validator
 		if staker != nil {
-			if err := vdrs.AddWeight(nodeID, staker.Weight); err != nil {
+			publicKey, err := s.getCurrentPublicKey(nodeID)
+			if err != nil {
+				return nil, err
+			}
+			if err := vdrs.Add(nodeID, publicKey, staker.Weight); err != nil {
 				return nil, err
 			}
 		}
@@ -1332,6 +1350,13 @@ func (s *state) sync(genesis []byte) error {
 			err,
 		)
 	}
+
+	if err := s.backfillValidatorHistory(); err != nil {
+		return fmt.Errorf(
+			"failed to backfill the validator history: %w",
+			err,
+		)
+	}
 	return nil
 }
 
@@ -1357,6 +1382,12 @@ func (s *state) init(genesisBytes []byte) error {
 		return err
 	}
 
+	// The history of a new database is recorded from genesis, so it never
+	// needs to be backfilled.
+	if err := s.singletonDB.Put(validatorHistoryBackfilledKey, nil); err != nil {
+		return err
+	}
+
 	return s.Commit()
 }
 
@@ -1477,6 +1508,10 @@ func (s *state) writeCurrentPrimaryNetworkStakers(height uint64) error {
 			weightDiff.Decrease = validatorDiff.validatorDeleted
 			weightDiff.Amount = staker.Weight
 
+			if err := s.writeStakingPeriod(staker, validatorDiff.validatorDeleted, height); err != nil {
+				return err
+			}
+
 			if validatorDiff.validatorDeleted {
 				if err := s.currentValidatorList.Delete(staker.TxID[:]); err != nil {
 					return fmt.Errorf("failed to delete current staker: %w", err)
@@ -1537,7 +1572,7 @@ func (s *state) writeCurrentPrimaryNetworkStakers(height uint64) error {
 		if weightDiff.Decrease {
 			err = s.cfg.Validators.RemoveWeight(constants.PrimaryNetworkID, nodeID, weightDiff.Amount)
 		} else {
-			err = s.cfg.Validators.AddWeight(constants.PrimaryNetworkID, nodeID, weightDiff.Amount)
+			err = s.addValidatorWeight(constants.PrimaryNetworkID, nodeID, weightDiff.Amount)
 		}
 		if err != nil {
 			return fmt.Errorf("failed to update validator weight: %w", err)
@@ -1588,6 +1623,10 @@ func (s *state) writeCurrentSubnetStakers(height uint64) error {
 				weightDiff.Decrease = validatorDiff.validatorDeleted
 				weightDiff.Amount = staker.Weight
 
+				if err := s.writeStakingPeriod(staker, validatorDiff.validatorDeleted, height); err != nil {
+					return err
+				}
+
 				if validatorDiff.validatorDeleted {
 					err = s.currentSubnetValidatorList.Delete(staker.TxID[:])
 				} else {
@@ -1629,7 +1668,7 @@ func (s *state) writeCurrentSubnetStakers(height uint64) error {
 				if weightDiff.Decrease {
 					err = s.cfg.Validators.RemoveWeight(subnetID, nodeID, weightDiff.Amount)
 				} else {
-					err = s.cfg.Validators.AddWeight(subnetID, nodeID, weightDiff.Amount)
+					err = s.addValidatorWeight(subnetID, nodeID, weightDiff.Amount)
 				}
 				if err != nil {
 					return fmt.Errorf("failed to update validator weight: %w", err)
@@ -1641,6 +1680,17 @@ func (s *state) writeCurrentSubnetStakers(height uint64) error {
 	return nil
 }
 
+// addValidatorWeight adds [weight] to [nodeID] in the validator set of
+// [subnetID], along with the BLS public key it registered on the primary
+// network.
+func (s *state) addValidatorWeight(subnetID ids.ID, nodeID ids.NodeID, weight uint64) error {
+	publicKey, err := s.getCurrentPublicKey(nodeID)
+	if err != nil {
+		return err
+	}
+	return s.cfg.Validators.Add(subnetID, nodeID, publicKey, weight)
+}
+
 func writeCurrentDelegatorDiff(
 	currentDelegatorList linkeddb.LinkedDB,
 	weightDiff *ValidatorWeightDiff,
@@ -14,10 +14,13 @@ import (
 
 	"github.com/dim4egster/qmallgo/database"
 	"github.com/dim4egster/qmallgo/database/memdb"
+	"github.com/dim4egster/qmallgo/database/prefixdb"
 	"github.com/dim4egster/qmallgo/ids"
 	"github.com/dim4egster/qmallgo/snow"
+	"github.com/dim4egster/qmallgo/snow/choices"
 	"github.com/dim4egster/qmallgo/snow/validators"
 	"github.com/dim4egster/qmallgo/utils/constants"
+	"github.com/dim4egster/qmallgo/utils/crypto/bls"
 	"github.com/dim4egster/qmallgo/utils/units"
 	"github.com/dim4egster/qmallgo/utils/wrappers"
 	"github.com/dim4egster/qmallgo/vms/components/avax"
@@ -26,6 +29,8 @@ import (
 	"github.com/dim4egster/qmallgo/vms/platformvm/genesis"
 	"github.com/dim4egster/qmallgo/vms/platformvm/metrics"
 	"github.com/dim4egster/qmallgo/vms/platformvm/reward"
+	"github.com/dim4egster/qmallgo/vms/platformvm/signer"
+	"github.com/dim4egster/qmallgo/vms/platformvm/status"
 	"github.com/dim4egster/qmallgo/vms/platformvm/txs"
 	"github.com/dim4egster/qmallgo/vms/platformvm/validator"
 	"github.com/dim4egster/qmallgo/vms/secp256k1fx"
@@ -486,3 +491,183 @@ func TestValidatorWeightDiff(t *testing.T) {
 		})
 	}
 }
+
+func TestValidatorHistory(t *testing.T) {
+	require := require.New(t)
+	stateIntf, _ := newInitializedState(require)
+	state := stateIntf.(*state)
+
+	sk, err := bls.NewSecretKey()
+	require.NoError(err)
+	pop := signer.NewProofOfPossession(sk)
+
+	nodeID := ids.GenerateTestNodeID()
+	tx := &txs.Tx{Unsigned: &txs.AddPermissionlessValidatorTx{
+		BaseTx: txs.BaseTx{BaseTx: avax.BaseTx{
+			NetworkID:    constants.UnitTestID,
+			BlockchainID: ids.GenerateTestID(),
+		}},
+		Validator: validator.Validator{
+			NodeID: nodeID,
+			Start:  uint64(initialTime.Unix()),
+			End:    uint64(initialValidatorEndTime.Unix()),
+			Wght:   units.Avax,
+		},
+		Subnet:                constants.PrimaryNetworkID,
+		Signer:                pop,
+		ValidatorRewardsOwner: &secp256k1fx.OutputOwners{},
+		DelegatorRewardsOwner: &secp256k1fx.OutputOwners{},
+	}}
+	require.NoError(tx.Sign(txs.Codec, nil))
+
+	staker := &Staker{
+		TxID:      tx.ID(),
+		NodeID:    nodeID,
+		SubnetID:  constants.PrimaryNetworkID,
+		Weight:    units.Avax,
+		StartTime: initialTime,
+		EndTime:   initialValidatorEndTime,
+	}
+	state.AddTx(tx, status.Committed)
+	state.PutCurrentValidator(staker)
+	state.SetHeight(1)
+	require.NoError(state.Commit())
+
+	history, err := state.GetValidatorHistory(nodeID)
+	require.NoError(err)
+	require.Equal([]*StakingPeriod{{
+		TxID:        tx.ID(),
+		SubnetID:    constants.PrimaryNetworkID,
+		Weight:      units.Avax,
+		StartTime:   uint64(initialTime.Unix()),
+		EndTime:     uint64(initialValidatorEndTime.Unix()),
+		StartHeight: 1,
+		PublicKey:   pop.PublicKey[:],
+	}}, history)
+
+	state.DeleteCurrentValidator(staker)
+	state.SetHeight(2)
+	require.NoError(state.Commit())
+
+	history, err = state.GetValidatorHistory(nodeID)
+	require.NoError(err)
+	require.Len(history, 1)
+	require.EqualValues(2, history[0].EndHeight)
+
+	history, err = state.GetValidatorHistory(ids.GenerateTestNodeID())
+	require.NoError(err)
+	require.Empty(history)
+}
+
+func TestBackfillValidatorHistory(t *testing.T) {
+	require := require.New(t)
+	stateIntf, _ := newInitializedState(require)
+	state := stateIntf.(*state)
+
+	acceptBlock := func(height uint64) {
+		blk, err := blocks.NewApricotCommitBlock(state.GetLastAccepted(), height)
+		require.NoError(err)
+		state.AddStatelessBlock(blk, choices.Accepted)
+		state.SetLastAccepted(blk.ID())
+		state.SetHeight(height)
+		require.NoError(state.Commit())
+	}
+	acceptBlock(1)
+
+	sk, err := bls.NewSecretKey()
+	require.NoError(err)
+	pop := signer.NewProofOfPossession(sk)
+
+	nodeID := ids.GenerateTestNodeID()
+	tx := &txs.Tx{Unsigned: &txs.AddPermissionlessValidatorTx{
+		BaseTx: txs.BaseTx{BaseTx: avax.BaseTx{
+			NetworkID:    constants.UnitTestID,
+			BlockchainID: ids.GenerateTestID(),
+		}},
+		Validator: validator.Validator{
+			NodeID: nodeID,
+			Start:  uint64(initialTime.Unix()),
+			End:    uint64(initialValidatorEndTime.Unix()),
+			Wght:   units.Avax,
+		},
+		Subnet:                constants.PrimaryNetworkID,
+		Signer:                pop,
+		ValidatorRewardsOwner: &secp256k1fx.OutputOwners{},
+		DelegatorRewardsOwner: &secp256k1fx.OutputOwners{},
+	}}
+	require.NoError(tx.Sign(txs.Codec, nil))
+
+	staker := &Staker{
+		TxID:      tx.ID(),
+		NodeID:    nodeID,
+		SubnetID:  constants.PrimaryNetworkID,
+		Weight:    units.Avax,
+		StartTime: initialTime,
+		EndTime:   initialValidatorEndTime,
+	}
+	state.AddTx(tx, status.Committed)
+	state.PutCurrentValidator(staker)
+	acceptBlock(2)
+
+	state.PutCurrentDelegator(&Staker{
+		TxID:     ids.GenerateTestID(),
+		NodeID:   nodeID,
+		SubnetID: constants.PrimaryNetworkID,
+		Weight:   units.Avax,
+	})
+	acceptBlock(3)
+
+	// Forget the history, as if the validators were added before the history
+	// was recorded.
+	initialStaker, err := state.GetCurrentValidator(constants.PrimaryNetworkID, initialNodeID)
+	require.NoError(err)
+	for _, staker := range []*Staker{staker, initialStaker} {
+		historyDB := prefixdb.New(staker.NodeID[:], state.validatorHistoryDB)
+		require.NoError(historyDB.Delete(staker.TxID[:]))
+	}
+	require.NoError(state.Commit())
+
+	state.cfg.ValidatorHistoryBackfillDepth = 3
+	require.NoError(state.backfillValidatorHistory())
+
+	history, err := state.GetValidatorHistory(nodeID)
+	require.NoError(err)
+	require.Equal([]*StakingPeriod{{
+		TxID:        tx.ID(),
+		SubnetID:    constants.PrimaryNetworkID,
+		Weight:      units.Avax,
+		StartTime:   uint64(initialTime.Unix()),
+		EndTime:     uint64(initialValidatorEndTime.Unix()),
+		StartHeight: 2,
+		PublicKey:   pop.PublicKey[:],
+	}}, history)
+
+	history, err = state.GetValidatorHistory(initialNodeID)
+	require.NoError(err)
+	require.Len(history, 1)
+	require.Zero(history[0].StartHeight)
+
+	// The history is only backfilled once.
+	historyDB := prefixdb.New(nodeID[:], state.validatorHistoryDB)
+	require.NoError(historyDB.Delete(staker.TxID[:]))
+	require.NoError(state.backfillValidatorHistory())
+	history, err = state.GetValidatorHistory(nodeID)
+	require.NoError(err)
+	require.Empty(history)
+
+	// Periods that started more than the backfill depth before the last
+	// accepted block are partial.
+	require.NoError(state.singletonDB.Delete(validatorHistoryBackfilledKey))
+	historyDB = prefixdb.New(initialNodeID[:], state.validatorHistoryDB)
+	require.NoError(historyDB.Delete(initialStaker.TxID[:]))
+	state.cfg.ValidatorHistoryBackfillDepth = 1
+	require.NoError(state.backfillValidatorHistory())
+
+	for _, nodeID := range []ids.NodeID{nodeID, initialNodeID} {
+		history, err = state.GetValidatorHistory(nodeID)
+		require.NoError(err)
+		require.Len(history, 1)
+		require.Equal(uint64(2), history[0].StartHeight)
+		require.True(history[0].Partial)
+	}
+}
@@ -0,0 +1,292 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/dim4egster/qmallgo/database"
+	"github.com/dim4egster/qmallgo/database/prefixdb"
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/utils/constants"
+	"github.com/dim4egster/qmallgo/utils/crypto/bls"
+	"github.com/dim4egster/qmallgo/utils/math"
+	"github.com/dim4egster/qmallgo/vms/platformvm/genesis"
+	"github.com/dim4egster/qmallgo/vms/platformvm/signer"
+	"github.com/dim4egster/qmallgo/vms/platformvm/txs"
+)
+
+// StakingPeriod is a period during which a node validated a subnet.
+type StakingPeriod struct {
+	TxID      ids.ID `serialize:"true"`
+	SubnetID  ids.ID `serialize:"true"`
+	Weight    uint64 `serialize:"true"`
+	StartTime uint64 `serialize:"true"`
+	EndTime   uint64 `serialize:"true"`
+	// StartHeight is the height of the block that added the validator.
+	StartHeight uint64 `serialize:"true"`
+	// EndHeight is the height of the block that removed the validator. It is
+	// 0 while the validator is in the current validator set.
+	EndHeight uint64 `serialize:"true"`
+	// PublicKey is the BLS public key registered by the validator, if any.
+	PublicKey []byte `serialize:"true"`
+	// Partial is true if the period was backfilled and started before the
+	// oldest height that was searched. StartHeight is then that height, at
+	// which the validator was already validating.
+	Partial bool `serialize:"true"`
+}
+
+func (s *state) GetValidatorHistory(nodeID ids.NodeID) ([]*StakingPeriod, error) {
+	historyDB := prefixdb.New(nodeID[:], s.validatorHistoryDB)
+	iter := historyDB.NewIterator()
+	defer iter.Release()
+
+	var periods []*StakingPeriod
+	for iter.Next() {
+		period := &StakingPeriod{}
+		if _, err := genesis.Codec.Unmarshal(iter.Value(), period); err != nil {
+			return nil, err
+		}
+		periods = append(periods, period)
+	}
+	sort.SliceStable(periods, func(i, j int) bool {
+		return periods[i].StartHeight < periods[j].StartHeight
+	})
+	return periods, iter.Error()
+}
+
+// writeStakingPeriod records that [staker] was added to, or removed from, the
+// current validator set at [height].
+func (s *state) writeStakingPeriod(staker *Staker, removed bool, height uint64) error {
+	historyDB := prefixdb.New(staker.NodeID[:], s.validatorHistoryDB)
+
+	period := &StakingPeriod{}
+	if removed {
+		periodBytes, err := historyDB.Get(staker.TxID[:])
+		if err == database.ErrNotFound {
+			// The validator was added before the history was recorded and
+			// removed before it was backfilled.
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get staking period: %w", err)
+		}
+		if _, err := genesis.Codec.Unmarshal(periodBytes, period); err != nil {
+			return fmt.Errorf("failed to parse staking period: %w", err)
+		}
+		period.EndHeight = height
+	} else {
+		var err error
+		period, err = s.newStakingPeriod(staker, height)
+		if err != nil {
+			return err
+		}
+	}
+	return putStakingPeriod(historyDB, period)
+}
+
+// newStakingPeriod returns the staking period of [staker], which was added to
+// the current validator set at [height].
+func (s *state) newStakingPeriod(staker *Staker, height uint64) (*StakingPeriod, error) {
+	publicKey, err := s.getPublicKey(staker.TxID)
+	if err != nil {
+		return nil, err
+	}
+	return &StakingPeriod{
+		TxID:        staker.TxID,
+		SubnetID:    staker.SubnetID,
+		Weight:      staker.Weight,
+		StartTime:   uint64(staker.StartTime.Unix()),
+		EndTime:     uint64(staker.EndTime.Unix()),
+		StartHeight: height,
+		PublicKey:   publicKey,
+	}, nil
+}
+
+func putStakingPeriod(historyDB database.KeyValueWriter, period *StakingPeriod) error {
+	periodBytes, err := genesis.Codec.Marshal(txs.Version, period)
+	if err != nil {
+		return fmt.Errorf("failed to serialize staking period: %w", err)
+	}
+	return historyDB.Put(period.TxID[:], periodBytes)
+}
+
+// backfillValidatorHistory records the staking periods of the current
+// validators that were added before the history was recorded. It only runs
+// once per database.
+//
+// The height each period started at is found by walking the validator weight
+// diffs back from the last accepted block until the validator's weight drops
+// to zero. At most [ValidatorHistoryBackfillDepth] blocks are walked, so that
+// the first start doesn't walk back to genesis. Periods that started before
+// that are recorded as partial. Staking periods that ended before the history
+// was recorded can't be recovered, as their txs aren't tracked.
+func (s *state) backfillValidatorHistory() error {
+	backfilled, err := s.singletonDB.Has(validatorHistoryBackfilledKey)
+	if err != nil || backfilled {
+		return err
+	}
+
+	lastAccepted, _, err := s.GetStatelessBlock(s.lastAccepted)
+	if err != nil {
+		return fmt.Errorf("failed to get last accepted block: %w", err)
+	}
+	height := lastAccepted.Height()
+	minHeight := uint64(0)
+	if depth := s.cfg.ValidatorHistoryBackfillDepth; height > depth {
+		minHeight = height - depth
+	}
+
+	for subnetID, subnetValidators := range s.currentStakers.validators {
+		// Weight of each validator that is missing from the history, as of
+		// [height].
+		weights := make(map[ids.NodeID]uint64)
+		for nodeID, validator := range subnetValidators {
+			staker := validator.validator
+			if staker == nil {
+				continue
+			}
+			historyDB := prefixdb.New(nodeID[:], s.validatorHistoryDB)
+			hasPeriod, err := historyDB.Has(staker.TxID[:])
+			if err != nil {
+				return fmt.Errorf("failed to get staking period: %w", err)
+			}
+			if hasPeriod {
+				continue
+			}
+
+			weight := staker.Weight
+			delegatorIterator := NewTreeIterator(validator.delegators)
+			for delegatorIterator.Next() {
+				weight, err = math.Add64(weight, delegatorIterator.Value().Weight)
+				if err != nil {
+					delegatorIterator.Release()
+					return err
+				}
+			}
+			delegatorIterator.Release()
+			weights[nodeID] = weight
+		}
+
+		startHeights, err := s.getStakingStartHeights(subnetID, weights, height, minHeight)
+		if err != nil {
+			return err
+		}
+		for nodeID, startHeight := range startHeights {
+			staker := subnetValidators[nodeID].validator
+			period, err := s.newStakingPeriod(staker, startHeight)
+			if err != nil {
+				return err
+			}
+			// The weights of the nodes whose start wasn't found were left in
+			// [weights].
+			_, period.Partial = weights[nodeID]
+			historyDB := prefixdb.New(nodeID[:], s.validatorHistoryDB)
+			if err := putStakingPeriod(historyDB, period); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := s.singletonDB.Put(validatorHistoryBackfilledKey, nil); err != nil {
+		return err
+	}
+	return s.Commit()
+}
+
+// getStakingStartHeights returns the height at which each node in [weights]
+// most recently started validating [subnetID], given the node's weight after
+// the block at [height] was accepted. The diffs of the blocks at or below
+// [minHeight] aren't read. The nodes that were already validating at
+// [minHeight] are left in [weights], with [minHeight] as their start height,
+// unless [minHeight] is genesis.
+func (s *state) getStakingStartHeights(
+	subnetID ids.ID,
+	weights map[ids.NodeID]uint64,
+	height uint64,
+	minHeight uint64,
+) (map[ids.NodeID]uint64, error) {
+	startHeights := make(map[ids.NodeID]uint64, len(weights))
+	for ; len(weights) > 0 && height > minHeight; height-- {
+		weightDiffs, err := s.GetValidatorWeightDiffs(height, subnetID)
+		if err != nil {
+			return nil, err
+		}
+		for nodeID, weightDiff := range weightDiffs {
+			weight, ok := weights[nodeID]
+			if !ok {
+				continue
+			}
+
+			// Undo the diff to get the node's weight before [height].
+			if weightDiff.Decrease {
+				weight, err = math.Add64(weight, weightDiff.Amount)
+				if err != nil {
+					return nil, err
+				}
+			} else {
+				weight, err = math.Sub64(weight, weightDiff.Amount)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			if weight == 0 {
+				startHeights[nodeID] = height
+				delete(weights, nodeID)
+			} else {
+				weights[nodeID] = weight
+			}
+		}
+	}
+
+	// The remaining nodes have validated since [height]. If [height] is
+	// genesis, their start heights are known.
+	for nodeID := range weights {
+		startHeights[nodeID] = height
+		if height == 0 {
+			delete(weights, nodeID)
+		}
+	}
+	return startHeights, nil
+}
+
+// getPublicKey returns the BLS public key registered by the validator tx
+// [txID], or nil if the tx didn't register one.
+func (s *state) getPublicKey(txID ids.ID) ([]byte, error) {
+	tx, _, err := s.GetTx(txID)
+	if err == database.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get validator tx %s: %w", txID, err)
+	}
+	addValidatorTx, ok := tx.Unsigned.(*txs.AddPermissionlessValidatorTx)
+	if !ok {
+		return nil, nil
+	}
+	pop, ok := addValidatorTx.Signer.(*signer.ProofOfPossession)
+	if !ok {
+		return nil, nil
+	}
+	return pop.PublicKey[:], nil
+}
+
+// getCurrentPublicKey returns the BLS public key registered by [nodeID] as a
+// current primary network validator, or nil if it didn't register one.
+func (s *state) getCurrentPublicKey(nodeID ids.NodeID) (*bls.PublicKey, error) {
+	staker, err := s.GetCurrentValidator(constants.PrimaryNetworkID, nodeID)
+	if err == database.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	publicKeyBytes, err := s.getPublicKey(staker.TxID)
+	if err != nil || len(publicKeyBytes) == 0 {
+		return nil, err
+	}
+	return bls.PublicKeyFromBytes(publicKeyBytes)
+}
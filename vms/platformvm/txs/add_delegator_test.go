@@ -12,6 +12,7 @@ import (
 	"github.com/dim4egster/qmallgo/ids"
 	"github.com/dim4egster/qmallgo/snow"
 	"github.com/dim4egster/qmallgo/utils/crypto"
+	"github.com/dim4egster/qmallgo/utils/crypto/keychain"
 	"github.com/dim4egster/qmallgo/utils/timer/mockable"
 	"github.com/dim4egster/qmallgo/vms/components/avax"
 	"github.com/dim4egster/qmallgo/vms/platformvm/stakeable"
@@ -19,14 +20,23 @@ import (
 	"github.com/dim4egster/qmallgo/vms/secp256k1fx"
 )
 
-var preFundedKeys = crypto.BuildTestKeys()
+var (
+	preFundedKeys    = crypto.BuildTestKeys()
+	preFundedSigners = make([]keychain.Signer, len(preFundedKeys))
+)
+
+func init() {
+	for i, key := range preFundedKeys {
+		preFundedSigners[i] = key
+	}
+}
 
 func TestAddDelegatorTxSyntacticVerify(t *testing.T) {
 	require := require.New(t)
 	clk := mockable.Clock{}
 	ctx := snow.DefaultContextTest()
 	ctx.AVAXAssetID = ids.GenerateTestID()
-	signers := [][]*crypto.PrivateKeySECP256K1R{preFundedKeys}
+	signers := [][]keychain.Signer{preFundedSigners}
 
 	var (
 		stx            *Tx
@@ -129,7 +139,7 @@ func TestAddDelegatorTxSyntacticVerifyNotAVAX(t *testing.T) {
 	clk := mockable.Clock{}
 	ctx := snow.DefaultContextTest()
 	ctx.AVAXAssetID = ids.GenerateTestID()
-	signers := [][]*crypto.PrivateKeySECP256K1R{preFundedKeys}
+	signers := [][]keychain.Signer{preFundedSigners}
 
 	var (
 		stx            *Tx
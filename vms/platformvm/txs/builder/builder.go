@@ -10,7 +10,7 @@ import (
 
 	"github.com/dim4egster/qmallgo/ids"
 	"github.com/dim4egster/qmallgo/snow"
-	"github.com/dim4egster/qmallgo/utils/crypto"
+	"github.com/dim4egster/qmallgo/utils/crypto/keychain"
 	"github.com/dim4egster/qmallgo/utils/math"
 	"github.com/dim4egster/qmallgo/utils/timer/mockable"
 	"github.com/dim4egster/qmallgo/vms/components/avax"
@@ -41,25 +41,25 @@ type Builder interface {
 type AtomicTxBuilder interface {
 	// chainID: chain to import UTXOs from
 	// to: address of recipient
-	// keys: keys to import the funds
+	// kc: keychain to import the funds
 	// changeAddr: address to send change to, if there is any
 	NewImportTx(
 		chainID ids.ID,
 		to ids.ShortID,
-		keys []*crypto.PrivateKeySECP256K1R,
+		kc keychain.Keychain,
 		changeAddr ids.ShortID,
 	) (*txs.Tx, error)
 
 	// amount: amount of tokens to export
 	// chainID: chain to send the UTXOs to
 	// to: address of recipient
-	// keys: keys to pay the fee and provide the tokens
+	// kc: keychain to pay the fee and provide the tokens
 	// changeAddr: address to send change to, if there is any
 	NewExportTx(
 		amount uint64,
 		chainID ids.ID,
 		to ids.ShortID,
-		keys []*crypto.PrivateKeySECP256K1R,
+		kc keychain.Keychain,
 		changeAddr ids.ShortID,
 	) (*txs.Tx, error)
 }
@@ -70,7 +70,7 @@ type DecisionTxBuilder interface {
 	// vmID: ID of VM this chain runs
 	// fxIDs: ids of features extensions this chain supports
 	// chainName: name of the chain
-	// keys: keys to sign the tx
+	// kc: keychain to sign the tx
 	// changeAddr: address to send change to, if there is any
 	NewCreateChainTx(
 		subnetID ids.ID,
@@ -78,18 +78,18 @@ type DecisionTxBuilder interface {
 		vmID ids.ID,
 		fxIDs []ids.ID,
 		chainName string,
-		keys []*crypto.PrivateKeySECP256K1R,
+		kc keychain.Keychain,
 		changeAddr ids.ShortID,
 	) (*txs.Tx, error)
 
 	// threshold: [threshold] of [ownerAddrs] needed to manage this subnet
 	// ownerAddrs: control addresses for the new subnet
-	// keys: keys to pay the fee
+	// kc: keychain to pay the fee
 	// changeAddr: address to send change to, if there is any
 	NewCreateSubnetTx(
 		threshold uint32,
 		ownerAddrs []ids.ShortID,
-		keys []*crypto.PrivateKeySECP256K1R,
+		kc keychain.Keychain,
 		changeAddr ids.ShortID,
 	) (*txs.Tx, error)
 }
@@ -101,7 +101,7 @@ type ProposalTxBuilder interface {
 	// nodeID: ID of the node we want to validate with
 	// rewardAddress: address to send reward to, if applicable
 	// shares: 10,000 times percentage of reward taken from delegators
-	// keys: Keys providing the staked tokens
+	// kc: Keychain providing the staked tokens
 	// changeAddr: Address to send change to, if there is any
 	NewAddValidatorTx(
 		stakeAmount,
@@ -110,7 +110,7 @@ type ProposalTxBuilder interface {
 		nodeID ids.NodeID,
 		rewardAddress ids.ShortID,
 		shares uint32,
-		keys []*crypto.PrivateKeySECP256K1R,
+		kc keychain.Keychain,
 		changeAddr ids.ShortID,
 	) (*txs.Tx, error)
 
@@ -119,7 +119,7 @@ type ProposalTxBuilder interface {
 	// endTime: unix time they stop delegating
 	// nodeID: ID of the node we are delegating to
 	// rewardAddress: address to send reward to, if applicable
-	// keys: keys providing the staked tokens
+	// kc: keychain providing the staked tokens
 	// changeAddr: address to send change to, if there is any
 	NewAddDelegatorTx(
 		stakeAmount,
@@ -127,7 +127,7 @@ type ProposalTxBuilder interface {
 		endTime uint64,
 		nodeID ids.NodeID,
 		rewardAddress ids.ShortID,
-		keys []*crypto.PrivateKeySECP256K1R,
+		kc keychain.Keychain,
 		changeAddr ids.ShortID,
 	) (*txs.Tx, error)
 
@@ -136,7 +136,7 @@ type ProposalTxBuilder interface {
 	// endTime:  unix time they top delegating
 	// nodeID: ID of the node validating
 	// subnetID: ID of the subnet the validator will validate
-	// keys: keys to use for adding the validator
+	// kc: keychain to use for adding the validator
 	// changeAddr: address to send change to, if there is any
 	NewAddSubnetValidatorTx(
 		weight,
@@ -144,18 +144,18 @@ type ProposalTxBuilder interface {
 		endTime uint64,
 		nodeID ids.NodeID,
 		subnetID ids.ID,
-		keys []*crypto.PrivateKeySECP256K1R,
+		kc keychain.Keychain,
 		changeAddr ids.ShortID,
 	) (*txs.Tx, error)
 
 	// Creates a transaction that removes [nodeID]
 	// as a validator from [subnetID]
-	// keys: keys to use for removing the validator
+	// kc: keychain to use for removing the validator
 	// changeAddr: address to send change to, if there is any
 	NewRemoveSubnetValidatorTx(
 		nodeID ids.NodeID,
 		subnetID ids.ID,
-		keys []*crypto.PrivateKeySECP256K1R,
+		kc keychain.Keychain,
 		changeAddr ids.ShortID,
 	) (*txs.Tx, error)
 
@@ -202,23 +202,21 @@ type builder struct {
 func (b *builder) NewImportTx(
 	from ids.ID,
 	to ids.ShortID,
-	keys []*crypto.PrivateKeySECP256K1R,
+	kc keychain.Keychain,
 	changeAddr ids.ShortID,
 ) (*txs.Tx, error) {
-	kc := secp256k1fx.NewKeychain(keys...)
-
 	atomicUTXOs, _, _, err := b.GetAtomicUTXOs(from, kc.Addresses(), ids.ShortEmpty, ids.Empty, MaxPageSize)
 	if err != nil {
 		return nil, fmt.Errorf("problem retrieving atomic UTXOs: %w", err)
 	}
 
 	importedInputs := []*avax.TransferableInput{}
-	signers := [][]*crypto.PrivateKeySECP256K1R{}
+	signers := [][]keychain.Signer{}
 
 	importedAmounts := make(map[ids.ID]uint64)
 	now := b.clk.Unix()
 	for _, utxo := range atomicUTXOs {
-		inputIntf, utxoSigners, err := kc.Spend(utxo.Out, now)
+		inputIntf, utxoSigners, err := secp256k1fx.Spend(kc, utxo.Out, now)
 		if err != nil {
 			continue
 		}
@@ -250,8 +248,8 @@ func (b *builder) NewImportTx(
 	outs := []*avax.TransferableOutput{}
 	switch {
 	case importedAVAX < b.cfg.TxFee: // imported amount goes toward paying tx fee
-		var baseSigners [][]*crypto.PrivateKeySECP256K1R
-		ins, outs, _, baseSigners, err = b.Spend(keys, 0, b.cfg.TxFee-importedAVAX, changeAddr)
+		var baseSigners [][]keychain.Signer
+		ins, outs, _, baseSigners, err = b.Spend(kc, 0, b.cfg.TxFee-importedAVAX, changeAddr)
 		if err != nil {
 			return nil, fmt.Errorf("couldn't generate tx inputs/outputs: %w", err)
 		}
@@ -302,14 +300,14 @@ func (b *builder) NewExportTx(
 	amount uint64,
 	chainID ids.ID,
 	to ids.ShortID,
-	keys []*crypto.PrivateKeySECP256K1R,
+	kc keychain.Keychain,
 	changeAddr ids.ShortID,
 ) (*txs.Tx, error) {
 	toBurn, err := math.Add64(amount, b.cfg.TxFee)
 	if err != nil {
 		return nil, fmt.Errorf("amount (%d) + tx fee(%d) overflows", amount, b.cfg.TxFee)
 	}
-	ins, outs, _, signers, err := b.Spend(keys, 0, toBurn, changeAddr)
+	ins, outs, _, signers, err := b.Spend(kc, 0, toBurn, changeAddr)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't generate tx inputs/outputs: %w", err)
 	}
@@ -348,17 +346,17 @@ func (b *builder) NewCreateChainTx(
 	vmID ids.ID,
 	fxIDs []ids.ID,
 	chainName string,
-	keys []*crypto.PrivateKeySECP256K1R,
+	kc keychain.Keychain,
 	changeAddr ids.ShortID,
 ) (*txs.Tx, error) {
 	timestamp := b.state.GetTimestamp()
 	createBlockchainTxFee := b.cfg.GetCreateBlockchainTxFee(timestamp)
-	ins, outs, _, signers, err := b.Spend(keys, 0, createBlockchainTxFee, changeAddr)
+	ins, outs, _, signers, err := b.Spend(kc, 0, createBlockchainTxFee, changeAddr)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't generate tx inputs/outputs: %w", err)
 	}
 
-	subnetAuth, subnetSigners, err := b.Authorize(b.state, subnetID, keys)
+	subnetAuth, subnetSigners, err := b.Authorize(b.state, subnetID, kc)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't authorize tx's subnet restrictions: %w", err)
 	}
@@ -392,12 +390,12 @@ func (b *builder) NewCreateChainTx(
 func (b *builder) NewCreateSubnetTx(
 	threshold uint32,
 	ownerAddrs []ids.ShortID,
-	keys []*crypto.PrivateKeySECP256K1R,
+	kc keychain.Keychain,
 	changeAddr ids.ShortID,
 ) (*txs.Tx, error) {
 	timestamp := b.state.GetTimestamp()
 	createSubnetTxFee := b.cfg.GetCreateSubnetTxFee(timestamp)
-	ins, outs, _, signers, err := b.Spend(keys, 0, createSubnetTxFee, changeAddr)
+	ins, outs, _, signers, err := b.Spend(kc, 0, createSubnetTxFee, changeAddr)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't generate tx inputs/outputs: %w", err)
 	}
@@ -432,10 +430,10 @@ func (b *builder) NewAddValidatorTx(
 	nodeID ids.NodeID,
 	rewardAddress ids.ShortID,
 	shares uint32,
-	keys []*crypto.PrivateKeySECP256K1R,
+	kc keychain.Keychain,
 	changeAddr ids.ShortID,
 ) (*txs.Tx, error) {
-	ins, unstakedOuts, stakedOuts, signers, err := b.Spend(keys, stakeAmount, b.cfg.AddPrimaryNetworkValidatorFee, changeAddr)
+	ins, unstakedOuts, stakedOuts, signers, err := b.Spend(kc, stakeAmount, b.cfg.AddPrimaryNetworkValidatorFee, changeAddr)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't generate tx inputs/outputs: %w", err)
 	}
@@ -474,10 +472,10 @@ func (b *builder) NewAddDelegatorTx(
 	endTime uint64,
 	nodeID ids.NodeID,
 	rewardAddress ids.ShortID,
-	keys []*crypto.PrivateKeySECP256K1R,
+	kc keychain.Keychain,
 	changeAddr ids.ShortID,
 ) (*txs.Tx, error) {
-	ins, unlockedOuts, lockedOuts, signers, err := b.Spend(keys, stakeAmount, b.cfg.AddPrimaryNetworkDelegatorFee, changeAddr)
+	ins, unlockedOuts, lockedOuts, signers, err := b.Spend(kc, stakeAmount, b.cfg.AddPrimaryNetworkDelegatorFee, changeAddr)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't generate tx inputs/outputs: %w", err)
 	}
@@ -515,15 +513,15 @@ func (b *builder) NewAddSubnetValidatorTx(
 	endTime uint64,
 	nodeID ids.NodeID,
 	subnetID ids.ID,
-	keys []*crypto.PrivateKeySECP256K1R,
+	kc keychain.Keychain,
 	changeAddr ids.ShortID,
 ) (*txs.Tx, error) {
-	ins, outs, _, signers, err := b.Spend(keys, 0, b.cfg.TxFee, changeAddr)
+	ins, outs, _, signers, err := b.Spend(kc, 0, b.cfg.TxFee, changeAddr)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't generate tx inputs/outputs: %w", err)
 	}
 
-	subnetAuth, subnetSigners, err := b.Authorize(b.state, subnetID, keys)
+	subnetAuth, subnetSigners, err := b.Authorize(b.state, subnetID, kc)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't authorize tx's subnet restrictions: %w", err)
 	}
@@ -558,15 +556,15 @@ func (b *builder) NewAddSubnetValidatorTx(
 func (b *builder) NewRemoveSubnetValidatorTx(
 	nodeID ids.NodeID,
 	subnetID ids.ID,
-	keys []*crypto.PrivateKeySECP256K1R,
+	kc keychain.Keychain,
 	changeAddr ids.ShortID,
 ) (*txs.Tx, error) {
-	ins, outs, _, signers, err := b.Spend(keys, 0, b.cfg.TxFee, changeAddr)
+	ins, outs, _, signers, err := b.Spend(kc, 0, b.cfg.TxFee, changeAddr)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't generate tx inputs/outputs: %w", err)
 	}
 
-	subnetAuth, subnetSigners, err := b.Authorize(b.state, subnetID, keys)
+	subnetAuth, subnetSigners, err := b.Authorize(b.state, subnetID, kc)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't authorize tx's subnet restrictions: %w", err)
 	}
@@ -9,7 +9,7 @@ import (
 	time "time"
 
 	ids "github.com/dim4egster/qmallgo/ids"
-	crypto "github.com/dim4egster/qmallgo/utils/crypto"
+	keychain "github.com/dim4egster/qmallgo/utils/crypto/keychain"
 	txs "github.com/dim4egster/qmallgo/vms/platformvm/txs"
 	gomock "github.com/golang/mock/gomock"
 )
@@ -38,7 +38,7 @@ func (m *MockBuilder) EXPECT() *MockBuilderMockRecorder {
 }
 
 // NewAddDelegatorTx mocks base method.
-func (m *MockBuilder) NewAddDelegatorTx(arg0, arg1, arg2 uint64, arg3 ids.NodeID, arg4 ids.ShortID, arg5 []*crypto.PrivateKeySECP256K1R, arg6 ids.ShortID) (*txs.Tx, error) {
+func (m *MockBuilder) NewAddDelegatorTx(arg0, arg1, arg2 uint64, arg3 ids.NodeID, arg4 ids.ShortID, arg5 keychain.Keychain, arg6 ids.ShortID) (*txs.Tx, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "NewAddDelegatorTx", arg0, arg1, arg2, arg3, arg4, arg5, arg6)
 	ret0, _ := ret[0].(*txs.Tx)
@@ -53,7 +53,7 @@ func (mr *MockBuilderMockRecorder) NewAddDelegatorTx(arg0, arg1, arg2, arg3, arg
 }
 
 // NewAddSubnetValidatorTx mocks base method.
-func (m *MockBuilder) NewAddSubnetValidatorTx(arg0, arg1, arg2 uint64, arg3 ids.NodeID, arg4 ids.ID, arg5 []*crypto.PrivateKeySECP256K1R, arg6 ids.ShortID) (*txs.Tx, error) {
+func (m *MockBuilder) NewAddSubnetValidatorTx(arg0, arg1, arg2 uint64, arg3 ids.NodeID, arg4 ids.ID, arg5 keychain.Keychain, arg6 ids.ShortID) (*txs.Tx, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "NewAddSubnetValidatorTx", arg0, arg1, arg2, arg3, arg4, arg5, arg6)
 	ret0, _ := ret[0].(*txs.Tx)
@@ -68,7 +68,7 @@ func (mr *MockBuilderMockRecorder) NewAddSubnetValidatorTx(arg0, arg1, arg2, arg
 }
 
 // NewAddValidatorTx mocks base method.
-func (m *MockBuilder) NewAddValidatorTx(arg0, arg1, arg2 uint64, arg3 ids.NodeID, arg4 ids.ShortID, arg5 uint32, arg6 []*crypto.PrivateKeySECP256K1R, arg7 ids.ShortID) (*txs.Tx, error) {
+func (m *MockBuilder) NewAddValidatorTx(arg0, arg1, arg2 uint64, arg3 ids.NodeID, arg4 ids.ShortID, arg5 uint32, arg6 keychain.Keychain, arg7 ids.ShortID) (*txs.Tx, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "NewAddValidatorTx", arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7)
 	ret0, _ := ret[0].(*txs.Tx)
@@ -98,7 +98,7 @@ func (mr *MockBuilderMockRecorder) NewAdvanceTimeTx(arg0 interface{}) *gomock.Ca
 }
 
 // NewCreateChainTx mocks base method.
-func (m *MockBuilder) NewCreateChainTx(arg0 ids.ID, arg1 []byte, arg2 ids.ID, arg3 []ids.ID, arg4 string, arg5 []*crypto.PrivateKeySECP256K1R, arg6 ids.ShortID) (*txs.Tx, error) {
+func (m *MockBuilder) NewCreateChainTx(arg0 ids.ID, arg1 []byte, arg2 ids.ID, arg3 []ids.ID, arg4 string, arg5 keychain.Keychain, arg6 ids.ShortID) (*txs.Tx, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "NewCreateChainTx", arg0, arg1, arg2, arg3, arg4, arg5, arg6)
 	ret0, _ := ret[0].(*txs.Tx)
@@ -113,7 +113,7 @@ func (mr *MockBuilderMockRecorder) NewCreateChainTx(arg0, arg1, arg2, arg3, arg4
 }
 
 // NewCreateSubnetTx mocks base method.
-func (m *MockBuilder) NewCreateSubnetTx(arg0 uint32, arg1 []ids.ShortID, arg2 []*crypto.PrivateKeySECP256K1R, arg3 ids.ShortID) (*txs.Tx, error) {
+func (m *MockBuilder) NewCreateSubnetTx(arg0 uint32, arg1 []ids.ShortID, arg2 keychain.Keychain, arg3 ids.ShortID) (*txs.Tx, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "NewCreateSubnetTx", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(*txs.Tx)
@@ -128,7 +128,7 @@ func (mr *MockBuilderMockRecorder) NewCreateSubnetTx(arg0, arg1, arg2, arg3 inte
 }
 
 // NewExportTx mocks base method.
-func (m *MockBuilder) NewExportTx(arg0 uint64, arg1 ids.ID, arg2 ids.ShortID, arg3 []*crypto.PrivateKeySECP256K1R, arg4 ids.ShortID) (*txs.Tx, error) {
+func (m *MockBuilder) NewExportTx(arg0 uint64, arg1 ids.ID, arg2 ids.ShortID, arg3 keychain.Keychain, arg4 ids.ShortID) (*txs.Tx, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "NewExportTx", arg0, arg1, arg2, arg3, arg4)
 	ret0, _ := ret[0].(*txs.Tx)
@@ -143,7 +143,7 @@ func (mr *MockBuilderMockRecorder) NewExportTx(arg0, arg1, arg2, arg3, arg4 inte
 }
 
 // NewImportTx mocks base method.
-func (m *MockBuilder) NewImportTx(arg0 ids.ID, arg1 ids.ShortID, arg2 []*crypto.PrivateKeySECP256K1R, arg3 ids.ShortID) (*txs.Tx, error) {
+func (m *MockBuilder) NewImportTx(arg0 ids.ID, arg1 ids.ShortID, arg2 keychain.Keychain, arg3 ids.ShortID) (*txs.Tx, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "NewImportTx", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(*txs.Tx)
@@ -158,7 +158,7 @@ func (mr *MockBuilderMockRecorder) NewImportTx(arg0, arg1, arg2, arg3 interface{
 }
 
 // NewRemoveSubnetValidatorTx mocks base method.
-func (m *MockBuilder) NewRemoveSubnetValidatorTx(arg0 ids.NodeID, arg1 ids.ID, arg2 []*crypto.PrivateKeySECP256K1R, arg3 ids.ShortID) (*txs.Tx, error) {
+func (m *MockBuilder) NewRemoveSubnetValidatorTx(arg0 ids.NodeID, arg1 ids.ID, arg2 keychain.Keychain, arg3 ids.ShortID) (*txs.Tx, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "NewRemoveSubnetValidatorTx", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(*txs.Tx)
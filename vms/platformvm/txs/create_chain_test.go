@@ -10,6 +10,7 @@ import (
 	"github.com/dim4egster/qmallgo/snow"
 	"github.com/dim4egster/qmallgo/utils/constants"
 	"github.com/dim4egster/qmallgo/utils/crypto"
+	"github.com/dim4egster/qmallgo/utils/crypto/keychain"
 	"github.com/dim4egster/qmallgo/vms/components/avax"
 	"github.com/dim4egster/qmallgo/vms/secp256k1fx"
 )
@@ -170,7 +171,7 @@ func TestUnsignedCreateChainTxVerify(t *testing.T) {
 			SubnetAuth:  subnetAuth,
 		}
 
-		signers := [][]*crypto.PrivateKeySECP256K1R{preFundedKeys}
+		signers := [][]keychain.Signer{preFundedSigners}
 		stx, err := NewSigned(createChainTx, Codec, signers)
 		if err != nil {
 			t.Fatal(err)
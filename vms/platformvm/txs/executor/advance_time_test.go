@@ -18,6 +18,7 @@ import (
 	"github.com/dim4egster/qmallgo/vms/platformvm/state"
 	"github.com/dim4egster/qmallgo/vms/platformvm/status"
 	"github.com/dim4egster/qmallgo/vms/platformvm/txs"
+	"github.com/dim4egster/qmallgo/vms/secp256k1fx"
 )
 
 // Ensure semantic verification updates the current and pending staker set
@@ -398,7 +399,7 @@ func TestAdvanceTimeTxUpdateStakers(t *testing.T) {
 					uint64(staker.endTime.Unix()),
 					staker.nodeID,    // validator ID
 					testSubnet1.ID(), // Subnet ID
-					[]*crypto.PrivateKeySECP256K1R{preFundedKeys[0], preFundedKeys[1]},
+					secp256k1fx.NewKeychain(preFundedKeys[0], preFundedKeys[1]),
 					ids.ShortEmpty,
 				)
 				require.NoError(err)
@@ -487,7 +488,7 @@ func TestAdvanceTimeTxRemoveSubnetValidator(t *testing.T) {
 		uint64(subnetVdr1EndTime.Unix()),   // end time
 		subnetValidatorNodeID,              // Node ID
 		testSubnet1.ID(),                   // Subnet ID
-		[]*crypto.PrivateKeySECP256K1R{preFundedKeys[0], preFundedKeys[1]},
+		secp256k1fx.NewKeychain(preFundedKeys[0], preFundedKeys[1]),
 		ids.ShortEmpty,
 	)
 	require.NoError(err)
@@ -513,7 +514,7 @@ func TestAdvanceTimeTxRemoveSubnetValidator(t *testing.T) {
 		uint64(subnetVdr1EndTime.Add(time.Second).Add(defaultMinStakingDuration).Unix()), // end time
 		subnetVdr2NodeID, // Node ID
 		testSubnet1.ID(), // Subnet ID
-		[]*crypto.PrivateKeySECP256K1R{preFundedKeys[0], preFundedKeys[1]}, // Keys
+		secp256k1fx.NewKeychain(preFundedKeys[0], preFundedKeys[1]), // Keys
 		ids.ShortEmpty, // reward address
 	)
 	require.NoError(err)
@@ -585,7 +586,7 @@ func TestWhitelistedSubnet(t *testing.T) {
 				uint64(subnetVdr1EndTime.Unix()),   // end time
 				ids.NodeID(subnetValidatorNodeID),  // Node ID
 				testSubnet1.ID(),                   // Subnet ID
-				[]*crypto.PrivateKeySECP256K1R{preFundedKeys[0], preFundedKeys[1]},
+				secp256k1fx.NewKeychain(preFundedKeys[0], preFundedKeys[1]),
 				ids.ShortEmpty,
 			)
 			if err != nil {
@@ -706,11 +707,7 @@ func TestAdvanceTimeTxDelegatorStakerWeight(t *testing.T) {
 		uint64(pendingDelegatorEndTime.Unix()),
 		nodeID,
 		preFundedKeys[0].PublicKey().Address(),
-		[]*crypto.PrivateKeySECP256K1R{
-			preFundedKeys[0],
-			preFundedKeys[1],
-			preFundedKeys[4],
-		},
+		secp256k1fx.NewKeychain(preFundedKeys[0], preFundedKeys[1], preFundedKeys[4]),
 		ids.ShortEmpty,
 	)
 	require.NoError(t, err)
@@ -816,7 +813,7 @@ func TestAdvanceTimeTxDelegatorStakers(t *testing.T) {
 		uint64(pendingDelegatorEndTime.Unix()),
 		nodeID,
 		preFundedKeys[0].PublicKey().Address(),
-		[]*crypto.PrivateKeySECP256K1R{preFundedKeys[0], preFundedKeys[1], preFundedKeys[4]},
+		secp256k1fx.NewKeychain(preFundedKeys[0], preFundedKeys[1], preFundedKeys[4]),
 		ids.ShortEmpty,
 	)
 	require.NoError(t, err)
@@ -983,7 +980,7 @@ func addPendingValidator(
 		nodeID,
 		ids.ShortID(nodeID),
 		reward.PercentDenominator,
-		keys,
+		secp256k1fx.NewKeychain(keys...),
 		ids.ShortEmpty,
 	)
 	if err != nil {
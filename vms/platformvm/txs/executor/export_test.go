@@ -14,6 +14,7 @@ import (
 	"github.com/dim4egster/qmallgo/ids"
 	"github.com/dim4egster/qmallgo/utils/crypto"
 	"github.com/dim4egster/qmallgo/vms/platformvm/state"
+	"github.com/dim4egster/qmallgo/vms/secp256k1fx"
 )
 
 func TestNewExportTx(t *testing.T) {
@@ -66,7 +67,7 @@ func TestNewExportTx(t *testing.T) {
 				defaultBalance-defaultTxFee, // Amount of tokens to export
 				tt.destinationChainID,
 				to,
-				tt.sourceKeys,
+				secp256k1fx.NewKeychain(tt.sourceKeys...),
 				ids.ShortEmpty, // Change address
 			)
 			if tt.shouldErr {
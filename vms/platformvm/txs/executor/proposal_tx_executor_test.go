@@ -38,7 +38,7 @@ func TestProposalTxExecuteAddDelegator(t *testing.T) {
 			newValidatorID,                  // node ID
 			rewardAddress,                   // Reward Address
 			reward.PercentDenominator,       // Shares
-			[]*crypto.PrivateKeySECP256K1R{preFundedKeys[0]},
+			secp256k1fx.NewKeychain(preFundedKeys[0]),
 			ids.ShortEmpty,
 		)
 		if err != nil {
@@ -69,7 +69,7 @@ func TestProposalTxExecuteAddDelegator(t *testing.T) {
 			newValidatorID,                  // node ID
 			rewardAddress,                   // Reward Address
 			reward.PercentDenominator,       // Shared
-			[]*crypto.PrivateKeySECP256K1R{preFundedKeys[0]},
+			secp256k1fx.NewKeychain(preFundedKeys[0]),
 			ids.ShortEmpty,
 		)
 		if err != nil {
@@ -272,7 +272,7 @@ func TestProposalTxExecuteAddDelegator(t *testing.T) {
 				tt.endTime,
 				tt.nodeID,
 				tt.rewardAddress,
-				tt.feeKeys,
+				secp256k1fx.NewKeychain(tt.feeKeys...),
 				ids.ShortEmpty,
 			)
 			if err != nil {
@@ -342,7 +342,7 @@ func TestProposalTxExecuteAddSubnetValidator(t *testing.T) {
 			uint64(defaultValidateEndTime.Unix())+1,
 			ids.NodeID(nodeID),
 			testSubnet1.ID(),
-			[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+			secp256k1fx.NewKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 			ids.ShortEmpty, // change addr
 		)
 		if err != nil {
@@ -382,7 +382,7 @@ func TestProposalTxExecuteAddSubnetValidator(t *testing.T) {
 			uint64(defaultValidateEndTime.Unix()),
 			ids.NodeID(nodeID),
 			testSubnet1.ID(),
-			[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+			secp256k1fx.NewKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 			ids.ShortEmpty, // change addr
 		)
 		if err != nil {
@@ -429,7 +429,7 @@ func TestProposalTxExecuteAddSubnetValidator(t *testing.T) {
 		pendingDSValidatorID,         // node ID
 		nodeID,                       // reward address
 		reward.PercentDenominator,    // shares
-		[]*crypto.PrivateKeySECP256K1R{preFundedKeys[0]},
+		secp256k1fx.NewKeychain(preFundedKeys[0]),
 		ids.ShortEmpty,
 	)
 	if err != nil {
@@ -444,7 +444,7 @@ func TestProposalTxExecuteAddSubnetValidator(t *testing.T) {
 			uint64(DSEndTime.Unix()),
 			pendingDSValidatorID,
 			testSubnet1.ID(),
-			[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+			secp256k1fx.NewKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 			ids.ShortEmpty, // change addr
 		)
 		if err != nil {
@@ -498,7 +498,7 @@ func TestProposalTxExecuteAddSubnetValidator(t *testing.T) {
 			uint64(DSEndTime.Unix()),
 			pendingDSValidatorID,
 			testSubnet1.ID(),
-			[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+			secp256k1fx.NewKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 			ids.ShortEmpty, // change addr
 		)
 		if err != nil {
@@ -536,7 +536,7 @@ func TestProposalTxExecuteAddSubnetValidator(t *testing.T) {
 			uint64(DSEndTime.Unix())+1, // stop validating subnet after stopping validating primary network
 			pendingDSValidatorID,
 			testSubnet1.ID(),
-			[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+			secp256k1fx.NewKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 			ids.ShortEmpty, // change addr
 		)
 		if err != nil {
@@ -574,7 +574,7 @@ func TestProposalTxExecuteAddSubnetValidator(t *testing.T) {
 			uint64(DSEndTime.Unix()),   // same end time as for primary network
 			pendingDSValidatorID,
 			testSubnet1.ID(),
-			[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+			secp256k1fx.NewKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 			ids.ShortEmpty, // change addr
 		)
 		if err != nil {
@@ -615,7 +615,7 @@ func TestProposalTxExecuteAddSubnetValidator(t *testing.T) {
 			uint64(newTimestamp.Add(defaultMinStakingDuration).Unix()), // end time
 			ids.NodeID(nodeID), // node ID
 			testSubnet1.ID(),   // subnet ID
-			[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+			secp256k1fx.NewKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 			ids.ShortEmpty, // change addr
 		)
 		if err != nil {
@@ -655,7 +655,7 @@ func TestProposalTxExecuteAddSubnetValidator(t *testing.T) {
 		uint64(defaultValidateEndTime.Unix()),   // end time
 		ids.NodeID(nodeID),                      // node ID
 		testSubnet1.ID(),                        // subnet ID
-		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+		secp256k1fx.NewKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 		ids.ShortEmpty,
 	)
 	if err != nil {
@@ -683,7 +683,7 @@ func TestProposalTxExecuteAddSubnetValidator(t *testing.T) {
 			uint64(defaultValidateEndTime.Unix()),   // end time
 			ids.NodeID(nodeID),                      // node ID
 			testSubnet1.ID(),                        // subnet ID
-			[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+			secp256k1fx.NewKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 			ids.ShortEmpty, // change addr
 		)
 		if err != nil {
@@ -726,7 +726,7 @@ func TestProposalTxExecuteAddSubnetValidator(t *testing.T) {
 			uint64(defaultGenesisTime.Add(defaultMinStakingDuration).Unix())+1, // end time
 			ids.NodeID(nodeID), // node ID
 			testSubnet1.ID(),   // subnet ID
-			[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1], testSubnet1ControlKeys[2]},
+			secp256k1fx.NewKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1], testSubnet1ControlKeys[2]),
 			ids.ShortEmpty, // change addr
 		)
 		if err != nil {
@@ -763,7 +763,7 @@ func TestProposalTxExecuteAddSubnetValidator(t *testing.T) {
 			uint64(defaultGenesisTime.Add(defaultMinStakingDuration).Unix()), // end time
 			ids.NodeID(nodeID), // node ID
 			testSubnet1.ID(),   // subnet ID
-			[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[2]},
+			secp256k1fx.NewKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[2]),
 			ids.ShortEmpty, // change addr
 		)
 		if err != nil {
@@ -807,7 +807,7 @@ func TestProposalTxExecuteAddSubnetValidator(t *testing.T) {
 			uint64(defaultGenesisTime.Add(defaultMinStakingDuration).Unix()), // end time
 			ids.NodeID(nodeID), // node ID
 			testSubnet1.ID(),   // subnet ID
-			[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], preFundedKeys[1]},
+			secp256k1fx.NewKeychain(testSubnet1ControlKeys[0], preFundedKeys[1]),
 			ids.ShortEmpty, // change addr
 		)
 		if err != nil {
@@ -851,7 +851,7 @@ func TestProposalTxExecuteAddSubnetValidator(t *testing.T) {
 			uint64(defaultGenesisTime.Add(defaultMinStakingDuration).Unix())+1, // end time
 			ids.NodeID(nodeID), // node ID
 			testSubnet1.ID(),   // subnet ID
-			[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+			secp256k1fx.NewKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 			ids.ShortEmpty, // change addr
 		)
 		if err != nil {
@@ -914,7 +914,7 @@ func TestProposalTxExecuteAddValidator(t *testing.T) {
 			nodeID,
 			ids.ShortEmpty,
 			reward.PercentDenominator,
-			[]*crypto.PrivateKeySECP256K1R{preFundedKeys[0]},
+			secp256k1fx.NewKeychain(preFundedKeys[0]),
 			ids.ShortEmpty, // change addr
 		)
 		if err != nil {
@@ -952,7 +952,7 @@ func TestProposalTxExecuteAddValidator(t *testing.T) {
 			nodeID,
 			ids.ShortEmpty,
 			reward.PercentDenominator,
-			[]*crypto.PrivateKeySECP256K1R{preFundedKeys[0]},
+			secp256k1fx.NewKeychain(preFundedKeys[0]),
 			ids.ShortEmpty, // change addr
 		)
 		if err != nil {
@@ -990,7 +990,7 @@ func TestProposalTxExecuteAddValidator(t *testing.T) {
 			nodeID,
 			ids.ShortEmpty,
 			reward.PercentDenominator,
-			[]*crypto.PrivateKeySECP256K1R{preFundedKeys[0]},
+			secp256k1fx.NewKeychain(preFundedKeys[0]),
 			ids.ShortEmpty, // change addr
 		)
 		if err != nil {
@@ -1029,7 +1029,7 @@ func TestProposalTxExecuteAddValidator(t *testing.T) {
 			nodeID,
 			ids.ShortEmpty,
 			reward.PercentDenominator, // shares
-			[]*crypto.PrivateKeySECP256K1R{preFundedKeys[0]},
+			secp256k1fx.NewKeychain(preFundedKeys[0]),
 			ids.ShortEmpty, // change addr
 		)
 		if err != nil {
@@ -1081,7 +1081,7 @@ func TestProposalTxExecuteAddValidator(t *testing.T) {
 			nodeID,
 			ids.ShortEmpty,
 			reward.PercentDenominator,
-			[]*crypto.PrivateKeySECP256K1R{preFundedKeys[0]},
+			secp256k1fx.NewKeychain(preFundedKeys[0]),
 			ids.ShortEmpty, // change addr
 		)
 		if err != nil {
@@ -11,7 +11,6 @@ import (
 
 	"github.com/dim4egster/qmallgo/ids"
 	"github.com/dim4egster/qmallgo/utils/constants"
-	"github.com/dim4egster/qmallgo/utils/crypto"
 	"github.com/dim4egster/qmallgo/utils/math"
 	"github.com/dim4egster/qmallgo/vms/components/avax"
 	"github.com/dim4egster/qmallgo/vms/platformvm/reward"
@@ -259,7 +258,7 @@ func TestRewardDelegatorTxExecuteOnCommit(t *testing.T) {
 		vdrNodeID,        // node ID
 		vdrRewardAddress, // reward address
 		reward.PercentDenominator/4,
-		[]*crypto.PrivateKeySECP256K1R{preFundedKeys[0]},
+		secp256k1fx.NewKeychain(preFundedKeys[0]),
 		ids.ShortEmpty,
 	)
 	require.NoError(err)
@@ -273,7 +272,7 @@ func TestRewardDelegatorTxExecuteOnCommit(t *testing.T) {
 		delEndTime,
 		vdrNodeID,
 		delRewardAddress,
-		[]*crypto.PrivateKeySECP256K1R{preFundedKeys[0]},
+		secp256k1fx.NewKeychain(preFundedKeys[0]),
 		ids.ShortEmpty, // Change address
 	)
 	require.NoError(err)
@@ -392,7 +391,7 @@ func TestRewardDelegatorTxExecuteOnAbort(t *testing.T) {
 		vdrNodeID,        // node ID
 		vdrRewardAddress, // reward address
 		reward.PercentDenominator/4,
-		[]*crypto.PrivateKeySECP256K1R{preFundedKeys[0]},
+		secp256k1fx.NewKeychain(preFundedKeys[0]),
 		ids.ShortEmpty,
 	)
 	require.NoError(err)
@@ -405,7 +404,7 @@ func TestRewardDelegatorTxExecuteOnAbort(t *testing.T) {
 		delEndTime,
 		vdrNodeID,
 		delRewardAddress,
-		[]*crypto.PrivateKeySECP256K1R{preFundedKeys[0]},
+		secp256k1fx.NewKeychain(preFundedKeys[0]),
 		ids.ShortEmpty,
 	)
 	require.NoError(err)
@@ -77,7 +77,7 @@ func TestStandardTxExecutorAddValidatorTxEmptyID(t *testing.T) {
 			ids.EmptyNodeID,
 			ids.GenerateTestShortID(),
 			reward.PercentDenominator,
-			[]*crypto.PrivateKeySECP256K1R{preFundedKeys[0]},
+			secp256k1fx.NewKeychain(preFundedKeys[0]),
 			ids.ShortEmpty, // change addr
 		)
 		if err != nil {
@@ -118,7 +118,7 @@ func TestStandardTxExecutorAddDelegator(t *testing.T) {
 			newValidatorID,                  // node ID
 			rewardAddress,                   // Reward Address
 			reward.PercentDenominator,       // Shares
-			[]*crypto.PrivateKeySECP256K1R{preFundedKeys[0]},
+			secp256k1fx.NewKeychain(preFundedKeys[0]),
 			ids.ShortEmpty,
 		)
 		if err != nil {
@@ -149,7 +149,7 @@ func TestStandardTxExecutorAddDelegator(t *testing.T) {
 			newValidatorID,                  // node ID
 			rewardAddress,                   // Reward Address
 			reward.PercentDenominator,       // Shared
-			[]*crypto.PrivateKeySECP256K1R{preFundedKeys[0]},
+			secp256k1fx.NewKeychain(preFundedKeys[0]),
 			ids.ShortEmpty,
 		)
 		if err != nil {
@@ -352,7 +352,7 @@ func TestStandardTxExecutorAddDelegator(t *testing.T) {
 				tt.endTime,
 				tt.nodeID,
 				tt.rewardAddress,
-				tt.feeKeys,
+				secp256k1fx.NewKeychain(tt.feeKeys...),
 				ids.ShortEmpty,
 			)
 			if err != nil {
@@ -419,7 +419,7 @@ func TestStandardTxExecutorAddSubnetValidator(t *testing.T) {
 			uint64(defaultValidateEndTime.Unix())+1,
 			ids.NodeID(nodeID),
 			testSubnet1.ID(),
-			[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+			secp256k1fx.NewKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 			ids.ShortEmpty, // change addr
 		)
 		if err != nil {
@@ -453,7 +453,7 @@ func TestStandardTxExecutorAddSubnetValidator(t *testing.T) {
 			uint64(defaultValidateEndTime.Unix()),
 			ids.NodeID(nodeID),
 			testSubnet1.ID(),
-			[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+			secp256k1fx.NewKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 			ids.ShortEmpty, // change addr
 		)
 		if err != nil {
@@ -494,7 +494,7 @@ func TestStandardTxExecutorAddSubnetValidator(t *testing.T) {
 		pendingDSValidatorID,         // node ID
 		nodeID,                       // reward address
 		reward.PercentDenominator,    // shares
-		[]*crypto.PrivateKeySECP256K1R{preFundedKeys[0]},
+		secp256k1fx.NewKeychain(preFundedKeys[0]),
 		ids.ShortEmpty,
 	)
 	if err != nil {
@@ -509,7 +509,7 @@ func TestStandardTxExecutorAddSubnetValidator(t *testing.T) {
 			uint64(DSEndTime.Unix()),
 			pendingDSValidatorID,
 			testSubnet1.ID(),
-			[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+			secp256k1fx.NewKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 			ids.ShortEmpty, // change addr
 		)
 		if err != nil {
@@ -557,7 +557,7 @@ func TestStandardTxExecutorAddSubnetValidator(t *testing.T) {
 			uint64(DSEndTime.Unix()),
 			pendingDSValidatorID,
 			testSubnet1.ID(),
-			[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+			secp256k1fx.NewKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 			ids.ShortEmpty, // change addr
 		)
 		if err != nil {
@@ -589,7 +589,7 @@ func TestStandardTxExecutorAddSubnetValidator(t *testing.T) {
 			uint64(DSEndTime.Unix())+1, // stop validating subnet after stopping validating primary network
 			pendingDSValidatorID,
 			testSubnet1.ID(),
-			[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+			secp256k1fx.NewKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 			ids.ShortEmpty, // change addr
 		)
 		if err != nil {
@@ -621,7 +621,7 @@ func TestStandardTxExecutorAddSubnetValidator(t *testing.T) {
 			uint64(DSEndTime.Unix()),   // same end time as for primary network
 			pendingDSValidatorID,
 			testSubnet1.ID(),
-			[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+			secp256k1fx.NewKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 			ids.ShortEmpty, // change addr
 		)
 		if err != nil {
@@ -656,7 +656,7 @@ func TestStandardTxExecutorAddSubnetValidator(t *testing.T) {
 			uint64(newTimestamp.Add(defaultMinStakingDuration).Unix()), // end time
 			ids.NodeID(nodeID), // node ID
 			testSubnet1.ID(),   // subnet ID
-			[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+			secp256k1fx.NewKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 			ids.ShortEmpty, // change addr
 		)
 		if err != nil {
@@ -690,7 +690,7 @@ func TestStandardTxExecutorAddSubnetValidator(t *testing.T) {
 		uint64(defaultValidateEndTime.Unix()),   // end time
 		ids.NodeID(nodeID),                      // node ID
 		testSubnet1.ID(),                        // subnet ID
-		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+		secp256k1fx.NewKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 		ids.ShortEmpty,
 	)
 	if err != nil {
@@ -718,7 +718,7 @@ func TestStandardTxExecutorAddSubnetValidator(t *testing.T) {
 			uint64(defaultValidateEndTime.Unix()),   // end time
 			ids.NodeID(nodeID),                      // node ID
 			testSubnet1.ID(),                        // subnet ID
-			[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+			secp256k1fx.NewKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 			ids.ShortEmpty, // change addr
 		)
 		if err != nil {
@@ -755,7 +755,7 @@ func TestStandardTxExecutorAddSubnetValidator(t *testing.T) {
 			uint64(defaultGenesisTime.Add(defaultMinStakingDuration).Unix())+1, // end time
 			ids.NodeID(nodeID), // node ID
 			testSubnet1.ID(),   // subnet ID
-			[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1], testSubnet1ControlKeys[2]},
+			secp256k1fx.NewKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1], testSubnet1ControlKeys[2]),
 			ids.ShortEmpty, // change addr
 		)
 		if err != nil {
@@ -786,7 +786,7 @@ func TestStandardTxExecutorAddSubnetValidator(t *testing.T) {
 			uint64(defaultGenesisTime.Add(defaultMinStakingDuration).Unix()), // end time
 			ids.NodeID(nodeID), // node ID
 			testSubnet1.ID(),   // subnet ID
-			[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[2]},
+			secp256k1fx.NewKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[2]),
 			ids.ShortEmpty, // change addr
 		)
 		if err != nil {
@@ -824,7 +824,7 @@ func TestStandardTxExecutorAddSubnetValidator(t *testing.T) {
 			uint64(defaultGenesisTime.Add(defaultMinStakingDuration).Unix()), // end time
 			ids.NodeID(nodeID), // node ID
 			testSubnet1.ID(),   // subnet ID
-			[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], preFundedKeys[1]},
+			secp256k1fx.NewKeychain(testSubnet1ControlKeys[0], preFundedKeys[1]),
 			ids.ShortEmpty, // change addr
 		)
 		if err != nil {
@@ -862,7 +862,7 @@ func TestStandardTxExecutorAddSubnetValidator(t *testing.T) {
 			uint64(defaultGenesisTime.Add(defaultMinStakingDuration).Unix())+1, // end time
 			ids.NodeID(nodeID), // node ID
 			testSubnet1.ID(),   // subnet ID
-			[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+			secp256k1fx.NewKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 			ids.ShortEmpty, // change addr
 		)
 		if err != nil {
@@ -921,7 +921,7 @@ func TestStandardTxExecutorAddValidator(t *testing.T) {
 			nodeID,
 			ids.ShortEmpty,
 			reward.PercentDenominator,
-			[]*crypto.PrivateKeySECP256K1R{preFundedKeys[0]},
+			secp256k1fx.NewKeychain(preFundedKeys[0]),
 			ids.ShortEmpty, // change addr
 		)
 		if err != nil {
@@ -953,7 +953,7 @@ func TestStandardTxExecutorAddValidator(t *testing.T) {
 			nodeID,
 			ids.ShortEmpty,
 			reward.PercentDenominator,
-			[]*crypto.PrivateKeySECP256K1R{preFundedKeys[0]},
+			secp256k1fx.NewKeychain(preFundedKeys[0]),
 			ids.ShortEmpty, // change addr
 		)
 		if err != nil {
@@ -985,7 +985,7 @@ func TestStandardTxExecutorAddValidator(t *testing.T) {
 			nodeID,
 			ids.ShortEmpty,
 			reward.PercentDenominator,
-			[]*crypto.PrivateKeySECP256K1R{preFundedKeys[0]},
+			secp256k1fx.NewKeychain(preFundedKeys[0]),
 			ids.ShortEmpty, // change addr
 		)
 		if err != nil {
@@ -1018,7 +1018,7 @@ func TestStandardTxExecutorAddValidator(t *testing.T) {
 			nodeID,
 			ids.ShortEmpty,
 			reward.PercentDenominator, // shares
-			[]*crypto.PrivateKeySECP256K1R{preFundedKeys[0]},
+			secp256k1fx.NewKeychain(preFundedKeys[0]),
 			ids.ShortEmpty, // change addr // key
 		)
 		if err != nil {
@@ -1064,7 +1064,7 @@ func TestStandardTxExecutorAddValidator(t *testing.T) {
 			nodeID,
 			ids.ShortEmpty,
 			reward.PercentDenominator,
-			[]*crypto.PrivateKeySECP256K1R{preFundedKeys[0]},
+			secp256k1fx.NewKeychain(preFundedKeys[0]),
 			ids.ShortEmpty, // change addr
 		)
 		if err != nil {
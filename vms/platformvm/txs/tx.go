@@ -11,6 +11,7 @@ import (
 	"github.com/dim4egster/qmallgo/ids"
 	"github.com/dim4egster/qmallgo/snow"
 	"github.com/dim4egster/qmallgo/utils/crypto"
+	"github.com/dim4egster/qmallgo/utils/crypto/keychain"
 	"github.com/dim4egster/qmallgo/utils/hashing"
 	"github.com/dim4egster/qmallgo/vms/components/avax"
 	"github.com/dim4egster/qmallgo/vms/components/verify"
@@ -38,7 +39,7 @@ type Tx struct {
 func NewSigned(
 	unsigned UnsignedTx,
 	c codec.Manager,
-	signers [][]*crypto.PrivateKeySECP256K1R,
+	signers [][]keychain.Signer,
 ) (*Tx, error) {
 	res := &Tx{Unsigned: unsigned}
 	return res, res.Sign(c, signers)
@@ -97,7 +98,7 @@ func (tx *Tx) SyntacticVerify(ctx *snow.Context) error {
 }
 
 // Sign this transaction with the provided signers
-func (tx *Tx) Sign(c codec.Manager, signers [][]*crypto.PrivateKeySECP256K1R) error {
+func (tx *Tx) Sign(c codec.Manager, signers [][]keychain.Signer) error {
 	unsignedBytes, err := c.Marshal(Version, &tx.Unsigned)
 	if err != nil {
 		return fmt.Errorf("couldn't marshal UnsignedTx: %w", err)
@@ -11,7 +11,7 @@ import (
 
 	"github.com/dim4egster/qmallgo/ids"
 	"github.com/dim4egster/qmallgo/snow"
-	"github.com/dim4egster/qmallgo/utils/crypto"
+	"github.com/dim4egster/qmallgo/utils/crypto/keychain"
 	"github.com/dim4egster/qmallgo/utils/hashing"
 	"github.com/dim4egster/qmallgo/utils/math"
 	"github.com/dim4egster/qmallgo/utils/timer/mockable"
@@ -63,7 +63,7 @@ func Produce(
 type Spender interface {
 	// Spend the provided amount while deducting the provided fee.
 	// Arguments:
-	// - [keys] are the owners of the funds
+	// - [kc] signs for the owners of the funds
 	// - [amount] is the amount of funds that are trying to be staked
 	// - [fee] is the amount of QMALL that should be burned
 	// - [changeAddr] is the address that change, if there is any, is sent to
@@ -75,7 +75,7 @@ type Spender interface {
 	//                   the staking period
 	// - [signers] the proof of ownership of the funds being moved
 	Spend(
-		keys []*crypto.PrivateKeySECP256K1R,
+		kc keychain.Keychain,
 		amount uint64,
 		fee uint64,
 		changeAddr ids.ShortID,
@@ -83,19 +83,19 @@ type Spender interface {
 		[]*avax.TransferableInput, // inputs
 		[]*avax.TransferableOutput, // returnedOutputs
 		[]*avax.TransferableOutput, // stakedOutputs
-		[][]*crypto.PrivateKeySECP256K1R, // signers
+		[][]keychain.Signer, // signers
 		error,
 	)
 
 	// Authorize an operation on behalf of the named subnet with the provided
-	// keys.
+	// keychain.
 	Authorize(
 		state state.Chain,
 		subnetID ids.ID,
-		keys []*crypto.PrivateKeySECP256K1R,
+		kc keychain.Keychain,
 	) (
 		verify.Verifiable, // Input that names owners
-		[]*crypto.PrivateKeySECP256K1R, // Keys that prove ownership
+		[]keychain.Signer, // Signers that prove ownership
 		error,
 	)
 }
@@ -168,7 +168,7 @@ type handler struct {
 }
 
 func (h *handler) Spend(
-	keys []*crypto.PrivateKeySECP256K1R,
+	kc keychain.Keychain,
 	amount uint64,
 	fee uint64,
 	changeAddr ids.ShortID,
@@ -176,27 +176,22 @@ func (h *handler) Spend(
 	[]*avax.TransferableInput, // inputs
 	[]*avax.TransferableOutput, // returnedOutputs
 	[]*avax.TransferableOutput, // stakedOutputs
-	[][]*crypto.PrivateKeySECP256K1R, // signers
+	[][]keychain.Signer, // signers
 	error,
 ) {
-	addrs := ids.NewShortSet(len(keys)) // The addresses controlled by [keys]
-	for _, key := range keys {
-		addrs.Add(key.PublicKey().Address())
-	}
-	utxos, err := avax.GetAllUTXOs(h.utxosReader, addrs) // The UTXOs controlled by [keys]
+	addrs := kc.Addresses()                              // The addresses controlled by [kc]
+	utxos, err := avax.GetAllUTXOs(h.utxosReader, addrs) // The UTXOs controlled by [kc]
 	if err != nil {
 		return nil, nil, nil, nil, fmt.Errorf("couldn't get UTXOs: %w", err)
 	}
 
-	kc := secp256k1fx.NewKeychain(keys...) // Keychain consumes UTXOs and creates new ones
-
 	// Minimum time this transaction will be issued at
 	now := uint64(h.clk.Time().Unix())
 
 	ins := []*avax.TransferableInput{}
 	returnedOuts := []*avax.TransferableOutput{}
 	stakedOuts := []*avax.TransferableOutput{}
-	signers := [][]*crypto.PrivateKeySECP256K1R{}
+	signers := [][]keychain.Signer{}
 
 	// Amount of QMALL that has been staked
 	amountStaked := uint64(0)
@@ -231,7 +226,7 @@ func (h *handler) Spend(
 			continue
 		}
 
-		inIntf, inSigners, err := kc.Spend(out.TransferableOut, now)
+		inIntf, inSigners, err := secp256k1fx.Spend(kc, out.TransferableOut, now)
 		if err != nil {
 			// We couldn't spend the output, so move on to the next one
 			continue
@@ -324,7 +319,7 @@ func (h *handler) Spend(
 			out = inner.TransferableOut
 		}
 
-		inIntf, inSigners, err := kc.Spend(out, now)
+		inIntf, inSigners, err := secp256k1fx.Spend(kc, out, now)
 		if err != nil {
 			// We couldn't spend this UTXO, so we skip to the next one
 			continue
@@ -412,10 +407,10 @@ func (h *handler) Spend(
 func (h *handler) Authorize(
 	state state.Chain,
 	subnetID ids.ID,
-	keys []*crypto.PrivateKeySECP256K1R,
+	kc keychain.Keychain,
 ) (
 	verify.Verifiable, // Input that names owners
-	[]*crypto.PrivateKeySECP256K1R, // Keys that prove ownership
+	[]keychain.Signer, // Signers that prove ownership
 	error,
 ) {
 	subnetTx, _, err := state.GetTx(subnetID)
@@ -431,20 +426,17 @@ func (h *handler) Authorize(
 		return nil, nil, fmt.Errorf("expected tx type *txs.CreateSubnetTx but got %T", subnetTx.Unsigned)
 	}
 
-	// Make sure the owners of the subnet match the provided keys
+	// Make sure the owners of the subnet match the provided keychain
 	owner, ok := subnet.Owner.(*secp256k1fx.OutputOwners)
 	if !ok {
 		return nil, nil, fmt.Errorf("expected *secp256k1fx.OutputOwners but got %T", subnet.Owner)
 	}
 
-	// Add the keys to a keychain
-	kc := secp256k1fx.NewKeychain(keys...)
-
 	// Make sure that the operation is valid after a minimum time
 	now := uint64(h.clk.Time().Unix())
 
 	// Attempt to prove ownership of the subnet
-	indices, signers, matches := kc.Match(owner, now)
+	indices, signers, matches := secp256k1fx.Match(kc, owner, now)
 	if !matches {
 		return nil, nil, errCantSign
 	}
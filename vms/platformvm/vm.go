@@ -20,6 +20,7 @@ import (
 	"github.com/dim4egster/qmallgo/database"
 	"github.com/dim4egster/qmallgo/database/manager"
 	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/pubsub"
 	"github.com/dim4egster/qmallgo/snow"
 	"github.com/dim4egster/qmallgo/snow/consensus/snowman"
 	"github.com/dim4egster/qmallgo/snow/engine/common"
@@ -28,6 +29,7 @@ import (
 	"github.com/dim4egster/qmallgo/snow/validators"
 	"github.com/dim4egster/qmallgo/utils"
 	"github.com/dim4egster/qmallgo/utils/constants"
+	"github.com/dim4egster/qmallgo/utils/crypto/bls"
 	"github.com/dim4egster/qmallgo/utils/json"
 	"github.com/dim4egster/qmallgo/utils/logging"
 	"github.com/dim4egster/qmallgo/utils/math"
@@ -103,6 +105,9 @@ type VM struct {
 	txBuilder         txbuilder.Builder
 	txExecutorBackend *txexecutor.Backend
 	manager           blockexecutor.Manager
+
+	// pubsub notifies websocket subscribers of accepted txs
+	pubsub *pubsub.Server
 }
 
 // Initialize this blockchain.
@@ -189,6 +194,8 @@ func (vm *VM) Initialize(
 		Bootstrapped: &vm.bootstrapped,
 	}
 
+	vm.pubsub = pubsub.New(ctx.NetworkID, ctx.Log)
+
 	// Note: There is a circular dependency between the mempool and block
 	//       builder which is broken by passing in the vm.
 	mempool, err := mempool.NewMempool("mempool", registerer, vm)
@@ -202,6 +209,7 @@ func (vm *VM) Initialize(
 		vm.state,
 		vm.txExecutorBackend,
 		vm.recentlyAccepted,
+		vm.pubsub,
 	)
 	vm.Builder = blockbuilder.New(
 		mempool,
@@ -276,6 +284,9 @@ func (vm *VM) createSubnet(subnetID ids.ID) error {
 // onBootstrapStarted marks this VM as bootstrapping
 func (vm *VM) onBootstrapStarted() error {
 	vm.bootstrapped.SetValue(false)
+	if vm.Services != nil {
+		vm.Services.remove(vm.ctx.ChainID)
+	}
 	return vm.fx.Bootstrapping()
 }
 
@@ -310,6 +321,13 @@ func (vm *VM) onNormalOperationsStarted() error {
 
 	// Start the block builder
 	vm.Builder.ResetBlockTimer()
+
+	if vm.Services != nil {
+		vm.Services.add(vm.ctx.ChainID, &Service{
+			vm:          vm,
+			addrManager: avax.NewAddressManager(vm.ctx),
+		})
+	}
 	return nil
 }
 
@@ -332,6 +350,10 @@ func (vm *VM) Shutdown() error {
 
 	vm.Builder.Shutdown()
 
+	if vm.Services != nil {
+		vm.Services.remove(vm.ctx.ChainID)
+	}
+
 	if vm.bootstrapped.GetValue() {
 		primaryValidatorSet, exist := vm.Validators.GetValidators(constants.PrimaryNetworkID)
 		if !exist {
@@ -412,6 +434,10 @@ func (vm *VM) CreateHandlers() (map[string]*common.HTTPHandler, error) {
 		"": {
 			Handler: server,
 		},
+		"/events": {
+			LockOptions: common.NoLock,
+			Handler:     vm.pubsub,
+		},
 	}, nil
 }
 
@@ -447,7 +473,7 @@ func (vm *VM) Disconnected(vdrID ids.NodeID) error {
 
 // GetValidatorSet returns the validator set at the specified height for the
 // provided subnetID.
-func (vm *VM) GetValidatorSet(height uint64, subnetID ids.ID) (map[ids.NodeID]uint64, error) {
+func (vm *VM) GetValidatorSet(height uint64, subnetID ids.ID) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
 	validatorSetsCache, exists := vm.validatorSetCaches[subnetID]
 	if !exists {
 		validatorSetsCache = &cache.LRU{Size: validatorSetsCacheSize}
@@ -458,7 +484,7 @@ func (vm *VM) GetValidatorSet(height uint64, subnetID ids.ID) (map[ids.NodeID]ui
 	}
 
 	if validatorSetIntf, ok := validatorSetsCache.Get(height); ok {
-		validatorSet, ok := validatorSetIntf.(map[ids.NodeID]uint64)
+		validatorSet, ok := validatorSetIntf.(map[ids.NodeID]*validators.GetValidatorOutput)
 		if !ok {
 			return nil, errWrongCacheType
 		}
@@ -483,11 +509,18 @@ func (vm *VM) GetValidatorSet(height uint64, subnetID ids.ID) (map[ids.NodeID]ui
 	}
 	currentValidatorList := currentValidators.List()
 
-	vdrSet := make(map[ids.NodeID]uint64, len(currentValidatorList))
+	vdrSet := make(map[ids.NodeID]*validators.GetValidatorOutput, len(currentValidatorList))
 	for _, vdr := range currentValidatorList {
-		vdrSet[vdr.ID()] = vdr.Weight()
+		vdrSet[vdr.ID()] = &validators.GetValidatorOutput{
+			NodeID:    vdr.ID(),
+			PublicKey: vdr.PublicKey(),
+			Weight:    vdr.Weight(),
+		}
 	}
 
+	// Validators whose weight changed after [height] may have registered a
+	// different public key, if any, at [height].
+	changedVdrs := ids.NodeIDSet{}
 	for i := lastAcceptedHeight; i > height; i-- {
 		diffs, err := vm.state.GetValidatorWeightDiffs(i, subnetID)
 		if err != nil {
@@ -495,6 +528,16 @@ func (vm *VM) GetValidatorSet(height uint64, subnetID ids.ID) (map[ids.NodeID]ui
 		}
 
 		for nodeID, diff := range diffs {
+			changedVdrs.Add(nodeID)
+
+			vdr, ok := vdrSet[nodeID]
+			if !ok {
+				vdr = &validators.GetValidatorOutput{
+					NodeID: nodeID,
+				}
+				vdrSet[nodeID] = vdr
+			}
+
 			var op func(uint64, uint64) (uint64, error)
 			if diff.Decrease {
 				// The validator's weight was decreased at this block, so in the
@@ -506,18 +549,29 @@ func (vm *VM) GetValidatorSet(height uint64, subnetID ids.ID) (map[ids.NodeID]ui
 				op = math.Sub64
 			}
 
-			newWeight, err := op(vdrSet[nodeID], diff.Amount)
+			newWeight, err := op(vdr.Weight, diff.Amount)
 			if err != nil {
 				return nil, err
 			}
 			if newWeight == 0 {
 				delete(vdrSet, nodeID)
 			} else {
-				vdrSet[nodeID] = newWeight
+				vdr.Weight = newWeight
 			}
 		}
 	}
 
+	for nodeID := range changedVdrs {
+		vdr, ok := vdrSet[nodeID]
+		if !ok {
+			continue
+		}
+		vdr.PublicKey, err = vm.getPublicKey(nodeID, height)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// cache the validator set
 	validatorSetsCache.Put(height, vdrSet)
 
@@ -528,6 +582,25 @@ func (vm *VM) GetValidatorSet(height uint64, subnetID ids.ID) (map[ids.NodeID]ui
 	return vdrSet, nil
 }
 
+// getPublicKey returns the BLS public key that [nodeID] registered on the
+// primary network for the staking period that includes [height], or nil if it
+// didn't register one.
+func (vm *VM) getPublicKey(nodeID ids.NodeID, height uint64) (*bls.PublicKey, error) {
+	history, err := vm.state.GetValidatorHistory(nodeID)
+	if err != nil {
+		return nil, err
+	}
+	for _, period := range history {
+		if period.SubnetID != constants.PrimaryNetworkID || len(period.PublicKey) == 0 {
+			continue
+		}
+		if period.StartHeight <= height && (period.EndHeight == 0 || height < period.EndHeight) {
+			return bls.PublicKeyFromBytes(period.PublicKey)
+		}
+	}
+	return nil, nil
+}
+
 // GetMinimumHeight returns the height of the most recent block beyond the
 // horizon of our recentlyAccepted window.
 //
@@ -23,7 +23,7 @@ import (
 	"github.com/dim4egster/qmallgo/snow/uptime"
 	"github.com/dim4egster/qmallgo/snow/validators"
 	"github.com/dim4egster/qmallgo/utils/constants"
-	"github.com/dim4egster/qmallgo/utils/crypto"
+	"github.com/dim4egster/qmallgo/utils/crypto/keychain"
 	"github.com/dim4egster/qmallgo/utils/timer/mockable"
 	"github.com/dim4egster/qmallgo/version"
 	"github.com/dim4egster/qmallgo/vms/components/avax"
@@ -62,7 +62,7 @@ func TestAddDelegatorTxOverDelegatedRegression(t *testing.T) {
 		nodeID,
 		changeAddr,
 		reward.PercentDenominator,
-		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		secp256k1fx.NewKeychain(keys[0]),
 		changeAddr,
 	)
 	require.NoError(err)
@@ -92,7 +92,7 @@ func TestAddDelegatorTxOverDelegatedRegression(t *testing.T) {
 		uint64(firstDelegatorEndTime.Unix()),
 		nodeID,
 		changeAddr,
-		[]*crypto.PrivateKeySECP256K1R{keys[0], keys[1]},
+		secp256k1fx.NewKeychain(keys[0], keys[1]),
 		changeAddr,
 	)
 	require.NoError(err)
@@ -124,7 +124,7 @@ func TestAddDelegatorTxOverDelegatedRegression(t *testing.T) {
 		uint64(secondDelegatorEndTime.Unix()),
 		nodeID,
 		changeAddr,
-		[]*crypto.PrivateKeySECP256K1R{keys[0], keys[1], keys[3]},
+		secp256k1fx.NewKeychain(keys[0], keys[1], keys[3]),
 		changeAddr,
 	)
 	require.NoError(err)
@@ -147,7 +147,7 @@ func TestAddDelegatorTxOverDelegatedRegression(t *testing.T) {
 		uint64(thirdDelegatorEndTime.Unix()),
 		nodeID,
 		changeAddr,
-		[]*crypto.PrivateKeySECP256K1R{keys[0], keys[1], keys[4]},
+		secp256k1fx.NewKeychain(keys[0], keys[1], keys[4]),
 		changeAddr,
 	)
 	require.NoError(err)
@@ -224,7 +224,7 @@ func TestAddDelegatorTxHeapCorruption(t *testing.T) {
 				ids.NodeID(id),
 				id,
 				reward.PercentDenominator,
-				[]*crypto.PrivateKeySECP256K1R{keys[0], keys[1]},
+				secp256k1fx.NewKeychain(keys[0], keys[1]),
 				changeAddr,
 			)
 			require.NoError(err)
@@ -246,7 +246,7 @@ func TestAddDelegatorTxHeapCorruption(t *testing.T) {
 				uint64(delegator1EndTime.Unix()),
 				ids.NodeID(id),
 				keys[0].PublicKey().Address(),
-				[]*crypto.PrivateKeySECP256K1R{keys[0], keys[1]},
+				secp256k1fx.NewKeychain(keys[0], keys[1]),
 				changeAddr,
 			)
 			require.NoError(err)
@@ -268,7 +268,7 @@ func TestAddDelegatorTxHeapCorruption(t *testing.T) {
 				uint64(delegator2EndTime.Unix()),
 				ids.NodeID(id),
 				keys[0].PublicKey().Address(),
-				[]*crypto.PrivateKeySECP256K1R{keys[0], keys[1]},
+				secp256k1fx.NewKeychain(keys[0], keys[1]),
 				changeAddr,
 			)
 			require.NoError(err)
@@ -290,7 +290,7 @@ func TestAddDelegatorTxHeapCorruption(t *testing.T) {
 				uint64(delegator3EndTime.Unix()),
 				ids.NodeID(id),
 				keys[0].PublicKey().Address(),
-				[]*crypto.PrivateKeySECP256K1R{keys[0], keys[1]},
+				secp256k1fx.NewKeychain(keys[0], keys[1]),
 				changeAddr,
 			)
 			require.NoError(err)
@@ -312,7 +312,7 @@ func TestAddDelegatorTxHeapCorruption(t *testing.T) {
 				uint64(delegator4EndTime.Unix()),
 				ids.NodeID(id),
 				keys[0].PublicKey().Address(),
-				[]*crypto.PrivateKeySECP256K1R{keys[0], keys[1]},
+				secp256k1fx.NewKeychain(keys[0], keys[1]),
 				changeAddr,
 			)
 			require.NoError(err)
@@ -382,7 +382,7 @@ func TestUnverifiedParentPanicRegression(t *testing.T) {
 	addSubnetTx0, err := vm.txBuilder.NewCreateSubnetTx(
 		1,
 		[]ids.ShortID{addr0},
-		[]*crypto.PrivateKeySECP256K1R{key0},
+		secp256k1fx.NewKeychain(key0),
 		addr0,
 	)
 	if err != nil {
@@ -392,7 +392,7 @@ func TestUnverifiedParentPanicRegression(t *testing.T) {
 	addSubnetTx1, err := vm.txBuilder.NewCreateSubnetTx(
 		1,
 		[]ids.ShortID{addr1},
-		[]*crypto.PrivateKeySECP256K1R{key1},
+		secp256k1fx.NewKeychain(key1),
 		addr1,
 	)
 	if err != nil {
@@ -402,7 +402,7 @@ func TestUnverifiedParentPanicRegression(t *testing.T) {
 	addSubnetTx2, err := vm.txBuilder.NewCreateSubnetTx(
 		1,
 		[]ids.ShortID{addr1},
-		[]*crypto.PrivateKeySECP256K1R{key1},
+		secp256k1fx.NewKeychain(key1),
 		addr0,
 	)
 	if err != nil {
@@ -494,7 +494,7 @@ func TestRejectedStateRegressionInvalidValidatorTimestamp(t *testing.T) {
 		nodeID,
 		ids.ShortID(nodeID),
 		reward.PercentDenominator,
-		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		secp256k1fx.NewKeychain(keys[0]),
 		ids.ShortEmpty,
 	)
 	require.NoError(err)
@@ -570,7 +570,7 @@ func TestRejectedStateRegressionInvalidValidatorTimestamp(t *testing.T) {
 		},
 	}
 	signedImportTx := &txs.Tx{Unsigned: unsignedImportTx}
-	err = signedImportTx.Sign(txs.Codec, [][]*crypto.PrivateKeySECP256K1R{
+	err = signedImportTx.Sign(txs.Codec, [][]keychain.Signer{
 		{}, // There is one input, with no required signers
 	})
 	require.NoError(err)
@@ -738,7 +738,7 @@ func TestRejectedStateRegressionInvalidValidatorReward(t *testing.T) {
 		nodeID0,
 		ids.ShortID(nodeID0),
 		reward.PercentDenominator,
-		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		secp256k1fx.NewKeychain(keys[0]),
 		ids.ShortEmpty,
 	)
 	require.NoError(err)
@@ -868,7 +868,7 @@ func TestRejectedStateRegressionInvalidValidatorReward(t *testing.T) {
 		},
 	}
 	signedImportTx := &txs.Tx{Unsigned: unsignedImportTx}
-	err = signedImportTx.Sign(txs.Codec, [][]*crypto.PrivateKeySECP256K1R{
+	err = signedImportTx.Sign(txs.Codec, [][]keychain.Signer{
 		{}, // There is one input, with no required signers
 	})
 	require.NoError(err)
@@ -942,7 +942,7 @@ func TestRejectedStateRegressionInvalidValidatorReward(t *testing.T) {
 		nodeID1,
 		ids.ShortID(nodeID1),
 		reward.PercentDenominator,
-		[]*crypto.PrivateKeySECP256K1R{keys[1]},
+		secp256k1fx.NewKeychain(keys[1]),
 		ids.ShortEmpty,
 	)
 	require.NoError(err)
@@ -1115,16 +1115,16 @@ func TestValidatorSetAtCacheOverwriteRegression(t *testing.T) {
 	require.NoError(err)
 	require.EqualValues(1, currentHeight)
 
-	expectedValidators1 := map[ids.NodeID]uint64{
-		nodeID0: defaultWeight,
-		nodeID1: defaultWeight,
-		nodeID2: defaultWeight,
-		nodeID3: defaultWeight,
-		nodeID4: defaultWeight,
+	expectedValidators1 := map[ids.NodeID]*validators.GetValidatorOutput{
+		nodeID0: {NodeID: nodeID0, Weight: defaultWeight},
+		nodeID1: {NodeID: nodeID1, Weight: defaultWeight},
+		nodeID2: {NodeID: nodeID2, Weight: defaultWeight},
+		nodeID3: {NodeID: nodeID3, Weight: defaultWeight},
+		nodeID4: {NodeID: nodeID4, Weight: defaultWeight},
 	}
-	validators, err := vm.GetValidatorSet(1, constants.PrimaryNetworkID)
+	vdrs, err := vm.GetValidatorSet(1, constants.PrimaryNetworkID)
 	require.NoError(err)
-	require.Equal(expectedValidators1, validators)
+	require.Equal(expectedValidators1, vdrs)
 
 	newValidatorStartTime0 := defaultGenesisTime.Add(txexecutor.SyncBound).Add(1 * time.Second)
 	newValidatorEndTime0 := newValidatorStartTime0.Add(defaultMaxStakingDuration)
@@ -1139,7 +1139,7 @@ func TestValidatorSetAtCacheOverwriteRegression(t *testing.T) {
 		nodeID5,
 		ids.GenerateTestShortID(),
 		reward.PercentDenominator,
-		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		secp256k1fx.NewKeychain(keys[0]),
 		ids.GenerateTestShortID(),
 	)
 	require.NoError(err)
@@ -1166,9 +1166,9 @@ func TestValidatorSetAtCacheOverwriteRegression(t *testing.T) {
 	require.EqualValues(3, currentHeight)
 
 	for i := uint64(1); i <= 3; i++ {
-		validators, err = vm.GetValidatorSet(i, constants.PrimaryNetworkID)
+		vdrs, err = vm.GetValidatorSet(i, constants.PrimaryNetworkID)
 		require.NoError(err)
-		require.Equal(expectedValidators1, validators)
+		require.Equal(expectedValidators1, vdrs)
 	}
 
 	// Create the tx that moves the first new validator from the pending
@@ -1200,22 +1200,22 @@ func TestValidatorSetAtCacheOverwriteRegression(t *testing.T) {
 	require.EqualValues(5, currentHeight)
 
 	for i := uint64(1); i <= 4; i++ {
-		validators, err = vm.GetValidatorSet(i, constants.PrimaryNetworkID)
+		vdrs, err = vm.GetValidatorSet(i, constants.PrimaryNetworkID)
 		require.NoError(err)
-		require.Equal(expectedValidators1, validators)
+		require.Equal(expectedValidators1, vdrs)
 	}
 
-	expectedValidators2 := map[ids.NodeID]uint64{
-		nodeID0: defaultWeight,
-		nodeID1: defaultWeight,
-		nodeID2: defaultWeight,
-		nodeID3: defaultWeight,
-		nodeID4: defaultWeight,
-		nodeID5: vm.MaxValidatorStake,
+	expectedValidators2 := map[ids.NodeID]*validators.GetValidatorOutput{
+		nodeID0: {NodeID: nodeID0, Weight: defaultWeight},
+		nodeID1: {NodeID: nodeID1, Weight: defaultWeight},
+		nodeID2: {NodeID: nodeID2, Weight: defaultWeight},
+		nodeID3: {NodeID: nodeID3, Weight: defaultWeight},
+		nodeID4: {NodeID: nodeID4, Weight: defaultWeight},
+		nodeID5: {NodeID: nodeID5, Weight: vm.MaxValidatorStake},
 	}
-	validators, err = vm.GetValidatorSet(5, constants.PrimaryNetworkID)
+	vdrs, err = vm.GetValidatorSet(5, constants.PrimaryNetworkID)
 	require.NoError(err)
-	require.Equal(expectedValidators2, validators)
+	require.Equal(expectedValidators2, vdrs)
 }
 
 func TestAddDelegatorTxAddBeforeRemove(t *testing.T) {
@@ -1257,7 +1257,7 @@ func TestAddDelegatorTxAddBeforeRemove(t *testing.T) {
 		ids.NodeID(id),
 		id,
 		reward.PercentDenominator,
-		[]*crypto.PrivateKeySECP256K1R{keys[0], keys[1]},
+		secp256k1fx.NewKeychain(keys[0], keys[1]),
 		changeAddr,
 	)
 	require.NoError(err)
@@ -1279,7 +1279,7 @@ func TestAddDelegatorTxAddBeforeRemove(t *testing.T) {
 		uint64(delegator1EndTime.Unix()),
 		ids.NodeID(id),
 		keys[0].PublicKey().Address(),
-		[]*crypto.PrivateKeySECP256K1R{keys[0], keys[1]},
+		secp256k1fx.NewKeychain(keys[0], keys[1]),
 		changeAddr,
 	)
 	require.NoError(err)
@@ -1301,7 +1301,7 @@ func TestAddDelegatorTxAddBeforeRemove(t *testing.T) {
 		uint64(delegator2EndTime.Unix()),
 		ids.NodeID(id),
 		keys[0].PublicKey().Address(),
-		[]*crypto.PrivateKeySECP256K1R{keys[0], keys[1]},
+		secp256k1fx.NewKeychain(keys[0], keys[1]),
 		changeAddr,
 	)
 	require.NoError(err)
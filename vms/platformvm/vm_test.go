@@ -35,8 +35,10 @@ import (
 	"github.com/dim4egster/qmallgo/snow/networking/timeout"
 	"github.com/dim4egster/qmallgo/snow/uptime"
 	"github.com/dim4egster/qmallgo/snow/validators"
+	"github.com/dim4egster/qmallgo/utils/compression"
 	"github.com/dim4egster/qmallgo/utils/constants"
 	"github.com/dim4egster/qmallgo/utils/crypto"
+	"github.com/dim4egster/qmallgo/utils/crypto/keychain"
 	"github.com/dim4egster/qmallgo/utils/formatting"
 	"github.com/dim4egster/qmallgo/utils/formatting/address"
 	"github.com/dim4egster/qmallgo/utils/json"
@@ -368,8 +370,8 @@ func defaultVM() (*VM, database.Database, *mutableSharedMemory) {
 		2, // threshold; 2 sigs from keys[0], keys[1], keys[2] needed to add validator to this subnet
 		// control keys are keys[0], keys[1], keys[2]
 		[]ids.ShortID{keys[0].PublicKey().Address(), keys[1].PublicKey().Address(), keys[2].PublicKey().Address()},
-		[]*crypto.PrivateKeySECP256K1R{keys[0]}, // pays tx fee
-		keys[0].PublicKey().Address(),           // change addr
+		secp256k1fx.NewKeychain(keys[0]), // pays tx fee
+		keys[0].PublicKey().Address(),    // change addr
 	)
 	if err != nil {
 		panic(err)
@@ -443,8 +445,8 @@ func GenesisVMWithArgs(t *testing.T, args *api.BuildGenesisArgs) ([]byte, chan c
 		2, // threshold; 2 sigs from keys[0], keys[1], keys[2] needed to add validator to this subnet
 		// control keys are keys[0], keys[1], keys[2]
 		[]ids.ShortID{keys[0].PublicKey().Address(), keys[1].PublicKey().Address(), keys[2].PublicKey().Address()},
-		[]*crypto.PrivateKeySECP256K1R{keys[0]}, // pays tx fee
-		keys[0].PublicKey().Address(),           // change addr
+		secp256k1fx.NewKeychain(keys[0]), // pays tx fee
+		keys[0].PublicKey().Address(),    // change addr
 	)
 	if err != nil {
 		panic(err)
@@ -569,7 +571,7 @@ func TestAddValidatorCommit(t *testing.T) {
 		nodeID,
 		rewardAddress,
 		reward.PercentDenominator,
-		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		secp256k1fx.NewKeychain(keys[0]),
 		ids.ShortEmpty, // change addr
 	)
 	require.NoError(err)
@@ -628,7 +630,7 @@ func TestInvalidAddValidatorCommit(t *testing.T) {
 		nodeID,
 		ids.ShortID(nodeID),
 		reward.PercentDenominator,
-		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		secp256k1fx.NewKeychain(keys[0]),
 		ids.ShortEmpty, // change addr
 	)
 	if err != nil {
@@ -688,7 +690,7 @@ func TestAddValidatorReject(t *testing.T) {
 		nodeID,
 		rewardAddress,
 		reward.PercentDenominator,
-		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		secp256k1fx.NewKeychain(keys[0]),
 		ids.ShortEmpty, // change addr
 	)
 	require.NoError(err)
@@ -752,7 +754,7 @@ func TestAddValidatorInvalidNotReissued(t *testing.T) {
 		repeatNodeID,
 		ids.ShortID(repeatNodeID),
 		reward.PercentDenominator,
-		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		secp256k1fx.NewKeychain(keys[0]),
 		ids.ShortEmpty, // change addr
 	)
 	if err != nil {
@@ -788,7 +790,7 @@ func TestAddSubnetValidatorAccept(t *testing.T) {
 		uint64(endTime.Unix()),
 		nodeID,
 		testSubnet1.ID(),
-		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+		secp256k1fx.NewKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 		ids.ShortEmpty, // change addr
 	)
 	require.NoError(err)
@@ -861,7 +863,7 @@ func TestAddSubnetValidatorReject(t *testing.T) {
 		uint64(endTime.Unix()),
 		nodeID,
 		testSubnet1.ID(),
-		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[1], testSubnet1ControlKeys[2]},
+		secp256k1fx.NewKeychain(testSubnet1ControlKeys[1], testSubnet1ControlKeys[2]),
 		ids.ShortEmpty, // change addr
 	)
 	require.NoError(err)
@@ -1233,7 +1235,7 @@ func TestCreateChain(t *testing.T) {
 		ids.ID{'t', 'e', 's', 't', 'v', 'm'},
 		nil,
 		"name",
-		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+		secp256k1fx.NewKeychain(testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]),
 		ids.ShortEmpty, // change addr
 	)
 	if err != nil {
@@ -1290,8 +1292,8 @@ func TestCreateSubnet(t *testing.T) {
 			keys[0].PublicKey().Address(),
 			keys[1].PublicKey().Address(),
 		},
-		[]*crypto.PrivateKeySECP256K1R{keys[0]}, // payer
-		keys[0].PublicKey().Address(),           // change addr
+		secp256k1fx.NewKeychain(keys[0]), // payer
+		keys[0].PublicKey().Address(),    // change addr
 	)
 	require.NoError(err)
 
@@ -1331,7 +1333,7 @@ func TestCreateSubnet(t *testing.T) {
 		uint64(endTime.Unix()),
 		nodeID,
 		createSubnetTx.ID(),
-		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		secp256k1fx.NewKeychain(keys[0]),
 		ids.ShortEmpty, // change addr
 	)
 	require.NoError(err)
@@ -1503,7 +1505,7 @@ func TestAtomicImport(t *testing.T) {
 	if _, err := vm.txBuilder.NewImportTx(
 		vm.ctx.XChainID,
 		recipientKey.PublicKey().Address(),
-		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		secp256k1fx.NewKeychain(keys[0]),
 		ids.ShortEmpty, // change addr
 	); err == nil {
 		t.Fatalf("should have errored due to missing utxos")
@@ -1540,7 +1542,7 @@ func TestAtomicImport(t *testing.T) {
 	tx, err := vm.txBuilder.NewImportTx(
 		vm.ctx.XChainID,
 		recipientKey.PublicKey().Address(),
-		[]*crypto.PrivateKeySECP256K1R{recipientKey},
+		secp256k1fx.NewKeychain(recipientKey),
 		ids.ShortEmpty, // change addr
 	)
 	if err != nil {
@@ -1594,7 +1596,7 @@ func TestOptimisticAtomicImport(t *testing.T) {
 			},
 		}},
 	}}
-	if err := tx.Sign(txs.Codec, [][]*crypto.PrivateKeySECP256K1R{{}}); err != nil {
+	if err := tx.Sign(txs.Codec, [][]keychain.Signer{{}}); err != nil {
 		t.Fatal(err)
 	}
 
@@ -1981,6 +1983,7 @@ func TestBootstrapPartiallyAccepted(t *testing.T) {
 					TimeoutCoefficient: 1.25,
 				},
 				benchlist,
+				timetracker.NewNoHealthTracker(),
 				"",
 				prometheus.NewRegistry(),
 			)
@@ -1993,7 +1996,7 @@ func TestBootstrapPartiallyAccepted(t *testing.T) {
 			metrics := prometheus.NewRegistry()
 			mc, err := message.NewCreator(metrics, "dummyNamespace", true, 10*time.Second)
 			require.NoError(err)
-			mcProto, err := message.NewCreatorWithProto(metrics, "dummyNamespace", true, 10*time.Second)
+			mcProto, err := message.NewCreatorWithProto(metrics, "dummyNamespace", compression.TypeGzip, nil, 10*time.Second)
 			require.NoError(err)
 
 			err = chainRouter.Initialize(ids.EmptyNodeID, logging.NoLog{}, mc, timeoutManager, time.Second, ids.Set{}, ids.Set{}, nil, router.HealthConfig{}, "", prometheus.NewRegistry())
@@ -2089,6 +2092,7 @@ func TestBootstrapPartiallyAccepted(t *testing.T) {
 				nil,
 				time.Hour,
 				cpuTracker,
+				handler.DefaultLanesConfig,
 			)
 			require.NoError(err)
 
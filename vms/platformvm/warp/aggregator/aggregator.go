@@ -0,0 +1,144 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package aggregator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/snow/validators"
+	"github.com/dim4egster/qmallgo/utils/crypto/bls"
+	"github.com/dim4egster/qmallgo/vms/platformvm/warp"
+)
+
+var (
+	errInsufficientWeight = errors.New("insufficient weight signed the message")
+	errNoValidSignature   = errors.New("no valid signature")
+)
+
+// Aggregator collects the signatures of the validators of a subnet to produce
+// signed messages.
+type Aggregator struct {
+	subnetID    ids.ID
+	pChainState validators.State
+	client      SignatureGetter
+}
+
+// New returns an Aggregator of the signatures of the validators of
+// [subnetID].
+func New(subnetID ids.ID, pChainState validators.State, client SignatureGetter) *Aggregator {
+	return &Aggregator{
+		subnetID:    subnetID,
+		pChainState: pChainState,
+		client:      client,
+	}
+}
+
+type signatureResult struct {
+	index     int
+	signature *bls.Signature
+	err       error
+}
+
+// AggregateSignatures requests the signature of [msg] from every validator of
+// the subnet at [pChainHeight], and returns the signed message once at least
+// [quorumNum]/[quorumDen] of the weight of the subnet signed it.
+func (a *Aggregator) AggregateSignatures(
+	ctx context.Context,
+	msg *warp.UnsignedMessage,
+	pChainHeight uint64,
+	quorumNum uint64,
+	quorumDen uint64,
+) (*warp.Message, error) {
+	vdrs, totalWeight, err := warp.GetCanonicalValidatorSet(a.pChainState, pChainHeight, a.subnetID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fail fast if the validators with public keys can't reach a quorum.
+	possibleWeight, err := warp.SumWeight(vdrs)
+	if err != nil {
+		return nil, err
+	}
+	if err := warp.VerifyWeight(possibleWeight, totalWeight, quorumNum, quorumDen); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan signatureResult, len(vdrs))
+	for i, vdr := range vdrs {
+		go func(i int, vdr *warp.Validator) {
+			sig, err := a.getSignature(ctx, vdr, msg)
+			results <- signatureResult{
+				index:     i,
+				signature: sig,
+				err:       err,
+			}
+		}(i, vdr)
+	}
+
+	var (
+		signers   = make([]int, 0, len(vdrs))
+		sigs      = make([]*bls.Signature, 0, len(vdrs))
+		sigWeight uint64
+	)
+	for range vdrs {
+		result := <-results
+		if result.err != nil {
+			continue
+		}
+
+		signers = append(signers, result.index)
+		sigs = append(sigs, result.signature)
+		// Can't overflow as the total weight didn't overflow.
+		sigWeight += vdrs[result.index].Weight
+
+		if warp.VerifyWeight(sigWeight, totalWeight, quorumNum, quorumDen) != nil {
+			continue
+		}
+
+		aggSig, err := bls.AggregateSignatures(sigs)
+		if err != nil {
+			return nil, err
+		}
+		signature := &warp.BitSetSignature{
+			Signers: warp.NewSigners(signers),
+		}
+		copy(signature.Signature[:], bls.SignatureToBytes(aggSig))
+		return warp.NewMessage(msg, signature)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf(
+		"%w: %d of %d",
+		errInsufficientWeight,
+		sigWeight,
+		totalWeight,
+	)
+}
+
+// getSignature requests the signature of [msg] from the nodes of [vdr] until
+// one of them returns a valid signature.
+func (a *Aggregator) getSignature(
+	ctx context.Context,
+	vdr *warp.Validator,
+	msg *warp.UnsignedMessage,
+) (*bls.Signature, error) {
+	for _, nodeID := range vdr.NodeIDs {
+		sig, err := a.client.GetSignature(ctx, nodeID, msg)
+		if err != nil {
+			continue
+		}
+		if bls.Verify(vdr.PublicKey, sig, msg.Bytes()) {
+			return sig, nil
+		}
+	}
+	return nil, errNoValidSignature
+}
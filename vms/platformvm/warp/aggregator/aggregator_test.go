@@ -0,0 +1,176 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package aggregator
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/snow/engine/common"
+	"github.com/dim4egster/qmallgo/snow/validators"
+	"github.com/dim4egster/qmallgo/utils/crypto/bls"
+	"github.com/dim4egster/qmallgo/utils/logging"
+	"github.com/dim4egster/qmallgo/vms/platformvm/warp"
+)
+
+var errRefused = errors.New("refused")
+
+type testBackend struct {
+	refuse bool
+}
+
+func (b *testBackend) VerifyMessage(*warp.UnsignedMessage) error {
+	if b.refuse {
+		return errRefused
+	}
+	return nil
+}
+
+type testNetwork struct {
+	handlers map[ids.NodeID]*Handler
+	backends map[ids.NodeID]*testBackend
+	client   *Client
+	state    validators.State
+
+	lock      sync.Mutex
+	responded map[uint32]bool
+}
+
+// newTestNetwork returns a network of validators of [chainID] with [weights],
+// and a validator without a public key with a weight of 10. Requests from the
+// client are served synchronously by the handlers of the validators.
+func newTestNetwork(t *testing.T, chainID ids.ID, weights ...uint64) *testNetwork {
+	n := &testNetwork{
+		handlers:  make(map[ids.NodeID]*Handler),
+		backends:  make(map[ids.NodeID]*testBackend),
+		responded: make(map[uint32]bool),
+	}
+
+	vdrSet := make(map[ids.NodeID]*validators.GetValidatorOutput)
+	for _, weight := range weights {
+		sk, err := bls.NewSecretKey()
+		require.NoError(t, err)
+
+		nodeID := ids.GenerateTestNodeID()
+		vdrSet[nodeID] = &validators.GetValidatorOutput{
+			NodeID:    nodeID,
+			PublicKey: bls.PublicFromSecretKey(sk),
+			Weight:    weight,
+		}
+
+		sender := &common.SenderTest{T: t}
+		sender.SendAppResponseF = func(_ ids.NodeID, requestID uint32, response []byte) error {
+			n.lock.Lock()
+			n.responded[requestID] = true
+			n.lock.Unlock()
+			return n.client.AppResponse(nodeID, requestID, response)
+		}
+		n.backends[nodeID] = &testBackend{}
+		n.handlers[nodeID] = NewHandler(logging.NoLog{}, n.backends[nodeID], warp.NewSigner(sk, chainID), sender)
+	}
+	nodeID := ids.GenerateTestNodeID()
+	vdrSet[nodeID] = &validators.GetValidatorOutput{
+		NodeID: nodeID,
+		Weight: 10,
+	}
+
+	n.state = &validators.TestState{
+		T: t,
+		GetValidatorSetF: func(uint64, ids.ID) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+			return vdrSet, nil
+		},
+	}
+
+	sender := &common.SenderTest{T: t}
+	sender.SendAppRequestF = func(nodeIDs ids.NodeIDSet, requestID uint32, request []byte) error {
+		for nodeID := range nodeIDs {
+			if handler, ok := n.handlers[nodeID]; ok {
+				if err := handler.AppRequest(ids.EmptyNodeID, requestID, time.Time{}, request); err != nil {
+					return err
+				}
+			}
+
+			n.lock.Lock()
+			responded := n.responded[requestID]
+			n.lock.Unlock()
+			if !responded {
+				// The request times out.
+				if err := n.client.AppRequestFailed(nodeID, requestID); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	n.client = NewClient(sender)
+	return n
+}
+
+func TestAggregateSignatures(t *testing.T) {
+	require := require.New(t)
+
+	chainID := ids.GenerateTestID()
+	subnetID := ids.GenerateTestID()
+	n := newTestNetwork(t, chainID, 30, 30, 30)
+	aggregator := New(subnetID, n.state, n.client)
+
+	msg, err := warp.NewUnsignedMessage(chainID, []byte("payload"))
+	require.NoError(err)
+
+	signedMsg, err := aggregator.AggregateSignatures(context.Background(), msg, 1, warp.QuorumNumerator, warp.QuorumDenominator)
+	require.NoError(err)
+	require.Equal(msg.Bytes(), signedMsg.UnsignedMessage.Bytes())
+
+	subnets := &testSubnetLookup{subnetID: subnetID}
+	verifier := warp.NewVerifier(subnets, n.state, warp.QuorumNumerator, warp.QuorumDenominator)
+	require.NoError(verifier.Verify(signedMsg, 1))
+
+	parsedMsg, err := warp.ParseMessage(signedMsg.Bytes())
+	require.NoError(err)
+	require.NoError(verifier.Verify(parsedMsg, 1))
+}
+
+func TestAggregateSignaturesInsufficientWeight(t *testing.T) {
+	require := require.New(t)
+
+	chainID := ids.GenerateTestID()
+	subnetID := ids.GenerateTestID()
+	n := newTestNetwork(t, chainID, 30, 30, 30)
+	for _, backend := range n.backends {
+		backend.refuse = true
+		break
+	}
+	aggregator := New(subnetID, n.state, n.client)
+
+	msg, err := warp.NewUnsignedMessage(chainID, []byte("payload"))
+	require.NoError(err)
+
+	_, err = aggregator.AggregateSignatures(context.Background(), msg, 1, warp.QuorumNumerator, warp.QuorumDenominator)
+	require.ErrorIs(err, errInsufficientWeight)
+
+	// A lower quorum is reached without the refusing validator.
+	signedMsg, err := aggregator.AggregateSignatures(context.Background(), msg, 1, 1, 2)
+	require.NoError(err)
+	require.NoError(signedMsg.Signature.Verify(msg, n.state, subnetID, 1, 1, 2))
+
+	// The validators don't sign messages of other chains.
+	otherMsg, err := warp.NewUnsignedMessage(ids.GenerateTestID(), []byte("payload"))
+	require.NoError(err)
+	_, err = aggregator.AggregateSignatures(context.Background(), otherMsg, 1, 1, 2)
+	require.ErrorIs(err, errInsufficientWeight)
+}
+
+type testSubnetLookup struct {
+	subnetID ids.ID
+}
+
+func (l *testSubnetLookup) SubnetID(ids.ID) (ids.ID, error) {
+	return l.subnetID, nil
+}
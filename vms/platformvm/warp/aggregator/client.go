@@ -0,0 +1,131 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package aggregator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/snow/engine/common"
+	"github.com/dim4egster/qmallgo/utils/crypto/bls"
+	"github.com/dim4egster/qmallgo/vms/platformvm/warp"
+)
+
+var (
+	_ SignatureGetter = &Client{}
+
+	errRequestFailed = errors.New("signature request failed")
+)
+
+// SignatureGetter fetches the signature of a message from a validator.
+type SignatureGetter interface {
+	// GetSignature returns the signature of [msg] by [nodeID]. The signature
+	// isn't verified.
+	GetSignature(ctx context.Context, nodeID ids.NodeID, msg *warp.UnsignedMessage) (*bls.Signature, error)
+}
+
+// Client requests the signatures of messages from validators with AppRequest
+// messages. A VM passes the AppResponse and AppRequestFailed messages of the
+// requests it issued to it.
+//
+// GetSignature blocks until the response arrives, so it must not be called
+// while holding the context lock of the chain.
+type Client struct {
+	sender common.AppSender
+
+	lock          sync.Mutex
+	nextRequestID uint32
+	requests      map[uint32]*signatureRequest
+}
+
+type signatureRequest struct {
+	nodeID ids.NodeID
+	// response receives the response to the request, or nil if it failed.
+	response chan []byte
+}
+
+// NewClient returns a Client that sends its requests with [sender].
+func NewClient(sender common.AppSender) *Client {
+	return &Client{
+		sender:   sender,
+		requests: make(map[uint32]*signatureRequest),
+	}
+}
+
+func (c *Client) GetSignature(ctx context.Context, nodeID ids.NodeID, msg *warp.UnsignedMessage) (*bls.Signature, error) {
+	reqBytes, err := Codec.Marshal(codecVersion, &SignatureRequest{
+		UnsignedMessage: msg.Bytes(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req := &signatureRequest{
+		nodeID:   nodeID,
+		response: make(chan []byte, 1),
+	}
+
+	c.lock.Lock()
+	requestID := c.nextRequestID
+	c.nextRequestID++
+	c.requests[requestID] = req
+	c.lock.Unlock()
+
+	defer func() {
+		c.lock.Lock()
+		delete(c.requests, requestID)
+		c.lock.Unlock()
+	}()
+
+	nodeIDs := ids.NewNodeIDSet(1)
+	nodeIDs.Add(nodeID)
+	if err := c.sender.SendAppRequest(nodeIDs, requestID, reqBytes); err != nil {
+		return nil, err
+	}
+
+	var respBytes []byte
+	select {
+	case respBytes = <-req.response:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if respBytes == nil {
+		return nil, fmt.Errorf("%w: %s didn't respond", errRequestFailed, nodeID)
+	}
+
+	resp := SignatureResponse{}
+	if _, err := Codec.Unmarshal(respBytes, &resp); err != nil {
+		return nil, fmt.Errorf("%w: %s", errRequestFailed, err)
+	}
+	return bls.SignatureFromBytes(resp.Signature[:])
+}
+
+// AppResponse delivers [response] to the request [requestID]. Responses to
+// unknown requests are ignored.
+func (c *Client) AppResponse(nodeID ids.NodeID, requestID uint32, response []byte) error {
+	c.deliver(nodeID, requestID, response)
+	return nil
+}
+
+// AppRequestFailed marks the request [requestID] as failed. Unknown requests
+// are ignored.
+func (c *Client) AppRequestFailed(nodeID ids.NodeID, requestID uint32) error {
+	c.deliver(nodeID, requestID, nil)
+	return nil
+}
+
+func (c *Client) deliver(nodeID ids.NodeID, requestID uint32, response []byte) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	req, ok := c.requests[requestID]
+	if !ok || req.nodeID != nodeID {
+		return
+	}
+	delete(c.requests, requestID)
+	req.response <- response
+}
@@ -0,0 +1,34 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package aggregator
+
+import (
+	"github.com/dim4egster/qmallgo/codec"
+	"github.com/dim4egster/qmallgo/codec/linearcodec"
+	"github.com/dim4egster/qmallgo/utils/crypto/bls"
+)
+
+const codecVersion = 0
+
+// Codec does serialization and deserialization for signature requests and
+// responses.
+var Codec codec.Manager
+
+func init() {
+	Codec = codec.NewDefaultManager()
+	if err := Codec.RegisterCodec(codecVersion, linearcodec.NewDefault()); err != nil {
+		panic(err)
+	}
+}
+
+// SignatureRequest asks a validator to sign a warp message.
+type SignatureRequest struct {
+	// UnsignedMessage is the binary representation of the message to sign.
+	UnsignedMessage []byte `serialize:"true"`
+}
+
+// SignatureResponse is the signature of the requested message.
+type SignatureResponse struct {
+	Signature [bls.SignatureLen]byte `serialize:"true"`
+}
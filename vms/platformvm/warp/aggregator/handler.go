@@ -0,0 +1,102 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package aggregator
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/snow/engine/common"
+	"github.com/dim4egster/qmallgo/utils/logging"
+	"github.com/dim4egster/qmallgo/vms/platformvm/warp"
+)
+
+// Backend decides which messages this node signs.
+type Backend interface {
+	// VerifyMessage returns nil if this node is willing to sign [msg]. For
+	// example, a VM would only sign the messages that its accepted blocks
+	// sent.
+	VerifyMessage(msg *warp.UnsignedMessage) error
+}
+
+// Handler serves the signature requests of other nodes. A VM passes the
+// AppRequest messages that are signature requests to it.
+type Handler struct {
+	log     logging.Logger
+	backend Backend
+	signer  warp.Signer
+	sender  common.AppSender
+}
+
+// NewHandler returns a Handler that signs the messages that [backend] allows
+// with [signer].
+func NewHandler(
+	log logging.Logger,
+	backend Backend,
+	signer warp.Signer,
+	sender common.AppSender,
+) *Handler {
+	return &Handler{
+		log:     log,
+		backend: backend,
+		signer:  signer,
+		sender:  sender,
+	}
+}
+
+// AppRequest responds to [request] with the signature of the requested
+// message. Requests that can't be served are dropped, so that the requester
+// times out and asks another validator.
+func (h *Handler) AppRequest(nodeID ids.NodeID, requestID uint32, _ time.Time, request []byte) error {
+	req := SignatureRequest{}
+	if _, err := Codec.Unmarshal(request, &req); err != nil {
+		h.log.Debug("dropping malformed signature request",
+			zap.Stringer("nodeID", nodeID),
+			zap.Uint32("requestID", requestID),
+			zap.Error(err),
+		)
+		return nil
+	}
+
+	msg, err := warp.ParseUnsignedMessage(req.UnsignedMessage)
+	if err != nil {
+		h.log.Debug("dropping signature request of malformed message",
+			zap.Stringer("nodeID", nodeID),
+			zap.Uint32("requestID", requestID),
+			zap.Error(err),
+		)
+		return nil
+	}
+
+	if err := h.backend.VerifyMessage(msg); err != nil {
+		h.log.Debug("refusing to sign message",
+			zap.Stringer("nodeID", nodeID),
+			zap.Uint32("requestID", requestID),
+			zap.Stringer("messageID", msg.ID()),
+			zap.Error(err),
+		)
+		return nil
+	}
+
+	sig, err := h.signer.Sign(msg)
+	if err != nil {
+		h.log.Debug("failed to sign message",
+			zap.Stringer("nodeID", nodeID),
+			zap.Uint32("requestID", requestID),
+			zap.Stringer("messageID", msg.ID()),
+			zap.Error(err),
+		)
+		return nil
+	}
+
+	resp := SignatureResponse{}
+	copy(resp.Signature[:], sig)
+	respBytes, err := Codec.Marshal(codecVersion, &resp)
+	if err != nil {
+		return err
+	}
+	return h.sender.SendAppResponse(nodeID, requestID, respBytes)
+}
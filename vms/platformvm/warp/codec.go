@@ -0,0 +1,31 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warp
+
+import (
+	"math"
+
+	"github.com/dim4egster/qmallgo/codec"
+	"github.com/dim4egster/qmallgo/codec/linearcodec"
+	"github.com/dim4egster/qmallgo/utils/wrappers"
+)
+
+const codecVersion = 0
+
+// Codec does serialization and deserialization for warp messages.
+var Codec codec.Manager
+
+func init() {
+	Codec = codec.NewManager(math.MaxInt)
+	lc := linearcodec.NewCustomMaxLength(math.MaxInt32)
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		lc.RegisterType(&BitSetSignature{}),
+		Codec.RegisterCodec(codecVersion, lc),
+	)
+	if errs.Errored() {
+		panic(errs.Err)
+	}
+}
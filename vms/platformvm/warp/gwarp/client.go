@@ -0,0 +1,35 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gwarp
+
+import (
+	"context"
+
+	"github.com/dim4egster/qmallgo/vms/platformvm/warp"
+
+	warppb "github.com/dim4egster/qmallgo/proto/pb/warp"
+)
+
+var _ warp.Signer = &Client{}
+
+// Client is a warp signer that talks over RPC.
+type Client struct {
+	client warppb.SignerClient
+}
+
+// NewClient returns a warp signer connected to a remote warp signer
+func NewClient(client warppb.SignerClient) *Client {
+	return &Client{client: client}
+}
+
+func (c *Client) Sign(msg *warp.UnsignedMessage) ([]byte, error) {
+	resp, err := c.client.Sign(context.Background(), &warppb.SignRequest{
+		SourceChainId: msg.SourceChainID[:],
+		Payload:       msg.Payload,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Signature, nil
+}
@@ -0,0 +1,46 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gwarp
+
+import (
+	"context"
+
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/vms/platformvm/warp"
+
+	warppb "github.com/dim4egster/qmallgo/proto/pb/warp"
+)
+
+var _ warppb.SignerServer = &Server{}
+
+// Server is a warp signer that is managed over RPC.
+type Server struct {
+	warppb.UnsafeSignerServer
+	signer warp.Signer
+}
+
+// NewServer returns a warp signer server connected to [signer]
+func NewServer(signer warp.Signer) *Server {
+	return &Server{signer: signer}
+}
+
+func (s *Server) Sign(_ context.Context, req *warppb.SignRequest) (*warppb.SignResponse, error) {
+	sourceChainID, err := ids.ToID(req.SourceChainId)
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err := warp.NewUnsignedMessage(sourceChainID, req.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := s.signer.Sign(msg)
+	if err != nil {
+		return nil, err
+	}
+	return &warppb.SignResponse{
+		Signature: sig,
+	}, nil
+}
@@ -0,0 +1,86 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warp
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/snow/validators"
+	"github.com/dim4egster/qmallgo/utils/crypto/bls"
+)
+
+var (
+	_ Signature = &BitSetSignature{}
+
+	errInvalidSignature = errors.New("invalid signature")
+)
+
+// Signature is the signature of a message by the validators of a subnet.
+type Signature interface {
+	// Verify that this signature of [msg] was signed by at least
+	// [quorumNum]/[quorumDen] of the weight of the validators of [subnetID] at
+	// [pChainHeight].
+	Verify(
+		msg *UnsignedMessage,
+		pChainState validators.State,
+		subnetID ids.ID,
+		pChainHeight uint64,
+		quorumNum uint64,
+		quorumDen uint64,
+	) error
+}
+
+// BitSetSignature is the aggregate of the signatures of the validators of a
+// subnet, along with the set of validators that signed.
+type BitSetSignature struct {
+	// Signers is the big-endian encoding of the indices of the validators that
+	// signed, in the canonical validator set. See GetCanonicalValidatorSet.
+	Signers []byte `serialize:"true"`
+	// Signature is the aggregate of the signatures of the signers.
+	Signature [bls.SignatureLen]byte `serialize:"true"`
+}
+
+func (s *BitSetSignature) Verify(
+	msg *UnsignedMessage,
+	pChainState validators.State,
+	subnetID ids.ID,
+	pChainHeight uint64,
+	quorumNum uint64,
+	quorumDen uint64,
+) error {
+	vdrs, totalWeight, err := GetCanonicalValidatorSet(pChainState, pChainHeight, subnetID)
+	if err != nil {
+		return err
+	}
+
+	signers, err := FilterValidators(s.Signers, vdrs)
+	if err != nil {
+		return err
+	}
+
+	sigWeight, err := SumWeight(signers)
+	if err != nil {
+		return err
+	}
+	if err := VerifyWeight(sigWeight, totalWeight, quorumNum, quorumDen); err != nil {
+		return err
+	}
+
+	aggPubKey, err := AggregatePublicKeys(signers)
+	if err != nil {
+		return err
+	}
+
+	aggSig, err := bls.SignatureFromBytes(s.Signature[:])
+	if err != nil {
+		return fmt.Errorf("%w: %s", errInvalidSignature, err)
+	}
+
+	if !bls.Verify(aggPubKey, aggSig, msg.Bytes()) {
+		return errInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/snow/validators"
+	"github.com/dim4egster/qmallgo/utils/crypto/bls"
+)
+
+type testValidator struct {
+	nodeID ids.NodeID
+	sk     *bls.SecretKey
+	weight uint64
+}
+
+func newTestValidators(t *testing.T, weights ...uint64) ([]*testValidator, validators.State) {
+	vdrs := make([]*testValidator, len(weights))
+	vdrSet := make(map[ids.NodeID]*validators.GetValidatorOutput, len(weights))
+	for i, weight := range weights {
+		sk, err := bls.NewSecretKey()
+		require.NoError(t, err)
+
+		vdrs[i] = &testValidator{
+			nodeID: ids.GenerateTestNodeID(),
+			sk:     sk,
+			weight: weight,
+		}
+		vdrSet[vdrs[i].nodeID] = &validators.GetValidatorOutput{
+			NodeID:    vdrs[i].nodeID,
+			PublicKey: bls.PublicFromSecretKey(sk),
+			Weight:    weight,
+		}
+	}
+
+	// A validator without a public key counts towards the total weight.
+	nodeID := ids.GenerateTestNodeID()
+	vdrSet[nodeID] = &validators.GetValidatorOutput{
+		NodeID: nodeID,
+		Weight: 10,
+	}
+
+	state := &validators.TestState{
+		T: t,
+		GetValidatorSetF: func(uint64, ids.ID) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+			return vdrSet, nil
+		},
+	}
+	return vdrs, state
+}
+
+// sign returns the signature of [msg] by the validators of [vdrs] at
+// [indices] of the canonical validator set.
+func sign(t *testing.T, msg *UnsignedMessage, vdrs []*testValidator, canonicalVdrs []*Validator, indices ...int) *BitSetSignature {
+	sigs := make([]*bls.Signature, 0, len(indices))
+	for _, index := range indices {
+		for _, vdr := range vdrs {
+			if vdr.nodeID == canonicalVdrs[index].NodeIDs[0] {
+				sigBytes, err := NewSigner(vdr.sk, msg.SourceChainID).Sign(msg)
+				require.NoError(t, err)
+				sig, err := bls.SignatureFromBytes(sigBytes)
+				require.NoError(t, err)
+				sigs = append(sigs, sig)
+			}
+		}
+	}
+	aggSig, err := bls.AggregateSignatures(sigs)
+	require.NoError(t, err)
+
+	signature := &BitSetSignature{
+		Signers: NewSigners(indices),
+	}
+	copy(signature.Signature[:], bls.SignatureToBytes(aggSig))
+	return signature
+}
+
+func TestSignatureVerify(t *testing.T) {
+	require := require.New(t)
+
+	vdrs, state := newTestValidators(t, 30, 30, 30)
+	subnetID := ids.GenerateTestID()
+	canonicalVdrs, totalWeight, err := GetCanonicalValidatorSet(state, 1, subnetID)
+	require.NoError(err)
+	require.Len(canonicalVdrs, 3)
+	require.EqualValues(100, totalWeight)
+
+	msg, err := NewUnsignedMessage(ids.GenerateTestID(), []byte("payload"))
+	require.NoError(err)
+
+	// 60% of the weight is below the quorum.
+	signature := sign(t, msg, vdrs, canonicalVdrs, 0, 2)
+	err = signature.Verify(msg, state, subnetID, 1, QuorumNumerator, QuorumDenominator)
+	require.ErrorIs(err, errInsufficientWeight)
+	require.NoError(signature.Verify(msg, state, subnetID, 1, 1, 2))
+
+	signature = sign(t, msg, vdrs, canonicalVdrs, 0, 1, 2)
+	require.NoError(signature.Verify(msg, state, subnetID, 1, QuorumNumerator, QuorumDenominator))
+
+	// The signature doesn't verify another message.
+	otherMsg, err := NewUnsignedMessage(msg.SourceChainID, []byte("other payload"))
+	require.NoError(err)
+	err = signature.Verify(otherMsg, state, subnetID, 1, QuorumNumerator, QuorumDenominator)
+	require.ErrorIs(err, errInvalidSignature)
+
+	// The signature doesn't verify with the wrong signers.
+	signature.Signers = NewSigners([]int{0, 1})
+	err = signature.Verify(msg, state, subnetID, 1, 1, 2)
+	require.ErrorIs(err, errInvalidSignature)
+}
+
+func TestFilterValidators(t *testing.T) {
+	require := require.New(t)
+
+	vdrs := []*Validator{{Weight: 1}, {Weight: 2}, {Weight: 3}}
+
+	filteredVdrs, err := FilterValidators(NewSigners([]int{0, 2}), vdrs)
+	require.NoError(err)
+	require.Equal([]*Validator{vdrs[0], vdrs[2]}, filteredVdrs)
+
+	_, err = FilterValidators(NewSigners([]int{3}), vdrs)
+	require.ErrorIs(err, errInvalidBitSet)
+
+	// Leading zeros are rejected.
+	_, err = FilterValidators([]byte{0x00, 0x01}, vdrs)
+	require.ErrorIs(err, errInvalidBitSet)
+}
+
+func TestSignerRejectsOtherChains(t *testing.T) {
+	require := require.New(t)
+
+	sk, err := bls.NewSecretKey()
+	require.NoError(err)
+	signer := NewSigner(sk, ids.GenerateTestID())
+
+	msg, err := NewUnsignedMessage(ids.GenerateTestID(), []byte("payload"))
+	require.NoError(err)
+	_, err = signer.Sign(msg)
+	require.ErrorIs(err, errWrongSourceChainID)
+}
+
+func TestMessageParse(t *testing.T) {
+	require := require.New(t)
+
+	unsignedMsg, err := NewUnsignedMessage(ids.GenerateTestID(), []byte("payload"))
+	require.NoError(err)
+
+	parsedUnsignedMsg, err := ParseUnsignedMessage(unsignedMsg.Bytes())
+	require.NoError(err)
+	require.Equal(unsignedMsg, parsedUnsignedMsg)
+
+	msg, err := NewMessage(unsignedMsg, &BitSetSignature{
+		Signers:   NewSigners([]int{1}),
+		Signature: [bls.SignatureLen]byte{1, 2, 3},
+	})
+	require.NoError(err)
+
+	parsedMsg, err := ParseMessage(msg.Bytes())
+	require.NoError(err)
+	require.Equal(msg, parsedMsg)
+	require.Equal(unsignedMsg.ID(), parsedMsg.ID())
+}
@@ -0,0 +1,48 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warp
+
+import (
+	"errors"
+
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/utils/crypto/bls"
+)
+
+var (
+	_ Signer = &signer{}
+
+	errWrongSourceChainID = errors.New("wrong SourceChainID")
+)
+
+// Signer signs unsigned messages with the BLS key of this node.
+type Signer interface {
+	// Sign returns the signature of [msg].
+	//
+	// A message can only be signed by the signer of its source chain, so that
+	// a chain can't forge the messages of another chain.
+	Sign(msg *UnsignedMessage) ([]byte, error)
+}
+
+// NewSigner returns a Signer of the messages sent by [chainID].
+func NewSigner(sk *bls.SecretKey, chainID ids.ID) Signer {
+	return &signer{
+		sk:      sk,
+		chainID: chainID,
+	}
+}
+
+type signer struct {
+	sk      *bls.SecretKey
+	chainID ids.ID
+}
+
+func (s *signer) Sign(msg *UnsignedMessage) ([]byte, error) {
+	if msg.SourceChainID != s.chainID {
+		return nil, errWrongSourceChainID
+	}
+
+	sig := bls.Sign(s.sk, msg.Bytes())
+	return bls.SignatureToBytes(sig), nil
+}
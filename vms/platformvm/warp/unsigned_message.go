@@ -0,0 +1,68 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warp
+
+import (
+	"fmt"
+
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/utils/hashing"
+)
+
+// UnsignedMessage is a message sent by a chain to be signed by the validators
+// of its subnet.
+type UnsignedMessage struct {
+	SourceChainID ids.ID `serialize:"true"`
+	Payload       []byte `serialize:"true"`
+
+	bytes []byte
+	id    ids.ID
+}
+
+// NewUnsignedMessage creates a new *UnsignedMessage and initializes it.
+func NewUnsignedMessage(sourceChainID ids.ID, payload []byte) (*UnsignedMessage, error) {
+	msg := &UnsignedMessage{
+		SourceChainID: sourceChainID,
+		Payload:       payload,
+	}
+	return msg, msg.Initialize()
+}
+
+// ParseUnsignedMessage converts a slice of bytes into an initialized
+// *UnsignedMessage.
+func ParseUnsignedMessage(b []byte) (*UnsignedMessage, error) {
+	msg := &UnsignedMessage{}
+	if _, err := Codec.Unmarshal(b, msg); err != nil {
+		return nil, err
+	}
+	msg.initialize(b)
+	return msg, nil
+}
+
+// Initialize recalculates the result of Bytes() and ID().
+func (m *UnsignedMessage) Initialize() error {
+	bytes, err := Codec.Marshal(codecVersion, m)
+	if err != nil {
+		return fmt.Errorf("couldn't marshal warp unsigned message: %w", err)
+	}
+	m.initialize(bytes)
+	return nil
+}
+
+func (m *UnsignedMessage) initialize(bytes []byte) {
+	m.bytes = bytes
+	m.id = hashing.ComputeHash256Array(bytes)
+}
+
+// Bytes returns the binary representation of this message. It assumes that
+// the message is initialized from either NewUnsignedMessage,
+// ParseUnsignedMessage, or an explicit call to Initialize.
+func (m *UnsignedMessage) Bytes() []byte {
+	return m.bytes
+}
+
+// ID returns the hash of the binary representation of this message.
+func (m *UnsignedMessage) ID() ids.ID {
+	return m.id
+}
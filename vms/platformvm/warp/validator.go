@@ -0,0 +1,178 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/snow/validators"
+	"github.com/dim4egster/qmallgo/utils/crypto/bls"
+	"github.com/dim4egster/qmallgo/utils/math"
+)
+
+var (
+	errInvalidBitSet      = errors.New("bitset is invalid")
+	errInsufficientWeight = errors.New("signature weight is insufficient")
+	errWeightOverflow     = errors.New("weight overflowed")
+)
+
+// Validator is a validator of a subnet identified by its BLS public key. The
+// weights of the nodes of the subnet that registered the same public key are
+// combined.
+type Validator struct {
+	PublicKey      *bls.PublicKey
+	PublicKeyBytes []byte
+	Weight         uint64
+	NodeIDs        []ids.NodeID
+}
+
+type validatorsByPublicKey []*Validator
+
+func (v validatorsByPublicKey) Len() int      { return len(v) }
+func (v validatorsByPublicKey) Swap(i, j int) { v[j], v[i] = v[i], v[j] }
+func (v validatorsByPublicKey) Less(i, j int) bool {
+	return bytes.Compare(v[i].PublicKeyBytes, v[j].PublicKeyBytes) < 0
+}
+
+// GetCanonicalValidatorSet returns the validators of [subnetID] at
+// [pChainHeight] that registered a BLS public key, sorted by public key, along
+// with the total weight of the subnet. The weight of the validators that
+// didn't register a public key is included in the total weight, as they are
+// unable to sign messages.
+func GetCanonicalValidatorSet(
+	pChainState validators.State,
+	pChainHeight uint64,
+	subnetID ids.ID,
+) ([]*Validator, uint64, error) {
+	vdrSet, err := pChainState.GetValidatorSet(pChainHeight, subnetID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("couldn't get validator set: %w", err)
+	}
+
+	var (
+		vdrs        = make(map[string]*Validator, len(vdrSet))
+		totalWeight uint64
+	)
+	for _, vdr := range vdrSet {
+		totalWeight, err = math.Add64(totalWeight, vdr.Weight)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%w: %s", errWeightOverflow, err)
+		}
+
+		if vdr.PublicKey == nil {
+			continue
+		}
+
+		pkBytes := bls.PublicKeyToBytes(vdr.PublicKey)
+		uniqueVdr, ok := vdrs[string(pkBytes)]
+		if !ok {
+			uniqueVdr = &Validator{
+				PublicKey:      vdr.PublicKey,
+				PublicKeyBytes: pkBytes,
+			}
+			vdrs[string(pkBytes)] = uniqueVdr
+		}
+
+		// Can't overflow as the total weight didn't overflow.
+		uniqueVdr.Weight += vdr.Weight
+		uniqueVdr.NodeIDs = append(uniqueVdr.NodeIDs, vdr.NodeID)
+	}
+
+	canonicalVdrs := make([]*Validator, 0, len(vdrs))
+	for _, vdr := range vdrs {
+		ids.SortNodeIDs(vdr.NodeIDs)
+		canonicalVdrs = append(canonicalVdrs, vdr)
+	}
+	sort.Sort(validatorsByPublicKey(canonicalVdrs))
+	return canonicalVdrs, totalWeight, nil
+}
+
+// NewSigners returns the big-endian encoding of the set of validators at
+// [indices] of a canonical validator set.
+func NewSigners(indices []int) []byte {
+	signers := new(big.Int)
+	for _, index := range indices {
+		signers.SetBit(signers, index, 1)
+	}
+	return signers.Bytes()
+}
+
+// FilterValidators returns the validators of [vdrs] whose indices are set in
+// the big-endian encoded [signers].
+func FilterValidators(signers []byte, vdrs []*Validator) ([]*Validator, error) {
+	indices := new(big.Int).SetBytes(signers)
+	// Reject non-canonical encodings, so that a signature has a unique
+	// representation.
+	if !bytes.Equal(indices.Bytes(), signers) {
+		return nil, fmt.Errorf("%w: non-canonical encoding", errInvalidBitSet)
+	}
+	if indices.BitLen() > len(vdrs) {
+		return nil, fmt.Errorf(
+			"%w: index %d is out of bounds of %d validators",
+			errInvalidBitSet,
+			indices.BitLen()-1,
+			len(vdrs),
+		)
+	}
+
+	filteredVdrs := make([]*Validator, 0, len(vdrs))
+	for i, vdr := range vdrs {
+		if indices.Bit(i) == 1 {
+			filteredVdrs = append(filteredVdrs, vdr)
+		}
+	}
+	return filteredVdrs, nil
+}
+
+// SumWeight returns the total weight of [vdrs].
+func SumWeight(vdrs []*Validator) (uint64, error) {
+	weight := uint64(0)
+	for _, vdr := range vdrs {
+		newWeight, err := math.Add64(weight, vdr.Weight)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %s", errWeightOverflow, err)
+		}
+		weight = newWeight
+	}
+	return weight, nil
+}
+
+// AggregatePublicKeys returns the aggregate of the public keys of [vdrs].
+func AggregatePublicKeys(vdrs []*Validator) (*bls.PublicKey, error) {
+	pks := make([]*bls.PublicKey, len(vdrs))
+	for i, vdr := range vdrs {
+		pks[i] = vdr.PublicKey
+	}
+	return bls.AggregatePublicKeys(pks)
+}
+
+// VerifyWeight returns nil if [sigWeight] is at least [quorumNum]/[quorumDen]
+// of [totalWeight].
+func VerifyWeight(sigWeight, totalWeight, quorumNum, quorumDen uint64) error {
+	// Compute in big integers to avoid overflows.
+	scaledSigWeight := new(big.Int).Mul(
+		new(big.Int).SetUint64(sigWeight),
+		new(big.Int).SetUint64(quorumDen),
+	)
+	scaledTotalWeight := new(big.Int).Mul(
+		new(big.Int).SetUint64(totalWeight),
+		new(big.Int).SetUint64(quorumNum),
+	)
+	if scaledSigWeight.Cmp(scaledTotalWeight) < 0 {
+		return fmt.Errorf(
+			"%w: %d*%d < %d*%d",
+			errInsufficientWeight,
+			sigWeight,
+			quorumDen,
+			totalWeight,
+			quorumNum,
+		)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warp
+
+import (
+	"fmt"
+
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/snow/validators"
+)
+
+const (
+	// QuorumNumerator and QuorumDenominator are the default fraction of the
+	// weight of a subnet that must sign a message for it to be accepted.
+	QuorumNumerator   = 67
+	QuorumDenominator = 100
+)
+
+var _ Verifier = &verifier{}
+
+// SubnetLookup returns the subnet that validates a chain. It is implemented by
+// the SNLookup of the snow context.
+type SubnetLookup interface {
+	SubnetID(chainID ids.ID) (ids.ID, error)
+}
+
+// Verifier checks the signatures of messages.
+type Verifier interface {
+	// Verify that [msg] was signed by the validators of the subnet of its
+	// source chain at [pChainHeight].
+	Verify(msg *Message, pChainHeight uint64) error
+}
+
+// NewVerifier returns a Verifier that requires [quorumNum]/[quorumDen] of the
+// weight of the source subnet to sign a message.
+func NewVerifier(
+	subnets SubnetLookup,
+	pChainState validators.State,
+	quorumNum uint64,
+	quorumDen uint64,
+) Verifier {
+	return &verifier{
+		subnets:     subnets,
+		pChainState: pChainState,
+		quorumNum:   quorumNum,
+		quorumDen:   quorumDen,
+	}
+}
+
+type verifier struct {
+	subnets              SubnetLookup
+	pChainState          validators.State
+	quorumNum, quorumDen uint64
+}
+
+func (v *verifier) Verify(msg *Message, pChainHeight uint64) error {
+	subnetID, err := v.subnets.SubnetID(msg.SourceChainID)
+	if err != nil {
+		return fmt.Errorf("couldn't get subnet of chain %s: %w", msg.SourceChainID, err)
+	}
+	return msg.Signature.Verify(
+		&msg.UnsignedMessage,
+		v.pChainState,
+		subnetID,
+		pChainHeight,
+		v.quorumNum,
+		v.quorumDen,
+	)
+}
@@ -898,12 +898,12 @@ func initTestRemoteProposerVM(
 	}
 	valState.GetMinimumHeightF = func() (uint64, error) { return coreGenBlk.Height(), nil }
 	valState.GetCurrentHeightF = func() (uint64, error) { return defaultPChainHeight, nil }
-	valState.GetValidatorSetF = func(height uint64, subnetID ids.ID) (map[ids.NodeID]uint64, error) {
-		res := make(map[ids.NodeID]uint64)
-		res[proVM.ctx.NodeID] = uint64(10)
-		res[ids.NodeID{1}] = uint64(5)
-		res[ids.NodeID{2}] = uint64(6)
-		res[ids.NodeID{3}] = uint64(7)
+	valState.GetValidatorSetF = func(height uint64, subnetID ids.ID) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+		res := make(map[ids.NodeID]*validators.GetValidatorOutput)
+		res[proVM.ctx.NodeID] = &validators.GetValidatorOutput{NodeID: proVM.ctx.NodeID, Weight: 10}
+		res[ids.NodeID{1}] = &validators.GetValidatorOutput{NodeID: ids.NodeID{1}, Weight: 5}
+		res[ids.NodeID{2}] = &validators.GetValidatorOutput{NodeID: ids.NodeID{2}, Weight: 6}
+		res[ids.NodeID{3}] = &validators.GetValidatorOutput{NodeID: ids.NodeID{3}, Weight: 7}
 		return res, nil
 	}
 
@@ -22,7 +22,7 @@ func TestWindowerNoValidators(t *testing.T) {
 	nodeID := ids.GenerateTestNodeID()
 	vdrState := &validators.TestState{
 		T: t,
-		GetValidatorSetF: func(height uint64, subnetID ids.ID) (map[ids.NodeID]uint64, error) {
+		GetValidatorSetF: func(height uint64, subnetID ids.ID) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
 			return nil, nil
 		},
 	}
@@ -43,9 +43,9 @@ func TestWindowerRepeatedValidator(t *testing.T) {
 	nonValidatorID := ids.GenerateTestNodeID()
 	vdrState := &validators.TestState{
 		T: t,
-		GetValidatorSetF: func(height uint64, subnetID ids.ID) (map[ids.NodeID]uint64, error) {
-			return map[ids.NodeID]uint64{
-				validatorID: 10,
+		GetValidatorSetF: func(height uint64, subnetID ids.ID) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+			return map[ids.NodeID]*validators.GetValidatorOutput{
+				validatorID: {NodeID: validatorID, Weight: 10},
 			}, nil
 		},
 	}
@@ -72,12 +72,12 @@ func TestWindowerChangeByHeight(t *testing.T) {
 	}
 	vdrState := &validators.TestState{
 		T: t,
-		GetValidatorSetF: func(height uint64, subnetID ids.ID) (map[ids.NodeID]uint64, error) {
-			validators := make(map[ids.NodeID]uint64, MaxWindows)
+		GetValidatorSetF: func(height uint64, subnetID ids.ID) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+			vdrs := make(map[ids.NodeID]*validators.GetValidatorOutput, MaxWindows)
 			for _, id := range validatorIDs {
-				validators[id] = 1
+				vdrs[id] = &validators.GetValidatorOutput{NodeID: id, Weight: 1}
 			}
-			return validators, nil
+			return vdrs, nil
 		},
 	}
 
@@ -131,12 +131,12 @@ func TestWindowerChangeByChain(t *testing.T) {
 	}
 	vdrState := &validators.TestState{
 		T: t,
-		GetValidatorSetF: func(height uint64, subnetID ids.ID) (map[ids.NodeID]uint64, error) {
-			validators := make(map[ids.NodeID]uint64, MaxWindows)
+		GetValidatorSetF: func(height uint64, subnetID ids.ID) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+			vdrs := make(map[ids.NodeID]*validators.GetValidatorOutput, MaxWindows)
 			for _, id := range validatorIDs {
-				validators[id] = 1
+				vdrs[id] = &validators.GetValidatorOutput{NodeID: id, Weight: 1}
 			}
-			return validators, nil
+			return vdrs, nil
 		},
 	}
 
@@ -14,6 +14,7 @@ import (
 	"github.com/dim4egster/qmallgo/ids"
 	"github.com/dim4egster/qmallgo/snow/choices"
 	"github.com/dim4egster/qmallgo/snow/consensus/snowman"
+	"github.com/dim4egster/qmallgo/snow/validators"
 	"github.com/dim4egster/qmallgo/vms/proposervm/block"
 	"github.com/dim4egster/qmallgo/vms/proposervm/proposer"
 )
@@ -638,9 +639,9 @@ func TestGetBlock_MutatedSignature(t *testing.T) {
 	coreVM, valState, proVM, coreGenBlk, _ := initTestProposerVM(t, time.Time{}, 0)
 
 	// Make sure that we will be sampled to perform the proposals.
-	valState.GetValidatorSetF = func(height uint64, subnetID ids.ID) (map[ids.NodeID]uint64, error) {
-		res := make(map[ids.NodeID]uint64)
-		res[proVM.ctx.NodeID] = uint64(10)
+	valState.GetValidatorSetF = func(height uint64, subnetID ids.ID) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+		res := make(map[ids.NodeID]*validators.GetValidatorOutput)
+		res[proVM.ctx.NodeID] = &validators.GetValidatorOutput{NodeID: proVM.ctx.NodeID, Weight: 10}
 		return res, nil
 	}
 
@@ -133,12 +133,12 @@ func initTestProposerVM(
 	}
 	valState.GetMinimumHeightF = func() (uint64, error) { return coreGenBlk.HeightV, nil }
 	valState.GetCurrentHeightF = func() (uint64, error) { return defaultPChainHeight, nil }
-	valState.GetValidatorSetF = func(height uint64, subnetID ids.ID) (map[ids.NodeID]uint64, error) {
-		res := make(map[ids.NodeID]uint64)
-		res[proVM.ctx.NodeID] = uint64(10)
-		res[ids.NodeID{1}] = uint64(5)
-		res[ids.NodeID{2}] = uint64(6)
-		res[ids.NodeID{3}] = uint64(7)
+	valState.GetValidatorSetF = func(height uint64, subnetID ids.ID) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+		res := make(map[ids.NodeID]*validators.GetValidatorOutput)
+		res[proVM.ctx.NodeID] = &validators.GetValidatorOutput{NodeID: proVM.ctx.NodeID, Weight: 10}
+		res[ids.NodeID{1}] = &validators.GetValidatorOutput{NodeID: ids.NodeID{1}, Weight: 5}
+		res[ids.NodeID{2}] = &validators.GetValidatorOutput{NodeID: ids.NodeID{2}, Weight: 6}
+		res[ids.NodeID{3}] = &validators.GetValidatorOutput{NodeID: ids.NodeID{3}, Weight: 7}
 		return res, nil
 	}
 
@@ -872,9 +872,9 @@ func TestExpiredBuildBlock(t *testing.T) {
 	}
 	valState.GetMinimumHeightF = func() (uint64, error) { return coreGenBlk.Height(), nil }
 	valState.GetCurrentHeightF = func() (uint64, error) { return defaultPChainHeight, nil }
-	valState.GetValidatorSetF = func(height uint64, subnetID ids.ID) (map[ids.NodeID]uint64, error) {
-		return map[ids.NodeID]uint64{
-			{1}: 100,
+	valState.GetValidatorSetF = func(height uint64, subnetID ids.ID) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+		return map[ids.NodeID]*validators.GetValidatorOutput{
+			{1}: {NodeID: ids.NodeID{1}, Weight: 100},
 		}, nil
 	}
 
@@ -1147,9 +1147,9 @@ func TestInnerVMRollback(t *testing.T) {
 		T: t,
 	}
 	valState.GetCurrentHeightF = func() (uint64, error) { return defaultPChainHeight, nil }
-	valState.GetValidatorSetF = func(height uint64, subnetID ids.ID) (map[ids.NodeID]uint64, error) {
-		return map[ids.NodeID]uint64{
-			{1}: 100,
+	valState.GetValidatorSetF = func(height uint64, subnetID ids.ID) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+		return map[ids.NodeID]*validators.GetValidatorOutput{
+			{1}: {NodeID: ids.NodeID{1}, Weight: 100},
 		}, nil
 	}
 
@@ -1314,9 +1314,9 @@ func TestInnerVMRollback(t *testing.T) {
 func TestBuildBlockDuringWindow(t *testing.T) {
 	coreVM, valState, proVM, coreGenBlk, _ := initTestProposerVM(t, time.Time{}, 0) // enable ProBlks
 
-	valState.GetValidatorSetF = func(height uint64, subnetID ids.ID) (map[ids.NodeID]uint64, error) {
-		return map[ids.NodeID]uint64{
-			proVM.ctx.NodeID: 10,
+	valState.GetValidatorSetF = func(height uint64, subnetID ids.ID) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+		return map[ids.NodeID]*validators.GetValidatorOutput{
+			proVM.ctx.NodeID: {NodeID: proVM.ctx.NodeID, Weight: 10},
 		}, nil
 	}
 
@@ -1821,12 +1821,12 @@ func TestRejectedHeightNotIndexed(t *testing.T) {
 	}
 	valState.GetMinimumHeightF = func() (uint64, error) { return coreGenBlk.HeightV, nil }
 	valState.GetCurrentHeightF = func() (uint64, error) { return defaultPChainHeight, nil }
-	valState.GetValidatorSetF = func(height uint64, subnetID ids.ID) (map[ids.NodeID]uint64, error) {
-		res := make(map[ids.NodeID]uint64)
-		res[proVM.ctx.NodeID] = uint64(10)
-		res[ids.NodeID{1}] = uint64(5)
-		res[ids.NodeID{2}] = uint64(6)
-		res[ids.NodeID{3}] = uint64(7)
+	valState.GetValidatorSetF = func(height uint64, subnetID ids.ID) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+		res := make(map[ids.NodeID]*validators.GetValidatorOutput)
+		res[proVM.ctx.NodeID] = &validators.GetValidatorOutput{NodeID: proVM.ctx.NodeID, Weight: 10}
+		res[ids.NodeID{1}] = &validators.GetValidatorOutput{NodeID: ids.NodeID{1}, Weight: 5}
+		res[ids.NodeID{2}] = &validators.GetValidatorOutput{NodeID: ids.NodeID{2}, Weight: 6}
+		res[ids.NodeID{3}] = &validators.GetValidatorOutput{NodeID: ids.NodeID{3}, Weight: 7}
 		return res, nil
 	}
 
@@ -1999,12 +1999,12 @@ func TestRejectedOptionHeightNotIndexed(t *testing.T) {
 	}
 	valState.GetMinimumHeightF = func() (uint64, error) { return coreGenBlk.HeightV, nil }
 	valState.GetCurrentHeightF = func() (uint64, error) { return defaultPChainHeight, nil }
-	valState.GetValidatorSetF = func(height uint64, subnetID ids.ID) (map[ids.NodeID]uint64, error) {
-		res := make(map[ids.NodeID]uint64)
-		res[proVM.ctx.NodeID] = uint64(10)
-		res[ids.NodeID{1}] = uint64(5)
-		res[ids.NodeID{2}] = uint64(6)
-		res[ids.NodeID{3}] = uint64(7)
+	valState.GetValidatorSetF = func(height uint64, subnetID ids.ID) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+		res := make(map[ids.NodeID]*validators.GetValidatorOutput)
+		res[proVM.ctx.NodeID] = &validators.GetValidatorOutput{NodeID: proVM.ctx.NodeID, Weight: 10}
+		res[ids.NodeID{1}] = &validators.GetValidatorOutput{NodeID: ids.NodeID{1}, Weight: 5}
+		res[ids.NodeID{2}] = &validators.GetValidatorOutput{NodeID: ids.NodeID{2}, Weight: 6}
+		res[ids.NodeID{3}] = &validators.GetValidatorOutput{NodeID: ids.NodeID{3}, Weight: 7}
 		return res, nil
 	}
 
@@ -0,0 +1,87 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package grpcutils
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/propagation"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+var (
+	_ propagation.TextMapCarrier = metadataCarrier{}
+
+	// tracePropagator encodes span contexts using the W3C trace context
+	// headers.
+	tracePropagator = propagation.TraceContext{}
+)
+
+// metadataCarrier allows span contexts to be propagated in gRPC metadata.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	values := metadata.MD(c).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// traceUnaryClientInterceptor injects the span context of the request's
+// context into the outgoing metadata, so that spans recorded by the server
+// are parented by the caller's span.
+func traceUnaryClientInterceptor(
+	ctx context.Context,
+	method string,
+	req interface{},
+	reply interface{},
+	cc *grpc.ClientConn,
+	invoker grpc.UnaryInvoker,
+	opts ...grpc.CallOption,
+) error {
+	if !oteltrace.SpanContextFromContext(ctx).IsValid() {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+	tracePropagator.Inject(ctx, metadataCarrier(md))
+	ctx = metadata.NewOutgoingContext(ctx, md)
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// traceUnaryServerInterceptor extracts the span context that was injected by
+// [traceUnaryClientInterceptor] into the request's context.
+func traceUnaryServerInterceptor(
+	ctx context.Context,
+	req interface{},
+	_ *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		ctx = tracePropagator.Extract(ctx, metadataCarrier(md))
+	}
+	return handler(ctx, req)
+}
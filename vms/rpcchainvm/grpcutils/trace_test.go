@@ -0,0 +1,50 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package grpcutils
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func TestTraceInterceptorsPropagateSpanContext(t *testing.T) {
+	require := require.New(t)
+
+	spanCtx := oteltrace.NewSpanContext(oteltrace.SpanContextConfig{
+		TraceID:    oteltrace.TraceID{1},
+		SpanID:     oteltrace.SpanID{2},
+		TraceFlags: oteltrace.FlagsSampled,
+	})
+	ctx := oteltrace.ContextWithSpanContext(context.Background(), spanCtx)
+	ctx = metadata.AppendToOutgoingContext(ctx, "key", "value")
+
+	var outgoingMD metadata.MD
+	invoker := func(ctx context.Context, _ string, _, _ interface{}, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+		var ok bool
+		outgoingMD, ok = metadata.FromOutgoingContext(ctx)
+		require.True(ok)
+		return nil
+	}
+	require.NoError(traceUnaryClientInterceptor(ctx, "method", nil, nil, nil, invoker))
+	require.Equal([]string{"value"}, outgoingMD.Get("key"))
+
+	serverCtx := metadata.NewIncomingContext(context.Background(), outgoingMD)
+	handler := func(ctx context.Context, _ interface{}) (interface{}, error) {
+		remoteSpanCtx := oteltrace.SpanContextFromContext(ctx)
+		require.True(remoteSpanCtx.IsRemote())
+		require.Equal(spanCtx.TraceID(), remoteSpanCtx.TraceID())
+		require.Equal(spanCtx.SpanID(), remoteSpanCtx.SpanID())
+		require.True(remoteSpanCtx.IsSampled())
+		return nil, nil
+	}
+	_, err := traceUnaryServerInterceptor(serverCtx, nil, nil, handler)
+	require.NoError(err)
+}
@@ -86,6 +86,7 @@ var (
 			Timeout:             defaultClientKeepAliveTimeOut,
 			PermitWithoutStream: defaultPermitWithoutStream,
 		}),
+		grpc.WithChainUnaryInterceptor(traceUnaryClientInterceptor),
 	}
 
 	DefaultServerOptions = []grpc.ServerOption{
@@ -102,6 +103,7 @@ var (
 			MaxConnectionAge:      defaultServerMaxConnectionAge,
 			MaxConnectionAgeGrace: defaultServerMaxConnectionAgeGrace,
 		}),
+		grpc.ChainUnaryInterceptor(traceUnaryServerInterceptor),
 	}
 )
 
@@ -39,10 +39,12 @@ import (
 	"github.com/dim4egster/qmallgo/snow/engine/common"
 	"github.com/dim4egster/qmallgo/snow/engine/common/appsender"
 	"github.com/dim4egster/qmallgo/snow/engine/snowman/block"
+	"github.com/dim4egster/qmallgo/snow/validators/gvalidators"
 	"github.com/dim4egster/qmallgo/utils/resource"
 	"github.com/dim4egster/qmallgo/utils/wrappers"
 	"github.com/dim4egster/qmallgo/version"
 	"github.com/dim4egster/qmallgo/vms/components/chain"
+	"github.com/dim4egster/qmallgo/vms/platformvm/warp/gwarp"
 	"github.com/dim4egster/qmallgo/vms/rpcchainvm/ghttp"
 	"github.com/dim4egster/qmallgo/vms/rpcchainvm/grpcutils"
 	"github.com/dim4egster/qmallgo/vms/rpcchainvm/gsubnetlookup"
@@ -56,7 +58,9 @@ import (
 	rpcdbpb "github.com/dim4egster/qmallgo/proto/pb/rpcdb"
 	sharedmemorypb "github.com/dim4egster/qmallgo/proto/pb/sharedmemory"
 	subnetlookuppb "github.com/dim4egster/qmallgo/proto/pb/subnetlookup"
+	validatorstatepb "github.com/dim4egster/qmallgo/proto/pb/validatorstate"
 	vmpb "github.com/dim4egster/qmallgo/proto/pb/vm"
+	warppb "github.com/dim4egster/qmallgo/proto/pb/warp"
 )
 
 const (
@@ -89,12 +93,14 @@ type VMClient struct {
 	pid            int
 	processTracker resource.ProcessTracker
 
-	messenger    *messenger.Server
-	keystore     *gkeystore.Server
-	sharedMemory *gsharedmemory.Server
-	bcLookup     *galiasreader.Server
-	snLookup     *gsubnetlookup.Server
-	appSender    *appsender.Server
+	messenger      *messenger.Server
+	keystore       *gkeystore.Server
+	sharedMemory   *gsharedmemory.Server
+	bcLookup       *galiasreader.Server
+	snLookup       *gsubnetlookup.Server
+	validatorState *gvalidators.Server
+	warpSigner     *gwarp.Server
+	appSender      *appsender.Server
 
 	serverCloser grpcutils.ServerCloser
 	conns        []*grpc.ClientConn
@@ -180,6 +186,12 @@ func (vm *VMClient) Initialize(
 	vm.sharedMemory = gsharedmemory.NewServer(ctx.SharedMemory, dbManager.Current().Database)
 	vm.bcLookup = galiasreader.NewServer(ctx.BCLookup)
 	vm.snLookup = gsubnetlookup.NewServer(ctx.SNLookup)
+	if ctx.ValidatorState != nil {
+		vm.validatorState = gvalidators.NewServer(ctx.ValidatorState)
+	}
+	if ctx.WarpSigner != nil {
+		vm.warpSigner = gwarp.NewServer(ctx.WarpSigner)
+	}
 	vm.appSender = appsender.NewServer(appSender)
 
 	serverListener, err := grpcutils.NewListener()
@@ -316,6 +328,15 @@ func (vm *VMClient) getInitServer(opts []grpc.ServerOption) *grpc.Server {
 	aliasreaderpb.RegisterAliasReaderServer(server, vm.bcLookup)
 	// register the subnet alias service
 	subnetlookuppb.RegisterSubnetLookupServer(server, vm.snLookup)
+	// register the validator state service, if the chain has access to the
+	// P-chain validators
+	if vm.validatorState != nil {
+		validatorstatepb.RegisterValidatorStateServer(server, vm.validatorState)
+	}
+	// register the warp signer service, if the node can sign warp messages
+	if vm.warpSigner != nil {
+		warppb.RegisterSignerServer(server, vm.warpSigner)
+	}
 	// register the app sender service
 	appsenderpb.RegisterAppSenderServer(server, vm.appSender)
 	// register the health service
@@ -435,8 +456,18 @@ func (vm *VMClient) Disconnected(nodeID ids.NodeID) error {
 	return err
 }
 
+// traceContext returns the context of the operation the chain is currently
+// executing. Passing it to the server allows the server's spans to be parented
+// by the span of the operation that caused the request.
+func (vm *VMClient) traceContext() context.Context {
+	if vm.ctx == nil {
+		return context.Background()
+	}
+	return vm.ctx.TraceContext.Get()
+}
+
 func (vm *VMClient) buildBlock() (snowman.Block, error) {
-	resp, err := vm.client.BuildBlock(context.Background(), &emptypb.Empty{})
+	resp, err := vm.client.BuildBlock(vm.traceContext(), &emptypb.Empty{})
 	if err != nil {
 		return nil, err
 	}
@@ -464,7 +495,7 @@ func (vm *VMClient) buildBlock() (snowman.Block, error) {
 }
 
 func (vm *VMClient) parseBlock(bytes []byte) (snowman.Block, error) {
-	resp, err := vm.client.ParseBlock(context.Background(), &vmpb.ParseBlockRequest{
+	resp, err := vm.client.ParseBlock(vm.traceContext(), &vmpb.ParseBlockRequest{
 		Bytes: bytes,
 	})
 	if err != nil {
@@ -499,7 +530,7 @@ func (vm *VMClient) parseBlock(bytes []byte) (snowman.Block, error) {
 }
 
 func (vm *VMClient) getBlock(id ids.ID) (snowman.Block, error) {
-	resp, err := vm.client.GetBlock(context.Background(), &vmpb.GetBlockRequest{
+	resp, err := vm.client.GetBlock(vm.traceContext(), &vmpb.GetBlockRequest{
 		Id: id[:],
 	})
 	if err != nil {
@@ -532,7 +563,7 @@ func (vm *VMClient) getBlock(id ids.ID) (snowman.Block, error) {
 }
 
 func (vm *VMClient) SetPreference(id ids.ID) error {
-	_, err := vm.client.SetPreference(context.Background(), &vmpb.SetPreferenceRequest{
+	_, err := vm.client.SetPreference(vm.traceContext(), &vmpb.SetPreferenceRequest{
 		Id: id[:],
 	})
 	return err
@@ -619,7 +650,7 @@ func (vm *VMClient) GetAncestors(
 	maxBlocksSize int,
 	maxBlocksRetrivalTime time.Duration,
 ) ([][]byte, error) {
-	resp, err := vm.client.GetAncestors(context.Background(), &vmpb.GetAncestorsRequest{
+	resp, err := vm.client.GetAncestors(vm.traceContext(), &vmpb.GetAncestorsRequest{
 		BlkId:                 blkID[:],
 		MaxBlocksNum:          int32(maxBlocksNum),
 		MaxBlocksSize:         int32(maxBlocksSize),
@@ -632,7 +663,7 @@ func (vm *VMClient) GetAncestors(
 }
 
 func (vm *VMClient) BatchedParseBlock(blksBytes [][]byte) ([]snowman.Block, error) {
-	resp, err := vm.client.BatchedParseBlock(context.Background(), &vmpb.BatchedParseBlockRequest{
+	resp, err := vm.client.BatchedParseBlock(vm.traceContext(), &vmpb.BatchedParseBlockRequest{
 		Request: blksBytes,
 	})
 	if err != nil {
@@ -815,7 +846,7 @@ func (b *blockClient) ID() ids.ID { return b.id }
 
 func (b *blockClient) Accept() error {
 	b.status = choices.Accepted
-	_, err := b.vm.client.BlockAccept(context.Background(), &vmpb.BlockAcceptRequest{
+	_, err := b.vm.client.BlockAccept(b.vm.traceContext(), &vmpb.BlockAcceptRequest{
 		Id: b.id[:],
 	})
 	return err
@@ -823,7 +854,7 @@ func (b *blockClient) Accept() error {
 
 func (b *blockClient) Reject() error {
 	b.status = choices.Rejected
-	_, err := b.vm.client.BlockReject(context.Background(), &vmpb.BlockRejectRequest{
+	_, err := b.vm.client.BlockReject(b.vm.traceContext(), &vmpb.BlockRejectRequest{
 		Id: b.id[:],
 	})
 	return err
@@ -834,7 +865,7 @@ func (b *blockClient) Status() choices.Status { return b.status }
 func (b *blockClient) Parent() ids.ID { return b.parentID }
 
 func (b *blockClient) Verify() error {
-	resp, err := b.vm.client.BlockVerify(context.Background(), &vmpb.BlockVerifyRequest{
+	resp, err := b.vm.client.BlockVerify(b.vm.traceContext(), &vmpb.BlockVerifyRequest{
 		Bytes: b.bytes,
 	})
 	if err != nil {
@@ -29,9 +29,12 @@ import (
 	"github.com/dim4egster/qmallgo/snow/engine/common"
 	"github.com/dim4egster/qmallgo/snow/engine/common/appsender"
 	"github.com/dim4egster/qmallgo/snow/engine/snowman/block"
+	"github.com/dim4egster/qmallgo/snow/validators/gvalidators"
+	"github.com/dim4egster/qmallgo/trace"
 	"github.com/dim4egster/qmallgo/utils/logging"
 	"github.com/dim4egster/qmallgo/utils/wrappers"
 	"github.com/dim4egster/qmallgo/version"
+	"github.com/dim4egster/qmallgo/vms/platformvm/warp/gwarp"
 	"github.com/dim4egster/qmallgo/vms/rpcchainvm/ghttp"
 	"github.com/dim4egster/qmallgo/vms/rpcchainvm/grpcutils"
 	"github.com/dim4egster/qmallgo/vms/rpcchainvm/gsubnetlookup"
@@ -45,7 +48,9 @@ import (
 	rpcdbpb "github.com/dim4egster/qmallgo/proto/pb/rpcdb"
 	sharedmemorypb "github.com/dim4egster/qmallgo/proto/pb/sharedmemory"
 	subnetlookuppb "github.com/dim4egster/qmallgo/proto/pb/subnetlookup"
+	validatorstatepb "github.com/dim4egster/qmallgo/proto/pb/validatorstate"
 	vmpb "github.com/dim4egster/qmallgo/proto/pb/vm"
+	warppb "github.com/dim4egster/qmallgo/proto/pb/warp"
 )
 
 var _ vmpb.VMServer = &VMServer{}
@@ -169,6 +174,8 @@ func (vm *VMServer) Initialize(_ context.Context, req *vmpb.InitializeRequest) (
 	sharedMemoryClient := gsharedmemory.NewClient(sharedmemorypb.NewSharedMemoryClient(clientConn))
 	bcLookupClient := galiasreader.NewClient(aliasreaderpb.NewAliasReaderClient(clientConn))
 	snLookupClient := gsubnetlookup.NewClient(subnetlookuppb.NewSubnetLookupClient(clientConn))
+	validatorStateClient := gvalidators.NewClient(validatorstatepb.NewValidatorStateClient(clientConn))
+	warpSignerClient := gwarp.NewClient(warppb.NewSignerClient(clientConn))
 	appSenderClient := appsender.NewClient(appsenderpb.NewAppSenderClient(clientConn))
 
 	toEngine := make(chan common.Message, 1)
@@ -197,14 +204,19 @@ func (vm *VMServer) Initialize(_ context.Context, req *vmpb.InitializeRequest) (
 		XChainID:    xChainID,
 		AVAXAssetID: avaxAssetID,
 
-		Log:          logging.NoLog{},
-		Keystore:     keystoreClient,
-		SharedMemory: sharedMemoryClient,
-		BCLookup:     bcLookupClient,
-		SNLookup:     snLookupClient,
-		Metrics:      metrics.NewOptionalGatherer(),
+		Log:            logging.NoLog{},
+		Keystore:       keystoreClient,
+		SharedMemory:   sharedMemoryClient,
+		BCLookup:       bcLookupClient,
+		SNLookup:       snLookupClient,
+		ValidatorState: validatorStateClient,
+		WarpSigner:     warpSignerClient,
+		Metrics:        metrics.NewOptionalGatherer(),
 
-		// TODO: support snowman++ fields
+		Tracer:       trace.Noop,
+		TraceContext: &trace.CurrentContext{},
+
+		// TODO: support the remaining snowman++ fields
 	}
 
 	if err := vm.vm.Initialize(vm.ctx, dbManager, req.GenesisBytes, req.UpgradeBytes, req.ConfigBytes, toEngine, nil, appSenderClient); err != nil {
@@ -371,7 +383,9 @@ func (vm *VMServer) Disconnected(_ context.Context, req *vmpb.DisconnectedReques
 	return &emptypb.Empty{}, vm.vm.Disconnected(nodeID)
 }
 
-func (vm *VMServer) BuildBlock(context.Context, *emptypb.Empty) (*vmpb.BuildBlockResponse, error) {
+func (vm *VMServer) BuildBlock(ctx context.Context, _ *emptypb.Empty) (*vmpb.BuildBlockResponse, error) {
+	defer vm.setTraceContext(ctx)()
+
 	blk, err := vm.vm.BuildBlock()
 	if err != nil {
 		return nil, err
@@ -387,7 +401,9 @@ func (vm *VMServer) BuildBlock(context.Context, *emptypb.Empty) (*vmpb.BuildBloc
 	}, nil
 }
 
-func (vm *VMServer) ParseBlock(_ context.Context, req *vmpb.ParseBlockRequest) (*vmpb.ParseBlockResponse, error) {
+func (vm *VMServer) ParseBlock(ctx context.Context, req *vmpb.ParseBlockRequest) (*vmpb.ParseBlockResponse, error) {
+	defer vm.setTraceContext(ctx)()
+
 	blk, err := vm.vm.ParseBlock(req.Bytes)
 	if err != nil {
 		return nil, err
@@ -403,7 +419,9 @@ func (vm *VMServer) ParseBlock(_ context.Context, req *vmpb.ParseBlockRequest) (
 	}, nil
 }
 
-func (vm *VMServer) GetBlock(_ context.Context, req *vmpb.GetBlockRequest) (*vmpb.GetBlockResponse, error) {
+func (vm *VMServer) GetBlock(ctx context.Context, req *vmpb.GetBlockRequest) (*vmpb.GetBlockResponse, error) {
+	defer vm.setTraceContext(ctx)()
+
 	id, err := ids.ToID(req.Id)
 	if err != nil {
 		return nil, err
@@ -425,7 +443,9 @@ func (vm *VMServer) GetBlock(_ context.Context, req *vmpb.GetBlockRequest) (*vmp
 	}, nil
 }
 
-func (vm *VMServer) SetPreference(_ context.Context, req *vmpb.SetPreferenceRequest) (*emptypb.Empty, error) {
+func (vm *VMServer) SetPreference(ctx context.Context, req *vmpb.SetPreferenceRequest) (*emptypb.Empty, error) {
+	defer vm.setTraceContext(ctx)()
+
 	id, err := ids.ToID(req.Id)
 	if err != nil {
 		return nil, err
@@ -531,7 +551,9 @@ func (vm *VMServer) Gather(context.Context, *emptypb.Empty) (*vmpb.GatherRespons
 	return &vmpb.GatherResponse{MetricFamilies: mfs}, err
 }
 
-func (vm *VMServer) GetAncestors(_ context.Context, req *vmpb.GetAncestorsRequest) (*vmpb.GetAncestorsResponse, error) {
+func (vm *VMServer) GetAncestors(ctx context.Context, req *vmpb.GetAncestorsRequest) (*vmpb.GetAncestorsResponse, error) {
+	defer vm.setTraceContext(ctx)()
+
 	blkID, err := ids.ToID(req.BlkId)
 	if err != nil {
 		return nil, err
@@ -556,6 +578,8 @@ func (vm *VMServer) BatchedParseBlock(
 	ctx context.Context,
 	req *vmpb.BatchedParseBlockRequest,
 ) (*vmpb.BatchedParseBlockResponse, error) {
+	defer vm.setTraceContext(ctx)()
+
 	blocks := make([]*vmpb.ParseBlockResponse, len(req.Request))
 	for i, blockBytes := range req.Request {
 		block, err := vm.ParseBlock(ctx, &vmpb.ParseBlockRequest{
@@ -726,7 +750,9 @@ func (vm *VMServer) GetStateSummary(
 	}, nil
 }
 
-func (vm *VMServer) BlockVerify(_ context.Context, req *vmpb.BlockVerifyRequest) (*vmpb.BlockVerifyResponse, error) {
+func (vm *VMServer) BlockVerify(ctx context.Context, req *vmpb.BlockVerifyRequest) (*vmpb.BlockVerifyResponse, error) {
+	defer vm.setTraceContext(ctx)()
+
 	blk, err := vm.vm.ParseBlock(req.Bytes)
 	if err != nil {
 		return nil, err
@@ -739,7 +765,9 @@ func (vm *VMServer) BlockVerify(_ context.Context, req *vmpb.BlockVerifyRequest)
 	}, nil
 }
 
-func (vm *VMServer) BlockAccept(_ context.Context, req *vmpb.BlockAcceptRequest) (*emptypb.Empty, error) {
+func (vm *VMServer) BlockAccept(ctx context.Context, req *vmpb.BlockAcceptRequest) (*emptypb.Empty, error) {
+	defer vm.setTraceContext(ctx)()
+
 	id, err := ids.ToID(req.Id)
 	if err != nil {
 		return nil, err
@@ -754,7 +782,9 @@ func (vm *VMServer) BlockAccept(_ context.Context, req *vmpb.BlockAcceptRequest)
 	return &emptypb.Empty{}, nil
 }
 
-func (vm *VMServer) BlockReject(_ context.Context, req *vmpb.BlockRejectRequest) (*emptypb.Empty, error) {
+func (vm *VMServer) BlockReject(ctx context.Context, req *vmpb.BlockRejectRequest) (*emptypb.Empty, error) {
+	defer vm.setTraceContext(ctx)()
+
 	id, err := ids.ToID(req.Id)
 	if err != nil {
 		return nil, err
@@ -792,3 +822,16 @@ func (vm *VMServer) StateSummaryAccept(
 		Err:      errorToErrCode[err],
 	}, errorToRPCError(err)
 }
+
+// setTraceContext records [ctx] as the context of the operation the VM is
+// currently executing, so that spans started by the VM are parented by the
+// client's span. The returned function restores the previous context.
+func (vm *VMServer) setTraceContext(ctx context.Context) func() {
+	if vm.ctx == nil {
+		return func() {}
+	}
+	previousCtx := vm.ctx.TraceContext.Set(ctx)
+	return func() {
+		vm.ctx.TraceContext.Set(previousCtx)
+	}
+}
@@ -10,11 +10,16 @@ import (
 
 	"github.com/dim4egster/qmallgo/ids"
 	"github.com/dim4egster/qmallgo/utils/crypto"
+	"github.com/dim4egster/qmallgo/utils/crypto/keychain"
 	"github.com/dim4egster/qmallgo/utils/formatting"
 	"github.com/dim4egster/qmallgo/vms/components/verify"
 )
 
-var errCantSpend = errors.New("unable to spend this UTXO")
+var (
+	errCantSpend = errors.New("unable to spend this UTXO")
+
+	_ keychain.Keychain = &Keychain{}
+)
 
 // Keychain is a collection of keys that can be used to spend outputs
 type Keychain struct {
@@ -48,12 +53,20 @@ func (kc *Keychain) Add(key *crypto.PrivateKeySECP256K1R) {
 	}
 }
 
-// Get a key from the keychain. If the key is unknown, the
-func (kc Keychain) Get(id ids.ShortID) (*crypto.PrivateKeySECP256K1R, bool) {
+// Get a signer from the keychain. If the key is unknown, false is returned.
+func (kc Keychain) Get(id ids.ShortID) (keychain.Signer, bool) {
+	key, ok := kc.get(id)
+	if !ok {
+		return nil, false
+	}
+	return key, true
+}
+
+func (kc Keychain) get(id ids.ShortID) (*crypto.PrivateKeySECP256K1R, bool) {
 	if i, ok := kc.addrToKeyIndex[id]; ok {
 		return kc.Keys[i], true
 	}
-	return &crypto.PrivateKeySECP256K1R{}, false
+	return nil, false
 }
 
 // Addresses returns a list of addresses this keychain manages
@@ -73,16 +86,36 @@ func (kc *Keychain) New() (*crypto.PrivateKeySECP256K1R, error) {
 
 // Spend attempts to create an input
 func (kc *Keychain) Spend(out verify.Verifiable, time uint64) (verify.Verifiable, []*crypto.PrivateKeySECP256K1R, error) {
+	return spend(kc.get, out, time)
+}
+
+// Match attempts to match a list of addresses up to the provided threshold
+func (kc *Keychain) Match(owners *OutputOwners, time uint64) ([]uint32, []*crypto.PrivateKeySECP256K1R, bool) {
+	return match(kc.get, owners, time)
+}
+
+// Spend attempts to create an input spending [out] with the signers of [kc]
+func Spend(kc keychain.Keychain, out verify.Verifiable, time uint64) (verify.Verifiable, []keychain.Signer, error) {
+	return spend(kc.Get, out, time)
+}
+
+// Match attempts to match a list of addresses up to the provided threshold
+// with the signers of [kc]
+func Match(kc keychain.Keychain, owners *OutputOwners, time uint64) ([]uint32, []keychain.Signer, bool) {
+	return match(kc.Get, owners, time)
+}
+
+func spend[T any](get func(ids.ShortID) (T, bool), out verify.Verifiable, time uint64) (verify.Verifiable, []T, error) {
 	switch out := out.(type) {
 	case *MintOutput:
-		if sigIndices, keys, able := kc.Match(&out.OutputOwners, time); able {
+		if sigIndices, keys, able := match(get, &out.OutputOwners, time); able {
 			return &Input{
 				SigIndices: sigIndices,
 			}, keys, nil
 		}
 		return nil, nil, errCantSpend
 	case *TransferOutput:
-		if sigIndices, keys, able := kc.Match(&out.OutputOwners, time); able {
+		if sigIndices, keys, able := match(get, &out.OutputOwners, time); able {
 			return &TransferInput{
 				Amt: out.Amt,
 				Input: Input{
@@ -95,15 +128,14 @@ func (kc *Keychain) Spend(out verify.Verifiable, time uint64) (verify.Verifiable
 	return nil, nil, fmt.Errorf("can't spend UTXO because it is unexpected type %T", out)
 }
 
-// Match attempts to match a list of addresses up to the provided threshold
-func (kc *Keychain) Match(owners *OutputOwners, time uint64) ([]uint32, []*crypto.PrivateKeySECP256K1R, bool) {
+func match[T any](get func(ids.ShortID) (T, bool), owners *OutputOwners, time uint64) ([]uint32, []T, bool) {
 	if time < owners.Locktime {
 		return nil, nil, false
 	}
 	sigs := make([]uint32, 0, owners.Threshold)
-	keys := make([]*crypto.PrivateKeySECP256K1R, 0, owners.Threshold)
+	keys := make([]T, 0, owners.Threshold)
 	for i := uint32(0); i < uint32(len(owners.Addrs)) && uint32(len(keys)) < owners.Threshold; i++ {
-		if key, exists := kc.Get(owners.Addrs[i]); exists {
+		if key, exists := get(owners.Addrs[i]); exists {
 			sigs = append(sigs, i)
 			keys = append(keys, key)
 		}
@@ -56,7 +56,7 @@ func TestKeychainAdd(t *testing.T) {
 	addr, _ := ids.ShortFromString(addrs[0])
 	rsk, exists := kc.Get(addr)
 	require.True(exists)
-	require.Equal(sk.Bytes(), rsk.Bytes())
+	require.Equal(sk, rsk)
 
 	addrs := kc.Addresses()
 	require.Equal(1, addrs.Len())
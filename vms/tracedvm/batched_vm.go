@@ -0,0 +1,57 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package tracedvm
+
+import (
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/snow/consensus/snowman"
+	"github.com/dim4egster/qmallgo/snow/engine/snowman/block"
+)
+
+func (vm *blockVM) GetAncestors(
+	blkID ids.ID,
+	maxBlocksNum int,
+	maxBlocksSize int,
+	maxBlocksRetrivalTime time.Duration,
+) ([][]byte, error) {
+	if vm.bVM == nil {
+		return nil, block.ErrRemoteVMNotImplemented
+	}
+
+	end := vm.start("GetAncestors",
+		attribute.Stringer("blkID", blkID),
+		attribute.Int("maxBlocksNum", maxBlocksNum),
+	)
+	defer end()
+
+	return vm.bVM.GetAncestors(
+		blkID,
+		maxBlocksNum,
+		maxBlocksSize,
+		maxBlocksRetrivalTime,
+	)
+}
+
+func (vm *blockVM) BatchedParseBlock(blks [][]byte) ([]snowman.Block, error) {
+	if vm.bVM == nil {
+		return nil, block.ErrRemoteVMNotImplemented
+	}
+
+	end := vm.start("BatchedParseBlock", attribute.Int("numBlocks", len(blks)))
+	defer end()
+
+	blocks, err := vm.bVM.BatchedParseBlock(blks)
+	wrappedBlocks := make([]snowman.Block, len(blocks))
+	for i, block := range blocks {
+		wrappedBlocks[i] = &tracedBlock{
+			Block: block,
+			vm:    vm,
+		}
+	}
+	return wrappedBlocks, err
+}
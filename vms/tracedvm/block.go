@@ -0,0 +1,74 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package tracedvm
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/dim4egster/qmallgo/snow/consensus/snowman"
+)
+
+var (
+	_ snowman.Block       = &tracedBlock{}
+	_ snowman.OracleBlock = &tracedBlock{}
+)
+
+type tracedBlock struct {
+	snowman.Block
+
+	vm *blockVM
+}
+
+func (tb *tracedBlock) Verify() error {
+	end := tb.vm.start("Verify", tb.attributes()...)
+	defer end()
+
+	return tb.Block.Verify()
+}
+
+func (tb *tracedBlock) Accept() error {
+	end := tb.vm.start("Accept", tb.attributes()...)
+	defer end()
+
+	return tb.Block.Accept()
+}
+
+func (tb *tracedBlock) Reject() error {
+	end := tb.vm.start("Reject", tb.attributes()...)
+	defer end()
+
+	return tb.Block.Reject()
+}
+
+func (tb *tracedBlock) Options() ([2]snowman.Block, error) {
+	oracleBlock, ok := tb.Block.(snowman.OracleBlock)
+	if !ok {
+		return [2]snowman.Block{}, snowman.ErrNotOracle
+	}
+
+	end := tb.vm.start("Options", tb.attributes()...)
+	defer end()
+
+	blks, err := oracleBlock.Options()
+	if err != nil {
+		return [2]snowman.Block{}, err
+	}
+	return [2]snowman.Block{
+		&tracedBlock{
+			Block: blks[0],
+			vm:    tb.vm,
+		},
+		&tracedBlock{
+			Block: blks[1],
+			vm:    tb.vm,
+		},
+	}, nil
+}
+
+func (tb *tracedBlock) attributes() []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.Stringer("blkID", tb.ID()),
+		attribute.Int64("height", int64(tb.Height())),
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package tracedvm
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/dim4egster/qmallgo/database/manager"
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/snow"
+	"github.com/dim4egster/qmallgo/snow/consensus/snowman"
+	"github.com/dim4egster/qmallgo/snow/engine/common"
+	"github.com/dim4egster/qmallgo/snow/engine/snowman/block"
+	"github.com/dim4egster/qmallgo/trace"
+
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+var (
+	_ block.ChainVM              = &blockVM{}
+	_ block.BatchedChainVM       = &blockVM{}
+	_ block.HeightIndexedChainVM = &blockVM{}
+	_ block.StateSyncableVM      = &blockVM{}
+)
+
+type blockVM struct {
+	block.ChainVM
+	bVM  block.BatchedChainVM
+	hVM  block.HeightIndexedChainVM
+	ssVM block.StateSyncableVM
+
+	// name is used to prefix the names of the spans recorded by this VM
+	name   string
+	tracer trace.Tracer
+
+	// traceContext is the context of the operation the chain is currently
+	// executing. It is populated during Initialize.
+	traceContext *trace.CurrentContext
+}
+
+// NewBlockVM returns a VM that records a span for every call made to [vm].
+// Spans are parented by the chain's current trace context, which allows calls
+// to the VM to be attributed to the message that caused them.
+func NewBlockVM(vm block.ChainVM, name string, tracer trace.Tracer) block.ChainVM {
+	bVM, _ := vm.(block.BatchedChainVM)
+	hVM, _ := vm.(block.HeightIndexedChainVM)
+	ssVM, _ := vm.(block.StateSyncableVM)
+	return &blockVM{
+		ChainVM: vm,
+		bVM:     bVM,
+		hVM:     hVM,
+		ssVM:    ssVM,
+		name:    name,
+		tracer:  tracer,
+	}
+}
+
+func (vm *blockVM) Initialize(
+	ctx *snow.Context,
+	db manager.Manager,
+	genesisBytes,
+	upgradeBytes,
+	configBytes []byte,
+	toEngine chan<- common.Message,
+	fxs []*common.Fx,
+	appSender common.AppSender,
+) error {
+	vm.traceContext = ctx.TraceContext
+
+	end := vm.start("Initialize")
+	defer end()
+
+	return vm.ChainVM.Initialize(ctx, db, genesisBytes, upgradeBytes, configBytes, toEngine, fxs, appSender)
+}
+
+func (vm *blockVM) BuildBlock() (snowman.Block, error) {
+	end := vm.start("BuildBlock")
+	defer end()
+
+	blk, err := vm.ChainVM.BuildBlock()
+	if err != nil {
+		return nil, err
+	}
+	return &tracedBlock{
+		Block: blk,
+		vm:    vm,
+	}, nil
+}
+
+func (vm *blockVM) ParseBlock(b []byte) (snowman.Block, error) {
+	end := vm.start("ParseBlock", attribute.Int("blockLen", len(b)))
+	defer end()
+
+	blk, err := vm.ChainVM.ParseBlock(b)
+	if err != nil {
+		return nil, err
+	}
+	return &tracedBlock{
+		Block: blk,
+		vm:    vm,
+	}, nil
+}
+
+func (vm *blockVM) GetBlock(id ids.ID) (snowman.Block, error) {
+	end := vm.start("GetBlock", attribute.Stringer("blkID", id))
+	defer end()
+
+	blk, err := vm.ChainVM.GetBlock(id)
+	if err != nil {
+		return nil, err
+	}
+	return &tracedBlock{
+		Block: blk,
+		vm:    vm,
+	}, nil
+}
+
+func (vm *blockVM) SetPreference(id ids.ID) error {
+	end := vm.start("SetPreference", attribute.Stringer("blkID", id))
+	defer end()
+
+	return vm.ChainVM.SetPreference(id)
+}
+
+func (vm *blockVM) LastAccepted() (ids.ID, error) {
+	end := vm.start("LastAccepted")
+	defer end()
+
+	return vm.ChainVM.LastAccepted()
+}
+
+// start records a span named [op] and sets it as the chain's current trace
+// context, so that any spans started while executing [op] are parented by
+// it. The returned function ends the span and restores the previous context.
+func (vm *blockVM) start(op string, attrs ...attribute.KeyValue) func() {
+	ctx, span := vm.tracer.Start(
+		vm.traceContext.Get(),
+		fmt.Sprintf("%s.%s", vm.name, op),
+		oteltrace.WithAttributes(attrs...),
+	)
+	previousCtx := vm.traceContext.Set(ctx)
+	return func() {
+		vm.traceContext.Set(previousCtx)
+		span.End()
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package tracedvm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/dim4egster/qmallgo/database/manager"
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/snow"
+	"github.com/dim4egster/qmallgo/snow/choices"
+	"github.com/dim4egster/qmallgo/snow/consensus/snowman"
+	"github.com/dim4egster/qmallgo/snow/engine/common"
+	"github.com/dim4egster/qmallgo/snow/engine/snowman/block"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+type testTracer struct {
+	oteltrace.Tracer
+}
+
+func (*testTracer) Close() error {
+	return nil
+}
+
+func TestBlockVMSpanHierarchy(t *testing.T) {
+	require := require.New(t)
+
+	recorder := tracetest.NewSpanRecorder()
+	tracer := &testTracer{
+		Tracer: sdktrace.NewTracerProvider(
+			sdktrace.WithSpanProcessor(recorder),
+		).Tracer("test"),
+	}
+
+	ctx := snow.DefaultContextTest()
+	blk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		HeightV: 1,
+	}
+	innerVM := &block.TestVM{
+		TestVM: common.TestVM{
+			T: t,
+			InitializeF: func(*snow.Context, manager.Manager, []byte, []byte, []byte, chan<- common.Message, []*common.Fx, common.AppSender) error {
+				return nil
+			},
+		},
+		BuildBlockF: func() (snowman.Block, error) {
+			return blk, nil
+		},
+	}
+	vm := NewBlockVM(NewBlockVM(innerVM, "inner", tracer), "outer", tracer)
+	require.NoError(vm.Initialize(ctx, nil, nil, nil, nil, nil, nil, nil))
+
+	parentCtx, parentSpan := tracer.Start(ctx.TraceContext.Get(), "handler")
+	ctx.TraceContext.Set(parentCtx)
+
+	builtBlk, err := vm.BuildBlock()
+	require.NoError(err)
+	require.NoError(builtBlk.Verify())
+	require.NoError(builtBlk.Accept())
+
+	// The chain's trace context should be restored once the VM has returned.
+	require.Equal(parentCtx, ctx.TraceContext.Get())
+	parentSpan.End()
+
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	for _, op := range []string{"BuildBlock", "Verify", "Accept"} {
+		outer, ok := spans["outer."+op]
+		require.True(ok, op)
+		inner, ok := spans["inner."+op]
+		require.True(ok, op)
+
+		require.Equal(parentSpan.SpanContext().SpanID(), outer.Parent().SpanID())
+		require.Equal(outer.SpanContext().SpanID(), inner.Parent().SpanID())
+		require.Equal(parentSpan.SpanContext().TraceID(), inner.SpanContext().TraceID())
+	}
+}
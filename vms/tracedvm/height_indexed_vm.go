@@ -0,0 +1,33 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package tracedvm
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/snow/engine/snowman/block"
+)
+
+func (vm *blockVM) VerifyHeightIndex() error {
+	if vm.hVM == nil {
+		return block.ErrHeightIndexedVMNotImplemented
+	}
+
+	end := vm.start("VerifyHeightIndex")
+	defer end()
+
+	return vm.hVM.VerifyHeightIndex()
+}
+
+func (vm *blockVM) GetBlockIDAtHeight(height uint64) (ids.ID, error) {
+	if vm.hVM == nil {
+		return ids.Empty, block.ErrHeightIndexedVMNotImplemented
+	}
+
+	end := vm.start("GetBlockIDAtHeight", attribute.Int64("height", int64(height)))
+	defer end()
+
+	return vm.hVM.GetBlockIDAtHeight(height)
+}
@@ -0,0 +1,65 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package tracedvm
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/dim4egster/qmallgo/snow/engine/snowman/block"
+)
+
+func (vm *blockVM) StateSyncEnabled() (bool, error) {
+	if vm.ssVM == nil {
+		return false, nil
+	}
+
+	end := vm.start("StateSyncEnabled")
+	defer end()
+
+	return vm.ssVM.StateSyncEnabled()
+}
+
+func (vm *blockVM) GetOngoingSyncStateSummary() (block.StateSummary, error) {
+	if vm.ssVM == nil {
+		return nil, block.ErrStateSyncableVMNotImplemented
+	}
+
+	end := vm.start("GetOngoingSyncStateSummary")
+	defer end()
+
+	return vm.ssVM.GetOngoingSyncStateSummary()
+}
+
+func (vm *blockVM) GetLastStateSummary() (block.StateSummary, error) {
+	if vm.ssVM == nil {
+		return nil, block.ErrStateSyncableVMNotImplemented
+	}
+
+	end := vm.start("GetLastStateSummary")
+	defer end()
+
+	return vm.ssVM.GetLastStateSummary()
+}
+
+func (vm *blockVM) ParseStateSummary(summaryBytes []byte) (block.StateSummary, error) {
+	if vm.ssVM == nil {
+		return nil, block.ErrStateSyncableVMNotImplemented
+	}
+
+	end := vm.start("ParseStateSummary", attribute.Int("summaryLen", len(summaryBytes)))
+	defer end()
+
+	return vm.ssVM.ParseStateSummary(summaryBytes)
+}
+
+func (vm *blockVM) GetStateSummary(height uint64) (block.StateSummary, error) {
+	if vm.ssVM == nil {
+		return nil, block.ErrStateSyncableVMNotImplemented
+	}
+
+	end := vm.start("GetStateSummary", attribute.Int64("height", int64(height)))
+	defer end()
+
+	return vm.ssVM.GetStateSummary(height)
+}
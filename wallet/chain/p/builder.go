@@ -289,8 +289,9 @@ func (b *builder) NewBaseTx(
 	outputs []*avax.TransferableOutput,
 	options ...common.Option,
 ) (*txs.CreateSubnetTx, error) {
+	fee := b.backend.CreateSubnetTxFee()
 	toBurn := map[ids.ID]uint64{
-		b.backend.AVAXAssetID(): b.backend.CreateSubnetTxFee(),
+		b.backend.AVAXAssetID(): fee,
 	}
 	for _, out := range outputs {
 		assetID := out.AssetID()
@@ -307,6 +308,7 @@ func (b *builder) NewBaseTx(
 	if err != nil {
 		return nil, err
 	}
+	ops.RecordSpend(inputs, changeOutputs, nil, fee)
 	outputs = append(outputs, changeOutputs...)
 	avax.SortTransferableOutputs(outputs, txs.Codec) // sort the outputs
 
@@ -329,8 +331,9 @@ func (b *builder) NewAddValidatorTx(
 	options ...common.Option,
 ) (*txs.AddValidatorTx, error) {
 	avaxAssetID := b.backend.AVAXAssetID()
+	fee := b.backend.AddPrimaryNetworkValidatorFee()
 	toBurn := map[ids.ID]uint64{
-		avaxAssetID: b.backend.AddPrimaryNetworkValidatorFee(),
+		avaxAssetID: fee,
 	}
 	toStake := map[ids.ID]uint64{
 		avaxAssetID: vdr.Wght,
@@ -340,6 +343,7 @@ func (b *builder) NewAddValidatorTx(
 	if err != nil {
 		return nil, err
 	}
+	ops.RecordSpend(inputs, baseOutputs, stakeOutputs, fee)
 
 	ids.SortShortIDs(rewardsOwner.Addrs)
 	return &txs.AddValidatorTx{
@@ -361,8 +365,9 @@ func (b *builder) NewAddSubnetValidatorTx(
 	vdr *validator.SubnetValidator,
 	options ...common.Option,
 ) (*txs.AddSubnetValidatorTx, error) {
+	fee := b.backend.AddSubnetValidatorFee()
 	toBurn := map[ids.ID]uint64{
-		b.backend.AVAXAssetID(): b.backend.AddSubnetValidatorFee(),
+		b.backend.AVAXAssetID(): fee,
 	}
 	toStake := map[ids.ID]uint64{}
 	ops := common.NewOptions(options)
@@ -370,6 +375,7 @@ func (b *builder) NewAddSubnetValidatorTx(
 	if err != nil {
 		return nil, err
 	}
+	ops.RecordSpend(inputs, outputs, nil, fee)
 
 	subnetAuth, err := b.authorizeSubnet(vdr.Subnet, ops)
 	if err != nil {
@@ -394,8 +400,9 @@ func (b *builder) NewRemoveSubnetValidatorTx(
 	subnetID ids.ID,
 	options ...common.Option,
 ) (*txs.RemoveSubnetValidatorTx, error) {
+	fee := b.backend.BaseTxFee()
 	toBurn := map[ids.ID]uint64{
-		b.backend.AVAXAssetID(): b.backend.BaseTxFee(),
+		b.backend.AVAXAssetID(): fee,
 	}
 	toStake := map[ids.ID]uint64{}
 	ops := common.NewOptions(options)
@@ -403,6 +410,7 @@ func (b *builder) NewRemoveSubnetValidatorTx(
 	if err != nil {
 		return nil, err
 	}
+	ops.RecordSpend(inputs, outputs, nil, fee)
 
 	subnetAuth, err := b.authorizeSubnet(subnetID, ops)
 	if err != nil {
@@ -429,8 +437,9 @@ func (b *builder) NewAddDelegatorTx(
 	options ...common.Option,
 ) (*txs.AddDelegatorTx, error) {
 	avaxAssetID := b.backend.AVAXAssetID()
+	fee := b.backend.AddPrimaryNetworkDelegatorFee()
 	toBurn := map[ids.ID]uint64{
-		avaxAssetID: b.backend.AddPrimaryNetworkDelegatorFee(),
+		avaxAssetID: fee,
 	}
 	toStake := map[ids.ID]uint64{
 		b.backend.AVAXAssetID(): vdr.Wght,
@@ -440,6 +449,7 @@ func (b *builder) NewAddDelegatorTx(
 	if err != nil {
 		return nil, err
 	}
+	ops.RecordSpend(inputs, baseOutputs, stakeOutputs, fee)
 
 	ids.SortShortIDs(rewardsOwner.Addrs)
 	return &txs.AddDelegatorTx{
@@ -464,8 +474,9 @@ func (b *builder) NewCreateChainTx(
 	chainName string,
 	options ...common.Option,
 ) (*txs.CreateChainTx, error) {
+	fee := b.backend.CreateBlockchainTxFee()
 	toBurn := map[ids.ID]uint64{
-		b.backend.AVAXAssetID(): b.backend.CreateBlockchainTxFee(),
+		b.backend.AVAXAssetID(): fee,
 	}
 	toStake := map[ids.ID]uint64{}
 	ops := common.NewOptions(options)
@@ -473,6 +484,7 @@ func (b *builder) NewCreateChainTx(
 	if err != nil {
 		return nil, err
 	}
+	ops.RecordSpend(inputs, outputs, nil, fee)
 
 	subnetAuth, err := b.authorizeSubnet(subnetID, ops)
 	if err != nil {
@@ -501,8 +513,9 @@ func (b *builder) NewCreateSubnetTx(
 	owner *secp256k1fx.OutputOwners,
 	options ...common.Option,
 ) (*txs.CreateSubnetTx, error) {
+	fee := b.backend.CreateSubnetTxFee()
 	toBurn := map[ids.ID]uint64{
-		b.backend.AVAXAssetID(): b.backend.CreateSubnetTxFee(),
+		b.backend.AVAXAssetID(): fee,
 	}
 	toStake := map[ids.ID]uint64{}
 	ops := common.NewOptions(options)
@@ -510,6 +523,7 @@ func (b *builder) NewCreateSubnetTx(
 	if err != nil {
 		return nil, err
 	}
+	ops.RecordSpend(inputs, outputs, nil, fee)
 
 	ids.SortShortIDs(owner.Addrs)
 	return &txs.CreateSubnetTx{
@@ -605,6 +619,10 @@ func (b *builder) NewImportTx(
 		}
 		delete(importedAmounts, avaxAssetID)
 	}
+	consumedInputs := make([]*avax.TransferableInput, 0, len(importedInputs)+len(inputs))
+	consumedInputs = append(consumedInputs, importedInputs...)
+	consumedInputs = append(consumedInputs, inputs...)
+	ops.RecordSpend(consumedInputs, outputs, nil, txFee)
 
 	for assetID, amount := range importedAmounts {
 		outputs = append(outputs, &avax.TransferableOutput{
@@ -635,8 +653,9 @@ func (b *builder) NewExportTx(
 	outputs []*avax.TransferableOutput,
 	options ...common.Option,
 ) (*txs.ExportTx, error) {
+	fee := b.backend.BaseTxFee()
 	toBurn := map[ids.ID]uint64{
-		b.backend.AVAXAssetID(): b.backend.BaseTxFee(),
+		b.backend.AVAXAssetID(): fee,
 	}
 	for _, out := range outputs {
 		assetID := out.AssetID()
@@ -653,6 +672,7 @@ func (b *builder) NewExportTx(
 	if err != nil {
 		return nil, err
 	}
+	ops.RecordSpend(inputs, changeOutputs, nil, fee)
 
 	avax.SortTransferableOutputs(outputs, txs.Codec) // sort exported outputs
 	return &txs.ExportTx{
@@ -685,8 +705,9 @@ func (b *builder) NewTransformSubnetTx(
 	uptimeRequirement uint32,
 	options ...common.Option,
 ) (*txs.TransformSubnetTx, error) {
+	fee := b.backend.TransformSubnetTxFee()
 	toBurn := map[ids.ID]uint64{
-		b.backend.AVAXAssetID(): b.backend.TransformSubnetTxFee(),
+		b.backend.AVAXAssetID(): fee,
 		assetID:                 maxSupply - initialSupply,
 	}
 	toStake := map[ids.ID]uint64{}
@@ -695,6 +716,7 @@ func (b *builder) NewTransformSubnetTx(
 	if err != nil {
 		return nil, err
 	}
+	ops.RecordSpend(inputs, outputs, nil, fee)
 
 	subnetAuth, err := b.authorizeSubnet(subnetID, ops)
 	if err != nil {
@@ -737,11 +759,12 @@ func (b *builder) NewAddPermissionlessValidatorTx(
 	options ...common.Option,
 ) (*txs.AddPermissionlessValidatorTx, error) {
 	avaxAssetID := b.backend.AVAXAssetID()
-	toBurn := map[ids.ID]uint64{}
-	if vdr.Subnet == constants.PrimaryNetworkID {
-		toBurn[avaxAssetID] = b.backend.AddPrimaryNetworkValidatorFee()
-	} else {
-		toBurn[avaxAssetID] = b.backend.AddSubnetValidatorFee()
+	fee := b.backend.AddPrimaryNetworkValidatorFee()
+	if vdr.Subnet != constants.PrimaryNetworkID {
+		fee = b.backend.AddSubnetValidatorFee()
+	}
+	toBurn := map[ids.ID]uint64{
+		avaxAssetID: fee,
 	}
 	toStake := map[ids.ID]uint64{
 		assetID: vdr.Wght,
@@ -751,6 +774,7 @@ func (b *builder) NewAddPermissionlessValidatorTx(
 	if err != nil {
 		return nil, err
 	}
+	ops.RecordSpend(inputs, baseOutputs, stakeOutputs, fee)
 
 	ids.SortShortIDs(validationRewardsOwner.Addrs)
 	ids.SortShortIDs(delegationRewardsOwner.Addrs)
@@ -779,11 +803,12 @@ func (b *builder) NewAddPermissionlessDelegatorTx(
 	options ...common.Option,
 ) (*txs.AddPermissionlessDelegatorTx, error) {
 	avaxAssetID := b.backend.AVAXAssetID()
-	toBurn := map[ids.ID]uint64{}
-	if vdr.Subnet == constants.PrimaryNetworkID {
-		toBurn[avaxAssetID] = b.backend.AddPrimaryNetworkDelegatorFee()
-	} else {
-		toBurn[avaxAssetID] = b.backend.AddSubnetDelegatorFee()
+	fee := b.backend.AddPrimaryNetworkDelegatorFee()
+	if vdr.Subnet != constants.PrimaryNetworkID {
+		fee = b.backend.AddSubnetDelegatorFee()
+	}
+	toBurn := map[ids.ID]uint64{
+		avaxAssetID: fee,
 	}
 	toStake := map[ids.ID]uint64{
 		assetID: vdr.Wght,
@@ -793,6 +818,7 @@ func (b *builder) NewAddPermissionlessDelegatorTx(
 	if err != nil {
 		return nil, err
 	}
+	ops.RecordSpend(inputs, baseOutputs, stakeOutputs, fee)
 
 	ids.SortShortIDs(rewardsOwner.Addrs)
 	return &txs.AddPermissionlessDelegatorTx{
@@ -869,6 +895,9 @@ func (b *builder) getBalance(
 //     place into the staked outputs. First locked UTXOs are attempted to be
 //     used for these funds, and then unlocked UTXOs will be attempted to be
 //     used. There is no preferential ordering on the unlock times.
+//
+// The UTXOs of each asset are chosen by the coin selection strategy of
+// [options].
 func (b *builder) spend(
 	amountsToBurn map[ids.ID]uint64,
 	amountsToStake map[ids.ID]uint64,
@@ -886,6 +915,7 @@ func (b *builder) spend(
 
 	addrs := options.Addresses(b.addrs)
 	minIssuanceTime := options.MinIssuanceTime()
+	coinSelection := options.CoinSelection()
 
 	addr, ok := addrs.Peek()
 	if !ok {
@@ -896,14 +926,21 @@ func (b *builder) spend(
 		Addrs:     []ids.ShortID{addr},
 	})
 
-	// Iterate over the locked UTXOs
+	// Collect the locked UTXOs that can be staked
+	var (
+		lockedUTXOs    []*avax.UTXO
+		lockedOuts     []*stakeable.LockOut
+		lockedTransfer []*secp256k1fx.TransferOutput
+		lockedSigs     [][]uint32
+		lockedAssetIDs []ids.ID
+		lockedAmounts  []uint64
+	)
 	for _, utxo := range utxos {
 		assetID := utxo.AssetID()
-		remainingAmountToStake := amountsToStake[assetID]
 
-		// If we have staked enough of the asset, then we have no need burn
-		// more.
-		if remainingAmountToStake == 0 {
+		// If we don't need to stake the asset, then we have no need to spend
+		// it here.
+		if amountsToStake[assetID] == 0 {
 			continue
 		}
 
@@ -931,6 +968,22 @@ func (b *builder) spend(
 			continue
 		}
 
+		lockedUTXOs = append(lockedUTXOs, utxo)
+		lockedOuts = append(lockedOuts, lockedOut)
+		lockedTransfer = append(lockedTransfer, out)
+		lockedSigs = append(lockedSigs, inputSigIndices)
+		lockedAssetIDs = append(lockedAssetIDs, assetID)
+		lockedAmounts = append(lockedAmounts, out.Amt)
+	}
+
+	// Consume the selected locked UTXOs
+	selected := common.SelectUTXOs(coinSelection, lockedAssetIDs, lockedAmounts, amountsToStake)
+	for _, i := range selected {
+		utxo := lockedUTXOs[i]
+		lockedOut := lockedOuts[i]
+		out := lockedTransfer[i]
+		assetID := utxo.AssetID()
+
 		inputs = append(inputs, &avax.TransferableInput{
 			UTXOID: utxo.UTXOID,
 			Asset:  utxo.Asset,
@@ -939,7 +992,7 @@ func (b *builder) spend(
 				TransferableIn: &secp256k1fx.TransferInput{
 					Amt: out.Amt,
 					Input: secp256k1fx.Input{
-						SigIndices: inputSigIndices,
+						SigIndices: lockedSigs[i],
 					},
 				},
 			},
@@ -947,21 +1000,23 @@ func (b *builder) spend(
 
 		// Stake any value that should be staked
 		amountToStake := math.Min64(
-			remainingAmountToStake, // Amount we still need to stake
-			out.Amt,                // Amount available to stake
+			amountsToStake[assetID], // Amount we still need to stake
+			out.Amt,                 // Amount available to stake
 		)
 
 		// Add the output to the staked outputs
-		stakeOutputs = append(stakeOutputs, &avax.TransferableOutput{
-			Asset: utxo.Asset,
-			Out: &stakeable.LockOut{
-				Locktime: lockedOut.Locktime,
-				TransferableOut: &secp256k1fx.TransferOutput{
-					Amt:          amountToStake,
-					OutputOwners: out.OutputOwners,
+		if amountToStake > 0 {
+			stakeOutputs = append(stakeOutputs, &avax.TransferableOutput{
+				Asset: utxo.Asset,
+				Out: &stakeable.LockOut{
+					Locktime: lockedOut.Locktime,
+					TransferableOut: &secp256k1fx.TransferOutput{
+						Amt:          amountToStake,
+						OutputOwners: out.OutputOwners,
+					},
 				},
-			},
-		})
+			})
+		}
 
 		amountsToStake[assetID] -= amountToStake
 		if remainingAmount := out.Amt - amountToStake; remainingAmount > 0 {
@@ -979,15 +1034,32 @@ func (b *builder) spend(
 		}
 	}
 
-	// Iterate over the unlocked UTXOs
+	// Collect the unlocked UTXOs that can be burned or staked
+	amountsToConsume := make(map[ids.ID]uint64, len(amountsToBurn)+len(amountsToStake))
+	for assetID, amount := range amountsToBurn {
+		amountsToConsume[assetID] = amount
+	}
+	for assetID, amount := range amountsToStake {
+		amountToConsume, err := math.Add64(amountsToConsume[assetID], amount)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		amountsToConsume[assetID] = amountToConsume
+	}
+
+	var (
+		unlockedUTXOs    []*avax.UTXO
+		unlockedOuts     []*secp256k1fx.TransferOutput
+		unlockedSigs     [][]uint32
+		unlockedAssetIDs []ids.ID
+		unlockedAmounts  []uint64
+	)
 	for _, utxo := range utxos {
 		assetID := utxo.AssetID()
-		remainingAmountToStake := amountsToStake[assetID]
-		remainingAmountToBurn := amountsToBurn[assetID]
 
-		// If we have consumed enough of the asset, then we have no need burn
-		// more.
-		if remainingAmountToStake == 0 && remainingAmountToBurn == 0 {
+		// If we don't need to consume the asset, then we have no need to
+		// spend it here.
+		if amountsToConsume[assetID] == 0 {
 			continue
 		}
 
@@ -1012,29 +1084,43 @@ func (b *builder) spend(
 			continue
 		}
 
+		unlockedUTXOs = append(unlockedUTXOs, utxo)
+		unlockedOuts = append(unlockedOuts, out)
+		unlockedSigs = append(unlockedSigs, inputSigIndices)
+		unlockedAssetIDs = append(unlockedAssetIDs, assetID)
+		unlockedAmounts = append(unlockedAmounts, out.Amt)
+	}
+
+	// Consume the selected unlocked UTXOs
+	selected = common.SelectUTXOs(coinSelection, unlockedAssetIDs, unlockedAmounts, amountsToConsume)
+	for _, i := range selected {
+		utxo := unlockedUTXOs[i]
+		out := unlockedOuts[i]
+		assetID := utxo.AssetID()
+
 		inputs = append(inputs, &avax.TransferableInput{
 			UTXOID: utxo.UTXOID,
 			Asset:  utxo.Asset,
 			In: &secp256k1fx.TransferInput{
 				Amt: out.Amt,
 				Input: secp256k1fx.Input{
-					SigIndices: inputSigIndices,
+					SigIndices: unlockedSigs[i],
 				},
 			},
 		})
 
 		// Burn any value that should be burned
 		amountToBurn := math.Min64(
-			remainingAmountToBurn, // Amount we still need to burn
-			out.Amt,               // Amount available to burn
+			amountsToBurn[assetID], // Amount we still need to burn
+			out.Amt,                // Amount available to burn
 		)
 		amountsToBurn[assetID] -= amountToBurn
 
 		amountAvalibleToStake := out.Amt - amountToBurn
 		// Burn any value that should be burned
 		amountToStake := math.Min64(
-			remainingAmountToStake, // Amount we still need to stake
-			amountAvalibleToStake,  // Amount available to stake
+			amountsToStake[assetID], // Amount we still need to stake
+			amountAvalibleToStake,   // Amount available to stake
 		)
 		amountsToStake[assetID] -= amountToStake
 		if amountToStake > 0 {
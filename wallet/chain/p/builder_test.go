@@ -0,0 +1,143 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package p
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/utils/constants"
+	"github.com/dim4egster/qmallgo/utils/units"
+	"github.com/dim4egster/qmallgo/vms/components/avax"
+	"github.com/dim4egster/qmallgo/vms/platformvm/stakeable"
+	"github.com/dim4egster/qmallgo/vms/platformvm/validator"
+	"github.com/dim4egster/qmallgo/vms/secp256k1fx"
+	"github.com/dim4egster/qmallgo/wallet/subnet/primary/common"
+)
+
+func TestSpendCoinSelection(t *testing.T) {
+	var (
+		avaxAssetID = ids.GenerateTestID()
+		addr        = ids.GenerateTestShortID()
+		owners      = secp256k1fx.OutputOwners{
+			Threshold: 1,
+			Addrs:     []ids.ShortID{addr},
+		}
+		lockedUntil uint64 = 100
+		now         uint64 = 10
+	)
+	newUTXO := func(amount uint64, locktime uint64) *avax.UTXO {
+		var out avax.TransferableOut = &secp256k1fx.TransferOutput{
+			Amt:          amount,
+			OutputOwners: owners,
+		}
+		if locktime != 0 {
+			out = &stakeable.LockOut{
+				Locktime:        locktime,
+				TransferableOut: out,
+			}
+		}
+		return &avax.UTXO{
+			UTXOID: avax.UTXOID{TxID: ids.GenerateTestID()},
+			Asset:  avax.Asset{ID: avaxAssetID},
+			Out:    out,
+		}
+	}
+	utxos := []*avax.UTXO{
+		newUTXO(units.Avax, 0),
+		newUTXO(2*units.Avax, lockedUntil),
+		newUTXO(5*units.Avax, 0),
+		newUTXO(8*units.Avax, lockedUntil),
+	}
+	backend := &testBackend{
+		Context: NewContext(constants.UnitTestID, avaxAssetID, units.MilliAvax, units.MilliAvax, units.MilliAvax, units.MilliAvax, units.MilliAvax, units.MilliAvax, units.MilliAvax, units.MilliAvax),
+		utxos: map[ids.ID][]*avax.UTXO{
+			constants.PlatformChainID: utxos,
+		},
+	}
+	addrs := ids.ShortSet{}
+	addrs.Add(addr)
+	builder := NewBuilder(addrs, backend)
+
+	vdr := &validator.Validator{
+		NodeID: ids.GenerateTestNodeID(),
+		Start:  now,
+		End:    lockedUntil,
+		Wght:   3 * units.Avax,
+	}
+
+	tests := []struct {
+		name                string
+		selection           common.CoinSelection
+		expectedInputs      []*avax.UTXO
+		expectedChangeCount int
+	}{
+		{
+			// Both locked UTXOs are needed to cover the stake in backend
+			// order.
+			name:                "backend order",
+			selection:           common.BackendOrder,
+			expectedInputs:      []*avax.UTXO{utxos[0], utxos[1], utxos[3]},
+			expectedChangeCount: 2,
+		},
+		{
+			// The largest locked UTXO covers the stake and the largest unlocked
+			// UTXO covers the fee.
+			name:                "largest first",
+			selection:           common.LargestFirst,
+			expectedInputs:      []*avax.UTXO{utxos[2], utxos[3]},
+			expectedChangeCount: 2,
+		},
+		{
+			name:                "smallest sufficient",
+			selection:           common.SmallestSufficient,
+			expectedInputs:      []*avax.UTXO{utxos[0], utxos[3]},
+			expectedChangeCount: 2,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			var summary common.SpendSummary
+			utx, err := builder.NewAddValidatorTx(
+				vdr,
+				&owners,
+				0,
+				common.WithMinIssuanceTime(now),
+				common.WithCoinSelection(test.selection),
+				common.WithDryRun(&summary),
+			)
+			require.NoError(err)
+
+			expectedInputIDs := ids.Set{}
+			for _, utxo := range test.expectedInputs {
+				expectedInputIDs.Add(utxo.InputID())
+			}
+			require.Equal(expectedInputIDs, utx.InputIDs())
+
+			require.Equal(utx.Ins, summary.Inputs)
+			require.Equal(utx.Outs, summary.ChangeOutputs)
+			require.Equal(utx.StakeOuts, summary.StakeOutputs)
+			require.Len(summary.ChangeOutputs, test.expectedChangeCount)
+			require.Equal(units.MilliAvax, summary.Fee)
+
+			// The fee is the difference between the consumed and produced
+			// value.
+			var consumed, produced uint64
+			for _, in := range summary.Inputs {
+				consumed += in.In.Amount()
+			}
+			for _, out := range summary.ChangeOutputs {
+				produced += out.Out.Amount()
+			}
+			for _, out := range summary.StakeOutputs {
+				produced += out.Out.Amount()
+			}
+			require.Equal(summary.Fee, consumed-produced)
+		})
+	}
+}
@@ -0,0 +1,411 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package p
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	stdcontext "context"
+
+	"github.com/dim4egster/qmallgo/database"
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/utils/constants"
+	"github.com/dim4egster/qmallgo/utils/crypto"
+	"github.com/dim4egster/qmallgo/vms/components/avax"
+	"github.com/dim4egster/qmallgo/vms/components/verify"
+	"github.com/dim4egster/qmallgo/vms/platformvm/stakeable"
+	"github.com/dim4egster/qmallgo/vms/platformvm/txs"
+	"github.com/dim4egster/qmallgo/vms/secp256k1fx"
+	"github.com/dim4egster/qmallgo/wallet/subnet/primary/common"
+)
+
+var (
+	_ SignerBackend = &PartialTx{}
+	_ txs.Visitor   = &partialTxVisitor{}
+
+	errMismatchedTx          = errors.New("partial txs don't sign the same tx")
+	errMismatchedCredentials = errors.New("partial txs have mismatched credentials")
+	errConflictingSignatures = errors.New("partial txs have conflicting signatures")
+	errNoPartialTxs          = errors.New("no partial txs to merge")
+	errMismatchedSignerHints = errors.New("signer hints don't match the tx credentials")
+	errNilPartialTx          = errors.New("nil tx is not valid")
+)
+
+// PartialUTXO is a UTXO consumed by a partially signed transaction, along
+// with the chain it is consumed from.
+type PartialUTXO struct {
+	ChainID ids.ID     `serialize:"true" json:"chainID"`
+	UTXO    *avax.UTXO `serialize:"true" json:"utxo"`
+}
+
+// PartialTx is a transaction that may be missing signatures, bundled with
+// everything needed to sign it without access to a node.
+//
+// A PartialTx implements SignerBackend, so it can be signed offline with:
+//
+//	NewSigner(kc, partialTx).Sign(ctx, partialTx.Tx)
+//
+// Once every signer has signed their copy, the copies can be combined with
+// MergePartialTxs and the resulting Tx issued.
+type PartialTx struct {
+	// Tx is the transaction along with the signatures added so far.
+	Tx *txs.Tx `serialize:"true" json:"tx"`
+	// UTXOs are the UTXOs consumed by [Tx].
+	UTXOs []*PartialUTXO `serialize:"true" json:"utxos"`
+	// SubnetTxs are the txs that created the subnets [Tx] must be authorized
+	// by.
+	SubnetTxs []*txs.Tx `serialize:"true" json:"subnetTxs"`
+	// Signers contains, for each credential of [Tx], the address that is
+	// expected to produce each of its signatures.
+	Signers [][]ids.ShortID `serialize:"true" json:"signers"`
+}
+
+// NewPartialTx exports [utx] along with the UTXOs, subnets, and signer
+// addresses that are needed to sign it. All the UTXOs consumed by [utx] must
+// be available in [backend].
+func NewPartialTx(ctx stdcontext.Context, backend SignerBackend, utx txs.UnsignedTx) (*PartialTx, error) {
+	tx := &txs.Tx{Unsigned: utx}
+	if err := initializeTx(tx); err != nil {
+		return nil, err
+	}
+
+	v := &partialTxVisitor{
+		backend: backend,
+		ctx:     ctx,
+		ptx:     &PartialTx{Tx: tx},
+	}
+	if err := utx.Visit(v); err != nil {
+		return nil, err
+	}
+	return v.ptx, nil
+}
+
+// ParsePartialTx parses a partially signed transaction produced by Bytes.
+func ParsePartialTx(b []byte) (*PartialTx, error) {
+	ptx := &PartialTx{}
+	if _, err := txs.Codec.Unmarshal(b, ptx); err != nil {
+		return nil, fmt.Errorf("couldn't parse partial tx: %w", err)
+	}
+	if ptx.Tx == nil || ptx.Tx.Unsigned == nil {
+		return nil, errNilPartialTx
+	}
+	if err := initializeTx(ptx.Tx); err != nil {
+		return nil, err
+	}
+	for _, subnetTx := range ptx.SubnetTxs {
+		if subnetTx == nil || subnetTx.Unsigned == nil {
+			return nil, errNilPartialTx
+		}
+		if err := initializeTx(subnetTx); err != nil {
+			return nil, err
+		}
+	}
+	return ptx, nil
+}
+
+// Bytes returns the binary representation of this partially signed
+// transaction.
+func (p *PartialTx) Bytes() ([]byte, error) {
+	return txs.Codec.Marshal(txs.Version, p)
+}
+
+func (p *PartialTx) GetUTXO(_ stdcontext.Context, chainID, utxoID ids.ID) (*avax.UTXO, error) {
+	for _, utxo := range p.UTXOs {
+		if utxo.ChainID == chainID && utxo.UTXO.InputID() == utxoID {
+			return utxo.UTXO, nil
+		}
+	}
+	return nil, database.ErrNotFound
+}
+
+func (p *PartialTx) GetTx(_ stdcontext.Context, txID ids.ID) (*txs.Tx, error) {
+	for _, subnetTx := range p.SubnetTxs {
+		if subnetTx.ID() == txID {
+			return subnetTx, nil
+		}
+	}
+	return nil, database.ErrNotFound
+}
+
+// MissingSigners returns the addresses that still need to sign [Tx]. Each
+// address is returned at most once.
+func (p *PartialTx) MissingSigners() ([]ids.ShortID, error) {
+	statuses, err := p.SigningStatus()
+	if err != nil {
+		return nil, err
+	}
+	return common.MissingSigners(statuses), nil
+}
+
+// SigningStatus verifies the signatures added to [Tx] so far and reports
+// which signature slots of each credential have been filled.
+func (p *PartialTx) SigningStatus() ([]*common.CredentialStatus, error) {
+	creds, err := p.credentials()
+	if err != nil {
+		return nil, err
+	}
+	return common.SigningStatus(p.Tx.Unsigned.Bytes(), p.Signers, creds)
+}
+
+// credentials returns the credentials of [Tx], or nil if [Tx] hasn't been
+// signed yet.
+func (p *PartialTx) credentials() ([]*secp256k1fx.Credential, error) {
+	if len(p.Tx.Creds) == 0 {
+		return nil, nil
+	}
+	if len(p.Tx.Creds) != len(p.Signers) {
+		return nil, errMismatchedSignerHints
+	}
+
+	creds := make([]*secp256k1fx.Credential, len(p.Tx.Creds))
+	for credIndex, credIntf := range p.Tx.Creds {
+		cred, ok := credIntf.(*secp256k1fx.Credential)
+		if !ok {
+			return nil, errUnknownCredentialType
+		}
+		if len(cred.Sigs) != len(p.Signers[credIndex]) {
+			return nil, errMismatchedSignerHints
+		}
+		creds[credIndex] = cred
+	}
+	return creds, nil
+}
+
+// MergePartialTxs combines the signatures of copies of the same partially
+// signed transaction. The provided partial txs are not modified.
+func MergePartialTxs(ptxs ...*PartialTx) (*PartialTx, error) {
+	if len(ptxs) == 0 {
+		return nil, errNoPartialTxs
+	}
+	for _, ptx := range ptxs {
+		if ptx == nil || ptx.Tx == nil || ptx.Tx.Unsigned == nil {
+			return nil, errNilPartialTx
+		}
+	}
+
+	first := ptxs[0]
+	unsignedBytes := first.Tx.Unsigned.Bytes()
+	merged := &PartialTx{
+		Tx:        &txs.Tx{Unsigned: first.Tx.Unsigned},
+		UTXOs:     first.UTXOs,
+		SubnetTxs: first.SubnetTxs,
+		Signers:   first.Signers,
+	}
+
+	var mergedCreds []*secp256k1fx.Credential
+	for _, ptx := range ptxs {
+		if !bytes.Equal(ptx.Tx.Unsigned.Bytes(), unsignedBytes) {
+			return nil, errMismatchedTx
+		}
+		creds, err := ptx.credentials()
+		if err != nil {
+			return nil, err
+		}
+		if creds == nil {
+			continue
+		}
+		if mergedCreds == nil {
+			mergedCreds = make([]*secp256k1fx.Credential, len(creds))
+			for credIndex, cred := range creds {
+				mergedCreds[credIndex] = &secp256k1fx.Credential{
+					Sigs: make([][crypto.SECP256K1RSigLen]byte, len(cred.Sigs)),
+				}
+			}
+		}
+		if len(creds) != len(mergedCreds) {
+			return nil, errMismatchedCredentials
+		}
+
+		for credIndex, cred := range creds {
+			mergedSigs := mergedCreds[credIndex].Sigs
+			if len(cred.Sigs) != len(mergedSigs) {
+				return nil, errMismatchedCredentials
+			}
+			for sigIndex, sig := range cred.Sigs {
+				switch mergedSig := mergedSigs[sigIndex]; {
+				case sig == emptySig:
+				case mergedSig == emptySig:
+					mergedSigs[sigIndex] = sig
+				case mergedSig != sig:
+					return nil, fmt.Errorf("%w: credential %d signature %d",
+						errConflictingSignatures,
+						credIndex,
+						sigIndex,
+					)
+				}
+			}
+		}
+	}
+
+	for _, cred := range mergedCreds {
+		merged.Tx.Creds = append(merged.Tx.Creds, cred)
+	}
+	return merged, initializeTx(merged.Tx)
+}
+
+func initializeTx(tx *txs.Tx) error {
+	unsignedBytes, err := txs.Codec.Marshal(txs.Version, &tx.Unsigned)
+	if err != nil {
+		return fmt.Errorf("couldn't marshal unsigned tx: %w", err)
+	}
+	signedBytes, err := txs.Codec.Marshal(txs.Version, tx)
+	if err != nil {
+		return fmt.Errorf("couldn't marshal tx: %w", err)
+	}
+	tx.Initialize(unsignedBytes, signedBytes)
+	return nil
+}
+
+// partialTxVisitor collects the UTXOs, subnets, and signer addresses needed
+// to sign a transaction. The credentials are visited in the same order as the
+// signerVisitor.
+type partialTxVisitor struct {
+	backend SignerBackend
+	ctx     stdcontext.Context
+	ptx     *PartialTx
+}
+
+func (*partialTxVisitor) AdvanceTimeTx(*txs.AdvanceTimeTx) error         { return errUnsupportedTxType }
+func (*partialTxVisitor) RewardValidatorTx(*txs.RewardValidatorTx) error { return errUnsupportedTxType }
+
+func (v *partialTxVisitor) AddValidatorTx(tx *txs.AddValidatorTx) error {
+	return v.addInputs(constants.PlatformChainID, tx.Ins)
+}
+
+func (v *partialTxVisitor) AddSubnetValidatorTx(tx *txs.AddSubnetValidatorTx) error {
+	if err := v.addInputs(constants.PlatformChainID, tx.Ins); err != nil {
+		return err
+	}
+	return v.addSubnetAuth(tx.Validator.Subnet, tx.SubnetAuth)
+}
+
+func (v *partialTxVisitor) AddDelegatorTx(tx *txs.AddDelegatorTx) error {
+	return v.addInputs(constants.PlatformChainID, tx.Ins)
+}
+
+func (v *partialTxVisitor) CreateChainTx(tx *txs.CreateChainTx) error {
+	if err := v.addInputs(constants.PlatformChainID, tx.Ins); err != nil {
+		return err
+	}
+	return v.addSubnetAuth(tx.SubnetID, tx.SubnetAuth)
+}
+
+func (v *partialTxVisitor) CreateSubnetTx(tx *txs.CreateSubnetTx) error {
+	return v.addInputs(constants.PlatformChainID, tx.Ins)
+}
+
+func (v *partialTxVisitor) ImportTx(tx *txs.ImportTx) error {
+	if err := v.addInputs(constants.PlatformChainID, tx.Ins); err != nil {
+		return err
+	}
+	return v.addInputs(tx.SourceChain, tx.ImportedInputs)
+}
+
+func (v *partialTxVisitor) ExportTx(tx *txs.ExportTx) error {
+	return v.addInputs(constants.PlatformChainID, tx.Ins)
+}
+
+func (v *partialTxVisitor) RemoveSubnetValidatorTx(tx *txs.RemoveSubnetValidatorTx) error {
+	if err := v.addInputs(constants.PlatformChainID, tx.Ins); err != nil {
+		return err
+	}
+	return v.addSubnetAuth(tx.Subnet, tx.SubnetAuth)
+}
+
+func (v *partialTxVisitor) TransformSubnetTx(tx *txs.TransformSubnetTx) error {
+	if err := v.addInputs(constants.PlatformChainID, tx.Ins); err != nil {
+		return err
+	}
+	return v.addSubnetAuth(tx.Subnet, tx.SubnetAuth)
+}
+
+func (v *partialTxVisitor) AddPermissionlessValidatorTx(tx *txs.AddPermissionlessValidatorTx) error {
+	return v.addInputs(constants.PlatformChainID, tx.Ins)
+}
+
+func (v *partialTxVisitor) AddPermissionlessDelegatorTx(tx *txs.AddPermissionlessDelegatorTx) error {
+	return v.addInputs(constants.PlatformChainID, tx.Ins)
+}
+
+func (v *partialTxVisitor) addInputs(sourceChainID ids.ID, ins []*avax.TransferableInput) error {
+	for _, transferInput := range ins {
+		input, ok := transferInput.In.(*secp256k1fx.TransferInput)
+		if !ok {
+			return errUnknownInputType
+		}
+
+		utxoID := transferInput.InputID()
+		utxo, err := v.backend.GetUTXO(v.ctx, sourceChainID, utxoID)
+		if err != nil {
+			return fmt.Errorf("failed to fetch UTXO %q: %w", utxoID, err)
+		}
+
+		outIntf := utxo.Out
+		if stakeableOut, ok := outIntf.(*stakeable.LockOut); ok {
+			outIntf = stakeableOut.TransferableOut
+		}
+
+		out, ok := outIntf.(*secp256k1fx.TransferOutput)
+		if !ok {
+			return errUnknownOutputType
+		}
+
+		signers, err := getSignerAddrs(out.Addrs, input.SigIndices)
+		if err != nil {
+			return err
+		}
+		v.ptx.UTXOs = append(v.ptx.UTXOs, &PartialUTXO{
+			ChainID: sourceChainID,
+			UTXO:    utxo,
+		})
+		v.ptx.Signers = append(v.ptx.Signers, signers)
+	}
+	return nil
+}
+
+func (v *partialTxVisitor) addSubnetAuth(subnetID ids.ID, subnetAuth verify.Verifiable) error {
+	subnetInput, ok := subnetAuth.(*secp256k1fx.Input)
+	if !ok {
+		return errUnknownSubnetAuthType
+	}
+
+	subnetTx, err := v.backend.GetTx(v.ctx, subnetID)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to fetch subnet %q: %w",
+			subnetID,
+			err,
+		)
+	}
+	subnet, ok := subnetTx.Unsigned.(*txs.CreateSubnetTx)
+	if !ok {
+		return errWrongTxType
+	}
+
+	owner, ok := subnet.Owner.(*secp256k1fx.OutputOwners)
+	if !ok {
+		return errUnknownOwnerType
+	}
+
+	signers, err := getSignerAddrs(owner.Addrs, subnetInput.SigIndices)
+	if err != nil {
+		return err
+	}
+	v.ptx.SubnetTxs = append(v.ptx.SubnetTxs, subnetTx)
+	v.ptx.Signers = append(v.ptx.Signers, signers)
+	return nil
+}
+
+func getSignerAddrs(addrs []ids.ShortID, sigIndices []uint32) ([]ids.ShortID, error) {
+	signers := make([]ids.ShortID, len(sigIndices))
+	for sigIndex, addrIndex := range sigIndices {
+		if addrIndex >= uint32(len(addrs)) {
+			return nil, errInvalidUTXOSigIndex
+		}
+		signers[sigIndex] = addrs[addrIndex]
+	}
+	return signers, nil
+}
@@ -0,0 +1,245 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package p
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	stdcontext "context"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dim4egster/qmallgo/database"
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/utils/constants"
+	"github.com/dim4egster/qmallgo/utils/crypto"
+	"github.com/dim4egster/qmallgo/utils/formatting"
+	"github.com/dim4egster/qmallgo/utils/units"
+	"github.com/dim4egster/qmallgo/vms/components/avax"
+	"github.com/dim4egster/qmallgo/vms/components/verify"
+	"github.com/dim4egster/qmallgo/vms/platformvm/txs"
+	"github.com/dim4egster/qmallgo/vms/platformvm/validator"
+	"github.com/dim4egster/qmallgo/vms/secp256k1fx"
+)
+
+var _ BuilderBackend = &testBackend{}
+
+type testBackend struct {
+	Context
+
+	utxos map[ids.ID][]*avax.UTXO
+	txs   map[ids.ID]*txs.Tx
+}
+
+func (b *testBackend) UTXOs(_ stdcontext.Context, sourceChainID ids.ID) ([]*avax.UTXO, error) {
+	return b.utxos[sourceChainID], nil
+}
+
+func (b *testBackend) GetUTXO(_ stdcontext.Context, chainID, utxoID ids.ID) (*avax.UTXO, error) {
+	for _, utxo := range b.utxos[chainID] {
+		if utxo.InputID() == utxoID {
+			return utxo, nil
+		}
+	}
+	return nil, database.ErrNotFound
+}
+
+func (b *testBackend) GetTx(_ stdcontext.Context, txID ids.ID) (*txs.Tx, error) {
+	tx, ok := b.txs[txID]
+	if !ok {
+		return nil, database.ErrNotFound
+	}
+	return tx, nil
+}
+
+// writePartialTx and readPartialTx store the partial tx as a hex encoded file,
+// the format used by main/partialtx.
+func writePartialTx(t *testing.T, path string, ptx *PartialTx) {
+	ptxBytes, err := ptx.Bytes()
+	require.NoError(t, err)
+	ptxHex, err := formatting.Encode(formatting.Hex, ptxBytes)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, []byte(ptxHex), 0o600))
+}
+
+func readPartialTx(t *testing.T, path string) *PartialTx {
+	ptxHex, err := os.ReadFile(path)
+	require.NoError(t, err)
+	ptxBytes, err := formatting.Decode(formatting.Hex, string(ptxHex))
+	require.NoError(t, err)
+	ptx, err := ParsePartialTx(ptxBytes)
+	require.NoError(t, err)
+	return ptx
+}
+
+// newTestSubnetValidatorTx builds a tx that consumes a UTXO held by a 2-of-2
+// multisig of the first two returned keys, and that is authorized by the
+// subnet owner, the third returned key.
+func newTestSubnetValidatorTx(t *testing.T) ([]*crypto.PrivateKeySECP256K1R, *testBackend, txs.UnsignedTx) {
+	require := require.New(t)
+
+	keys := make([]*crypto.PrivateKeySECP256K1R, 3)
+	for i := range keys {
+		keyIntf, err := (&crypto.FactorySECP256K1R{}).NewPrivateKey()
+		require.NoError(err)
+		keys[i] = keyIntf.(*crypto.PrivateKeySECP256K1R)
+	}
+
+	avaxAssetID := ids.GenerateTestID()
+	subnetTx := &txs.Tx{Unsigned: &txs.CreateSubnetTx{
+		BaseTx: txs.BaseTx{BaseTx: avax.BaseTx{
+			NetworkID:    constants.UnitTestID,
+			BlockchainID: constants.PlatformChainID,
+		}},
+		Owner: &secp256k1fx.OutputOwners{
+			Threshold: 1,
+			Addrs:     []ids.ShortID{keys[2].PublicKey().Address()},
+		},
+	}}
+	require.NoError(initializeTx(subnetTx))
+	subnetID := subnetTx.ID()
+
+	utxo := &avax.UTXO{
+		UTXOID: avax.UTXOID{TxID: ids.GenerateTestID()},
+		Asset:  avax.Asset{ID: avaxAssetID},
+		Out: &secp256k1fx.TransferOutput{
+			Amt: 10 * units.Avax,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 2,
+				Addrs: []ids.ShortID{
+					keys[0].PublicKey().Address(),
+					keys[1].PublicKey().Address(),
+				},
+			},
+		},
+	}
+	backend := &testBackend{
+		Context: NewContext(constants.UnitTestID, avaxAssetID, units.MilliAvax, units.MilliAvax, units.MilliAvax, units.MilliAvax, units.MilliAvax, units.MilliAvax, units.MilliAvax, units.MilliAvax),
+		utxos: map[ids.ID][]*avax.UTXO{
+			constants.PlatformChainID: {utxo},
+		},
+		txs: map[ids.ID]*txs.Tx{
+			subnetID: subnetTx,
+		},
+	}
+
+	addrs := ids.ShortSet{}
+	for _, key := range keys {
+		addrs.Add(key.PublicKey().Address())
+	}
+	utx, err := NewBuilder(addrs, backend).NewAddSubnetValidatorTx(&validator.SubnetValidator{
+		Validator: validator.Validator{
+			NodeID: ids.GenerateTestNodeID(),
+			Start:  1,
+			End:    2,
+			Wght:   1,
+		},
+		Subnet: subnetID,
+	})
+	require.NoError(err)
+	return keys, backend, utx
+}
+
+func TestPartialTxRoundTrip(t *testing.T) {
+	require := require.New(t)
+	ctx := stdcontext.Background()
+
+	keys, backend, utx := newTestSubnetValidatorTx(t)
+
+	// Export the unsigned tx on the online machine.
+	dir := t.TempDir()
+	unsignedPath := filepath.Join(dir, "unsigned.tx")
+	ptx, err := NewPartialTx(ctx, backend, utx)
+	require.NoError(err)
+	require.Equal(
+		[][]ids.ShortID{
+			{keys[0].PublicKey().Address(), keys[1].PublicKey().Address()},
+			{keys[2].PublicKey().Address()},
+		},
+		ptx.Signers,
+	)
+	writePartialTx(t, unsignedPath, ptx)
+
+	// Each key holder signs the tx offline.
+	signedPaths := make([]string, len(keys))
+	for i, key := range keys {
+		ptx := readPartialTx(t, unsignedPath)
+		require.NoError(NewSigner(secp256k1fx.NewKeychain(key), ptx).Sign(ctx, ptx.Tx))
+
+		missing, err := ptx.MissingSigners()
+		require.NoError(err)
+		require.Len(missing, len(keys)-1)
+		require.NotContains(missing, key.PublicKey().Address())
+
+		signedPaths[i] = filepath.Join(dir, "signed.tx."+key.PublicKey().Address().String())
+		writePartialTx(t, signedPaths[i], ptx)
+	}
+
+	// The signatures are merged and the tx can be issued.
+	signed := make([]*PartialTx, len(signedPaths))
+	for i, path := range signedPaths {
+		signed[i] = readPartialTx(t, path)
+	}
+	merged, err := MergePartialTxs(signed...)
+	require.NoError(err)
+	missing, err := merged.MissingSigners()
+	require.NoError(err)
+	require.Empty(missing)
+
+	expectedTx, err := NewSigner(secp256k1fx.NewKeychain(keys...), backend).SignUnsigned(ctx, utx)
+	require.NoError(err)
+	require.Equal(expectedTx.Bytes(), merged.Tx.Bytes())
+	require.Equal(expectedTx.ID(), merged.Tx.ID())
+}
+
+func TestMergePartialTxsErrors(t *testing.T) {
+	require := require.New(t)
+
+	_, err := MergePartialTxs()
+	require.ErrorIs(err, errNoPartialTxs)
+
+	newPartialTx := func(memo []byte, sig byte) *PartialTx {
+		ptx := &PartialTx{
+			Tx: &txs.Tx{
+				Unsigned: &txs.CreateSubnetTx{
+					BaseTx: txs.BaseTx{BaseTx: avax.BaseTx{Memo: memo}},
+					Owner:  &secp256k1fx.OutputOwners{},
+				},
+				Creds: []verify.Verifiable{
+					&secp256k1fx.Credential{
+						Sigs: [][crypto.SECP256K1RSigLen]byte{{sig}},
+					},
+				},
+			},
+			Signers: [][]ids.ShortID{{ids.GenerateTestShortID()}},
+		}
+		require.NoError(initializeTx(ptx.Tx))
+		return ptx
+	}
+
+	_, err = MergePartialTxs(newPartialTx(nil, 1), nil)
+	require.ErrorIs(err, errNilPartialTx)
+
+	_, err = MergePartialTxs(&PartialTx{}, newPartialTx(nil, 1))
+	require.ErrorIs(err, errNilPartialTx)
+
+	_, err = MergePartialTxs(newPartialTx(nil, 1), newPartialTx([]byte{1}, 1))
+	require.ErrorIs(err, errMismatchedTx)
+
+	_, err = MergePartialTxs(newPartialTx(nil, 1), newPartialTx(nil, 2))
+	require.ErrorIs(err, errConflictingSignatures)
+
+	merged, err := MergePartialTxs(newPartialTx(nil, 0), newPartialTx(nil, 2))
+	require.NoError(err)
+	require.Equal(
+		[]verify.Verifiable{
+			&secp256k1fx.Credential{
+				Sigs: [][crypto.SECP256K1RSigLen]byte{{2}},
+			},
+		},
+		merged.Tx.Creds,
+	)
+}
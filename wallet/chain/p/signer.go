@@ -7,9 +7,9 @@ import (
 	stdcontext "context"
 
 	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/utils/crypto/keychain"
 	"github.com/dim4egster/qmallgo/vms/components/avax"
 	"github.com/dim4egster/qmallgo/vms/platformvm/txs"
-	"github.com/dim4egster/qmallgo/vms/secp256k1fx"
 )
 
 var _ Signer = &txSigner{}
@@ -25,11 +25,11 @@ type SignerBackend interface {
 }
 
 type txSigner struct {
-	kc      *secp256k1fx.Keychain
+	kc      keychain.Keychain
 	backend SignerBackend
 }
 
-func NewSigner(kc *secp256k1fx.Keychain, backend SignerBackend) Signer {
+func NewSigner(kc keychain.Keychain, backend SignerBackend) Signer {
 	return &txSigner{
 		kc:      kc,
 		backend: backend,
@@ -0,0 +1,80 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package p
+
+import (
+	"fmt"
+
+	stdcontext "context"
+
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/utils/crypto/keychain"
+	"github.com/dim4egster/qmallgo/vms/platformvm/txs"
+	"github.com/dim4egster/qmallgo/wallet/subnet/primary/common"
+)
+
+// SigningSession coordinates the signing of a transaction whose keys are held
+// by different parties. Signatures can be added incrementally, either by
+// signing with a local keychain or by adding a copy of the partial tx that was
+// signed elsewhere. Every added signature is verified against the address
+// expected to produce it.
+type SigningSession struct {
+	ptx *PartialTx
+}
+
+// NewSigningSession starts a session to sign [ptx]. Any signatures already
+// present in [ptx] are kept.
+func NewSigningSession(ptx *PartialTx) (*SigningSession, error) {
+	if _, err := ptx.SigningStatus(); err != nil {
+		return nil, err
+	}
+	return &SigningSession{ptx: ptx}, nil
+}
+
+// PartialTx returns the partial tx with the signatures added so far, so that
+// it can be handed to the next signer.
+func (s *SigningSession) PartialTx() *PartialTx {
+	return s.ptx
+}
+
+// Sign adds the signatures of the keys in [kc]. No node access is needed.
+func (s *SigningSession) Sign(ctx stdcontext.Context, kc keychain.Keychain) error {
+	return NewSigner(kc, s.ptx).Sign(ctx, s.ptx.Tx)
+}
+
+// Add merges the signatures of [ptx], which must be a copy of the session's
+// partial tx, into the session.
+func (s *SigningSession) Add(ptx *PartialTx) error {
+	if _, err := ptx.SigningStatus(); err != nil {
+		return err
+	}
+	merged, err := MergePartialTxs(s.ptx, ptx)
+	if err != nil {
+		return err
+	}
+	s.ptx = merged
+	return nil
+}
+
+// Status reports which signature slots of each credential have been filled.
+func (s *SigningSession) Status() ([]*common.CredentialStatus, error) {
+	return s.ptx.SigningStatus()
+}
+
+// MissingSigners returns the addresses that still need to sign.
+func (s *SigningSession) MissingSigners() ([]ids.ShortID, error) {
+	return s.ptx.MissingSigners()
+}
+
+// Finalize returns the signed tx once every input's threshold is met.
+func (s *SigningSession) Finalize() (*txs.Tx, error) {
+	missing, err := s.ptx.MissingSigners()
+	if err != nil {
+		return nil, err
+	}
+	if len(missing) != 0 {
+		return nil, fmt.Errorf("%w from %v", common.ErrMissingSignatures, missing)
+	}
+	return s.ptx.Tx, nil
+}
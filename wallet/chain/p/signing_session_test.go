@@ -0,0 +1,105 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package p
+
+import (
+	"testing"
+
+	stdcontext "context"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/vms/secp256k1fx"
+	"github.com/dim4egster/qmallgo/wallet/subnet/primary/common"
+)
+
+func TestSigningSession(t *testing.T) {
+	require := require.New(t)
+	ctx := stdcontext.Background()
+
+	keys, backend, utx := newTestSubnetValidatorTx(t)
+	addrs := make([]ids.ShortID, len(keys))
+	for i, key := range keys {
+		addrs[i] = key.PublicKey().Address()
+	}
+
+	ptx, err := NewPartialTx(ctx, backend, utx)
+	require.NoError(err)
+	session, err := NewSigningSession(ptx)
+	require.NoError(err)
+
+	_, err = session.Finalize()
+	require.ErrorIs(err, common.ErrMissingSignatures)
+
+	// The first multisig owner signs locally.
+	require.NoError(session.Sign(ctx, secp256k1fx.NewKeychain(keys[0])))
+	status, err := session.Status()
+	require.NoError(err)
+	require.Equal(
+		[]*common.CredentialStatus{
+			{
+				Signers: []ids.ShortID{addrs[0], addrs[1]},
+				Signed:  []bool{true, false},
+			},
+			{
+				Signers: []ids.ShortID{addrs[2]},
+				Signed:  []bool{false},
+			},
+		},
+		status,
+	)
+
+	// The second multisig owner signs their own copy of the exported tx.
+	otherPtx, err := ParsePartialTx(mustBytes(t, ptx))
+	require.NoError(err)
+	require.NoError(NewSigner(secp256k1fx.NewKeychain(keys[1]), otherPtx).Sign(ctx, otherPtx.Tx))
+	require.NoError(session.Add(otherPtx))
+
+	missing, err := session.MissingSigners()
+	require.NoError(err)
+	require.Equal([]ids.ShortID{addrs[2]}, missing)
+	_, err = session.Finalize()
+	require.ErrorIs(err, common.ErrMissingSignatures)
+
+	// The subnet owner signs last.
+	require.NoError(session.Sign(ctx, secp256k1fx.NewKeychain(keys[2])))
+	tx, err := session.Finalize()
+	require.NoError(err)
+
+	expectedTx, err := NewSigner(secp256k1fx.NewKeychain(keys...), backend).SignUnsigned(ctx, utx)
+	require.NoError(err)
+	require.Equal(expectedTx.Bytes(), tx.Bytes())
+}
+
+func TestSigningSessionRejectsWrongSigner(t *testing.T) {
+	require := require.New(t)
+	ctx := stdcontext.Background()
+
+	keys, backend, utx := newTestSubnetValidatorTx(t)
+	ptx, err := NewPartialTx(ctx, backend, utx)
+	require.NoError(err)
+	session, err := NewSigningSession(ptx)
+	require.NoError(err)
+
+	// Sign the multisig input with a key that doesn't own it.
+	forgedPtx, err := ParsePartialTx(mustBytes(t, ptx))
+	require.NoError(err)
+	forgedTx, err := NewSigner(secp256k1fx.NewKeychain(keys[0]), forgedPtx).SignUnsigned(ctx, utx)
+	require.NoError(err)
+	forgedTx.Creds[0].(*secp256k1fx.Credential).Sigs[1] = forgedTx.Creds[0].(*secp256k1fx.Credential).Sigs[0]
+	forgedPtx.Tx = forgedTx
+
+	require.ErrorIs(session.Add(forgedPtx), common.ErrInvalidSignature)
+
+	missing, err := session.MissingSigners()
+	require.NoError(err)
+	require.Len(missing, len(keys))
+}
+
+func mustBytes(t *testing.T, ptx *PartialTx) []byte {
+	b, err := ptx.Bytes()
+	require.NoError(t, err)
+	return b
+}
@@ -7,6 +7,8 @@ import (
 	"errors"
 	"time"
 
+	stdcontext "context"
+
 	"github.com/dim4egster/qmallgo/ids"
 	"github.com/dim4egster/qmallgo/vms/components/avax"
 	"github.com/dim4egster/qmallgo/vms/platformvm"
@@ -33,6 +35,10 @@ type Wallet interface {
 	// Signer returns the signer that will be used to sign the transactions.
 	Signer() Signer
 
+	// UTXOs returns the UTXOs tracked by the wallet that were sent from
+	// [sourceChainID] to this chain.
+	UTXOs(ctx stdcontext.Context, sourceChainID ids.ID) ([]*avax.UTXO, error)
+
 	// IssueBaseTx creates, signs, and issues a new simple value transfer.
 	// Because the P-chain doesn't intend for balance transfers to occur, this
 	// method is expensive and abuses the creation of subnets.
@@ -233,12 +239,17 @@ type Wallet interface {
 	) (ids.ID, error)
 
 	// IssueUnsignedTx signs and issues the unsigned tx.
+	//
+	// If [common.WithDryRun] is provided, the tx is neither signed nor issued
+	// and the empty ID is returned.
 	IssueUnsignedTx(
 		utx txs.UnsignedTx,
 		options ...common.Option,
 	) (ids.ID, error)
 
 	// IssueTx issues the signed tx.
+	//
+	// If [common.WithDryRun] is provided, the tx isn't issued.
 	IssueTx(
 		tx *txs.Tx,
 		options ...common.Option,
@@ -466,6 +477,10 @@ func (w *wallet) IssueUnsignedTx(
 	options ...common.Option,
 ) (ids.ID, error) {
 	ops := common.NewOptions(options)
+	if ops.DryRun() != nil {
+		return ids.Empty, nil
+	}
+
 	ctx := ops.Context()
 	tx, err := w.signer.SignUnsigned(ctx, utx)
 	if err != nil {
@@ -480,6 +495,10 @@ func (w *wallet) IssueTx(
 	options ...common.Option,
 ) (ids.ID, error) {
 	ops := common.NewOptions(options)
+	if ops.DryRun() != nil {
+		return tx.ID(), nil
+	}
+
 	ctx := ops.Context()
 	txID, err := w.client.IssueTx(ctx, tx.Bytes())
 	if err != nil {
@@ -191,8 +191,9 @@ func (b *builder) NewBaseTx(
 	outputs []*avax.TransferableOutput,
 	options ...common.Option,
 ) (*txs.BaseTx, error) {
+	fee := b.backend.BaseTxFee()
 	toBurn := map[ids.ID]uint64{
-		b.backend.AVAXAssetID(): b.backend.BaseTxFee(),
+		b.backend.AVAXAssetID(): fee,
 	}
 	for _, out := range outputs {
 		assetID := out.AssetID()
@@ -208,6 +209,7 @@ func (b *builder) NewBaseTx(
 	if err != nil {
 		return nil, err
 	}
+	ops.RecordSpend(inputs, changeOutputs, nil, fee)
 	outputs = append(outputs, changeOutputs...)
 	avax.SortTransferableOutputs(outputs, Parser.Codec()) // sort the outputs
 
@@ -227,14 +229,16 @@ func (b *builder) NewCreateAssetTx(
 	initialState map[uint32][]verify.State,
 	options ...common.Option,
 ) (*txs.CreateAssetTx, error) {
+	fee := b.backend.CreateAssetTxFee()
 	toBurn := map[ids.ID]uint64{
-		b.backend.AVAXAssetID(): b.backend.CreateAssetTxFee(),
+		b.backend.AVAXAssetID(): fee,
 	}
 	ops := common.NewOptions(options)
 	inputs, outputs, err := b.spend(toBurn, ops)
 	if err != nil {
 		return nil, err
 	}
+	ops.RecordSpend(inputs, outputs, nil, fee)
 
 	codec := Parser.Codec()
 	states := make([]*txs.InitialState, 0, len(initialState))
@@ -268,14 +272,16 @@ func (b *builder) NewOperationTx(
 	operations []*txs.Operation,
 	options ...common.Option,
 ) (*txs.OperationTx, error) {
+	fee := b.backend.BaseTxFee()
 	toBurn := map[ids.ID]uint64{
-		b.backend.AVAXAssetID(): b.backend.BaseTxFee(),
+		b.backend.AVAXAssetID(): fee,
 	}
 	ops := common.NewOptions(options)
 	inputs, outputs, err := b.spend(toBurn, ops)
 	if err != nil {
 		return nil, err
 	}
+	ops.RecordSpend(inputs, outputs, nil, fee)
 
 	txs.SortOperations(operations, Parser.Codec())
 	return &txs.OperationTx{
@@ -422,6 +428,10 @@ func (b *builder) NewImportTx(
 		}
 		delete(importedAmounts, avaxAssetID)
 	}
+	consumedInputs := make([]*avax.TransferableInput, 0, len(importedInputs)+len(inputs))
+	consumedInputs = append(consumedInputs, importedInputs...)
+	consumedInputs = append(consumedInputs, inputs...)
+	ops.RecordSpend(consumedInputs, outputs, nil, txFee)
 
 	for assetID, amount := range importedAmounts {
 		outputs = append(outputs, &avax.TransferableOutput{
@@ -452,8 +462,9 @@ func (b *builder) NewExportTx(
 	outputs []*avax.TransferableOutput,
 	options ...common.Option,
 ) (*txs.ExportTx, error) {
+	fee := b.backend.BaseTxFee()
 	toBurn := map[ids.ID]uint64{
-		b.backend.AVAXAssetID(): b.backend.BaseTxFee(),
+		b.backend.AVAXAssetID(): fee,
 	}
 	for _, out := range outputs {
 		assetID := out.AssetID()
@@ -469,6 +480,7 @@ func (b *builder) NewExportTx(
 	if err != nil {
 		return nil, err
 	}
+	ops.RecordSpend(inputs, changeOutputs, nil, fee)
 
 	avax.SortTransferableOutputs(outputs, Parser.Codec())
 	return &txs.ExportTx{
@@ -549,14 +561,20 @@ func (b *builder) spend(
 		Addrs:     []ids.ShortID{addr},
 	})
 
-	// Iterate over the UTXOs
+	// Collect the UTXOs that can be burned
+	var (
+		spendableUTXOs    []*avax.UTXO
+		spendableOuts     []*secp256k1fx.TransferOutput
+		spendableSigs     [][]uint32
+		spendableAssetIDs []ids.ID
+		spendableAmounts  []uint64
+	)
 	for _, utxo := range utxos {
 		assetID := utxo.AssetID()
-		remainingAmountToBurn := amountsToBurn[assetID]
 
-		// If we have consumed enough of the asset, then we have no need burn
-		// more.
-		if remainingAmountToBurn == 0 {
+		// If we don't need to burn the asset, then we have no need to spend
+		// it.
+		if amountsToBurn[assetID] == 0 {
 			continue
 		}
 
@@ -573,21 +591,35 @@ func (b *builder) spend(
 			continue
 		}
 
+		spendableUTXOs = append(spendableUTXOs, utxo)
+		spendableOuts = append(spendableOuts, out)
+		spendableSigs = append(spendableSigs, inputSigIndices)
+		spendableAssetIDs = append(spendableAssetIDs, assetID)
+		spendableAmounts = append(spendableAmounts, out.Amt)
+	}
+
+	// Consume the selected UTXOs
+	selected := common.SelectUTXOs(options.CoinSelection(), spendableAssetIDs, spendableAmounts, amountsToBurn)
+	for _, i := range selected {
+		utxo := spendableUTXOs[i]
+		out := spendableOuts[i]
+		assetID := utxo.AssetID()
+
 		inputs = append(inputs, &avax.TransferableInput{
 			UTXOID: utxo.UTXOID,
 			Asset:  utxo.Asset,
 			In: &secp256k1fx.TransferInput{
 				Amt: out.Amt,
 				Input: secp256k1fx.Input{
-					SigIndices: inputSigIndices,
+					SigIndices: spendableSigs[i],
 				},
 			},
 		})
 
 		// Burn any value that should be burned
 		amountToBurn := math.Min64(
-			remainingAmountToBurn, // Amount we still need to burn
-			out.Amt,               // Amount available to burn
+			amountsToBurn[assetID], // Amount we still need to burn
+			out.Amt,                // Amount available to burn
 		)
 		amountsToBurn[assetID] -= amountToBurn
 		if remainingAmount := out.Amt - amountToBurn; remainingAmount > 0 {
@@ -0,0 +1,393 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package x
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	stdcontext "context"
+
+	"github.com/dim4egster/qmallgo/database"
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/utils/crypto"
+	"github.com/dim4egster/qmallgo/vms/avm/fxs"
+	"github.com/dim4egster/qmallgo/vms/avm/txs"
+	"github.com/dim4egster/qmallgo/vms/components/avax"
+	"github.com/dim4egster/qmallgo/vms/components/verify"
+	"github.com/dim4egster/qmallgo/vms/nftfx"
+	"github.com/dim4egster/qmallgo/vms/propertyfx"
+	"github.com/dim4egster/qmallgo/vms/secp256k1fx"
+	"github.com/dim4egster/qmallgo/wallet/subnet/primary/common"
+)
+
+var (
+	_ SignerBackend = &PartialTx{}
+
+	errMismatchedTx          = errors.New("partial txs don't sign the same tx")
+	errMismatchedCredentials = errors.New("partial txs have mismatched credentials")
+	errConflictingSignatures = errors.New("partial txs have conflicting signatures")
+	errNoPartialTxs          = errors.New("no partial txs to merge")
+	errMismatchedSignerHints = errors.New("signer hints don't match the tx credentials")
+	errNilPartialTx          = errors.New("nil tx is not valid")
+)
+
+// PartialUTXO is a UTXO consumed by a partially signed transaction, along
+// with the chain it is consumed from.
+type PartialUTXO struct {
+	ChainID ids.ID     `serialize:"true" json:"chainID"`
+	UTXO    *avax.UTXO `serialize:"true" json:"utxo"`
+}
+
+// PartialTx is a transaction that may be missing signatures, bundled with
+// everything needed to sign it without access to a node.
+//
+// A PartialTx implements SignerBackend, so it can be signed offline with:
+//
+//	NewSigner(kc, partialTx).Sign(ctx, partialTx.Tx)
+//
+// Once every signer has signed their copy, the copies can be combined with
+// MergePartialTxs and the resulting Tx issued.
+type PartialTx struct {
+	// Tx is the transaction along with the signatures added so far.
+	Tx *txs.Tx `serialize:"true" json:"tx"`
+	// UTXOs are the UTXOs consumed by [Tx].
+	UTXOs []*PartialUTXO `serialize:"true" json:"utxos"`
+	// Signers contains, for each credential of [Tx], the address that is
+	// expected to produce each of its signatures.
+	Signers [][]ids.ShortID `serialize:"true" json:"signers"`
+}
+
+// NewPartialTx exports [utx] along with the UTXOs and signer addresses that
+// are needed to sign it. All the UTXOs consumed by [utx] must be available in
+// [backend].
+func NewPartialTx(ctx stdcontext.Context, backend SignerBackend, utx txs.UnsignedTx) (*PartialTx, error) {
+	tx := &txs.Tx{Unsigned: utx}
+	if err := initializeTx(tx); err != nil {
+		return nil, err
+	}
+
+	ptx := &PartialTx{Tx: tx}
+	var err error
+	switch utx := utx.(type) {
+	case *txs.BaseTx:
+		err = ptx.addInputs(ctx, backend, utx.BlockchainID, utx.Ins)
+	case *txs.CreateAssetTx:
+		err = ptx.addInputs(ctx, backend, utx.BlockchainID, utx.Ins)
+	case *txs.OperationTx:
+		err = ptx.addInputs(ctx, backend, utx.BlockchainID, utx.Ins)
+		if err == nil {
+			err = ptx.addOps(ctx, backend, utx.BlockchainID, utx.Ops)
+		}
+	case *txs.ImportTx:
+		err = ptx.addInputs(ctx, backend, utx.BlockchainID, utx.Ins)
+		if err == nil {
+			err = ptx.addInputs(ctx, backend, utx.SourceChain, utx.ImportedIns)
+		}
+	case *txs.ExportTx:
+		err = ptx.addInputs(ctx, backend, utx.BlockchainID, utx.Ins)
+	default:
+		err = fmt.Errorf("%w: %T", errUnknownTxType, utx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ptx, nil
+}
+
+// ParsePartialTx parses a partially signed transaction produced by Bytes.
+func ParsePartialTx(b []byte) (*PartialTx, error) {
+	ptx := &PartialTx{}
+	if _, err := Parser.Codec().Unmarshal(b, ptx); err != nil {
+		return nil, fmt.Errorf("couldn't parse partial tx: %w", err)
+	}
+	if ptx.Tx == nil || ptx.Tx.Unsigned == nil {
+		return nil, errNilPartialTx
+	}
+	return ptx, initializeTx(ptx.Tx)
+}
+
+// Bytes returns the binary representation of this partially signed
+// transaction.
+func (p *PartialTx) Bytes() ([]byte, error) {
+	return Parser.Codec().Marshal(txs.CodecVersion, p)
+}
+
+func (p *PartialTx) GetUTXO(_ stdcontext.Context, chainID, utxoID ids.ID) (*avax.UTXO, error) {
+	for _, utxo := range p.UTXOs {
+		if utxo.ChainID == chainID && utxo.UTXO.InputID() == utxoID {
+			return utxo.UTXO, nil
+		}
+	}
+	return nil, database.ErrNotFound
+}
+
+// MissingSigners returns the addresses that still need to sign [Tx]. Each
+// address is returned at most once.
+func (p *PartialTx) MissingSigners() ([]ids.ShortID, error) {
+	statuses, err := p.SigningStatus()
+	if err != nil {
+		return nil, err
+	}
+	return common.MissingSigners(statuses), nil
+}
+
+// SigningStatus verifies the signatures added to [Tx] so far and reports
+// which signature slots of each credential have been filled.
+func (p *PartialTx) SigningStatus() ([]*common.CredentialStatus, error) {
+	creds, err := p.credentials()
+	if err != nil {
+		return nil, err
+	}
+	return common.SigningStatus(p.Tx.Unsigned.Bytes(), p.Signers, creds)
+}
+
+// credentials returns the credentials of [Tx], or nil if [Tx] hasn't been
+// signed yet.
+func (p *PartialTx) credentials() ([]*secp256k1fx.Credential, error) {
+	if len(p.Tx.Creds) == 0 {
+		return nil, nil
+	}
+	if len(p.Tx.Creds) != len(p.Signers) {
+		return nil, errMismatchedSignerHints
+	}
+
+	creds := make([]*secp256k1fx.Credential, len(p.Tx.Creds))
+	for credIndex, fxCred := range p.Tx.Creds {
+		cred, err := getCredential(fxCred.Verifiable)
+		if err != nil {
+			return nil, err
+		}
+		if len(cred.Sigs) != len(p.Signers[credIndex]) {
+			return nil, errMismatchedSignerHints
+		}
+		creds[credIndex] = cred
+	}
+	return creds, nil
+}
+
+func (p *PartialTx) addInputs(
+	ctx stdcontext.Context,
+	backend SignerBackend,
+	sourceChainID ids.ID,
+	ins []*avax.TransferableInput,
+) error {
+	for _, transferInput := range ins {
+		input, ok := transferInput.In.(*secp256k1fx.TransferInput)
+		if !ok {
+			return errUnknownInputType
+		}
+
+		utxoID := transferInput.InputID()
+		utxo, err := backend.GetUTXO(ctx, sourceChainID, utxoID)
+		if err != nil {
+			return fmt.Errorf("failed to fetch UTXO %q: %w", utxoID, err)
+		}
+
+		out, ok := utxo.Out.(*secp256k1fx.TransferOutput)
+		if !ok {
+			return errUnknownOutputType
+		}
+
+		signers, err := getSignerAddrs(out.Addrs, input.SigIndices)
+		if err != nil {
+			return err
+		}
+		p.UTXOs = append(p.UTXOs, &PartialUTXO{
+			ChainID: sourceChainID,
+			UTXO:    utxo,
+		})
+		p.Signers = append(p.Signers, signers)
+	}
+	return nil
+}
+
+func (p *PartialTx) addOps(
+	ctx stdcontext.Context,
+	backend SignerBackend,
+	sourceChainID ids.ID,
+	ops []*txs.Operation,
+) error {
+	for _, op := range ops {
+		var input *secp256k1fx.Input
+		switch op := op.Op.(type) {
+		case *secp256k1fx.MintOperation:
+			input = &op.MintInput
+		case *nftfx.MintOperation:
+			input = &op.MintInput
+		case *nftfx.TransferOperation:
+			input = &op.Input
+		case *propertyfx.MintOperation:
+			input = &op.MintInput
+		case *propertyfx.BurnOperation:
+			input = &op.Input
+		default:
+			return errUnknownOpType
+		}
+
+		if len(op.UTXOIDs) != 1 {
+			return errInvalidNumUTXOsInOp
+		}
+		utxoID := op.UTXOIDs[0].InputID()
+		utxo, err := backend.GetUTXO(ctx, sourceChainID, utxoID)
+		if err != nil {
+			return fmt.Errorf("failed to fetch UTXO %q: %w", utxoID, err)
+		}
+
+		var addrs []ids.ShortID
+		switch out := utxo.Out.(type) {
+		case *secp256k1fx.MintOutput:
+			addrs = out.Addrs
+		case *nftfx.MintOutput:
+			addrs = out.Addrs
+		case *nftfx.TransferOutput:
+			addrs = out.Addrs
+		case *propertyfx.MintOutput:
+			addrs = out.Addrs
+		case *propertyfx.OwnedOutput:
+			addrs = out.Addrs
+		default:
+			return errUnknownOutputType
+		}
+
+		signers, err := getSignerAddrs(addrs, input.SigIndices)
+		if err != nil {
+			return err
+		}
+		p.UTXOs = append(p.UTXOs, &PartialUTXO{
+			ChainID: sourceChainID,
+			UTXO:    utxo,
+		})
+		p.Signers = append(p.Signers, signers)
+	}
+	return nil
+}
+
+// MergePartialTxs combines the signatures of copies of the same partially
+// signed transaction. The provided partial txs are not modified.
+func MergePartialTxs(ptxs ...*PartialTx) (*PartialTx, error) {
+	if len(ptxs) == 0 {
+		return nil, errNoPartialTxs
+	}
+	for _, ptx := range ptxs {
+		if ptx == nil || ptx.Tx == nil || ptx.Tx.Unsigned == nil {
+			return nil, errNilPartialTx
+		}
+	}
+
+	first := ptxs[0]
+	unsignedBytes := first.Tx.Unsigned.Bytes()
+	merged := &PartialTx{
+		Tx:      &txs.Tx{Unsigned: first.Tx.Unsigned},
+		UTXOs:   first.UTXOs,
+		Signers: first.Signers,
+	}
+
+	var mergedCreds []*secp256k1fx.Credential
+	for _, ptx := range ptxs {
+		if !bytes.Equal(ptx.Tx.Unsigned.Bytes(), unsignedBytes) {
+			return nil, errMismatchedTx
+		}
+		creds, err := ptx.credentials()
+		if err != nil {
+			return nil, err
+		}
+		if creds == nil {
+			continue
+		}
+		if mergedCreds == nil {
+			merged.Tx.Creds, mergedCreds, err = newCredentials(ptx.Tx.Creds)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if len(creds) != len(mergedCreds) {
+			return nil, errMismatchedCredentials
+		}
+
+		for credIndex, cred := range creds {
+			mergedSigs := mergedCreds[credIndex].Sigs
+			if len(cred.Sigs) != len(mergedSigs) {
+				return nil, errMismatchedCredentials
+			}
+			for sigIndex, sig := range cred.Sigs {
+				switch mergedSig := mergedSigs[sigIndex]; {
+				case sig == emptySig:
+				case mergedSig == emptySig:
+					mergedSigs[sigIndex] = sig
+				case mergedSig != sig:
+					return nil, fmt.Errorf("%w: credential %d signature %d",
+						errConflictingSignatures,
+						credIndex,
+						sigIndex,
+					)
+				}
+			}
+		}
+	}
+	return merged, initializeTx(merged.Tx)
+}
+
+// newCredentials returns unsigned credentials of the same types as [creds],
+// along with the secp256k1fx credentials they are built on.
+func newCredentials(creds []*fxs.FxCredential) ([]*fxs.FxCredential, []*secp256k1fx.Credential, error) {
+	fxCreds := make([]*fxs.FxCredential, len(creds))
+	secpCreds := make([]*secp256k1fx.Credential, len(creds))
+	for credIndex, fxCred := range creds {
+		var credIntf verify.Verifiable
+		switch fxCred.Verifiable.(type) {
+		case *secp256k1fx.Credential:
+			credIntf = &secp256k1fx.Credential{}
+		case *nftfx.Credential:
+			credIntf = &nftfx.Credential{}
+		case *propertyfx.Credential:
+			credIntf = &propertyfx.Credential{}
+		default:
+			return nil, nil, errUnknownCredentialType
+		}
+
+		cred, err := getCredential(credIntf)
+		if err != nil {
+			return nil, nil, err
+		}
+		oldCred, err := getCredential(fxCred.Verifiable)
+		if err != nil {
+			return nil, nil, err
+		}
+		cred.Sigs = make([][crypto.SECP256K1RSigLen]byte, len(oldCred.Sigs))
+
+		fxCreds[credIndex] = &fxs.FxCredential{
+			FxID:       fxCred.FxID,
+			Verifiable: credIntf,
+		}
+		secpCreds[credIndex] = cred
+	}
+	return fxCreds, secpCreds, nil
+}
+
+func initializeTx(tx *txs.Tx) error {
+	codec := Parser.Codec()
+	unsignedBytes, err := codec.Marshal(txs.CodecVersion, &tx.Unsigned)
+	if err != nil {
+		return fmt.Errorf("couldn't marshal unsigned tx: %w", err)
+	}
+	signedBytes, err := codec.Marshal(txs.CodecVersion, tx)
+	if err != nil {
+		return fmt.Errorf("couldn't marshal tx: %w", err)
+	}
+	tx.Initialize(unsignedBytes, signedBytes)
+	return nil
+}
+
+func getSignerAddrs(addrs []ids.ShortID, sigIndices []uint32) ([]ids.ShortID, error) {
+	signers := make([]ids.ShortID, len(sigIndices))
+	for sigIndex, addrIndex := range sigIndices {
+		if addrIndex >= uint32(len(addrs)) {
+			return nil, errInvalidUTXOSigIndex
+		}
+		signers[sigIndex] = addrs[addrIndex]
+	}
+	return signers, nil
+}
@@ -0,0 +1,195 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package x
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	stdcontext "context"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dim4egster/qmallgo/database"
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/utils/constants"
+	"github.com/dim4egster/qmallgo/utils/crypto"
+	"github.com/dim4egster/qmallgo/utils/formatting"
+	"github.com/dim4egster/qmallgo/utils/units"
+	"github.com/dim4egster/qmallgo/vms/avm/fxs"
+	"github.com/dim4egster/qmallgo/vms/avm/txs"
+	"github.com/dim4egster/qmallgo/vms/components/avax"
+	"github.com/dim4egster/qmallgo/vms/nftfx"
+	"github.com/dim4egster/qmallgo/vms/secp256k1fx"
+)
+
+var _ BuilderBackend = &testBackend{}
+
+type testBackend struct {
+	Context
+
+	utxos map[ids.ID][]*avax.UTXO
+}
+
+func (b *testBackend) UTXOs(_ stdcontext.Context, sourceChainID ids.ID) ([]*avax.UTXO, error) {
+	return b.utxos[sourceChainID], nil
+}
+
+func (b *testBackend) GetUTXO(_ stdcontext.Context, chainID, utxoID ids.ID) (*avax.UTXO, error) {
+	for _, utxo := range b.utxos[chainID] {
+		if utxo.InputID() == utxoID {
+			return utxo, nil
+		}
+	}
+	return nil, database.ErrNotFound
+}
+
+// writePartialTx and readPartialTx store the partial tx as a hex encoded file,
+// the format used by main/partialtx.
+func writePartialTx(t *testing.T, path string, ptx *PartialTx) {
+	ptxBytes, err := ptx.Bytes()
+	require.NoError(t, err)
+	ptxHex, err := formatting.Encode(formatting.Hex, ptxBytes)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, []byte(ptxHex), 0o600))
+}
+
+func readPartialTx(t *testing.T, path string) *PartialTx {
+	ptxHex, err := os.ReadFile(path)
+	require.NoError(t, err)
+	ptxBytes, err := formatting.Decode(formatting.Hex, string(ptxHex))
+	require.NoError(t, err)
+	ptx, err := ParsePartialTx(ptxBytes)
+	require.NoError(t, err)
+	return ptx
+}
+
+func TestPartialTxRoundTrip(t *testing.T) {
+	require := require.New(t)
+	ctx := stdcontext.Background()
+
+	keys := make([]*crypto.PrivateKeySECP256K1R, 2)
+	addrs := make([]ids.ShortID, len(keys))
+	for i := range keys {
+		keyIntf, err := (&crypto.FactorySECP256K1R{}).NewPrivateKey()
+		require.NoError(err)
+		keys[i] = keyIntf.(*crypto.PrivateKeySECP256K1R)
+		addrs[i] = keys[i].PublicKey().Address()
+	}
+
+	// The funds are held by a 2-of-2 multisig.
+	chainID := ids.GenerateTestID()
+	avaxAssetID := ids.GenerateTestID()
+	utxo := &avax.UTXO{
+		UTXOID: avax.UTXOID{TxID: ids.GenerateTestID()},
+		Asset:  avax.Asset{ID: avaxAssetID},
+		Out: &secp256k1fx.TransferOutput{
+			Amt: 10 * units.Avax,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 2,
+				Addrs:     addrs,
+			},
+		},
+	}
+	backend := &testBackend{
+		Context: NewContext(constants.UnitTestID, chainID, avaxAssetID, units.MilliAvax, units.MilliAvax),
+		utxos: map[ids.ID][]*avax.UTXO{
+			chainID: {utxo},
+		},
+	}
+
+	addrSet := ids.ShortSet{}
+	addrSet.Add(addrs...)
+	utx, err := NewBuilder(addrSet, backend).NewBaseTx([]*avax.TransferableOutput{{
+		Asset: avax.Asset{ID: avaxAssetID},
+		Out: &secp256k1fx.TransferOutput{
+			Amt: units.Avax,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{ids.GenerateTestShortID()},
+			},
+		},
+	}})
+	require.NoError(err)
+
+	// Export the unsigned tx on the online machine.
+	dir := t.TempDir()
+	unsignedPath := filepath.Join(dir, "unsigned.tx")
+	ptx, err := NewPartialTx(ctx, backend, utx)
+	require.NoError(err)
+	require.Equal([][]ids.ShortID{addrs}, ptx.Signers)
+	writePartialTx(t, unsignedPath, ptx)
+
+	// Each key holder signs the tx offline.
+	signedPaths := make([]string, len(keys))
+	for i, key := range keys {
+		ptx := readPartialTx(t, unsignedPath)
+		require.NoError(NewSigner(secp256k1fx.NewKeychain(key), ptx).Sign(ctx, ptx.Tx))
+
+		missing, err := ptx.MissingSigners()
+		require.NoError(err)
+		require.Equal([]ids.ShortID{addrs[1-i]}, missing)
+
+		signedPaths[i] = filepath.Join(dir, "signed.tx."+addrs[i].String())
+		writePartialTx(t, signedPaths[i], ptx)
+	}
+
+	// The signatures are merged and the tx can be issued.
+	signed := make([]*PartialTx, len(signedPaths))
+	for i, path := range signedPaths {
+		signed[i] = readPartialTx(t, path)
+	}
+	merged, err := MergePartialTxs(signed...)
+	require.NoError(err)
+	missing, err := merged.MissingSigners()
+	require.NoError(err)
+	require.Empty(missing)
+
+	expectedTx, err := NewSigner(secp256k1fx.NewKeychain(keys...), backend).SignUnsigned(ctx, utx)
+	require.NoError(err)
+	require.Equal(expectedTx.Bytes(), merged.Tx.Bytes())
+}
+
+func TestMergePartialTxsKeepsCredentialTypes(t *testing.T) {
+	require := require.New(t)
+
+	newPartialTx := func(sig byte) *PartialTx {
+		ptx := &PartialTx{
+			Tx: &txs.Tx{
+				Unsigned: &txs.BaseTx{},
+				Creds: []*fxs.FxCredential{{
+					Verifiable: &nftfx.Credential{Credential: secp256k1fx.Credential{
+						Sigs: [][crypto.SECP256K1RSigLen]byte{{1}, {sig}},
+					}},
+				}},
+			},
+			Signers: [][]ids.ShortID{{ids.GenerateTestShortID(), ids.GenerateTestShortID()}},
+		}
+		require.NoError(initializeTx(ptx.Tx))
+		return ptx
+	}
+
+	merged, err := MergePartialTxs(newPartialTx(0), newPartialTx(2))
+	require.NoError(err)
+	cred, ok := merged.Tx.Creds[0].Verifiable.(*nftfx.Credential)
+	require.True(ok)
+	require.Equal([][crypto.SECP256K1RSigLen]byte{{1}, {2}}, cred.Sigs)
+
+	_, err = MergePartialTxs(newPartialTx(2), newPartialTx(3))
+	require.ErrorIs(err, errConflictingSignatures)
+}
+
+func TestMergePartialTxsNil(t *testing.T) {
+	require := require.New(t)
+
+	_, err := MergePartialTxs()
+	require.ErrorIs(err, errNoPartialTxs)
+
+	_, err = MergePartialTxs(nil)
+	require.ErrorIs(err, errNilPartialTx)
+
+	_, err = MergePartialTxs(&PartialTx{}, &PartialTx{Tx: &txs.Tx{}})
+	require.ErrorIs(err, errNilPartialTx)
+}
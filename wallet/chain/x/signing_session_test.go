@@ -0,0 +1,181 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package x
+
+import (
+	"testing"
+
+	stdcontext "context"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/utils/constants"
+	"github.com/dim4egster/qmallgo/utils/crypto"
+	"github.com/dim4egster/qmallgo/utils/units"
+	"github.com/dim4egster/qmallgo/vms/avm/txs"
+	"github.com/dim4egster/qmallgo/vms/components/avax"
+	"github.com/dim4egster/qmallgo/vms/secp256k1fx"
+	"github.com/dim4egster/qmallgo/wallet/subnet/primary/common"
+)
+
+// newTestBaseTx returns a tx that spends a UTXO held by a 2-of-2 multisig of
+// keys[0] and keys[1], and a UTXO held by keys[2].
+func newTestBaseTx(t *testing.T) ([]*crypto.PrivateKeySECP256K1R, *testBackend, txs.UnsignedTx) {
+	require := require.New(t)
+
+	keys := make([]*crypto.PrivateKeySECP256K1R, 3)
+	for i := range keys {
+		keyIntf, err := (&crypto.FactorySECP256K1R{}).NewPrivateKey()
+		require.NoError(err)
+		keys[i] = keyIntf.(*crypto.PrivateKeySECP256K1R)
+	}
+
+	chainID := ids.GenerateTestID()
+	avaxAssetID := ids.GenerateTestID()
+	newUTXO := func(threshold uint32, keys ...*crypto.PrivateKeySECP256K1R) *avax.UTXO {
+		addrs := make([]ids.ShortID, len(keys))
+		for i, key := range keys {
+			addrs[i] = key.PublicKey().Address()
+		}
+		return &avax.UTXO{
+			UTXOID: avax.UTXOID{TxID: ids.GenerateTestID()},
+			Asset:  avax.Asset{ID: avaxAssetID},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: units.Avax,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: threshold,
+					Addrs:     addrs,
+				},
+			},
+		}
+	}
+	backend := &testBackend{
+		Context: NewContext(constants.UnitTestID, chainID, avaxAssetID, units.MilliAvax, units.MilliAvax),
+		utxos: map[ids.ID][]*avax.UTXO{
+			chainID: {
+				newUTXO(2, keys[0], keys[1]),
+				newUTXO(1, keys[2]),
+			},
+		},
+	}
+
+	addrs := ids.ShortSet{}
+	for _, key := range keys {
+		addrs.Add(key.PublicKey().Address())
+	}
+	// Both UTXOs are needed to pay for the output and the fee.
+	utx, err := NewBuilder(addrs, backend).NewBaseTx([]*avax.TransferableOutput{{
+		Asset: avax.Asset{ID: avaxAssetID},
+		Out: &secp256k1fx.TransferOutput{
+			Amt: units.Avax,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{ids.GenerateTestShortID()},
+			},
+		},
+	}})
+	require.NoError(err)
+	return keys, backend, utx
+}
+
+func TestSigningSession(t *testing.T) {
+	require := require.New(t)
+	ctx := stdcontext.Background()
+
+	keys, backend, utx := newTestBaseTx(t)
+	addrs := make([]ids.ShortID, len(keys))
+	for i, key := range keys {
+		addrs[i] = key.PublicKey().Address()
+	}
+
+	ptx, err := NewPartialTx(ctx, backend, utx)
+	require.NoError(err)
+	require.ElementsMatch(
+		[][]ids.ShortID{
+			{addrs[0], addrs[1]},
+			{addrs[2]},
+		},
+		ptx.Signers,
+	)
+	session, err := NewSigningSession(ptx)
+	require.NoError(err)
+
+	_, err = session.Finalize()
+	require.ErrorIs(err, common.ErrMissingSignatures)
+
+	// The first multisig owner signs locally.
+	require.NoError(session.Sign(ctx, secp256k1fx.NewKeychain(keys[0])))
+	status, err := session.Status()
+	require.NoError(err)
+	require.Len(status, 2)
+	for _, credStatus := range status {
+		for i, signer := range credStatus.Signers {
+			require.Equal(signer == addrs[0], credStatus.Signed[i])
+		}
+		require.False(credStatus.Complete())
+	}
+
+	// The second multisig owner signs their own copy of the exported tx.
+	otherPtx, err := ParsePartialTx(mustBytes(t, ptx))
+	require.NoError(err)
+	require.NoError(NewSigner(secp256k1fx.NewKeychain(keys[1]), otherPtx).Sign(ctx, otherPtx.Tx))
+	require.NoError(session.Add(otherPtx))
+
+	missing, err := session.MissingSigners()
+	require.NoError(err)
+	require.Equal([]ids.ShortID{addrs[2]}, missing)
+	_, err = session.Finalize()
+	require.ErrorIs(err, common.ErrMissingSignatures)
+
+	// The owner of the other UTXO signs last, which meets every threshold.
+	require.NoError(session.Sign(ctx, secp256k1fx.NewKeychain(keys[2])))
+	status, err = session.Status()
+	require.NoError(err)
+	for _, credStatus := range status {
+		require.True(credStatus.Complete())
+	}
+	tx, err := session.Finalize()
+	require.NoError(err)
+
+	expectedTx, err := NewSigner(secp256k1fx.NewKeychain(keys...), backend).SignUnsigned(ctx, utx)
+	require.NoError(err)
+	require.Equal(expectedTx.Bytes(), tx.Bytes())
+}
+
+func TestSigningSessionRejectsWrongSigner(t *testing.T) {
+	require := require.New(t)
+	ctx := stdcontext.Background()
+
+	keys, backend, utx := newTestBaseTx(t)
+	ptx, err := NewPartialTx(ctx, backend, utx)
+	require.NoError(err)
+	session, err := NewSigningSession(ptx)
+	require.NoError(err)
+
+	// Sign the multisig input with a key that doesn't own it.
+	multisigIndex := 0
+	if len(ptx.Signers[multisigIndex]) != 2 {
+		multisigIndex = 1
+	}
+	forgedPtx, err := ParsePartialTx(mustBytes(t, ptx))
+	require.NoError(err)
+	forgedTx, err := NewSigner(secp256k1fx.NewKeychain(keys[0]), forgedPtx).SignUnsigned(ctx, utx)
+	require.NoError(err)
+	forgedCred := forgedTx.Creds[multisigIndex].Verifiable.(*secp256k1fx.Credential)
+	forgedCred.Sigs[1] = forgedCred.Sigs[0]
+	forgedPtx.Tx = forgedTx
+
+	require.ErrorIs(session.Add(forgedPtx), common.ErrInvalidSignature)
+
+	missing, err := session.MissingSigners()
+	require.NoError(err)
+	require.Len(missing, len(keys))
+}
+
+func mustBytes(t *testing.T, ptx *PartialTx) []byte {
+	b, err := ptx.Bytes()
+	require.NoError(t, err)
+	return b
+}
@@ -0,0 +1,298 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package primary
+
+import (
+	"context"
+
+	"github.com/dim4egster/qmallgo/database"
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/utils/constants"
+	"github.com/dim4egster/qmallgo/utils/crypto"
+	"github.com/dim4egster/qmallgo/utils/wrappers"
+	"github.com/dim4egster/qmallgo/vms/components/avax"
+	"github.com/dim4egster/qmallgo/vms/secp256k1fx"
+	"github.com/dim4egster/qmallgo/wallet/chain/p"
+	"github.com/dim4egster/qmallgo/wallet/chain/x"
+	"github.com/dim4egster/qmallgo/wallet/subnet/primary/common"
+
+	avmtxs "github.com/dim4egster/qmallgo/vms/avm/txs"
+	pvmtxs "github.com/dim4egster/qmallgo/vms/platformvm/txs"
+)
+
+var (
+	_ batchChain = &xBatchChain{}
+	_ batchChain = &pBatchChain{}
+
+	_ x.BuilderBackend = &xBatchBackend{}
+	_ p.BuilderBackend = &pBatchBackend{}
+)
+
+// batchTx is a tx built from a batch of UTXOs that hasn't been issued yet.
+type batchTx struct {
+	// size is the number of bytes of the tx once it is signed.
+	size int
+	// maxSize is the number of bytes the chain's codec allows a tx to have.
+	maxSize int
+	issue   func() (ids.ID, error)
+}
+
+// batchChain builds txs that spend exactly the provided batch of UTXOs on a
+// chain of the primary network.
+type batchChain interface {
+	chainID() ids.ID
+	avaxAssetID() ids.ID
+	baseTxFee() uint64
+	exportTxFee() uint64
+
+	utxos(ctx context.Context, sourceChainID ids.ID) ([]*avax.UTXO, error)
+
+	newBaseTx(
+		utxos []*avax.UTXO,
+		addrs ids.ShortSet,
+		outputs []*avax.TransferableOutput,
+		options []common.Option,
+	) (*batchTx, error)
+	newExportTx(
+		utxos []*avax.UTXO,
+		addrs ids.ShortSet,
+		destinationChainID ids.ID,
+		outputs []*avax.TransferableOutput,
+		options []common.Option,
+	) (*batchTx, error)
+	newImportTx(
+		sourceChainID ids.ID,
+		utxos []*avax.UTXO,
+		addrs ids.ShortSet,
+		to *secp256k1fx.OutputOwners,
+		options []common.Option,
+	) (*batchTx, error)
+}
+
+func newBatchChain(w Wallet, chainID ids.ID) (batchChain, error) {
+	switch chainID {
+	case constants.PlatformChainID:
+		return &pBatchChain{w: w.P()}, nil
+	case w.X().BlockchainID():
+		return &xBatchChain{w: w.X()}, nil
+	default:
+		return nil, errUnknownChain
+	}
+}
+
+type xBatchChain struct {
+	w x.Wallet
+}
+
+func (c *xBatchChain) chainID() ids.ID     { return c.w.BlockchainID() }
+func (c *xBatchChain) avaxAssetID() ids.ID { return c.w.AVAXAssetID() }
+func (c *xBatchChain) baseTxFee() uint64   { return c.w.BaseTxFee() }
+func (c *xBatchChain) exportTxFee() uint64 { return c.w.BaseTxFee() }
+
+func (c *xBatchChain) utxos(ctx context.Context, sourceChainID ids.ID) ([]*avax.UTXO, error) {
+	return c.w.UTXOs(ctx, sourceChainID)
+}
+
+func (c *xBatchChain) newBaseTx(
+	utxos []*avax.UTXO,
+	addrs ids.ShortSet,
+	outputs []*avax.TransferableOutput,
+	options []common.Option,
+) (*batchTx, error) {
+	utx, err := c.builder(c.chainID(), utxos, addrs).NewBaseTx(outputs, options...)
+	if err != nil {
+		return nil, err
+	}
+	return c.newBatchTx(utx, utx.Ins, options)
+}
+
+func (c *xBatchChain) newExportTx(
+	utxos []*avax.UTXO,
+	addrs ids.ShortSet,
+	destinationChainID ids.ID,
+	outputs []*avax.TransferableOutput,
+	options []common.Option,
+) (*batchTx, error) {
+	utx, err := c.builder(c.chainID(), utxos, addrs).NewExportTx(destinationChainID, outputs, options...)
+	if err != nil {
+		return nil, err
+	}
+	return c.newBatchTx(utx, utx.Ins, options)
+}
+
+func (c *xBatchChain) newImportTx(
+	sourceChainID ids.ID,
+	utxos []*avax.UTXO,
+	addrs ids.ShortSet,
+	to *secp256k1fx.OutputOwners,
+	options []common.Option,
+) (*batchTx, error) {
+	utx, err := c.builder(sourceChainID, utxos, addrs).NewImportTx(sourceChainID, to, options...)
+	if err != nil {
+		return nil, err
+	}
+	ins := make([]*avax.TransferableInput, 0, len(utx.Ins)+len(utx.ImportedIns))
+	ins = append(ins, utx.Ins...)
+	ins = append(ins, utx.ImportedIns...)
+	return c.newBatchTx(utx, ins, options)
+}
+
+func (c *xBatchChain) builder(sourceChainID ids.ID, utxos []*avax.UTXO, addrs ids.ShortSet) x.Builder {
+	return x.NewBuilder(addrs, &xBatchBackend{
+		Context:       c.w,
+		sourceChainID: sourceChainID,
+		utxos:         utxos,
+	})
+}
+
+func (c *xBatchChain) newBatchTx(
+	utx avmtxs.UnsignedTx,
+	ins []*avax.TransferableInput,
+	options []common.Option,
+) (*batchTx, error) {
+	codec := x.Parser.Codec()
+	unsignedBytes, err := codec.Marshal(avmtxs.CodecVersion, &utx)
+	if err != nil {
+		return nil, err
+	}
+	return &batchTx{
+		size:    signedTxSize(len(unsignedBytes), ins),
+		maxSize: codec.MaxSize(),
+		issue: func() (ids.ID, error) {
+			return c.w.IssueUnsignedTx(utx, options...)
+		},
+	}, nil
+}
+
+type pBatchChain struct {
+	w p.Wallet
+}
+
+func (*pBatchChain) chainID() ids.ID       { return constants.PlatformChainID }
+func (c *pBatchChain) avaxAssetID() ids.ID { return c.w.AVAXAssetID() }
+
+func (c *pBatchChain) baseTxFee() uint64   { return c.w.BaseTxFee() }
+func (c *pBatchChain) exportTxFee() uint64 { return c.w.BaseTxFee() }
+
+func (c *pBatchChain) utxos(ctx context.Context, sourceChainID ids.ID) ([]*avax.UTXO, error) {
+	return c.w.UTXOs(ctx, sourceChainID)
+}
+
+// newBaseTx fails as the P-chain has no tx that only moves funds. The P
+// builder's NewBaseTx creates a subnet, which burns the much higher subnet
+// creation fee.
+func (*pBatchChain) newBaseTx(
+	[]*avax.UTXO,
+	ids.ShortSet,
+	[]*avax.TransferableOutput,
+	[]common.Option,
+) (*batchTx, error) {
+	return nil, errNoPChainTransfers
+}
+
+func (c *pBatchChain) newExportTx(
+	utxos []*avax.UTXO,
+	addrs ids.ShortSet,
+	destinationChainID ids.ID,
+	outputs []*avax.TransferableOutput,
+	options []common.Option,
+) (*batchTx, error) {
+	utx, err := c.builder(c.chainID(), utxos, addrs).NewExportTx(destinationChainID, outputs, options...)
+	if err != nil {
+		return nil, err
+	}
+	return c.newBatchTx(utx, utx.Ins, options)
+}
+
+func (c *pBatchChain) newImportTx(
+	sourceChainID ids.ID,
+	utxos []*avax.UTXO,
+	addrs ids.ShortSet,
+	to *secp256k1fx.OutputOwners,
+	options []common.Option,
+) (*batchTx, error) {
+	utx, err := c.builder(sourceChainID, utxos, addrs).NewImportTx(sourceChainID, to, options...)
+	if err != nil {
+		return nil, err
+	}
+	ins := make([]*avax.TransferableInput, 0, len(utx.Ins)+len(utx.ImportedInputs))
+	ins = append(ins, utx.Ins...)
+	ins = append(ins, utx.ImportedInputs...)
+	return c.newBatchTx(utx, ins, options)
+}
+
+func (c *pBatchChain) builder(sourceChainID ids.ID, utxos []*avax.UTXO, addrs ids.ShortSet) p.Builder {
+	return p.NewBuilder(addrs, &pBatchBackend{
+		Context:       c.w,
+		sourceChainID: sourceChainID,
+		utxos:         utxos,
+	})
+}
+
+func (c *pBatchChain) newBatchTx(
+	utx pvmtxs.UnsignedTx,
+	ins []*avax.TransferableInput,
+	options []common.Option,
+) (*batchTx, error) {
+	unsignedBytes, err := pvmtxs.Codec.Marshal(pvmtxs.Version, &utx)
+	if err != nil {
+		return nil, err
+	}
+	return &batchTx{
+		size:    signedTxSize(len(unsignedBytes), ins),
+		maxSize: pvmtxs.Codec.MaxSize(),
+		issue: func() (ids.ID, error) {
+			return c.w.IssueUnsignedTx(utx, options...)
+		},
+	}, nil
+}
+
+// xBatchBackend only exposes the UTXOs of a batch to the X-chain builder.
+type xBatchBackend struct {
+	x.Context
+
+	sourceChainID ids.ID
+	utxos         []*avax.UTXO
+}
+
+func (b *xBatchBackend) UTXOs(_ context.Context, sourceChainID ids.ID) ([]*avax.UTXO, error) {
+	if sourceChainID != b.sourceChainID {
+		return nil, nil
+	}
+	return b.utxos, nil
+}
+
+// pBatchBackend only exposes the UTXOs of a batch to the P-chain builder.
+type pBatchBackend struct {
+	p.Context
+
+	sourceChainID ids.ID
+	utxos         []*avax.UTXO
+}
+
+func (b *pBatchBackend) UTXOs(_ context.Context, sourceChainID ids.ID) ([]*avax.UTXO, error) {
+	if sourceChainID != b.sourceChainID {
+		return nil, nil
+	}
+	return b.utxos, nil
+}
+
+// GetTx is only used to authorize subnet modifications, which batches never
+// perform.
+func (*pBatchBackend) GetTx(context.Context, ids.ID) (*pvmtxs.Tx, error) {
+	return nil, database.ErrNotFound
+}
+
+// signedTxSize returns the size of a tx whose unsigned tx is [unsignedSize]
+// bytes once a secp256k1fx credential has been added for each of [ins].
+func signedTxSize(unsignedSize int, ins []*avax.TransferableInput) int {
+	size := unsignedSize + wrappers.IntLen // number of credentials
+	for _, in := range ins {
+		size += wrappers.IntLen + wrappers.IntLen // type ID and number of signatures
+		if in, ok := in.In.(*secp256k1fx.TransferInput); ok {
+			size += len(in.SigIndices) * crypto.SECP256K1RSigLen
+		}
+	}
+	return size
+}
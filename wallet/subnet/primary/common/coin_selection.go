@@ -0,0 +1,221 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"sort"
+
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/utils/math"
+)
+
+var (
+	// BackendOrder consumes UTXOs in the order they are returned by the
+	// backend. This is the default strategy.
+	BackendOrder CoinSelection = backendOrder{}
+
+	// LargestFirst consumes the largest UTXOs first.
+	LargestFirst CoinSelection = largestFirst{}
+
+	// SmallestSufficient consumes the smallest UTXO that covers the amount on
+	// its own. If no single UTXO is large enough, the smallest UTXOs are
+	// consumed first. This leaves large UTXOs untouched where possible.
+	SmallestSufficient CoinSelection = smallestSufficient{}
+
+	// MinimizeInputs consumes as few UTXOs as possible. Among the selections
+	// with the fewest inputs, it prefers the one producing the least change.
+	MinimizeInputs CoinSelection = minimizeInputs{}
+
+	_ CoinSelection = &consolidateDust{}
+)
+
+// CoinSelection decides which UTXOs of an asset are consumed to cover an
+// amount.
+type CoinSelection interface {
+	// Select returns the indices of [amounts] that should be consumed, in the
+	// order they should be consumed, to cover [target]. If [amounts] can't
+	// cover [target], the returned indices may not either.
+	Select(amounts []uint64, target uint64) []int
+}
+
+// ConsolidateDust returns a strategy that consumes every UTXO worth at most
+// [threshold], even if they aren't needed to cover the amount, so that they
+// are merged into the change output. Any remaining amount is covered by the
+// largest UTXOs.
+func ConsolidateDust(threshold uint64) CoinSelection {
+	return &consolidateDust{threshold: threshold}
+}
+
+// SelectUTXOs applies [selection] separately to the UTXOs of each asset.
+//
+//   - [assetIDs] and [amounts] are the asset and the spendable amount of each
+//     candidate UTXO.
+//   - [targets] maps assetID to the amount that needs to be consumed. UTXOs
+//     of assets without a target are never selected.
+//
+// The indices of the selected candidates are returned in the order they should
+// be consumed.
+func SelectUTXOs(
+	selection CoinSelection,
+	assetIDs []ids.ID,
+	amounts []uint64,
+	targets map[ids.ID]uint64,
+) []int {
+	var (
+		orderedAssetIDs []ids.ID
+		assetIndices    = make(map[ids.ID][]int)
+	)
+	for i, assetID := range assetIDs {
+		if targets[assetID] == 0 {
+			continue
+		}
+		if _, ok := assetIndices[assetID]; !ok {
+			orderedAssetIDs = append(orderedAssetIDs, assetID)
+		}
+		assetIndices[assetID] = append(assetIndices[assetID], i)
+	}
+
+	var selected []int
+	for _, assetID := range orderedAssetIDs {
+		indices := assetIndices[assetID]
+		assetAmounts := make([]uint64, len(indices))
+		for j, i := range indices {
+			assetAmounts[j] = amounts[i]
+		}
+		for _, j := range selection.Select(assetAmounts, targets[assetID]) {
+			selected = append(selected, indices[j])
+		}
+	}
+	return selected
+}
+
+type backendOrder struct{}
+
+func (backendOrder) Select(amounts []uint64, target uint64) []int {
+	order := make([]int, len(amounts))
+	for i := range order {
+		order[i] = i
+	}
+	selected, _ := selectPrefix(amounts, order, target)
+	return selected
+}
+
+type largestFirst struct{}
+
+func (largestFirst) Select(amounts []uint64, target uint64) []int {
+	selected, _ := selectPrefix(amounts, sortedIndices(amounts, false), target)
+	return selected
+}
+
+type smallestSufficient struct{}
+
+func (smallestSufficient) Select(amounts []uint64, target uint64) []int {
+	best := -1
+	for i, amount := range amounts {
+		if amount >= target && (best == -1 || amount < amounts[best]) {
+			best = i
+		}
+	}
+	if best != -1 {
+		return []int{best}
+	}
+	selected, _ := selectPrefix(amounts, sortedIndices(amounts, true), target)
+	return selected
+}
+
+type minimizeInputs struct{}
+
+func (minimizeInputs) Select(amounts []uint64, target uint64) []int {
+	order := sortedIndices(amounts, false)
+	selected, covered := selectPrefix(amounts, order, target)
+	if !covered || len(selected) == 0 {
+		return selected
+	}
+
+	// Consuming the largest UTXOs first requires the fewest inputs. The last
+	// input can be replaced by the smallest UTXO that still covers the target,
+	// which reduces the change without adding inputs.
+	last := len(selected) - 1
+	var consumed uint64
+	for _, i := range selected[:last] {
+		consumed += amounts[i]
+	}
+	needed := target - consumed
+	for j := len(order) - 1; j >= last; j-- {
+		if i := order[j]; amounts[i] >= needed {
+			selected[last] = i
+			break
+		}
+	}
+	return selected
+}
+
+type consolidateDust struct {
+	threshold uint64
+}
+
+func (c *consolidateDust) Select(amounts []uint64, target uint64) []int {
+	var (
+		selected []int
+		dust     uint64
+		rest     []int
+	)
+	for _, i := range sortedIndices(amounts, true) {
+		if amounts[i] > c.threshold {
+			rest = append(rest, i)
+			continue
+		}
+		selected = append(selected, i)
+		dust = safeAdd(dust, amounts[i])
+	}
+	if dust >= target {
+		return selected
+	}
+
+	// Cover the remainder with the largest UTXOs.
+	for left, right := 0, len(rest)-1; left < right; left, right = left+1, right-1 {
+		rest[left], rest[right] = rest[right], rest[left]
+	}
+	remaining, _ := selectPrefix(amounts, rest, target-dust)
+	return append(selected, remaining...)
+}
+
+// selectPrefix returns the shortest prefix of [order] whose amounts cover
+// [target], and whether the target was covered.
+func selectPrefix(amounts []uint64, order []int, target uint64) ([]int, bool) {
+	var consumed uint64
+	for n, i := range order {
+		if consumed >= target {
+			return order[:n], true
+		}
+		consumed = safeAdd(consumed, amounts[i])
+	}
+	return order, consumed >= target
+}
+
+// sortedIndices returns the indices of [amounts] sorted by amount. Equal
+// amounts keep their original order.
+func sortedIndices(amounts []uint64, ascending bool) []int {
+	order := make([]int, len(amounts))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		if ascending {
+			return amounts[order[i]] < amounts[order[j]]
+		}
+		return amounts[order[i]] > amounts[order[j]]
+	})
+	return order
+}
+
+// safeAdd returns a + b, saturating at the maximum uint64 value. Any total that
+// overflows covers every target.
+func safeAdd(a, b uint64) uint64 {
+	sum, err := math.Add64(a, b)
+	if err != nil {
+		return ^uint64(0)
+	}
+	return sum
+}
@@ -0,0 +1,103 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dim4egster/qmallgo/ids"
+)
+
+func TestCoinSelection(t *testing.T) {
+	amounts := []uint64{5, 1, 20, 2, 12, 1}
+	tests := []struct {
+		name      string
+		selection CoinSelection
+		target    uint64
+		expected  []int
+	}{
+		{
+			name:      "backend order",
+			selection: BackendOrder,
+			target:    7,
+			expected:  []int{0, 1, 2},
+		},
+		{
+			name:      "largest first",
+			selection: LargestFirst,
+			target:    25,
+			expected:  []int{2, 4},
+		},
+		{
+			name:      "smallest sufficient single utxo",
+			selection: SmallestSufficient,
+			target:    6,
+			expected:  []int{4},
+		},
+		{
+			name:      "smallest sufficient falls back to smallest first",
+			selection: SmallestSufficient,
+			target:    25,
+			expected:  []int{1, 5, 3, 0, 4, 2},
+		},
+		{
+			name:      "minimize inputs reduces change",
+			selection: MinimizeInputs,
+			target:    24,
+			expected:  []int{2, 0},
+		},
+		{
+			name:      "minimize inputs insufficient",
+			selection: MinimizeInputs,
+			target:    100,
+			expected:  []int{2, 4, 0, 3, 1, 5},
+		},
+		{
+			name:      "consolidate dust covered by dust",
+			selection: ConsolidateDust(2),
+			target:    3,
+			expected:  []int{1, 5, 3},
+		},
+		{
+			name:      "consolidate dust with largest remainder",
+			selection: ConsolidateDust(2),
+			target:    10,
+			expected:  []int{1, 5, 3, 2},
+		},
+		{
+			name:      "nothing to consume",
+			selection: MinimizeInputs,
+			target:    0,
+			expected:  []int{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			selected := test.selection.Select(amounts, test.target)
+			require.Equal(t, test.expected, append([]int{}, selected...))
+		})
+	}
+}
+
+func TestSelectUTXOs(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		assetA = ids.GenerateTestID()
+		assetB = ids.GenerateTestID()
+		assetC = ids.GenerateTestID()
+	)
+	selected := SelectUTXOs(
+		LargestFirst,
+		[]ids.ID{assetA, assetB, assetA, assetC, assetB},
+		[]uint64{1, 2, 3, 4, 5},
+		map[ids.ID]uint64{
+			assetA: 2,
+			assetB: 6,
+		},
+	)
+	require.Equal([]int{2, 4, 1}, selected)
+}
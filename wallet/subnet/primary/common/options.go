@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/vms/components/avax"
 	"github.com/dim4egster/qmallgo/vms/secp256k1fx"
 )
 
@@ -34,6 +35,10 @@ type Options struct {
 
 	pollFrequencySet bool
 	pollFrequency    time.Duration
+
+	coinSelection CoinSelection
+
+	dryRun *SpendSummary
 }
 
 func NewOptions(ops []Option) *Options {
@@ -97,6 +102,33 @@ func (o *Options) PollFrequency() time.Duration {
 	return defaultPollFrequency
 }
 
+func (o *Options) CoinSelection() CoinSelection {
+	if o.coinSelection != nil {
+		return o.coinSelection
+	}
+	return BackendOrder
+}
+
+func (o *Options) DryRun() *SpendSummary { return o.dryRun }
+
+// RecordSpend populates the summary requested by [WithDryRun], if any.
+func (o *Options) RecordSpend(
+	inputs []*avax.TransferableInput,
+	changeOutputs []*avax.TransferableOutput,
+	stakeOutputs []*avax.TransferableOutput,
+	fee uint64,
+) {
+	if o.dryRun == nil {
+		return
+	}
+	*o.dryRun = SpendSummary{
+		Inputs:        inputs,
+		ChangeOutputs: changeOutputs,
+		StakeOutputs:  stakeOutputs,
+		Fee:           fee,
+	}
+}
+
 func WithContext(ctx context.Context) Option {
 	return func(o *Options) {
 		o.ctx = ctx
@@ -147,3 +179,18 @@ func WithPollFrequency(pollFrequency time.Duration) Option {
 		o.pollFrequency = pollFrequency
 	}
 }
+
+func WithCoinSelection(coinSelection CoinSelection) Option {
+	return func(o *Options) {
+		o.coinSelection = coinSelection
+	}
+}
+
+// WithDryRun causes wallets to build the transaction without signing or
+// issuing it. The builder populates [summary] with the selected inputs, the
+// change outputs and the fee.
+func WithDryRun(summary *SpendSummary) Option {
+	return func(o *Options) {
+		o.dryRun = summary
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/utils/crypto"
+	"github.com/dim4egster/qmallgo/utils/hashing"
+	"github.com/dim4egster/qmallgo/vms/secp256k1fx"
+)
+
+var (
+	ErrMissingSignatures = errors.New("missing signatures")
+	ErrInvalidSignature  = errors.New("invalid signature")
+
+	errMismatchedSlots = errors.New("credentials don't match the expected signers")
+
+	emptySig [crypto.SECP256K1RSigLen]byte
+)
+
+// CredentialStatus reports which of the signature slots of a credential have
+// been filled.
+type CredentialStatus struct {
+	// Signers is the address expected to sign each slot.
+	Signers []ids.ShortID
+	// Signed reports whether each slot holds a signature.
+	Signed []bool
+}
+
+// Complete returns true if every slot of the credential holds a signature.
+//
+// Because secp256k1fx requires an input to provide exactly as many signatures
+// as the threshold of the output it spends, a complete credential meets the
+// threshold.
+func (s *CredentialStatus) Complete() bool {
+	for _, signed := range s.Signed {
+		if !signed {
+			return false
+		}
+	}
+	return true
+}
+
+// SigningStatus verifies the populated signatures of [creds] and returns the
+// status of each credential.
+//
+//   - [unsignedBytes] are the bytes of the unsigned tx that is being signed.
+//   - [signers] contains, for each credential, the address expected to sign
+//     each of its slots.
+//   - [creds] are the credentials of the tx. If [creds] is empty, the tx is
+//     treated as unsigned.
+func SigningStatus(
+	unsignedBytes []byte,
+	signers [][]ids.ShortID,
+	creds []*secp256k1fx.Credential,
+) ([]*CredentialStatus, error) {
+	if len(creds) != 0 && len(creds) != len(signers) {
+		return nil, errMismatchedSlots
+	}
+
+	var (
+		factory      crypto.FactorySECP256K1R
+		unsignedHash = hashing.ComputeHash256(unsignedBytes)
+		statuses     = make([]*CredentialStatus, len(signers))
+	)
+	for credIndex, credSigners := range signers {
+		status := &CredentialStatus{
+			Signers: credSigners,
+			Signed:  make([]bool, len(credSigners)),
+		}
+		statuses[credIndex] = status
+		if len(creds) == 0 {
+			continue
+		}
+
+		cred := creds[credIndex]
+		if len(cred.Sigs) != len(credSigners) {
+			return nil, errMismatchedSlots
+		}
+		for sigIndex, sig := range cred.Sigs {
+			if sig == emptySig {
+				continue
+			}
+
+			pk, err := factory.RecoverHashPublicKey(unsignedHash, sig[:])
+			if err != nil {
+				return nil, fmt.Errorf("%w: credential %d signature %d: %s",
+					ErrInvalidSignature,
+					credIndex,
+					sigIndex,
+					err,
+				)
+			}
+			if addr := pk.Address(); addr != credSigners[sigIndex] {
+				return nil, fmt.Errorf("%w: credential %d signature %d signed by %s rather than %s",
+					ErrInvalidSignature,
+					credIndex,
+					sigIndex,
+					addr,
+					credSigners[sigIndex],
+				)
+			}
+			status.Signed[sigIndex] = true
+		}
+	}
+	return statuses, nil
+}
+
+// MissingSigners returns the addresses that still need to sign according to
+// [statuses]. Each address is returned at most once.
+func MissingSigners(statuses []*CredentialStatus) []ids.ShortID {
+	var (
+		missing    []ids.ShortID
+		missingSet = ids.ShortSet{}
+	)
+	for _, status := range statuses {
+		for sigIndex, addr := range status.Signers {
+			if status.Signed[sigIndex] || missingSet.Contains(addr) {
+				continue
+			}
+			missingSet.Add(addr)
+			missing = append(missing, addr)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,92 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/utils/crypto"
+	"github.com/dim4egster/qmallgo/vms/secp256k1fx"
+)
+
+func TestSigningStatus(t *testing.T) {
+	require := require.New(t)
+
+	keys := make([]*crypto.PrivateKeySECP256K1R, 3)
+	addrs := make([]ids.ShortID, len(keys))
+	for i := range keys {
+		keyIntf, err := (&crypto.FactorySECP256K1R{}).NewPrivateKey()
+		require.NoError(err)
+		keys[i] = keyIntf.(*crypto.PrivateKeySECP256K1R)
+		addrs[i] = keys[i].PublicKey().Address()
+	}
+	unsignedBytes := []byte("unsigned tx")
+	sign := func(key *crypto.PrivateKeySECP256K1R) [crypto.SECP256K1RSigLen]byte {
+		sigBytes, err := key.Sign(unsignedBytes)
+		require.NoError(err)
+		var sig [crypto.SECP256K1RSigLen]byte
+		copy(sig[:], sigBytes)
+		return sig
+	}
+
+	// Two 2-of-2 multisig inputs that share addrs[0], which must sign both
+	// credentials.
+	signers := [][]ids.ShortID{
+		{addrs[0], addrs[1]},
+		{addrs[0], addrs[2]},
+	}
+
+	// Without credentials, the tx is unsigned.
+	statuses, err := SigningStatus(unsignedBytes, signers, nil)
+	require.NoError(err)
+	require.Equal(
+		[]*CredentialStatus{
+			{Signers: signers[0], Signed: []bool{false, false}},
+			{Signers: signers[1], Signed: []bool{false, false}},
+		},
+		statuses,
+	)
+	require.False(statuses[0].Complete())
+	require.Equal([]ids.ShortID{addrs[0], addrs[1], addrs[2]}, MissingSigners(statuses))
+
+	// Partially signed credentials report their empty slots.
+	creds := []*secp256k1fx.Credential{
+		{Sigs: [][crypto.SECP256K1RSigLen]byte{sign(keys[0]), {}}},
+		{Sigs: [][crypto.SECP256K1RSigLen]byte{sign(keys[0]), sign(keys[2])}},
+	}
+	statuses, err = SigningStatus(unsignedBytes, signers, creds)
+	require.NoError(err)
+	require.Equal([]bool{true, false}, statuses[0].Signed)
+	require.False(statuses[0].Complete())
+	require.True(statuses[1].Complete())
+	require.Equal([]ids.ShortID{addrs[1]}, MissingSigners(statuses))
+
+	// Once every slot is signed, nobody is missing.
+	creds[0].Sigs[1] = sign(keys[1])
+	statuses, err = SigningStatus(unsignedBytes, signers, creds)
+	require.NoError(err)
+	require.True(statuses[0].Complete())
+	require.Empty(MissingSigners(statuses))
+
+	// A signature by a key other than the expected signer is rejected.
+	creds[0].Sigs[1] = sign(keys[2])
+	_, err = SigningStatus(unsignedBytes, signers, creds)
+	require.ErrorIs(err, ErrInvalidSignature)
+
+	// A malformed signature is rejected.
+	creds[0].Sigs[1] = [crypto.SECP256K1RSigLen]byte{1}
+	_, err = SigningStatus(unsignedBytes, signers, creds)
+	require.ErrorIs(err, ErrInvalidSignature)
+
+	// The credentials must have a slot for every expected signer.
+	_, err = SigningStatus(unsignedBytes, signers, creds[:1])
+	require.ErrorIs(err, errMismatchedSlots)
+
+	creds[0].Sigs = creds[0].Sigs[:1]
+	_, err = SigningStatus(unsignedBytes, signers, creds)
+	require.ErrorIs(err, errMismatchedSlots)
+}
@@ -5,6 +5,7 @@ package common
 
 import (
 	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/vms/components/avax"
 	"github.com/dim4egster/qmallgo/vms/secp256k1fx"
 )
 
@@ -28,3 +29,17 @@ func MatchOwners(
 	}
 	return sigs, uint32(len(sigs)) == owners.Threshold
 }
+
+// SpendSummary describes how a builder funded a transaction. It is populated
+// when the transaction is built with [WithDryRun].
+type SpendSummary struct {
+	// Inputs are the UTXOs consumed by the transaction, including any
+	// imported UTXOs.
+	Inputs []*avax.TransferableInput
+	// ChangeOutputs return the excess value of the inputs to the change owner.
+	ChangeOutputs []*avax.TransferableOutput
+	// StakeOutputs are the outputs locked for staking.
+	StakeOutputs []*avax.TransferableOutput
+	// Fee is the amount of AVAX burned by the transaction.
+	Fee uint64
+}
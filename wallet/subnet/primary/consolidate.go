@@ -0,0 +1,391 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package primary
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/utils/constants"
+	"github.com/dim4egster/qmallgo/utils/math"
+	"github.com/dim4egster/qmallgo/vms/components/avax"
+	"github.com/dim4egster/qmallgo/vms/platformvm/stakeable"
+	"github.com/dim4egster/qmallgo/vms/secp256k1fx"
+	"github.com/dim4egster/qmallgo/wallet/subnet/primary/common"
+)
+
+// DefaultMaxBatchInputs is the default maximum number of UTXOs consumed by
+// each tx issued by Consolidate and Sweep.
+const DefaultMaxBatchInputs = 256
+
+var (
+	errUnknownChain      = errors.New("unknown chain")
+	errNoPChainTransfers = errors.New("the P-chain can only move funds with export txs")
+	errNoAddresses       = errors.New("no addresses provided")
+	errInsufficientFunds = errors.New("insufficient funds")
+	errTxTooLarge        = errors.New("tx exceeds the maximum size")
+)
+
+// Consolidate merges the UTXOs of [assetID] on [chainID] that are spendable by
+// [addrs] into a single output per issued tx. The outputs are sent to the
+// change owner of [options], which defaults to one of [addrs].
+//
+// Each tx consumes at most [maxInputs] UTXOs, starting with the smallest ones.
+// If a tx would exceed the maximum tx size allowed by the chain's codec, fewer
+// UTXOs are consumed by that tx. If the consolidated asset isn't AVAX, the fees
+// are paid with the AVAX held by [addrs].
+//
+// The P-chain has no tx that only moves funds, so [chainID] can't be the
+// P-chain.
+//
+// The IDs of the issued txs are returned in issuance order.
+func Consolidate(
+	w Wallet,
+	chainID ids.ID,
+	assetID ids.ID,
+	addrs ids.ShortSet,
+	maxInputs int,
+	options ...common.Option,
+) ([]ids.ID, error) {
+	if chainID == constants.PlatformChainID {
+		return nil, errNoPChainTransfers
+	}
+	c, err := newBatchChain(w, chainID)
+	if err != nil {
+		return nil, err
+	}
+	addr, ok := addrs.Peek()
+	if !ok {
+		return nil, errNoAddresses
+	}
+
+	ops := common.NewOptions(options)
+	to := ops.ChangeOwner(&secp256k1fx.OutputOwners{
+		Threshold: 1,
+		Addrs:     []ids.ShortID{addr},
+	})
+	utxos, err := spendableUTXOs(c, c.chainID(), addrs, ops)
+	if err != nil {
+		return nil, err
+	}
+	return spendBatches(c, assetID, utxos[assetID], addrs, to, maxInputs, 2, ops, options,
+		c.baseTxFee(),
+		func(batch []*avax.UTXO, outputs []*avax.TransferableOutput) (*batchTx, error) {
+			return c.newBaseTx(batch, addrs, outputs, options)
+		},
+	)
+}
+
+// Sweep moves all the funds spendable by [addrs] on [sourceChainID] to [to] on
+// [destinationChainID].
+//
+// If the chains differ, the AVAX is exported to [addrs] and then imported to
+// [to] on [destinationChainID]. Assets that can't be exported are sent to [to]
+// on [sourceChainID]. Locked UTXOs aren't moved.
+//
+// Each tx consumes at most [maxInputs] UTXOs, with fewer UTXOs consumed if a
+// tx would exceed the maximum tx size allowed by the chain's codec.
+//
+// The P-chain has no tx that only moves funds, so funds can be swept from the
+// P-chain only to another chain.
+//
+// The IDs of the issued txs are returned in issuance order.
+func Sweep(
+	w Wallet,
+	sourceChainID ids.ID,
+	destinationChainID ids.ID,
+	addrs ids.ShortSet,
+	to *secp256k1fx.OutputOwners,
+	maxInputs int,
+	options ...common.Option,
+) ([]ids.ID, error) {
+	if sourceChainID == constants.PlatformChainID && destinationChainID == constants.PlatformChainID {
+		return nil, errNoPChainTransfers
+	}
+	source, err := newBatchChain(w, sourceChainID)
+	if err != nil {
+		return nil, err
+	}
+	destination, err := newBatchChain(w, destinationChainID)
+	if err != nil {
+		return nil, err
+	}
+	addr, ok := addrs.Peek()
+	if !ok {
+		return nil, errNoAddresses
+	}
+
+	ops := common.NewOptions(options)
+	utxos, err := spendableUTXOs(source, source.chainID(), addrs, ops)
+	if err != nil {
+		return nil, err
+	}
+
+	// AVAX is moved last because it pays the fees of the other assets.
+	avaxAssetID := source.avaxAssetID()
+	assetIDs := make([]ids.ID, 0, len(utxos))
+	for assetID := range utxos {
+		if assetID != avaxAssetID {
+			assetIDs = append(assetIDs, assetID)
+		}
+	}
+	ids.SortIDs(assetIDs)
+
+	var txIDs []ids.ID
+	for _, assetID := range assetIDs {
+		assetTxIDs, err := spendBatches(source, assetID, utxos[assetID], addrs, to, maxInputs, 1, ops, options,
+			source.baseTxFee(),
+			func(batch []*avax.UTXO, outputs []*avax.TransferableOutput) (*batchTx, error) {
+				return source.newBaseTx(batch, addrs, outputs, options)
+			},
+		)
+		txIDs = append(txIDs, assetTxIDs...)
+		if err != nil {
+			return txIDs, err
+		}
+	}
+
+	// Paying the fees of the other assets changed the AVAX UTXOs.
+	utxos, err = spendableUTXOs(source, source.chainID(), addrs, ops)
+	if err != nil {
+		return txIDs, err
+	}
+
+	if sourceChainID == destinationChainID {
+		avaxTxIDs, err := spendBatches(source, avaxAssetID, utxos[avaxAssetID], addrs, to, maxInputs, 1, ops, options,
+			source.baseTxFee(),
+			func(batch []*avax.UTXO, outputs []*avax.TransferableOutput) (*batchTx, error) {
+				return source.newBaseTx(batch, addrs, outputs, options)
+			},
+		)
+		return append(txIDs, avaxTxIDs...), err
+	}
+
+	// The exported funds remain owned by [addrs] so that they can be imported.
+	self := &secp256k1fx.OutputOwners{
+		Threshold: 1,
+		Addrs:     []ids.ShortID{addr},
+	}
+	exportTxIDs, err := spendBatches(source, avaxAssetID, utxos[avaxAssetID], addrs, self, maxInputs, 1, ops, options,
+		source.exportTxFee(),
+		func(batch []*avax.UTXO, outputs []*avax.TransferableOutput) (*batchTx, error) {
+			return source.newExportTx(batch, addrs, destinationChainID, outputs, options)
+		},
+	)
+	txIDs = append(txIDs, exportTxIDs...)
+	if err != nil {
+		return txIDs, err
+	}
+
+	importTxIDs, err := importBatches(source, destination, addrs, to, maxInputs, ops, options)
+	return append(txIDs, importTxIDs...), err
+}
+
+// spendBatches issues txs that each consume a batch of [utxos], which must all
+// be of [assetID], until fewer than [minInputs] UTXOs remain. [build] is
+// provided with the UTXOs the tx may consume and a single output, owned by
+// [to], holding the value of the batch less [fee] if [assetID] is AVAX.
+func spendBatches(
+	c batchChain,
+	assetID ids.ID,
+	utxos []*spendableUTXO,
+	addrs ids.ShortSet,
+	to *secp256k1fx.OutputOwners,
+	maxInputs int,
+	minInputs int,
+	ops *common.Options,
+	options []common.Option,
+	fee uint64,
+	build func(batch []*avax.UTXO, outputs []*avax.TransferableOutput) (*batchTx, error),
+) ([]ids.ID, error) {
+	if maxInputs <= 0 {
+		maxInputs = DefaultMaxBatchInputs
+	}
+	avaxAssetID := c.avaxAssetID()
+
+	var txIDs []ids.ID
+	for len(utxos) >= minInputs {
+		// The fees of other assets are paid with the AVAX that is currently
+		// spendable, which changes as txs are issued.
+		var feeUTXOs []*avax.UTXO
+		if assetID != avaxAssetID {
+			spendable, err := spendableUTXOs(c, c.chainID(), addrs, ops)
+			if err != nil {
+				return txIDs, err
+			}
+			for _, utxo := range spendable[avaxAssetID] {
+				feeUTXOs = append(feeUTXOs, utxo.utxo)
+			}
+		}
+
+		var (
+			numInputs = math.Min(maxInputs, len(utxos))
+			tx        *batchTx
+		)
+		for {
+			batch := make([]*avax.UTXO, numInputs, numInputs+len(feeUTXOs))
+			var amount uint64
+			for i, utxo := range utxos[:numInputs] {
+				batch[i] = utxo.utxo
+				newAmount, err := math.Add64(amount, utxo.amount)
+				if err != nil {
+					return txIDs, err
+				}
+				amount = newAmount
+			}
+			batch = append(batch, feeUTXOs...)
+
+			if assetID == avaxAssetID {
+				if amount <= fee {
+					return txIDs, fmt.Errorf(
+						"%w: batch of %d UTXOs holds %d, which doesn't cover the fee of %d",
+						errInsufficientFunds,
+						numInputs,
+						amount,
+						fee,
+					)
+				}
+				amount -= fee
+			}
+
+			var err error
+			tx, err = build(batch, []*avax.TransferableOutput{{
+				Asset: avax.Asset{ID: assetID},
+				Out: &secp256k1fx.TransferOutput{
+					Amt:          amount,
+					OutputOwners: *to,
+				},
+			}})
+			if err != nil {
+				return txIDs, err
+			}
+			if tx.size <= tx.maxSize {
+				break
+			}
+			if numInputs/2 < minInputs {
+				return txIDs, fmt.Errorf("%w: %d > %d", errTxTooLarge, tx.size, tx.maxSize)
+			}
+			numInputs /= 2
+		}
+
+		txID, err := tx.issue()
+		if err != nil {
+			return txIDs, err
+		}
+		txIDs = append(txIDs, txID)
+		utxos = utxos[numInputs:]
+	}
+	return txIDs, nil
+}
+
+// importBatches issues txs on [destination] that each import a batch of the
+// UTXOs exported from [source] to [addrs].
+func importBatches(
+	source batchChain,
+	destination batchChain,
+	addrs ids.ShortSet,
+	to *secp256k1fx.OutputOwners,
+	maxInputs int,
+	ops *common.Options,
+	options []common.Option,
+) ([]ids.ID, error) {
+	if maxInputs <= 0 {
+		maxInputs = DefaultMaxBatchInputs
+	}
+
+	spendable, err := spendableUTXOs(destination, source.chainID(), addrs, ops)
+	if err != nil {
+		return nil, err
+	}
+	var utxos []*avax.UTXO
+	for _, assetUTXOs := range spendable {
+		for _, utxo := range assetUTXOs {
+			utxos = append(utxos, utxo.utxo)
+		}
+	}
+
+	var txIDs []ids.ID
+	for len(utxos) > 0 {
+		var (
+			numInputs = math.Min(maxInputs, len(utxos))
+			tx        *batchTx
+		)
+		for {
+			tx, err = destination.newImportTx(source.chainID(), utxos[:numInputs], addrs, to, options)
+			if err != nil {
+				return txIDs, err
+			}
+			if tx.size <= tx.maxSize {
+				break
+			}
+			if numInputs == 1 {
+				return txIDs, fmt.Errorf("%w: %d > %d", errTxTooLarge, tx.size, tx.maxSize)
+			}
+			numInputs /= 2
+		}
+
+		txID, err := tx.issue()
+		if err != nil {
+			return txIDs, err
+		}
+		txIDs = append(txIDs, txID)
+		utxos = utxos[numInputs:]
+	}
+	return txIDs, nil
+}
+
+type spendableUTXO struct {
+	utxo   *avax.UTXO
+	amount uint64
+}
+
+// spendableUTXOs returns the unlocked UTXOs on [c] that were sent from
+// [sourceChainID] and that can be spent by [addrs], grouped by asset. The UTXOs
+// of each asset are sorted from smallest to largest.
+func spendableUTXOs(
+	c batchChain,
+	sourceChainID ids.ID,
+	addrs ids.ShortSet,
+	ops *common.Options,
+) (map[ids.ID][]*spendableUTXO, error) {
+	utxos, err := c.utxos(ops.Context(), sourceChainID)
+	if err != nil {
+		return nil, err
+	}
+
+	minIssuanceTime := ops.MinIssuanceTime()
+	spendable := make(map[ids.ID][]*spendableUTXO)
+	for _, utxo := range utxos {
+		outIntf := utxo.Out
+		if lockedOut, ok := outIntf.(*stakeable.LockOut); ok {
+			if lockedOut.Locktime > minIssuanceTime {
+				// This output is currently locked, so it can't be moved.
+				continue
+			}
+			outIntf = lockedOut.TransferableOut
+		}
+
+		out, ok := outIntf.(*secp256k1fx.TransferOutput)
+		if !ok {
+			continue
+		}
+		if _, ok := common.MatchOwners(&out.OutputOwners, addrs, minIssuanceTime); !ok {
+			continue
+		}
+
+		assetID := utxo.AssetID()
+		spendable[assetID] = append(spendable[assetID], &spendableUTXO{
+			utxo:   utxo,
+			amount: out.Amt,
+		})
+	}
+	for _, assetUTXOs := range spendable {
+		sort.SliceStable(assetUTXOs, func(i, j int) bool {
+			return assetUTXOs[i].amount < assetUTXOs[j].amount
+		})
+	}
+	return spendable, nil
+}
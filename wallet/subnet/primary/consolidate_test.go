@@ -0,0 +1,251 @@
+// Copyright (C) 2019-2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package primary
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dim4egster/qmallgo/ids"
+	"github.com/dim4egster/qmallgo/utils/constants"
+	"github.com/dim4egster/qmallgo/utils/crypto"
+	"github.com/dim4egster/qmallgo/utils/hashing"
+	"github.com/dim4egster/qmallgo/utils/rpc"
+	"github.com/dim4egster/qmallgo/utils/units"
+	"github.com/dim4egster/qmallgo/vms/avm"
+	"github.com/dim4egster/qmallgo/vms/components/avax"
+	"github.com/dim4egster/qmallgo/vms/platformvm"
+	"github.com/dim4egster/qmallgo/vms/platformvm/txs"
+	"github.com/dim4egster/qmallgo/vms/secp256k1fx"
+	"github.com/dim4egster/qmallgo/wallet/chain/p"
+	"github.com/dim4egster/qmallgo/wallet/chain/x"
+	"github.com/dim4egster/qmallgo/wallet/subnet/primary/common"
+)
+
+const testFee = units.MilliAvax
+
+type testXClient struct {
+	avm.Client
+
+	numIssued int
+}
+
+func (c *testXClient) IssueTx(_ context.Context, txBytes []byte, _ ...rpc.Option) (ids.ID, error) {
+	c.numIssued++
+	return hashing.ComputeHash256Array(txBytes), nil
+}
+
+type testPClient struct {
+	platformvm.Client
+
+	numIssued int
+}
+
+func (c *testPClient) IssueTx(_ context.Context, txBytes []byte, _ ...rpc.Option) (ids.ID, error) {
+	c.numIssued++
+	return hashing.ComputeHash256Array(txBytes), nil
+}
+
+type testEnv struct {
+	wallet      Wallet
+	utxos       UTXOs
+	xClient     *testXClient
+	pClient     *testPClient
+	xChainID    ids.ID
+	avaxAssetID ids.ID
+	addrs       ids.ShortSet
+	owner       *secp256k1fx.OutputOwners
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	keyIntf, err := (&crypto.FactorySECP256K1R{}).NewPrivateKey()
+	require.NoError(t, err)
+	key := keyIntf.(*crypto.PrivateKeySECP256K1R)
+	kc := secp256k1fx.NewKeychain(key)
+
+	env := &testEnv{
+		utxos:       NewUTXOs(),
+		xClient:     &testXClient{},
+		pClient:     &testPClient{},
+		xChainID:    ids.GenerateTestID(),
+		avaxAssetID: ids.GenerateTestID(),
+		addrs:       kc.Addresses(),
+		owner: &secp256k1fx.OutputOwners{
+			Threshold: 1,
+			Addrs:     []ids.ShortID{key.PublicKey().Address()},
+		},
+	}
+
+	pCTX := p.NewContext(constants.UnitTestID, env.avaxAssetID, testFee, testFee, testFee, testFee, testFee, testFee, testFee, testFee)
+	pBackend := p.NewBackend(pCTX, NewChainUTXOs(constants.PlatformChainID, env.utxos), make(map[ids.ID]*txs.Tx))
+	xCTX := x.NewContext(constants.UnitTestID, env.xChainID, env.avaxAssetID, testFee, testFee)
+	xBackend := x.NewBackend(xCTX, env.xChainID, NewChainUTXOs(env.xChainID, env.utxos))
+	env.wallet = NewWalletWithOptions(
+		NewWallet(
+			p.NewWallet(p.NewBuilder(env.addrs, pBackend), p.NewSigner(kc, pBackend), env.pClient, pBackend),
+			x.NewWallet(x.NewBuilder(env.addrs, xBackend), x.NewSigner(kc, xBackend), env.xClient, xBackend),
+		),
+		common.WithAssumeDecided(),
+	)
+	return env
+}
+
+func (e *testEnv) addUTXOs(t *testing.T, chainID ids.ID, assetID ids.ID, amounts ...uint64) {
+	for _, amount := range amounts {
+		require.NoError(t, e.utxos.AddUTXO(context.Background(), chainID, chainID, &avax.UTXO{
+			UTXOID: avax.UTXOID{TxID: ids.GenerateTestID()},
+			Asset:  avax.Asset{ID: assetID},
+			Out: &secp256k1fx.TransferOutput{
+				Amt:          amount,
+				OutputOwners: *e.owner,
+			},
+		}))
+	}
+}
+
+// balances returns the amounts of the UTXOs of [assetID] on [chainID] that
+// were sent from [sourceChainID].
+func (e *testEnv) balances(t *testing.T, sourceChainID, chainID, assetID ids.ID) []uint64 {
+	utxos, err := e.utxos.UTXOs(context.Background(), sourceChainID, chainID)
+	require.NoError(t, err)
+	var amounts []uint64
+	for _, utxo := range utxos {
+		if utxo.AssetID() == assetID {
+			amounts = append(amounts, utxo.Out.(*secp256k1fx.TransferOutput).Amt)
+		}
+	}
+	return amounts
+}
+
+func TestConsolidateAVAX(t *testing.T) {
+	require := require.New(t)
+	env := newTestEnv(t)
+
+	env.addUTXOs(t, env.xChainID, env.avaxAssetID,
+		units.Avax, units.Avax, units.Avax, units.Avax, units.Avax,
+		units.Avax, units.Avax, units.Avax, units.Avax, units.Avax,
+	)
+
+	txIDs, err := Consolidate(env.wallet, env.xChainID, env.avaxAssetID, env.addrs, 4)
+	require.NoError(err)
+	require.Len(txIDs, 3)
+	require.Equal(3, env.xClient.numIssued)
+	require.ElementsMatch(
+		[]uint64{
+			4*units.Avax - testFee,
+			4*units.Avax - testFee,
+			2*units.Avax - testFee,
+		},
+		env.balances(t, env.xChainID, env.xChainID, env.avaxAssetID),
+	)
+}
+
+func TestConsolidateAssetPaysFeeInAVAX(t *testing.T) {
+	require := require.New(t)
+	env := newTestEnv(t)
+
+	assetID := ids.GenerateTestID()
+	env.addUTXOs(t, env.xChainID, assetID, 1, 2, 3)
+	env.addUTXOs(t, env.xChainID, env.avaxAssetID, units.Avax)
+
+	txIDs, err := Consolidate(env.wallet, env.xChainID, assetID, env.addrs, 0)
+	require.NoError(err)
+	require.Len(txIDs, 1)
+	require.Equal([]uint64{6}, env.balances(t, env.xChainID, env.xChainID, assetID))
+	require.Equal(
+		[]uint64{units.Avax - testFee},
+		env.balances(t, env.xChainID, env.xChainID, env.avaxAssetID),
+	)
+}
+
+func TestConsolidateRespectsMaxTxSize(t *testing.T) {
+	require := require.New(t)
+	env := newTestEnv(t)
+
+	// A tx consuming all of these UTXOs would exceed the maximum tx size.
+	amounts := make([]uint64, 2000)
+	for i := range amounts {
+		amounts[i] = units.Avax
+	}
+	env.addUTXOs(t, env.xChainID, env.avaxAssetID, amounts...)
+
+	txIDs, err := Consolidate(env.wallet, env.xChainID, env.avaxAssetID, env.addrs, len(amounts))
+	require.NoError(err)
+	require.Len(txIDs, 2)
+	require.ElementsMatch(
+		[]uint64{
+			1000*units.Avax - testFee,
+			1000*units.Avax - testFee,
+		},
+		env.balances(t, env.xChainID, env.xChainID, env.avaxAssetID),
+	)
+}
+
+func TestSweepCrossChain(t *testing.T) {
+	require := require.New(t)
+	env := newTestEnv(t)
+
+	assetID := ids.GenerateTestID()
+	env.addUTXOs(t, env.xChainID, assetID, 7)
+	env.addUTXOs(t, env.xChainID, env.avaxAssetID,
+		units.Avax, units.Avax, units.Avax, units.Avax, units.Avax,
+	)
+
+	to := &secp256k1fx.OutputOwners{
+		Threshold: 1,
+		Addrs:     []ids.ShortID{ids.GenerateTestShortID()},
+	}
+	txIDs, err := Sweep(env.wallet, env.xChainID, constants.PlatformChainID, env.addrs, to, 2)
+	require.NoError(err)
+
+	// One tx moves the other asset, three export the AVAX, and two import it.
+	require.Len(txIDs, 6)
+	require.Equal(4, env.xClient.numIssued)
+	require.Equal(2, env.pClient.numIssued)
+
+	// Nothing remains spendable by the original owner.
+	remaining, err := spendableUTXOs(&xBatchChain{w: env.wallet.X()}, env.xChainID, env.addrs, common.NewOptions(nil))
+	require.NoError(err)
+	require.Empty(remaining)
+	remaining, err = spendableUTXOs(&pBatchChain{w: env.wallet.P()}, env.xChainID, env.addrs, common.NewOptions(nil))
+	require.NoError(err)
+	require.Empty(remaining)
+
+	xUTXOs, err := env.utxos.UTXOs(context.Background(), env.xChainID, env.xChainID)
+	require.NoError(err)
+	require.Len(xUTXOs, 1)
+	require.Equal(assetID, xUTXOs[0].AssetID())
+	require.Equal(*to, xUTXOs[0].Out.(*secp256k1fx.TransferOutput).OutputOwners)
+
+	var swept uint64
+	pUTXOs, err := env.utxos.UTXOs(context.Background(), constants.PlatformChainID, constants.PlatformChainID)
+	require.NoError(err)
+	for _, utxo := range pUTXOs {
+		out := utxo.Out.(*secp256k1fx.TransferOutput)
+		require.Equal(*to, out.OutputOwners)
+		swept += out.Amt
+	}
+	// The other asset's tx, the exports, and the imports each paid a fee.
+	require.Equal(5*units.Avax-6*testFee, swept)
+}
+
+func TestPChainTransfersRejected(t *testing.T) {
+	require := require.New(t)
+	env := newTestEnv(t)
+
+	env.addUTXOs(t, constants.PlatformChainID, env.avaxAssetID, units.Avax, units.Avax)
+
+	_, err := Consolidate(env.wallet, constants.PlatformChainID, env.avaxAssetID, env.addrs, 0)
+	require.ErrorIs(err, errNoPChainTransfers)
+
+	to := &secp256k1fx.OutputOwners{
+		Threshold: 1,
+		Addrs:     []ids.ShortID{ids.GenerateTestShortID()},
+	}
+	_, err = Sweep(env.wallet, constants.PlatformChainID, constants.PlatformChainID, env.addrs, to, 0)
+	require.ErrorIs(err, errNoPChainTransfers)
+	require.Zero(env.pClient.numIssued)
+}